@@ -22,6 +22,7 @@ package lib
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -109,6 +110,7 @@ func (c *Client) Init() error {
 
 func (c *Client) initHTTPClient() error {
 	tr := new(http.Transport)
+	tr.Proxy = c.Config.Proxy
 	if c.Config.TLS.Enabled {
 		log.Info("TLS Enabled")
 
@@ -116,6 +118,7 @@ func (c *Client) initHTTPClient() error {
 		if err2 != nil {
 			return fmt.Errorf("Failed to get client TLS config: %s", err2)
 		}
+		tlsConfig.VerifyPeerCertificate = c.Config.VerifyPeerCertificate
 		tr.TLSClientConfig = tlsConfig
 	}
 	c.httpClient = &http.Client{Transport: tr}
@@ -131,6 +134,37 @@ type GetServerInfoResponse struct {
 	CAChain []byte
 	// Version of the server
 	Version string
+	// IssuerPublicKey is the serialized idemix issuer public key
+	IssuerPublicKey []byte
+	// IssuerRevocationPublicKey is the PEM-encoded idemix issuer revocation public key
+	IssuerRevocationPublicKey []byte
+}
+
+// GetCAInfo returns generic CA information
+func (c *Client) GetCAInfo(req *api.GetCAInfoRequest) (*GetServerInfoResponse, error) {
+	err := c.Init()
+	if err != nil {
+		return nil, err
+	}
+	body, err := util.Marshal(req, "GetCAInfo")
+	if err != nil {
+		return nil, err
+	}
+	cainforeq, err := c.newPost("cainfo", body)
+	if err != nil {
+		return nil, err
+	}
+	netSI := &serverInfoResponseNet{}
+	err = c.SendReq(cainforeq, netSI)
+	if err != nil {
+		return nil, err
+	}
+	localSI := &GetServerInfoResponse{}
+	err = c.net2LocalServerInfo(netSI, localSI)
+	if err != nil {
+		return nil, err
+	}
+	return localSI, nil
 }
 
 // Convert from network to local server information
@@ -139,9 +173,19 @@ func (c *Client) net2LocalServerInfo(net *serverInfoResponseNet, local *GetServe
 	if err != nil {
 		return err
 	}
+	issuerPublicKey, err := util.B64Decode(net.IssuerPublicKey)
+	if err != nil {
+		return err
+	}
+	issuerRevocationPublicKey, err := util.B64Decode(net.IssuerRevocationPublicKey)
+	if err != nil {
+		return err
+	}
 	local.CAName = net.CAName
 	local.CAChain = caChain
 	local.Version = net.Version
+	local.IssuerPublicKey = issuerPublicKey
+	local.IssuerRevocationPublicKey = issuerRevocationPublicKey
 	return nil
 }
 
@@ -311,6 +355,12 @@ func (c *Client) SendReq(req *http.Request, result interface{}) (err error) {
 		return err
 	}
 
+	if timeout := c.Config.Timeouts[path.Base(req.URL.Path)]; timeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return errors.Wrapf(err, "%s failure of request: %s", req.Method, reqStr)
@@ -346,12 +396,12 @@ func (c *Client) SendReq(req *http.Request, result interface{}) (err error) {
 					errorMsg = errorMsg + fmt.Sprintf("\n%s", msg)
 				}
 			}
-			return errors.Errorf(errorMsg)
+			return errors.WithStack(&HTTPError{StatusCode: resp.StatusCode, Message: errorMsg})
 		}
 	}
 	scode := resp.StatusCode
 	if scode >= 400 {
-		return errors.Errorf("Failed with server status code %d for request:\n%s", scode, reqStr)
+		return errors.WithStack(&HTTPError{StatusCode: scode, Message: fmt.Sprintf("Failed with server status code %d for request:\n%s", scode, reqStr)})
 	}
 	if body == nil {
 		return errors.Errorf("Empty response body:\n%s", reqStr)
@@ -21,6 +21,11 @@ Please review third_party pinning scripts and patches for more details.
 package lib
 
 import (
+	"crypto/x509"
+	"net/http"
+	"net/url"
+	"time"
+
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/api"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/lib/tls"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
@@ -38,4 +43,11 @@ type ClientConfig struct {
 	CAInfo     api.GetCAInfoRequest
 	CAName     string           `help:"Name of CA"`
 	CSP        core.CryptoSuite `mapstructure:"bccsp"`
+	// Proxy returns the proxy to use for a given request (nil for a direct connection)
+	Proxy func(*http.Request) (*url.URL, error) `mapstructure:"-"`
+	// VerifyPeerCertificate performs additional verification of the CA server's certificates (optional)
+	VerifyPeerCertificate func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error `mapstructure:"-"`
+	// Timeouts are the timeouts of the requests by endpoint (e.g. "enroll"). The requests of the
+	// other endpoints don't time out.
+	Timeouts map[string]time.Duration `mapstructure:"-"`
 }
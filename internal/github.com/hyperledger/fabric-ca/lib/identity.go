@@ -22,6 +22,7 @@ package lib
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/pkg/errors"
 
@@ -141,6 +142,31 @@ func (i *Identity) Revoke(req *api.RevocationRequest) (*api.RevocationResponse,
 	return &api.RevocationResponse{RevokedCerts: result.RevokedCerts, CRL: crl}, nil
 }
 
+// AddAffiliation adds a new affiliation to the server
+func (i *Identity) AddAffiliation(req *api.AddAffiliationRequest) (*api.AffiliationResponse, error) {
+	log.Debugf("Entering identity.AddAffiliation with request: %+v", req)
+	if req.Name == "" {
+		return nil, errors.New("Affiliation to add was not specified")
+	}
+
+	reqBody, err := util.Marshal(req, "addAffiliation")
+	if err != nil {
+		return nil, err
+	}
+
+	// Send a post to the "affiliations" endpoint with req as body
+	result := &api.AffiliationResponse{}
+	queryParam := make(map[string]string)
+	queryParam["force"] = strconv.FormatBool(req.Force)
+	err = i.Post("affiliations", reqBody, result, queryParam)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Successfully added new affiliation")
+	return result, nil
+}
+
 // Post sends arbitrary request body (reqBody) to an endpoint.
 // This adds an authorization header which contains the signature
 // of this identity over the body and non-signature part of the authorization header.
@@ -0,0 +1,24 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+/*
+Notice: This file has been modified for Hyperledger Fabric SDK Go usage.
+Please review third_party pinning scripts and patches for more details.
+*/
+
+package lib
+
+// HTTPError is returned when the fabric-ca-server responds with errors, so that
+// the HTTP status code of the response is available to the SDK
+type HTTPError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	// Message is the error message of the response
+	Message string
+}
+
+func (e *HTTPError) Error() string {
+	return e.Message
+}
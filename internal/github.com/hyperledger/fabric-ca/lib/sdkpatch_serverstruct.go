@@ -26,6 +26,10 @@ type serverInfoResponseNet struct {
 	CAChain string
 	// Version of the server
 	Version string
+	// Base64 encoding of the serialized idemix issuer public key
+	IssuerPublicKey string
+	// Base64 encoding of the PEM-encoded idemix issuer revocation public key
+	IssuerRevocationPublicKey string
 }
 
 type enrollmentResponseNet struct {
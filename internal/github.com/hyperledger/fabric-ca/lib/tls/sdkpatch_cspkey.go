@@ -0,0 +1,70 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+/*
+Notice: This file has been modified for Hyperledger Fabric SDK Go usage.
+Please review third_party pinning scripts and patches for more details.
+*/
+
+package tls
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/pkg/errors"
+
+	factory "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/sdkpatch/cryptosuitebridge"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+)
+
+// loadX509KeyPairFromCSP returns the TLS certificate of the PEM-encoded certificate chain, which
+// signs with the given private key of the crypto suite, so that the key never leaves the crypto suite
+func loadX509KeyPairFromCSP(certPEMBlock []byte, key core.Key, csp core.CryptoSuite) (*tls.Certificate, error) {
+	if !key.Private() {
+		return nil, errors.New("TLS client key of the crypto suite is not a private key")
+	}
+
+	cert := &tls.Certificate{}
+	for {
+		var certDERBlock *pem.Block
+		certDERBlock, certPEMBlock = pem.Decode(certPEMBlock)
+		if certDERBlock == nil {
+			break
+		}
+		if certDERBlock.Type == "CERTIFICATE" {
+			cert.Certificate = append(cert.Certificate, certDERBlock.Bytes)
+		}
+	}
+	if len(cert.Certificate) == 0 {
+		return nil, errors.New("Failed to find certificate PEM data in bytes")
+	}
+
+	signer, err := factory.NewCspSigner(csp, key)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed to create signer of TLS client key")
+	}
+
+	x509Cert, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to parse TLS client certificate")
+	}
+	certPubKey, err := x509.MarshalPKIXPublicKey(x509Cert.PublicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to marshal public key of TLS client certificate")
+	}
+	keyPubKey, err := x509.MarshalPKIXPublicKey(signer.Public())
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to marshal public key of TLS client key")
+	}
+	if !bytes.Equal(certPubKey, keyPubKey) {
+		return nil, errors.New("TLS client key of the crypto suite doesn't match the TLS client certificate")
+	}
+
+	cert.PrivateKey = signer
+	return cert, nil
+}
@@ -44,6 +44,8 @@ type ClientTLSConfig struct {
 type KeyCertFiles struct {
 	KeyFile  []byte `help:"PEM-encoded key bytes when mutual authentication is enabled"`
 	CertFile []byte `help:"PEM-encoded certificate bytes when mutual authenticate is enabled"`
+	// Key is the private key of the certificate in the crypto suite (e.g. in a HSM), which is used instead of KeyFile
+	Key core.Key `skip:"true"`
 }
 
 // GetClientTLSConfig creates a tls.Config object from certs and roots
@@ -60,7 +62,12 @@ func GetClientTLSConfig(cfg *ClientTLSConfig, csp core.CryptoSuite) (*tls.Config
 			return nil, err
 		}
 
-		clientCert, err := util.LoadX509KeyPair(cfg.Client.CertFile, cfg.Client.KeyFile, csp)
+		var clientCert *tls.Certificate
+		if cfg.Client.Key != nil {
+			clientCert, err = loadX509KeyPairFromCSP(cfg.Client.CertFile, cfg.Client.Key, csp)
+		} else {
+			clientCert, err = util.LoadX509KeyPair(cfg.Client.CertFile, cfg.Client.KeyFile, csp)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,411 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package admin provides the operations of the peer CLI with sensible defaults, so that operators can
+// replace the shell scripts which administer a network with small Go programs. The operations are
+// performed with the identity of an administrator of an organization:
+//
+//  peer channel create                        CreateChannel
+//  peer channel join                          JoinChannel
+//  peer channel fetch                         FetchBlock
+//  peer chaincode package                     PackageChaincode
+//  peer chaincode install                     InstallChaincode
+//  peer chaincode instantiate                 InstantiateChaincode
+//  peer lifecycle chaincode package           PackageLifecycleChaincode
+//  peer lifecycle chaincode install           InstallLifecycleChaincode
+//  peer lifecycle chaincode queryinstalled    QueryInstalledLifecycleChaincodes
+//  peer lifecycle chaincode approveformyorg   ApproveChaincode
+//  peer lifecycle chaincode commit            CommitChaincode
+//  peer chaincode query                       QueryChaincode
+//
+// Basic Flow:
+//  1) Create the admin of an organization
+//  2) Create the channel and join the peers of the organization
+//  3) Install and instantiate the chaincode or, on peers with the new chaincode lifecycle, install the
+//     lifecycle package and approve and commit the chaincode definition with the returned package ID
+//
+//  sdk, err := fabsdk.New(config.FromFile("config.yaml"))
+//  ...
+//  orgAdmin, err := admin.New(sdk, "Org1")
+//  ...
+//  _, err = orgAdmin.CreateChannel("mychannel", "channel.tx")
+//  ...
+//  err = orgAdmin.JoinChannel("mychannel")
+//  ...
+//  _, err = orgAdmin.InstallChaincode(resmgmt.InstallCCRequest{Name: "mycc", Version: "1.0", Path: "github.com/example_cc"})
+//  ...
+//  // or, with the new chaincode lifecycle:
+//  ccPkg, err := orgAdmin.PackageLifecycleChaincode("mycc_1.0", "github.com/example_cc")
+//  ...
+//  packageID, err := orgAdmin.InstallLifecycleChaincode("mycc_1.0", ccPkg)
+//  ...
+//  _, err = orgAdmin.ApproveChaincode("mychannel", &lifecycle.ChaincodeDefinition{Name: "mycc", Version: "1.0"}, packageID)
+//  ...
+//  payload, err := orgAdmin.QueryChaincode("mychannel", "mycc", "query", [][]byte{[]byte("a")})
+package admin
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/lifecycle"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/ccpackager/gopackager"
+	lcpackager "github.com/hyperledger/fabric-sdk-go/pkg/fab/ccpackager/lifecycle"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+var logger = logging.NewLogger("fabsdk/admin")
+
+// Positions of the blocks which may be fetched with FetchBlock, besides block numbers
+const (
+	// Oldest is the genesis block of the channel
+	Oldest = "oldest"
+	// Newest is the newest block of the channel
+	Newest = "newest"
+	// Config is the current configuration block of the channel
+	Config = "config"
+)
+
+// resourceClient is the subset of the resource management client used by the admin
+type resourceClient interface {
+	SaveChannel(req resmgmt.SaveChannelRequest, options ...resmgmt.RequestOption) (resmgmt.SaveChannelResponse, error)
+	JoinChannel(channelID string, options ...resmgmt.RequestOption) error
+	QueryBlockFromOrderer(channelID string, blockNumber uint64, options ...resmgmt.RequestOption) (*common.Block, error)
+	QueryNewestBlockFromOrderer(channelID string, options ...resmgmt.RequestOption) (*common.Block, error)
+	QueryConfigBlockFromOrderer(channelID string, options ...resmgmt.RequestOption) (*common.Block, error)
+	InstallCC(req resmgmt.InstallCCRequest, options ...resmgmt.RequestOption) ([]resmgmt.InstallCCResponse, error)
+	InstantiateCC(channelID string, req resmgmt.InstantiateCCRequest, options ...resmgmt.RequestOption) (resmgmt.InstantiateCCResponse, error)
+	LifecycleInstallCC(req resmgmt.LifecycleInstallCCRequest, options ...resmgmt.RequestOption) ([]resmgmt.LifecycleInstallCCResponse, error)
+	LifecycleQueryInstalledCC(options ...resmgmt.RequestOption) ([]resmgmt.LifecycleInstalledCC, error)
+}
+
+// channelClientProvider returns the channel client of the given channel
+type channelClientProvider func(channelID string) (lifecycle.ChannelClient, error)
+
+// Admin performs the operations of the peer CLI with the identity of an administrator of an organization
+type Admin struct {
+	user           string
+	mspID          string
+	resClient      resourceClient
+	channelClients channelClientProvider
+	goPath         string
+	retry          retry.Opts
+
+	lock    sync.Mutex
+	clients map[string]lifecycle.ChannelClient
+}
+
+// Option describes a functional parameter for the New constructor
+type Option func(*Admin)
+
+// WithUser sets the name of the administrator of the organization, which by default is "Admin"
+func WithUser(username string) Option {
+	return func(a *Admin) {
+		a.user = username
+	}
+}
+
+// WithGoPath sets the GOPATH in which the source of the chaincodes is packaged. By default the
+// GOPATH environment variable is used.
+func WithGoPath(goPath string) Option {
+	return func(a *Admin) {
+		a.goPath = goPath
+	}
+}
+
+// WithRetry sets the retry options of the resource management operations, which by default
+// are retry.DefaultResMgmtOpts. The retry options of a call may be set with resmgmt.WithRetry.
+func WithRetry(retryOpts retry.Opts) Option {
+	return func(a *Admin) {
+		a.retry = retryOpts
+	}
+}
+
+// New returns the admin of the given organization
+func New(sdk *fabsdk.FabricSDK, org string, opts ...Option) (*Admin, error) {
+	a := newAdmin(opts...)
+
+	contextOpts := []fabsdk.ContextOption{fabsdk.WithUser(a.user), fabsdk.WithOrg(org)}
+	clientProvider := sdk.Context(contextOpts...)
+	ctx, err := clientProvider()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create admin context")
+	}
+	a.mspID = ctx.Identifier().MSPID
+
+	a.resClient, err = resmgmt.New(clientProvider)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create resource management client")
+	}
+
+	a.channelClients = func(channelID string) (lifecycle.ChannelClient, error) {
+		return channel.New(sdk.ChannelContext(channelID, contextOpts...))
+	}
+
+	return a, nil
+}
+
+func newAdmin(opts ...Option) *Admin {
+	a := &Admin{
+		user:    "Admin",
+		retry:   retry.DefaultResMgmtOpts,
+		clients: make(map[string]lifecycle.ChannelClient),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// CreateChannel creates the channel with the channel configuration transaction at the given path
+// (created with configtxgen), which is signed by the admin
+func (a *Admin) CreateChannel(channelID, channelConfigPath string, options ...resmgmt.RequestOption) (fab.TransactionID, error) {
+	if channelConfigPath == "" {
+		return fab.EmptyTransactionID, errors.New("channel configuration path is required")
+	}
+
+	resp, err := a.resClient.SaveChannel(resmgmt.SaveChannelRequest{ChannelID: channelID, ChannelConfigPath: channelConfigPath}, a.resmgmtOpts(options)...)
+	if err != nil {
+		return fab.EmptyTransactionID, errors.WithMessage(err, "failed to create channel")
+	}
+	logger.Infof("Created channel [%s]", channelID)
+	return resp.TransactionID, nil
+}
+
+// JoinChannel joins the peers of the organization (or the peers given with resmgmt.WithTargets)
+// to the channel
+func (a *Admin) JoinChannel(channelID string, options ...resmgmt.RequestOption) error {
+	if err := a.resClient.JoinChannel(channelID, a.resmgmtOpts(options)...); err != nil {
+		return errors.WithMessage(err, "failed to join channel")
+	}
+	logger.Infof("Joined channel [%s]", channelID)
+	return nil
+}
+
+// FetchBlock fetches a block of the channel from the orderer. The position is a block number or
+// one of Oldest, Newest and Config.
+func (a *Admin) FetchBlock(channelID, position string, options ...resmgmt.RequestOption) (*common.Block, error) {
+	options = a.resmgmtOpts(options)
+
+	switch position {
+	case Oldest:
+		return a.resClient.QueryBlockFromOrderer(channelID, 0, options...)
+	case Newest:
+		return a.resClient.QueryNewestBlockFromOrderer(channelID, options...)
+	case Config:
+		return a.resClient.QueryConfigBlockFromOrderer(channelID, options...)
+	}
+
+	blockNumber, err := strconv.ParseUint(position, 10, 64)
+	if err != nil {
+		return nil, errors.Errorf("invalid block position [%s]: expecting a block number, %s, %s or %s", position, Oldest, Newest, Config)
+	}
+	return a.resClient.QueryBlockFromOrderer(channelID, blockNumber, options...)
+}
+
+// PackageChaincode packages the Go chaincode with the given path, relative to the GOPATH
+func (a *Admin) PackageChaincode(chaincodePath string) (*api.CCPackage, error) {
+	ccPackage, err := gopackager.NewCCPackage(chaincodePath, a.goPath)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to package chaincode")
+	}
+	return ccPackage, nil
+}
+
+// InstallChaincode installs the chaincode on the peers of the organization (or the peers given with
+// resmgmt.WithTargets). If the request doesn't include a package then the chaincode is packaged from its path.
+func (a *Admin) InstallChaincode(req resmgmt.InstallCCRequest, options ...resmgmt.RequestOption) ([]resmgmt.InstallCCResponse, error) {
+	if req.Package == nil {
+		ccPackage, err := a.PackageChaincode(req.Path)
+		if err != nil {
+			return nil, err
+		}
+		req.Package = ccPackage
+	}
+
+	resps, err := a.resClient.InstallCC(req, a.resmgmtOpts(options)...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to install chaincode")
+	}
+	logger.Infof("Installed chaincode [%s:%s]", req.Name, req.Version)
+	return resps, nil
+}
+
+// InstantiateChaincode instantiates the chaincode on the channel
+func (a *Admin) InstantiateChaincode(channelID string, req resmgmt.InstantiateCCRequest, options ...resmgmt.RequestOption) (fab.TransactionID, error) {
+	resp, err := a.resClient.InstantiateCC(channelID, req, a.resmgmtOpts(options)...)
+	if err != nil {
+		return fab.EmptyTransactionID, errors.WithMessage(err, "failed to instantiate chaincode")
+	}
+	logger.Infof("Instantiated chaincode [%s:%s] on channel [%s]", req.Name, req.Version, channelID)
+	return resp.TransactionID, nil
+}
+
+// PackageLifecycleChaincode packages the Go chaincode with the given path, relative to the GOPATH, for the new
+// chaincode lifecycle. The label of the package is part of its ID.
+func (a *Admin) PackageLifecycleChaincode(label, chaincodePath string) ([]byte, error) {
+	ccPackage, err := a.PackageChaincode(chaincodePath)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg, err := lcpackager.NewCCPackage(&lcpackager.Descriptor{Path: chaincodePath, Type: ccPackage.Type, Label: label, Code: ccPackage.Code})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to package chaincode")
+	}
+	return pkg, nil
+}
+
+// InstallLifecycleChaincode installs the chaincode package (see PackageLifecycleChaincode) with the new chaincode
+// lifecycle on the peers of the organization (or the peers given with resmgmt.WithTargets) and returns the ID of
+// the package, with which the chaincode definition is approved
+func (a *Admin) InstallLifecycleChaincode(label string, ccPackage []byte, options ...resmgmt.RequestOption) (string, error) {
+	resps, err := a.resClient.LifecycleInstallCC(resmgmt.LifecycleInstallCCRequest{Label: label, Package: ccPackage}, a.resmgmtOpts(options)...)
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to install chaincode")
+	}
+
+	var packageID string
+	for _, resp := range resps {
+		if resp.PackageID == "" {
+			continue
+		}
+		if packageID != "" && resp.PackageID != packageID {
+			return "", errors.Errorf("peers returned different package IDs [%s] and [%s]", packageID, resp.PackageID)
+		}
+		packageID = resp.PackageID
+	}
+	if packageID == "" {
+		return "", errors.New("failed to install chaincode: no package ID returned")
+	}
+
+	logger.Infof("Installed chaincode package [%s]", packageID)
+	return packageID, nil
+}
+
+// QueryInstalledLifecycleChaincodes returns the chaincode packages installed with the new chaincode lifecycle on
+// the peer given with resmgmt.WithTargets (or resmgmt.WithTargetEndpoints)
+func (a *Admin) QueryInstalledLifecycleChaincodes(options ...resmgmt.RequestOption) ([]resmgmt.LifecycleInstalledCC, error) {
+	installed, err := a.resClient.LifecycleQueryInstalledCC(a.resmgmtOpts(options)...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to query installed chaincodes")
+	}
+	return installed, nil
+}
+
+// ApproveChaincode approves the chaincode definition for the organization with the package with the given ID
+// (see InstallLifecycleChaincode). If the package ID is empty then the definition is approved without a package.
+// The approval is endorsed by the peers of the organization unless targets are given with channel.WithTargets.
+// The sequence of the definition defaults to 1.
+func (a *Admin) ApproveChaincode(channelID string, definition *lifecycle.ChaincodeDefinition, packageID string, options ...channel.RequestOption) (fab.TransactionID, error) {
+	client, err := a.lifecycleClient(channelID)
+	if err != nil {
+		return fab.EmptyTransactionID, err
+	}
+
+	options = append([]channel.RequestOption{channel.WithTargetFilter(&mspFilter{mspID: a.mspID})}, options...)
+	txID, err := client.ApproveForMyOrg(withDefaults(definition), packageID, options...)
+	if err != nil {
+		return fab.EmptyTransactionID, errors.WithMessage(err, "failed to approve chaincode definition")
+	}
+	logger.Infof("Approved chaincode definition [%s] on channel [%s] for [%s]", definition.Name, channelID, a.mspID)
+	return txID, nil
+}
+
+// CommitChaincode commits the chaincode definition on the channel. The sequence of the definition defaults to 1.
+func (a *Admin) CommitChaincode(channelID string, definition *lifecycle.ChaincodeDefinition, options ...channel.RequestOption) (fab.TransactionID, error) {
+	client, err := a.lifecycleClient(channelID)
+	if err != nil {
+		return fab.EmptyTransactionID, err
+	}
+
+	txID, err := client.Commit(withDefaults(definition), options...)
+	if err != nil {
+		return fab.EmptyTransactionID, errors.WithMessage(err, "failed to commit chaincode definition")
+	}
+	logger.Infof("Committed chaincode definition [%s] on channel [%s]", definition.Name, channelID)
+	return txID, nil
+}
+
+// QueryChaincode queries the chaincode and returns the payload of the response
+func (a *Admin) QueryChaincode(channelID, chaincodeID, fcn string, args [][]byte, options ...channel.RequestOption) ([]byte, error) {
+	client, err := a.channelClient(channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Query(channel.Request{ChaincodeID: chaincodeID, Fcn: fcn, Args: args}, options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to query chaincode")
+	}
+	return resp.Payload, nil
+}
+
+// resmgmtOpts prepends the default options to the options of a resource management operation
+func (a *Admin) resmgmtOpts(options []resmgmt.RequestOption) []resmgmt.RequestOption {
+	return append([]resmgmt.RequestOption{resmgmt.WithRetry(a.retry)}, options...)
+}
+
+func (a *Admin) lifecycleClient(channelID string) (*lifecycle.Client, error) {
+	client, err := a.channelClient(channelID)
+	if err != nil {
+		return nil, err
+	}
+	return lifecycle.New(client), nil
+}
+
+// channelClient returns the channel client of the channel, which is created once
+func (a *Admin) channelClient(channelID string) (lifecycle.ChannelClient, error) {
+	if channelID == "" {
+		return nil, errors.New("must provide channel ID")
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	client, ok := a.clients[channelID]
+	if ok {
+		return client, nil
+	}
+
+	client, err := a.channelClients(channelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create channel client")
+	}
+	a.clients[channelID] = client
+	return client, nil
+}
+
+// withDefaults returns a copy of the chaincode definition with the defaults set
+func withDefaults(definition *lifecycle.ChaincodeDefinition) *lifecycle.ChaincodeDefinition {
+	if definition == nil {
+		return nil
+	}
+	d := *definition
+	if d.Sequence == 0 {
+		d.Sequence = 1
+	}
+	return &d
+}
+
+// mspFilter accepts the peers of an organization
+type mspFilter struct {
+	mspID string
+}
+
+// Accept returns true if the peer belongs to the organization
+func (f *mspFilter) Accept(peer fab.Peer) bool {
+	return peer.MSPID() == f.mspID
+}
@@ -0,0 +1,217 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package admin
+
+import (
+	"os"
+	"path"
+	"strconv"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/test/mockchannel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/lifecycle"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+func TestFetchBlock(t *testing.T) {
+	resClient := &mockResourceClient{}
+	a := newTestAdmin(resClient, nil)
+
+	_, err := a.FetchBlock("mychannel", Oldest)
+	require.NoError(t, err)
+	_, err = a.FetchBlock("mychannel", "5")
+	require.NoError(t, err)
+	_, err = a.FetchBlock("mychannel", Newest)
+	require.NoError(t, err)
+	_, err = a.FetchBlock("mychannel", Config)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"block 0", "block 5", "newest", "config"}, resClient.calls)
+
+	_, err = a.FetchBlock("mychannel", "latest")
+	assert.Error(t, err, "expecting error for invalid position")
+}
+
+func TestCreateAndJoinChannel(t *testing.T) {
+	resClient := &mockResourceClient{}
+	a := newTestAdmin(resClient, nil)
+
+	txID, err := a.CreateChannel("mychannel", "channel.tx")
+	require.NoError(t, err)
+	assert.Equal(t, fab.TransactionID("txid"), txID)
+	require.NoError(t, a.JoinChannel("mychannel"))
+	assert.Equal(t, []string{"save mychannel channel.tx", "join mychannel"}, resClient.calls)
+
+	_, err = a.CreateChannel("mychannel", "")
+	assert.Error(t, err, "expecting error without channel configuration")
+
+	resClient.err = errors.New("orderer unavailable")
+	err = a.JoinChannel("mychannel")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "orderer unavailable")
+}
+
+func TestInstallChaincode(t *testing.T) {
+	pwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	resClient := &mockResourceClient{}
+	a := newTestAdmin(resClient, nil, WithGoPath(path.Join(pwd, "../../test/fixtures/testdata")))
+
+	_, err = a.InstallChaincode(resmgmt.InstallCCRequest{Name: "mycc", Version: "1.0", Path: "github.com/example_cc"})
+	require.NoError(t, err)
+	require.NotNil(t, resClient.installed, "expecting chaincode to be packaged")
+	assert.NotEmpty(t, resClient.installed.Package.Code)
+
+	_, err = a.InstallChaincode(resmgmt.InstallCCRequest{Name: "mycc", Version: "1.0", Path: "github.com/nonexistent_cc"})
+	assert.Error(t, err, "expecting packaging error")
+}
+
+func TestLifecycleInstall(t *testing.T) {
+	pwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	resClient := &mockResourceClient{packageIDs: []string{"mycc_1:1234", "mycc_1:1234"}}
+	a := newTestAdmin(resClient, nil, WithGoPath(path.Join(pwd, "../../test/fixtures/testdata")))
+
+	ccPkg, err := a.PackageLifecycleChaincode("mycc_1", "github.com/example_cc")
+	require.NoError(t, err)
+	assert.NotEmpty(t, ccPkg)
+
+	packageID, err := a.InstallLifecycleChaincode("mycc_1", ccPkg)
+	require.NoError(t, err)
+	assert.Equal(t, "mycc_1:1234", packageID)
+	assert.Equal(t, ccPkg, resClient.lifecycleInstalled.Package)
+
+	_, err = a.PackageLifecycleChaincode("mycc:1", "github.com/example_cc")
+	assert.Error(t, err, "expecting error for invalid label")
+
+	resClient.packageIDs = []string{"mycc_1:1234", "mycc_1:5678"}
+	_, err = a.InstallLifecycleChaincode("mycc_1", ccPkg)
+	assert.Error(t, err, "expecting error for different package IDs")
+
+	resClient.packageIDs = nil
+	_, err = a.InstallLifecycleChaincode("mycc_1", ccPkg)
+	assert.Error(t, err, "expecting error without package ID")
+
+	installed, err := a.QueryInstalledLifecycleChaincodes()
+	require.NoError(t, err)
+	assert.Equal(t, []resmgmt.LifecycleInstalledCC{{PackageID: "mycc_1:1234", Label: "mycc_1"}}, installed)
+}
+
+func TestLifecycle(t *testing.T) {
+	channelClient := mockchannel.New()
+	channelClient.OnExecute(lifecycle.ChaincodeID, "ApproveChaincodeDefinitionForMyOrg").ReturnResponse(channel.Response{TransactionID: "txid1"})
+	channelClient.OnExecute(lifecycle.ChaincodeID, "CommitChaincodeDefinition").ReturnResponse(channel.Response{TransactionID: "txid2"})
+	channelClient.OnQuery("mycc", "query").Return([]byte("100"))
+
+	created := 0
+	a := newTestAdmin(&mockResourceClient{}, func(channelID string) (lifecycle.ChannelClient, error) {
+		created++
+		return channelClient, nil
+	})
+
+	definition := &lifecycle.ChaincodeDefinition{Name: "mycc", Version: "1.0"}
+	txID, err := a.ApproveChaincode("mychannel", definition, "mycc_1:1234")
+	require.NoError(t, err)
+	assert.Equal(t, fab.TransactionID("txid1"), txID)
+
+	txID, err = a.CommitChaincode("mychannel", definition)
+	require.NoError(t, err)
+	assert.Equal(t, fab.TransactionID("txid2"), txID)
+	assert.Equal(t, int64(0), definition.Sequence, "definition shouldn't be modified")
+
+	payload, err := a.QueryChaincode("mychannel", "mycc", "query", [][]byte{[]byte("a")})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("100"), payload)
+	assert.Equal(t, 1, created, "expecting channel client to be created once")
+
+	_, err = a.QueryChaincode("", "mycc", "query", nil)
+	assert.Error(t, err, "expecting error without channel ID")
+}
+
+func TestDefaults(t *testing.T) {
+	assert.Equal(t, int64(1), withDefaults(&lifecycle.ChaincodeDefinition{Name: "mycc"}).Sequence)
+	assert.Equal(t, int64(3), withDefaults(&lifecycle.ChaincodeDefinition{Name: "mycc", Sequence: 3}).Sequence)
+
+	filter := &mspFilter{mspID: "Org1MSP"}
+	assert.True(t, filter.Accept(mocks.NewMockPeer("peer1", "peer1.org1.example.com:7051")))
+	peer2 := mocks.NewMockPeer("peer2", "peer2.org2.example.com:7051")
+	peer2.MockMSP = "Org2MSP"
+	assert.False(t, filter.Accept(peer2))
+}
+
+func newTestAdmin(resClient resourceClient, channelClients channelClientProvider, opts ...Option) *Admin {
+	a := newAdmin(opts...)
+	a.mspID = "Org1MSP"
+	a.resClient = resClient
+	a.channelClients = channelClients
+	return a
+}
+
+type mockResourceClient struct {
+	calls              []string
+	installed          *resmgmt.InstallCCRequest
+	lifecycleInstalled *resmgmt.LifecycleInstallCCRequest
+	packageIDs         []string
+	err                error
+}
+
+func (c *mockResourceClient) SaveChannel(req resmgmt.SaveChannelRequest, options ...resmgmt.RequestOption) (resmgmt.SaveChannelResponse, error) {
+	c.calls = append(c.calls, "save "+req.ChannelID+" "+req.ChannelConfigPath)
+	return resmgmt.SaveChannelResponse{TransactionID: "txid"}, c.err
+}
+
+func (c *mockResourceClient) JoinChannel(channelID string, options ...resmgmt.RequestOption) error {
+	c.calls = append(c.calls, "join "+channelID)
+	return c.err
+}
+
+func (c *mockResourceClient) QueryBlockFromOrderer(channelID string, blockNumber uint64, options ...resmgmt.RequestOption) (*common.Block, error) {
+	c.calls = append(c.calls, "block "+strconv.FormatUint(blockNumber, 10))
+	return &common.Block{}, c.err
+}
+
+func (c *mockResourceClient) QueryNewestBlockFromOrderer(channelID string, options ...resmgmt.RequestOption) (*common.Block, error) {
+	c.calls = append(c.calls, "newest")
+	return &common.Block{}, c.err
+}
+
+func (c *mockResourceClient) QueryConfigBlockFromOrderer(channelID string, options ...resmgmt.RequestOption) (*common.Block, error) {
+	c.calls = append(c.calls, "config")
+	return &common.Block{}, c.err
+}
+
+func (c *mockResourceClient) InstallCC(req resmgmt.InstallCCRequest, options ...resmgmt.RequestOption) ([]resmgmt.InstallCCResponse, error) {
+	c.installed = &req
+	return []resmgmt.InstallCCResponse{{Target: "peer1", Status: 200}}, c.err
+}
+
+func (c *mockResourceClient) InstantiateCC(channelID string, req resmgmt.InstantiateCCRequest, options ...resmgmt.RequestOption) (resmgmt.InstantiateCCResponse, error) {
+	c.calls = append(c.calls, "instantiate "+req.Name)
+	return resmgmt.InstantiateCCResponse{TransactionID: "txid"}, c.err
+}
+
+func (c *mockResourceClient) LifecycleInstallCC(req resmgmt.LifecycleInstallCCRequest, options ...resmgmt.RequestOption) ([]resmgmt.LifecycleInstallCCResponse, error) {
+	c.lifecycleInstalled = &req
+	var resps []resmgmt.LifecycleInstallCCResponse
+	for i, packageID := range c.packageIDs {
+		resps = append(resps, resmgmt.LifecycleInstallCCResponse{Target: "peer" + strconv.Itoa(i), Status: 200, PackageID: packageID})
+	}
+	return resps, c.err
+}
+
+func (c *mockResourceClient) LifecycleQueryInstalledCC(options ...resmgmt.RequestOption) ([]resmgmt.LifecycleInstalledCC, error) {
+	return []resmgmt.LifecycleInstalledCC{{PackageID: "mycc_1:1234", Label: "mycc_1"}}, c.err
+}
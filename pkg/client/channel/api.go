@@ -13,6 +13,8 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 	"github.com/pkg/errors"
@@ -20,11 +22,17 @@ import (
 
 // opts allows the user to specify more advanced options
 type requestOptions struct {
-	Targets       []fab.Peer // targets
-	TargetFilter  fab.TargetFilter
-	Retry         retry.Opts
-	Timeouts      map[fab.TimeoutType]time.Duration //timeout options for channel client operations
-	ParentContext reqContext.Context                //parent grpc context for channel client operations (query, execute, invokehandler)
+	Targets         []fab.Peer // targets
+	TargetFilter    fab.TargetFilter
+	Retry           retry.Opts
+	Timeouts        map[fab.TimeoutType]time.Duration //timeout options for channel client operations
+	ParentContext   reqContext.Context                //parent grpc context for channel client operations (query, execute, invokehandler)
+	Coverage        bool                              //endorse with a set of peers which satisfies the endorsement policy of the chaincode
+	Transient       map[string][]byte                 //transient entries added to the transient map of the request
+	Headers         map[string][]byte                 //application headers of the proposal
+	MinEndorsements int                               //number of endorsements after which the remaining proposals are cancelled
+	EarlyCompletion bool                              //complete the endorsement as soon as the endorsement policy of the chaincode is satisfied
+	Identity        msp.SigningIdentity               //identity which signs the request instead of the identity of the client
 }
 
 // RequestOption func for each Opts argument
@@ -107,6 +115,19 @@ func WithRetry(retryOpt retry.Opts) RequestOption {
 	}
 }
 
+// WithRetryPolicy option to configure retries with the registered retry policy with the given name
+// (see retry.RegisterPolicy and the retryPolicies section of the configuration)
+func WithRetryPolicy(name string) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		retryOpts, err := retry.Policy(name)
+		if err != nil {
+			return err
+		}
+		o.Retry = retryOpts
+		return nil
+	}
+}
+
 //WithTimeout encapsulates key value pairs of timeout type, timeout duration to Options
 func WithTimeout(timeoutType fab.TimeoutType, timeout time.Duration) RequestOption {
 	return func(ctx context.Client, o *requestOptions) error {
@@ -118,6 +139,78 @@ func WithTimeout(timeoutType fab.TimeoutType, timeout time.Duration) RequestOpti
 	}
 }
 
+// WithCallTimeout overrides the configured timeouts of the given types for this call only, e.g.
+// WithCallTimeout(map[fab.TimeoutType]time.Duration{fab.Execute: 10 * time.Second, fab.PeerResponse: 5 * time.Second}).
+// Timeouts which aren't given are taken from the configuration.
+func WithCallTimeout(timeouts map[fab.TimeoutType]time.Duration) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		o.Timeouts = contextImpl.OverrideTimeouts(o.Timeouts, timeouts)
+		return nil
+	}
+}
+
+// WithPolicyCoverage option to endorse with a minimal set of peers which satisfies the endorsement
+// policy of the chaincode. The policy is fetched from the peers (and cached) and the proposal is sent
+// in parallel to the peers of the set. If some of them fail then the proposal is sent to other peers
+// which, with the successful endorsers, satisfy the policy, until the policy is satisfied or no such
+// peers remain. The peers are chosen from the targets, if any, or from the peers of the channel.
+func WithPolicyCoverage() RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		o.Coverage = true
+		return nil
+	}
+}
+
+// WithMinEndorsements option to complete the endorsement as soon as n of the targets endorsed the proposal,
+// instead of waiting for the responses of all the targets. The proposal is sent to the targets in parallel and
+// the proposals still in flight are cancelled. The responses of the other targets aren't returned.
+func WithMinEndorsements(n int) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		if n <= 0 {
+			return errors.New("minimum number of endorsements must be greater than zero")
+		}
+		o.MinEndorsements = n
+		return nil
+	}
+}
+
+// WithEarlyCompletion option to complete the endorsement as soon as the endorsements received satisfy the
+// endorsement policy of the chaincode, instead of waiting for the responses of all the targets. The proposals
+// still in flight are cancelled. The policy is fetched from the peers (and cached), as with WithPolicyCoverage.
+// If WithMinEndorsements is also given, the endorsement completes as soon as either condition is met.
+func WithEarlyCompletion() RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		o.EarlyCompletion = true
+		return nil
+	}
+}
+
+// WithTransient option to add an entry to the transient data of the proposal, replacing the entry with the
+// same key in the TransientMap of the request, if any. Transient data is sent to the endorsers but isn't
+// included in the transaction.
+func WithTransient(key string, value []byte) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		if o.Transient == nil {
+			o.Transient = make(map[string][]byte)
+		}
+		o.Transient[key] = value
+		return nil
+	}
+}
+
+// WithHeader option to add an application header (e.g. a correlation ID) to the proposal. Headers are
+// included in the transaction, as decorations of the chaincode input, and may be read back from the
+// proposal or transaction with txn.ProposalHeaders or txn.TransactionHeaders.
+func WithHeader(key string, value []byte) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		if o.Headers == nil {
+			o.Headers = make(map[string][]byte)
+		}
+		o.Headers[key] = value
+		return nil
+	}
+}
+
 //WithParentContext encapsulates grpc context parent to Options
 func WithParentContext(parentContext reqContext.Context) RequestOption {
 	return func(ctx context.Client, o *requestOptions) error {
@@ -125,3 +218,15 @@ func WithParentContext(parentContext reqContext.Context) RequestOption {
 		return nil
 	}
 }
+
+// WithIdentity signs the request with the given identity instead of the identity of the client,
+// e.g. for a service which acts on behalf of its users. Query responses of such requests aren't cached.
+func WithIdentity(identity msp.SigningIdentity) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		if identity == nil {
+			return errors.New("identity is nil")
+		}
+		o.Identity = identity
+		return nil
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+const (
+	lscc                = "lscc"
+	lsccGetCCData       = "getccdata"
+	ccPolicyCacheExpiry = 5 * time.Minute
+)
+
+type ccPolicy struct {
+	policy  *common.SignaturePolicyEnvelope
+	fetched time.Time
+}
+
+// ccPolicyProvider provides the endorsement policies of the chaincodes of the channel, which are
+// queried from the lifecycle system chaincode and cached for a few minutes (in case of an upgrade)
+type ccPolicyProvider struct {
+	client   *Client
+	lock     sync.Mutex
+	policies map[string]*ccPolicy
+}
+
+func newCCPolicyProvider(client *Client) *ccPolicyProvider {
+	return &ccPolicyProvider{client: client, policies: make(map[string]*ccPolicy)}
+}
+
+// GetChaincodePolicy returns the endorsement policy of the chaincode
+func (p *ccPolicyProvider) GetChaincodePolicy(chaincodeID string) (*common.SignaturePolicyEnvelope, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if cached, ok := p.policies[chaincodeID]; ok && time.Since(cached.fetched) < ccPolicyCacheExpiry {
+		return cached.policy, nil
+	}
+
+	channelID := p.client.context.ChannelID()
+	response, err := p.client.query(Request{ChaincodeID: lscc, Fcn: lsccGetCCData, Args: [][]byte{[]byte(channelID), []byte(chaincodeID)}})
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("error querying chaincode data for chaincode [%s] on channel [%s]", chaincodeID, channelID))
+	}
+
+	ccData := &ccprovider.ChaincodeData{}
+	if err := proto.Unmarshal(response.Payload, ccData); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling chaincode data")
+	}
+	policy := &common.SignaturePolicyEnvelope{}
+	if err := proto.Unmarshal(ccData.Policy, policy); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling SignaturePolicyEnvelope")
+	}
+
+	p.policies[chaincodeID] = &ccPolicy{policy: policy, fetched: time.Now()}
+	return policy, nil
+}
@@ -9,15 +9,18 @@ package channel
 
 import (
 	reqContext "context"
+	"sync"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/discovery/greylist"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/filter"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/recovery"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/pkg/errors"
 )
@@ -32,11 +35,66 @@ type Client struct {
 	membership   fab.ChannelMembership
 	eventService fab.EventService
 	greylist     *greylist.Filter
+	queryCache   *queryCache
+	retryOpts    retry.Opts
+	retryBudget  *retry.Budget
+	resubmits    int
+	ccPolicies   *ccPolicyProvider
+	// queryDefaults and executeDefaults are the default options of queries and transactions, which
+	// are created once since the default target filters look up the channel peers in the configuration
+	queryDefaults   []RequestOption
+	executeDefaults []RequestOption
+	// identity signs the requests instead of the identity of the channel context (see WithIdentity)
+	identity msp.SigningIdentity
 }
 
 // ClientOption describes a functional parameter for the New constructor
 type ClientOption func(*Client) error
 
+// WithQueryCache enables caching of query responses, which is intended for read-heavy applications
+// which mostly query static (reference) data. A cached response is returned for a query with the same
+// chaincode ID, function and arguments until a transaction which updates any of the namespaces read by
+// the query is committed (as observed from block events) or, if maxAge is not zero, the response is older
+// than maxAge. Up to maxEntries responses are cached (1000 if zero). Queries with transient data are not cached.
+// The client must be closed when it is no longer needed.
+func WithQueryCache(maxAge time.Duration, maxEntries int) ClientOption {
+	return func(cc *Client) error {
+		cache, err := newQueryCache(cc.eventService, maxAge, maxEntries)
+		if err != nil {
+			return errors.WithMessage(err, "query cache creation failed")
+		}
+		cc.queryCache = cache
+		return nil
+	}
+}
+
+// WithRetryBudget limits the total number of retries of the requests of the client to maxRetries
+// within any window of the given duration, so that the retries of many failing requests don't
+// amplify an outage of the peers. The budget applies to the requests whose retry options don't
+// have their own budget.
+func WithRetryBudget(maxRetries int, window time.Duration) ClientOption {
+	return func(cc *Client) error {
+		if maxRetries < 0 || window <= 0 {
+			return errors.New("retry budget requires non-negative max retries and a positive window")
+		}
+		cc.retryBudget = retry.NewBudget(maxRetries, window)
+		return nil
+	}
+}
+
+// WithConflictResubmit enables the re-endorsement and resubmission, up to maxResubmits times, of the
+// transactions which are invalidated by MVCC or phantom read conflicts with concurrent transactions
+// (see status.Conflict). Resubmissions are independent of the retry options of the requests.
+func WithConflictResubmit(maxResubmits int) ClientOption {
+	return func(cc *Client) error {
+		if maxResubmits < 0 {
+			return errors.New("max resubmits must not be negative")
+		}
+		cc.resubmits = maxResubmits
+		return nil
+	}
+}
+
 // New returns a Client instance.
 func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client, error) {
 
@@ -61,12 +119,27 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 		return nil, errors.WithMessage(err, "membership creation failed")
 	}
 
+	retryOpts, err := defaultRetryOpts(channelContext)
+	if err != nil {
+		return nil, err
+	}
+
 	channelClient := Client{
 		membership:   membership,
 		eventService: eventService,
 		greylist:     greylistProvider,
 		context:      channelContext,
+		retryOpts:    retryOpts,
+		queryDefaults: []RequestOption{
+			addDefaultTimeout(fab.Query),
+			addDefaultTargetFilter(filter.NewEndpointFilter(channelContext, filter.ChaincodeQuery)),
+		},
+		executeDefaults: []RequestOption{
+			addDefaultTimeout(fab.Execute),
+			addDefaultTargetFilter(filter.NewEndpointFilter(channelContext, filter.EndorsingPeer)),
+		},
 	}
+	channelClient.ccPolicies = newCCPolicyProvider(&channelClient)
 
 	for _, param := range opts {
 		err := param(&channelClient)
@@ -78,28 +151,65 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 	return &channelClient, nil
 }
 
+// defaultRetryOpts returns the options of the retry policy of the channel, which are used
+// for the requests without retry options
+func defaultRetryOpts(channelContext context.Channel) (retry.Opts, error) {
+	chConfig, err := channelContext.EndpointConfig().ChannelConfig(channelContext.ChannelID())
+	if err != nil || chConfig.Policies.RetryPolicy == "" {
+		return retry.Opts{}, nil
+	}
+	retryOpts, err := retry.Policy(chConfig.Policies.RetryPolicy)
+	if err != nil {
+		return retry.Opts{}, errors.WithMessage(err, "invalid retry policy of channel")
+	}
+	return retryOpts, nil
+}
+
 // Query chaincode using request and optional options provided
 func (cc *Client) Query(request Request, options ...RequestOption) (Response, error) {
+	if cc.queryCache == nil || len(request.TransientMap) > 0 || cc.hasProposalMetadata(options) {
+		return cc.query(request, options...)
+	}
+
+	if response, ok := cc.queryCache.get(request); ok {
+		logger.Debugf("Returning cached response for query [%s:%s]", request.ChaincodeID, request.Fcn)
+		return response, nil
+	}
 
-	options = append(options, addDefaultTimeout(fab.Query))
-	options = append(options, addDefaultTargetFilter(cc.context, filter.ChaincodeQuery))
+	generation := cc.queryCache.start()
+	response, err := cc.query(request, options...)
+	if err != nil {
+		return response, err
+	}
+	cc.queryCache.put(request, response, generation)
+	return response, nil
+}
+
+// hasProposalMetadata returns true if the options add transient data or headers to the proposal,
+// or if the request is signed by another identity, in which case the response isn't cached
+func (cc *Client) hasProposalMetadata(options []RequestOption) bool {
+	o, err := cc.prepareOptsFromOptions(cc.context, options...)
+	return err == nil && (len(o.Transient) > 0 || len(o.Headers) > 0 || o.Identity != nil)
+}
+
+func (cc *Client) query(request Request, options ...RequestOption) (Response, error) {
+	options = append(options, cc.queryDefaults...)
 
 	return cc.InvokeHandler(invoke.NewQueryHandler(), request, options...)
 }
 
 // Execute prepares and executes transaction using request and optional options provided
 func (cc *Client) Execute(request Request, options ...RequestOption) (Response, error) {
-	options = append(options, addDefaultTimeout(fab.Execute))
-	options = append(options, addDefaultTargetFilter(cc.context, filter.EndorsingPeer))
+	options = append(options, cc.executeDefaults...)
 
 	return cc.InvokeHandler(invoke.NewExecuteHandler(), request, options...)
 }
 
 // addDefaultTargetFilter adds default target filter if target filter is not specified
-func addDefaultTargetFilter(chCtx context.Channel, ft filter.EndpointType) RequestOption {
+func addDefaultTargetFilter(targetFilter fab.TargetFilter) RequestOption {
 	return func(ctx context.Client, o *requestOptions) error {
 		if len(o.Targets) == 0 && o.TargetFilter == nil {
-			return WithTargetFilter(filter.NewEndpointFilter(chCtx, ft))(ctx, o)
+			return WithTargetFilter(targetFilter)(ctx, o)
 		}
 		return nil
 	}
@@ -115,7 +225,9 @@ func addDefaultTimeout(tt fab.TimeoutType) RequestOption {
 	}
 }
 
-//InvokeHandler invokes handler using request and options provided
+//InvokeHandler invokes handler using request and options provided.
+//The request and client contexts passed to the handler are reused by other requests once the
+//handler returns, so the handler must not retain them.
 func (cc *Client) InvokeHandler(handler invoke.Handler, request Request, options ...RequestOption) (Response, error) {
 	//Read execute tx options
 	txnOpts, err := cc.prepareOptsFromOptions(cc.context, options...)
@@ -132,33 +244,52 @@ func (cc *Client) InvokeHandler(handler invoke.Handler, request Request, options
 		return Response{}, err
 	}
 
+	resetRequestContext := func() {
+		requestContext.Opts.Targets = txnOpts.Targets
+		requestContext.Error = nil
+		requestContext.Response = invoke.Response{}
+	}
+
 	invoker := retry.NewInvoker(
 		requestContext.RetryHandler,
+		retry.WithContext(reqCtx),
 		retry.WithBeforeRetry(
 			func(err error) {
 				cc.greylist.Greylist(err)
 
 				// Reset context parameters
-				requestContext.Opts.Targets = txnOpts.Targets
-				requestContext.Error = nil
-				requestContext.Response = invoke.Response{}
+				resetRequestContext()
 			},
 		),
 	)
 
 	complete := make(chan bool)
 	go func() {
-		_, _ = invoker.Invoke(
-			func() (interface{}, error) {
-				handler.Handle(requestContext, clientContext)
-				return nil, requestContext.Error
-			})
+		// A panicking (custom) handler is reported as the error of the request
+		err := recovery.Call(func() {
+			_, _ = invoker.Invoke(
+				func() (interface{}, error) {
+					handler.Handle(requestContext, clientContext)
+					for resubmit := 1; resubmit <= cc.resubmits && status.IsCategory(requestContext.Error, status.Conflict); resubmit++ {
+						logger.Debugf("Resubmitting transaction (#%d) after conflict [%s]", resubmit, requestContext.Error)
+						resetRequestContext()
+						handler.Handle(requestContext, clientContext)
+					}
+					return nil, requestContext.Error
+				})
+		})
+		if err != nil {
+			requestContext.Error = errors.WithMessage(err, "invoke handler failed")
+		}
 		complete <- true
 	}()
 	select {
 	case <-complete:
-		return Response(requestContext.Response), requestContext.Error
+		response, err := Response(requestContext.Response), requestContext.Error
+		releaseHandlerContexts(requestContext, clientContext)
+		return response, err
 	case <-reqCtx.Done():
+		// The handler may still be using the contexts, which therefore aren't released
 		return Response{}, status.New(status.ClientStatus, status.Timeout.ToInt32(),
 			"request timed out or been cancelled", nil)
 	}
@@ -176,7 +307,7 @@ func (cc *Client) createReqContext(txnOpts *requestOptions) (reqContext.Context,
 		txnOpts.Timeouts[fab.Execute] = cc.context.EndpointConfig().Timeout(fab.Execute)
 	}
 
-	reqCtx, cancel := contextImpl.NewRequest(cc.context, contextImpl.WithTimeout(txnOpts.Timeouts[fab.Execute]),
+	reqCtx, cancel := contextImpl.NewRequest(cc.requestContext(txnOpts), contextImpl.WithTimeout(txnOpts.Timeouts[fab.Execute]),
 		contextImpl.WithParent(txnOpts.ParentContext))
 	//Add timeout overrides here as a value so that it can be used by immediate child contexts (in handlers/transactors)
 	reqCtx = reqContext.WithValue(reqCtx, contextImpl.ReqContextTimeoutOverrides, txnOpts.Timeouts)
@@ -184,6 +315,29 @@ func (cc *Client) createReqContext(txnOpts *requestOptions) (reqContext.Context,
 	return reqCtx, cancel
 }
 
+// requestContext returns the client context of a request, which supplies the identity signing the request
+func (cc *Client) requestContext(txnOpts *requestOptions) context.Client {
+	identity := txnOpts.Identity
+	if identity == nil {
+		identity = cc.identity
+	}
+	if identity == nil {
+		return cc.context
+	}
+	return &contextImpl.Client{Providers: cc.context, SigningIdentity: identity}
+}
+
+// WithIdentity returns a client which shares the channel context, the connections and the event service of
+// this client and whose requests are signed by the given identity, e.g. for a service which acts on behalf of
+// its users. The identity of a single request may be set with the WithIdentity request option instead.
+// Since responses may depend on the identity, the returned client doesn't use the query cache of this client.
+func (cc *Client) WithIdentity(identity msp.SigningIdentity) *Client {
+	clone := *cc
+	clone.identity = identity
+	clone.queryCache = nil
+	return &clone
+}
+
 //prepareHandlerContexts prepares context objects for handlers
 func (cc *Client) prepareHandlerContexts(reqCtx reqContext.Context, request Request, o requestOptions) (*invoke.RequestContext, *invoke.ClientContext, error) {
 
@@ -210,15 +364,18 @@ func (cc *Client) prepareHandlerContexts(reqCtx reqContext.Context, request Requ
 		return true
 	}
 
-	clientContext := &invoke.ClientContext{
-		Selection:    cc.context.SelectionService(),
-		Discovery:    cc.context.DiscoveryService(),
-		Membership:   cc.membership,
-		Transactor:   transactor,
-		EventService: cc.eventService,
+	clientContext := clientContextPool.Get().(*invoke.ClientContext)
+	*clientContext = invoke.ClientContext{
+		Selection:      cc.context.SelectionService(),
+		Discovery:      cc.context.DiscoveryService(),
+		Membership:     cc.membership,
+		Transactor:     transactor,
+		EventService:   cc.eventService,
+		PolicyProvider: cc.ccPolicies,
 	}
 
-	requestContext := &invoke.RequestContext{
+	requestContext := requestContextPool.Get().(*invoke.RequestContext)
+	*requestContext = invoke.RequestContext{
 		Request:         invoke.Request(request),
 		Opts:            invoke.Opts(o),
 		Response:        invoke.Response{},
@@ -230,18 +387,100 @@ func (cc *Client) prepareHandlerContexts(reqCtx reqContext.Context, request Requ
 	return requestContext, clientContext, nil
 }
 
+// The request and client contexts of the handlers are pooled, since they're allocated for each request
+var (
+	requestContextPool = sync.Pool{New: func() interface{} { return &invoke.RequestContext{} }}
+	clientContextPool  = sync.Pool{New: func() interface{} { return &invoke.ClientContext{} }}
+)
+
+// releaseHandlerContexts clears the contexts (so that they don't keep the request and the response
+// alive) and returns them to their pools
+func releaseHandlerContexts(requestContext *invoke.RequestContext, clientContext *invoke.ClientContext) {
+	*requestContext = invoke.RequestContext{}
+	requestContextPool.Put(requestContext)
+	*clientContext = invoke.ClientContext{}
+	clientContextPool.Put(clientContext)
+}
+
 //prepareOptsFromOptions Reads apitxn.Opts from Option array
 func (cc *Client) prepareOptsFromOptions(ctx context.Client, options ...RequestOption) (requestOptions, error) {
-	txnOpts := requestOptions{}
+	txnOpts := requestOptions{Retry: cc.retryOpts}
 	for _, option := range options {
 		err := option(ctx, &txnOpts)
 		if err != nil {
 			return txnOpts, errors.WithMessage(err, "Failed to read opts")
 		}
 	}
+	if txnOpts.Retry.Budget == nil {
+		txnOpts.Retry.Budget = cc.retryBudget
+	}
 	return txnOpts, nil
 }
 
+// ResolveOptions resolves the given request options once and returns a request option which applies
+// the resolved options, as if the given options were applied. Applications which send many requests
+// with the same options pass the returned option to each request instead of having the options
+// resolved for each request (e.g. WithTargetURLs looks up the peers in the configuration and creates them).
+func (cc *Client) ResolveOptions(options ...RequestOption) (RequestOption, error) {
+	// The budget of the retry options tells whether an option set the retry options
+	resolved := requestOptions{Retry: retry.Opts{Budget: unresolvedRetryBudget}}
+	for _, option := range options {
+		if err := option(cc.context, &resolved); err != nil {
+			return nil, errors.WithMessage(err, "Failed to read opts")
+		}
+	}
+	return withResolvedOptions(resolved), nil
+}
+
+var unresolvedRetryBudget = &retry.Budget{}
+
+// withResolvedOptions sets the options which were set by the resolved options
+func withResolvedOptions(resolved requestOptions) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		if resolved.Targets != nil {
+			o.Targets = resolved.Targets
+		}
+		if resolved.TargetFilter != nil {
+			o.TargetFilter = resolved.TargetFilter
+		}
+		if resolved.Retry.Budget != unresolvedRetryBudget {
+			o.Retry = resolved.Retry
+		}
+		if resolved.ParentContext != nil {
+			o.ParentContext = resolved.ParentContext
+		}
+		if resolved.MinEndorsements > 0 {
+			o.MinEndorsements = resolved.MinEndorsements
+		}
+		if resolved.Identity != nil {
+			o.Identity = resolved.Identity
+		}
+		o.Coverage = o.Coverage || resolved.Coverage
+		o.EarlyCompletion = o.EarlyCompletion || resolved.EarlyCompletion
+
+		// The maps are copied since the request may modify them (e.g. the default timeouts are added)
+		for tt, timeout := range resolved.Timeouts {
+			if o.Timeouts == nil {
+				o.Timeouts = make(map[fab.TimeoutType]time.Duration, len(resolved.Timeouts))
+			}
+			o.Timeouts[tt] = timeout
+		}
+		for key, value := range resolved.Transient {
+			if o.Transient == nil {
+				o.Transient = make(map[string][]byte, len(resolved.Transient))
+			}
+			o.Transient[key] = value
+		}
+		for key, value := range resolved.Headers {
+			if o.Headers == nil {
+				o.Headers = make(map[string][]byte, len(resolved.Headers))
+			}
+			o.Headers[key] = value
+		}
+		return nil
+	}
+}
+
 // RegisterChaincodeEvent registers chain code event
 // @param {chan bool} channel which receives event details when the event is complete
 // @returns {object} object handle that should be used to unregister
@@ -254,3 +493,10 @@ func (cc *Client) RegisterChaincodeEvent(chainCodeID string, eventFilter string)
 func (cc *Client) UnregisterChaincodeEvent(registration fab.Registration) {
 	cc.eventService.Unregister(registration)
 }
+
+// Close releases the resources held by the client (i.e. the query cache)
+func (cc *Client) Close() {
+	if cc.queryCache != nil {
+		cc.queryCache.close()
+	}
+}
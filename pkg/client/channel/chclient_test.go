@@ -18,6 +18,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
 	txnmocks "github.com/hyperledger/fabric-sdk-go/pkg/client/common/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/staticselection"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/recovery"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
@@ -252,6 +253,65 @@ func TestInvokeHandler(t *testing.T) {
 	}
 }
 
+// identityHandler records the ID of the identity which signs the request
+type identityHandler struct {
+	id string
+}
+
+func (h *identityHandler) Handle(requestContext *invoke.RequestContext, clientContext *invoke.ClientContext) {
+	if ctx, ok := contextImpl.RequestClientContext(requestContext.Ctx); ok {
+		h.id = ctx.Identifier().ID
+	}
+}
+
+func TestWithIdentity(t *testing.T) {
+	chClient := setupChannelClient(nil, t)
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+	user1 := mspmocks.NewMockSigningIdentity("user1", "Org1MSP")
+	user2 := mspmocks.NewMockSigningIdentity("user2", "Org1MSP")
+
+	handler := &identityHandler{}
+	_, err := chClient.InvokeHandler(handler, request)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", handler.id, "expecting request to be signed by the identity of the client")
+
+	_, err = chClient.InvokeHandler(handler, request, WithIdentity(user1))
+	assert.NoError(t, err)
+	assert.Equal(t, "user1", handler.id, "expecting request to be signed by the identity of the request")
+
+	user2Client := chClient.WithIdentity(user2)
+	_, err = user2Client.InvokeHandler(handler, request)
+	assert.NoError(t, err)
+	assert.Equal(t, "user2", handler.id, "expecting request to be signed by the identity of the client")
+
+	_, err = user2Client.InvokeHandler(handler, request, WithIdentity(user1))
+	assert.NoError(t, err)
+	assert.Equal(t, "user1", handler.id, "expecting identity of the request to override the identity of the client")
+
+	_, err = chClient.InvokeHandler(handler, request)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", handler.id, "expecting identity of the original client to be unchanged")
+
+	_, err = chClient.InvokeHandler(handler, request, WithIdentity(nil))
+	assert.Error(t, err, "expecting error for nil identity")
+}
+
+type panickingHandler struct{}
+
+func (h *panickingHandler) Handle(requestContext *invoke.RequestContext, clientContext *invoke.ClientContext) {
+	panic("handler failed")
+}
+
+func TestInvokeHandlerPanic(t *testing.T) {
+	chClient := setupChannelClient(nil, t)
+
+	_, err := chClient.InvokeHandler(&panickingHandler{}, Request{ChaincodeID: "testCC", Fcn: "move", Args: [][]byte{[]byte("a"), []byte("b"), []byte("1")}})
+	panicErr, ok := errors.Cause(err).(*recovery.PanicError)
+	if assert.True(t, ok, "Expected panic error got %+v", err) {
+		assert.Equal(t, "handler failed", panicErr.Value)
+	}
+}
+
 // customEndorsementHandler ignores the channel in the ClientContext
 // and instead sends the proposal to the given channel
 type customEndorsementHandler struct {
@@ -310,6 +370,66 @@ func TestQueryWithCustomEndorser(t *testing.T) {
 	}
 }
 
+func TestResolveOptions(t *testing.T) {
+	chClient := setupChannelClient(nil, t)
+	target := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+
+	_, err := chClient.ResolveOptions(WithMinEndorsements(0))
+	assert.Error(t, err, "expected error for invalid option")
+
+	resolved, err := chClient.ResolveOptions(WithTargets(target), WithTimeout(fab.Query, 5*time.Second), WithHeader("key", []byte("value")))
+	assert.NoError(t, err)
+
+	retryOpts := retry.Opts{Attempts: 3}
+	opts := requestOptions{Retry: retryOpts}
+	assert.NoError(t, resolved(chClient.context, &opts))
+	assert.Equal(t, []fab.Peer{target}, opts.Targets)
+	assert.Equal(t, 5*time.Second, opts.Timeouts[fab.Query])
+	assert.Equal(t, []byte("value"), opts.Headers["key"])
+	assert.Equal(t, retryOpts, opts.Retry, "retry options weren't resolved and shouldn't be changed")
+
+	// Each request gets its own copy of the maps
+	opts.Timeouts[fab.Execute] = time.Second
+	other := requestOptions{}
+	assert.NoError(t, resolved(chClient.context, &other))
+	assert.Len(t, other.Timeouts, 1)
+
+	resolved, err = chClient.ResolveOptions(WithRetry(retry.Opts{}))
+	assert.NoError(t, err)
+	assert.NoError(t, resolved(chClient.context, &opts))
+	assert.Equal(t, retry.Opts{}, opts.Retry, "resolved retry options should be set")
+}
+
+func BenchmarkQuery(b *testing.B) {
+	chClient := setupChannelClient(nil, b)
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := chClient.Query(request); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkQueryResolvedOptions(b *testing.B) {
+	chClient := setupChannelClient(nil, b)
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+	opts, err := chClient.ResolveOptions(WithTimeout(fab.Query, 5*time.Second), WithRetry(retry.Opts{}))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := chClient.Query(request, opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func TestExecuteTxSelectionError(t *testing.T) {
 	chClient := setupChannelClientWithError(nil, errors.New("Test Error"), nil, t)
 
@@ -521,7 +641,7 @@ func setupTestContext() context.Client {
 	return ctx
 }
 
-func setupCustomTestContext(t *testing.T, selectionService fab.SelectionService, discoveryService fab.DiscoveryService, orderers []fab.Orderer) context.ClientProvider {
+func setupCustomTestContext(t testing.TB, selectionService fab.SelectionService, discoveryService fab.DiscoveryService, orderers []fab.Orderer) context.ClientProvider {
 	user := mspmocks.NewMockSigningIdentity("test", "test")
 	ctx := fcmocks.NewMockContext(user)
 
@@ -574,12 +694,12 @@ func setupTestSelection(discErr error, peers []fab.Peer) (*txnmocks.MockSelectio
 	return mockSelection.CreateSelectionService("mychannel")
 }
 
-func setupChannelClient(peers []fab.Peer, t *testing.T) *Client {
+func setupChannelClient(peers []fab.Peer, t testing.TB) *Client {
 
 	return setupChannelClientWithError(nil, nil, peers, t)
 }
 
-func setupChannelClientWithError(discErr error, selectionErr error, peers []fab.Peer, t *testing.T) *Client {
+func setupChannelClientWithError(discErr error, selectionErr error, peers []fab.Peer, t testing.TB) *Client {
 
 	discoveryService, err := setupTestDiscovery(discErr, nil)
 	if err != nil {
@@ -658,3 +778,129 @@ func createClientContext(client context.Client) context.ClientProvider {
 		return client, nil
 	}
 }
+
+func TestExecuteTxWithRetryPolicy(t *testing.T) {
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer1.Error = status.New(status.EndorserClientStatus, status.ConnectionFailed.ToInt32(), "test", nil)
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+
+	retry.RegisterPolicy("chclient-test", retry.Opts{
+		Attempts:       2,
+		BackoffFactor:  1,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		RetryableCodes: retry.ChannelClientRetryableCodes,
+	})
+
+	_, err := chClient.Query(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}},
+		WithRetryPolicy("chclient-test"))
+	assert.Error(t, err, "expected error")
+	assert.Equal(t, 3, testPeer1.ProcessProposalCalls, "expected peer to be called for the initial attempt and the retries")
+
+	_, err = chClient.Query(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}},
+		WithRetryPolicy("unknown"))
+	assert.Error(t, err, "expected error for unknown retry policy")
+}
+
+func TestRetryBudget(t *testing.T) {
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer1.Error = status.New(status.EndorserClientStatus, status.ConnectionFailed.ToInt32(), "test", nil)
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+	assert.NoError(t, WithRetryBudget(1, time.Minute)(chClient))
+	assert.Error(t, WithRetryBudget(1, 0)(chClient))
+
+	retryOpts := retry.Opts{
+		Attempts:       3,
+		BackoffFactor:  1,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		RetryableCodes: retry.ChannelClientRetryableCodes,
+	}
+	_, err := chClient.Query(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}},
+		WithRetry(retryOpts))
+	assert.Error(t, err, "expected error")
+	assert.Equal(t, 2, testPeer1.ProcessProposalCalls, "expected a single retry within the budget")
+}
+
+func TestConflictResubmit(t *testing.T) {
+	mockEventService := fcmocks.NewMockEventService()
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+
+	validationCodes := []pb.TxValidationCode{pb.TxValidationCode_MVCC_READ_CONFLICT, pb.TxValidationCode_PHANTOM_READ_CONFLICT, pb.TxValidationCode_VALID}
+	go func() {
+		for _, validationCode := range validationCodes {
+			select {
+			case txStatusReg := <-mockEventService.TxStatusRegCh:
+				txStatusReg.Eventch <- &fab.TxStatusEvent{TxID: txStatusReg.TxID, TxValidationCode: validationCode}
+			case <-time.After(time.Second * 5):
+				panic("Timed out waiting for execute Tx to register event callback")
+			}
+		}
+	}()
+
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+	chClient.eventService = mockEventService
+	assert.Error(t, WithConflictResubmit(-1)(chClient))
+	assert.NoError(t, WithConflictResubmit(2)(chClient))
+
+	response, err := chClient.Execute(Request{ChaincodeID: "test", Fcn: "invoke",
+		Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}})
+	assert.NoError(t, err)
+	assert.Equal(t, pb.TxValidationCode_VALID, response.TxValidationCode)
+	assert.Equal(t, 3, testPeer1.ProcessProposalCalls, "Expected the transaction to be endorsed three times")
+}
+
+func TestConflictResubmitExhausted(t *testing.T) {
+	mockEventService := fcmocks.NewMockEventService()
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+
+	go func() {
+		for i := 0; i < 2; i++ {
+			select {
+			case txStatusReg := <-mockEventService.TxStatusRegCh:
+				txStatusReg.Eventch <- &fab.TxStatusEvent{TxID: txStatusReg.TxID, TxValidationCode: pb.TxValidationCode_MVCC_READ_CONFLICT}
+			case <-time.After(time.Second * 5):
+				panic("Timed out waiting for execute Tx to register event callback")
+			}
+		}
+	}()
+
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+	chClient.eventService = mockEventService
+	assert.NoError(t, WithConflictResubmit(1)(chClient))
+
+	_, err := chClient.Execute(Request{ChaincodeID: "test", Fcn: "invoke",
+		Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}})
+	assert.True(t, status.IsCategory(err, status.Conflict), "Expected conflict error got %+v", err)
+	assert.Equal(t, 2, testPeer1.ProcessProposalCalls, "Expected the transaction to be resubmitted once")
+}
+
+// conflictingHandler fails with a wrapped conflict until it has been called the given number of times
+type conflictingHandler struct {
+	conflicts int
+	calls     int
+}
+
+func (h *conflictingHandler) Handle(requestContext *invoke.RequestContext, clientContext *invoke.ClientContext) {
+	h.calls++
+	if h.calls <= h.conflicts {
+		requestContext.Error = errors.WithMessage(errors.Wrap(status.New(status.EventServerStatus,
+			int32(pb.TxValidationCode_MVCC_READ_CONFLICT), "conflict", nil), "commit failed"), "execute failed")
+	}
+}
+
+func TestConflictResubmitWrappedError(t *testing.T) {
+	chClient := setupChannelClient(nil, t)
+	assert.NoError(t, WithConflictResubmit(2)(chClient))
+	request := Request{ChaincodeID: "testCC", Fcn: "move", Args: [][]byte{[]byte("a"), []byte("b"), []byte("1")}}
+
+	handler := &conflictingHandler{conflicts: 2}
+	_, err := chClient.InvokeHandler(handler, request)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, handler.calls, "Expected the wrapped conflict to be resubmitted twice")
+
+	handler = &conflictingHandler{conflicts: 3}
+	_, err = chClient.InvokeHandler(handler, request)
+	assert.True(t, status.IsCategory(err, status.Conflict), "Expected conflict error got %+v", err)
+	assert.Equal(t, 3, handler.calls, "Expected the resubmissions to be exhausted")
+}
@@ -15,16 +15,24 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
 
 // Opts allows the user to specify more advanced options
 type Opts struct {
-	Targets       []fab.Peer // targets
-	TargetFilter  fab.TargetFilter
-	Retry         retry.Opts
-	Timeouts      map[fab.TimeoutType]time.Duration
-	ParentContext reqContext.Context //parent grpc context
+	Targets         []fab.Peer // targets
+	TargetFilter    fab.TargetFilter
+	Retry           retry.Opts
+	Timeouts        map[fab.TimeoutType]time.Duration
+	ParentContext   reqContext.Context  //parent grpc context
+	Coverage        bool                //endorse with a set of peers which satisfies the endorsement policy
+	Transient       map[string][]byte   //transient entries added to the transient map of the request
+	Headers         map[string][]byte   //application headers of the proposal
+	MinEndorsements int                 //number of endorsements after which the remaining proposals are cancelled
+	EarlyCompletion bool                //complete the endorsement as soon as the endorsement policy is satisfied (requires PolicyProvider)
+	Identity        msp.SigningIdentity //identity which signs the request instead of the identity of the client
 }
 
 // Request contains the parameters to execute transaction
@@ -58,6 +66,13 @@ type ClientContext struct {
 	Membership   fab.ChannelMembership
 	Transactor   fab.Transactor
 	EventService fab.EventService
+	// PolicyProvider provides the endorsement policies of the chaincodes (required by Opts.Coverage and Opts.EarlyCompletion)
+	PolicyProvider ChaincodePolicyProvider
+}
+
+// ChaincodePolicyProvider provides the endorsement policy of a chaincode
+type ChaincodePolicyProvider interface {
+	GetChaincodePolicy(chaincodeID string) (*common.SignaturePolicyEnvelope, error)
 }
 
 //RequestContext contains request, opts, response parameters for handler execution
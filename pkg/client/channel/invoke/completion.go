@@ -0,0 +1,82 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/dynamicselection/pgresolver"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+// endorsementCompletion returns the function which completes the endorsement as soon as Opts.MinEndorsements
+// targets endorsed the proposal or, with Opts.EarlyCompletion, the endorsements satisfy the endorsement policy
+// of the chaincode. Returns nil if the responses of all the targets are required.
+func endorsementCompletion(requestContext *RequestContext, clientContext *ClientContext) (fab.ProposalCompletion, error) {
+	minEndorsements := requestContext.Opts.MinEndorsements
+	if minEndorsements <= 0 && !requestContext.Opts.EarlyCompletion {
+		return nil, nil
+	}
+
+	var policySatisfied func(endorsers []fab.Peer) bool
+	if requestContext.Opts.EarlyCompletion {
+		resolver, err := policyResolver(requestContext.Request.ChaincodeID, clientContext)
+		if err != nil {
+			return nil, err
+		}
+		policySatisfied = func(endorsers []fab.Peer) bool {
+			group, err := resolver.Resolve(endorsers)
+			return err == nil && group != nil && len(group.Peers()) > 0
+		}
+	}
+
+	targets := make(map[string]fab.Peer)
+	for _, p := range requestContext.Opts.Targets {
+		targets[p.URL()] = p
+	}
+
+	return func(responses []*fab.TransactionProposalResponse) bool {
+		var endorsers []fab.Peer
+		for _, r := range responses {
+			if r.ProposalResponse.GetResponse().Status != int32(common.Status_SUCCESS) {
+				continue
+			}
+			if p, ok := targets[r.Endorser]; ok {
+				endorsers = append(endorsers, p)
+			}
+		}
+		if minEndorsements > 0 && len(endorsers) >= minEndorsements {
+			logger.Debugf("Received %d endorsements - endorsement completed", len(endorsers))
+			return true
+		}
+		if policySatisfied != nil && policySatisfied(endorsers) {
+			logger.Debugf("Endorsement policy satisfied by %d endorsements - endorsement completed", len(endorsers))
+			return true
+		}
+		return false
+	}, nil
+}
+
+// policyResolver returns the resolver which finds the groups of peers satisfying the endorsement policy of the chaincode
+func policyResolver(ccID string, clientContext *ClientContext) (pgresolver.PeerGroupResolver, error) {
+	if clientContext.PolicyProvider == nil {
+		return nil, errors.New("chaincode policy provider is required for early completion")
+	}
+
+	policy, err := clientContext.PolicyProvider.GetChaincodePolicy(ccID)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("failed to get endorsement policy of chaincode [%s]", ccID))
+	}
+	groupRetriever, err := pgresolver.CompileSignaturePolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+	return pgresolver.NewPeerGroupResolver(groupRetriever, &coverageLBP{})
+}
@@ -0,0 +1,83 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	reqContext "context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+)
+
+func TestEndorsementHandlerEarlyCompletion(t *testing.T) {
+	org1Peer := &fcmocks.MockPeer{MockURL: "peer0.org1.com", MockMSP: "Org1MSP", Status: 200, Payload: []byte("value")}
+	org2Peer := &fcmocks.MockPeer{MockURL: "peer0.org2.com", MockMSP: "Org2MSP", Status: 200, Payload: []byte("value")}
+	slowPeer := newSlowPeer("peer1.org2.com", "Org2MSP")
+	peers := []fab.Peer{org1Peer, org2Peer, slowPeer}
+
+	clientContext := setupCoverageClientContext(t, peers, "Org1MSP", "Org2MSP")
+	requestContext := prepareRequestContext(Request{ChaincodeID: "test", Fcn: "invoke"}, Opts{Targets: peers, EarlyCompletion: true}, t)
+
+	NewEndorsementHandler().Handle(requestContext, clientContext)
+	require.NoError(t, requestContext.Error)
+	assert.Equal(t, []string{"peer0.org1.com", "peer0.org2.com"}, endorsers(requestContext.Response.Responses))
+	assert.Equal(t, []byte("value"), requestContext.Response.Payload)
+	slowPeer.assertCancelled(t)
+
+	// The policy provider is required
+	clientContext.PolicyProvider = nil
+	requestContext = prepareRequestContext(Request{ChaincodeID: "test", Fcn: "invoke"}, Opts{Targets: peers, EarlyCompletion: true}, t)
+	NewEndorsementHandler().Handle(requestContext, clientContext)
+	require.Error(t, requestContext.Error)
+	assert.Contains(t, requestContext.Error.Error(), "policy provider is required")
+}
+
+func TestEndorsementHandlerMinEndorsements(t *testing.T) {
+	org1Peer := &fcmocks.MockPeer{MockURL: "peer0.org1.com", MockMSP: "Org1MSP", Status: 200, Payload: []byte("value")}
+	slowPeer := newSlowPeer("peer1.org1.com", "Org1MSP")
+	peers := []fab.Peer{org1Peer, slowPeer}
+
+	clientContext := setupChannelClientContext(nil, nil, nil, t)
+	requestContext := prepareRequestContext(Request{ChaincodeID: "test", Fcn: "invoke"}, Opts{Targets: peers, MinEndorsements: 1}, t)
+
+	NewEndorsementHandler().Handle(requestContext, clientContext)
+	require.NoError(t, requestContext.Error)
+	assert.Equal(t, []string{"peer0.org1.com"}, endorsers(requestContext.Response.Responses))
+	slowPeer.assertCancelled(t)
+}
+
+// slowPeer doesn't respond until its proposal is cancelled
+type slowPeer struct {
+	*fcmocks.MockPeer
+	cancelled chan struct{}
+}
+
+func newSlowPeer(url, mspID string) *slowPeer {
+	return &slowPeer{
+		MockPeer:  &fcmocks.MockPeer{MockURL: url, MockMSP: mspID, Status: 200},
+		cancelled: make(chan struct{}),
+	}
+}
+
+func (p *slowPeer) ProcessTransactionProposal(ctx reqContext.Context, request fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
+	<-ctx.Done()
+	close(p.cancelled)
+	return nil, ctx.Err()
+}
+
+func (p *slowPeer) assertCancelled(t *testing.T) {
+	select {
+	case <-p.cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected proposal to peer [%s] to be cancelled", p.MockURL)
+	}
+}
@@ -0,0 +1,198 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/dynamicselection/pgresolver"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+// handleCoverage endorses the proposal with a set of peers which satisfies the endorsement policy of the chaincode
+func (e *EndorsementHandler) handleCoverage(requestContext *RequestContext, clientContext *ClientContext) {
+	proposal, err := createTransactionProposal(clientContext.Transactor, &requestContext.Request, &requestContext.Opts)
+	if err != nil {
+		requestContext.Error = err
+		return
+	}
+	requestContext.Response.Proposal = proposal
+	requestContext.Response.TransactionID = proposal.TxnID
+
+	transactionProposalResponses, err := endorseWithCoverage(requestContext, clientContext, proposal)
+	if err != nil {
+		requestContext.Error = err
+		return
+	}
+
+	requestContext.Response.Responses = transactionProposalResponses
+	requestContext.Response.Payload = transactionProposalResponses[0].ProposalResponse.GetResponse().Payload
+	requestContext.Response.ChaincodeStatus = transactionProposalResponses[0].ChaincodeStatus
+
+	//Delegate to next step if any
+	if e.next != nil {
+		e.next.Handle(requestContext, clientContext)
+	}
+}
+
+// endorseWithCoverage sends the proposal to a minimal group of peers which satisfies the endorsement policy.
+// The peers which fail are excluded and the proposal is sent to the peers of another group which includes
+// as many successful endorsers as possible, until all the peers of a group endorsed the proposal.
+func endorseWithCoverage(requestContext *RequestContext, clientContext *ClientContext, proposal *fab.TransactionProposal) ([]*fab.TransactionProposalResponse, error) {
+	if clientContext.PolicyProvider == nil {
+		return nil, errors.New("chaincode policy provider is required for policy coverage")
+	}
+
+	ccID := requestContext.Request.ChaincodeID
+	policy, err := clientContext.PolicyProvider.GetChaincodePolicy(ccID)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("failed to get endorsement policy of chaincode [%s]", ccID))
+	}
+	groupRetriever, err := pgresolver.CompileSignaturePolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	peers, err := coverageCandidates(requestContext, clientContext)
+	if err != nil {
+		return nil, err
+	}
+
+	endorsed := make(map[string]*fab.TransactionProposalResponse)
+	failed := make(map[string]bool)
+	var errs error
+	for {
+		var available []fab.Peer
+		for _, p := range peers {
+			if !failed[p.URL()] {
+				available = append(available, p)
+			}
+		}
+
+		resolver, err := pgresolver.NewPeerGroupResolver(groupRetriever, &coverageLBP{endorsed: endorsed})
+		if err != nil {
+			return nil, err
+		}
+		group, err := resolver.Resolve(available)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to resolve endorsers")
+		}
+		if group == nil || len(group.Peers()) == 0 {
+			msg := fmt.Sprintf("endorsement policy of chaincode [%s] can't be satisfied by the available peers", ccID)
+			if errs != nil {
+				msg = fmt.Sprintf("%s: %s", msg, errs)
+			}
+			return nil, status.New(status.ClientStatus, status.NoPeersFound.ToInt32(), msg, nil)
+		}
+
+		var pending []fab.Peer
+		for _, p := range group.Peers() {
+			if endorsed[p.URL()] == nil {
+				pending = append(pending, p)
+			}
+		}
+		if len(pending) == 0 {
+			responses := make([]*fab.TransactionProposalResponse, 0, len(group.Peers()))
+			for _, p := range group.Peers() {
+				responses = append(responses, endorsed[p.URL()])
+			}
+			return responses, nil
+		}
+
+		logger.Debugf("Sending proposal for chaincode [%s] to %d peers (%d endorsements, %d failed peers)", ccID, len(pending), len(endorsed), len(failed))
+		responses, err := clientContext.Transactor.SendTransactionProposal(proposal, peer.PeersToTxnProcessors(pending))
+		if err != nil {
+			if isChaincodeError(err) {
+				// The chaincode would fail on the other peers as well
+				return nil, err
+			}
+			errs = multi.Append(errs, err)
+		}
+		for _, r := range responses {
+			if r.ProposalResponse.GetResponse().Status == int32(common.Status_SUCCESS) {
+				endorsed[r.Endorser] = r
+			}
+		}
+		for _, p := range pending {
+			if endorsed[p.URL()] == nil {
+				logger.Debugf("Peer [%s] failed to endorse proposal for chaincode [%s]", p.URL(), ccID)
+				failed[p.URL()] = true
+			}
+		}
+	}
+}
+
+// coverageCandidates returns the peers which may endorse the proposal
+func coverageCandidates(requestContext *RequestContext, clientContext *ClientContext) ([]fab.Peer, error) {
+	if len(requestContext.Opts.Targets) > 0 {
+		return requestContext.Opts.Targets, nil
+	}
+
+	peers, err := clientContext.Discovery.GetPeers()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get peers of channel")
+	}
+	if requestContext.SelectionFilter == nil {
+		return peers, nil
+	}
+	var filtered []fab.Peer
+	for _, p := range peers {
+		if requestContext.SelectionFilter(p) {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered, nil
+}
+
+func isChaincodeError(err error) bool {
+	errs, ok := errors.Cause(err).(multi.Errors)
+	if !ok {
+		errs = multi.Errors{err}
+	}
+	for _, e := range errs {
+		if s, ok := status.FromError(e); ok && s.Group == status.ChaincodeStatus {
+			return true
+		}
+	}
+	return false
+}
+
+// coverageLBP chooses the peer group with the fewest peers which haven't endorsed the proposal yet
+// and, among those, the smallest group
+type coverageLBP struct {
+	endorsed map[string]*fab.TransactionProposalResponse
+}
+
+func (lbp *coverageLBP) Choose(peerGroups []pgresolver.PeerGroup) pgresolver.PeerGroup {
+	var chosen pgresolver.PeerGroup
+	chosenPending := 0
+	for _, group := range peerGroups {
+		pending := 0
+		for _, p := range group.Peers() {
+			if lbp.endorsed[p.URL()] == nil {
+				pending++
+			}
+		}
+		if chosen == nil || pending < chosenPending || (pending == chosenPending && len(group.Peers()) < len(chosen.Peers())) {
+			chosen = group
+			chosenPending = pending
+		}
+	}
+	if chosen == nil {
+		return pgresolver.NewPeerGroup()
+	}
+	return chosen
+}
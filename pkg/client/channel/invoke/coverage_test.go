@@ -0,0 +1,105 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/dynamicselection/pgresolver"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+func TestEndorsementHandlerCoverage(t *testing.T) {
+	failingPeer := &fcmocks.MockPeer{MockURL: "peer0.org1.com", MockMSP: "Org1MSP", Status: 200, Error: errors.New("connection refused")}
+	org1Peer := &fcmocks.MockPeer{MockURL: "peer1.org1.com", MockMSP: "Org1MSP", Status: 200, Payload: []byte("value")}
+	org2Peer := &fcmocks.MockPeer{MockURL: "peer0.org2.com", MockMSP: "Org2MSP", Status: 200, Payload: []byte("value")}
+	org3Peer := &fcmocks.MockPeer{MockURL: "peer0.org3.com", MockMSP: "Org3MSP", Status: 200, Payload: []byte("value")}
+	peers := []fab.Peer{failingPeer, org1Peer, org2Peer, org3Peer}
+
+	requestContext := prepareRequestContext(Request{ChaincodeID: "test", Fcn: "invoke"}, Opts{Coverage: true}, t)
+	clientContext := setupCoverageClientContext(t, peers, "Org1MSP", "Org2MSP")
+
+	NewEndorsementHandler().Handle(requestContext, clientContext)
+	require.NoError(t, requestContext.Error)
+
+	// Org3 isn't required and the successful endorsement of Org2 isn't requested again
+	assert.Equal(t, []string{"peer0.org2.com", "peer1.org1.com"}, endorsers(requestContext.Response.Responses))
+	assert.Equal(t, 1, org2Peer.ProcessProposalCalls)
+	assert.Equal(t, 0, org3Peer.ProcessProposalCalls)
+	assert.Equal(t, []byte("value"), requestContext.Response.Payload)
+	assert.NotEmpty(t, requestContext.Response.TransactionID)
+
+	// Targets restrict the candidates
+	requestContext = prepareRequestContext(Request{ChaincodeID: "test", Fcn: "invoke"}, Opts{Coverage: true, Targets: []fab.Peer{failingPeer, org2Peer}}, t)
+	NewEndorsementHandler().Handle(requestContext, clientContext)
+	require.Error(t, requestContext.Error)
+	s, ok := status.FromError(requestContext.Error)
+	require.True(t, ok)
+	assert.Equal(t, status.NoPeersFound.ToInt32(), s.Code)
+	assert.Contains(t, requestContext.Error.Error(), "connection refused")
+}
+
+func TestEndorsementHandlerCoverageChaincodeError(t *testing.T) {
+	org1Peer := &fcmocks.MockPeer{MockURL: "peer0.org1.com", MockMSP: "Org1MSP", Status: 200, Error: status.NewFromExtractedChaincodeError(500, "invalid arguments")}
+	otherOrg1Peer := &fcmocks.MockPeer{MockURL: "peer1.org1.com", MockMSP: "Org1MSP", Status: 200}
+
+	requestContext := prepareRequestContext(Request{ChaincodeID: "test", Fcn: "invoke"}, Opts{Coverage: true, Targets: []fab.Peer{org1Peer}}, t)
+	clientContext := setupCoverageClientContext(t, []fab.Peer{org1Peer, otherOrg1Peer}, "Org1MSP")
+
+	NewEndorsementHandler().Handle(requestContext, clientContext)
+	require.Error(t, requestContext.Error)
+	assert.Contains(t, requestContext.Error.Error(), "invalid arguments")
+	assert.Equal(t, 0, otherOrg1Peer.ProcessProposalCalls)
+
+	clientContext.PolicyProvider = &mockPolicyProvider{err: errors.New("lscc unavailable")}
+	requestContext = prepareRequestContext(Request{ChaincodeID: "test", Fcn: "invoke"}, Opts{Coverage: true}, t)
+	NewEndorsementHandler().Handle(requestContext, clientContext)
+	require.Error(t, requestContext.Error)
+	assert.Contains(t, requestContext.Error.Error(), "lscc unavailable")
+}
+
+func setupCoverageClientContext(t *testing.T, peers []fab.Peer, mspIDs ...string) *ClientContext {
+	clientContext := setupChannelClientContext(nil, nil, nil, t)
+
+	discoveryService, err := setupTestDiscovery(nil, peers)
+	require.NoError(t, err)
+	clientContext.Discovery = discoveryService
+
+	signedBy, identities, err := pgresolver.GetPolicies(mspIDs...)
+	require.NoError(t, err)
+	clientContext.PolicyProvider = &mockPolicyProvider{policy: &common.SignaturePolicyEnvelope{
+		Rule:       pgresolver.NewNOutOfPolicy(int32(len(mspIDs)), signedBy...),
+		Identities: identities,
+	}}
+	return clientContext
+}
+
+func endorsers(responses []*fab.TransactionProposalResponse) []string {
+	var urls []string
+	for _, r := range responses {
+		urls = append(urls, r.Endorser)
+	}
+	sort.Strings(urls)
+	return urls
+}
+
+type mockPolicyProvider struct {
+	policy *common.SignaturePolicyEnvelope
+	err    error
+}
+
+func (p *mockPolicyProvider) GetChaincodePolicy(chaincodeID string) (*common.SignaturePolicyEnvelope, error) {
+	return p.policy, p.err
+}
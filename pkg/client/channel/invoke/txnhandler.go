@@ -29,13 +29,24 @@ type EndorsementHandler struct {
 //Handle for endorsing transactions
 func (e *EndorsementHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
 
+	if requestContext.Opts.Coverage {
+		e.handleCoverage(requestContext, clientContext)
+		return
+	}
+
 	if len(requestContext.Opts.Targets) == 0 {
 		requestContext.Error = status.New(status.ClientStatus, status.NoPeersFound.ToInt32(), "targets were not provided", nil)
 		return
 	}
 
+	completion, err := endorsementCompletion(requestContext, clientContext)
+	if err != nil {
+		requestContext.Error = err
+		return
+	}
+
 	// Endorse Tx
-	transactionProposalResponses, proposal, err := createAndSendTransactionProposal(clientContext.Transactor, &requestContext.Request, peer.PeersToTxnProcessors(requestContext.Opts.Targets))
+	transactionProposalResponses, proposal, err := createAndSendTransactionProposal(clientContext.Transactor, &requestContext.Request, &requestContext.Opts, peer.PeersToTxnProcessors(requestContext.Opts.Targets), completion)
 
 	requestContext.Response.Proposal = proposal
 	requestContext.Response.TransactionID = proposal.TxnID // TODO: still needed?
@@ -65,7 +76,8 @@ type ProposalProcessorHandler struct {
 //Handle selects proposal processors
 func (h *ProposalProcessorHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
 	//Get proposal processor, if not supplied then use selection service to get available peers as endorser
-	if len(requestContext.Opts.Targets) == 0 {
+	//(with policy coverage the endorsers are chosen by the endorsement handler)
+	if len(requestContext.Opts.Targets) == 0 && !requestContext.Opts.Coverage {
 		var selectionOpts []options.Opt
 		if requestContext.SelectionFilter != nil {
 			selectionOpts = append(selectionOpts, selectopts.WithPeerFilter(requestContext.SelectionFilter))
@@ -238,25 +250,51 @@ func createAndSendTransaction(sender fab.Sender, proposal *fab.TransactionPropos
 	return transactionResponse, nil
 }
 
-func createAndSendTransactionProposal(transactor fab.ProposalSender, chrequest *Request, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, *fab.TransactionProposal, error) {
+func createAndSendTransactionProposal(transactor fab.ProposalSender, chrequest *Request, opts *Opts, targets []fab.ProposalProcessor, completion fab.ProposalCompletion) ([]*fab.TransactionProposalResponse, *fab.TransactionProposal, error) {
+	proposal, err := createTransactionProposal(transactor, chrequest, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if completion != nil {
+		if sender, ok := transactor.(fab.CompletingProposalSender); ok {
+			transactionProposalResponses, err := sender.SendTransactionProposalUntil(proposal, targets, completion)
+			return transactionProposalResponses, proposal, err
+		}
+		logger.Debugf("Transactor doesn't support early completion - waiting for the responses of all the targets")
+	}
+
+	transactionProposalResponses, err := transactor.SendTransactionProposal(proposal, targets)
+
+	return transactionProposalResponses, proposal, err
+}
+
+func createTransactionProposal(transactor fab.ProposalSender, chrequest *Request, opts *Opts) (*fab.TransactionProposal, error) {
 	request := fab.ChaincodeInvokeRequest{
 		ChaincodeID:  chrequest.ChaincodeID,
 		Fcn:          chrequest.Fcn,
 		Args:         chrequest.Args,
 		TransientMap: chrequest.TransientMap,
+		Headers:      opts.Headers,
+	}
+	if len(opts.Transient) > 0 {
+		request.TransientMap = make(map[string][]byte, len(chrequest.TransientMap)+len(opts.Transient))
+		for k, v := range chrequest.TransientMap {
+			request.TransientMap[k] = v
+		}
+		for k, v := range opts.Transient {
+			request.TransientMap[k] = v
+		}
 	}
 
 	txh, err := transactor.CreateTransactionHeader()
 	if err != nil {
-		return nil, nil, errors.WithMessage(err, "creating transaction header failed")
+		return nil, errors.WithMessage(err, "creating transaction header failed")
 	}
 
 	proposal, err := txn.CreateChaincodeInvokeProposal(txh, request)
 	if err != nil {
-		return nil, nil, errors.WithMessage(err, "creating transaction proposal failed")
+		return nil, errors.WithMessage(err, "creating transaction proposal failed")
 	}
-
-	transactionProposalResponses, err := transactor.SendTransactionProposal(proposal, targets)
-
-	return transactionProposalResponses, proposal, err
+	return proposal, nil
 }
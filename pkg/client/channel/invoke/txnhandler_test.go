@@ -21,6 +21,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
@@ -156,6 +157,31 @@ func TestEndorsementHandler(t *testing.T) {
 	assert.Nil(t, requestContext.Error)
 }
 
+func TestEndorsementHandlerProposalMetadata(t *testing.T) {
+	request := Request{ChaincodeID: "test", Fcn: "invoke", TransientMap: map[string][]byte{"a": []byte("1"), "b": []byte("2")}}
+	opts := Opts{
+		Targets:   []fab.Peer{fcmocks.NewMockPeer("p2", "")},
+		Transient: map[string][]byte{"b": []byte("3")},
+		Headers:   map[string][]byte{"correlation-id": []byte("abc-123")},
+	}
+
+	requestContext := prepareRequestContext(request, opts, t)
+	clientContext := setupChannelClientContext(nil, nil, nil, t)
+
+	handler := NewEndorsementHandler()
+	handler.Handle(requestContext, clientContext)
+	assert.Nil(t, requestContext.Error)
+
+	transient, err := txn.ProposalTransient(requestContext.Response.Proposal.Proposal)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string][]byte{"a": []byte("1"), "b": []byte("3")}, transient)
+	assert.Equal(t, []byte("2"), request.TransientMap["b"], "expecting transient map of request not to be modified")
+
+	headers, err := txn.ProposalHeaders(requestContext.Response.Proposal.Proposal)
+	assert.Nil(t, err)
+	assert.Equal(t, opts.Headers, headers)
+}
+
 // Target filter
 type filter struct {
 	peer fab.Peer
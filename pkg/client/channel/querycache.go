@@ -0,0 +1,375 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+const defaultQueryCacheMaxEntries = 1000
+
+// queryCache caches the responses of chaincode queries by chaincode, function and arguments.
+// The namespaces read by each query are taken from the read-write set of its response and the
+// cached response is invalidated when a valid transaction which writes to any of these namespaces
+// is committed (as observed from block events). If the client isn't allowed to receive (full)
+// block events then filtered block events are used and all responses are invalidated whenever
+// a valid transaction is committed.
+type queryCache struct {
+	maxAge       time.Duration
+	maxEntries   int
+	eventService fab.EventService
+	reg          fab.Registration
+	now          func() time.Time
+
+	lock    sync.Mutex
+	entries map[[sha256.Size]byte]*queryCacheEntry
+	// generation is incremented on each invalidation. Queries record the generation when they
+	// start so that responses which may have been invalidated while in flight are not cached.
+	generation    uint64
+	nsGenerations map[string]uint64
+	allGeneration uint64
+}
+
+type queryCacheEntry struct {
+	response   Response
+	namespaces []string
+	added      time.Time
+}
+
+func newQueryCache(eventService fab.EventService, maxAge time.Duration, maxEntries int) (*queryCache, error) {
+	if maxEntries <= 0 {
+		maxEntries = defaultQueryCacheMaxEntries
+	}
+
+	c := &queryCache{
+		maxAge:        maxAge,
+		maxEntries:    maxEntries,
+		eventService:  eventService,
+		now:           time.Now,
+		entries:       make(map[[sha256.Size]byte]*queryCacheEntry),
+		nsGenerations: make(map[string]uint64),
+	}
+
+	reg, blockch, err := eventService.RegisterBlockEvent()
+	if err == nil {
+		c.reg = reg
+		go c.listen(blockch)
+		return c, nil
+	}
+
+	logger.Warnf("Unable to register for block events (%s) - all cached query responses will be invalidated when a transaction is committed", err)
+
+	reg, filteredBlockch, err := eventService.RegisterFilteredBlockEvent()
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to register for filtered block events")
+	}
+	c.reg = reg
+	go c.listenFiltered(filteredBlockch)
+
+	return c, nil
+}
+
+// close stops invalidating the cache and clears it
+func (c *queryCache) close() {
+	c.eventService.Unregister(c.reg)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.entries = make(map[[sha256.Size]byte]*queryCacheEntry)
+}
+
+func (c *queryCache) listen(blockch <-chan *fab.BlockEvent) {
+	for event := range blockch {
+		namespaces, all := writtenNamespaces(event.Block)
+		if all {
+			c.invalidateAll()
+		} else if len(namespaces) > 0 {
+			c.invalidate(namespaces)
+		}
+	}
+	logger.Debugf("Block event channel closed - query cache is no longer invalidated")
+}
+
+func (c *queryCache) listenFiltered(filteredBlockch <-chan *fab.FilteredBlockEvent) {
+	for event := range filteredBlockch {
+		for _, tx := range event.FilteredBlock.FilteredTransactions {
+			if tx.TxValidationCode == pb.TxValidationCode_VALID {
+				c.invalidateAll()
+				break
+			}
+		}
+	}
+	logger.Debugf("Filtered block event channel closed - query cache is no longer invalidated")
+}
+
+// start returns the generation of the cache at the start of a query
+func (c *queryCache) start() uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.generation
+}
+
+func (c *queryCache) get(request Request) (Response, bool) {
+	key := queryCacheKey(request)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return Response{}, false
+	}
+	if c.maxAge > 0 && c.now().Sub(entry.added) >= c.maxAge {
+		delete(c.entries, key)
+		return Response{}, false
+	}
+	return entry.response, true
+}
+
+// put caches the response of a query which started at the given generation. The response
+// isn't cached if any of the namespaces read by the query were invalidated since then.
+func (c *queryCache) put(request Request, response Response, generation uint64) {
+	namespaces := readNamespaces(request, response)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.allGeneration > generation {
+		return
+	}
+	for _, ns := range namespaces {
+		if c.nsGenerations[ns] > generation {
+			logger.Debugf("Namespace [%s] was updated during query - response not cached", ns)
+			return
+		}
+	}
+
+	if len(c.entries) >= c.maxEntries {
+		c.evictOldest()
+	}
+	c.entries[queryCacheKey(request)] = &queryCacheEntry{
+		response:   response,
+		namespaces: namespaces,
+		added:      c.now(),
+	}
+}
+
+func (c *queryCache) invalidate(namespaces map[string]struct{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.generation++
+	for ns := range namespaces {
+		c.nsGenerations[ns] = c.generation
+	}
+
+	for key, entry := range c.entries {
+		for _, ns := range entry.namespaces {
+			if _, ok := namespaces[ns]; ok {
+				delete(c.entries, key)
+				break
+			}
+		}
+	}
+}
+
+func (c *queryCache) invalidateAll() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.generation++
+	c.allGeneration = c.generation
+	c.entries = make(map[[sha256.Size]byte]*queryCacheEntry)
+}
+
+func (c *queryCache) evictOldest() {
+	var oldestKey [sha256.Size]byte
+	var oldest *queryCacheEntry
+	for key, entry := range c.entries {
+		if oldest == nil || entry.added.Before(oldest.added) {
+			oldestKey = key
+			oldest = entry
+		}
+	}
+	if oldest != nil {
+		delete(c.entries, oldestKey)
+	}
+}
+
+// queryCacheKey returns the key of the request, which is a hash of the length-prefixed
+// chaincode ID, function and arguments
+func queryCacheKey(request Request) [sha256.Size]byte {
+	h := sha256.New()
+	write := func(b []byte) {
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(b)))
+		h.Write(length[:])
+		h.Write(b)
+	}
+
+	write([]byte(request.ChaincodeID))
+	write([]byte(request.Fcn))
+	for _, arg := range request.Args {
+		write(arg)
+	}
+
+	var key [sha256.Size]byte
+	copy(key[:], h.Sum(nil))
+	return key
+}
+
+// readNamespaces returns the namespaces in the read-write set of the response. If the
+// read-write set isn't available then the namespace of the chaincode is returned.
+func readNamespaces(request Request, response Response) []string {
+	if len(response.Responses) > 0 && response.Responses[0].ProposalResponse != nil {
+		txRWSet, err := responseRWSet(response.Responses[0].ProposalResponse)
+		if err != nil {
+			logger.Debugf("Unable to get read-write set of query response: %s", err)
+		} else if len(txRWSet.NsRwset) > 0 {
+			var namespaces []string
+			for _, nsRWSet := range txRWSet.NsRwset {
+				namespaces = append(namespaces, nsRWSet.Namespace)
+			}
+			return namespaces
+		}
+	}
+	return []string{request.ChaincodeID}
+}
+
+func responseRWSet(response *pb.ProposalResponse) (*rwset.TxReadWriteSet, error) {
+	if len(response.Payload) == 0 {
+		return nil, errors.New("proposal response payload is empty")
+	}
+	propRespPayload, err := utils.GetProposalResponsePayload(response.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling response payload")
+	}
+	return chaincodeActionRWSet(propRespPayload.Extension)
+}
+
+func chaincodeActionRWSet(extension []byte) (*rwset.TxReadWriteSet, error) {
+	ccAction, err := utils.GetChaincodeAction(extension)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling chaincode action")
+	}
+	txRWSet := &rwset.TxReadWriteSet{}
+	if err := proto.Unmarshal(ccAction.Results, txRWSet); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling read-write set")
+	}
+	return txRWSet, nil
+}
+
+// writtenNamespaces returns the namespaces written by the valid transactions of the block. If the
+// writes of a valid transaction can't be determined then all namespaces are considered to be written.
+func writtenNamespaces(block *cb.Block) (map[string]struct{}, bool) {
+	namespaces := make(map[string]struct{})
+	if block.Data == nil {
+		return namespaces, false
+	}
+
+	var txFilter ledgerutil.TxValidationFlags
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txFilter = ledgerutil.TxValidationFlags(block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+
+	for i, data := range block.Data.Data {
+		if i < len(txFilter) && !txFilter.IsValid(i) {
+			continue
+		}
+		if err := addWrittenNamespaces(data, namespaces); err != nil {
+			logger.Warnf("Unable to determine the namespaces written by transaction %d of block %d: %s", i, block.Header.GetNumber(), err)
+			return nil, true
+		}
+	}
+	return namespaces, false
+}
+
+func addWrittenNamespaces(data []byte, namespaces map[string]struct{}) error {
+	env, err := utils.GetEnvelopeFromBlock(data)
+	if err != nil {
+		return errors.Wrap(err, "error extracting Envelope from block")
+	}
+	payload, err := utils.GetPayload(env)
+	if err != nil {
+		return errors.Wrap(err, "error extracting Payload from envelope")
+	}
+	channelHeader := &cb.ChannelHeader{}
+	if err := proto.Unmarshal(payload.Header.ChannelHeader, channelHeader); err != nil {
+		return errors.Wrap(err, "error extracting ChannelHeader from payload")
+	}
+	if cb.HeaderType(channelHeader.Type) != cb.HeaderType_ENDORSER_TRANSACTION {
+		return nil
+	}
+
+	tx, err := utils.GetTransaction(payload.Data)
+	if err != nil {
+		return errors.Wrap(err, "error unmarshalling transaction payload")
+	}
+	for _, action := range tx.Actions {
+		chaincodeActionPayload, err := utils.GetChaincodeActionPayload(action.Payload)
+		if err != nil {
+			return errors.Wrap(err, "error unmarshalling chaincode action payload")
+		}
+		propRespPayload, err := utils.GetProposalResponsePayload(chaincodeActionPayload.Action.ProposalResponsePayload)
+		if err != nil {
+			return errors.Wrap(err, "error unmarshalling response payload")
+		}
+		txRWSet, err := chaincodeActionRWSet(propRespPayload.Extension)
+		if err != nil {
+			return err
+		}
+		for _, nsRWSet := range txRWSet.NsRwset {
+			written, err := hasWrites(nsRWSet)
+			if err != nil {
+				return err
+			}
+			if written {
+				namespaces[nsRWSet.Namespace] = struct{}{}
+			}
+		}
+	}
+	return nil
+}
+
+// hasWrites returns true if the read-write set writes public or private data of the namespace
+func hasWrites(nsRWSet *rwset.NsReadWriteSet) (bool, error) {
+	kvRWSet := &kvrwset.KVRWSet{}
+	if err := proto.Unmarshal(nsRWSet.Rwset, kvRWSet); err != nil {
+		return false, errors.Wrap(err, "error unmarshalling KV read-write set")
+	}
+	if len(kvRWSet.Writes) > 0 {
+		return true, nil
+	}
+
+	for _, collRWSet := range nsRWSet.CollectionHashedRwset {
+		hashedRWSet := &kvrwset.HashedRWSet{}
+		if err := proto.Unmarshal(collRWSet.HashedRwset, hashedRWSet); err != nil {
+			return false, errors.Wrap(err, "error unmarshalling hashed read-write set")
+		}
+		if len(hashedRWSet.HashedWrites) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
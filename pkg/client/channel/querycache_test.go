@@ -0,0 +1,210 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// txRWSet describes the namespaces read and written by a transaction
+type txRWSet struct {
+	reads  []string
+	writes []string
+}
+
+func newTestRWSetBytes(t *testing.T, rws txRWSet) []byte {
+	txRWSet := &rwset.TxReadWriteSet{DataModel: rwset.TxReadWriteSet_KV}
+	for _, ns := range rws.reads {
+		kvRWSet := &kvrwset.KVRWSet{Reads: []*kvrwset.KVRead{{Key: "key"}}}
+		txRWSet.NsRwset = append(txRWSet.NsRwset, &rwset.NsReadWriteSet{Namespace: ns, Rwset: marshal(t, kvRWSet)})
+	}
+	for _, ns := range rws.writes {
+		kvRWSet := &kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{{Key: "key", Value: []byte("value")}}}
+		txRWSet.NsRwset = append(txRWSet.NsRwset, &rwset.NsReadWriteSet{Namespace: ns, Rwset: marshal(t, kvRWSet)})
+	}
+
+	ccAction := &pb.ChaincodeAction{Results: marshal(t, txRWSet)}
+	return marshal(t, &pb.ProposalResponsePayload{Extension: marshal(t, ccAction)})
+}
+
+func newTestBlock(t *testing.T, validationCodes []pb.TxValidationCode, txs ...txRWSet) *cb.Block {
+	block := &cb.Block{
+		Header:   &cb.BlockHeader{Number: 1},
+		Data:     &cb.BlockData{},
+		Metadata: &cb.BlockMetadata{Metadata: make([][]byte, len(cb.BlockMetadataIndex_name))},
+	}
+
+	txFilter := ledgerutil.NewTxValidationFlags(len(txs))
+	for i, rws := range txs {
+		chaincodeActionPayload := &pb.ChaincodeActionPayload{
+			Action: &pb.ChaincodeEndorsedAction{ProposalResponsePayload: newTestRWSetBytes(t, rws)},
+		}
+		tx := &pb.Transaction{Actions: []*pb.TransactionAction{{Payload: marshal(t, chaincodeActionPayload)}}}
+		channelHeader := &cb.ChannelHeader{Type: int32(cb.HeaderType_ENDORSER_TRANSACTION), ChannelId: channelID}
+		payload := &cb.Payload{
+			Header: &cb.Header{ChannelHeader: marshal(t, channelHeader)},
+			Data:   marshal(t, tx),
+		}
+		block.Data.Data = append(block.Data.Data, marshal(t, &cb.Envelope{Payload: marshal(t, payload)}))
+		txFilter[i] = uint8(validationCodes[i])
+	}
+	block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = txFilter
+
+	return block
+}
+
+func marshal(t *testing.T, msg proto.Message) []byte {
+	bytes, err := proto.Marshal(msg)
+	require.NoError(t, err)
+	return bytes
+}
+
+func newTestQueryResponse(t *testing.T, payload string, reads ...string) Response {
+	return Response{
+		Payload: []byte(payload),
+		Responses: []*fab.TransactionProposalResponse{
+			{ProposalResponse: &pb.ProposalResponse{Payload: newTestRWSetBytes(t, txRWSet{reads: reads})}},
+		},
+	}
+}
+
+func TestWrittenNamespaces(t *testing.T) {
+	block := newTestBlock(t,
+		[]pb.TxValidationCode{pb.TxValidationCode_VALID, pb.TxValidationCode_MVCC_READ_CONFLICT, pb.TxValidationCode_VALID},
+		txRWSet{reads: []string{"cc1"}, writes: []string{"cc2"}},
+		txRWSet{writes: []string{"cc3"}},
+		txRWSet{reads: []string{"cc4"}},
+	)
+
+	namespaces, all := writtenNamespaces(block)
+	assert.False(t, all)
+	assert.Equal(t, map[string]struct{}{"cc2": {}}, namespaces, "expected only the namespaces written by valid transactions")
+
+	block.Data.Data[0] = []byte("invalid")
+	_, all = writtenNamespaces(block)
+	assert.True(t, all, "expected all namespaces to be invalidated for transaction which can't be parsed")
+}
+
+func TestQueryCache(t *testing.T) {
+	cache, err := newQueryCache(fcmocks.NewMockEventService(), 0, 0)
+	require.NoError(t, err)
+	defer cache.close()
+
+	request1 := Request{ChaincodeID: "cc1", Fcn: "query", Args: [][]byte{[]byte("a")}}
+	request2 := Request{ChaincodeID: "cc2", Fcn: "query", Args: [][]byte{[]byte("a")}}
+
+	_, ok := cache.get(request1)
+	assert.False(t, ok)
+
+	cache.put(request1, newTestQueryResponse(t, "r1", "cc1", "cc3"), cache.start())
+	cache.put(request2, newTestQueryResponse(t, "r2", "cc2"), cache.start())
+
+	response, ok := cache.get(request1)
+	require.True(t, ok)
+	assert.Equal(t, "r1", string(response.Payload))
+
+	_, ok = cache.get(Request{ChaincodeID: "cc1", Fcn: "query", Args: [][]byte{[]byte("b")}})
+	assert.False(t, ok, "expected no response for different arguments")
+
+	// A write to cc3, which is read by request1, invalidates its response
+	cache.invalidate(map[string]struct{}{"cc3": {}})
+	_, ok = cache.get(request1)
+	assert.False(t, ok)
+	_, ok = cache.get(request2)
+	assert.True(t, ok)
+
+	// A response is not cached if the namespaces it reads were invalidated during the query
+	generation := cache.start()
+	cache.invalidate(map[string]struct{}{"cc1": {}})
+	cache.put(request1, newTestQueryResponse(t, "r1", "cc1"), generation)
+	_, ok = cache.get(request1)
+	assert.False(t, ok)
+
+	cache.invalidateAll()
+	_, ok = cache.get(request2)
+	assert.False(t, ok)
+}
+
+func TestQueryCacheLimits(t *testing.T) {
+	cache, err := newQueryCache(fcmocks.NewMockEventService(), time.Minute, 2)
+	require.NoError(t, err)
+	defer cache.close()
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	request1 := Request{ChaincodeID: "cc", Fcn: "query", Args: [][]byte{[]byte("1")}}
+	request2 := Request{ChaincodeID: "cc", Fcn: "query", Args: [][]byte{[]byte("2")}}
+	request3 := Request{ChaincodeID: "cc", Fcn: "query", Args: [][]byte{[]byte("3")}}
+
+	cache.put(request1, Response{}, cache.start())
+	now = now.Add(time.Second)
+	cache.put(request2, Response{}, cache.start())
+	now = now.Add(time.Second)
+	cache.put(request3, Response{}, cache.start())
+
+	_, ok := cache.get(request1)
+	assert.False(t, ok, "expected oldest response to be evicted")
+	_, ok = cache.get(request3)
+	assert.True(t, ok)
+
+	// Without a read-write set, the response is invalidated by writes to the chaincode's namespace
+	cache.invalidate(map[string]struct{}{"cc": {}})
+	_, ok = cache.get(request3)
+	assert.False(t, ok)
+
+	cache.put(request1, Response{}, cache.start())
+	now = now.Add(time.Minute)
+	_, ok = cache.get(request1)
+	assert.False(t, ok, "expected response to expire")
+}
+
+func TestQueryWithCache(t *testing.T) {
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer1.Payload = []byte("value")
+
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+	require.NoError(t, WithQueryCache(0, 0)(chClient))
+	defer chClient.Close()
+
+	request := Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}
+	for i := 0; i < 3; i++ {
+		response, err := chClient.Query(request)
+		require.NoError(t, err)
+		assert.Equal(t, "value", string(response.Payload))
+	}
+	assert.Equal(t, 1, testPeer1.ProcessProposalCalls, "expected cached responses")
+
+	// Queries with transient data are not cached
+	request.TransientMap = map[string][]byte{"k": []byte("v")}
+	_, err := chClient.Query(request)
+	require.NoError(t, err)
+	_, err = chClient.Query(request)
+	require.NoError(t, err)
+	assert.Equal(t, 3, testPeer1.ProcessProposalCalls)
+
+	// Neither are queries with transient data or headers added by options
+	request.TransientMap = nil
+	_, err = chClient.Query(request, WithTransient("k", []byte("v")))
+	require.NoError(t, err)
+	_, err = chClient.Query(request, WithHeader("correlation-id", []byte("abc-123")))
+	require.NoError(t, err)
+	assert.Equal(t, 5, testPeer1.ProcessProposalCalls)
+}
@@ -0,0 +1,271 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package mockchannel provides a scriptable mock of the channel client, so that applications can
+// unit-test their business logic without a Fabric network or the internals of the SDK.
+//
+// Responses, errors and delays are scripted per chaincode and function. Scripted responses are
+// matched in the order in which they were added; a response scripted with Times(n) is used for
+// the next n matching requests only.
+//
+//  client := mockchannel.New()
+//  client.OnQuery("mycc", "get").Return([]byte("100"))
+//  client.OnExecute("mycc", "transfer").ReturnError(errors.New("endorsement failure")).Times(1)
+//  client.OnExecute("mycc", "transfer").Delay(10 * time.Millisecond).Return(nil)
+//
+//  app := NewApp(client) // the application depends on an interface such as mockchannel.ChannelClient
+//  ...
+//  client.PublishChaincodeEvent(&fab.CCEvent{ChaincodeID: "mycc", EventName: "transferred"})
+//  calls := client.Calls()
+package mockchannel
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// ChannelClient is the interface of the channel client which is implemented by the mock
+type ChannelClient interface {
+	Query(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+	Execute(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+	RegisterChaincodeEvent(chainCodeID string, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error)
+	UnregisterChaincodeEvent(registration fab.Registration)
+}
+
+// CallType is the type of a call to the mock client
+type CallType string
+
+// Call types
+const (
+	QueryCall   CallType = "query"
+	ExecuteCall CallType = "execute"
+)
+
+// Call is a request made to the mock client
+type Call struct {
+	Type    CallType
+	Request channel.Request
+}
+
+// Client is a scriptable mock of the channel client
+type Client struct {
+	lock          sync.Mutex
+	expectations  []*Expectation
+	calls         []Call
+	registrations map[*registration]struct{}
+	txNum         int
+}
+
+// New returns a new mock client without scripted responses
+func New() *Client {
+	return &Client{registrations: make(map[*registration]struct{})}
+}
+
+// OnQuery scripts the response to queries of the given chaincode function. An empty function
+// matches all functions of the chaincode.
+func (c *Client) OnQuery(chaincodeID, fcn string) *Expectation {
+	return c.on(QueryCall, chaincodeID, fcn)
+}
+
+// OnExecute scripts the response to transactions of the given chaincode function. An empty function
+// matches all functions of the chaincode.
+func (c *Client) OnExecute(chaincodeID, fcn string) *Expectation {
+	return c.on(ExecuteCall, chaincodeID, fcn)
+}
+
+func (c *Client) on(callType CallType, chaincodeID, fcn string) *Expectation {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	e := &Expectation{callType: callType, chaincodeID: chaincodeID, fcn: fcn, times: -1}
+	c.expectations = append(c.expectations, e)
+	return e
+}
+
+// Reset removes the scripted responses and the recorded calls
+func (c *Client) Reset() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.expectations = nil
+	c.calls = nil
+}
+
+// Calls returns the requests made to the client, in order
+func (c *Client) Calls() []Call {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return append([]Call{}, c.calls...)
+}
+
+// Query returns the scripted response to the query
+func (c *Client) Query(request channel.Request, options ...channel.RequestOption) (channel.Response, error) {
+	return c.call(QueryCall, request)
+}
+
+// Execute returns the scripted response to the transaction
+func (c *Client) Execute(request channel.Request, options ...channel.RequestOption) (channel.Response, error) {
+	return c.call(ExecuteCall, request)
+}
+
+func (c *Client) call(callType CallType, request channel.Request) (channel.Response, error) {
+	e, txID, ok := c.next(callType, request)
+	if !ok {
+		return channel.Response{}, errors.Errorf("no response scripted for %s of chaincode [%s] function [%s]", callType, request.ChaincodeID, request.Fcn)
+	}
+
+	if e.delay > 0 {
+		time.Sleep(e.delay)
+	}
+	if e.handler != nil {
+		return e.handler(request)
+	}
+	if e.err != nil {
+		return channel.Response{}, e.err
+	}
+
+	response := e.response
+	if response.TransactionID == "" {
+		response.TransactionID = txID
+	}
+	return response, nil
+}
+
+// next records the call and returns the first matching expectation
+func (c *Client) next(callType CallType, request channel.Request) (*Expectation, fab.TransactionID, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.calls = append(c.calls, Call{Type: callType, Request: request})
+	c.txNum++
+	txID := fab.TransactionID(fmt.Sprintf("mocktx%d", c.txNum))
+
+	for _, e := range c.expectations {
+		if e.times == 0 || !e.matches(callType, request) {
+			continue
+		}
+		if e.times > 0 {
+			e.times--
+		}
+		return e, txID, true
+	}
+	return nil, "", false
+}
+
+// RegisterChaincodeEvent registers for the chaincode events published with PublishChaincodeEvent
+func (c *Client) RegisterChaincodeEvent(chainCodeID string, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
+	filter, err := regexp.Compile(eventFilter)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "invalid event filter [%s] for chaincode [%s]", eventFilter, chainCodeID)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	reg := &registration{chaincodeID: chainCodeID, filter: filter, eventch: make(chan *fab.CCEvent, 100)}
+	c.registrations[reg] = struct{}{}
+	return reg, reg.eventch, nil
+}
+
+// UnregisterChaincodeEvent removes the chaincode event registration and closes its event channel
+func (c *Client) UnregisterChaincodeEvent(reg fab.Registration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	r, ok := reg.(*registration)
+	if !ok {
+		return
+	}
+	if _, ok := c.registrations[r]; ok {
+		delete(c.registrations, r)
+		close(r.eventch)
+	}
+}
+
+// PublishChaincodeEvent delivers the chaincode event to the matching registrations.
+// The event is dropped for registrations whose event buffer is full.
+func (c *Client) PublishChaincodeEvent(event *fab.CCEvent) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for reg := range c.registrations {
+		if reg.chaincodeID != event.ChaincodeID || !reg.filter.MatchString(event.EventName) {
+			continue
+		}
+		select {
+		case reg.eventch <- event:
+		default:
+		}
+	}
+}
+
+type registration struct {
+	chaincodeID string
+	filter      *regexp.Regexp
+	eventch     chan *fab.CCEvent
+}
+
+// Expectation is a scripted response of the mock client. By default, the response is successful
+// and has an empty payload.
+type Expectation struct {
+	callType    CallType
+	chaincodeID string
+	fcn         string
+	times       int
+	delay       time.Duration
+	response    channel.Response
+	err         error
+	handler     func(request channel.Request) (channel.Response, error)
+}
+
+// Return sets the payload of the response
+func (e *Expectation) Return(payload []byte) *Expectation {
+	e.response = channel.Response{Payload: payload, TxValidationCode: pb.TxValidationCode_VALID}
+	return e
+}
+
+// ReturnResponse sets the response
+func (e *Expectation) ReturnResponse(response channel.Response) *Expectation {
+	e.response = response
+	return e
+}
+
+// ReturnError makes the request fail with the given error
+func (e *Expectation) ReturnError(err error) *Expectation {
+	e.err = err
+	return e
+}
+
+// Do sets a function which computes the response from the request
+func (e *Expectation) Do(handler func(request channel.Request) (channel.Response, error)) *Expectation {
+	e.handler = handler
+	return e
+}
+
+// Delay delays the response by the given duration
+func (e *Expectation) Delay(delay time.Duration) *Expectation {
+	e.delay = delay
+	return e
+}
+
+// Times limits the response to the next n matching requests. By default, the response is unlimited.
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+	return e
+}
+
+func (e *Expectation) matches(callType CallType, request channel.Request) bool {
+	return e.callType == callType && e.chaincodeID == request.ChaincodeID && (e.fcn == "" || e.fcn == request.Fcn)
+}
@@ -0,0 +1,99 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mockchannel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// The channel client and the mock are interchangeable
+var _ ChannelClient = &channel.Client{}
+var _ ChannelClient = &Client{}
+
+func TestScriptedResponses(t *testing.T) {
+	client := New()
+	client.OnQuery("mycc", "get").Return([]byte("100"))
+	client.OnExecute("mycc", "transfer").ReturnError(errors.New("endorsement failure")).Times(1)
+	client.OnExecute("mycc", "transfer").ReturnResponse(channel.Response{TxValidationCode: pb.TxValidationCode_MVCC_READ_CONFLICT}).Times(1)
+	client.OnExecute("mycc", "").Do(func(request channel.Request) (channel.Response, error) {
+		return channel.Response{Payload: request.Args[0]}, nil
+	})
+
+	response, err := client.Query(channel.Request{ChaincodeID: "mycc", Fcn: "get", Args: [][]byte{[]byte("a")}})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("100"), response.Payload)
+	assert.NotEmpty(t, response.TransactionID)
+
+	_, err = client.Execute(channel.Request{ChaincodeID: "mycc", Fcn: "transfer"})
+	assert.EqualError(t, err, "endorsement failure")
+
+	response, err = client.Execute(channel.Request{ChaincodeID: "mycc", Fcn: "transfer"})
+	require.NoError(t, err)
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, response.TxValidationCode)
+
+	response, err = client.Execute(channel.Request{ChaincodeID: "mycc", Fcn: "transfer", Args: [][]byte{[]byte("b")}})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("b"), response.Payload)
+
+	_, err = client.Query(channel.Request{ChaincodeID: "othercc", Fcn: "get"})
+	assert.Error(t, err, "expecting error for request without scripted response")
+
+	calls := client.Calls()
+	require.Len(t, calls, 5)
+	assert.Equal(t, QueryCall, calls[0].Type)
+	assert.Equal(t, ExecuteCall, calls[1].Type)
+	assert.Equal(t, "othercc", calls[4].Request.ChaincodeID)
+
+	client.Reset()
+	assert.Empty(t, client.Calls())
+	_, err = client.Query(channel.Request{ChaincodeID: "mycc", Fcn: "get"})
+	assert.Error(t, err)
+}
+
+func TestDelay(t *testing.T) {
+	client := New()
+	client.OnQuery("mycc", "").Delay(50 * time.Millisecond)
+
+	start := time.Now()
+	_, err := client.Query(channel.Request{ChaincodeID: "mycc", Fcn: "get"})
+	require.NoError(t, err)
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+}
+
+func TestChaincodeEvents(t *testing.T) {
+	client := New()
+
+	reg, eventch, err := client.RegisterChaincodeEvent("mycc", "transfer.*")
+	require.NoError(t, err)
+
+	client.PublishChaincodeEvent(&fab.CCEvent{ChaincodeID: "othercc", EventName: "transferred"})
+	client.PublishChaincodeEvent(&fab.CCEvent{ChaincodeID: "mycc", EventName: "deleted"})
+	client.PublishChaincodeEvent(&fab.CCEvent{ChaincodeID: "mycc", EventName: "transferred", TxID: "tx1"})
+
+	select {
+	case event := <-eventch:
+		assert.Equal(t, "tx1", event.TxID)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for chaincode event")
+	}
+
+	client.UnregisterChaincodeEvent(reg)
+	_, ok := <-eventch
+	assert.False(t, ok, "expecting event channel to be closed")
+
+	_, _, err = client.RegisterChaincodeEvent("mycc", "[")
+	assert.Error(t, err)
+}
@@ -100,5 +100,8 @@ func (s *channelService) evaluate(ctx contextAPI.Channel, responses []fabdiscove
 		return nil, errors.Wrapf(err, "error getting peers from discovery response")
 	}
 
-	return asPeers(ctx, endpoints), nil
+	// Peers of the channel trust the TLS certificates of the channel's MSPs
+	return asPeers(ctx, endpoints, func(peerCfg *fab.NetworkPeer) (fab.Peer, error) {
+		return ctx.InfraProvider().CreateChannelPeerFromConfig(ctx, ctx.ChannelID(), peerCfg)
+	}), nil
 }
@@ -69,7 +69,7 @@ func (s *LocalService) queryPeers() ([]fab.Peer, error) {
 		return nil, errors.Wrapf(err, "error getting peers from discovery response")
 	}
 
-	return s.filterLocalMSP(asPeers(ctx, endpoints)), nil
+	return s.filterLocalMSP(asPeers(ctx, endpoints, ctx.InfraProvider().CreatePeerFromConfig)), nil
 }
 
 func (s *LocalService) getTarget(ctx contextAPI.Client) (*fab.PeerConfig, error) {
@@ -16,6 +16,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	fabdiscovery "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazyref"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/sdkevents"
 	"github.com/pkg/errors"
 )
 
@@ -43,15 +44,16 @@ type queryPeers func() ([]fab.Peer, error)
 
 func newService(query queryPeers, options options) *service {
 	logger.Debugf("Creating new dynamic discovery service with cache refresh interval %s", options.refreshInterval)
-	return &service{
+	s := &service{
 		responseTimeout: options.responseTimeout,
-		peersRef: lazyref.New(
-			func() (interface{}, error) {
-				return query()
-			},
-			lazyref.WithRefreshInterval(lazyref.InitOnFirstAccess, options.refreshInterval),
-		),
 	}
+	s.peersRef = lazyref.New(
+		func() (interface{}, error) {
+			return s.refresh(query)
+		},
+		lazyref.WithRefreshInterval(lazyref.InitOnFirstAccess, options.refreshInterval),
+	)
+	return s
 }
 
 // Initialize initializes the service with local context
@@ -95,6 +97,22 @@ func (s *service) GetPeers() ([]fab.Peer, error) {
 	return peers, nil
 }
 
+// refresh queries the peers and publishes a DiscoveryRefresh event with the result
+func (s *service) refresh(query queryPeers) ([]fab.Peer, error) {
+	peers, err := query()
+
+	event := &sdkevents.Event{Type: sdkevents.DiscoveryRefresh, Err: err}
+	if chCtx, ok := s.context().(contextAPI.Channel); ok {
+		event.ChannelID = chCtx.ChannelID()
+	}
+	for _, peer := range peers {
+		event.Peers = append(event.Peers, peer.URL())
+	}
+	sdkevents.BusFor(s.context().EndpointConfig()).Publish(event)
+
+	return peers, err
+}
+
 func (s *service) context() contextAPI.Client {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
@@ -107,7 +125,9 @@ func (s *service) discoveryClient() discoveryClient {
 	return s.discClient
 }
 
-func asPeers(ctx contextAPI.Client, endpoints []*discclient.Peer) []fab.Peer {
+type peerCreator func(peerCfg *fab.NetworkPeer) (fab.Peer, error)
+
+func asPeers(ctx contextAPI.Client, endpoints []*discclient.Peer, createPeer peerCreator) []fab.Peer {
 	var peers []fab.Peer
 	for _, endpoint := range endpoints {
 		url := endpoint.AliveMessage.GetAliveMsg().Membership.Endpoint
@@ -120,7 +140,7 @@ func asPeers(ctx contextAPI.Client, endpoints []*discclient.Peer) []fab.Peer {
 			continue
 		}
 
-		peer, err := ctx.InfraProvider().CreatePeerFromConfig(&fab.NetworkPeer{PeerConfig: *peerConfig, MSPID: endpoint.MSPID})
+		peer, err := createPeer(&fab.NetworkPeer{PeerConfig: *peerConfig, MSPID: endpoint.MSPID})
 		if err != nil {
 			logger.Warnf("Unable to create peer config for [%s]: %s", url, err)
 			continue
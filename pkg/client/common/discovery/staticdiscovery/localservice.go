@@ -13,7 +13,7 @@ import (
 
 type localDiscoveryService struct {
 	config fab.EndpointConfig
-	peers  []fab.Peer
+	peers  *refreshingPeers
 	mspID  string
 }
 
@@ -26,7 +26,7 @@ func (ds *localDiscoveryService) Initialize(ctx contextAPI.Local) error {
 // GetPeers is used to get local peers
 func (ds *localDiscoveryService) GetPeers() ([]fab.Peer, error) {
 	var peers []fab.Peer
-	for _, p := range ds.peers {
+	for _, p := range ds.peers.get() {
 		if p.MSPID() == ds.mspID {
 			peers = append(peers, p)
 		}
@@ -7,8 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package staticdiscovery
 
 import (
+	"time"
+
 	contextAPI "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
 
 	"github.com/pkg/errors"
 )
@@ -44,42 +47,73 @@ func (dp *DiscoveryProvider) CreateDiscoveryService(channelID string) (fab.Disco
 		return nil, errors.New("channel ID must be provided")
 	}
 
+	peers, err := newRefreshingPeers(func() ([]fab.NetworkPeer, error) {
+		return dp.channelPeers(channelID)
+	}, dp.createPeer, dp.refreshInterval())
+	if err != nil {
+		return nil, err
+	}
+
+	return &discoveryService{config: dp.config, peers: peers}, nil
+}
+
+func (dp *DiscoveryProvider) channelPeers(channelID string) ([]fab.NetworkPeer, error) {
 	// Use configured channel peers
 	chPeers, err := dp.config.ChannelPeers(channelID)
 	if err != nil {
 		return nil, errors.WithMessage(err, "unable to read configuration for channel peers")
 	}
 
-	peers := []fab.Peer{}
+	peers := []fab.NetworkPeer{}
 	for _, p := range chPeers {
-		newPeer, err := dp.fabPvdr.CreatePeerFromConfig(&p.NetworkPeer)
-		if err != nil || newPeer == nil {
-			return nil, errors.WithMessage(err, "NewPeer failed")
-		}
-
-		peers = append(peers, newPeer)
+		peers = append(peers, p.NetworkPeer)
 	}
 
-	return &discoveryService{config: dp.config, peers: peers}, nil
+	return peers, nil
 }
 
 // CreateLocalDiscoveryService return a local discovery service
 func (dp *DiscoveryProvider) CreateLocalDiscoveryService() (fab.DiscoveryService, error) {
-	peers := []fab.Peer{}
+	peers, err := newRefreshingPeers(dp.networkPeers, dp.createPeer, dp.refreshInterval())
+	if err != nil {
+		return nil, err
+	}
+
+	return &localDiscoveryService{config: dp.config, peers: peers}, nil
+}
 
+func (dp *DiscoveryProvider) networkPeers() ([]fab.NetworkPeer, error) {
 	netPeers, err := dp.config.NetworkPeers()
 	if err != nil {
 		return nil, errors.WithMessage(err, "unable to read configuration for network peers")
 	}
 
-	for _, p := range netPeers {
-		newPeer, err := dp.fabPvdr.CreatePeerFromConfig(&p)
-		if err != nil {
-			return nil, errors.WithMessage(err, "NewPeerFromConfig failed")
-		}
+	return netPeers, nil
+}
 
-		peers = append(peers, newPeer)
+func (dp *DiscoveryProvider) createPeer(peerCfg *fab.NetworkPeer) (fab.Peer, error) {
+	newPeer, err := dp.fabPvdr.CreatePeerFromConfig(peerCfg)
+	if err != nil || newPeer == nil {
+		return nil, errors.WithMessage(err, "NewPeerFromConfig failed")
 	}
 
-	return &localDiscoveryService{config: dp.config, peers: peers}, nil
+	return newPeer, nil
+}
+
+// refreshInterval returns the interval after which the peers are reloaded from the configuration.
+// Zero is returned (i.e. never reload) if none of the peers is configured with a DNS SRV URL since
+// the peers of a static configuration don't change.
+func (dp *DiscoveryProvider) refreshInterval() time.Duration {
+	networkConfig, err := dp.config.NetworkConfig()
+	if err != nil {
+		return 0
+	}
+
+	for _, p := range networkConfig.Peers {
+		if endpoint.IsSRV(p.URL) {
+			return dp.config.Timeout(fab.DiscoveryServiceRefresh)
+		}
+	}
+
+	return 0
 }
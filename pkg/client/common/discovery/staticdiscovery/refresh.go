@@ -0,0 +1,110 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package staticdiscovery
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+type peerConfigLoader func() ([]fab.NetworkPeer, error)
+
+type peerFactory func(peerCfg *fab.NetworkPeer) (fab.Peer, error)
+
+// refreshingPeers holds the peers loaded from the configuration. If any of the configured
+// peers has a DNS SRV URL then the configuration is reloaded once the refresh interval has
+// elapsed so that the SRV names are resolved again. Peer instances are only created for
+// new endpoints; the existing instances are kept for endpoints that didn't change.
+type refreshingPeers struct {
+	lock            sync.Mutex
+	peers           []fab.Peer
+	peersByURL      map[string]fab.Peer
+	load            peerConfigLoader
+	create          peerFactory
+	refreshInterval time.Duration
+	lastRefresh     time.Time
+}
+
+func newRefreshingPeers(load peerConfigLoader, create peerFactory, refreshInterval time.Duration) (*refreshingPeers, error) {
+	r := &refreshingPeers{
+		peersByURL:      make(map[string]fab.Peer),
+		load:            load,
+		create:          create,
+		refreshInterval: refreshInterval,
+	}
+
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	r.lastRefresh = time.Now()
+
+	return r, nil
+}
+
+func (r *refreshingPeers) get() []fab.Peer {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.refreshInterval > 0 && time.Since(r.lastRefresh) > r.refreshInterval {
+		if err := r.reload(); err != nil {
+			logger.Warnf("Unable to reload peers from config, using previously loaded peers: %s", err)
+		}
+		r.lastRefresh = time.Now()
+	}
+
+	return r.peers
+}
+
+// setFactory replaces the factory of the peers and recreates all peers with the new factory
+func (r *refreshingPeers) setFactory(create peerFactory) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.create = create
+	r.peersByURL = make(map[string]fab.Peer)
+	return r.reload()
+}
+
+func (r *refreshingPeers) reload() error {
+	peerConfigs, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	peers := make([]fab.Peer, 0, len(peerConfigs))
+	peersByURL := make(map[string]fab.Peer, len(peerConfigs))
+	changed := len(peerConfigs) != len(r.peersByURL)
+
+	for i := range peerConfigs {
+		url := strings.ToLower(peerConfigs[i].URL)
+
+		peer, ok := r.peersByURL[url]
+		if !ok {
+			peer, err = r.create(&peerConfigs[i])
+			if err != nil {
+				return err
+			}
+			changed = true
+		}
+
+		peers = append(peers, peer)
+		peersByURL[url] = peer
+	}
+
+	if changed {
+		r.peers = peers
+		r.peersByURL = peersByURL
+	}
+
+	return nil
+}
@@ -7,17 +7,26 @@ SPDX-License-Identifier: Apache-2.0
 package staticdiscovery
 
 import (
+	contextAPI "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 )
 
 // discoveryService implements discovery service
 type discoveryService struct {
 	config fab.EndpointConfig
-	peers  []fab.Peer
+	peers  *refreshingPeers
+}
+
+// Initialize recreates the peers for the channel of the given context so that connections
+// to the peers trust the TLS certificates of the channel's MSPs
+func (ds *discoveryService) Initialize(ctx contextAPI.Channel) error {
+	return ds.peers.setFactory(func(peerCfg *fab.NetworkPeer) (fab.Peer, error) {
+		return ctx.InfraProvider().CreateChannelPeerFromConfig(ctx, ctx.ChannelID(), peerCfg)
+	})
 }
 
 // GetPeers is used to get peers
 func (ds *discoveryService) GetPeers() ([]fab.Peer, error) {
 
-	return ds.peers, nil
+	return ds.peers.get(), nil
 }
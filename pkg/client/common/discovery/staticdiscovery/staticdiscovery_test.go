@@ -8,7 +8,9 @@ package staticdiscovery
 
 import (
 	"testing"
+	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
 	fabImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
@@ -52,6 +54,39 @@ func TestStaticDiscovery(t *testing.T) {
 
 }
 
+func TestStaticDiscoveryChannelPeers(t *testing.T) {
+	configBackend, err := config.FromFile("../../../../../test/fixtures/config/config_test.yaml")()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	config1, err := fabImpl.ConfigFromBackend(configBackend)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	ctx := mocks.NewMockContext(mockmsp.NewMockSigningIdentity("user1", "Org1MSP"))
+	infraProvider := &channelPeerInfraProvider{InfraProvider: ctx.InfraProvider()}
+	ctx.SetCustomInfraProvider(infraProvider)
+
+	discoveryProvider, err := New(config1)
+	assert.NoError(t, err)
+	assert.NoError(t, discoveryProvider.Initialize(ctx))
+
+	service, err := discoveryProvider.CreateDiscoveryService("mychannel")
+	assert.NoError(t, err)
+	assert.Empty(t, infraProvider.channels)
+
+	// The peers are recreated for the channel once the service is initialized with the channel context
+	err = service.(*discoveryService).Initialize(mocks.NewMockChannelContext(ctx, "mychannel"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mychannel"}, infraProvider.channels)
+
+	peers, err := service.GetPeers()
+	assert.NoError(t, err)
+	assert.Len(t, peers, 1)
+}
+
 func TestStaticDiscoveryWhenChannelIsEmpty(t *testing.T) {
 	configBackend, err := config.FromFile("../../../../../test/fixtures/config/config_test.yaml")()
 	if err != nil {
@@ -95,3 +130,50 @@ func TestStaticLocalDiscovery(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(peers))
 }
+
+func TestRefreshingPeers(t *testing.T) {
+	urls := []string{"peer0.org1.example.com:7051", "peer1.org1.example.com:7051"}
+	load := func() ([]fab.NetworkPeer, error) {
+		var peers []fab.NetworkPeer
+		for _, url := range urls {
+			peers = append(peers, fab.NetworkPeer{PeerConfig: fab.PeerConfig{URL: url}, MSPID: "Org1MSP"})
+		}
+		return peers, nil
+	}
+
+	created := 0
+	create := func(peerCfg *fab.NetworkPeer) (fab.Peer, error) {
+		created++
+		return &mocks.MockPeer{MockURL: peerCfg.URL, MockMSP: peerCfg.MSPID}, nil
+	}
+
+	peers, err := newRefreshingPeers(load, create, time.Millisecond)
+	assert.NoError(t, err)
+	initial := peers.get()
+	assert.Len(t, initial, 2)
+	assert.Equal(t, 2, created)
+
+	// Nothing changed so the existing peer instances are kept
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, initial, peers.get())
+	assert.Equal(t, 2, created)
+
+	// Only the peer for the new endpoint is created
+	urls = []string{"peer0.org1.example.com:7051", "peer2.org1.example.com:7051"}
+	time.Sleep(5 * time.Millisecond)
+	refreshed := peers.get()
+	assert.Len(t, refreshed, 2)
+	assert.Equal(t, 3, created)
+	assert.True(t, initial[0] == refreshed[0], "expecting the instance of the unchanged peer to be kept")
+	assert.Equal(t, "peer2.org1.example.com:7051", refreshed[1].URL())
+}
+
+type channelPeerInfraProvider struct {
+	fab.InfraProvider
+	channels []string
+}
+
+func (p *channelPeerInfraProvider) CreateChannelPeerFromConfig(ctx fab.ClientContext, channelID string, peerCfg *fab.NetworkPeer) (fab.Peer, error) {
+	p.channels = append(p.channels, channelID)
+	return p.InfraProvider.CreatePeerFromConfig(peerCfg)
+}
@@ -8,10 +8,14 @@ SPDX-License-Identifier: Apache-2.0
 package filter
 
 import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
 )
 
+var logger = logging.NewLogger("fabsdk/client")
+
 // EndpointType represents endpoint type
 type EndpointType int32
 
@@ -24,7 +28,8 @@ const (
 )
 
 // NewEndpointFilter creates a new endpoint filter that is based on configuration.
-// If channel peer is not configured it will be selected by default.
+// If channel peer is not configured it will be selected by default. Hot-standby peers
+// are only selected if none of the other peers of the endpoint type are available.
 func NewEndpointFilter(ctx context.Channel, et EndpointType) *EndpointFilter {
 
 	// Retrieve channel peers
@@ -58,6 +63,18 @@ func (f *EndpointFilter) Accept(peer fab.Peer) bool {
 		return true
 	}
 
+	if !f.hasType(chPeer) {
+		return false
+	}
+
+	if chPeer.Standby {
+		return !f.primaryAvailable()
+	}
+
+	return true
+}
+
+func (f *EndpointFilter) hasType(chPeer *fab.ChannelPeer) bool {
 	switch t := f.endpointType; t {
 	case ChaincodeQuery:
 		return chPeer.ChaincodeQuery
@@ -72,6 +89,31 @@ func (f *EndpointFilter) Accept(peer fab.Peer) bool {
 	return true
 }
 
+// availabilityChecker is implemented by comm managers which know whether the connection to a target is available
+type availabilityChecker interface {
+	IsAvailable(target string) bool
+}
+
+// primaryAvailable returns true if any of the channel's primary (i.e. not standby) peers of the
+// endpoint type is available. A peer is unavailable if its connection is failing, e.g. because
+// the peer is being drained.
+func (f *EndpointFilter) primaryAvailable() bool {
+	checker, _ := f.ctx.InfraProvider().CommManager().(availabilityChecker)
+
+	for i := range f.chPeers {
+		chPeer := &f.chPeers[i]
+		if chPeer.Standby || !f.hasType(chPeer) {
+			continue
+		}
+		if checker == nil || checker.IsAvailable(endpoint.ToAddress(chPeer.URL)) {
+			return true
+		}
+	}
+
+	logger.Debugf("None of the primary peers are available - selecting standby peers")
+	return false
+}
+
 func (f *EndpointFilter) getChannelPeer(peerConfig *fab.PeerConfig) *fab.ChannelPeer {
 	for _, chpeer := range f.chPeers {
 		if chpeer.URL == peerConfig.URL {
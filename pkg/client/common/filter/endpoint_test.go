@@ -8,6 +8,8 @@ package filter
 import (
 	"testing"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 )
 
@@ -115,3 +117,66 @@ func TestEventSourceFilter(t *testing.T) {
 	}
 
 }
+
+func TestStandbyPeerFilter(t *testing.T) {
+
+	channel, err := mocks.NewMockChannel(channelID)
+	if err != nil {
+		t.Fatalf("Failed to create mock channel: %s", err)
+	}
+
+	commManager := &mockCommManager{unavailable: map[string]bool{}}
+	ctx := &mockChannelContext{
+		Channel:       channel,
+		infraProvider: &mockInfraProvider{InfraProvider: channel.InfraProvider(), commManager: commManager},
+	}
+
+	// The mock config resolves all peers to "example.com", which is configured as a standby peer
+	allTypes := fab.PeerChannelConfig{EndorsingPeer: true, ChaincodeQuery: true, LedgerQuery: true, EventSource: true}
+	standby := allTypes
+	standby.Standby = true
+	chPeers := []fab.ChannelPeer{
+		{PeerChannelConfig: allTypes, NetworkPeer: fab.NetworkPeer{PeerConfig: fab.PeerConfig{URL: "grpcs://primary.example.com"}}},
+		{PeerChannelConfig: standby, NetworkPeer: fab.NetworkPeer{PeerConfig: fab.PeerConfig{URL: "example.com"}}},
+	}
+
+	ef := &EndpointFilter{endpointType: EndorsingPeer, ctx: ctx, chPeers: chPeers}
+
+	peer := mocks.NewMockPeer("Peer1", "example.com")
+	if ef.Accept(peer) {
+		t.Fatalf("Should not have accepted standby peer while primary peer is available")
+	}
+
+	commManager.unavailable["primary.example.com"] = true
+	if !ef.Accept(peer) {
+		t.Fatalf("Should have accepted standby peer since primary peer is unavailable")
+	}
+
+}
+
+type mockChannelContext struct {
+	context.Channel
+	infraProvider fab.InfraProvider
+}
+
+func (c *mockChannelContext) InfraProvider() fab.InfraProvider {
+	return c.infraProvider
+}
+
+type mockInfraProvider struct {
+	fab.InfraProvider
+	commManager fab.CommManager
+}
+
+func (p *mockInfraProvider) CommManager() fab.CommManager {
+	return p.commManager
+}
+
+type mockCommManager struct {
+	fab.CommManager
+	unavailable map[string]bool
+}
+
+func (m *mockCommManager) IsAvailable(target string) bool {
+	return !m.unavailable[target]
+}
@@ -40,6 +40,13 @@ func (t *MockTransactor) SendTransactionProposal(proposal *fab.TransactionPropos
 	return txn.SendProposal(rqtx, proposal, targets)
 }
 
+// SendTransactionProposalUntil sends a TransactionProposal to the target peers until done returns true.
+func (t *MockTransactor) SendTransactionProposalUntil(proposal *fab.TransactionProposal, targets []fab.ProposalProcessor, done fab.ProposalCompletion) ([]*fab.TransactionProposalResponse, error) {
+	rqtx, cancel := contextImpl.NewRequest(t.Ctx, contextImpl.WithTimeout(10*time.Second))
+	defer cancel()
+	return txn.SendProposalUntil(rqtx, proposal, targets, done)
+}
+
 // CreateTransaction create a transaction with proposal response.
 func (t *MockTransactor) CreateTransaction(request fab.TransactionRequest) (*fab.Transaction, error) {
 	return txn.New(request)
@@ -0,0 +1,317 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package balancer
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+// Type is the type of a load-balancing strategy
+type Type string
+
+const (
+	// RoundRobin chooses the candidates in turn
+	RoundRobin Type = "RoundRobin"
+	// Random chooses a random candidate
+	Random Type = "Random"
+	// LeastOutstanding chooses the candidate with the fewest outstanding requests
+	LeastOutstanding Type = "LeastOutstanding"
+	// LowestLatency chooses the candidate with the lowest (moving average) request latency
+	LowestLatency Type = "LowestLatency"
+	// Nearest chooses the candidate with the lowest round-trip time measured by background probes
+	Nearest Type = "Nearest"
+	// Weighted chooses a random candidate with a probability proportional to its configured weight
+	Weighted Type = "Weighted"
+)
+
+// Balancer chooses one of a number of equivalent candidates. Each candidate is a set of peers,
+// i.e. a single peer or a group of peers which together satisfy an endorsement policy.
+type Balancer interface {
+	// Choose returns the index of the chosen candidate or -1 if there are no candidates
+	Choose(candidates [][]fab.Peer) int
+}
+
+// WeightProvider returns the weight of a peer. Peers with a weight of zero
+// are only chosen if no other candidates are available.
+type WeightProvider func(peer fab.Peer) int
+
+// StatsProvider returns the request statistics of a peer
+type StatsProvider func(peer fab.Peer) peer.Stats
+
+// New returns a balancer of the given type. The type is case insensitive and
+// defaults to Random. The weights are only used by the Weighted balancer.
+func New(balancerType Type, weights WeightProvider) (Balancer, error) {
+	switch strings.ToLower(string(balancerType)) {
+	case "", "random":
+		return NewRandom(), nil
+	case "roundrobin":
+		return NewRoundRobin(), nil
+	case "leastoutstanding":
+		return NewLeastOutstanding(), nil
+	case "lowestlatency":
+		return NewLowestLatency(), nil
+	case "nearest":
+		return NewNearest(), nil
+	case "weighted":
+		if weights == nil {
+			return nil, errors.New("weights are required for the Weighted balancer")
+		}
+		return NewWeighted(weights), nil
+	default:
+		return nil, errors.Errorf("unsupported balancer [%s]", balancerType)
+	}
+}
+
+type roundRobin struct {
+	lock  sync.Mutex
+	index int
+}
+
+// NewRoundRobin returns a balancer which chooses the candidates in turn
+func NewRoundRobin() Balancer {
+	return &roundRobin{index: -1}
+}
+
+func (b *roundRobin) Choose(candidates [][]fab.Peer) int {
+	if len(candidates) == 0 {
+		return -1
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.index < 0 {
+		// First time - start at a random index
+		b.index = rand.Intn(len(candidates))
+	} else {
+		b.index++
+	}
+	if b.index >= len(candidates) {
+		b.index = 0
+	}
+
+	logger.Debugf("RoundRobin balancer - choosing index %d", b.index)
+	return b.index
+}
+
+type random struct{}
+
+// NewRandom returns a balancer which chooses a random candidate
+func NewRandom() Balancer {
+	return &random{}
+}
+
+func (b *random) Choose(candidates [][]fab.Peer) int {
+	if len(candidates) == 0 {
+		return -1
+	}
+
+	index := rand.Intn(len(candidates))
+
+	logger.Debugf("Random balancer - choosing index %d", index)
+	return index
+}
+
+type leastOutstanding struct {
+	stats StatsProvider
+}
+
+// NewLeastOutstanding returns a balancer which chooses the candidate with the fewest
+// outstanding requests. The outstanding requests of a candidate are those of all of its peers.
+func NewLeastOutstanding() Balancer {
+	return &leastOutstanding{stats: peerStats}
+}
+
+func (b *leastOutstanding) Choose(candidates [][]fab.Peer) int {
+	index := chooseMin(candidates, func(peers []fab.Peer) float64 {
+		outstanding := 0
+		for _, p := range peers {
+			outstanding += b.stats(p).Outstanding
+		}
+		return float64(outstanding)
+	})
+
+	logger.Debugf("LeastOutstanding balancer - choosing index %d", index)
+	return index
+}
+
+type lowestLatency struct {
+	stats StatsProvider
+}
+
+// NewLowestLatency returns a balancer which chooses the candidate with the lowest request latency.
+// Since requests to the peers of a candidate are sent concurrently, the latency of a candidate is
+// that of its slowest peer. Peers which haven't served any requests are preferred so that their
+// latency is measured.
+func NewLowestLatency() Balancer {
+	return &lowestLatency{stats: peerStats}
+}
+
+func (b *lowestLatency) Choose(candidates [][]fab.Peer) int {
+	index := chooseMin(candidates, func(peers []fab.Peer) float64 {
+		var latency time.Duration
+		for _, p := range peers {
+			if l := b.stats(p).Latency; l > latency {
+				latency = l
+			}
+		}
+		return float64(latency)
+	})
+
+	logger.Debugf("LowestLatency balancer - choosing index %d", index)
+	return index
+}
+
+type nearest struct {
+	stats StatsProvider
+}
+
+// NewNearest returns a balancer which chooses the candidate with the lowest round-trip time (RTT),
+// as measured by background probes (see peer.Prober). The RTT of a candidate is that of its most
+// distant peer. Candidates with peers which haven't been probed are only chosen if no probed
+// candidates are available.
+func NewNearest() Balancer {
+	return &nearest{stats: peerStats}
+}
+
+func (b *nearest) Choose(candidates [][]fab.Peer) int {
+	index := chooseMin(candidates, func(peers []fab.Peer) float64 {
+		var rtt time.Duration
+		for _, p := range peers {
+			s := b.stats(p)
+			if s.Probes == 0 {
+				return math.MaxFloat64
+			}
+			if s.RTT > rtt {
+				rtt = s.RTT
+			}
+		}
+		return float64(rtt)
+	})
+
+	logger.Debugf("Nearest balancer - choosing index %d", index)
+	return index
+}
+
+type weighted struct {
+	weights WeightProvider
+}
+
+// NewWeighted returns a balancer which chooses a random candidate with a probability proportional
+// to its weight. The weight of a candidate is the lowest weight of its peers.
+func NewWeighted(weights WeightProvider) Balancer {
+	return &weighted{weights: weights}
+}
+
+func (b *weighted) Choose(candidates [][]fab.Peer) int {
+	if len(candidates) == 0 {
+		return -1
+	}
+
+	total := 0
+	candidateWeights := make([]int, len(candidates))
+	for i, peers := range candidates {
+		candidateWeights[i] = b.weight(peers)
+		total += candidateWeights[i]
+	}
+
+	if total == 0 {
+		// None of the candidates have a weight
+		return rand.Intn(len(candidates))
+	}
+
+	n := rand.Intn(total)
+	for i, weight := range candidateWeights {
+		if n < weight {
+			logger.Debugf("Weighted balancer - choosing index %d", i)
+			return i
+		}
+		n -= weight
+	}
+
+	// Not reached
+	return len(candidates) - 1
+}
+
+func (b *weighted) weight(peers []fab.Peer) int {
+	if len(peers) == 0 {
+		return 0
+	}
+	weight := -1
+	for _, p := range peers {
+		w := b.weights(p)
+		if w < 0 {
+			w = 0
+		}
+		if weight < 0 || w < weight {
+			weight = w
+		}
+	}
+	return weight
+}
+
+// chooseMin returns the index of the candidate with the lowest cost. If several candidates
+// have the lowest cost then one of them is chosen at random so that they share the load.
+func chooseMin(candidates [][]fab.Peer, cost func(peers []fab.Peer) float64) int {
+	var lowest []int
+	var lowestCost float64
+	for i, peers := range candidates {
+		c := cost(peers)
+		if len(lowest) == 0 || c < lowestCost {
+			lowest = []int{i}
+			lowestCost = c
+		} else if c == lowestCost {
+			lowest = append(lowest, i)
+		}
+	}
+
+	if len(lowest) == 0 {
+		return -1
+	}
+	return lowest[rand.Intn(len(lowest))]
+}
+
+func peerStats(p fab.Peer) peer.Stats {
+	return peer.GetStats(p.URL())
+}
+
+// PeerBalancer chooses one of a number of equivalent peers (e.g. the peer to connect to for events)
+type PeerBalancer struct {
+	balancer Balancer
+}
+
+// NewPeerBalancer returns a PeerBalancer which uses the given balancer
+func NewPeerBalancer(balancer Balancer) *PeerBalancer {
+	return &PeerBalancer{balancer: balancer}
+}
+
+// Choose chooses one of the given peers
+func (b *PeerBalancer) Choose(peers []fab.Peer) (fab.Peer, error) {
+	if len(peers) == 0 {
+		logger.Warnf("No peers to choose from!")
+		return nil, nil
+	}
+
+	candidates := make([][]fab.Peer, len(peers))
+	for i, p := range peers {
+		candidates[i] = []fab.Peer{p}
+	}
+
+	return peers[b.balancer.Choose(candidates)], nil
+}
@@ -0,0 +1,164 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package balancer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
+)
+
+var (
+	peer1 = mocks.NewMockPeer("p1", "peer1.example.com:7051")
+	peer2 = mocks.NewMockPeer("p2", "peer2.example.com:7051")
+	peer3 = mocks.NewMockPeer("p3", "peer3.example.com:7051")
+)
+
+func candidates(peers ...fab.Peer) [][]fab.Peer {
+	c := make([][]fab.Peer, len(peers))
+	for i, p := range peers {
+		c[i] = []fab.Peer{p}
+	}
+	return c
+}
+
+func mockStats(stats map[string]peer.Stats) StatsProvider {
+	return func(p fab.Peer) peer.Stats {
+		return stats[p.URL()]
+	}
+}
+
+func TestNew(t *testing.T) {
+	weights := func(fab.Peer) int { return 1 }
+
+	for _, balancerType := range []Type{"", Random, RoundRobin, LeastOutstanding, LowestLatency, Nearest, Weighted, "leastoutstanding"} {
+		b, err := New(balancerType, weights)
+		require.NoErrorf(t, err, "unexpected error for balancer [%s]", balancerType)
+		require.NotNil(t, b)
+	}
+
+	_, err := New("Fastest", weights)
+	assert.Error(t, err, "expected error for unsupported balancer")
+
+	_, err = New(Weighted, nil)
+	assert.Error(t, err, "expected error for Weighted balancer without weights")
+}
+
+func TestNoCandidates(t *testing.T) {
+	for _, b := range []Balancer{NewRandom(), NewRoundRobin(), NewLeastOutstanding(), NewLowestLatency(), NewNearest(), NewWeighted(func(fab.Peer) int { return 1 })} {
+		assert.Equal(t, -1, b.Choose(nil))
+	}
+
+	p, err := NewPeerBalancer(NewRoundRobin()).Choose(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, p)
+}
+
+func TestRoundRobin(t *testing.T) {
+	b := NewRoundRobin()
+	c := candidates(peer1, peer2, peer3)
+
+	first := b.Choose(c)
+	for i := 1; i <= 6; i++ {
+		assert.Equal(t, (first+i)%len(c), b.Choose(c))
+	}
+}
+
+func TestLeastOutstanding(t *testing.T) {
+	b := &leastOutstanding{stats: mockStats(map[string]peer.Stats{
+		peer1.URL(): {Outstanding: 5},
+		peer2.URL(): {Outstanding: 1},
+		peer3.URL(): {Outstanding: 3},
+	})}
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, 1, b.Choose(candidates(peer1, peer2, peer3)))
+	}
+
+	// The outstanding requests of a group are those of all of its peers
+	groups := [][]fab.Peer{{peer1}, {peer2, peer3}}
+	assert.Equal(t, 1, b.Choose(groups))
+	groups = [][]fab.Peer{{peer3}, {peer2, peer3}}
+	assert.Equal(t, 0, b.Choose(groups))
+}
+
+func TestLowestLatency(t *testing.T) {
+	b := &lowestLatency{stats: mockStats(map[string]peer.Stats{
+		peer1.URL(): {Latency: 30 * time.Millisecond, Requests: 10},
+		peer2.URL(): {Latency: 10 * time.Millisecond, Requests: 10},
+		peer3.URL(): {Latency: 20 * time.Millisecond, Requests: 10},
+	})}
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, 1, b.Choose(candidates(peer1, peer2, peer3)))
+	}
+
+	// The latency of a group is that of its slowest peer
+	groups := [][]fab.Peer{{peer2, peer1}, {peer3}}
+	assert.Equal(t, 1, b.Choose(groups))
+
+	// Peers without requests are preferred
+	peer4 := mocks.NewMockPeer("p4", "peer4.example.com:7051")
+	assert.Equal(t, 3, b.Choose(candidates(peer1, peer2, peer3, peer4)))
+}
+
+func TestNearest(t *testing.T) {
+	b := &nearest{stats: mockStats(map[string]peer.Stats{
+		peer1.URL(): {RTT: 30 * time.Millisecond, Probes: 5},
+		peer2.URL(): {RTT: 10 * time.Millisecond, Probes: 5},
+		peer3.URL(): {RTT: 20 * time.Millisecond, Probes: 5},
+	})}
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, 1, b.Choose(candidates(peer1, peer2, peer3)))
+	}
+
+	// The RTT of a group is that of its most distant peer
+	groups := [][]fab.Peer{{peer2, peer1}, {peer3}}
+	assert.Equal(t, 1, b.Choose(groups))
+
+	// Peers which haven't been probed are avoided
+	peer4 := mocks.NewMockPeer("p4", "peer4.example.com:7051")
+	assert.Equal(t, 0, b.Choose(candidates(peer1, peer4)))
+	assert.Equal(t, 0, b.Choose(candidates(peer4)))
+}
+
+func TestWeighted(t *testing.T) {
+	weights := map[string]int{
+		peer1.URL(): 3,
+		peer2.URL(): 1,
+		peer3.URL(): 0,
+	}
+	b := NewWeighted(func(p fab.Peer) int { return weights[p.URL()] })
+
+	counts := make([]int, 3)
+	for i := 0; i < 4000; i++ {
+		counts[b.Choose(candidates(peer1, peer2, peer3))]++
+	}
+	assert.Equal(t, 0, counts[2], "peer with zero weight should not be chosen")
+	assert.True(t, counts[0] > 2*counts[1], "expected peer1 to be chosen about three times as often as peer2: %v", counts)
+
+	// The peer with a zero weight is chosen if there are no other candidates
+	assert.Equal(t, 0, b.Choose(candidates(peer3)))
+}
+
+func TestPeerBalancer(t *testing.T) {
+	b := NewPeerBalancer(&leastOutstanding{stats: mockStats(map[string]peer.Stats{
+		peer1.URL(): {Outstanding: 2},
+		peer2.URL(): {Outstanding: 0},
+	})})
+
+	p, err := b.Choose([]fab.Peer{peer1, peer2})
+	require.NoError(t, err)
+	assert.Equal(t, peer2.URL(), p.URL())
+}
@@ -19,8 +19,11 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/pkg/errors"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/balancer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/dynamicselection/pgresolver"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+	peerImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/api"
 )
 
@@ -39,6 +42,7 @@ type SelectionProvider struct {
 	config       fab.EndpointConfig
 	users        []ChannelUser
 	lbp          pgresolver.LoadBalancePolicy
+	customLBP    bool
 	providers    api.Providers
 	cacheTimeout time.Duration
 	refs         []*selectionService
@@ -48,10 +52,12 @@ type SelectionProvider struct {
 // Opt applies a selection provider option
 type Opt func(*SelectionProvider)
 
-// WithLoadBalancePolicy sets the load-balance policy
+// WithLoadBalancePolicy sets the load-balance policy, which takes precedence
+// over the balancer configured for the channel
 func WithLoadBalancePolicy(lbp pgresolver.LoadBalancePolicy) Opt {
 	return func(p *SelectionProvider) {
 		p.lbp = lbp
+		p.customLBP = true
 	}
 }
 
@@ -84,6 +90,9 @@ type selectionService struct {
 	pgLBP            pgresolver.LoadBalancePolicy
 	ccPolicyProvider CCPolicyProvider
 	discoveryService fab.DiscoveryService
+	channelService   fab.ChannelService
+	prober           *peerImpl.Prober
+	results          *resultCache
 }
 
 // Initialize allow for initializing providers
@@ -114,10 +123,16 @@ func (p *SelectionProvider) CreateSelectionService(channelID string) (fab.Select
 	if err != nil {
 		return nil, errors.WithMessage(err, "Failed to create cc policy provider")
 	}
-	svc, err := newSelectionService(channelID, p.lbp, ccPolicyProvider, p.cacheTimeout)
+	lbp, err := p.channelLBP(channelID)
 	if err != nil {
 		return nil, err
 	}
+	svc, err := newSelectionService(channelID, lbp, ccPolicyProvider, p.cacheTimeout)
+	if err != nil {
+		return nil, err
+	}
+	svc.prober = p.channelProber(channelID)
+	svc.results = p.channelResultCache(channelID)
 
 	p.refLock.Lock()
 	p.refs = append(p.refs, svc)
@@ -126,6 +141,83 @@ func (p *SelectionProvider) CreateSelectionService(channelID string) (fab.Select
 	return svc, nil
 }
 
+// channelLBP returns the load-balance policy for the channel, which uses the balancer
+// configured for the channel (if any) unless a custom policy was provided
+func (p *SelectionProvider) channelLBP(channelID string) (pgresolver.LoadBalancePolicy, error) {
+	if p.customLBP {
+		return p.lbp, nil
+	}
+
+	chConfig, err := p.config.ChannelConfig(channelID)
+	if err != nil || chConfig.Policies.Selection.Balancer == "" {
+		return p.lbp, nil
+	}
+
+	b, err := balancer.New(balancer.Type(chConfig.Policies.Selection.Balancer), p.peerWeights(channelID))
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("invalid balancer for channel [%s]", channelID))
+	}
+
+	logger.Debugf("Using balancer [%s] for channel [%s]", chConfig.Policies.Selection.Balancer, channelID)
+	return pgresolver.NewBalancedLBP(b), nil
+}
+
+// channelProber returns a started prober of the round-trip time to the channel's configured peers
+// if probing is enabled for the channel; otherwise nil is returned
+func (p *SelectionProvider) channelProber(channelID string) *peerImpl.Prober {
+	chConfig, err := p.config.ChannelConfig(channelID)
+	if err != nil || chConfig.Policies.Selection.ProbeInterval <= 0 {
+		return nil
+	}
+
+	prober := peerImpl.NewProber(chConfig.Policies.Selection.ProbeInterval, p.config.Timeout(fab.EndorserConnection))
+
+	chPeers, err := p.config.ChannelPeers(channelID)
+	if err != nil {
+		logger.Warnf("Failed to get peers of channel [%s]: %s", channelID, err)
+	}
+	for _, chPeer := range chPeers {
+		prober.Add(chPeer.URL)
+	}
+
+	logger.Debugf("Probing the round-trip time to the peers of channel [%s] every %s", channelID, chConfig.Policies.Selection.ProbeInterval)
+	prober.Start()
+	return prober
+}
+
+// channelResultCache returns the cache of selection results for the channel if a result cache TTL
+// is configured for the channel; otherwise nil is returned
+func (p *SelectionProvider) channelResultCache(channelID string) *resultCache {
+	chConfig, err := p.config.ChannelConfig(channelID)
+	if err != nil || chConfig.Policies.Selection.ResultCacheTTL <= 0 {
+		return nil
+	}
+
+	logger.Debugf("Caching selection results of channel [%s] for %s", channelID, chConfig.Policies.Selection.ResultCacheTTL)
+	return newResultCache(chConfig.Policies.Selection.ResultCacheTTL)
+}
+
+// peerWeights returns the weights of the channel's peers. Peers without a weight have a weight of one.
+func (p *SelectionProvider) peerWeights(channelID string) balancer.WeightProvider {
+	weights := make(map[string]int)
+	chPeers, err := p.config.ChannelPeers(channelID)
+	if err != nil {
+		logger.Warnf("Failed to get peers of channel [%s]: %s", channelID, err)
+	}
+	for _, chPeer := range chPeers {
+		if chPeer.Weight != 0 {
+			weights[endpoint.ToAddress(chPeer.URL)] = chPeer.Weight
+		}
+	}
+
+	return func(peer fab.Peer) int {
+		if weight, ok := weights[endpoint.ToAddress(peer.URL())]; ok {
+			return weight
+		}
+		return 1
+	}
+}
+
 // Close the selection services created by this provider
 func (p *SelectionProvider) Close() {
 	p.refLock.Lock()
@@ -160,6 +252,7 @@ func newSelectionService(channelID string, lbp pgresolver.LoadBalancePolicy, ccP
 
 func (s *selectionService) Initialize(context contextAPI.Channel) error {
 	s.discoveryService = context.DiscoveryService()
+	s.channelService = context.ChannelService()
 	return nil
 }
 
@@ -170,7 +263,8 @@ func (s *selectionService) GetEndorsersForChaincode(chaincodeIDs []string, opts
 
 	params := options.NewParams(opts)
 
-	resolver, err := s.getPeerGroupResolver(chaincodeIDs)
+	key := newResolverKey(s.channelID, chaincodeIDs...)
+	resolver, err := s.getPeerGroupResolver(key)
 	if err != nil {
 		return nil, errors.WithMessage(err, fmt.Sprintf("Error getting peer group resolver for chaincodes [%v] on channel [%s]", chaincodeIDs, s.channelID))
 	}
@@ -180,6 +274,19 @@ func (s *selectionService) GetEndorsersForChaincode(chaincodeIDs []string, opts
 		return nil, err
 	}
 
+	if s.prober != nil {
+		// Also probe the discovered peers
+		for _, peer := range peers {
+			s.prober.Add(peer.URL())
+		}
+	}
+
+	if s.results != nil {
+		if r, ok := resolver.(pgresolver.PeerGroupsResolver); ok {
+			return s.cachedEndorsers(newResultKey(key, params.Collections), r, peers, params.PeerFilter)
+		}
+	}
+
 	if params.PeerFilter != nil {
 		var filteredPeers []fab.Peer
 		for _, peer := range peers {
@@ -199,12 +306,61 @@ func (s *selectionService) GetEndorsersForChaincode(chaincodeIDs []string, opts
 	return peerGroup.Peers(), nil
 }
 
+// cachedEndorsers chooses the endorsers among the cached peer groups which satisfy the chaincode policies.
+// Filtering the peer groups is equivalent to resolving the peer groups of the filtered peers.
+func (s *selectionService) cachedEndorsers(key string, resolver pgresolver.PeerGroupsResolver, peers []fab.Peer, filter options.PeerFilter) ([]fab.Peer, error) {
+	configBlock, err := s.configBlockNumber()
+	if err != nil {
+		return nil, err
+	}
+	peerGroups, err := s.results.peerGroups(key, configBlock, resolver, peers)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter != nil {
+		var filteredGroups []pgresolver.PeerGroup
+		for _, peerGroup := range peerGroups {
+			if acceptsAll(filter, peerGroup.Peers()) {
+				filteredGroups = append(filteredGroups, peerGroup)
+			}
+		}
+		peerGroups = filteredGroups
+	}
+
+	return s.pgLBP.Choose(peerGroups).Peers(), nil
+}
+
+// configBlockNumber returns the number of the channel's last config block
+func (s *selectionService) configBlockNumber() (uint64, error) {
+	if s.channelService == nil {
+		return 0, nil
+	}
+	chConfig, err := s.channelService.ChannelConfig()
+	if err != nil {
+		return 0, errors.WithMessage(err, fmt.Sprintf("failed to get config of channel [%s]", s.channelID))
+	}
+	return chConfig.BlockNumber(), nil
+}
+
+func acceptsAll(filter options.PeerFilter, peers []fab.Peer) bool {
+	for _, peer := range peers {
+		if !filter(peer) {
+			return false
+		}
+	}
+	return true
+}
+
 func (s *selectionService) Close() {
 	s.pgResolvers.Close()
+	if s.prober != nil {
+		s.prober.Close()
+	}
 }
 
-func (s *selectionService) getPeerGroupResolver(chaincodeIDs []string) (pgresolver.PeerGroupResolver, error) {
-	value, err := s.pgResolvers.Get(newResolverKey(s.channelID, chaincodeIDs...))
+func (s *selectionService) getPeerGroupResolver(key *resolverKey) (pgresolver.PeerGroupResolver, error) {
+	value, err := s.pgResolvers.Get(key)
 	if err != nil {
 		return nil, err
 	}
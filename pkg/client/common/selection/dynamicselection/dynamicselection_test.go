@@ -13,6 +13,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/dynamicselection/pgresolver"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
@@ -172,6 +173,81 @@ func TestGetEndorsersForChaincodeTwoCCsTwoChannels(t *testing.T) {
 	verify(t, service, expected, channel2, cc1, cc2)
 }
 
+func TestGetEndorsersForChaincodeCachedResult(t *testing.T) {
+	discoveryService := &mockDiscoveryService{peers: []fab.Peer{p1, p2, p3, p4}}
+	selService, err := newMockSelectionService(
+		newMockCCDataProvider("").
+			add(cc1, getPolicy1()).
+			add(cc2, getPolicy2()),
+		pgresolver.NewRoundRobinLBP(),
+		discoveryService,
+	)
+	if err != nil {
+		t.Fatalf("got error creating selection service: %s", err)
+	}
+	service := selService.(*selectionService)
+	service.results = newResultCache(time.Minute)
+
+	// Org1 and Org2
+	verify(t, service, []pgresolver.PeerGroup{pg(p1, p3), pg(p1, p4), pg(p2, p3), pg(p2, p4)}, "", cc1, cc2)
+	key := newResolverKey("", cc1, cc2).String()
+	result := service.results.results[key]
+	if result == nil {
+		t.Fatalf("expected peer groups to be cached")
+	}
+
+	verify(t, service, []pgresolver.PeerGroup{pg(p1, p3), pg(p1, p4), pg(p2, p3), pg(p2, p4)}, "", cc1, cc2)
+	if service.results.results[key] != result {
+		t.Fatalf("expected peer groups to be served from the cache")
+	}
+
+	// The peer filter is applied to the cached peer groups
+	peers, err := service.GetEndorsersForChaincode([]string{cc1, cc2}, options.WithPeerFilter(func(peer fab.Peer) bool {
+		return peer.URL() != p1.URL() && peer.URL() != p3.URL()
+	}))
+	if err != nil {
+		t.Fatalf("error getting endorsers: %s", err)
+	}
+	if !containsAllPeers(peers, pg(p2, p4)) {
+		t.Fatalf("expected peer group %s but got %s", pg(p2, p4), toString(peers))
+	}
+
+	// The peer groups are resolved again when the channel's peers change
+	discoveryService.peers = []fab.Peer{p2, p3}
+	verify(t, service, []pgresolver.PeerGroup{pg(p2, p3)}, "", cc1, cc2)
+	if service.results.results[key] == result {
+		t.Fatalf("expected peer groups to be resolved again")
+	}
+
+	// Expired peer groups are resolved again
+	result = service.results.results[key]
+	result.expiry = time.Now()
+	verify(t, service, []pgresolver.PeerGroup{pg(p2, p3)}, "", cc1, cc2)
+	if service.results.results[key] == result {
+		t.Fatalf("expected expired peer groups to be resolved again")
+	}
+
+	// The peer groups are cached separately for each set of collections
+	if _, err = service.GetEndorsersForChaincode([]string{cc1, cc2}, options.WithCollections("coll2", "coll1")); err != nil {
+		t.Fatalf("error getting endorsers: %s", err)
+	}
+	collKey := newResultKey(newResolverKey("", cc1, cc2), []string{"coll1", "coll2"})
+	if collKey == key || service.results.results[collKey] == nil {
+		t.Fatalf("expected peer groups to be cached by collections")
+	}
+
+	// All peer groups are resolved again when the channel config changes
+	result = service.results.results[key]
+	service.channelService = &mockChannelService{blockNumber: 1}
+	verify(t, service, []pgresolver.PeerGroup{pg(p2, p3)}, "", cc1, cc2)
+	if service.results.results[key] == result {
+		t.Fatalf("expected peer groups to be resolved again after config change")
+	}
+	if service.results.results[collKey] != nil {
+		t.Fatalf("expected all cached peer groups to be invalidated after config change")
+	}
+}
+
 func verify(t *testing.T, service fab.SelectionService, expectedPeerGroups []pgresolver.PeerGroup, channelID string, chaincodeIDs ...string) {
 	// Set the log level to WARNING since the following spits out too much info in DEBUG
 	module := "pg-resolver"
@@ -491,3 +567,12 @@ func newMockDiscoveryService(peers ...fab.Peer) fab.DiscoveryService {
 func (s *mockDiscoveryService) GetPeers() ([]fab.Peer, error) {
 	return s.peers, nil
 }
+
+type mockChannelService struct {
+	fab.ChannelService
+	blockNumber uint64
+}
+
+func (cs *mockChannelService) ChannelConfig() (fab.ChannelCfg, error) {
+	return &mocks.MockChannelCfg{MockBlockNumber: cs.blockNumber}, nil
+}
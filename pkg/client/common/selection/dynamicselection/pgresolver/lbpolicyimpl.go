@@ -8,6 +8,9 @@ package pgresolver
 
 import (
 	"math/rand"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/balancer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 )
 
 type randomLBP struct {
@@ -63,3 +66,30 @@ func (lbp *roundRobinLBP) Choose(peerGroups []PeerGroup) PeerGroup {
 
 	return peerGroups[lbp.index]
 }
+
+type balancedLBP struct {
+	balancer balancer.Balancer
+}
+
+// NewBalancedLBP returns a load-balance policy which chooses peer groups using the given balancer
+func NewBalancedLBP(b balancer.Balancer) LoadBalancePolicy {
+	return &balancedLBP{balancer: b}
+}
+
+func (lbp *balancedLBP) Choose(peerGroups []PeerGroup) PeerGroup {
+	if len(peerGroups) == 0 {
+		logger.Warn("No available peer groups\n")
+		// Return an empty PeerGroup
+		return NewPeerGroup()
+	}
+
+	candidates := make([][]fab.Peer, len(peerGroups))
+	for i, peerGroup := range peerGroups {
+		candidates[i] = peerGroup.Peers()
+	}
+
+	index := lbp.balancer.Choose(candidates)
+
+	logger.Debugf("balancedLBP - Choosing index %d\n", index)
+	return peerGroups[index]
+}
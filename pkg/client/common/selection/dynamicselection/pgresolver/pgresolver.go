@@ -18,6 +18,15 @@ type PeerGroupResolver interface {
 	Resolve(peers []fab.Peer) (PeerGroup, error)
 }
 
+// PeerGroupsResolver is a PeerGroupResolver which also returns all of the peer groups that satisfy
+// a chaincode's endorsement policy, so that they can be cached and chosen from later.
+type PeerGroupsResolver interface {
+	PeerGroupResolver
+	// PeerGroups returns all of the groups of the given set of available peers which satisfy
+	// the endorsement policy.
+	PeerGroups(peers []fab.Peer) ([]PeerGroup, error)
+}
+
 // LoadBalancePolicy is used to pick a peer group from a given set of peer groups
 type LoadBalancePolicy interface {
 	// Choose returns one of the peer groups from the given set of peer groups.
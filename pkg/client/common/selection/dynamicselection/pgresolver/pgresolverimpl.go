@@ -48,7 +48,7 @@ func NewRandomPeerGroupResolver(sigPolicyEnv *common.SignaturePolicyEnvelope) (P
 	return NewPeerGroupResolver(groupRetriever, NewRandomLBP())
 }
 
-// NewPeerGroupResolver returns a new PeerGroupResolver
+// NewPeerGroupResolver returns a new PeerGroupResolver. The returned resolver is also a PeerGroupsResolver.
 func NewPeerGroupResolver(groupRetriever GroupRetriever, lbp LoadBalancePolicy) (PeerGroupResolver, error) {
 	return &peerGroupResolver{
 		groupRetriever: groupRetriever,
@@ -57,6 +57,15 @@ func NewPeerGroupResolver(groupRetriever GroupRetriever, lbp LoadBalancePolicy)
 }
 
 func (c *peerGroupResolver) Resolve(peers []fab.Peer) (PeerGroup, error) {
+	peerGroups, err := c.PeerGroups(peers)
+	if err != nil {
+		return nil, err
+	}
+	return c.lbp.Choose(peerGroups), nil
+}
+
+// PeerGroups returns all of the groups of the given peers which satisfy the policy
+func (c *peerGroupResolver) PeerGroups(peers []fab.Peer) ([]PeerGroup, error) {
 	peerRetriever := func(mspID string) []fab.Peer {
 		var mspPeers []fab.Peer
 		for _, peer := range peers {
@@ -89,7 +98,7 @@ func (c *peerGroupResolver) Resolve(peers []fab.Peer) (PeerGroup, error) {
 		logger.Debugf(s)
 	}
 
-	return c.lbp.Choose(peerGroups), nil
+	return peerGroups, nil
 }
 
 func (c *peerGroupResolver) getPeerGroups(peerRetriever MSPPeerRetriever) ([]PeerGroup, error) {
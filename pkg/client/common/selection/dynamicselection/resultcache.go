@@ -0,0 +1,117 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dynamicselection
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/dynamicselection/pgresolver"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+// resultCache caches the peer groups which satisfy the endorsement policies of a set of chaincodes
+// (and collections), so that they aren't computed for each transaction. A cached result expires after
+// the TTL and is invalidated when the channel's peers change or when the chaincode policies are refreshed.
+// All results are invalidated when the channel config (and hence the channel's membership) changes.
+type resultCache struct {
+	ttl         time.Duration
+	lock        sync.RWMutex
+	results     map[string]*selectionResult
+	configBlock uint64
+}
+
+type selectionResult struct {
+	resolver    pgresolver.PeerGroupsResolver
+	peersKey    string
+	configBlock uint64
+	peerGroups  []pgresolver.PeerGroup
+	expiry      time.Time
+}
+
+func newResultCache(ttl time.Duration) *resultCache {
+	return &resultCache{
+		ttl:     ttl,
+		results: make(map[string]*selectionResult),
+	}
+}
+
+// newResultKey returns the key of the selection result of the given chaincodes and collections
+func newResultKey(key *resolverKey, collections []string) string {
+	if len(collections) == 0 {
+		return key.String()
+	}
+	sorted := make([]string, len(collections))
+	copy(sorted, collections)
+	sort.Strings(sorted)
+	return key.String() + "|" + strings.Join(sorted, ",")
+}
+
+// validate invalidates all cached results if they were resolved for another config block of the channel
+func (c *resultCache) validate(configBlock uint64) {
+	c.lock.RLock()
+	current := c.configBlock
+	c.lock.RUnlock()
+
+	if configBlock == current {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if configBlock != c.configBlock {
+		logger.Debugf("Channel config changed (block %d -> %d), invalidating cached selection results", c.configBlock, configBlock)
+		c.results = make(map[string]*selectionResult)
+		c.configBlock = configBlock
+	}
+}
+
+// peerGroups returns the cached peer groups of the given key or, if they aren't cached (or the cached
+// result is no longer valid for the given config block, resolver and peers), resolves and caches them
+func (c *resultCache) peerGroups(key string, configBlock uint64, resolver pgresolver.PeerGroupsResolver, peers []fab.Peer) ([]pgresolver.PeerGroup, error) {
+	c.validate(configBlock)
+	peersKey := newPeersKey(peers)
+
+	c.lock.RLock()
+	result, ok := c.results[key]
+	c.lock.RUnlock()
+
+	if ok && result.resolver == resolver && result.peersKey == peersKey && result.configBlock == configBlock && time.Now().Before(result.expiry) {
+		return result.peerGroups, nil
+	}
+
+	logger.Debugf("Resolving peer groups for [%s]", key)
+	peerGroups, err := resolver.PeerGroups(peers)
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	c.results[key] = &selectionResult{
+		resolver:    resolver,
+		peersKey:    peersKey,
+		configBlock: configBlock,
+		peerGroups:  peerGroups,
+		expiry:      time.Now().Add(c.ttl),
+	}
+	c.lock.Unlock()
+
+	return peerGroups, nil
+}
+
+// newPeersKey returns a key which identifies the membership of the given peers
+func newPeersKey(peers []fab.Peer) string {
+	ids := make([]string, len(peers))
+	for i, peer := range peers {
+		ids[i] = peer.MSPID() + "/" + peer.URL()
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
@@ -19,7 +19,8 @@ type PeerFilter func(peer fab.Peer) bool
 
 // Params defines the parameters of a selection service request
 type Params struct {
-	PeerFilter PeerFilter
+	PeerFilter  PeerFilter
+	Collections []string
 }
 
 // NewParams creates new parameters based on the provided options
@@ -47,3 +48,23 @@ func (p *Params) SetPeerFilter(value PeerFilter) {
 	logger.Debugf("PeerFilter: %#v", value)
 	p.PeerFilter = value
 }
+
+// WithCollections sets the private data collections which are accessed by the chaincodes of the request.
+// Selection results are cached separately for each set of collections.
+func WithCollections(collections ...string) copts.Opt {
+	return func(p copts.Params) {
+		if setter, ok := p.(collectionsSetter); ok {
+			setter.SetCollections(collections)
+		}
+	}
+}
+
+type collectionsSetter interface {
+	SetCollections(value []string)
+}
+
+// SetCollections sets the collections
+func (p *Params) SetCollections(value []string) {
+	logger.Debugf("Collections: %v", value)
+	p.Collections = value
+}
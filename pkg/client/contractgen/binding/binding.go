@@ -0,0 +1,137 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package binding provides the runtime support of the contract clients generated by contractgen.
+package binding
+
+import (
+	"encoding/json"
+	"regexp"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+// ChannelClient is the subset of the channel client used by the generated contract clients
+// (implemented by channel.Client and mockchannel.Client)
+type ChannelClient interface {
+	Query(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+	Execute(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+	RegisterChaincodeEvent(chainCodeID string, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error)
+	UnregisterChaincodeEvent(registration fab.Registration)
+}
+
+// Contract invokes the transactions of a contract of a chaincode
+type Contract struct {
+	client      ChannelClient
+	chaincodeID string
+	name        string
+}
+
+// NewContract returns the contract with the given name of the chaincode. The transactions of the contract
+// are invoked with their qualified names (contract:transaction), or their names if the name is empty.
+func NewContract(client ChannelClient, chaincodeID string, name string) *Contract {
+	return &Contract{client: client, chaincodeID: chaincodeID, name: name}
+}
+
+// Query evaluates the transaction with the given arguments (see MarshalArgs)
+func (c *Contract) Query(txName string, args []interface{}, options ...channel.RequestOption) (channel.Response, error) {
+	request, err := c.request(txName, args)
+	if err != nil {
+		return channel.Response{}, err
+	}
+	response, err := c.client.Query(request, options...)
+	if err != nil {
+		return response, errors.WithMessage(err, "failed to evaluate transaction "+request.Fcn)
+	}
+	return response, nil
+}
+
+// Execute submits the transaction with the given arguments (see MarshalArgs)
+func (c *Contract) Execute(txName string, args []interface{}, options ...channel.RequestOption) (channel.Response, error) {
+	request, err := c.request(txName, args)
+	if err != nil {
+		return channel.Response{}, err
+	}
+	response, err := c.client.Execute(request, options...)
+	if err != nil {
+		return response, errors.WithMessage(err, "failed to submit transaction "+request.Fcn)
+	}
+	return response, nil
+}
+
+// RegisterEvent registers for the chaincode events with the given name
+func (c *Contract) RegisterEvent(eventName string) (fab.Registration, <-chan *fab.CCEvent, error) {
+	reg, events, err := c.client.RegisterChaincodeEvent(c.chaincodeID, "^"+regexp.QuoteMeta(eventName)+"$")
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to register for event "+eventName)
+	}
+	return reg, events, nil
+}
+
+// Unregister removes the event registration
+func (c *Contract) Unregister(reg fab.Registration) {
+	c.client.UnregisterChaincodeEvent(reg)
+}
+
+func (c *Contract) request(txName string, args []interface{}) (channel.Request, error) {
+	fcn := txName
+	if c.name != "" {
+		fcn = c.name + ":" + txName
+	}
+	marshalled, err := MarshalArgs(args...)
+	if err != nil {
+		return channel.Request{}, errors.WithMessage(err, "invalid arguments of transaction "+fcn)
+	}
+	return channel.Request{ChaincodeID: c.chaincodeID, Fcn: fcn, Args: marshalled}, nil
+}
+
+// MarshalArgs marshals the arguments of a transaction as the contract API expects them:
+// strings and byte slices as is and the other values as JSON
+func MarshalArgs(args ...interface{}) ([][]byte, error) {
+	marshalled := make([][]byte, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case string:
+			marshalled[i] = []byte(v)
+		case []byte:
+			marshalled[i] = v
+		default:
+			bytes, err := json.Marshal(arg)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to marshal argument %d", i)
+			}
+			marshalled[i] = bytes
+		}
+	}
+	return marshalled, nil
+}
+
+// Unmarshal unmarshals the result of a transaction or the payload of an event into the value pointed to by v.
+// Strings are returned as is and the other values are unmarshalled from JSON. An empty payload leaves the value unchanged.
+func Unmarshal(payload []byte, v interface{}) error {
+	if s, ok := v.(*string); ok {
+		*s = string(payload)
+		return nil
+	}
+	if len(payload) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal payload into %T", v)
+	}
+	return nil
+}
+
+// InvalidEvent logs an event which is dropped because its payload can't be decoded
+func InvalidEvent(event *fab.CCEvent, err error) {
+	logger.Warnf("Dropping event [%s] of transaction [%s]: %s", event.EventName, event.TxID, err)
+}
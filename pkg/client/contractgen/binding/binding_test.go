@@ -0,0 +1,65 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package binding
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/test/mockchannel"
+)
+
+func TestMarshalArgs(t *testing.T) {
+	args, err := MarshalArgs("asset1", []byte("raw"), int64(10), true, map[string]string{"color": "blue"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("asset1"), []byte("raw"), []byte("10"), []byte("true"), []byte(`{"color":"blue"}`)}, args)
+
+	_, err = MarshalArgs(make(chan int))
+	assert.Error(t, err)
+}
+
+func TestUnmarshal(t *testing.T) {
+	var s string
+	require.NoError(t, Unmarshal([]byte("asset1"), &s))
+	assert.Equal(t, "asset1", s)
+
+	var n int64
+	require.NoError(t, Unmarshal([]byte("10"), &n))
+	assert.Equal(t, int64(10), n)
+	require.NoError(t, Unmarshal(nil, &n), "expecting empty payload to be ignored")
+	assert.Equal(t, int64(10), n)
+
+	var m map[string]string
+	assert.Error(t, Unmarshal([]byte("not json"), &m))
+}
+
+func TestContract(t *testing.T) {
+	client := mockchannel.New()
+	client.OnQuery("assets", "AssetContract:ReadAsset").Return([]byte(`{"ID":"asset1"}`))
+	client.OnExecute("assets", "AssetContract:DeleteAsset").ReturnError(errors.New("asset not found"))
+	client.OnQuery("assets", "ReadAsset").Return([]byte(`{}`))
+
+	contract := NewContract(client, "assets", "AssetContract")
+	response, err := contract.Query("ReadAsset", []interface{}{"asset1"})
+	require.NoError(t, err)
+	assert.Equal(t, []byte(`{"ID":"asset1"}`), response.Payload)
+
+	_, err = contract.Execute("DeleteAsset", []interface{}{"asset1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "asset not found")
+
+	_, err = NewContract(client, "assets", "").Query("ReadAsset", nil)
+	require.NoError(t, err, "expecting unqualified transaction name without contract name")
+
+	calls := client.Calls()
+	require.Len(t, calls, 3)
+	assert.Equal(t, channel.Request{ChaincodeID: "assets", Fcn: "AssetContract:ReadAsset", Args: [][]byte{[]byte("asset1")}}, calls[0].Request)
+}
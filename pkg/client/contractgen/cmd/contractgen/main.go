@@ -0,0 +1,56 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Command contractgen generates the typed Go client of a chaincode written with the contract API from
+// the metadata of the chaincode (see package contractgen). It's meant to be run with go generate:
+//
+//  //go:generate go run github.com/hyperledger/fabric-sdk-go/pkg/client/contractgen/cmd/contractgen -metadata metadata.json -package assets -out assets.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/contractgen"
+)
+
+func main() {
+	metadataPath := flag.String("metadata", "", "path of the JSON metadata of the chaincode")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "package of the generated code (defaults to the package of go generate)")
+	out := flag.String("out", "", "path of the generated file (defaults to standard output)")
+	flag.Parse()
+
+	if err := generate(*metadataPath, *pkg, *out); err != nil {
+		fmt.Fprintf(os.Stderr, "contractgen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func generate(metadataPath, pkg, out string) error {
+	if metadataPath == "" {
+		return fmt.Errorf("must provide metadata path")
+	}
+	data, err := ioutil.ReadFile(metadataPath)
+	if err != nil {
+		return err
+	}
+	metadata, err := contractgen.ParseMetadata(data)
+	if err != nil {
+		return err
+	}
+	src, err := contractgen.Generate(metadata, contractgen.Options{Package: pkg, Source: filepath.Base(metadataPath)})
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return ioutil.WriteFile(out, src, 0644)
+}
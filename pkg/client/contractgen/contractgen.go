@@ -0,0 +1,500 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package contractgen generates typed Go clients of chaincodes written with the contract API, from the
+// transaction schema (metadata) of the chaincode. For each contract the generator emits a client type with
+// a method per transaction, which marshals the arguments, submits or evaluates the transaction with the
+// channel client and unmarshals the result, the structs of the schemas of the metadata and, for the events
+// added to the metadata, typed event registrations.
+//
+// The generator is usually run with go generate, with the contractgen command:
+//
+//  //go:generate go run github.com/hyperledger/fabric-sdk-go/pkg/client/contractgen/cmd/contractgen -metadata metadata.json -package assets -out assets.go
+//
+//  Basic Flow:
+//  1) Get the metadata of the chaincode (e.g. with the org.hyperledger.fabric:GetMetadata transaction)
+//  2) Generate the client
+//  3) Create the contract client with the channel client
+//
+//  contract := assets.NewAssetContract(channelClient, "assets")
+//  asset, err := contract.ReadAsset("asset1")
+//  ...
+//  txID, err := contract.CreateAsset(assets.Asset{ID: "asset2", Size: 10})
+package contractgen
+
+import (
+	"bytes"
+	"go/format"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// Options are the options of the generator
+type Options struct {
+	// Package is the name of the package of the generated code
+	Package string
+	// Source is the name of the metadata file, which is recorded in the header of the generated code
+	Source string
+}
+
+// Generate generates the typed clients of the contracts of the chaincode and returns the formatted source
+func Generate(metadata *Metadata, opts Options) ([]byte, error) {
+	if metadata == nil {
+		return nil, errors.New("metadata is nil")
+	}
+	if opts.Package == "" {
+		return nil, errors.New("must provide package name")
+	}
+
+	file, err := newFileModel(metadata, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := fileTemplate.Execute(&buf, file); err != nil {
+		return nil, errors.Wrap(err, "failed to generate contract bindings")
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to format contract bindings")
+	}
+	return src, nil
+}
+
+type fileModel struct {
+	Package   string
+	Source    string
+	NeedsFab  bool
+	Structs   []structModel
+	Contracts []contractModel
+}
+
+type structModel struct {
+	Name   string
+	Doc    string
+	Fields []fieldModel
+}
+
+type fieldModel struct {
+	Name string
+	Type string
+	Tag  string
+}
+
+type contractModel struct {
+	Name         string
+	TypeName     string
+	Transactions []transactionModel
+	Events       []eventModel
+}
+
+type transactionModel struct {
+	Name       string
+	Method     string
+	Submit     bool
+	Params     []fieldModel
+	Args       string
+	ReturnType string
+	Results    string
+}
+
+type eventModel struct {
+	Name        string
+	TypeName    string
+	PayloadType string
+}
+
+func newFileModel(metadata *Metadata, opts Options) (*fileModel, error) {
+	g := &generator{schemas: metadata.Components.Schemas}
+	file := &fileModel{Package: opts.Package, Source: opts.Source}
+
+	for _, name := range sortedKeys(metadata.Components.Schemas) {
+		s, err := g.structModel(name, metadata.Components.Schemas[name])
+		if err != nil {
+			return nil, err
+		}
+		file.Structs = append(file.Structs, s)
+	}
+
+	var contractNames []string
+	for name := range metadata.Contracts {
+		contractNames = append(contractNames, name)
+	}
+	sort.Strings(contractNames)
+
+	types := make(map[string]string)
+	for _, s := range file.Structs {
+		types[s.Name] = "schema " + s.Name
+	}
+	for _, name := range contractNames {
+		contract := metadata.Contracts[name]
+		if contract.Name == systemContract {
+			continue
+		}
+		c, err := g.contractModel(contract)
+		if err != nil {
+			return nil, errors.WithMessage(err, "contract "+contract.Name)
+		}
+		for _, typeName := range c.typeNames() {
+			if other, ok := types[typeName]; ok {
+				return nil, errors.Errorf("type [%s] of contract [%s] conflicts with %s", typeName, contract.Name, other)
+			}
+			types[typeName] = "contract " + contract.Name
+		}
+		if len(c.Events) > 0 {
+			file.NeedsFab = true
+		}
+		for _, tx := range c.Transactions {
+			if tx.Submit {
+				file.NeedsFab = true
+			}
+		}
+		file.Contracts = append(file.Contracts, c)
+	}
+	return file, nil
+}
+
+func (c *contractModel) typeNames() []string {
+	names := []string{c.TypeName}
+	for _, e := range c.Events {
+		names = append(names, e.TypeName)
+	}
+	return names
+}
+
+// generator maps the schemas of the metadata to Go types
+type generator struct {
+	schemas map[string]Schema
+}
+
+func (g *generator) structModel(name string, schema Schema) (structModel, error) {
+	s := structModel{Name: goName(name), Doc: schema.Description}
+	if s.Doc == "" {
+		s.Doc = s.Name + " is the schema " + name + " of the chaincode"
+	}
+
+	required := make(map[string]bool)
+	for _, property := range schema.Required {
+		required[property] = true
+	}
+	for _, property := range sortedKeys(schema.Properties) {
+		propertySchema := schema.Properties[property]
+		fieldType, err := g.goType(&propertySchema, !required[property])
+		if err != nil {
+			return structModel{}, errors.WithMessage(err, "property "+property+" of schema "+name)
+		}
+		tag := property
+		if !required[property] {
+			tag += ",omitempty"
+		}
+		s.Fields = append(s.Fields, fieldModel{Name: goName(property), Type: fieldType, Tag: tag})
+	}
+	return s, nil
+}
+
+func (g *generator) contractModel(contract Contract) (contractModel, error) {
+	c := contractModel{Name: contract.Name, TypeName: goName(contract.Name)}
+	if !strings.HasSuffix(c.TypeName, "Contract") {
+		c.TypeName += "Contract"
+	}
+
+	methods := map[string]bool{"Unregister": true}
+	for _, tx := range contract.Transactions {
+		t, err := g.transactionModel(tx)
+		if err != nil {
+			return contractModel{}, errors.WithMessage(err, "transaction "+tx.Name)
+		}
+		if methods[t.Method] {
+			return contractModel{}, errors.Errorf("method [%s] of transaction [%s] is already defined", t.Method, tx.Name)
+		}
+		methods[t.Method] = true
+		c.Transactions = append(c.Transactions, t)
+	}
+
+	for _, event := range contract.Events {
+		e := eventModel{Name: event.Name, TypeName: goName(event.Name) + "Event", PayloadType: "[]byte"}
+		if event.Schema != nil {
+			payloadType, err := g.goType(event.Schema, true)
+			if err != nil {
+				return contractModel{}, errors.WithMessage(err, "event "+event.Name)
+			}
+			e.PayloadType = payloadType
+		}
+		method := "Register" + e.TypeName
+		if methods[method] {
+			return contractModel{}, errors.Errorf("method [%s] of event [%s] is already defined", method, event.Name)
+		}
+		methods[method] = true
+		c.Events = append(c.Events, e)
+	}
+	return c, nil
+}
+
+func (g *generator) transactionModel(tx Transaction) (transactionModel, error) {
+	t := transactionModel{Name: tx.Name, Method: goName(tx.Name), Submit: tx.IsSubmit()}
+
+	names := make(map[string]bool)
+	var args []string
+	for _, param := range tx.Parameters {
+		paramSchema := param.Schema
+		paramType, err := g.goType(&paramSchema, false)
+		if err != nil {
+			return transactionModel{}, errors.WithMessage(err, "parameter "+param.Name)
+		}
+		name := paramName(param.Name)
+		for names[name] {
+			name += "_"
+		}
+		names[name] = true
+		t.Params = append(t.Params, fieldModel{Name: name, Type: paramType})
+		args = append(args, name)
+	}
+	t.Args = "nil"
+	if len(args) > 0 {
+		t.Args = "[]interface{}{" + strings.Join(args, ", ") + "}"
+	}
+
+	var results []string
+	if tx.Returns != nil {
+		returnType, err := g.goType(tx.Returns, true)
+		if err != nil {
+			return transactionModel{}, errors.WithMessage(err, "return value")
+		}
+		t.ReturnType = returnType
+		results = append(results, "result")
+	}
+	if t.Submit {
+		results = append(results, "txID")
+	}
+	t.Results = strings.Join(append(results, "err"), ", ")
+	return t, nil
+}
+
+// goType returns the Go type of the schema. References to the schemas of the metadata are mapped
+// to pointers to the generated structs if pointer is true.
+func (g *generator) goType(schema *Schema, pointer bool) (string, error) {
+	if schema.Ref != "" {
+		if !strings.HasPrefix(schema.Ref, schemaRefPrefix) {
+			return "", errors.Errorf("unsupported reference [%s]", schema.Ref)
+		}
+		name := strings.TrimPrefix(schema.Ref, schemaRefPrefix)
+		if _, ok := g.schemas[name]; !ok {
+			return "", errors.Errorf("schema [%s] not found", name)
+		}
+		if pointer {
+			return "*" + goName(name), nil
+		}
+		return goName(name), nil
+	}
+
+	switch schema.Type {
+	case "string":
+		return "string", nil
+	case "integer":
+		if schema.Format == "int32" {
+			return "int32", nil
+		}
+		return "int64", nil
+	case "number":
+		if schema.Format == "float" {
+			return "float32", nil
+		}
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "array":
+		if schema.Items == nil {
+			return "[]interface{}", nil
+		}
+		itemType, err := g.goType(schema.Items, false)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + itemType, nil
+	case "object":
+		return "map[string]interface{}", nil
+	case "":
+		return "interface{}", nil
+	default:
+		return "", errors.Errorf("unsupported type [%s]", schema.Type)
+	}
+}
+
+// initialisms are the words which are written in upper case in Go names
+var initialisms = map[string]bool{
+	"API": true, "HTTP": true, "ID": true, "JSON": true, "MSP": true, "TX": true, "URL": true,
+}
+
+// goName returns the exported Go name of the given name
+func goName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for _, word := range words {
+		if initialisms[strings.ToUpper(word)] {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+
+	s := b.String()
+	if s == "" || unicode.IsDigit([]rune(s)[0]) {
+		s = "X" + s
+	}
+	return s
+}
+
+// reservedNames are the names which can't be used as parameter names of the generated methods
+var reservedNames = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true, "default": true,
+	"defer": true, "else": true, "fallthrough": true, "for": true, "func": true, "go": true, "goto": true,
+	"if": true, "import": true, "interface": true, "map": true, "package": true, "range": true,
+	"return": true, "select": true, "struct": true, "switch": true, "type": true, "var": true,
+	"c": true, "options": true, "response": true, "result": true, "txID": true, "err": true,
+	"binding": true, "channel": true, "fab": true,
+}
+
+// paramName returns the unexported Go name of the given name
+func paramName(name string) string {
+	runes := []rune(goName(name))
+	// Lower the leading upper case run, except for the first letter of the next word (e.g. IDValue -> idValue)
+	n := 0
+	for n < len(runes) && unicode.IsUpper(runes[n]) {
+		n++
+	}
+	if n > 1 && n < len(runes) && unicode.IsLower(runes[n]) {
+		n--
+	}
+	if n == 0 {
+		n = 1
+	}
+	for i := 0; i < n; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+
+	s := string(runes)
+	if reservedNames[s] {
+		s += "Arg"
+	}
+	return s
+}
+
+func sortedKeys(m map[string]Schema) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// comment formats the text as a Go comment
+func comment(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("// "+strings.TrimSpace(line), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+var fileTemplate = template.Must(template.New("contract").Funcs(template.FuncMap{"comment": comment}).Parse(`// Code generated by contractgen. DO NOT EDIT.
+{{- if .Source}}
+// Source: {{.Source}}
+{{- end}}
+
+package {{.Package}}
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/contractgen/binding"
+{{- if .NeedsFab}}
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+{{- end}}
+)
+{{range .Structs}}
+{{comment .Doc}}
+type {{.Name}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}} ` + "`" + `json:"{{.Tag}}"` + "`" + `
+{{- end}}
+}
+{{end}}
+{{- range $c := .Contracts}}
+// {{.TypeName}} is the client of the contract {{.Name}}
+type {{.TypeName}} struct {
+	contract *binding.Contract
+}
+
+// New{{.TypeName}} returns the client of the contract {{.Name}} of the given chaincode
+func New{{.TypeName}}(client binding.ChannelClient, chaincodeID string) *{{.TypeName}} {
+	return &{{.TypeName}}{contract: binding.NewContract(client, chaincodeID, "{{.Name}}")}
+}
+{{range .Transactions}}
+// {{.Method}} {{if .Submit}}submits{{else}}evaluates{{end}} the transaction {{.Name}}
+func (c *{{$c.TypeName}}) {{.Method}}({{range .Params}}{{.Name}} {{.Type}}, {{end}}options ...channel.RequestOption) ({{if .ReturnType}}result {{.ReturnType}}, {{end}}{{if .Submit}}txID fab.TransactionID, {{end}}err error) {
+	{{if or .Submit .ReturnType}}response, err :={{else}}_, err ={{end}} c.contract.{{if .Submit}}Execute{{else}}Query{{end}}("{{.Name}}", {{.Args}}, options...)
+	if err != nil {
+		return {{.Results}}
+	}
+{{- if .Submit}}
+	txID = response.TransactionID
+{{- end}}
+{{- if .ReturnType}}
+	err = binding.Unmarshal(response.Payload, &result)
+{{- end}}
+	return {{.Results}}
+}
+{{end}}
+{{- range .Events}}
+// {{.TypeName}} is the event {{.Name}} of the contract {{$c.Name}}
+type {{.TypeName}} struct {
+	*fab.CCEvent
+	Payload {{.PayloadType}}
+}
+
+// Register{{.TypeName}} registers for the {{.Name}} events of the contract. Events whose payload
+// can't be decoded are dropped. The event channel is closed once the registration is removed with Unregister.
+func (c *{{$c.TypeName}}) Register{{.TypeName}}() (fab.Registration, <-chan *{{.TypeName}}, error) {
+	reg, events, err := c.contract.RegisterEvent("{{.Name}}")
+	if err != nil {
+		return nil, nil, err
+	}
+	eventch := make(chan *{{.TypeName}}, cap(events))
+	go func() {
+		defer close(eventch)
+		for event := range events {
+			typed := &{{.TypeName}}{CCEvent: event}
+{{- if eq .PayloadType "[]byte"}}
+			typed.Payload = event.Payload
+{{- else}}
+			if err := binding.Unmarshal(event.Payload, &typed.Payload); err != nil {
+				binding.InvalidEvent(event, err)
+				continue
+			}
+{{- end}}
+			eventch <- typed
+		}
+	}()
+	return reg, eventch, nil
+}
+{{end}}
+{{- if .Events}}
+// Unregister removes the event registration and closes its event channel
+func (c *{{.TypeName}}) Unregister(reg fab.Registration) {
+	c.contract.Unregister(reg)
+}
+{{end}}
+{{- end}}`))
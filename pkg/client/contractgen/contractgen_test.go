@@ -0,0 +1,106 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package contractgen
+
+import (
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMetadata(t *testing.T) {
+	metadata := loadMetadata(t)
+
+	contract, ok := metadata.Contracts["AssetContract"]
+	require.True(t, ok)
+	require.Len(t, contract.Transactions, 6)
+	assert.Len(t, contract.Events, 2)
+
+	readAsset := contract.Transactions[1]
+	assert.False(t, readAsset.IsSubmit())
+	require.NotNil(t, readAsset.Returns)
+	assert.Equal(t, "#/components/schemas/Asset", readAsset.Returns.Ref)
+
+	assetExists := contract.Transactions[3]
+	require.NotNil(t, assetExists.Returns, "expecting wrapped return schema")
+	assert.Equal(t, "boolean", assetExists.Returns.Type)
+
+	deleteAsset := contract.Transactions[5]
+	assert.True(t, deleteAsset.IsSubmit(), "expecting untagged transaction to be submitted")
+	assert.Nil(t, deleteAsset.Returns)
+
+	_, err := ParseMetadata([]byte("{"))
+	assert.Error(t, err)
+}
+
+func TestGenerate(t *testing.T) {
+	src, err := Generate(loadMetadata(t), Options{Package: "example", Source: "metadata.json"})
+	require.NoError(t, err)
+
+	_, err = parser.ParseFile(token.NewFileSet(), "assets.go", src, 0)
+	require.NoError(t, err, "expecting valid Go source")
+
+	code := string(src)
+	assert.Contains(t, code, "func NewAssetContract(client binding.ChannelClient, chaincodeID string) *AssetContract")
+	assert.Contains(t, code, "func (c *AssetContract) CreateAsset(asset Asset, options ...channel.RequestOption) (txID fab.TransactionID, err error)")
+	assert.Contains(t, code, "func (c *AssetContract) ReadAsset(id string, options ...channel.RequestOption) (result *Asset, err error)")
+	assert.Contains(t, code, "func (c *AssetContract) GetAllAssets(options ...channel.RequestOption) (result []Asset, err error)")
+	assert.Contains(t, code, "func (c *AssetContract) RegisterAssetCreatedEvent() (fab.Registration, <-chan *AssetCreatedEvent, error)")
+	assert.Contains(t, code, "Timestamp int32  `json:\"Timestamp,omitempty\"`")
+	assert.NotContains(t, code, "GetMetadata", "system contract shouldn't be bound")
+
+	// The example package must be regenerated (go generate) when the generator changes
+	example, err := ioutil.ReadFile("example/assets.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(example), code, "example is out of date")
+}
+
+func TestGenerateErrors(t *testing.T) {
+	metadata := loadMetadata(t)
+	_, err := Generate(metadata, Options{})
+	assert.Error(t, err, "expecting error without package")
+	_, err = Generate(nil, Options{Package: "example"})
+	assert.Error(t, err, "expecting error without metadata")
+
+	invalid := []string{
+		`{"contracts": {"c": {"transactions": [{"name": "tx", "returns": {"$ref": "#/components/schemas/Missing"}}]}}}`,
+		`{"contracts": {"c": {"transactions": [{"name": "tx", "parameters": [{"name": "p", "schema": {"type": "date"}}]}]}}}`,
+		`{"contracts": {"c": {"transactions": [{"name": "tx"}, {"name": "Tx"}]}}}`,
+		`{"contracts": {"asset": {"transactions": []}}, "components": {"schemas": {"AssetContract": {"type": "object"}}}}`,
+	}
+	for _, data := range invalid {
+		metadata, err := ParseMetadata([]byte(data))
+		require.NoError(t, err)
+		_, err = Generate(metadata, Options{Package: "example"})
+		assert.Error(t, err, "expecting error for "+data)
+	}
+}
+
+func TestNames(t *testing.T) {
+	assert.Equal(t, "AssetID", goName("asset_id"))
+	assert.Equal(t, "NewOwner", goName("newOwner"))
+	assert.Equal(t, "X1st", goName("1st"))
+	assert.Equal(t, "OrgExampleAsset", goName("org.example.asset"))
+
+	assert.Equal(t, "id", paramName("ID"))
+	assert.Equal(t, "idValue", paramName("IDValue"))
+	assert.Equal(t, "newOwner", paramName("newOwner"))
+	assert.Equal(t, "typeArg", paramName("type"))
+	assert.Equal(t, "optionsArg", paramName("options"))
+}
+
+func loadMetadata(t *testing.T) *Metadata {
+	data, err := ioutil.ReadFile("testdata/metadata.json")
+	require.NoError(t, err)
+	metadata, err := ParseMetadata(data)
+	require.NoError(t, err)
+	return metadata
+}
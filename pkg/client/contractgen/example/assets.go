@@ -0,0 +1,156 @@
+// Code generated by contractgen. DO NOT EDIT.
+// Source: metadata.json
+
+package example
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/contractgen/binding"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+// Asset is an asset managed by the chaincode
+type Asset struct {
+	AppraisedValue float64    `json:"AppraisedValue,omitempty"`
+	Color          string     `json:"Color,omitempty"`
+	History        []Transfer `json:"History,omitempty"`
+	ID             string     `json:"ID"`
+	Owner          string     `json:"Owner"`
+	Size           int64      `json:"Size"`
+}
+
+// Transfer is the schema Transfer of the chaincode
+type Transfer struct {
+	From      string `json:"From"`
+	Timestamp int32  `json:"Timestamp,omitempty"`
+	To        string `json:"To"`
+}
+
+// AssetContract is the client of the contract AssetContract
+type AssetContract struct {
+	contract *binding.Contract
+}
+
+// NewAssetContract returns the client of the contract AssetContract of the given chaincode
+func NewAssetContract(client binding.ChannelClient, chaincodeID string) *AssetContract {
+	return &AssetContract{contract: binding.NewContract(client, chaincodeID, "AssetContract")}
+}
+
+// CreateAsset submits the transaction CreateAsset
+func (c *AssetContract) CreateAsset(asset Asset, options ...channel.RequestOption) (txID fab.TransactionID, err error) {
+	response, err := c.contract.Execute("CreateAsset", []interface{}{asset}, options...)
+	if err != nil {
+		return txID, err
+	}
+	txID = response.TransactionID
+	return txID, err
+}
+
+// ReadAsset evaluates the transaction ReadAsset
+func (c *AssetContract) ReadAsset(id string, options ...channel.RequestOption) (result *Asset, err error) {
+	response, err := c.contract.Query("ReadAsset", []interface{}{id}, options...)
+	if err != nil {
+		return result, err
+	}
+	err = binding.Unmarshal(response.Payload, &result)
+	return result, err
+}
+
+// TransferAsset submits the transaction TransferAsset
+func (c *AssetContract) TransferAsset(id string, newOwner string, options ...channel.RequestOption) (result string, txID fab.TransactionID, err error) {
+	response, err := c.contract.Execute("TransferAsset", []interface{}{id, newOwner}, options...)
+	if err != nil {
+		return result, txID, err
+	}
+	txID = response.TransactionID
+	err = binding.Unmarshal(response.Payload, &result)
+	return result, txID, err
+}
+
+// AssetExists evaluates the transaction AssetExists
+func (c *AssetContract) AssetExists(id string, options ...channel.RequestOption) (result bool, err error) {
+	response, err := c.contract.Query("AssetExists", []interface{}{id}, options...)
+	if err != nil {
+		return result, err
+	}
+	err = binding.Unmarshal(response.Payload, &result)
+	return result, err
+}
+
+// GetAllAssets evaluates the transaction GetAllAssets
+func (c *AssetContract) GetAllAssets(options ...channel.RequestOption) (result []Asset, err error) {
+	response, err := c.contract.Query("GetAllAssets", nil, options...)
+	if err != nil {
+		return result, err
+	}
+	err = binding.Unmarshal(response.Payload, &result)
+	return result, err
+}
+
+// DeleteAsset submits the transaction DeleteAsset
+func (c *AssetContract) DeleteAsset(id string, options ...channel.RequestOption) (txID fab.TransactionID, err error) {
+	response, err := c.contract.Execute("DeleteAsset", []interface{}{id}, options...)
+	if err != nil {
+		return txID, err
+	}
+	txID = response.TransactionID
+	return txID, err
+}
+
+// AssetCreatedEvent is the event AssetCreated of the contract AssetContract
+type AssetCreatedEvent struct {
+	*fab.CCEvent
+	Payload *Asset
+}
+
+// RegisterAssetCreatedEvent registers for the AssetCreated events of the contract. Events whose payload
+// can't be decoded are dropped. The event channel is closed once the registration is removed with Unregister.
+func (c *AssetContract) RegisterAssetCreatedEvent() (fab.Registration, <-chan *AssetCreatedEvent, error) {
+	reg, events, err := c.contract.RegisterEvent("AssetCreated")
+	if err != nil {
+		return nil, nil, err
+	}
+	eventch := make(chan *AssetCreatedEvent, cap(events))
+	go func() {
+		defer close(eventch)
+		for event := range events {
+			typed := &AssetCreatedEvent{CCEvent: event}
+			if err := binding.Unmarshal(event.Payload, &typed.Payload); err != nil {
+				binding.InvalidEvent(event, err)
+				continue
+			}
+			eventch <- typed
+		}
+	}()
+	return reg, eventch, nil
+}
+
+// AssetDeletedEvent is the event AssetDeleted of the contract AssetContract
+type AssetDeletedEvent struct {
+	*fab.CCEvent
+	Payload []byte
+}
+
+// RegisterAssetDeletedEvent registers for the AssetDeleted events of the contract. Events whose payload
+// can't be decoded are dropped. The event channel is closed once the registration is removed with Unregister.
+func (c *AssetContract) RegisterAssetDeletedEvent() (fab.Registration, <-chan *AssetDeletedEvent, error) {
+	reg, events, err := c.contract.RegisterEvent("AssetDeleted")
+	if err != nil {
+		return nil, nil, err
+	}
+	eventch := make(chan *AssetDeletedEvent, cap(events))
+	go func() {
+		defer close(eventch)
+		for event := range events {
+			typed := &AssetDeletedEvent{CCEvent: event}
+			typed.Payload = event.Payload
+			eventch <- typed
+		}
+	}()
+	return reg, eventch, nil
+}
+
+// Unregister removes the event registration and closes its event channel
+func (c *AssetContract) Unregister(reg fab.Registration) {
+	c.contract.Unregister(reg)
+}
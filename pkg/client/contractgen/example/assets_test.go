@@ -0,0 +1,80 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package example
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/test/mockchannel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+func TestAssetContract(t *testing.T) {
+	client := mockchannel.New()
+	client.OnExecute("assets", "AssetContract:CreateAsset").ReturnResponse(channel.Response{TransactionID: "txid1"})
+	client.OnQuery("assets", "AssetContract:ReadAsset").Return([]byte(`{"ID":"asset1","Owner":"alice","Size":10}`))
+	client.OnQuery("assets", "AssetContract:AssetExists").Return([]byte("true"))
+	client.OnExecute("assets", "AssetContract:TransferAsset").ReturnResponse(channel.Response{TransactionID: "txid2", Payload: []byte("alice")})
+
+	contract := NewAssetContract(client, "assets")
+
+	txID, err := contract.CreateAsset(Asset{ID: "asset1", Owner: "alice", Size: 10})
+	require.NoError(t, err)
+	assert.Equal(t, fab.TransactionID("txid1"), txID)
+
+	asset, err := contract.ReadAsset("asset1")
+	require.NoError(t, err)
+	assert.Equal(t, &Asset{ID: "asset1", Owner: "alice", Size: 10}, asset)
+
+	exists, err := contract.AssetExists("asset1")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	previousOwner, txID, err := contract.TransferAsset("asset1", "bob")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", previousOwner)
+	assert.Equal(t, fab.TransactionID("txid2"), txID)
+
+	calls := client.Calls()
+	require.Len(t, calls, 4)
+	assert.Equal(t, []byte(`{"ID":"asset1","Owner":"alice","Size":10}`), calls[0].Request.Args[0])
+	assert.Equal(t, [][]byte{[]byte("asset1"), []byte("bob")}, calls[3].Request.Args)
+}
+
+func TestAssetCreatedEvent(t *testing.T) {
+	client := mockchannel.New()
+	contract := NewAssetContract(client, "assets")
+
+	reg, eventch, err := contract.RegisterAssetCreatedEvent()
+	require.NoError(t, err)
+
+	client.PublishChaincodeEvent(&fab.CCEvent{ChaincodeID: "assets", EventName: "AssetCreated", TxID: "tx1", Payload: []byte("not json")})
+	client.PublishChaincodeEvent(&fab.CCEvent{ChaincodeID: "assets", EventName: "AssetDeleted", TxID: "tx2"})
+	client.PublishChaincodeEvent(&fab.CCEvent{ChaincodeID: "assets", EventName: "AssetCreated", TxID: "tx3", Payload: []byte(`{"ID":"asset1"}`)})
+
+	select {
+	case event := <-eventch:
+		assert.Equal(t, "tx3", event.TxID, "expecting invalid and other events to be dropped")
+		require.NotNil(t, event.Payload)
+		assert.Equal(t, "asset1", event.Payload.ID)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+
+	contract.Unregister(reg)
+	select {
+	case _, ok := <-eventch:
+		assert.False(t, ok, "expecting event channel to be closed")
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event channel to be closed")
+	}
+}
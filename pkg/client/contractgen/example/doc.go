@@ -0,0 +1,10 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package example is the client generated by contractgen for the sample asset chaincode (see testdata/metadata.json).
+package example
+
+//go:generate go run ../cmd/contractgen -metadata ../testdata/metadata.json -out assets.go
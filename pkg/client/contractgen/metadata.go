@@ -0,0 +1,133 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package contractgen
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// systemContract is the contract added by the contract API to every chaincode, which isn't bound
+const systemContract = "org.hyperledger.fabric"
+
+// schemaRefPrefix is the prefix of the references to the schemas of the components of the metadata
+const schemaRefPrefix = "#/components/schemas/"
+
+// Metadata is the transaction schema of a chaincode, as returned by the
+// org.hyperledger.fabric:GetMetadata transaction of the contract API
+type Metadata struct {
+	Contracts  map[string]Contract `json:"contracts"`
+	Components Components          `json:"components"`
+}
+
+// Components holds the schemas shared by the contracts of the chaincode
+type Components struct {
+	Schemas map[string]Schema `json:"schemas"`
+}
+
+// Contract is a contract of the chaincode
+type Contract struct {
+	Name         string        `json:"name"`
+	Transactions []Transaction `json:"transactions"`
+	// Events are the events emitted by the transactions of the contract. The contract API doesn't
+	// describe events, so they may be added to the metadata given to the generator.
+	Events []Event `json:"events,omitempty"`
+}
+
+// Transaction is a transaction of a contract
+type Transaction struct {
+	Name       string      `json:"name"`
+	Tag        []string    `json:"tag"`
+	Parameters []Parameter `json:"parameters"`
+	Returns    *Schema     `json:"returns"`
+}
+
+// Parameter is a parameter of a transaction
+type Parameter struct {
+	Name   string `json:"name"`
+	Schema Schema `json:"schema"`
+}
+
+// Event is a chaincode event whose payload is described by the schema
+type Event struct {
+	Name   string  `json:"name"`
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is the (JSON) schema of a value
+type Schema struct {
+	Type        string            `json:"type,omitempty"`
+	Format      string            `json:"format,omitempty"`
+	Ref         string            `json:"$ref,omitempty"`
+	Items       *Schema           `json:"items,omitempty"`
+	Properties  map[string]Schema `json:"properties,omitempty"`
+	Required    []string          `json:"required,omitempty"`
+	Description string            `json:"description,omitempty"`
+}
+
+// UnmarshalJSON accepts the return schema of a transaction both as a schema and, as written
+// by some versions of the contract API, as an object with the schema in its schema field
+func (t *Transaction) UnmarshalJSON(data []byte) error {
+	type transaction Transaction
+	var raw struct {
+		transaction
+		Returns json.RawMessage `json:"returns"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*t = Transaction(raw.transaction)
+	t.Returns = nil
+
+	if len(raw.Returns) == 0 || string(raw.Returns) == "null" {
+		return nil
+	}
+	var wrapped struct {
+		Schema *Schema `json:"schema"`
+	}
+	if err := json.Unmarshal(raw.Returns, &wrapped); err != nil {
+		return errors.Wrapf(err, "invalid return schema of transaction [%s]", t.Name)
+	}
+	if wrapped.Schema != nil {
+		t.Returns = wrapped.Schema
+		return nil
+	}
+	var schema Schema
+	if err := json.Unmarshal(raw.Returns, &schema); err != nil {
+		return errors.Wrapf(err, "invalid return schema of transaction [%s]", t.Name)
+	}
+	t.Returns = &schema
+	return nil
+}
+
+// IsSubmit returns true if the transaction is tagged as a transaction which is submitted to the orderer.
+// Transactions which are tagged neither as submitted nor as evaluated are submitted, as with the contract API.
+func (t *Transaction) IsSubmit() bool {
+	for _, tag := range t.Tag {
+		if strings.HasPrefix(strings.ToLower(tag), "evaluate") {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseMetadata parses the JSON metadata of a chaincode
+func ParseMetadata(data []byte) (*Metadata, error) {
+	metadata := &Metadata{}
+	if err := json.Unmarshal(data, metadata); err != nil {
+		return nil, errors.Wrap(err, "failed to parse chaincode metadata")
+	}
+	for name, contract := range metadata.Contracts {
+		if contract.Name == "" {
+			contract.Name = name
+			metadata.Contracts[name] = contract
+		}
+	}
+	return metadata, nil
+}
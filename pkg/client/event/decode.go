@@ -0,0 +1,109 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package event
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+// Decoder decodes the payload of a chaincode event into the value pointed to by v
+type Decoder func(payload []byte, v interface{}) error
+
+// JSONDecoder decodes JSON payloads
+func JSONDecoder(payload []byte, v interface{}) error {
+	return json.Unmarshal(payload, v)
+}
+
+// ProtoDecoder decodes protobuf payloads into values which implement proto.Message
+func ProtoDecoder(payload []byte, v interface{}) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return errors.Errorf("%T is not a proto message", v)
+	}
+	return proto.Unmarshal(payload, msg)
+}
+
+// DecodedCCEvent is a chaincode event with its decoded payload
+type DecodedCCEvent struct {
+	*fab.CCEvent
+	// Value is a pointer to the value of the target type decoded from the payload of the event
+	Value interface{}
+}
+
+// RegisterDecodedChaincodeEvent registers for chaincode events whose payloads are decoded into values of the type
+// of the given target, e.g. &Asset{}. The payloads are decoded with the decoder given with WithDecoder; by default
+// payloads are decoded as protobuf if the target is a proto message and as JSON otherwise. Events whose payload
+// can't be decoded are dropped and passed to the handler given with WithDecodeErrorHandler, if any. Note that the
+// events of filtered blocks have no payload, so the client must be created with WithBlockEvents.
+// Unregister must be called when the registration is no longer needed.
+//  Parameters:
+//  ccID is the chaincode ID for which events are to be received
+//  eventFilter is the chaincode event filter (regular expression) for which events are to be received
+//  target is a value (or a pointer to a value) of the type of the decoded payloads
+//
+//  Returns:
+//  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
+func (c *Client) RegisterDecodedChaincodeEvent(ccID, eventFilter string, target interface{}, options ...DecodeOption) (fab.Registration, <-chan *DecodedCCEvent, error) {
+	if target == nil {
+		return nil, nil, errors.New("target is nil")
+	}
+	targetType := reflect.TypeOf(target)
+	if targetType.Kind() == reflect.Ptr {
+		targetType = targetType.Elem()
+	}
+
+	opts := decodeOptions{}
+	for _, option := range options {
+		if err := option(&opts); err != nil {
+			return nil, nil, errors.WithMessage(err, "failed to read decode options")
+		}
+	}
+	if opts.decoder == nil {
+		opts.decoder = JSONDecoder
+		if reflect.PtrTo(targetType).Implements(reflect.TypeOf((*proto.Message)(nil)).Elem()) {
+			opts.decoder = ProtoDecoder
+		}
+	}
+
+	reg, events, err := c.RegisterChaincodeEvent(ccID, eventFilter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	freg := newForwarderRegistration(reg)
+	eventch := make(chan *DecodedCCEvent, cap(events))
+	go func() {
+		defer close(eventch)
+		for event := range events {
+			value := reflect.New(targetType).Interface()
+			if err := opts.decoder(event.Payload, value); err != nil {
+				err = errors.Wrapf(err, "failed to decode payload of event [%s] of transaction [%s]", event.EventName, event.TxID)
+				if opts.errorHandler != nil {
+					opts.errorHandler(event, err)
+				} else {
+					logger.Warnf("Dropping chaincode event: %s", err)
+				}
+				continue
+			}
+			select {
+			case eventch <- &DecodedCCEvent{CCEvent: event, Value: value}:
+			case <-freg.done:
+				return
+			}
+		}
+	}()
+	return freg, eventch, nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+type asset struct {
+	ID    string `json:"id"`
+	Owner string `json:"owner"`
+}
+
+func TestDecodedChaincodeEvents(t *testing.T) {
+	eventService := &ccEventService{eventch: make(chan *fab.CCEvent, 10)}
+	client := &Client{eventService: eventService}
+
+	var failed []*fab.CCEvent
+	reg, eventch, err := client.RegisterDecodedChaincodeEvent("mycc", "transferred", asset{}, WithDecodeErrorHandler(func(event *fab.CCEvent, err error) {
+		failed = append(failed, event)
+	}))
+	require.NoError(t, err)
+
+	eventService.eventch <- &fab.CCEvent{TxID: "txid1", EventName: "transferred", Payload: []byte("not json")}
+	eventService.eventch <- &fab.CCEvent{TxID: "txid2", EventName: "transferred", Payload: []byte(`{"id":"asset1","owner":"bob"}`)}
+
+	event := receiveDecodedEvent(t, eventch)
+	assert.Equal(t, "txid2", event.TxID)
+	assert.Equal(t, &asset{ID: "asset1", Owner: "bob"}, event.Value)
+	require.Len(t, failed, 1, "expecting decode failure to be passed to the error handler")
+	assert.Equal(t, "txid1", failed[0].TxID)
+
+	client.Unregister(reg)
+	_, ok := <-eventch
+	assert.False(t, ok, "expecting event channel to be closed")
+}
+
+func TestDecodedChaincodeEventsProto(t *testing.T) {
+	eventService := &ccEventService{eventch: make(chan *fab.CCEvent, 10)}
+	client := &Client{eventService: eventService}
+
+	reg, eventch, err := client.RegisterDecodedChaincodeEvent("mycc", "deployed", &pb.ChaincodeID{})
+	require.NoError(t, err)
+	defer client.Unregister(reg)
+
+	payload, err := proto.Marshal(&pb.ChaincodeID{Name: "othercc", Version: "v1"})
+	require.NoError(t, err)
+	eventService.eventch <- &fab.CCEvent{TxID: "txid1", EventName: "deployed", Payload: payload}
+
+	event := receiveDecodedEvent(t, eventch)
+	ccID, ok := event.Value.(*pb.ChaincodeID)
+	require.True(t, ok, "expecting payload to be decoded as proto by default")
+	assert.Equal(t, "othercc", ccID.Name)
+	assert.Equal(t, "v1", ccID.Version)
+}
+
+func TestDecodedChaincodeEventsUnregisterWithoutReading(t *testing.T) {
+	eventService := &ccEventService{eventch: make(chan *fab.CCEvent, 10)}
+	client := &Client{eventService: eventService}
+
+	reg, eventch, err := client.RegisterDecodedChaincodeEvent("mycc", "transferred", asset{})
+	require.NoError(t, err)
+
+	// Fill the event channel, so that the forwarder is blocked
+	for i := 0; i < cap(eventch)+2; i++ {
+		eventService.eventch <- &fab.CCEvent{TxID: "txid", EventName: "transferred", Payload: []byte(`{"id":"asset1"}`)}
+	}
+	for len(eventch) < cap(eventch) || len(eventService.eventch) > 1 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	client.Unregister(reg)
+	for i := 0; i < cap(eventch); i++ {
+		receiveDecodedEvent(t, eventch)
+	}
+	select {
+	case _, ok := <-eventch:
+		assert.False(t, ok, "expecting forwarder to stop and close the event channel")
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for event channel to be closed")
+	}
+}
+
+func TestDecodeOptions(t *testing.T) {
+	client := &Client{eventService: &ccEventService{eventch: make(chan *fab.CCEvent)}}
+
+	_, _, err := client.RegisterDecodedChaincodeEvent("mycc", "transferred", nil)
+	assert.Error(t, err, "expecting error without target")
+	_, _, err = client.RegisterDecodedChaincodeEvent("mycc", "transferred", asset{}, WithDecoder(nil))
+	assert.Error(t, err, "expecting error without decoder")
+
+	assert.Error(t, ProtoDecoder([]byte{}, &asset{}), "expecting error for target which isn't a proto message")
+}
+
+func receiveDecodedEvent(t *testing.T, eventch <-chan *DecodedCCEvent) *DecodedCCEvent {
+	select {
+	case event, ok := <-eventch:
+		require.True(t, ok, "unexpected closed channel")
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for chaincode event")
+	}
+	return nil
+}
+
+// ccEventService delivers the chaincode events sent to its event channel
+type ccEventService struct {
+	fab.EventService
+	eventch chan *fab.CCEvent
+}
+
+func (s *ccEventService) RegisterChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
+	return ccID, s.eventch, nil
+}
+
+func (s *ccEventService) Unregister(reg fab.Registration) {
+	close(s.eventch)
+}
@@ -15,6 +15,9 @@ SPDX-License-Identifier: Apache-2.0
 package event
 
 import (
+	"sync"
+	"time"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client"
@@ -25,6 +28,8 @@ import (
 type Client struct {
 	eventService      fab.EventService
 	permitBlockEvents bool
+	ctx               context.Client
+	requestOptions    []RequestOption
 }
 
 // New returns a Client instance. Client receives events such as block, filtered block,
@@ -61,6 +66,7 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 	}
 
 	eventClient.eventService = es
+	eventClient.ctx = channelContext
 
 	return &eventClient, nil
 }
@@ -68,19 +74,39 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 // RegisterBlockEvent registers for block events. If the caller does not have permission
 // to register for block events then an error is returned. Unregister must be called when the registration is no longer needed.
 //  Parameters:
-//  filter is an optional filter that filters out unwanted events. (Note: Only one filter may be specified.)
+//  filter is an optional filter that filters out unwanted events, e.g. headertypefilter.New(cb.HeaderType_CONFIG)
+//  for the blocks with config transactions or namespacefilter.New("mycc") for the blocks with transactions of
+//  chaincode mycc (see RegisterTxEvent to receive the matching transactions only). (Note: Only one filter may be specified.)
 //
 //  Returns:
 //  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
 func (c *Client) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
-	return c.eventService.RegisterBlockEvent(filter...)
+	var eventch <-chan *fab.BlockEvent
+	reg, err := c.register(func() (fab.Registration, error) {
+		reg, ch, err := c.eventService.RegisterBlockEvent(filter...)
+		eventch = ch
+		return reg, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return reg, eventch, nil
 }
 
 // RegisterFilteredBlockEvent registers for filtered block events. Unregister must be called when the registration is no longer needed.
 //  Returns:
 //  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
 func (c *Client) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
-	return c.eventService.RegisterFilteredBlockEvent()
+	var eventch <-chan *fab.FilteredBlockEvent
+	reg, err := c.register(func() (fab.Registration, error) {
+		reg, ch, err := c.eventService.RegisterFilteredBlockEvent()
+		eventch = ch
+		return reg, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return reg, eventch, nil
 }
 
 // RegisterChaincodeEvent registers for chaincode events. Unregister must be called when the registration is no longer needed.
@@ -91,7 +117,16 @@ func (c *Client) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.Fil
 //  Returns:
 //  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
 func (c *Client) RegisterChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
-	return c.eventService.RegisterChaincodeEvent(ccID, eventFilter)
+	var eventch <-chan *fab.CCEvent
+	reg, err := c.register(func() (fab.Registration, error) {
+		reg, ch, err := c.eventService.RegisterChaincodeEvent(ccID, eventFilter)
+		eventch = ch
+		return reg, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return reg, eventch, nil
 }
 
 // RegisterTxStatusEvent registers for transaction status events. Unregister must be called when the registration is no longer needed.
@@ -101,12 +136,106 @@ func (c *Client) RegisterChaincodeEvent(ccID, eventFilter string) (fab.Registrat
 //  Returns:
 //  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
 func (c *Client) RegisterTxStatusEvent(txID string) (fab.Registration, <-chan *fab.TxStatusEvent, error) {
-	return c.eventService.RegisterTxStatusEvent(txID)
+	var eventch <-chan *fab.TxStatusEvent
+	reg, err := c.register(func() (fab.Registration, error) {
+		reg, ch, err := c.eventService.RegisterTxStatusEvent(txID)
+		eventch = ch
+		return reg, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return reg, eventch, nil
 }
 
 // Unregister removes the given registration and closes the event channel.
 //  Parameters:
 //  reg is the registration handle that was returned from one of the Register functions
 func (c *Client) Unregister(reg fab.Registration) {
+	if fr, ok := reg.(*forwarderRegistration); ok {
+		fr.stop()
+		reg = fr.Registration
+	}
 	c.eventService.Unregister(reg)
 }
+
+// forwarderRegistration is the registration of a goroutine which forwards the converted events of an underlying
+// registration. The forwarder stops when the registration is removed, even if the consumer stopped reading.
+type forwarderRegistration struct {
+	fab.Registration
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func newForwarderRegistration(reg fab.Registration) *forwarderRegistration {
+	return &forwarderRegistration{Registration: reg, done: make(chan struct{})}
+}
+
+func (r *forwarderRegistration) stop() {
+	r.stopOnce.Do(func() {
+		close(r.done)
+	})
+}
+
+// WithRequestOptions returns a client which shares the event service of this client and applies the given
+// request options to its registrations, e.g. to override the registration timeout of one call:
+//  reg, eventch, err := client.WithRequestOptions(event.WithCallTimeout(map[fab.TimeoutType]time.Duration{fab.EventReg: 2 * time.Second})).RegisterTxStatusEvent(txID)
+func (c *Client) WithRequestOptions(options ...RequestOption) *Client {
+	clone := *c
+	clone.requestOptions = append(append([]RequestOption{}, c.requestOptions...), options...)
+	return &clone
+}
+
+// register calls the given function, which registers for events, and waits for the registration for at
+// most the registration timeout (fab.EventReg). A registration which completes after the timeout is removed.
+func (c *Client) register(register func() (fab.Registration, error)) (fab.Registration, error) {
+	opts, err := c.prepareRequestOpts()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get opts for event registration")
+	}
+
+	timeout := opts.Timeouts[fab.EventReg]
+	if timeout <= 0 {
+		return register()
+	}
+
+	type result struct {
+		reg fab.Registration
+		err error
+	}
+	resultch := make(chan result, 1)
+	go func() {
+		reg, err := register()
+		resultch <- result{reg: reg, err: err}
+	}()
+
+	select {
+	case r := <-resultch:
+		return r.reg, r.err
+	case <-time.After(timeout):
+		go func() {
+			if r := <-resultch; r.err == nil {
+				c.eventService.Unregister(r.reg)
+			}
+		}()
+		return nil, errors.Errorf("timed out after %s waiting for event registration", timeout)
+	}
+}
+
+// prepareRequestOpts reads the request options of the client and sets the default timeouts
+func (c *Client) prepareRequestOpts() (requestOptions, error) {
+	opts := requestOptions{}
+	for _, option := range c.requestOptions {
+		if err := option(c.ctx, &opts); err != nil {
+			return opts, errors.WithMessage(err, "failed to read opts")
+		}
+	}
+
+	if opts.Timeouts == nil {
+		opts.Timeouts = make(map[fab.TimeoutType]time.Duration)
+	}
+	if opts.Timeouts[fab.EventReg] == 0 && c.ctx != nil {
+		opts.Timeouts[fab.EventReg] = c.ctx.EndpointConfig().Timeout(fab.EventReg)
+	}
+	return opts, nil
+}
@@ -447,3 +447,50 @@ func newServiceWithMockProducer(opts []options.Opt, pOpts ...producerOpt) (*serv
 
 	return serv, eventProducer, nil
 }
+
+func TestRegistrationTimeout(t *testing.T) {
+	ctx := createChannelContext(setupCustomTestContext(t, nil), channelID)
+
+	client, err := New(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create new event client: %s", err)
+	}
+
+	eventService := &blockingEventService{release: make(chan struct{}), unregistered: make(chan fab.Registration, 1)}
+	client.eventService = eventService
+
+	timeoutClient := client.WithRequestOptions(WithCallTimeout(map[fab.TimeoutType]time.Duration{fab.EventReg: 50 * time.Millisecond}))
+	if _, _, err = timeoutClient.RegisterTxStatusEvent("1234"); err == nil {
+		t.Fatalf("expecting registration timeout error but got none")
+	}
+	assert.Empty(t, client.requestOptions, "request options shouldn't be added to the original client")
+
+	close(eventService.release)
+
+	select {
+	case reg := <-eventService.unregistered:
+		assert.NotNil(t, reg, "late registration should be unregistered")
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for late registration to be unregistered")
+	}
+
+	if _, _, err = timeoutClient.RegisterTxStatusEvent("5678"); err != nil {
+		t.Fatalf("error registering for TxStatus events: %s", err)
+	}
+}
+
+// blockingEventService blocks registrations until it's released
+type blockingEventService struct {
+	fab.EventService
+	release      chan struct{}
+	unregistered chan fab.Registration
+}
+
+func (s *blockingEventService) RegisterTxStatusEvent(txID string) (fab.Registration, <-chan *fab.TxStatusEvent, error) {
+	<-s.release
+	return txID, make(chan *fab.TxStatusEvent), nil
+}
+
+func (s *blockingEventService) Unregister(reg fab.Registration) {
+	s.unregistered <- reg
+}
@@ -6,6 +6,16 @@ SPDX-License-Identifier: Apache-2.0
 
 package event
 
+import (
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
 // ClientOption describes a functional parameter for the New constructor
 type ClientOption func(*Client) error
 
@@ -17,3 +27,94 @@ func WithBlockEvents() ClientOption {
 		return nil
 	}
 }
+
+// RequestOption describes a functional parameter of the registrations of a client returned by
+// Client.WithRequestOptions
+type RequestOption func(ctx context.Client, opts *requestOptions) error
+
+type requestOptions struct {
+	Timeouts map[fab.TimeoutType]time.Duration //timeout options for event registrations
+}
+
+//WithTimeout encapsulates key value pairs of timeout type, timeout duration to Options
+//if not provided, default timeout configuration from config will be used
+func WithTimeout(timeoutType fab.TimeoutType, timeout time.Duration) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		if o.Timeouts == nil {
+			o.Timeouts = make(map[fab.TimeoutType]time.Duration)
+		}
+		o.Timeouts[timeoutType] = timeout
+		return nil
+	}
+}
+
+// WithCallTimeout overrides the configured timeouts of the given types for the registrations of the
+// client only, e.g. WithCallTimeout(map[fab.TimeoutType]time.Duration{fab.EventReg: 2 * time.Second}).
+// Timeouts which aren't given are taken from the configuration.
+func WithCallTimeout(timeouts map[fab.TimeoutType]time.Duration) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		o.Timeouts = contextImpl.OverrideTimeouts(o.Timeouts, timeouts)
+		return nil
+	}
+}
+
+// DecodeOption describes a functional parameter of RegisterDecodedChaincodeEvent
+type DecodeOption func(opts *decodeOptions) error
+
+type decodeOptions struct {
+	decoder      Decoder
+	errorHandler func(event *fab.CCEvent, err error)
+}
+
+// WithDecoder sets the decoder of the payloads of the chaincode events, e.g. JSONDecoder or ProtoDecoder
+func WithDecoder(decoder Decoder) DecodeOption {
+	return func(opts *decodeOptions) error {
+		if decoder == nil {
+			return errors.New("decoder is nil")
+		}
+		opts.decoder = decoder
+		return nil
+	}
+}
+
+// WithDecodeErrorHandler sets the handler which is called with the chaincode events whose payload can't be
+// decoded, instead of logging them. The handler is called from the goroutine which delivers the events,
+// so it must not block.
+func WithDecodeErrorHandler(handler func(event *fab.CCEvent, err error)) DecodeOption {
+	return func(opts *decodeOptions) error {
+		opts.errorHandler = handler
+		return nil
+	}
+}
+
+// TxEventOption describes a filter of the transactions received with RegisterTxEvent
+type TxEventOption func(opts *txEventOptions) error
+
+type txEventOptions struct {
+	headerTypes []cb.HeaderType
+	namespaces  []string
+}
+
+// WithHeaderTypes filters the transactions by type, e.g. WithHeaderTypes(cb.HeaderType_CONFIG) for the
+// config transactions of the channel
+func WithHeaderTypes(headerTypes ...cb.HeaderType) TxEventOption {
+	return func(opts *txEventOptions) error {
+		if len(headerTypes) == 0 {
+			return errors.New("must provide header types")
+		}
+		opts.headerTypes = append(opts.headerTypes, headerTypes...)
+		return nil
+	}
+}
+
+// WithNamespaces filters the transactions by namespace: only the endorser transactions which invoke
+// one of the given chaincodes are received. Requires block events (see WithBlockEvents).
+func WithNamespaces(namespaces ...string) TxEventOption {
+	return func(opts *txEventOptions) error {
+		if len(namespaces) == 0 {
+			return errors.New("must provide namespaces")
+		}
+		opts.namespaces = append(opts.namespaces, namespaces...)
+		return nil
+	}
+}
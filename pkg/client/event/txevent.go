@@ -0,0 +1,212 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package event
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/blockfilter/headertypefilter"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/blockfilter/namespacefilter"
+	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// TxEvent contains the data of a committed transaction
+type TxEvent struct {
+	// TxID is the ID of the transaction
+	TxID string
+	// HeaderType is the type of the transaction, e.g. ENDORSER_TRANSACTION or CONFIG
+	HeaderType cb.HeaderType
+	// TxValidationCode is the status code of the commit
+	TxValidationCode pb.TxValidationCode
+	// BlockNumber contains the block number in which the transaction was committed
+	BlockNumber uint64
+	// Namespaces are the chaincodes invoked by an endorser transaction
+	// NOTE: Namespaces and Envelope will be nil unless the client receives block events
+	Namespaces []string
+	// Envelope is the transaction envelope
+	Envelope *cb.Envelope
+	// SourceURL specifies the URL of the peer that produced the event
+	SourceURL string
+}
+
+// RegisterTxEvent registers for the committed transactions of the types and namespaces given with WithHeaderTypes
+// and WithNamespaces, e.g. for the config transactions of the channel only. Blocks without such transactions aren't
+// delivered to the registration. Transactions are taken from block events if the client was created with
+// WithBlockEvents and from filtered block events otherwise, in which case they can't be filtered by namespace.
+// Unregister must be called when the registration is no longer needed.
+//  Parameters:
+//  options are the filters of the transactions. All the transactions are received if no filter is given.
+//
+//  Returns:
+//  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
+func (c *Client) RegisterTxEvent(options ...TxEventOption) (fab.Registration, <-chan *TxEvent, error) {
+	opts := txEventOptions{}
+	for _, option := range options {
+		if err := option(&opts); err != nil {
+			return nil, nil, errors.WithMessage(err, "failed to read transaction filter options")
+		}
+	}
+
+	if c.permitBlockEvents {
+		return c.registerBlockTxEvents(opts)
+	}
+	if len(opts.namespaces) > 0 {
+		return nil, nil, errors.New("transactions can only be filtered by namespace with block events (see WithBlockEvents)")
+	}
+	return c.registerFilteredBlockTxEvents(opts)
+}
+
+func (c *Client) registerBlockTxEvents(opts txEventOptions) (fab.Registration, <-chan *TxEvent, error) {
+	var filters []fab.BlockFilter
+	if len(opts.headerTypes) > 0 {
+		filters = append(filters, headertypefilter.New(opts.headerTypes...))
+	}
+	if len(opts.namespaces) > 0 {
+		filters = append(filters, namespacefilter.New(opts.namespaces...))
+	}
+
+	var blockFilter []fab.BlockFilter
+	if len(filters) > 0 {
+		blockFilter = append(blockFilter, func(block *cb.Block) bool {
+			for _, filter := range filters {
+				if !filter(block) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+
+	reg, blocks, err := c.RegisterBlockEvent(blockFilter...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	freg := newForwarderRegistration(reg)
+	eventch := make(chan *TxEvent, cap(blocks))
+	go func() {
+		defer close(eventch)
+		for event := range blocks {
+			for _, txEvent := range blockTxEvents(event) {
+				if opts.accept(txEvent) && !forwardTxEvent(freg, eventch, txEvent) {
+					return
+				}
+			}
+		}
+	}()
+	return freg, eventch, nil
+}
+
+func (c *Client) registerFilteredBlockTxEvents(opts txEventOptions) (fab.Registration, <-chan *TxEvent, error) {
+	reg, blocks, err := c.RegisterFilteredBlockEvent()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	freg := newForwarderRegistration(reg)
+	eventch := make(chan *TxEvent, cap(blocks))
+	go func() {
+		defer close(eventch)
+		for event := range blocks {
+			for _, tx := range event.FilteredBlock.FilteredTransactions {
+				txEvent := &TxEvent{
+					TxID:             tx.Txid,
+					HeaderType:       tx.Type,
+					TxValidationCode: tx.TxValidationCode,
+					BlockNumber:      event.FilteredBlock.Number,
+					SourceURL:        event.SourceURL,
+				}
+				if opts.accept(txEvent) && !forwardTxEvent(freg, eventch, txEvent) {
+					return
+				}
+			}
+		}
+	}()
+	return freg, eventch, nil
+}
+
+// forwardTxEvent sends the event to the channel unless the registration is removed first, in which case false is returned
+func forwardTxEvent(reg *forwarderRegistration, eventch chan<- *TxEvent, txEvent *TxEvent) bool {
+	select {
+	case eventch <- txEvent:
+		return true
+	case <-reg.done:
+		return false
+	}
+}
+
+// blockTxEvents returns the transactions of the block. Transactions which can't be read are skipped.
+func blockTxEvents(event *fab.BlockEvent) []*TxEvent {
+	block := event.Block
+
+	var txFilter ledgerutil.TxValidationFlags
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txFilter = ledgerutil.TxValidationFlags(block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+
+	var txEvents []*TxEvent
+	for i := range block.Data.Data {
+		env, err := utils.ExtractEnvelope(block, i)
+		if err != nil {
+			logger.Warnf("Skipping transaction %d of block %d: error extracting envelope: %s", i, block.Header.Number, err)
+			continue
+		}
+		payload, err := utils.ExtractPayload(env)
+		if err != nil {
+			logger.Warnf("Skipping transaction %d of block %d: error extracting payload: %s", i, block.Header.Number, err)
+			continue
+		}
+		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			logger.Warnf("Skipping transaction %d of block %d: error extracting channel header: %s", i, block.Header.Number, err)
+			continue
+		}
+
+		txEvent := &TxEvent{
+			TxID:        chdr.TxId,
+			HeaderType:  cb.HeaderType(chdr.Type),
+			BlockNumber: block.Header.Number,
+			Envelope:    env,
+			SourceURL:   event.SourceURL,
+		}
+		if i < len(txFilter) {
+			txEvent.TxValidationCode = txFilter.Flag(i)
+		}
+		if txEvent.HeaderType == cb.HeaderType_ENDORSER_TRANSACTION {
+			txEvent.Namespaces, err = namespacefilter.Namespaces(payload)
+			if err != nil {
+				logger.Warnf("Error extracting namespaces of transaction [%s]: %s", chdr.TxId, err)
+			}
+		}
+		txEvents = append(txEvents, txEvent)
+	}
+	return txEvents
+}
+
+// accept returns true if the transaction passes the filters
+func (opts *txEventOptions) accept(txEvent *TxEvent) bool {
+	if len(opts.headerTypes) > 0 && !hasHeaderType(txEvent.HeaderType, opts.headerTypes) {
+		return false
+	}
+	if len(opts.namespaces) > 0 && !namespacefilter.Contains(txEvent.Namespaces, opts.namespaces...) {
+		return false
+	}
+	return true
+}
+
+func hasHeaderType(headerType cb.HeaderType, headerTypes []cb.HeaderType) bool {
+	for _, t := range headerTypes {
+		if t == headerType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	servicemocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/mocks"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+func TestTxEventsByHeaderType(t *testing.T) {
+	eventService, eventProducer, err := newServiceWithMockProducer(defaultOpts, withBlockLedger(sourceURL))
+	require.NoError(t, err)
+	defer eventProducer.Close()
+	defer eventService.Stop()
+
+	client, err := New(createChannelContext(setupCustomTestContext(t, nil), channelID))
+	require.NoError(t, err)
+	client.eventService = eventService
+	client.permitBlockEvents = true
+
+	reg, eventch, err := client.RegisterTxEvent(WithHeaderTypes(cb.HeaderType_CONFIG))
+	require.NoError(t, err)
+	defer client.Unregister(reg)
+
+	eventProducer.Ledger().NewBlock(channelID, servicemocks.NewTransactionWithCCEvent("txid1", pb.TxValidationCode_VALID, "mycc1", "event1", nil))
+	eventProducer.Ledger().NewBlock(channelID,
+		servicemocks.NewTransactionWithCCEvent("txid2", pb.TxValidationCode_VALID, "mycc1", "event1", nil),
+		servicemocks.NewTransaction("txid3", pb.TxValidationCode_VALID, cb.HeaderType_CONFIG),
+	)
+
+	event := receiveTxEvent(t, eventch)
+	assert.Equal(t, "txid3", event.TxID, "expecting endorser transactions to be filtered out")
+	assert.Equal(t, cb.HeaderType_CONFIG, event.HeaderType)
+	assert.NotNil(t, event.Envelope)
+}
+
+func TestTxEventsByNamespace(t *testing.T) {
+	eventService, eventProducer, err := newServiceWithMockProducer(defaultOpts, withBlockLedger(sourceURL))
+	require.NoError(t, err)
+	defer eventProducer.Close()
+	defer eventService.Stop()
+
+	client, err := New(createChannelContext(setupCustomTestContext(t, nil), channelID))
+	require.NoError(t, err)
+	client.eventService = eventService
+
+	_, _, err = client.RegisterTxEvent(WithNamespaces("mycc2"))
+	assert.Error(t, err, "expecting error for namespace filter without block events")
+
+	client.permitBlockEvents = true
+	reg, eventch, err := client.RegisterTxEvent(WithNamespaces("mycc2"))
+	require.NoError(t, err)
+	defer client.Unregister(reg)
+
+	eventProducer.Ledger().NewBlock(channelID,
+		servicemocks.NewTransactionWithCCEvent("txid1", pb.TxValidationCode_VALID, "mycc1", "event1", nil),
+		servicemocks.NewTransactionWithCCEvent("txid2", pb.TxValidationCode_MVCC_READ_CONFLICT, "mycc2", "event2", nil),
+	)
+
+	event := receiveTxEvent(t, eventch)
+	assert.Equal(t, "txid2", event.TxID, "expecting transactions of other chaincodes to be filtered out")
+	assert.Equal(t, []string{"mycc2"}, event.Namespaces)
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, event.TxValidationCode)
+}
+
+func TestTxEventsFromFilteredBlocks(t *testing.T) {
+	eventService, eventProducer, err := newServiceWithMockProducer(defaultOpts, withFilteredBlockLedger(sourceURL))
+	require.NoError(t, err)
+	defer eventProducer.Close()
+	defer eventService.Stop()
+
+	client, err := New(createChannelContext(setupCustomTestContext(t, nil), channelID))
+	require.NoError(t, err)
+	client.eventService = eventService
+
+	_, _, err = client.RegisterTxEvent(WithHeaderTypes())
+	assert.Error(t, err, "expecting error without header types")
+
+	reg, eventch, err := client.RegisterTxEvent(WithHeaderTypes(cb.HeaderType_ENDORSER_TRANSACTION))
+	require.NoError(t, err)
+	defer client.Unregister(reg)
+
+	configTx := servicemocks.NewFilteredTx("txid1", pb.TxValidationCode_VALID)
+	configTx.Type = cb.HeaderType_CONFIG
+	endorserTx := servicemocks.NewFilteredTx("txid2", pb.TxValidationCode_VALID)
+	endorserTx.Type = cb.HeaderType_ENDORSER_TRANSACTION
+	eventProducer.Ledger().NewFilteredBlock(channelID, configTx, endorserTx)
+
+	event := receiveTxEvent(t, eventch)
+	assert.Equal(t, "txid2", event.TxID, "expecting config transactions to be filtered out")
+	assert.Nil(t, event.Envelope)
+}
+
+func TestTxEventsUnregisterWithoutReading(t *testing.T) {
+	eventService := &filteredBlockEventService{eventch: make(chan *fab.FilteredBlockEvent, 10)}
+	client := &Client{eventService: eventService}
+
+	reg, eventch, err := client.RegisterTxEvent()
+	require.NoError(t, err)
+
+	// Fill the event channel, so that the forwarder is blocked
+	for i := 0; i < cap(eventch)+2; i++ {
+		eventService.eventch <- &fab.FilteredBlockEvent{FilteredBlock: &pb.FilteredBlock{
+			ChannelId:            channelID,
+			FilteredTransactions: []*pb.FilteredTransaction{servicemocks.NewFilteredTx("txid", pb.TxValidationCode_VALID)},
+		}}
+	}
+	for len(eventch) < cap(eventch) || len(eventService.eventch) > 1 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	client.Unregister(reg)
+	for i := 0; i < cap(eventch); i++ {
+		receiveTxEvent(t, eventch)
+	}
+	select {
+	case _, ok := <-eventch:
+		assert.False(t, ok, "expecting forwarder to stop and close the event channel")
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for event channel to be closed")
+	}
+}
+
+func receiveTxEvent(t *testing.T, eventch <-chan *TxEvent) *TxEvent {
+	select {
+	case event, ok := <-eventch:
+		require.True(t, ok, "unexpected closed channel")
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for transaction event")
+	}
+	return nil
+}
+
+type filteredBlockEventService struct {
+	fab.EventService
+	eventch chan *fab.FilteredBlockEvent
+}
+
+func (s *filteredBlockEventService) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
+	return "filteredblock", s.eventch, nil
+}
+
+func (s *filteredBlockEventService) Unregister(reg fab.Registration) {
+	close(s.eventch)
+}
@@ -0,0 +1,424 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package explorer maintains the indexes which back explorer-style UIs: transactions by ID,
+// blocks by number, chaincode events by name and keys by namespace. The indexes are kept in a
+// pluggable key-value store (see keyvaluestore) and are fed by an off-chain synchronizer, which
+// takes care of checkpointing, replays and gaps in the block events (see offchain).
+//  Basic Flow:
+//  1) Create the key-value store and the indexer
+//  2) Create and start an off-chain synchronizer with the indexer as sink
+//  3) Query the indexes
+//
+//  store, err := keyvaluestore.NewBoltDB(&keyvaluestore.BoltDBKeyValueStoreOptions{Path: "/var/explorer/mychannel.db"})
+//  ...
+//  indexer, err := explorer.NewIndexer(store)
+//  ...
+//  synchronizer, err := offchain.New(channelProvider, indexer)
+//  ...
+//  err = synchronizer.Start()
+//  ...
+//  tx, err := indexer.Transaction(txID)
+package explorer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/offchain"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/keyvaluestore"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+// ErrNotFound is returned when the requested block or transaction isn't indexed
+var ErrNotFound = errors.New("not found")
+
+const checkpointKey = "checkpoint"
+
+// BlockRecord is the index entry of a block
+type BlockRecord struct {
+	Number       uint64   `json:"number"`
+	DataHash     []byte   `json:"dataHash"`
+	PreviousHash []byte   `json:"previousHash"`
+	TxIDs        []string `json:"txIds"`
+}
+
+// TxRecord is the index entry of a transaction
+type TxRecord struct {
+	TxID           string              `json:"txId"`
+	BlockNumber    uint64              `json:"blockNumber"`
+	TxNum          uint64              `json:"txNum"`
+	Timestamp      time.Time           `json:"timestamp"`
+	ChaincodeID    string              `json:"chaincodeId"`
+	ValidationCode pb.TxValidationCode `json:"validationCode"`
+	Writes         []*KeyRecord        `json:"writes,omitempty"`
+	EventNames     []string            `json:"eventNames,omitempty"`
+}
+
+// EventRecord is the index entry of a chaincode event
+type EventRecord struct {
+	TxID        string `json:"txId"`
+	BlockNumber uint64 `json:"blockNumber"`
+	ChaincodeID string `json:"chaincodeId"`
+	EventName   string `json:"eventName"`
+	Payload     []byte `json:"payload,omitempty"`
+}
+
+// KeyRecord is the index entry of a key: the transaction which last wrote it
+type KeyRecord struct {
+	Namespace   string `json:"namespace"`
+	Collection  string `json:"collection,omitempty"`
+	Key         string `json:"key"`
+	IsDelete    bool   `json:"isDelete,omitempty"`
+	TxID        string `json:"txId,omitempty"`
+	BlockNumber uint64 `json:"blockNumber,omitempty"`
+}
+
+// Indexer indexes the blocks applied by an off-chain synchronizer. It implements offchain.Sink
+// and offchain.Checkpointer, so the checkpoint is stored along with the indexes (atomically if
+// the store implements keyvaluestore.BatchKVStore).
+type Indexer struct {
+	store core.KVStore
+	lock  sync.RWMutex
+}
+
+// NewIndexer returns an indexer which keeps its indexes in the given store. Several indexers
+// (e.g. one per channel) may share a store with keyvaluestore.NewNamespaced.
+func NewIndexer(store core.KVStore) (*Indexer, error) {
+	if store == nil {
+		return nil, errors.New("key-value store is required")
+	}
+	return &Indexer{store: store}, nil
+}
+
+// Apply indexes the block. Blocks which aren't after the checkpoint are ignored.
+func (i *Indexer) Apply(block *offchain.Block) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	lastBlock, ok, err := i.lastBlock()
+	if err != nil {
+		return err
+	}
+	if ok && block.Number <= lastBlock {
+		logger.Debugf("Ignoring block %d which isn't after checkpoint %d", block.Number, lastBlock)
+		return nil
+	}
+
+	b := &batch{indexer: i, values: make(map[string]interface{})}
+
+	blockRecord := &BlockRecord{Number: block.Number, DataHash: block.DataHash, PreviousHash: block.PreviousHash}
+	for _, tx := range block.Transactions {
+		blockRecord.TxIDs = append(blockRecord.TxIDs, tx.TxID)
+		if err := b.addTransaction(block.Number, tx); err != nil {
+			return err
+		}
+	}
+	b.put(blockKey(block.Number), blockRecord)
+	b.put(checkpointKey, strconv.FormatUint(block.Number, 10))
+
+	return b.store()
+}
+
+// LastBlock returns the number of the last block which was indexed, or false if none was indexed
+func (i *Indexer) LastBlock() (uint64, bool, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	return i.lastBlock()
+}
+
+// SetLastBlock records the number of the last block which was indexed
+func (i *Indexer) SetLastBlock(blockNum uint64) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	return i.storeJSON(checkpointKey, strconv.FormatUint(blockNum, 10))
+}
+
+// Block returns the index entry of the block with the given number
+func (i *Indexer) Block(number uint64) (*BlockRecord, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	record := &BlockRecord{}
+	if err := i.load(blockKey(number), record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// Blocks returns the index entries of the blocks in the given (inclusive) range, in order. Blocks
+// which aren't indexed yet are omitted.
+func (i *Indexer) Blocks(from, to uint64) ([]*BlockRecord, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	lastBlock, ok, err := i.lastBlock()
+	if err != nil || !ok {
+		return nil, err
+	}
+	if to > lastBlock {
+		to = lastBlock
+	}
+	if from > to {
+		return nil, nil
+	}
+
+	var keys []interface{}
+	for number := from; number <= to; number++ {
+		keys = append(keys, blockKey(number))
+	}
+	values, err := keyvaluestore.LoadBatch(i.store, keys)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load blocks %d to %d", from, to)
+	}
+
+	var records []*BlockRecord
+	for j, value := range values {
+		if value == nil {
+			continue
+		}
+		record := &BlockRecord{}
+		if err := unmarshal(value, record); err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("invalid index entry [%s]", keys[j]))
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// Transaction returns the index entry of the transaction with the given ID
+func (i *Indexer) Transaction(txID string) (*TxRecord, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	record := &TxRecord{}
+	if err := i.load(txKey(txID), record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// ChaincodeEvents returns the chaincode events with the given name which were set by valid
+// transactions of the given chaincode, in the order in which they were committed
+func (i *Indexer) ChaincodeEvents(ccID, eventName string) ([]*EventRecord, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	var records []*EventRecord
+	if err := i.load(eventsKey(ccID, eventName), &records); err != nil && err != ErrNotFound {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Keys returns the keys of the given namespace which exist in the world state or in private
+// data collections, ordered by collection and key
+func (i *Indexer) Keys(namespace string) ([]*KeyRecord, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	keys := make(map[string]*KeyRecord)
+	if err := i.load(keysKey(namespace), &keys); err != nil && err != ErrNotFound {
+		return nil, err
+	}
+
+	records := make([]*KeyRecord, 0, len(keys))
+	for _, record := range keys {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(a, b int) bool {
+		if records[a].Collection != records[b].Collection {
+			return records[a].Collection < records[b].Collection
+		}
+		return records[a].Key < records[b].Key
+	})
+	return records, nil
+}
+
+func (i *Indexer) lastBlock() (uint64, bool, error) {
+	var s string
+	if err := i.load(checkpointKey, &s); err != nil {
+		if err == ErrNotFound {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	blockNum, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "invalid checkpoint [%s]", s)
+	}
+	return blockNum, true, nil
+}
+
+// load unmarshals the value of the given key, or returns ErrNotFound
+func (i *Indexer) load(key string, v interface{}) error {
+	value, err := i.store.Load(key)
+	if err != nil {
+		if errors.Cause(err) == core.ErrKeyValueNotFound {
+			return ErrNotFound
+		}
+		return errors.Wrapf(err, "failed to load index entry [%s]", key)
+	}
+	if err := unmarshal(value, v); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("invalid index entry [%s]", key))
+	}
+	return nil
+}
+
+func (i *Indexer) storeJSON(key string, v interface{}) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal index entry [%s]", key)
+	}
+	return errors.Wrapf(i.store.Store(key, value), "failed to store index entry [%s]", key)
+}
+
+// batch collects the index entries updated by a block
+type batch struct {
+	indexer *Indexer
+	order   []string
+	values  map[string]interface{}
+}
+
+func (b *batch) addTransaction(blockNum uint64, tx *offchain.Transaction) error {
+	record := &TxRecord{
+		TxID:           tx.TxID,
+		BlockNumber:    blockNum,
+		TxNum:          tx.TxNum,
+		Timestamp:      tx.Timestamp,
+		ChaincodeID:    tx.ChaincodeID,
+		ValidationCode: tx.ValidationCode,
+	}
+
+	for _, w := range tx.Writes {
+		keyRecord := &KeyRecord{Namespace: w.Namespace, Collection: w.Collection, Key: w.Key, IsDelete: w.IsDelete}
+		record.Writes = append(record.Writes, keyRecord)
+
+		keys, err := b.keys(w.Namespace)
+		if err != nil {
+			return err
+		}
+		id := w.Collection + "~" + w.Key
+		if w.IsDelete {
+			delete(keys, id)
+		} else {
+			keys[id] = &KeyRecord{Namespace: w.Namespace, Collection: w.Collection, Key: w.Key, TxID: tx.TxID, BlockNumber: blockNum}
+		}
+	}
+
+	if tx.ValidationCode == pb.TxValidationCode_VALID {
+		for _, event := range tx.ChaincodeEvents {
+			record.EventNames = append(record.EventNames, event.EventName)
+
+			events, err := b.events(event.ChaincodeID, event.EventName)
+			if err != nil {
+				return err
+			}
+			*events = append(*events, &EventRecord{
+				TxID:        tx.TxID,
+				BlockNumber: blockNum,
+				ChaincodeID: event.ChaincodeID,
+				EventName:   event.EventName,
+				Payload:     event.Payload,
+			})
+		}
+	}
+
+	// The record of the original transaction is kept for duplicates
+	if tx.ValidationCode != pb.TxValidationCode_DUPLICATE_TXID {
+		b.put(txKey(tx.TxID), record)
+	}
+	return nil
+}
+
+// keys returns the key index of the namespace, as updated by the batch so far
+func (b *batch) keys(namespace string) (map[string]*KeyRecord, error) {
+	key := keysKey(namespace)
+	if value, ok := b.values[key]; ok {
+		return value.(map[string]*KeyRecord), nil
+	}
+	keys := make(map[string]*KeyRecord)
+	if err := b.indexer.load(key, &keys); err != nil && err != ErrNotFound {
+		return nil, err
+	}
+	b.put(key, keys)
+	return keys, nil
+}
+
+// events returns the event index of the chaincode event, as updated by the batch so far
+func (b *batch) events(ccID, eventName string) (*[]*EventRecord, error) {
+	key := eventsKey(ccID, eventName)
+	if value, ok := b.values[key]; ok {
+		return value.(*[]*EventRecord), nil
+	}
+	var events []*EventRecord
+	if err := b.indexer.load(key, &events); err != nil && err != ErrNotFound {
+		return nil, err
+	}
+	b.put(key, &events)
+	return &events, nil
+}
+
+func (b *batch) put(key string, value interface{}) {
+	if _, ok := b.values[key]; !ok {
+		b.order = append(b.order, key)
+	}
+	b.values[key] = value
+}
+
+// store stores the entries of the batch, in the order in which they were added (so the checkpoint,
+// which is added last, is stored last if the store doesn't support batches)
+func (b *batch) store() error {
+	entries := make([]keyvaluestore.Entry, len(b.order))
+	for j, key := range b.order {
+		value, err := json.Marshal(b.values[key])
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal index entry [%s]", key)
+		}
+		entries[j] = keyvaluestore.Entry{Key: key, Value: value}
+	}
+	return errors.Wrap(keyvaluestore.StoreBatch(b.indexer.store, entries), "failed to store index entries")
+}
+
+func unmarshal(value interface{}, v interface{}) error {
+	var data []byte
+	switch value := value.(type) {
+	case []byte:
+		data = value
+	case string:
+		data = []byte(value)
+	default:
+		return errors.Errorf("unexpected value type %T", value)
+	}
+	return errors.Wrap(json.Unmarshal(data, v), "failed to unmarshal index entry")
+}
+
+func blockKey(number uint64) string {
+	return fmt.Sprintf("block/%020d", number)
+}
+
+func txKey(txID string) string {
+	return "tx/" + url.PathEscape(txID)
+}
+
+func eventsKey(ccID, eventName string) string {
+	return "events/" + url.PathEscape(ccID) + "/" + url.PathEscape(eventName)
+}
+
+func keysKey(namespace string) string {
+	return "keys/" + url.PathEscape(namespace)
+}
@@ -0,0 +1,164 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package explorer
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/offchain"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/keyvaluestore"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+var _ offchain.Checkpointer = (*Indexer)(nil)
+
+func TestIndexer(t *testing.T) {
+	indexer, cleanup := newTestIndexer(t)
+	defer cleanup()
+
+	_, ok, err := indexer.LastBlock()
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	timestamp := time.Unix(1500000000, 0).UTC()
+	require.NoError(t, indexer.Apply(&offchain.Block{Number: 0, DataHash: []byte("hash0")}))
+	require.NoError(t, indexer.Apply(&offchain.Block{
+		Number:       1,
+		DataHash:     []byte("hash1"),
+		PreviousHash: []byte("prev1"),
+		Transactions: []*offchain.Transaction{
+			{
+				TxID:        "tx1",
+				Timestamp:   timestamp,
+				ChaincodeID: "mycc",
+				Writes: []*offchain.Write{
+					{Namespace: "mycc", Key: "b", Value: []byte("1")},
+					{Namespace: "mycc", Key: "a", Value: []byte("2")},
+					{Namespace: "mycc", Collection: "coll1", Key: "c", Value: []byte("3")},
+				},
+				ChaincodeEvents: []*fab.CCEvent{{TxID: "tx1", ChaincodeID: "mycc", EventName: "created", Payload: []byte("b")}},
+			},
+			{
+				TxID:            "tx2",
+				TxNum:           1,
+				ChaincodeID:     "mycc",
+				ValidationCode:  pb.TxValidationCode_MVCC_READ_CONFLICT,
+				ChaincodeEvents: []*fab.CCEvent{{TxID: "tx2", ChaincodeID: "mycc", EventName: "created", Payload: []byte("x")}},
+			},
+		},
+	}))
+	require.NoError(t, indexer.Apply(&offchain.Block{
+		Number: 2,
+		Transactions: []*offchain.Transaction{
+			{
+				TxID:            "tx3",
+				ChaincodeID:     "mycc",
+				Writes:          []*offchain.Write{{Namespace: "mycc", Key: "b", IsDelete: true}},
+				ChaincodeEvents: []*fab.CCEvent{{TxID: "tx3", ChaincodeID: "mycc", EventName: "created", Payload: []byte("d")}},
+			},
+			{TxID: "tx1", TxNum: 1, ChaincodeID: "mycc", ValidationCode: pb.TxValidationCode_DUPLICATE_TXID},
+		},
+	}))
+
+	lastBlock, ok, err := indexer.LastBlock()
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, 2, lastBlock)
+
+	tx, err := indexer.Transaction("tx1")
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, tx.BlockNumber, "expecting duplicate transaction not to replace the original")
+	assert.Equal(t, timestamp, tx.Timestamp)
+	assert.Equal(t, pb.TxValidationCode_VALID, tx.ValidationCode)
+	assert.Len(t, tx.Writes, 3)
+	assert.Equal(t, []string{"created"}, tx.EventNames)
+
+	tx, err = indexer.Transaction("tx2")
+	require.NoError(t, err)
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, tx.ValidationCode)
+	assert.Empty(t, tx.EventNames, "expecting no events for invalid transaction")
+
+	_, err = indexer.Transaction("unknown")
+	assert.Equal(t, ErrNotFound, err)
+
+	block, err := indexer.Block(1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tx1", "tx2"}, block.TxIDs)
+	assert.Equal(t, []byte("prev1"), block.PreviousHash)
+
+	blocks, err := indexer.Blocks(1, 10)
+	require.NoError(t, err)
+	require.Len(t, blocks, 2)
+	assert.EqualValues(t, 1, blocks[0].Number)
+	assert.EqualValues(t, 2, blocks[1].Number)
+
+	blocks, err = indexer.Blocks(5, 10)
+	require.NoError(t, err)
+	assert.Empty(t, blocks)
+
+	events, err := indexer.ChaincodeEvents("mycc", "created")
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "tx1", events[0].TxID)
+	assert.Equal(t, []byte("b"), events[0].Payload)
+	assert.Equal(t, "tx3", events[1].TxID)
+
+	events, err = indexer.ChaincodeEvents("mycc", "deleted")
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	keys, err := indexer.Keys("mycc")
+	require.NoError(t, err)
+	require.Len(t, keys, 2, "expecting deleted key to be removed")
+	assert.Equal(t, &KeyRecord{Namespace: "mycc", Key: "a", TxID: "tx1", BlockNumber: 1}, keys[0])
+	assert.Equal(t, &KeyRecord{Namespace: "mycc", Collection: "coll1", Key: "c", TxID: "tx1", BlockNumber: 1}, keys[1])
+}
+
+func TestIndexerReplay(t *testing.T) {
+	indexer, cleanup := newTestIndexer(t)
+	defer cleanup()
+
+	block := &offchain.Block{Number: 1, Transactions: []*offchain.Transaction{{
+		TxID:            "tx1",
+		ChaincodeID:     "mycc",
+		ChaincodeEvents: []*fab.CCEvent{{TxID: "tx1", ChaincodeID: "mycc", EventName: "created"}},
+	}}}
+	require.NoError(t, indexer.Apply(block))
+	require.NoError(t, indexer.Apply(block))
+
+	events, err := indexer.ChaincodeEvents("mycc", "created")
+	require.NoError(t, err)
+	assert.Len(t, events, 1, "expecting replayed block to be ignored")
+
+	require.NoError(t, indexer.SetLastBlock(5))
+	lastBlock, _, err := indexer.LastBlock()
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, lastBlock)
+
+	_, err = NewIndexer(nil)
+	assert.Error(t, err)
+}
+
+func newTestIndexer(t *testing.T) (*Indexer, func()) {
+	path, err := ioutil.TempDir("", "explorer")
+	require.NoError(t, err)
+
+	store, err := keyvaluestore.New(&keyvaluestore.FileKeyValueStoreOptions{Path: path})
+	require.NoError(t, err)
+
+	indexer, err := NewIndexer(store)
+	require.NoError(t, err)
+
+	return indexer, func() { os.RemoveAll(path) }
+}
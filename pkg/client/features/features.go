@@ -0,0 +1,273 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package features reports which features of the SDK are usable on a channel, based on the
+// capabilities of the channel configuration and, optionally, on the versions of the peers.
+// High-level clients use it to fail early with an UnsupportedError (or fall back to an
+// older mechanism) instead of sending requests which the peers would reject:
+//
+//  set, err := features.New(channelProvider, features.WithPeerVersions(versions))
+//  ...
+//  if !set.Supported(features.NewLifecycle) {
+//      // use resmgmt.InstantiateCC instead
+//  }
+package features
+
+import (
+	reqContext "context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/bft"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/operations"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+// Feature is a feature of the SDK which depends on the capabilities of the channel
+type Feature string
+
+const (
+	// NewLifecycle is the chaincode lifecycle of Fabric 2.x (_lifecycle system chaincode)
+	NewLifecycle Feature = "new-lifecycle"
+	// StateBasedEndorsement is key-level endorsement policies
+	StateBasedEndorsement Feature = "state-based-endorsement"
+	// PrivateDataReconciliation is the reconciliation of missing private data by the peers
+	PrivateDataReconciliation Feature = "private-data-reconciliation"
+	// BFT is the support of BFT ordering services
+	BFT Feature = "bft"
+)
+
+// gate is the requirement of a feature
+type gate struct {
+	// capability is the minimum application capability, if any
+	capability string
+	// peerVersion is the minimum version of the peers, if any
+	peerVersion string
+	// check is an additional check of the channel configuration, if any
+	check  func(cfg fab.ChannelCfg) bool
+	reason string
+}
+
+var gates = map[Feature]gate{
+	NewLifecycle:              {capability: fab.V2_0Capability, peerVersion: "2.0"},
+	StateBasedEndorsement:     {capability: fab.V1_3Capability, peerVersion: "1.3"},
+	PrivateDataReconciliation: {capability: fab.V1_2Capability, peerVersion: "1.4"},
+	BFT:                       {check: bft.IsBFT, reason: "the channel isn't ordered by a BFT ordering service"},
+}
+
+// All returns all the features, sorted
+func All() []Feature {
+	all := make([]Feature, 0, len(gates))
+	for f := range gates {
+		all = append(all, f)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+	return all
+}
+
+// Status is whether a feature is usable on the channel
+type Status struct {
+	Feature   Feature
+	Supported bool
+	// Reason explains why the feature isn't supported
+	Reason string
+}
+
+// UnsupportedError is returned by clients when a feature isn't usable on the channel
+type UnsupportedError struct {
+	ChannelID string
+	Feature   Feature
+	Reason    string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("feature [%s] is not supported on channel [%s]: %s", e.Feature, e.ChannelID, e.Reason)
+}
+
+// IsUnsupported returns true if the cause of the error is an UnsupportedError
+func IsUnsupported(err error) bool {
+	_, ok := errors.Cause(err).(*UnsupportedError)
+	return ok
+}
+
+// Set holds the status of the features on a channel. It is a snapshot of the channel
+// configuration and should be renewed when the configuration is updated.
+type Set struct {
+	channelID    string
+	peerVersions map[string]string
+	status       map[Feature]*Status
+}
+
+// Option describes a functional parameter for the New and FromConfig constructors
+type Option func(*Set)
+
+// WithPeerVersions sets the versions of the peers of the channel, by URL (see QueryPeerVersions).
+// A feature isn't supported if any of the peers is older than the version which introduced it.
+func WithPeerVersions(versions map[string]string) Option {
+	return func(s *Set) {
+		s.peerVersions = versions
+	}
+}
+
+// New returns the features of the channel of the given provider
+func New(channelProvider context.ChannelProvider, opts ...Option) (*Set, error) {
+	ctx, err := channelProvider()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create channel context")
+	}
+	chService := ctx.ChannelService()
+	if chService == nil {
+		return nil, errors.New("channel service not initialized")
+	}
+	cfg, err := chService.ChannelConfig()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get channel config")
+	}
+	return FromConfig(cfg, opts...), nil
+}
+
+// FromConfig returns the features of the channel with the given configuration
+func FromConfig(cfg fab.ChannelCfg, opts ...Option) *Set {
+	s := &Set{
+		channelID: cfg.ID(),
+		status:    make(map[Feature]*Status, len(gates)),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	for f, g := range gates {
+		s.status[f] = s.evaluate(cfg, f, g)
+	}
+	return s
+}
+
+func (s *Set) evaluate(cfg fab.ChannelCfg, f Feature, g gate) *Status {
+	if g.capability != "" && !chconfig.IsCapabilitySupported(cfg, fab.ApplicationGroupKey, g.capability) {
+		return &Status{Feature: f, Reason: fmt.Sprintf("application capability %s is not enabled", g.capability)}
+	}
+	if g.check != nil && !g.check(cfg) {
+		return &Status{Feature: f, Reason: g.reason}
+	}
+	if g.peerVersion != "" {
+		urls := make([]string, 0, len(s.peerVersions))
+		for url := range s.peerVersions {
+			urls = append(urls, url)
+		}
+		sort.Strings(urls)
+		for _, url := range urls {
+			older, err := olderThan(s.peerVersions[url], g.peerVersion)
+			if err != nil {
+				logger.Warnf("Version of peer [%s] ignored: %s", url, err)
+				continue
+			}
+			if older {
+				return &Status{Feature: f, Reason: fmt.Sprintf("peer [%s] version %s is older than %s", url, s.peerVersions[url], g.peerVersion)}
+			}
+		}
+	}
+	return &Status{Feature: f, Supported: true}
+}
+
+// Supported returns true if the feature is usable on the channel
+func (s *Set) Supported(f Feature) bool {
+	return s.Status(f).Supported
+}
+
+// Check returns an UnsupportedError if the feature isn't usable on the channel
+func (s *Set) Check(f Feature) error {
+	status := s.Status(f)
+	if status.Supported {
+		return nil
+	}
+	return &UnsupportedError{ChannelID: s.channelID, Feature: f, Reason: status.Reason}
+}
+
+// Status returns the status of the feature
+func (s *Set) Status(f Feature) *Status {
+	status, ok := s.status[f]
+	if !ok {
+		return &Status{Feature: f, Reason: "unknown feature"}
+	}
+	copied := *status
+	return &copied
+}
+
+// Report returns the status of all the features, sorted by feature
+func (s *Set) Report() []*Status {
+	report := make([]*Status, 0, len(s.status))
+	for _, f := range All() {
+		report = append(report, s.Status(f))
+	}
+	return report
+}
+
+// VersionQuerier returns the version of a node (see operations.Client)
+type VersionQuerier interface {
+	Version(ctx reqContext.Context) (*operations.VersionInfo, error)
+}
+
+// QueryPeerVersions queries the versions of the peers from their operations services, by peer URL.
+// Peers which don't respond are skipped, so that an unreachable peer doesn't disable features.
+func QueryPeerVersions(ctx reqContext.Context, queriers map[string]VersionQuerier) map[string]string {
+	versions := make(map[string]string, len(queriers))
+	for url, querier := range queriers {
+		info, err := querier.Version(ctx)
+		if err != nil {
+			logger.Warnf("Failed to query version of peer [%s]: %s", url, err)
+			continue
+		}
+		versions[url] = info.Version
+	}
+	return versions
+}
+
+// olderThan returns true if the version (e.g. v1.4.3 or 2.2.0-snapshot-abc) is older than
+// the minimum major.minor version
+func olderThan(version, minimum string) (bool, error) {
+	v, err := parseVersion(version)
+	if err != nil {
+		return false, err
+	}
+	m, err := parseVersion(minimum)
+	if err != nil {
+		return false, err
+	}
+	if v[0] != m[0] {
+		return v[0] < m[0], nil
+	}
+	return v[1] < m[1], nil
+}
+
+// parseVersion returns the major and minor numbers of the version
+func parseVersion(version string) ([2]int, error) {
+	var parsed [2]int
+	v := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) < 2 {
+		return parsed, errors.Errorf("invalid version [%s]", version)
+	}
+	for i := range parsed {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return parsed, errors.Errorf("invalid version [%s]", version)
+		}
+		parsed[i] = n
+	}
+	return parsed, nil
+}
@@ -0,0 +1,116 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package features
+
+import (
+	reqContext "context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/operations"
+)
+
+func TestFromConfig(t *testing.T) {
+	cfg := mocks.NewMockChannelCfg("mychannel")
+	cfg.MockCapabilities = map[fab.ConfigGroupKey]map[string]bool{
+		fab.ApplicationGroupKey: {fab.V1_4_2Capability: true},
+	}
+	cfg.MockConsensusType = "etcdraft"
+
+	set := FromConfig(cfg)
+	assert.True(t, set.Supported(StateBasedEndorsement))
+	assert.True(t, set.Supported(PrivateDataReconciliation))
+	assert.False(t, set.Supported(NewLifecycle))
+	assert.Contains(t, set.Status(NewLifecycle).Reason, "V2_0")
+	assert.False(t, set.Supported(BFT))
+	assert.False(t, set.Supported(Feature("unknown")))
+
+	cfg.MockCapabilities[fab.ApplicationGroupKey][fab.V2_0Capability] = true
+	cfg.MockConsensusType = "BFT"
+	set = FromConfig(cfg)
+	for _, status := range set.Report() {
+		assert.True(t, status.Supported, "expecting feature [%s] to be supported", status.Feature)
+	}
+	assert.Len(t, set.Report(), len(All()))
+}
+
+func TestPeerVersions(t *testing.T) {
+	cfg := mocks.NewMockChannelCfg("mychannel")
+	cfg.MockCapabilities = map[fab.ConfigGroupKey]map[string]bool{
+		fab.ApplicationGroupKey: {fab.V2_0Capability: true},
+	}
+
+	set := FromConfig(cfg, WithPeerVersions(map[string]string{
+		"peer0.org1.example.com:7051": "v2.1.0",
+		"peer0.org2.example.com:7051": "1.4.6",
+		"peer1.org2.example.com:7051": "unknown",
+	}))
+	assert.False(t, set.Supported(NewLifecycle))
+	assert.Contains(t, set.Status(NewLifecycle).Reason, "peer0.org2.example.com:7051")
+	assert.True(t, set.Supported(PrivateDataReconciliation))
+
+	set = FromConfig(cfg, WithPeerVersions(map[string]string{"peer0.org1.example.com:7051": "2.2.0-snapshot-abc"}))
+	assert.True(t, set.Supported(NewLifecycle))
+}
+
+func TestCheck(t *testing.T) {
+	set := FromConfig(mocks.NewMockChannelCfg("mychannel"))
+	assert.Error(t, set.Check(Feature("unknown")))
+
+	err := set.Check(NewLifecycle)
+	require.Error(t, err)
+	assert.True(t, IsUnsupported(err))
+	assert.True(t, IsUnsupported(errors.WithMessage(err, "query failed")))
+	assert.False(t, IsUnsupported(errors.New("query failed")))
+	assert.Contains(t, err.Error(), "mychannel")
+}
+
+func TestQueryPeerVersions(t *testing.T) {
+	versions := QueryPeerVersions(reqContext.Background(), map[string]VersionQuerier{
+		"peer0.org1.example.com:7051": &mockVersionQuerier{version: "2.0.0"},
+		"peer0.org2.example.com:7051": &mockVersionQuerier{err: errors.New("connection refused")},
+	})
+	assert.Equal(t, map[string]string{"peer0.org1.example.com:7051": "2.0.0"}, versions)
+}
+
+func TestOlderThan(t *testing.T) {
+	tests := []struct {
+		version string
+		older   bool
+	}{
+		{"1.4.9", true},
+		{"v1.10.0", true},
+		{"2.0.0", false},
+		{"2.0.0-beta", false},
+		{"3.0", false},
+	}
+	for _, test := range tests {
+		older, err := olderThan(test.version, "2.0")
+		require.NoError(t, err)
+		assert.Equal(t, test.older, older, "version %s", test.version)
+	}
+
+	_, err := olderThan("2", "2.0")
+	assert.Error(t, err)
+}
+
+type mockVersionQuerier struct {
+	version string
+	err     error
+}
+
+func (q *mockVersionQuerier) Version(ctx reqContext.Context) (*operations.VersionInfo, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	return &operations.VersionInfo{Version: q.version}, nil
+}
@@ -0,0 +1,241 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fpc
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Offsets in the body of an SGX quote (the quote without its signature), as included in the
+// attestation verification reports of the Intel Attestation Service
+const (
+	quoteBodySize    = 432
+	mrEnclaveOffset  = 112
+	mrEnclaveSize    = 32
+	reportDataOffset = 368
+	reportDataSize   = 64
+)
+
+// QuoteStatusOK is the quote status of the attestation verification report of an enclave
+// running on an up to date platform
+const QuoteStatusOK = "OK"
+
+// Credentials are the credentials of the enclave of a private chaincode, as registered
+// in the enclave registry
+type Credentials struct {
+	// MREnclave is the measurement (hex encoded) of the enclave
+	MREnclave string `json:"mrenclave"`
+	// EnclaveVK is the PEM encoded ECDSA public key with which the enclave signs its responses
+	EnclaveVK []byte `json:"enclaveVk"`
+	// ChaincodeEK is the PEM encoded RSA public key to which the requests are encrypted
+	ChaincodeEK []byte `json:"chaincodeEk"`
+	// Evidence is the JSON encoded attestation verification report of the enclave (see IASReport)
+	Evidence []byte `json:"evidence"`
+}
+
+// IASReport is the attestation verification report of the quote of an enclave, as returned by the
+// Intel Attestation Service (IAS) together with its signature and signing certificate
+type IASReport struct {
+	// Body is the attestation verification report (see IASReportBody)
+	Body []byte `json:"iasReportBody"`
+	// Signature is the base64 encoded signature of the body (the X-IASReport-Signature header)
+	Signature string `json:"iasReportSignature"`
+	// SigningCertificate is the PEM encoded certificate chain of the report signing key, starting
+	// with the signing certificate (the URL encoded X-IASReport-Signing-Certificate header)
+	SigningCertificate string `json:"iasReportSigningCertificate"`
+}
+
+// IASReportBody is the attestation verification report of the quote of an enclave
+type IASReportBody struct {
+	ID        string `json:"id"`
+	Timestamp string `json:"timestamp"`
+	Version   int    `json:"version"`
+	// IsvEnclaveQuoteStatus is the result of the verification of the quote (e.g. "OK" or "GROUP_OUT_OF_DATE")
+	IsvEnclaveQuoteStatus string `json:"isvEnclaveQuoteStatus"`
+	// IsvEnclaveQuoteBody is the base64 encoded body of the quote, which holds the measurement and
+	// the report data of the enclave
+	IsvEnclaveQuoteBody string `json:"isvEnclaveQuoteBody"`
+}
+
+// Verifier verifies the credentials of the enclave of a private chaincode before any request
+// is encrypted to its key
+type Verifier interface {
+	Verify(chaincodeID string, credentials *Credentials) error
+}
+
+// AttestationVerifier verifies that the credentials are attested by the Intel Attestation Service
+// and that the attested enclave runs the expected code
+type AttestationVerifier struct {
+	roots         *x509.CertPool
+	mrEnclave     map[string]string
+	quoteStatuses map[string]bool
+}
+
+// VerifierOption describes a functional parameter for the NewAttestationVerifier constructor
+type VerifierOption func(*AttestationVerifier)
+
+// WithQuoteStatuses sets the quote statuses of the attestation verification reports which are
+// accepted, by default only "OK". Statuses such as "GROUP_OUT_OF_DATE" or "SW_HARDENING_NEEDED"
+// may be accepted for enclaves running on platforms which aren't fully up to date.
+func WithQuoteStatuses(statuses ...string) VerifierOption {
+	return func(v *AttestationVerifier) {
+		v.quoteStatuses = make(map[string]bool)
+		for _, status := range statuses {
+			v.quoteStatuses[status] = true
+		}
+	}
+}
+
+// NewAttestationVerifier returns a verifier which accepts the reports signed by the report signing
+// certificates issued by the given (PEM encoded) root CA certificate, i.e. the Intel SGX Attestation
+// Report Signing CA. mrEnclave is the expected measurement of the enclave, by chaincode ID.
+func NewAttestationVerifier(rootCert []byte, mrEnclave map[string]string, opts ...VerifierOption) (*AttestationVerifier, error) {
+	certs, err := parseCertificates(rootCert)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to parse attestation report signing CA certificate")
+	}
+	v := &AttestationVerifier{
+		roots:         x509.NewCertPool(),
+		mrEnclave:     mrEnclave,
+		quoteStatuses: map[string]bool{QuoteStatusOK: true},
+	}
+	for _, cert := range certs {
+		v.roots.AddCert(cert)
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v, nil
+}
+
+// Verify verifies the signature of the attestation verification report, the status and the
+// measurement of the quote of the enclave and the binding of the keys of the enclave to the quote
+func (v *AttestationVerifier) Verify(chaincodeID string, credentials *Credentials) error {
+	expected, ok := v.mrEnclave[chaincodeID]
+	if !ok {
+		return errors.Errorf("no expected enclave measurement for chaincode [%s]", chaincodeID)
+	}
+
+	report := &IASReport{}
+	if err := json.Unmarshal(credentials.Evidence, report); err != nil {
+		return errors.Wrap(err, "failed to unmarshal attestation evidence")
+	}
+	if err := v.verifySignature(report); err != nil {
+		return err
+	}
+
+	body := &IASReportBody{}
+	if err := json.Unmarshal(report.Body, body); err != nil {
+		return errors.Wrap(err, "failed to unmarshal attestation verification report")
+	}
+	if !v.quoteStatuses[body.IsvEnclaveQuoteStatus] {
+		return errors.Errorf("quote status [%s] of attestation verification report isn't accepted", body.IsvEnclaveQuoteStatus)
+	}
+	quote, err := base64.StdEncoding.DecodeString(body.IsvEnclaveQuoteBody)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode quote of attestation verification report")
+	}
+	if len(quote) < quoteBodySize {
+		return errors.Errorf("quote of attestation verification report is too short (%d bytes)", len(quote))
+	}
+
+	mrEnclave := hex.EncodeToString(quote[mrEnclaveOffset : mrEnclaveOffset+mrEnclaveSize])
+	if !strings.EqualFold(mrEnclave, expected) || !strings.EqualFold(credentials.MREnclave, expected) {
+		return errors.Errorf("enclave measurement [%s] of chaincode [%s] doesn't match [%s]", mrEnclave, chaincodeID, expected)
+	}
+
+	reportData := make([]byte, reportDataSize)
+	copy(reportData, ReportData(credentials))
+	if !bytes.Equal(quote[reportDataOffset:reportDataOffset+reportDataSize], reportData) {
+		return errors.New("enclave keys aren't bound to attestation evidence")
+	}
+	return nil
+}
+
+// verifySignature verifies that the report is signed by a signing certificate issued by the root CA
+func (v *AttestationVerifier) verifySignature(report *IASReport) error {
+	chain, err := url.PathUnescape(report.SigningCertificate)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode report signing certificate")
+	}
+	certs, err := parseCertificates([]byte(chain))
+	if err != nil {
+		return errors.WithMessage(err, "failed to parse report signing certificate")
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	opts := x509.VerifyOptions{Roots: v.roots, Intermediates: intermediates, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}}
+	if _, err := certs[0].Verify(opts); err != nil {
+		return errors.Wrap(err, "report signing certificate isn't issued by the attestation report signing CA")
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(report.Signature)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode signature of attestation verification report")
+	}
+	var algorithm x509.SignatureAlgorithm
+	switch certs[0].PublicKey.(type) {
+	case *rsa.PublicKey:
+		algorithm = x509.SHA256WithRSA
+	case *ecdsa.PublicKey:
+		algorithm = x509.ECDSAWithSHA256
+	default:
+		return errors.New("unsupported report signing key")
+	}
+	if err := certs[0].CheckSignature(algorithm, report.Body, signature); err != nil {
+		return errors.Wrap(err, "invalid signature of attestation verification report")
+	}
+	return nil
+}
+
+// ReportData returns the report data which binds the keys of the credentials to the quote of the enclave,
+// in which it precedes zero padding. It is the SHA-256 hash of the SHA-256 hash of the enclave verification
+// key followed by the SHA-256 hash of the chaincode encryption key, so that bytes may not be moved from
+// one key to the other without changing the report data.
+func ReportData(credentials *Credentials) []byte {
+	vkHash := sha256.Sum256(credentials.EnclaveVK)
+	ekHash := sha256.Sum256(credentials.ChaincodeEK)
+	h := sha256.New()
+	h.Write(vkHash[:])
+	h.Write(ekHash[:])
+	return h.Sum(nil)
+}
+
+// parseCertificates parses the PEM encoded certificates
+func parseCertificates(pemCerts []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var bl *pem.Block
+		bl, pemCerts = pem.Decode(pemCerts)
+		if bl == nil {
+			break
+		}
+		cert, err := x509.ParseCertificate(bl.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse certificate")
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("could not decode the PEM structure of the certificate")
+	}
+	return certs, nil
+}
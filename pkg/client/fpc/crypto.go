@@ -0,0 +1,196 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fpc
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// keySize is the size of the AES keys of the requests and the responses
+const keySize = 32
+
+// Request is the plaintext of an encrypted request, as decrypted by the enclave
+type Request struct {
+	Fcn  string   `json:"fcn"`
+	Args [][]byte `json:"args"`
+	// ResponseKey is the AES key to which the enclave encrypts the response
+	ResponseKey []byte `json:"responseKey"`
+}
+
+// EncryptedRequest is the single argument of the invocation of a private chaincode
+type EncryptedRequest struct {
+	// EncryptedKey is the AES key of the request, encrypted to the chaincode encryption key
+	// with RSA-OAEP (SHA-256)
+	EncryptedKey []byte `json:"encryptedKey"`
+	// EncryptedRequest is the Request, encrypted with AES-GCM (the nonce followed by the ciphertext)
+	EncryptedRequest []byte `json:"encryptedRequest"`
+}
+
+// EncryptedResponse is the payload of the response of a private chaincode
+type EncryptedResponse struct {
+	// EncryptedResponse is the result of the invocation, encrypted with the response key with AES-GCM
+	EncryptedResponse []byte `json:"encryptedResponse"`
+	// Signature is the ECDSA signature of the enclave of ResponseDigest
+	Signature []byte `json:"signature"`
+}
+
+// ResponseDigest returns the digest which is signed by the enclave: the SHA-256 hash of the encrypted
+// response followed by the SHA-256 hash of the encrypted request, which binds the response to the request
+func ResponseDigest(encryptedResponse, encryptedRequest []byte) []byte {
+	requestHash := sha256.Sum256(encryptedRequest)
+	h := sha256.New()
+	h.Write(encryptedResponse)
+	h.Write(requestHash[:])
+	return h.Sum(nil)
+}
+
+// encryptRequest encrypts the function and arguments to the chaincode encryption key. It returns the
+// argument of the invocation and the key of the response.
+func encryptRequest(chaincodeEK *rsa.PublicKey, fcn string, args [][]byte) ([]byte, []byte, error) {
+	requestKey, err := newKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	responseKey, err := newKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	plaintext, err := json.Marshal(&Request{Fcn: fcn, Args: args, ResponseKey: responseKey})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to marshal request")
+	}
+	encryptedRequest, err := seal(requestKey, plaintext)
+	if err != nil {
+		return nil, nil, err
+	}
+	encryptedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, chaincodeEK, requestKey, nil)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to encrypt request key")
+	}
+
+	arg, err := json.Marshal(&EncryptedRequest{EncryptedKey: encryptedKey, EncryptedRequest: encryptedRequest})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to marshal encrypted request")
+	}
+	return arg, responseKey, nil
+}
+
+// decryptResponse verifies the signature of the enclave of the response to the given request
+// and decrypts the response
+func decryptResponse(enclaveVK *ecdsa.PublicKey, responseKey, arg, payload []byte) ([]byte, error) {
+	response := &EncryptedResponse{}
+	if err := json.Unmarshal(payload, response); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal encrypted response")
+	}
+
+	sig := struct{ R, S *big.Int }{}
+	if _, err := asn1.Unmarshal(response.Signature, &sig); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal enclave signature")
+	}
+	if sig.R == nil || sig.S == nil || !ecdsa.Verify(enclaveVK, ResponseDigest(response.EncryptedResponse, arg), sig.R, sig.S) {
+		return nil, errors.New("invalid enclave signature of response")
+	}
+
+	return open(responseKey, response.EncryptedResponse)
+}
+
+// seal encrypts the plaintext with AES-GCM. The nonce precedes the ciphertext.
+func seal(key, plaintext []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Wrap(err, "failed to generate nonce")
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(key, ciphertext []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+	nonce := ciphertext[:aead.NonceSize()]
+	plaintext, err := aead.Open(nil, nonce, ciphertext[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt")
+	}
+	return plaintext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create AES cipher")
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create GCM")
+	}
+	return aead, nil
+}
+
+func newKey() ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, errors.Wrap(err, "failed to generate key")
+	}
+	return key, nil
+}
+
+// parseKeys parses the chaincode encryption key and the enclave verification key of the credentials
+func parseKeys(credentials *Credentials) (*rsa.PublicKey, *ecdsa.PublicKey, error) {
+	ek, err := parsePublicKey(credentials.ChaincodeEK)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "invalid chaincode encryption key")
+	}
+	chaincodeEK, ok := ek.(*rsa.PublicKey)
+	if !ok {
+		return nil, nil, errors.New("chaincode encryption key isn't an RSA key")
+	}
+
+	vk, err := parsePublicKey(credentials.EnclaveVK)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "invalid enclave verification key")
+	}
+	enclaveVK, ok := vk.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, nil, errors.New("enclave verification key isn't an ECDSA key")
+	}
+	return chaincodeEK, enclaveVK, nil
+}
+
+func parsePublicKey(pemKey []byte) (interface{}, error) {
+	bl, _ := pem.Decode(pemKey)
+	if bl == nil {
+		return nil, errors.New("could not decode the PEM structure")
+	}
+	key, err := x509.ParsePKIXPublicKey(bl.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse public key")
+	}
+	return key, nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package fpc provides client-side support for invoking Fabric Private Chaincodes (FPC), i.e.
+// chaincodes which run in an SGX enclave: the attestation verification report of the enclave, as
+// issued by the Intel Attestation Service (IAS), is verified, the function and arguments of each
+// request are encrypted to the chaincode encryption key and the response of the enclave is verified
+// and decrypted.
+//
+// Support is layered as a handler on the channel client, in front of the query or execute handlers:
+//
+//  verifier, err := fpc.NewAttestationVerifier(iasReportSigningCACert, map[string]string{"mycc": mrEnclave})
+//  ...
+//  response, err := client.InvokeHandler(fpc.NewHandler(verifier, invoke.NewQueryHandler()),
+//      channel.Request{ChaincodeID: "mycc", Fcn: "get", Args: [][]byte{[]byte("a")}})
+//
+// The credentials of the enclave are read from the enclave registry chaincode (see WithRegistry)
+// the first time a chaincode is invoked and are verified before they are used.
+package fpc
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/json"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+const (
+	// InvokeFcn is the function through which encrypted requests are passed to a private chaincode
+	InvokeFcn = "__invoke"
+
+	defaultRegistryCC  = "ercc"
+	defaultRegistryFcn = "queryEnclaveCredentials"
+)
+
+// Handler encrypts the requests to a private chaincode and decrypts its responses
+type Handler struct {
+	verifier    Verifier
+	next        invoke.Handler
+	registry    invoke.Handler
+	registryCC  string
+	registryFcn string
+	lock        sync.RWMutex
+	enclaves    map[string]*enclave
+}
+
+// enclave holds the verified keys of the enclave of a chaincode
+type enclave struct {
+	chaincodeEK *rsa.PublicKey
+	enclaveVK   *ecdsa.PublicKey
+}
+
+// Option describes a functional parameter for the NewHandler constructor
+type Option func(*Handler)
+
+// WithRegistry sets the chaincode and the function from which the credentials of the enclaves are read
+// (by default the "queryEnclaveCredentials" function of the "ercc" chaincode). The function is invoked
+// with the ID of the private chaincode and returns the JSON encoded Credentials.
+func WithRegistry(chaincodeID, fcn string) Option {
+	return func(h *Handler) {
+		h.registryCC = chaincodeID
+		h.registryFcn = fcn
+	}
+}
+
+// NewHandler returns a handler which verifies the credentials of the enclave with the given verifier,
+// encrypts the request and passes it to the next handler (typically invoke.NewQueryHandler() or
+// invoke.NewExecuteHandler()), and then verifies and decrypts the payload of the response.
+// The payloads of the individual endorser responses are left encrypted.
+func NewHandler(verifier Verifier, next invoke.Handler, opts ...Option) *Handler {
+	h := &Handler{
+		verifier:    verifier,
+		next:        next,
+		registry:    invoke.NewQueryHandler(),
+		registryCC:  defaultRegistryCC,
+		registryFcn: defaultRegistryFcn,
+		enclaves:    make(map[string]*enclave),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Handle encrypts the request, invokes the next handler and decrypts the response
+func (h *Handler) Handle(requestContext *invoke.RequestContext, clientContext *invoke.ClientContext) {
+	request := requestContext.Request
+	// The plaintext request is restored so that a retry encrypts it with fresh keys
+	defer func() { requestContext.Request = request }()
+
+	enclave, err := h.enclave(request.ChaincodeID, requestContext, clientContext)
+	if err != nil {
+		requestContext.Error = errors.WithMessage(err, "failed to get enclave credentials")
+		return
+	}
+
+	arg, responseKey, err := encryptRequest(enclave.chaincodeEK, request.Fcn, request.Args)
+	if err != nil {
+		requestContext.Error = errors.WithMessage(err, "failed to encrypt request")
+		return
+	}
+	requestContext.Request = invoke.Request{
+		ChaincodeID:  request.ChaincodeID,
+		Fcn:          InvokeFcn,
+		Args:         [][]byte{arg},
+		TransientMap: request.TransientMap,
+	}
+
+	h.next.Handle(requestContext, clientContext)
+	if requestContext.Error != nil {
+		return
+	}
+
+	payload, err := decryptResponse(enclave.enclaveVK, responseKey, arg, requestContext.Response.Payload)
+	if err != nil {
+		// The enclave may have been replaced, so its credentials are read again by the next request
+		h.evict(request.ChaincodeID)
+		requestContext.Error = errors.WithMessage(err, "failed to decrypt response")
+		return
+	}
+	requestContext.Response.Payload = payload
+}
+
+// enclave returns the verified keys of the enclave of the chaincode, reading its credentials
+// from the registry if they aren't cached
+func (h *Handler) enclave(chaincodeID string, requestContext *invoke.RequestContext, clientContext *invoke.ClientContext) (*enclave, error) {
+	h.lock.RLock()
+	e, ok := h.enclaves[chaincodeID]
+	h.lock.RUnlock()
+	if ok {
+		return e, nil
+	}
+
+	registryContext := *requestContext
+	registryContext.Request = invoke.Request{ChaincodeID: h.registryCC, Fcn: h.registryFcn, Args: [][]byte{[]byte(chaincodeID)}}
+	registryContext.Response = invoke.Response{}
+	registryContext.Error = nil
+	h.registry.Handle(&registryContext, clientContext)
+	if registryContext.Error != nil {
+		return nil, errors.WithMessage(registryContext.Error, "failed to query enclave registry")
+	}
+
+	credentials := &Credentials{}
+	if err := json.Unmarshal(registryContext.Response.Payload, credentials); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal enclave credentials")
+	}
+	if err := h.verifier.Verify(chaincodeID, credentials); err != nil {
+		return nil, errors.WithMessage(err, "enclave attestation verification failed")
+	}
+	chaincodeEK, enclaveVK, err := parseKeys(credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("Verified enclave [%s] of private chaincode [%s]", credentials.MREnclave, chaincodeID)
+
+	e = &enclave{chaincodeEK: chaincodeEK, enclaveVK: enclaveVK}
+	h.lock.Lock()
+	h.enclaves[chaincodeID] = e
+	h.lock.Unlock()
+	return e, nil
+}
+
+func (h *Handler) evict(chaincodeID string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	delete(h.enclaves, chaincodeID)
+}
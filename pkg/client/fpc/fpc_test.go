@@ -0,0 +1,353 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fpc
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
+)
+
+const (
+	testChaincode = "mycc"
+	testMREnclave = "c0ffeec0ffeec0ffeec0ffeec0ffeec0ffeec0ffeec0ffeec0ffeec0ffeec0ff"
+	badMREnclave  = "badbadbadbadbadbadbadbadbadbadbadbadbadbadbadbadbadbadbadbadbadb"
+)
+
+func TestHandler(t *testing.T) {
+	service := newTestAttestationService(t)
+	enclave := newTestEnclave(t)
+	registry := &mockRegistry{credentials: service.attest(t, enclave, testMREnclave)}
+
+	verifier, err := NewAttestationVerifier(service.rootCert, map[string]string{testChaincode: testMREnclave})
+	require.NoError(t, err)
+	h := NewHandler(verifier, enclave)
+	h.registry = registry
+
+	for _, arg := range []string{"a", "b"} {
+		requestContext := &invoke.RequestContext{Request: invoke.Request{ChaincodeID: testChaincode, Fcn: "get", Args: [][]byte{[]byte(arg)}}}
+		h.Handle(requestContext, &invoke.ClientContext{})
+		require.NoError(t, requestContext.Error)
+		assert.Equal(t, "get("+arg+")", string(requestContext.Response.Payload))
+		assert.Equal(t, "get", requestContext.Request.Fcn, "expecting plaintext request to be restored")
+	}
+	assert.Equal(t, 1, registry.queries, "expecting credentials to be cached")
+	assert.Equal(t, []string{testChaincode}, registry.chaincodes)
+}
+
+func TestHandlerAttestationFailure(t *testing.T) {
+	service := newTestAttestationService(t)
+	enclave := newTestEnclave(t)
+
+	verifier, err := NewAttestationVerifier(service.rootCert, map[string]string{testChaincode: testMREnclave})
+	require.NoError(t, err)
+
+	// Enclave runs unexpected code
+	h := NewHandler(verifier, enclave)
+	h.registry = &mockRegistry{credentials: service.attest(t, enclave, badMREnclave)}
+	requestContext := &invoke.RequestContext{Request: invoke.Request{ChaincodeID: testChaincode, Fcn: "get"}}
+	h.Handle(requestContext, &invoke.ClientContext{})
+	require.Error(t, requestContext.Error)
+	assert.Contains(t, requestContext.Error.Error(), "measurement")
+	assert.Equal(t, 0, enclave.invocations, "expecting request not to be sent")
+
+	// Keys are replaced after attestation
+	credentials := service.attest(t, enclave, testMREnclave)
+	credentials.ChaincodeEK = newTestEnclave(t).credentials(t, "").ChaincodeEK
+	h = NewHandler(verifier, enclave)
+	h.registry = &mockRegistry{credentials: credentials}
+	requestContext = &invoke.RequestContext{Request: invoke.Request{ChaincodeID: testChaincode, Fcn: "get"}}
+	h.Handle(requestContext, &invoke.ClientContext{})
+	require.Error(t, requestContext.Error)
+	assert.Contains(t, requestContext.Error.Error(), "bound")
+
+	// Report isn't signed by the attestation service
+	credentials = service.attest(t, enclave, testMREnclave)
+	credentials.Evidence = newTestAttestationService(t).attest(t, enclave, testMREnclave).Evidence
+	h = NewHandler(verifier, enclave)
+	h.registry = &mockRegistry{credentials: credentials}
+	requestContext = &invoke.RequestContext{Request: invoke.Request{ChaincodeID: testChaincode, Fcn: "get"}}
+	h.Handle(requestContext, &invoke.ClientContext{})
+	require.Error(t, requestContext.Error)
+	assert.Contains(t, requestContext.Error.Error(), "signing CA")
+
+	// Registry unavailable
+	h = NewHandler(verifier, enclave, WithRegistry("myercc", "getCredentials"))
+	h.registry = &mockRegistry{err: errors.New("no endorsers")}
+	requestContext = &invoke.RequestContext{Request: invoke.Request{ChaincodeID: testChaincode, Fcn: "get"}}
+	h.Handle(requestContext, &invoke.ClientContext{})
+	require.Error(t, requestContext.Error)
+	assert.Contains(t, requestContext.Error.Error(), "no endorsers")
+}
+
+func TestHandlerInvalidResponse(t *testing.T) {
+	service := newTestAttestationService(t)
+	enclave := newTestEnclave(t)
+	registry := &mockRegistry{credentials: service.attest(t, enclave, testMREnclave)}
+
+	verifier, err := NewAttestationVerifier(service.rootCert, map[string]string{testChaincode: testMREnclave})
+	require.NoError(t, err)
+	h := NewHandler(verifier, enclave)
+	h.registry = registry
+
+	// Response signed by another enclave
+	enclave.signer = newTestEnclave(t).vk
+	requestContext := &invoke.RequestContext{Request: invoke.Request{ChaincodeID: testChaincode, Fcn: "get"}}
+	h.Handle(requestContext, &invoke.ClientContext{})
+	require.Error(t, requestContext.Error)
+	assert.Contains(t, requestContext.Error.Error(), "invalid enclave signature")
+
+	// Credentials are read again after a failure
+	enclave.signer = enclave.vk
+	requestContext = &invoke.RequestContext{Request: invoke.Request{ChaincodeID: testChaincode, Fcn: "get"}}
+	h.Handle(requestContext, &invoke.ClientContext{})
+	require.NoError(t, requestContext.Error)
+	assert.Equal(t, 2, registry.queries)
+}
+
+// testEnclave simulates the enclave of a private chaincode. It is used as the next handler.
+type testEnclave struct {
+	ek          *rsa.PrivateKey
+	vk          *ecdsa.PrivateKey
+	signer      *ecdsa.PrivateKey
+	invocations int
+}
+
+func newTestEnclave(t *testing.T) *testEnclave {
+	ek, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	vk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return &testEnclave{ek: ek, vk: vk, signer: vk}
+}
+
+func (e *testEnclave) credentials(t *testing.T, mrEnclave string) *Credentials {
+	ek, err := x509.MarshalPKIXPublicKey(&e.ek.PublicKey)
+	require.NoError(t, err)
+	vk, err := x509.MarshalPKIXPublicKey(&e.vk.PublicKey)
+	require.NoError(t, err)
+	return &Credentials{
+		MREnclave:   mrEnclave,
+		ChaincodeEK: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: ek}),
+		EnclaveVK:   pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: vk}),
+	}
+}
+
+func (e *testEnclave) Handle(requestContext *invoke.RequestContext, clientContext *invoke.ClientContext) {
+	e.invocations++
+	payload, err := e.invoke(requestContext.Request)
+	if err != nil {
+		requestContext.Error = err
+		return
+	}
+	requestContext.Response.Payload = payload
+}
+
+func (e *testEnclave) invoke(request invoke.Request) ([]byte, error) {
+	if request.Fcn != InvokeFcn || len(request.Args) != 1 {
+		return nil, errors.New("invalid private chaincode invocation")
+	}
+	encryptedRequest := &EncryptedRequest{}
+	if err := json.Unmarshal(request.Args[0], encryptedRequest); err != nil {
+		return nil, err
+	}
+	requestKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, e.ek, encryptedRequest.EncryptedKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := open(requestKey, encryptedRequest.EncryptedRequest)
+	if err != nil {
+		return nil, err
+	}
+	r := &Request{}
+	if err := json.Unmarshal(plaintext, r); err != nil {
+		return nil, err
+	}
+
+	result := r.Fcn + "("
+	for _, arg := range r.Args {
+		result += string(arg)
+	}
+	result += ")"
+
+	encryptedResponse, err := seal(r.ResponseKey, []byte(result))
+	if err != nil {
+		return nil, err
+	}
+	signature, err := e.signer.Sign(rand.Reader, ResponseDigest(encryptedResponse, request.Args[0]), nil)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&EncryptedResponse{EncryptedResponse: encryptedResponse, Signature: signature})
+}
+
+func TestAttestationVerifier(t *testing.T) {
+	service := newTestAttestationService(t)
+	enclave := newTestEnclave(t)
+
+	verifier, err := NewAttestationVerifier(service.rootCert, map[string]string{testChaincode: testMREnclave})
+	require.NoError(t, err)
+	assert.NoError(t, verifier.Verify(testChaincode, service.attest(t, enclave, testMREnclave)))
+
+	err = verifier.Verify("othercc", service.attest(t, enclave, testMREnclave))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no expected enclave measurement")
+
+	// Platforms which aren't up to date are only accepted if configured
+	service.quoteStatus = "GROUP_OUT_OF_DATE"
+	err = verifier.Verify(testChaincode, service.attest(t, enclave, testMREnclave))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "GROUP_OUT_OF_DATE")
+
+	verifier, err = NewAttestationVerifier(service.rootCert, map[string]string{testChaincode: testMREnclave},
+		WithQuoteStatuses(QuoteStatusOK, "GROUP_OUT_OF_DATE"))
+	require.NoError(t, err)
+	assert.NoError(t, verifier.Verify(testChaincode, service.attest(t, enclave, testMREnclave)))
+
+	// Report modified after it was signed
+	credentials := service.attest(t, enclave, testMREnclave)
+	report := &IASReport{}
+	require.NoError(t, json.Unmarshal(credentials.Evidence, report))
+	report.Body = bytes.Replace(report.Body, []byte("GROUP_OUT_OF_DATE"), []byte("OK"), 1)
+	credentials.Evidence, err = json.Marshal(report)
+	require.NoError(t, err)
+	err = verifier.Verify(testChaincode, credentials)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid signature")
+
+	_, err = NewAttestationVerifier([]byte("invalid"), nil)
+	assert.Error(t, err)
+}
+
+func TestReportData(t *testing.T) {
+	reportData := ReportData(&Credentials{EnclaveVK: []byte("key1"), ChaincodeEK: []byte("key2")})
+	assert.Len(t, reportData, sha256.Size)
+	assert.NotEqual(t, reportData, ReportData(&Credentials{EnclaveVK: []byte("key"), ChaincodeEK: []byte("1key2")}),
+		"expecting the boundary between the keys to be bound")
+	assert.NotEqual(t, reportData, ReportData(&Credentials{EnclaveVK: []byte("key2"), ChaincodeEK: []byte("key1")}))
+}
+
+// testAttestationService simulates the Intel Attestation Service. It issues attestation verification
+// reports signed with a report signing certificate issued by its root CA.
+type testAttestationService struct {
+	rootCert    []byte
+	key         *rsa.PrivateKey
+	cert        []byte
+	quoteStatus string
+}
+
+func newTestAttestationService(t *testing.T) *testAttestationService {
+	rootKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Attestation Report Signing CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, &rootKey.PublicKey, rootKey)
+	require.NoError(t, err)
+	root, err := x509.ParseCertificate(rootDER)
+	require.NoError(t, err)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "Attestation Report Signing"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, root, &key.PublicKey, rootKey)
+	require.NoError(t, err)
+
+	return &testAttestationService{
+		rootCert:    pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: rootDER}),
+		key:         key,
+		cert:        pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		quoteStatus: QuoteStatusOK,
+	}
+}
+
+// attest returns the credentials of the enclave with an attestation verification report of a quote
+// with the given measurement
+func (s *testAttestationService) attest(t *testing.T, e *testEnclave, mrEnclave string) *Credentials {
+	credentials := e.credentials(t, testMREnclave)
+
+	quote := make([]byte, quoteBodySize)
+	measurement, err := hex.DecodeString(mrEnclave)
+	require.NoError(t, err)
+	copy(quote[mrEnclaveOffset:], measurement)
+	copy(quote[reportDataOffset:], ReportData(credentials))
+
+	body, err := json.Marshal(&IASReportBody{
+		ID:                    "1",
+		Timestamp:             time.Now().UTC().Format("2006-01-02T15:04:05.000000"),
+		Version:               3,
+		IsvEnclaveQuoteStatus: s.quoteStatus,
+		IsvEnclaveQuoteBody:   base64.StdEncoding.EncodeToString(quote),
+	})
+	require.NoError(t, err)
+	digest := sha256.Sum256(body)
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+
+	evidence, err := json.Marshal(&IASReport{
+		Body:               body,
+		Signature:          base64.StdEncoding.EncodeToString(signature),
+		SigningCertificate: url.PathEscape(string(s.cert) + string(s.rootCert)),
+	})
+	require.NoError(t, err)
+	credentials.Evidence = evidence
+	return credentials
+}
+
+type mockRegistry struct {
+	credentials *Credentials
+	err         error
+	queries     int
+	chaincodes  []string
+}
+
+func (r *mockRegistry) Handle(requestContext *invoke.RequestContext, clientContext *invoke.ClientContext) {
+	r.queries++
+	if r.err != nil {
+		requestContext.Error = r.err
+		return
+	}
+	r.chaincodes = append(r.chaincodes, string(requestContext.Request.Args[0]))
+	payload, err := json.Marshal(r.credentials)
+	if err != nil {
+		requestContext.Error = err
+		return
+	}
+	requestContext.Response.Payload = payload
+}
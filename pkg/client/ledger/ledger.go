@@ -380,7 +380,9 @@ func (c *Client) createRequestContext(opts *requestOptions) (reqContext.Context,
 		opts.Timeouts[fab.PeerResponse] = c.ctx.EndpointConfig().Timeout(fab.PeerResponse)
 	}
 
-	return contextImpl.NewRequest(c.ctx, contextImpl.WithTimeout(opts.Timeouts[fab.PeerResponse]), contextImpl.WithParent(opts.ParentContext))
+	reqCtx, cancel := contextImpl.NewRequest(c.ctx, contextImpl.WithTimeout(opts.Timeouts[fab.PeerResponse]), contextImpl.WithParent(opts.ParentContext))
+	//Add timeout overrides here as a value so that they're used by the requests to the peers
+	return reqContext.WithValue(reqCtx, contextImpl.ReqContextTimeoutOverrides, opts.Timeouts), cancel
 }
 
 // filterTargets is helper method to filter peers
@@ -12,6 +12,7 @@ import (
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	"github.com/pkg/errors"
 )
@@ -124,6 +125,16 @@ func WithTimeout(timeoutType fab.TimeoutType, timeout time.Duration) RequestOpti
 	}
 }
 
+// WithCallTimeout overrides the configured timeouts of the given types for this call only, e.g.
+// WithCallTimeout(map[fab.TimeoutType]time.Duration{fab.PeerResponse: 10 * time.Second, fab.EndorserConnection: 5 * time.Second}).
+// Timeouts which aren't given are taken from the configuration.
+func WithCallTimeout(timeouts map[fab.TimeoutType]time.Duration) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		o.Timeouts = contextImpl.OverrideTimeouts(o.Timeouts, timeouts)
+		return nil
+	}
+}
+
 //WithParentContext encapsulates grpc context parent to Options
 func WithParentContext(parentContext reqContext.Context) RequestOption {
 	return func(ctx context.Client, o *requestOptions) error {
@@ -0,0 +1,260 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package lifecycle queries the _lifecycle system chaincode of peers which support the new chaincode
+// lifecycle (Fabric 2.x) and tracks the approvals of chaincode definitions by the organizations of a
+// channel, which allows multi-organization rollouts to be coordinated from the SDK.
+//
+//  channelClient, err := channel.New(channelProvider)
+//  ...
+//  client := lifecycle.New(channelClient)
+//  tracker, err := lifecycle.NewReadinessTracker(client, &lifecycle.ChaincodeDefinition{Name: "mycc", Version: "2.0", Sequence: 2})
+//  ...
+//  err = tracker.Start()
+//  ...
+//  for change := range tracker.Changes() {
+//      fmt.Printf("%s approved: %t\n", change.Org, change.Approved)
+//  }
+package lifecycle
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/features"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+const (
+	// ChaincodeID is the ID of the lifecycle system chaincode
+	ChaincodeID = "_lifecycle"
+
+	checkCommitReadinessFcn = "CheckCommitReadiness"
+	approveForMyOrgFcn      = "ApproveChaincodeDefinitionForMyOrg"
+	commitFcn               = "CommitChaincodeDefinition"
+	queryApprovedFcn        = "QueryApprovedChaincodeDefinition"
+)
+
+// ChannelClient is the subset of the channel client used to invoke the lifecycle system chaincode
+type ChannelClient interface {
+	Query(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+	Execute(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+}
+
+// ChaincodeDefinition is a definition of a chaincode which is approved by the organizations
+// of a channel before it is committed
+type ChaincodeDefinition struct {
+	Name                string
+	Version             string
+	Sequence            int64
+	EndorsementPlugin   string
+	ValidationPlugin    string
+	ValidationParameter []byte
+	Collections         *common.CollectionConfigPackage
+	InitRequired        bool
+}
+
+// ApprovedChaincodeDefinition is a chaincode definition approved by an organization, which may not be
+// committed yet
+type ApprovedChaincodeDefinition struct {
+	ChaincodeDefinition
+	// PackageID is the ID of the chaincode package approved with the definition; it's empty
+	// if the definition was approved without a package
+	PackageID string
+}
+
+// Client invokes the lifecycle system chaincode of a channel
+type Client struct {
+	channelClient ChannelClient
+	features      *features.Set
+}
+
+// ClientOption describes a functional parameter for the New constructor
+type ClientOption func(*Client)
+
+// WithFeatures sets the features of the channel. If the new lifecycle isn't supported
+// on the channel then the queries fail with a features.UnsupportedError without being sent.
+func WithFeatures(set *features.Set) ClientOption {
+	return func(c *Client) {
+		c.features = set
+	}
+}
+
+// New returns a client which queries the lifecycle system chaincode with the given channel client
+func New(channelClient ChannelClient, opts ...ClientOption) *Client {
+	c := &Client{channelClient: channelClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// CheckCommitReadiness returns whether each organization of the channel has approved the given
+// chaincode definition, by MSP ID
+func (c *Client) CheckCommitReadiness(definition *ChaincodeDefinition, options ...channel.RequestOption) (map[string]bool, error) {
+	if definition == nil || definition.Name == "" {
+		return nil, errors.New("chaincode name is required")
+	}
+	if err := c.checkSupported(); err != nil {
+		return nil, err
+	}
+
+	args, err := proto.Marshal(&checkCommitReadinessArgs{
+		Sequence:            definition.Sequence,
+		Name:                definition.Name,
+		Version:             definition.Version,
+		EndorsementPlugin:   definition.EndorsementPlugin,
+		ValidationPlugin:    definition.ValidationPlugin,
+		ValidationParameter: definition.ValidationParameter,
+		Collections:         definition.Collections,
+		InitRequired:        definition.InitRequired,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal CheckCommitReadiness arguments")
+	}
+
+	response, err := c.channelClient.Query(channel.Request{ChaincodeID: ChaincodeID, Fcn: checkCommitReadinessFcn, Args: [][]byte{args}}, options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "CheckCommitReadiness failed")
+	}
+
+	result := &checkCommitReadinessResult{}
+	if err := proto.Unmarshal(response.Payload, result); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal CheckCommitReadiness result")
+	}
+	approvals := result.Approvals
+	if approvals == nil {
+		approvals = make(map[string]bool)
+	}
+	return approvals, nil
+}
+
+// ApproveForMyOrg approves the given chaincode definition for the organization of the client. The package ID
+// is the ID of the chaincode package installed on the peers of the organization; if it's empty then the
+// definition is approved without a package, i.e. the organization doesn't endorse for the chaincode.
+// The transaction should be endorsed by peers of the organization of the client only.
+func (c *Client) ApproveForMyOrg(definition *ChaincodeDefinition, packageID string, options ...channel.RequestOption) (fab.TransactionID, error) {
+	if definition == nil || definition.Name == "" {
+		return fab.EmptyTransactionID, errors.New("chaincode name is required")
+	}
+	if err := c.checkSupported(); err != nil {
+		return fab.EmptyTransactionID, err
+	}
+
+	source := &chaincodeSource{Unavailable: &chaincodeSourceUnavailable{}}
+	if packageID != "" {
+		source = &chaincodeSource{LocalPackage: &chaincodeSourceLocal{PackageID: packageID}}
+	}
+
+	args, err := proto.Marshal(&approveChaincodeDefinitionForMyOrgArgs{
+		Sequence:            definition.Sequence,
+		Name:                definition.Name,
+		Version:             definition.Version,
+		EndorsementPlugin:   definition.EndorsementPlugin,
+		ValidationPlugin:    definition.ValidationPlugin,
+		ValidationParameter: definition.ValidationParameter,
+		Collections:         definition.Collections,
+		InitRequired:        definition.InitRequired,
+		Source:              source,
+	})
+	if err != nil {
+		return fab.EmptyTransactionID, errors.Wrap(err, "failed to marshal ApproveChaincodeDefinitionForMyOrg arguments")
+	}
+
+	response, err := c.channelClient.Execute(channel.Request{ChaincodeID: ChaincodeID, Fcn: approveForMyOrgFcn, Args: [][]byte{args}}, options...)
+	if err != nil {
+		return fab.EmptyTransactionID, errors.WithMessage(err, "ApproveChaincodeDefinitionForMyOrg failed")
+	}
+	return response.TransactionID, nil
+}
+
+// Commit commits the given chaincode definition on the channel once it's approved by enough organizations
+// (see CheckCommitReadiness). The transaction should be endorsed by peers of the approving organizations.
+func (c *Client) Commit(definition *ChaincodeDefinition, options ...channel.RequestOption) (fab.TransactionID, error) {
+	if definition == nil || definition.Name == "" {
+		return fab.EmptyTransactionID, errors.New("chaincode name is required")
+	}
+	if err := c.checkSupported(); err != nil {
+		return fab.EmptyTransactionID, err
+	}
+
+	args, err := proto.Marshal(&commitChaincodeDefinitionArgs{
+		Sequence:            definition.Sequence,
+		Name:                definition.Name,
+		Version:             definition.Version,
+		EndorsementPlugin:   definition.EndorsementPlugin,
+		ValidationPlugin:    definition.ValidationPlugin,
+		ValidationParameter: definition.ValidationParameter,
+		Collections:         definition.Collections,
+		InitRequired:        definition.InitRequired,
+	})
+	if err != nil {
+		return fab.EmptyTransactionID, errors.Wrap(err, "failed to marshal CommitChaincodeDefinition arguments")
+	}
+
+	response, err := c.channelClient.Execute(channel.Request{ChaincodeID: ChaincodeID, Fcn: commitFcn, Args: [][]byte{args}}, options...)
+	if err != nil {
+		return fab.EmptyTransactionID, errors.WithMessage(err, "CommitChaincodeDefinition failed")
+	}
+	return response.TransactionID, nil
+}
+
+// QueryApproved returns the chaincode definition with the given name and sequence approved by an organization,
+// along with the ID of the package approved with it. If the sequence is 0 then the definition with the latest
+// sequence approved by the organization is returned. The definitions approved by an organization are private
+// to the organization, so the query must be sent to peers of the organization (see channel.WithTargets) and
+// comparing the results of the organizations of a channel reveals approvals which don't match.
+func (c *Client) QueryApproved(name string, sequence int64, options ...channel.RequestOption) (*ApprovedChaincodeDefinition, error) {
+	if name == "" {
+		return nil, errors.New("chaincode name is required")
+	}
+	if err := c.checkSupported(); err != nil {
+		return nil, err
+	}
+
+	args, err := proto.Marshal(&queryApprovedChaincodeDefinitionArgs{
+		Name:     name,
+		Sequence: sequence,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal QueryApprovedChaincodeDefinition arguments")
+	}
+
+	response, err := c.channelClient.Query(channel.Request{ChaincodeID: ChaincodeID, Fcn: queryApprovedFcn, Args: [][]byte{args}}, options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "QueryApprovedChaincodeDefinition failed")
+	}
+
+	result := &queryApprovedChaincodeDefinitionResult{}
+	if err := proto.Unmarshal(response.Payload, result); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal QueryApprovedChaincodeDefinition result")
+	}
+	return &ApprovedChaincodeDefinition{
+		ChaincodeDefinition: ChaincodeDefinition{
+			Name:                name,
+			Version:             result.Version,
+			Sequence:            result.Sequence,
+			EndorsementPlugin:   result.EndorsementPlugin,
+			ValidationPlugin:    result.ValidationPlugin,
+			ValidationParameter: result.ValidationParameter,
+			Collections:         result.Collections,
+			InitRequired:        result.InitRequired,
+		},
+		PackageID: result.Source.GetLocalPackage().GetPackageID(),
+	}, nil
+}
+
+func (c *Client) checkSupported() error {
+	if c.features == nil {
+		return nil
+	}
+	return c.features.Check(features.NewLifecycle)
+}
@@ -0,0 +1,262 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/test/mockchannel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/features"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+)
+
+func TestCheckCommitReadiness(t *testing.T) {
+	channelClient := mockchannel.New()
+	channelClient.OnQuery(ChaincodeID, checkCommitReadinessFcn).Do(func(request channel.Request) (channel.Response, error) {
+		args := &checkCommitReadinessArgs{}
+		if err := proto.Unmarshal(request.Args[0], args); err != nil {
+			return channel.Response{}, err
+		}
+		if args.Name != "mycc" || args.Version != "2.0" || args.Sequence != 2 || !args.InitRequired {
+			return channel.Response{}, errors.Errorf("unexpected definition %s", args)
+		}
+		return newReadinessResponse(t, map[string]bool{"Org1MSP": true, "Org2MSP": false}), nil
+	})
+
+	client := New(channelClient)
+	approvals, err := client.CheckCommitReadiness(&ChaincodeDefinition{Name: "mycc", Version: "2.0", Sequence: 2, InitRequired: true})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"Org1MSP": true, "Org2MSP": false}, approvals)
+
+	_, err = client.CheckCommitReadiness(&ChaincodeDefinition{Version: "2.0"})
+	assert.Error(t, err, "expecting error for definition without name")
+
+	channelClient.Reset()
+	channelClient.OnQuery(ChaincodeID, checkCommitReadinessFcn).ReturnError(errors.New("access denied"))
+	_, err = client.CheckCommitReadiness(&ChaincodeDefinition{Name: "mycc"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "access denied")
+}
+
+func TestCheckCommitReadinessUnsupported(t *testing.T) {
+	channelClient := mockchannel.New()
+	channelClient.OnQuery(ChaincodeID, checkCommitReadinessFcn).ReturnResponse(newReadinessResponse(t, map[string]bool{"Org1MSP": true}))
+
+	cfg := mocks.NewMockChannelCfg("mychannel")
+	cfg.MockCapabilities = map[fab.ConfigGroupKey]map[string]bool{fab.ApplicationGroupKey: {fab.V1_4_2Capability: true}}
+	client := New(channelClient, WithFeatures(features.FromConfig(cfg)))
+	_, err := client.CheckCommitReadiness(&ChaincodeDefinition{Name: "mycc"})
+	require.Error(t, err)
+	assert.True(t, features.IsUnsupported(err))
+	assert.Empty(t, channelClient.Calls(), "expecting query not to be sent")
+
+	tracker, err := NewReadinessTracker(client, &ChaincodeDefinition{Name: "mycc"}, WithPollInterval(10*time.Millisecond))
+	require.NoError(t, err)
+	require.NoError(t, tracker.Start())
+	defer tracker.Stop()
+	_, ok := <-tracker.Changes()
+	assert.False(t, ok, "expecting tracker to stop")
+	assert.True(t, features.IsUnsupported(tracker.Status().Err))
+
+	cfg.MockCapabilities[fab.ApplicationGroupKey][fab.V2_0Capability] = true
+	client = New(channelClient, WithFeatures(features.FromConfig(cfg)))
+	approvals, err := client.CheckCommitReadiness(&ChaincodeDefinition{Name: "mycc"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"Org1MSP": true}, approvals)
+}
+
+func TestApproveForMyOrg(t *testing.T) {
+	channelClient := mockchannel.New()
+	channelClient.OnExecute(ChaincodeID, approveForMyOrgFcn).Do(func(request channel.Request) (channel.Response, error) {
+		args := &approveChaincodeDefinitionForMyOrgArgs{}
+		if err := proto.Unmarshal(request.Args[0], args); err != nil {
+			return channel.Response{}, err
+		}
+		if args.Name != "mycc" || args.Sequence != 2 || args.Source.GetLocalPackage().GetPackageID() != "mycc_2:1234" {
+			return channel.Response{}, errors.Errorf("unexpected definition %s", args)
+		}
+		return channel.Response{TransactionID: "txid1"}, nil
+	}).Times(1)
+	channelClient.OnExecute(ChaincodeID, approveForMyOrgFcn).Do(func(request channel.Request) (channel.Response, error) {
+		args := &approveChaincodeDefinitionForMyOrgArgs{}
+		if err := proto.Unmarshal(request.Args[0], args); err != nil {
+			return channel.Response{}, err
+		}
+		if args.Source.GetUnavailable() == nil || args.Source.GetLocalPackage() != nil {
+			return channel.Response{}, errors.Errorf("expecting definition without package: %s", args)
+		}
+		return channel.Response{TransactionID: "txid2"}, nil
+	})
+
+	client := New(channelClient)
+	txID, err := client.ApproveForMyOrg(&ChaincodeDefinition{Name: "mycc", Version: "2.0", Sequence: 2}, "mycc_2:1234")
+	require.NoError(t, err)
+	assert.Equal(t, fab.TransactionID("txid1"), txID)
+
+	txID, err = client.ApproveForMyOrg(&ChaincodeDefinition{Name: "mycc", Version: "2.0", Sequence: 2}, "")
+	require.NoError(t, err)
+	assert.Equal(t, fab.TransactionID("txid2"), txID)
+
+	_, err = client.ApproveForMyOrg(&ChaincodeDefinition{Version: "2.0"}, "mycc_2:1234")
+	assert.Error(t, err, "expecting error for definition without name")
+}
+
+func TestCommit(t *testing.T) {
+	channelClient := mockchannel.New()
+	channelClient.OnExecute(ChaincodeID, commitFcn).Do(func(request channel.Request) (channel.Response, error) {
+		args := &commitChaincodeDefinitionArgs{}
+		if err := proto.Unmarshal(request.Args[0], args); err != nil {
+			return channel.Response{}, err
+		}
+		if args.Name != "mycc" || args.Version != "2.0" || args.Sequence != 2 {
+			return channel.Response{}, errors.Errorf("unexpected definition %s", args)
+		}
+		return channel.Response{TransactionID: "txid1"}, nil
+	}).Times(1)
+	channelClient.OnExecute(ChaincodeID, commitFcn).ReturnError(errors.New("endorsement policy failure"))
+
+	client := New(channelClient)
+	txID, err := client.Commit(&ChaincodeDefinition{Name: "mycc", Version: "2.0", Sequence: 2})
+	require.NoError(t, err)
+	assert.Equal(t, fab.TransactionID("txid1"), txID)
+
+	_, err = client.Commit(&ChaincodeDefinition{Name: "mycc", Version: "2.0", Sequence: 2})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "endorsement policy failure")
+}
+
+func TestQueryApproved(t *testing.T) {
+	channelClient := mockchannel.New()
+	channelClient.OnQuery(ChaincodeID, queryApprovedFcn).Do(func(request channel.Request) (channel.Response, error) {
+		args := &queryApprovedChaincodeDefinitionArgs{}
+		if err := proto.Unmarshal(request.Args[0], args); err != nil {
+			return channel.Response{}, err
+		}
+		if args.Name != "mycc" || args.Sequence != 0 {
+			return channel.Response{}, errors.Errorf("unexpected arguments %s", args)
+		}
+		payload, err := proto.Marshal(&queryApprovedChaincodeDefinitionResult{
+			Sequence:     3,
+			Version:      "3.0",
+			InitRequired: true,
+			Source:       &chaincodeSource{LocalPackage: &chaincodeSourceLocal{PackageID: "mycc_3:5678"}},
+		})
+		if err != nil {
+			return channel.Response{}, err
+		}
+		return channel.Response{Payload: payload}, nil
+	}).Times(1)
+	channelClient.OnQuery(ChaincodeID, queryApprovedFcn).ReturnError(errors.New("could not fetch approved chaincode definition"))
+
+	client := New(channelClient)
+	definition, err := client.QueryApproved("mycc", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "mycc", definition.Name)
+	assert.Equal(t, "3.0", definition.Version)
+	assert.Equal(t, int64(3), definition.Sequence)
+	assert.True(t, definition.InitRequired)
+	assert.Equal(t, "mycc_3:5678", definition.PackageID)
+
+	_, err = client.QueryApproved("mycc", 4)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "could not fetch approved chaincode definition")
+
+	_, err = client.QueryApproved("", 0)
+	assert.Error(t, err, "expecting error without chaincode name")
+}
+
+func TestReadinessTracker(t *testing.T) {
+	channelClient := mockchannel.New()
+	channelClient.OnQuery(ChaincodeID, checkCommitReadinessFcn).ReturnResponse(newReadinessResponse(t, map[string]bool{"Org1MSP": true, "Org2MSP": false, "Org3MSP": false})).Times(1)
+	channelClient.OnQuery(ChaincodeID, checkCommitReadinessFcn).ReturnError(errors.New("peer unavailable")).Times(1)
+	channelClient.OnQuery(ChaincodeID, checkCommitReadinessFcn).ReturnResponse(newReadinessResponse(t, map[string]bool{"Org1MSP": true, "Org2MSP": true, "Org3MSP": false}))
+
+	tracker, err := NewReadinessTracker(New(channelClient), &ChaincodeDefinition{Name: "mycc", Version: "2.0", Sequence: 2}, WithPollInterval(10*time.Millisecond))
+	require.NoError(t, err)
+	require.NoError(t, tracker.Start())
+	assert.Error(t, tracker.Start(), "expecting error when starting twice")
+
+	var changes []*ApprovalChange
+	for len(changes) < 4 {
+		select {
+		case change := <-tracker.Changes():
+			changes = append(changes, change)
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for changes; received %d", len(changes))
+		}
+	}
+	assert.Equal(t, "Org1MSP", changes[0].Org)
+	assert.True(t, changes[0].Approved)
+	assert.Equal(t, "Org2MSP", changes[1].Org)
+	assert.False(t, changes[1].Approved)
+	assert.Equal(t, "Org3MSP", changes[2].Org)
+	assert.False(t, changes[2].Ready)
+	assert.Equal(t, "Org2MSP", changes[3].Org)
+	assert.True(t, changes[3].Approved)
+	assert.True(t, changes[3].Ready, "expecting definition to be ready with a majority of approvals")
+
+	select {
+	case <-tracker.Ready():
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for readiness")
+	}
+
+	status := tracker.Status()
+	assert.True(t, status.Ready)
+	assert.Equal(t, []string{"Org1MSP", "Org2MSP"}, status.Approved())
+	assert.Equal(t, []string{"Org3MSP"}, status.Pending())
+
+	tracker.Stop()
+	for range tracker.Changes() {
+	}
+	tracker.Stop()
+}
+
+func TestReadinessTrackerPolicy(t *testing.T) {
+	approvals := map[string]bool{"Org1MSP": true, "Org2MSP": true, "Org3MSP": false}
+	assert.True(t, MajorityPolicy(approvals))
+	assert.False(t, AllPolicy(approvals))
+	assert.False(t, MajorityPolicy(map[string]bool{"Org1MSP": true, "Org2MSP": false}))
+	assert.False(t, MajorityPolicy(nil))
+	assert.False(t, AllPolicy(nil))
+
+	channelClient := mockchannel.New()
+	channelClient.OnQuery(ChaincodeID, checkCommitReadinessFcn).ReturnResponse(newReadinessResponse(t, approvals))
+	tracker, err := NewReadinessTracker(New(channelClient), &ChaincodeDefinition{Name: "mycc"}, WithReadyPolicy(AllPolicy), WithPollInterval(10*time.Millisecond))
+	require.NoError(t, err)
+	require.NoError(t, tracker.Start())
+	defer tracker.Stop()
+
+	for i := 0; i < 3; i++ {
+		change := <-tracker.Changes()
+		assert.False(t, change.Ready)
+	}
+	select {
+	case <-tracker.Ready():
+		t.Fatal("expecting definition not to be ready without all approvals")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	_, err = NewReadinessTracker(nil, &ChaincodeDefinition{Name: "mycc"})
+	assert.Error(t, err)
+	_, err = NewReadinessTracker(New(channelClient), &ChaincodeDefinition{})
+	assert.Error(t, err)
+}
+
+func newReadinessResponse(t *testing.T, approvals map[string]bool) channel.Response {
+	payload, err := proto.Marshal(&checkCommitReadinessResult{Approvals: approvals})
+	require.NoError(t, err)
+	return channel.Response{Payload: payload}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"github.com/golang/protobuf/proto"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+// The messages below are the subset of the messages of the _lifecycle system chaincode
+// (fabric/protos/peer/lifecycle/lifecycle.proto) used by this package, since the protos
+// of this version of Fabric don't include the new chaincode lifecycle.
+
+// checkCommitReadinessArgs is the message sent to invoke CheckCommitReadiness
+type checkCommitReadinessArgs struct {
+	Sequence            int64                           `protobuf:"varint,1,opt,name=sequence" json:"sequence,omitempty"`
+	Name                string                          `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	Version             string                          `protobuf:"bytes,3,opt,name=version" json:"version,omitempty"`
+	EndorsementPlugin   string                          `protobuf:"bytes,4,opt,name=endorsement_plugin,json=endorsementPlugin" json:"endorsement_plugin,omitempty"`
+	ValidationPlugin    string                          `protobuf:"bytes,5,opt,name=validation_plugin,json=validationPlugin" json:"validation_plugin,omitempty"`
+	ValidationParameter []byte                          `protobuf:"bytes,6,opt,name=validation_parameter,json=validationParameter,proto3" json:"validation_parameter,omitempty"`
+	Collections         *common.CollectionConfigPackage `protobuf:"bytes,7,opt,name=collections" json:"collections,omitempty"`
+	InitRequired        bool                            `protobuf:"varint,8,opt,name=init_required,json=initRequired" json:"init_required,omitempty"`
+}
+
+func (m *checkCommitReadinessArgs) Reset()         { *m = checkCommitReadinessArgs{} }
+func (m *checkCommitReadinessArgs) String() string { return proto.CompactTextString(m) }
+func (*checkCommitReadinessArgs) ProtoMessage()    {}
+
+// checkCommitReadinessResult is the message returned by CheckCommitReadiness
+type checkCommitReadinessResult struct {
+	Approvals map[string]bool `protobuf:"bytes,1,rep,name=approvals" json:"approvals,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+}
+
+func (m *checkCommitReadinessResult) Reset()         { *m = checkCommitReadinessResult{} }
+func (m *checkCommitReadinessResult) String() string { return proto.CompactTextString(m) }
+func (*checkCommitReadinessResult) ProtoMessage()    {}
+
+// approveChaincodeDefinitionForMyOrgArgs is the message sent to invoke ApproveChaincodeDefinitionForMyOrg
+type approveChaincodeDefinitionForMyOrgArgs struct {
+	Sequence            int64                           `protobuf:"varint,1,opt,name=sequence" json:"sequence,omitempty"`
+	Name                string                          `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	Version             string                          `protobuf:"bytes,3,opt,name=version" json:"version,omitempty"`
+	EndorsementPlugin   string                          `protobuf:"bytes,4,opt,name=endorsement_plugin,json=endorsementPlugin" json:"endorsement_plugin,omitempty"`
+	ValidationPlugin    string                          `protobuf:"bytes,5,opt,name=validation_plugin,json=validationPlugin" json:"validation_plugin,omitempty"`
+	ValidationParameter []byte                          `protobuf:"bytes,6,opt,name=validation_parameter,json=validationParameter,proto3" json:"validation_parameter,omitempty"`
+	Collections         *common.CollectionConfigPackage `protobuf:"bytes,7,opt,name=collections" json:"collections,omitempty"`
+	InitRequired        bool                            `protobuf:"varint,8,opt,name=init_required,json=initRequired" json:"init_required,omitempty"`
+	Source              *chaincodeSource                `protobuf:"bytes,9,opt,name=source" json:"source,omitempty"`
+}
+
+func (m *approveChaincodeDefinitionForMyOrgArgs) Reset() {
+	*m = approveChaincodeDefinitionForMyOrgArgs{}
+}
+func (m *approveChaincodeDefinitionForMyOrgArgs) String() string { return proto.CompactTextString(m) }
+func (*approveChaincodeDefinitionForMyOrgArgs) ProtoMessage()    {}
+
+// chaincodeSource is the package of an approved definition. In the lifecycle protos the fields are
+// a oneof, which is encoded the same way as the optional fields below.
+type chaincodeSource struct {
+	Unavailable  *chaincodeSourceUnavailable `protobuf:"bytes,1,opt,name=unavailable" json:"unavailable,omitempty"`
+	LocalPackage *chaincodeSourceLocal       `protobuf:"bytes,2,opt,name=local_package,json=localPackage" json:"local_package,omitempty"`
+}
+
+func (m *chaincodeSource) Reset()         { *m = chaincodeSource{} }
+func (m *chaincodeSource) String() string { return proto.CompactTextString(m) }
+func (*chaincodeSource) ProtoMessage()    {}
+
+func (m *chaincodeSource) GetUnavailable() *chaincodeSourceUnavailable {
+	if m != nil {
+		return m.Unavailable
+	}
+	return nil
+}
+
+func (m *chaincodeSource) GetLocalPackage() *chaincodeSourceLocal {
+	if m != nil {
+		return m.LocalPackage
+	}
+	return nil
+}
+
+// chaincodeSourceUnavailable is the source of a definition approved without a package
+type chaincodeSourceUnavailable struct {
+}
+
+func (m *chaincodeSourceUnavailable) Reset()         { *m = chaincodeSourceUnavailable{} }
+func (m *chaincodeSourceUnavailable) String() string { return proto.CompactTextString(m) }
+func (*chaincodeSourceUnavailable) ProtoMessage()    {}
+
+// chaincodeSourceLocal is the source of a definition approved with an installed package
+type chaincodeSourceLocal struct {
+	PackageID string `protobuf:"bytes,1,opt,name=package_id,json=packageId" json:"package_id,omitempty"`
+}
+
+func (m *chaincodeSourceLocal) Reset()         { *m = chaincodeSourceLocal{} }
+func (m *chaincodeSourceLocal) String() string { return proto.CompactTextString(m) }
+func (*chaincodeSourceLocal) ProtoMessage()    {}
+
+func (m *chaincodeSourceLocal) GetPackageID() string {
+	if m != nil {
+		return m.PackageID
+	}
+	return ""
+}
+
+// commitChaincodeDefinitionArgs is the message sent to invoke CommitChaincodeDefinition
+type commitChaincodeDefinitionArgs struct {
+	Sequence            int64                           `protobuf:"varint,1,opt,name=sequence" json:"sequence,omitempty"`
+	Name                string                          `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	Version             string                          `protobuf:"bytes,3,opt,name=version" json:"version,omitempty"`
+	EndorsementPlugin   string                          `protobuf:"bytes,4,opt,name=endorsement_plugin,json=endorsementPlugin" json:"endorsement_plugin,omitempty"`
+	ValidationPlugin    string                          `protobuf:"bytes,5,opt,name=validation_plugin,json=validationPlugin" json:"validation_plugin,omitempty"`
+	ValidationParameter []byte                          `protobuf:"bytes,6,opt,name=validation_parameter,json=validationParameter,proto3" json:"validation_parameter,omitempty"`
+	Collections         *common.CollectionConfigPackage `protobuf:"bytes,7,opt,name=collections" json:"collections,omitempty"`
+	InitRequired        bool                            `protobuf:"varint,8,opt,name=init_required,json=initRequired" json:"init_required,omitempty"`
+}
+
+func (m *commitChaincodeDefinitionArgs) Reset()         { *m = commitChaincodeDefinitionArgs{} }
+func (m *commitChaincodeDefinitionArgs) String() string { return proto.CompactTextString(m) }
+func (*commitChaincodeDefinitionArgs) ProtoMessage()    {}
+
+// queryApprovedChaincodeDefinitionArgs is the message sent to invoke QueryApprovedChaincodeDefinition
+type queryApprovedChaincodeDefinitionArgs struct {
+	Name     string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Sequence int64  `protobuf:"varint,2,opt,name=sequence" json:"sequence,omitempty"`
+}
+
+func (m *queryApprovedChaincodeDefinitionArgs) Reset() {
+	*m = queryApprovedChaincodeDefinitionArgs{}
+}
+func (m *queryApprovedChaincodeDefinitionArgs) String() string { return proto.CompactTextString(m) }
+func (*queryApprovedChaincodeDefinitionArgs) ProtoMessage()    {}
+
+// queryApprovedChaincodeDefinitionResult is the message returned by QueryApprovedChaincodeDefinition
+type queryApprovedChaincodeDefinitionResult struct {
+	Sequence            int64                           `protobuf:"varint,1,opt,name=sequence" json:"sequence,omitempty"`
+	Version             string                          `protobuf:"bytes,2,opt,name=version" json:"version,omitempty"`
+	EndorsementPlugin   string                          `protobuf:"bytes,3,opt,name=endorsement_plugin,json=endorsementPlugin" json:"endorsement_plugin,omitempty"`
+	ValidationPlugin    string                          `protobuf:"bytes,4,opt,name=validation_plugin,json=validationPlugin" json:"validation_plugin,omitempty"`
+	ValidationParameter []byte                          `protobuf:"bytes,5,opt,name=validation_parameter,json=validationParameter,proto3" json:"validation_parameter,omitempty"`
+	Collections         *common.CollectionConfigPackage `protobuf:"bytes,6,opt,name=collections" json:"collections,omitempty"`
+	InitRequired        bool                            `protobuf:"varint,7,opt,name=init_required,json=initRequired" json:"init_required,omitempty"`
+	Source              *chaincodeSource                `protobuf:"bytes,8,opt,name=source" json:"source,omitempty"`
+}
+
+func (m *queryApprovedChaincodeDefinitionResult) Reset() {
+	*m = queryApprovedChaincodeDefinitionResult{}
+}
+func (m *queryApprovedChaincodeDefinitionResult) String() string { return proto.CompactTextString(m) }
+func (*queryApprovedChaincodeDefinitionResult) ProtoMessage()    {}
@@ -0,0 +1,297 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/features"
+)
+
+const (
+	defaultPollInterval = 5 * time.Second
+	defaultBufferSize   = 100
+)
+
+// ReadinessQuerier returns the approvals of a chaincode definition by MSP ID (see Client.CheckCommitReadiness)
+type ReadinessQuerier interface {
+	CheckCommitReadiness(definition *ChaincodeDefinition, options ...channel.RequestOption) (map[string]bool, error)
+}
+
+// ReadinessStatus is the approval status of a chaincode definition
+type ReadinessStatus struct {
+	// Approvals holds whether each organization approved the definition, by MSP ID
+	Approvals map[string]bool
+	// Ready is true if the definition may be committed (see WithReadyPolicy)
+	Ready bool
+	// Time is the time of the last successful poll
+	Time time.Time
+	// Err is the error of the last poll, if it failed
+	Err error
+}
+
+// Approved returns the MSP IDs of the organizations which approved the definition, sorted
+func (s *ReadinessStatus) Approved() []string {
+	return s.orgs(true)
+}
+
+// Pending returns the MSP IDs of the organizations which haven't approved the definition, sorted
+func (s *ReadinessStatus) Pending() []string {
+	return s.orgs(false)
+}
+
+func (s *ReadinessStatus) orgs(approved bool) []string {
+	var orgs []string
+	for org, a := range s.Approvals {
+		if a == approved {
+			orgs = append(orgs, org)
+		}
+	}
+	sort.Strings(orgs)
+	return orgs
+}
+
+// ApprovalChange notifies a change of the approval of an organization. The first poll
+// notifies the approval of each organization.
+type ApprovalChange struct {
+	Org      string
+	Approved bool
+	// Ready is true if the definition may be committed after the change
+	Ready bool
+	Time  time.Time
+}
+
+// ReadyPolicy returns true if a definition with the given approvals may be committed
+type ReadyPolicy func(approvals map[string]bool) bool
+
+// MajorityPolicy is satisfied if a majority of the organizations approved the definition,
+// as with the default lifecycle endorsement policy of a channel
+func MajorityPolicy(approvals map[string]bool) bool {
+	approved := 0
+	for _, a := range approvals {
+		if a {
+			approved++
+		}
+	}
+	return len(approvals) > 0 && approved > len(approvals)/2
+}
+
+// AllPolicy is satisfied if all the organizations approved the definition
+func AllPolicy(approvals map[string]bool) bool {
+	for _, a := range approvals {
+		if !a {
+			return false
+		}
+	}
+	return len(approvals) > 0
+}
+
+// ReadinessTracker polls the commit readiness of a chaincode definition and notifies the changes
+// of the approvals of the organizations
+type ReadinessTracker struct {
+	querier     ReadinessQuerier
+	definition  *ChaincodeDefinition
+	interval    time.Duration
+	policy      ReadyPolicy
+	requestOpts []channel.RequestOption
+	changes     chan *ApprovalChange
+	ready       chan struct{}
+	stop        chan struct{}
+	done        chan struct{}
+	lock        sync.RWMutex
+	status      *ReadinessStatus
+	isReady     bool
+	started     bool
+	stopped     bool
+}
+
+// TrackerOption describes a functional parameter for the NewReadinessTracker constructor
+type TrackerOption func(*ReadinessTracker)
+
+// WithPollInterval sets the interval between two polls (5s by default)
+func WithPollInterval(interval time.Duration) TrackerOption {
+	return func(t *ReadinessTracker) {
+		t.interval = interval
+	}
+}
+
+// WithReadyPolicy sets the policy which determines whether the definition may be committed.
+// The default is MajorityPolicy; AllPolicy or a policy matching a custom lifecycle endorsement
+// policy of the channel may be used instead.
+func WithReadyPolicy(policy ReadyPolicy) TrackerOption {
+	return func(t *ReadinessTracker) {
+		t.policy = policy
+	}
+}
+
+// WithChangesBufferSize sets the size of the buffer of the changes channel (100 by default)
+func WithChangesBufferSize(size int) TrackerOption {
+	return func(t *ReadinessTracker) {
+		t.changes = make(chan *ApprovalChange, size)
+	}
+}
+
+// WithRequestOptions sets the options of the CheckCommitReadiness queries (e.g. the target peers)
+func WithRequestOptions(options ...channel.RequestOption) TrackerOption {
+	return func(t *ReadinessTracker) {
+		t.requestOpts = options
+	}
+}
+
+// NewReadinessTracker returns a tracker of the approvals of the given definition. The querier is typically a Client.
+func NewReadinessTracker(querier ReadinessQuerier, definition *ChaincodeDefinition, opts ...TrackerOption) (*ReadinessTracker, error) {
+	if querier == nil {
+		return nil, errors.New("querier is required")
+	}
+	if definition == nil || definition.Name == "" {
+		return nil, errors.New("chaincode name is required")
+	}
+
+	t := &ReadinessTracker{
+		querier:    querier,
+		definition: definition,
+		interval:   defaultPollInterval,
+		policy:     MajorityPolicy,
+		changes:    make(chan *ApprovalChange, defaultBufferSize),
+		ready:      make(chan struct{}),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+		status:     &ReadinessStatus{},
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t, nil
+}
+
+// Start starts polling
+func (t *ReadinessTracker) Start() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.started {
+		return errors.New("readiness tracker already started")
+	}
+	t.started = true
+
+	go t.run()
+	return nil
+}
+
+// Stop stops polling and closes the changes channel
+func (t *ReadinessTracker) Stop() {
+	t.lock.Lock()
+	if !t.started || t.stopped {
+		t.lock.Unlock()
+		return
+	}
+	t.stopped = true
+	close(t.stop)
+	t.lock.Unlock()
+
+	<-t.done
+}
+
+// Changes returns the channel of the changes of the approvals. The channel is closed when the tracker stops,
+// or when the new lifecycle isn't supported on the channel (see Status().Err).
+func (t *ReadinessTracker) Changes() <-chan *ApprovalChange {
+	return t.changes
+}
+
+// Ready returns a channel which is closed when the definition is ready to be committed
+func (t *ReadinessTracker) Ready() <-chan struct{} {
+	return t.ready
+}
+
+// Status returns the status of the last poll
+func (t *ReadinessTracker) Status() *ReadinessStatus {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	status := *t.status
+	status.Approvals = make(map[string]bool, len(t.status.Approvals))
+	for org, approved := range t.status.Approvals {
+		status.Approvals[org] = approved
+	}
+	return &status
+}
+
+func (t *ReadinessTracker) run() {
+	defer close(t.done)
+	defer close(t.changes)
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		if !t.poll() {
+			return
+		}
+		select {
+		case <-t.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll queries the approvals and notifies the changes. It returns false if the tracker was stopped
+// or if the new lifecycle isn't supported on the channel.
+func (t *ReadinessTracker) poll() bool {
+	approvals, err := t.querier.CheckCommitReadiness(t.definition, t.requestOpts...)
+	if features.IsUnsupported(err) {
+		// Polling won't succeed until the channel configuration is updated
+		logger.Warnf("Readiness tracker of chaincode [%s] stopped: %s", t.definition.Name, err)
+		t.lock.Lock()
+		t.status.Err = err
+		t.lock.Unlock()
+		return false
+	}
+	if err != nil {
+		logger.Warnf("Commit readiness of chaincode [%s] sequence %d not checked: %s", t.definition.Name, t.definition.Sequence, err)
+		t.lock.Lock()
+		t.status.Err = err
+		t.lock.Unlock()
+		return true
+	}
+
+	now := time.Now()
+	ready := t.policy(approvals)
+
+	t.lock.Lock()
+	previous := t.status
+	first := previous.Time.IsZero()
+	t.status = &ReadinessStatus{Approvals: approvals, Ready: ready, Time: now}
+	if ready && !t.isReady {
+		t.isReady = true
+		close(t.ready)
+	}
+	t.lock.Unlock()
+
+	orgs := make([]string, 0, len(approvals))
+	for org := range approvals {
+		orgs = append(orgs, org)
+	}
+	sort.Strings(orgs)
+	for _, org := range orgs {
+		approved := approvals[org]
+		if prev, ok := previous.Approvals[org]; !first && ok && prev == approved {
+			continue
+		}
+		select {
+		case t.changes <- &ApprovalChange{Org: org, Approved: approved, Ready: ready, Time: now}:
+		case <-t.stop:
+			return false
+		}
+	}
+	return true
+}
@@ -6,6 +6,27 @@ SPDX-License-Identifier: Apache-2.0
 
 package msp
 
+import (
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+)
+
+// Types of the identities registered with the CA. The CA may be configured with other types.
+const (
+	IdentityTypePeer    = "peer"
+	IdentityTypeOrderer = "orderer"
+	IdentityTypeClient  = "client"
+	IdentityTypeAdmin   = "admin"
+	IdentityTypeUser    = "user"
+)
+
+// Credentials are the certificate and private key of a user enrolled with a profile of the CA
+type Credentials struct {
+	Certificate []byte
+	PrivateKey  core.Key
+}
+
 // AttributeRequest is a request for an attribute.
 type AttributeRequest struct {
 	Name     string
@@ -16,13 +37,17 @@ type AttributeRequest struct {
 type RegistrationRequest struct {
 	// Name is the unique name of the identity
 	Name string
-	// Type of identity being registered (e.g. "peer, app, user")
+	// Type of identity being registered (e.g. IdentityTypePeer)
 	Type string
 	// MaxEnrollments is the number of times the secret can  be reused to enroll.
-	// if omitted, this defaults to max_enrollments configured on the server
+	// if omitted, this defaults to max_enrollments configured on the server.
+	// -1 allows an unlimited number of enrollments.
 	MaxEnrollments int
 	// The identity's affiliation e.g. org1.department1
 	Affiliation string
+	// CreateAffiliation creates the affiliation (and its parent affiliations) before registering
+	// the identity, if it doesn't exist. The registrar must be allowed to manage affiliations.
+	CreateAffiliation bool
 	// Optional attributes associated with this identity
 	Attributes []Attribute
 	// CAName is the name of the CA to connect to
@@ -37,6 +62,8 @@ type RegistrationRequest struct {
 type Attribute struct {
 	Name  string
 	Value string
+	// ECert adds the attribute to the enrollment certificates of the identity by default,
+	// i.e. when the enrollment doesn't request attributes
 	ECert bool
 }
 
@@ -72,3 +99,59 @@ type RevokedCert struct {
 	// AKI of the revoked certificate
 	AKI string
 }
+
+// GetCAInfoResponse is the response of the GetCAInfo call
+type GetCAInfoResponse struct {
+	// CAName is the name of the CA
+	CAName string
+	// CAChain is the PEM-encoded bytes of the CA chain. The first certificate of the chain is the root CA certificate.
+	CAChain []byte
+	// Version of the CA server
+	Version string
+}
+
+// IdemixIssuerKeys are the public keys of the idemix issuer of a CA, with which the idemix
+// credentials issued by the CA are verified
+type IdemixIssuerKeys struct {
+	// IssuerPublicKey is the serialized idemix issuer public key
+	IssuerPublicKey []byte
+	// RevocationPublicKey is the PEM-encoded revocation public key of the issuer
+	RevocationPublicKey []byte
+}
+
+// IdentityEventType is the type of an identity event
+type IdentityEventType string
+
+const (
+	// EnrollEvent is published when an identity is enrolled
+	EnrollEvent IdentityEventType = "enroll"
+	// ReenrollEvent is published when an identity is re-enrolled
+	ReenrollEvent IdentityEventType = "reenroll"
+	// RevokeEvent is published when certificates are revoked
+	RevokeEvent IdentityEventType = "revoke"
+)
+
+// IdentityEvent is published when an identity is successfully enrolled, re-enrolled or revoked with the CA
+type IdentityEvent struct {
+	Type IdentityEventType
+	// ID is the enrollment ID of the identity. It is empty for a revocation by serial and AKI.
+	ID string
+	// MSPID is the MSP ID under which the enrollment certificate is stored
+	MSPID string
+	// Profile is the CA profile of the enrollment, if any
+	Profile string
+	// Certificate is the PEM-encoded enrollment certificate (enrollment and re-enrollment)
+	Certificate []byte
+	// Serial is the serial number of the enrollment certificate, in hex (enrollment and re-enrollment)
+	Serial string
+	// NotAfter is the expiry of the enrollment certificate (enrollment and re-enrollment)
+	NotAfter time.Time
+	// RevokedCerts are the revoked certificates (revocation)
+	RevokedCerts []RevokedCert
+	// Reason is the reason of the revocation
+	Reason string
+}
+
+// IdentityEventHandler is notified of identity events, e.g. to keep an inventory of identities
+// or a secrets manager in sync. Handlers are invoked synchronously, so they should return quickly.
+type IdentityEventHandler func(event *IdentityEvent)
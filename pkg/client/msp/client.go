@@ -7,17 +7,26 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"strings"
+	"sync"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	mspctx "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/cryptoutil"
 	"github.com/hyperledger/fabric-sdk-go/pkg/msp"
 	mspapi "github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/certexpiry"
 	"github.com/pkg/errors"
 )
 
 // Client enables access to Client services
 type Client struct {
-	orgName string
-	ctx     context.Client
+	orgName       string
+	ctx           context.Client
+	lock          sync.RWMutex
+	caChain       []byte
+	revokedUsers  msp.RevokedUserCleanup
+	eventHandlers []IdentityEventHandler
 }
 
 // ClientOption describes a functional parameter for the New constructor
@@ -31,6 +40,46 @@ func WithOrg(orgName string) ClientOption {
 	}
 }
 
+// WithPinnedCAChain option pins the given PEM-encoded CA chain (e.g. previously retrieved
+// with GetCAInfo): the requests to the CA fail with ErrCAChainMismatch if the CA returns
+// another CA chain
+func WithPinnedCAChain(caChain []byte) ClientOption {
+	return func(msp *Client) error {
+		msp.caChain = caChain
+		return nil
+	}
+}
+
+// WithRevokedUsersMarked option marks the users of the user store whose enrollment certificates
+// are revoked with Revoke: GetSigningIdentity then returns ErrUserRevoked for them
+func WithRevokedUsersMarked() ClientOption {
+	return func(c *Client) error {
+		c.revokedUsers = msp.MarkRevokedUsers
+		return nil
+	}
+}
+
+// WithRevokedUsersPurged option deletes the users of the user store whose enrollment certificates
+// are revoked with Revoke, and their private keys from the key store
+func WithRevokedUsersPurged() ClientOption {
+	return func(c *Client) error {
+		c.revokedUsers = msp.PurgeRevokedUsers
+		return nil
+	}
+}
+
+// WithIdentityEventHandler option registers a handler which is notified when an identity is
+// successfully enrolled, re-enrolled or revoked through the client
+func WithIdentityEventHandler(handler IdentityEventHandler) ClientOption {
+	return func(c *Client) error {
+		if handler == nil {
+			return errors.New("identity event handler is nil")
+		}
+		c.eventHandlers = append(c.eventHandlers, handler)
+		return nil
+	}
+}
+
 // New creates a new Client instance
 func New(clientProvider context.ClientProvider, opts ...ClientOption) (*Client, error) {
 
@@ -61,9 +110,9 @@ func New(clientProvider context.ClientProvider, opts ...ClientOption) (*Client,
 	return &msp, nil
 }
 
-func newCAClient(ctx context.Client, orgName string) (mspapi.CAClient, error) {
+func newCAClient(ctx context.Client, orgName string, opts ...msp.CAClientOption) (mspapi.CAClient, error) {
 
-	caClient, err := msp.NewCAClient(orgName, ctx)
+	caClient, err := msp.NewCAClient(orgName, ctx, opts...)
 	if err != nil {
 		return nil, errors.WithMessage(err, "failed to create CA Client")
 	}
@@ -73,7 +122,9 @@ func newCAClient(ctx context.Client, orgName string) (mspapi.CAClient, error) {
 
 // enrollmentOptions represent enrollment options
 type enrollmentOptions struct {
-	secret string
+	secret  string
+	caName  string
+	profile string
 }
 
 // EnrollmentOption describes a functional parameter for Enroll
@@ -87,6 +138,25 @@ func WithSecret(secret string) EnrollmentOption {
 	}
 }
 
+// WithCAName enrollment option to enroll with the CA of the given name, for a CA server which
+// hosts several CAs
+func WithCAName(caName string) EnrollmentOption {
+	return func(o *enrollmentOptions) error {
+		o.caName = caName
+		return nil
+	}
+}
+
+// WithProfile enrollment option to enroll with the given signing profile of the CA, e.g. "tls" to
+// obtain a TLS certificate. The certificate enrolled with a profile doesn't replace the enrollment
+// certificate of the user; it is retrieved by calling GetProfileCredentials.
+func WithProfile(profile string) EnrollmentOption {
+	return func(o *enrollmentOptions) error {
+		o.profile = profile
+		return nil
+	}
+}
+
 // Enroll enrolls a registered user in order to receive a signed X509 certificate.
 // A new key pair is generated for the user. The private key and the
 // enrollment certificate issued by the CA are stored in SDK stores.
@@ -104,16 +174,21 @@ func (c *Client) Enroll(enrollmentID string, opts ...EnrollmentOption) error {
 		}
 	}
 
-	ca, err := newCAClient(c.ctx, c.orgName)
+	ca, err := newCAClient(c.ctx, c.orgName, c.caClientOptions()...)
 	if err != nil {
 		return err
 	}
-	return ca.Enroll(enrollmentID, eo.secret)
+	return ca.Enroll(&mspapi.EnrollmentRequest{
+		Name:    enrollmentID,
+		Secret:  eo.secret,
+		CAName:  eo.caName,
+		Profile: eo.profile,
+	})
 }
 
 // Reenroll reenrolls an enrolled user in order to obtain a new signed X509 certificate
 func (c *Client) Reenroll(enrollmentID string) error {
-	ca, err := newCAClient(c.ctx, c.orgName)
+	ca, err := newCAClient(c.ctx, c.orgName, c.caClientOptions()...)
 	if err != nil {
 		return err
 	}
@@ -124,7 +199,7 @@ func (c *Client) Reenroll(enrollmentID string) error {
 // request: Registration Request
 // Returns Enrolment Secret
 func (c *Client) Register(request *RegistrationRequest) (string, error) {
-	ca, err := newCAClient(c.ctx, c.orgName)
+	ca, err := newCAClient(c.ctx, c.orgName, c.caClientOptions()...)
 	if err != nil {
 		return "", err
 	}
@@ -135,13 +210,14 @@ func (c *Client) Register(request *RegistrationRequest) (string, error) {
 	}
 
 	r := mspapi.RegistrationRequest{
-		Name:           request.Name,
-		Type:           request.Type,
-		MaxEnrollments: request.MaxEnrollments,
-		Affiliation:    request.Affiliation,
-		Attributes:     a,
-		CAName:         request.CAName,
-		Secret:         request.Secret,
+		Name:              request.Name,
+		Type:              request.Type,
+		MaxEnrollments:    request.MaxEnrollments,
+		Affiliation:       request.Affiliation,
+		CreateAffiliation: request.CreateAffiliation,
+		Attributes:        a,
+		CAName:            request.CAName,
+		Secret:            request.Secret,
 	}
 	return ca.Register(&r)
 }
@@ -149,7 +225,7 @@ func (c *Client) Register(request *RegistrationRequest) (string, error) {
 // Revoke revokes a User with the Fabric CA
 // request: Revocation Request
 func (c *Client) Revoke(request *RevocationRequest) (*RevocationResponse, error) {
-	ca, err := newCAClient(c.ctx, c.orgName)
+	ca, err := newCAClient(c.ctx, c.orgName, c.caClientOptions()...)
 	if err != nil {
 		return nil, err
 	}
@@ -182,7 +258,220 @@ func (c *Client) GetSigningIdentity(id string) (mspctx.SigningIdentity, error) {
 		if err == mspctx.ErrUserNotFound {
 			return nil, ErrUserNotFound
 		}
+		if err == mspctx.ErrUserRevoked {
+			return nil, ErrUserRevoked
+		}
+		return nil, err
+	}
+	return si, nil
+}
+
+// ListUsers returns the IDs of the users of the organization which are enrolled in the user store
+// or embedded in the configuration
+func (c *Client) ListUsers() ([]string, error) {
+	lookup, err := c.identityLookup()
+	if err != nil {
+		return nil, err
+	}
+	return lookup.ListUsers()
+}
+
+// GetSigningIdentityByCert returns the signing identity of the user with the given
+// (PEM encoded) enrollment certificate
+func (c *Client) GetSigningIdentityByCert(cert []byte) (mspctx.SigningIdentity, error) {
+	lookup, err := c.identityLookup()
+	if err != nil {
+		return nil, err
+	}
+	si, err := lookup.GetSigningIdentityByCert(cert)
+	if err != nil {
+		if err == mspctx.ErrUserNotFound {
+			return nil, ErrUserNotFound
+		}
+		if err == mspctx.ErrUserRevoked {
+			return nil, ErrUserRevoked
+		}
 		return nil, err
 	}
 	return si, nil
 }
+
+// identityLookup returns the identity manager of the organization if it supports user lookup
+func (c *Client) identityLookup() (mspctx.IdentityLookup, error) {
+	im, ok := c.ctx.IdentityManager(c.orgName)
+	if !ok {
+		return nil, errors.Errorf("identity manager not found for organization [%s]", c.orgName)
+	}
+	lookup, ok := im.(mspctx.IdentityLookup)
+	if !ok {
+		return nil, errors.Errorf("identity manager of organization [%s] doesn't support user lookup", c.orgName)
+	}
+	return lookup, nil
+}
+
+// GetProfileCredentials returns the certificate and private key of the user enrolled with
+// the given profile of the CA (see WithProfile)
+func (c *Client) GetProfileCredentials(id string, profile string) (*Credentials, error) {
+	if profile == "" {
+		return nil, errors.New("profile is required")
+	}
+	netConfig, err := c.ctx.EndpointConfig().NetworkConfig()
+	if err != nil {
+		return nil, errors.WithMessage(err, "network config retrieval failed")
+	}
+	orgConfig, ok := netConfig.Organizations[strings.ToLower(c.orgName)]
+	if !ok {
+		return nil, errors.Errorf("org config retrieval failed for organization [%s]", c.orgName)
+	}
+
+	userData, err := c.ctx.UserStore().Load(mspctx.IdentityIdentifier{ID: id, MSPID: msp.ProfileMSPID(orgConfig.MSPID, profile)})
+	if err != nil {
+		if err == mspctx.ErrUserNotFound {
+			return nil, ErrUserNotFound
+		}
+		return nil, errors.WithMessage(err, "loading credentials failed")
+	}
+	pubKey, err := cryptoutil.GetPublicKeyFromCert(userData.EnrollmentCertificate, c.ctx.CryptoSuite())
+	if err != nil {
+		return nil, errors.WithMessage(err, "fetching public key from cert failed")
+	}
+	privateKey, err := c.ctx.CryptoSuite().GetKey(pubKey.SKI())
+	if err != nil {
+		return nil, errors.WithMessage(err, "fetching private key failed")
+	}
+	return &Credentials{Certificate: userData.EnrollmentCertificate, PrivateKey: privateKey}, nil
+}
+
+// ImportIdentity imports the identity of an MSP folder produced by cryptogen or fabric-ca-client
+// (with the signcerts, keystore and cacerts sub-folders) into the user store and the key store
+// of the SDK. The identity can then be retrieved with GetSigningIdentity.
+//
+// id is the ID of the identity. It defaults to the common name of the signing certificate if empty.
+// Returns the ID of the imported identity.
+func (c *Client) ImportIdentity(mspDir string, id string) (string, error) {
+	netConfig, err := c.ctx.EndpointConfig().NetworkConfig()
+	if err != nil {
+		return "", errors.WithMessage(err, "network config retrieval failed")
+	}
+	orgConfig, ok := netConfig.Organizations[strings.ToLower(c.orgName)]
+	if !ok {
+		return "", errors.Errorf("org config retrieval failed for organization [%s]", c.orgName)
+	}
+
+	result, err := msp.ImportMSPDir(&msp.ImportOptions{
+		MSPDir:            mspDir,
+		MSPID:             orgConfig.MSPID,
+		ID:                id,
+		UserStore:         c.ctx.UserStore(),
+		CryptoSuite:       c.ctx.CryptoSuite(),
+		CertExpiryTracker: certexpiry.TrackerFor(c.ctx.EndpointConfig()),
+	})
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to import identity")
+	}
+	return result.User.ID, nil
+}
+
+// caInfoOptions represent CA info options
+type caInfoOptions struct {
+	pin bool
+}
+
+// CAInfoOption describes a functional parameter for GetCAInfo
+type CAInfoOption func(*caInfoOptions) error
+
+// WithCAChainPinning CA info option to pin the returned CA chain. The subsequent requests to the CA
+// fail with ErrCAChainMismatch if the CA returns another CA chain, e.g. if the CA endpoint was hijacked.
+func WithCAChainPinning() CAInfoOption {
+	return func(o *caInfoOptions) error {
+		o.pin = true
+		return nil
+	}
+}
+
+// GetCAInfo returns the name, the chain and the version of the CA. If a CA chain is pinned, the
+// returned CA chain must match it.
+func (c *Client) GetCAInfo(opts ...CAInfoOption) (*GetCAInfoResponse, error) {
+	o := caInfoOptions{}
+	for _, param := range opts {
+		err := param(&o)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to get CA info")
+		}
+	}
+
+	ca, err := newCAClient(c.ctx, c.orgName, c.caClientOptions()...)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ca.GetCAInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	if o.pin {
+		c.lock.Lock()
+		c.caChain = resp.CAChain
+		c.lock.Unlock()
+	}
+
+	return &GetCAInfoResponse{
+		CAName:  resp.CAName,
+		CAChain: resp.CAChain,
+		Version: resp.Version,
+	}, nil
+}
+
+// GetIdemixIssuerKeys returns the public keys of the idemix issuer of the CA, with which the idemix
+// credentials issued by the CA are verified. The keys are cached once they are retrieved.
+// Returns ErrIdemixNotSupported if the CA doesn't issue idemix credentials.
+func (c *Client) GetIdemixIssuerKeys() (*IdemixIssuerKeys, error) {
+	ca, err := newCAClient(c.ctx, c.orgName, c.caClientOptions()...)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := ca.GetIdemixIssuerKeys()
+	if err != nil {
+		return nil, err
+	}
+	return &IdemixIssuerKeys{
+		IssuerPublicKey:     keys.IssuerPublicKey,
+		RevocationPublicKey: keys.RevocationPublicKey,
+	}, nil
+}
+
+func (c *Client) caClientOptions() []msp.CAClientOption {
+	opts := []msp.CAClientOption{msp.WithCAChain(c.pinnedCAChain()), msp.WithRevokedUserCleanup(c.revokedUsers)}
+	for _, handler := range c.eventHandlers {
+		opts = append(opts, msp.WithIdentityEventHandler(newIdentityEventHandler(handler)))
+	}
+	return opts
+}
+
+// newIdentityEventHandler adapts an identity event handler of the client to the CA client
+func newIdentityEventHandler(handler IdentityEventHandler) mspapi.IdentityEventHandler {
+	return func(event *mspapi.IdentityEvent) {
+		var revokedCerts []RevokedCert
+		for i := range event.RevokedCerts {
+			revokedCerts = append(revokedCerts, RevokedCert(event.RevokedCerts[i]))
+		}
+		handler(&IdentityEvent{
+			Type:         IdentityEventType(event.Type),
+			ID:           event.ID,
+			MSPID:        event.MSPID,
+			Profile:      event.Profile,
+			Certificate:  event.Certificate,
+			Serial:       event.Serial,
+			NotAfter:     event.NotAfter,
+			RevokedCerts: revokedCerts,
+			Reason:       event.Reason,
+		})
+	}
+}
+
+func (c *Client) pinnedCAChain() []byte {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.caChain
+}
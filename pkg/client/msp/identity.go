@@ -8,12 +8,22 @@ package msp
 
 import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	mspapi "github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
 	"github.com/pkg/errors"
 )
 
 var (
 	// ErrUserNotFound indicates the user was not found
 	ErrUserNotFound = errors.New("user not found")
+
+	// ErrUserRevoked indicates the enrollment certificate of the user was revoked
+	ErrUserRevoked = msp.ErrUserRevoked
+
+	// ErrCAChainMismatch indicates the CA returned another CA chain than the pinned CA chain
+	ErrCAChainMismatch = mspapi.ErrCAChainMismatch
+
+	// ErrIdemixNotSupported indicates the CA doesn't issue idemix credentials
+	ErrIdemixNotSupported = mspapi.ErrIdemixNotSupported
 )
 
 // IdentityManager provides management of identities in a Fabric network
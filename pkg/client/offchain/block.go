@@ -0,0 +1,247 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package offchain
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// Block is the projection of a committed block: its endorser transactions and their writes
+type Block struct {
+	// Number is the number of the block
+	Number uint64
+	// DataHash is the hash of the data of the block
+	DataHash []byte
+	// PreviousHash is the hash of the header of the previous block
+	PreviousHash []byte
+	// Transactions are the endorser transactions of the block which invoke or write to the
+	// synchronized namespaces, in the order of the block
+	Transactions []*Transaction
+}
+
+// Transaction is the projection of an endorser transaction
+type Transaction struct {
+	// TxID is the ID of the transaction
+	TxID string
+	// TxNum is the position of the transaction in the block
+	TxNum uint64
+	// Timestamp is the time at which the transaction was created by the client
+	Timestamp time.Time
+	// ChaincodeID is the chaincode invoked by the transaction
+	ChaincodeID string
+	// ValidationCode is the validation code with which the transaction was committed
+	ValidationCode pb.TxValidationCode
+	// Writes are the writes of the transaction, in the order of the read-write set. Invalid
+	// transactions have no writes since they don't change the state.
+	Writes []*Write
+	// ChaincodeEvents are the chaincode events set by the transaction
+	ChaincodeEvents []*fab.CCEvent
+}
+
+// Write is a write of a key of the world state or of a private data collection
+type Write struct {
+	// Namespace is the chaincode which wrote the key
+	Namespace string
+	// Collection is the private data collection of the key, or empty for the world state
+	Collection string
+	// Key is the key which was written
+	Key string
+	// Value is the new value of the key, or nil if the key was deleted
+	Value []byte
+	// IsDelete is true if the key was deleted
+	IsDelete bool
+}
+
+// PrivateDataProvider returns the private read-write sets of the transactions of the given block,
+// by position of the transaction in the block. Blocks delivered to clients don't contain private
+// data, so private writes are only synchronized if such a provider is configured (see WithPrivateData).
+type PrivateDataProvider func(blockNum uint64) (map[uint64]*rwset.TxPvtReadWriteSet, error)
+
+// blockProjector projects the blocks of the channel
+type blockProjector struct {
+	namespaces  map[string]struct{}
+	privateData PrivateDataProvider
+}
+
+// project returns the projection of the given block
+func (p *blockProjector) project(block *cb.Block) (*Block, error) {
+	if block.Header == nil || block.Data == nil {
+		return nil, errors.New("block header and data are required")
+	}
+
+	projection := &Block{
+		Number:       block.Header.Number,
+		DataHash:     block.Header.DataHash,
+		PreviousHash: block.Header.PreviousHash,
+	}
+
+	var pvtData map[uint64]*rwset.TxPvtReadWriteSet
+	if p.privateData != nil {
+		var err error
+		pvtData, err = p.privateData(block.Header.Number)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to get private data")
+		}
+	}
+
+	var txFilter ledgerutil.TxValidationFlags
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txFilter = ledgerutil.TxValidationFlags(block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+
+	for i, data := range block.Data.Data {
+		tx, err := p.transaction(block.Header.Number, data)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to project transaction")
+		}
+		if tx == nil {
+			continue
+		}
+		tx.TxNum = uint64(i)
+		if i < len(txFilter) {
+			tx.ValidationCode = txFilter.Flag(i)
+		}
+		if tx.ValidationCode != pb.TxValidationCode_VALID {
+			tx.Writes = nil
+		} else if pvtRWSet, ok := pvtData[tx.TxNum]; ok {
+			writes, err := p.privateWrites(pvtRWSet)
+			if err != nil {
+				return nil, err
+			}
+			tx.Writes = append(tx.Writes, writes...)
+		}
+		if len(tx.Writes) > 0 || p.synchronized(tx.ChaincodeID) {
+			projection.Transactions = append(projection.Transactions, tx)
+		}
+	}
+	return projection, nil
+}
+
+// transaction returns the projection of an endorser transaction, or nil for other transactions
+func (p *blockProjector) transaction(blockNum uint64, data []byte) (*Transaction, error) {
+	env, err := utils.GetEnvelopeFromBlock(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting Envelope from block")
+	}
+	payload, err := utils.GetPayload(env)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting Payload from envelope")
+	}
+	channelHeader := &cb.ChannelHeader{}
+	if err := proto.Unmarshal(payload.Header.ChannelHeader, channelHeader); err != nil {
+		return nil, errors.Wrap(err, "error extracting ChannelHeader from payload")
+	}
+	if cb.HeaderType(channelHeader.Type) != cb.HeaderType_ENDORSER_TRANSACTION {
+		return nil, nil
+	}
+
+	projection := &Transaction{TxID: channelHeader.TxId}
+	if ts := channelHeader.Timestamp; ts != nil {
+		projection.Timestamp = time.Unix(ts.Seconds, int64(ts.Nanos)).UTC()
+	}
+
+	tx, err := utils.GetTransaction(payload.Data)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling transaction payload")
+	}
+	for _, action := range tx.Actions {
+		chaincodeActionPayload, err := utils.GetChaincodeActionPayload(action.Payload)
+		if err != nil {
+			return nil, errors.Wrap(err, "error unmarshalling chaincode action payload")
+		}
+		propRespPayload, err := utils.GetProposalResponsePayload(chaincodeActionPayload.Action.ProposalResponsePayload)
+		if err != nil {
+			return nil, errors.Wrap(err, "error unmarshalling response payload")
+		}
+		chaincodeAction, err := utils.GetChaincodeAction(propRespPayload.Extension)
+		if err != nil {
+			return nil, errors.Wrap(err, "error unmarshalling chaincode action")
+		}
+		if projection.ChaincodeID == "" && chaincodeAction.ChaincodeId != nil {
+			projection.ChaincodeID = chaincodeAction.ChaincodeId.Name
+		}
+		if len(chaincodeAction.Events) > 0 {
+			ccEvent, err := utils.GetChaincodeEvents(chaincodeAction.Events)
+			if err != nil {
+				return nil, errors.Wrap(err, "error unmarshalling chaincode event")
+			}
+			if ccEvent.EventName != "" && p.synchronized(ccEvent.ChaincodeId) {
+				projection.ChaincodeEvents = append(projection.ChaincodeEvents, &fab.CCEvent{
+					TxID:        channelHeader.TxId,
+					ChaincodeID: ccEvent.ChaincodeId,
+					EventName:   ccEvent.EventName,
+					Payload:     ccEvent.Payload,
+					BlockNumber: blockNum,
+				})
+			}
+		}
+		txRWSet := &rwset.TxReadWriteSet{}
+		if err := proto.Unmarshal(chaincodeAction.Results, txRWSet); err != nil {
+			return nil, errors.Wrap(err, "error unmarshalling read-write set")
+		}
+		for _, nsRWSet := range txRWSet.NsRwset {
+			if !p.synchronized(nsRWSet.Namespace) {
+				continue
+			}
+			kvRWSet := &kvrwset.KVRWSet{}
+			if err := proto.Unmarshal(nsRWSet.Rwset, kvRWSet); err != nil {
+				return nil, errors.Wrap(err, "error unmarshalling KV read-write set")
+			}
+			projection.Writes = appendWrites(projection.Writes, nsRWSet.Namespace, "", kvRWSet.Writes)
+		}
+	}
+	return projection, nil
+}
+
+// privateWrites returns the writes of the private read-write set of a transaction
+func (p *blockProjector) privateWrites(pvtRWSet *rwset.TxPvtReadWriteSet) ([]*Write, error) {
+	var writes []*Write
+	for _, nsPvtRWSet := range pvtRWSet.NsPvtRwset {
+		if !p.synchronized(nsPvtRWSet.Namespace) {
+			continue
+		}
+		for _, collPvtRWSet := range nsPvtRWSet.CollectionPvtRwset {
+			kvRWSet := &kvrwset.KVRWSet{}
+			if err := proto.Unmarshal(collPvtRWSet.Rwset, kvRWSet); err != nil {
+				return nil, errors.Wrap(err, "error unmarshalling private KV read-write set")
+			}
+			writes = appendWrites(writes, nsPvtRWSet.Namespace, collPvtRWSet.CollectionName, kvRWSet.Writes)
+		}
+	}
+	return writes, nil
+}
+
+// synchronized returns true if the writes of the namespace are synchronized
+func (p *blockProjector) synchronized(namespace string) bool {
+	if len(p.namespaces) == 0 {
+		return true
+	}
+	_, ok := p.namespaces[namespace]
+	return ok
+}
+
+func appendWrites(writes []*Write, namespace, collection string, kvWrites []*kvrwset.KVWrite) []*Write {
+	for _, kvWrite := range kvWrites {
+		write := &Write{Namespace: namespace, Collection: collection, Key: kvWrite.Key, IsDelete: kvWrite.IsDelete}
+		if !kvWrite.IsDelete {
+			write.Value = kvWrite.Value
+		}
+		writes = append(writes, write)
+	}
+	return writes
+}
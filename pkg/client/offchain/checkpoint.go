@@ -0,0 +1,91 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package offchain
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+)
+
+// KVCheckpointer records the checkpoint in a key-value store (see keyvaluestore)
+type KVCheckpointer struct {
+	store core.KVStore
+	key   string
+}
+
+// NewKVCheckpointer returns a checkpointer which records the checkpoint in the given key-value
+// store under the given key
+func NewKVCheckpointer(store core.KVStore, key string) (*KVCheckpointer, error) {
+	if store == nil {
+		return nil, errors.New("key-value store is required")
+	}
+	if key == "" {
+		return nil, errors.New("checkpoint key is required")
+	}
+	return &KVCheckpointer{store: store, key: key}, nil
+}
+
+// LastBlock returns the number of the last block recorded in the store, or false if none was recorded
+func (c *KVCheckpointer) LastBlock() (uint64, bool, error) {
+	value, err := c.store.Load(c.key)
+	if err != nil {
+		if errors.Cause(err) == core.ErrKeyValueNotFound {
+			return 0, false, nil
+		}
+		return 0, false, errors.Wrapf(err, "failed to load checkpoint [%s]", c.key)
+	}
+
+	var s string
+	switch v := value.(type) {
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return 0, false, errors.Errorf("unexpected checkpoint type %T for [%s]", value, c.key)
+	}
+
+	blockNum, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "invalid checkpoint [%s] for [%s]", s, c.key)
+	}
+	return blockNum, true, nil
+}
+
+// SetLastBlock records the number of the last block in the store
+func (c *KVCheckpointer) SetLastBlock(blockNum uint64) error {
+	if err := c.store.Store(c.key, []byte(strconv.FormatUint(blockNum, 10))); err != nil {
+		return errors.Wrapf(err, "failed to store checkpoint [%s]", c.key)
+	}
+	return nil
+}
+
+// memCheckpointer keeps the checkpoint in memory, so synchronization restarts from the start
+// block when the process restarts
+type memCheckpointer struct {
+	lock      sync.RWMutex
+	lastBlock uint64
+	ok        bool
+}
+
+func (c *memCheckpointer) LastBlock() (uint64, bool, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.lastBlock, c.ok, nil
+}
+
+func (c *memCheckpointer) SetLastBlock(blockNum uint64) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.lastBlock = blockNum
+	c.ok = true
+	return nil
+}
@@ -0,0 +1,236 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package offchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCouchDBSink(t *testing.T) {
+	couchDB := newFakeCouchDB()
+	server := httptest.NewServer(couchDB)
+	defer server.Close()
+
+	sink, err := NewCouchDBSink(server.URL + "/mydb/")
+	require.NoError(t, err)
+
+	_, ok, err := sink.LastBlock()
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	block := &Block{Number: 1, Transactions: []*Transaction{
+		{TxID: "tx1", Writes: []*Write{
+			{Namespace: "mycc", Key: "a", Value: []byte(`{"owner":"alice"}`)},
+			{Namespace: "mycc", Collection: "coll1", Key: "b", Value: []byte{0xff, 0x01}},
+		}},
+		{TxID: "tx2", TxNum: 1, Writes: []*Write{
+			{Namespace: "mycc", Key: "a", Value: []byte(`{"owner":"bob"}`)},
+			{Namespace: "mycc", Key: "c", IsDelete: true},
+		}},
+	}}
+	require.NoError(t, sink.Apply(block))
+
+	doc := couchDB.doc("mycc~~a")
+	require.NotNil(t, doc)
+	assert.JSONEq(t, `{"owner":"bob"}`, string(doc.Value))
+	assert.Equal(t, "tx2", doc.TxID)
+	assert.False(t, doc.Binary)
+
+	doc = couchDB.doc("mycc~coll1~b")
+	require.NotNil(t, doc)
+	assert.True(t, doc.Binary)
+	var value []byte
+	require.NoError(t, json.Unmarshal(doc.Value, &value))
+	assert.Equal(t, []byte{0xff, 0x01}, value)
+
+	assert.Nil(t, couchDB.doc("mycc~~c"), "expecting no document for deleted key which didn't exist")
+
+	lastBlock, ok, err := sink.LastBlock()
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, 1, lastBlock)
+
+	// Replayed blocks are ignored
+	require.NoError(t, sink.Apply(block))
+
+	block = &Block{Number: 2, Transactions: []*Transaction{{TxID: "tx3", Writes: []*Write{
+		{Namespace: "mycc", Key: "a", IsDelete: true},
+	}}}}
+	require.NoError(t, sink.Apply(block))
+	assert.Nil(t, couchDB.doc("mycc~~a"))
+
+	// A block applied again after its checkpoint was lost is stored idempotently
+	require.NoError(t, sink.SetLastBlock(0))
+	block = &Block{Number: 1, Transactions: []*Transaction{{TxID: "tx1", Writes: []*Write{
+		{Namespace: "mycc", Key: "a", Value: []byte(`{"owner":"carol"}`)},
+	}}}}
+	require.NoError(t, sink.Apply(block))
+	assert.JSONEq(t, `{"owner":"carol"}`, string(couchDB.doc("mycc~~a").Value))
+}
+
+func TestCouchDBSinkErrors(t *testing.T) {
+	couchDB := newFakeCouchDB()
+	server := httptest.NewServer(couchDB)
+	defer server.Close()
+
+	_, err := NewCouchDBSink("")
+	assert.Error(t, err)
+
+	sink, err := NewCouchDBSink(server.URL+"/mydb", WithCouchDBClient(server.Client()), WithCouchDBCheckpointName("mychannel"))
+	require.NoError(t, err)
+
+	couchDB.failBulk = true
+	err = sink.Apply(&Block{Number: 1, Transactions: []*Transaction{{TxID: "tx1", Writes: []*Write{
+		{Namespace: "mycc", Key: "a", Value: []byte("1")},
+	}}}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "forbidden")
+
+	_, ok, err := sink.LastBlock()
+	require.NoError(t, err)
+	assert.False(t, ok, "expecting block not to be checkpointed")
+}
+
+// fakeCouchDB implements the subset of the CouchDB API used by the sink, for a single database
+type fakeCouchDB struct {
+	lock     sync.Mutex
+	docs     map[string]*couchDoc
+	local    map[string]*couchCheckpoint
+	failBulk bool
+}
+
+func newFakeCouchDB() *fakeCouchDB {
+	return &fakeCouchDB{docs: make(map[string]*couchDoc), local: make(map[string]*couchCheckpoint)}
+}
+
+func (db *fakeCouchDB) doc(id string) *couchDoc {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	doc := db.docs[id]
+	if doc == nil || doc.Deleted {
+		return nil
+	}
+	return doc
+}
+
+func (db *fakeCouchDB) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/mydb")
+	switch {
+	case r.Method == http.MethodPost && path == "/_all_docs":
+		db.allDocs(w, r)
+	case r.Method == http.MethodPost && path == "/_bulk_docs":
+		db.bulkDocs(w, r)
+	case strings.HasPrefix(path, "/_local/"):
+		db.localDoc(w, r, strings.TrimPrefix(path, "/_local/"))
+	default:
+		http.Error(w, `{"error":"not_found"}`, http.StatusNotFound)
+	}
+}
+
+func (db *fakeCouchDB) allDocs(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Keys []string `json:"keys"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var rows []map[string]interface{}
+	for _, key := range request.Keys {
+		doc, ok := db.docs[key]
+		if !ok {
+			rows = append(rows, map[string]interface{}{"key": key, "error": "not_found"})
+			continue
+		}
+		rows = append(rows, map[string]interface{}{"id": key, "key": key, "value": map[string]interface{}{"rev": doc.Rev, "deleted": doc.Deleted}})
+	}
+	writeJSON(w, map[string]interface{}{"rows": rows})
+}
+
+func (db *fakeCouchDB) bulkDocs(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Docs []*couchDoc `json:"docs"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var results []map[string]interface{}
+	for _, doc := range request.Docs {
+		if db.failBulk {
+			results = append(results, map[string]interface{}{"id": doc.ID, "error": "forbidden", "reason": "not allowed"})
+			continue
+		}
+		current, ok := db.docs[doc.ID]
+		if ok && !current.Deleted && current.Rev != doc.Rev {
+			results = append(results, map[string]interface{}{"id": doc.ID, "error": "conflict", "reason": "document update conflict"})
+			continue
+		}
+		doc.Rev = nextRev(current)
+		db.docs[doc.ID] = doc
+		results = append(results, map[string]interface{}{"id": doc.ID, "rev": doc.Rev})
+	}
+	writeJSON(w, results)
+}
+
+func (db *fakeCouchDB) localDoc(w http.ResponseWriter, r *http.Request, id string) {
+	current, ok := db.local[id]
+	switch r.Method {
+	case http.MethodGet:
+		if !ok {
+			http.Error(w, `{"error":"not_found"}`, http.StatusNotFound)
+			return
+		}
+		writeJSON(w, current)
+	case http.MethodPut:
+		checkpoint := &couchCheckpoint{}
+		if err := json.NewDecoder(r.Body).Decode(checkpoint); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if ok && current.Rev != checkpoint.Rev {
+			http.Error(w, `{"error":"conflict"}`, http.StatusConflict)
+			return
+		}
+		var n int
+		fmt.Sscanf(checkpoint.Rev, "0-%d", &n)
+		checkpoint.Rev = fmt.Sprintf("0-%d", n+1)
+		db.local[id] = checkpoint
+		writeJSON(w, map[string]interface{}{"ok": true, "id": "_local/" + id, "rev": checkpoint.Rev})
+	}
+}
+
+func nextRev(doc *couchDoc) string {
+	if doc == nil {
+		return "1-a"
+	}
+	var n int
+	fmt.Sscanf(doc.Rev, "%d-", &n)
+	return fmt.Sprintf("%d-a", n+1)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
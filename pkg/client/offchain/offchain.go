@@ -0,0 +1,301 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package offchain synchronizes an off-chain store (e.g. a SQL database or CouchDB) with the
+// world state of a channel. The synchronizer consumes the block events of the channel from the
+// last checkpointed block and projects the writes of the valid transactions into a Sink.
+//
+// Blocks are applied in order and exactly once per sink state: blocks which were already applied
+// (e.g. replayed by the event service after a reconnection) are skipped, and missing blocks (e.g.
+// after the event service skipped ahead) are queried from the ledger before the next block is
+// applied. Since a block may be applied again if the synchronizer stops between applying it and
+// checkpointing it, sinks must apply blocks idempotently. Sinks which record the checkpoint in the
+// same transaction as the writes (see SQLSink and CouchDBSink) implement Checkpointer.
+//  Basic Flow:
+//  1) Prepare channel client context
+//  2) Create the sink
+//  3) Create and start the synchronizer
+//  4) Stop the synchronizer (or wait for it to fail)
+//
+//  sink, err := offchain.NewSQLSink(db)
+//  ...
+//  synchronizer, err := offchain.New(channelProvider, sink, offchain.WithNamespaces("mycc"))
+//  ...
+//  err = synchronizer.Start()
+//  ...
+//  synchronizer.Stop()
+package offchain
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/ledger"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+// Sink is the off-chain store into which the writes of the blocks are projected
+type Sink interface {
+	// Apply projects the writes of the block into the store. Blocks are applied in order, but
+	// the last block may be applied again after a restart, so Apply must be idempotent.
+	Apply(block *Block) error
+}
+
+// Checkpointer records the number of the last block which was applied to the sink
+type Checkpointer interface {
+	// LastBlock returns the number of the last block which was applied, or false if no block
+	// was applied yet
+	LastBlock() (uint64, bool, error)
+	// SetLastBlock records the number of the last block which was applied
+	SetLastBlock(blockNum uint64) error
+}
+
+// EventSource is the source of the block events of the channel
+type EventSource interface {
+	RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error)
+	Unregister(reg fab.Registration)
+}
+
+// BlockQuerier queries the blocks which are missing from the block events
+type BlockQuerier interface {
+	QueryBlock(blockNumber uint64, options ...ledger.RequestOption) (*cb.Block, error)
+}
+
+// Synchronizer synchronizes a sink with the world state of a channel
+type Synchronizer struct {
+	sink            Sink
+	checkpointer    Checkpointer
+	sinkCheckpoints bool
+	startBlock      uint64
+	projector       blockProjector
+	newEventSource  func(fromBlock uint64) (EventSource, error)
+	blockQuerier    BlockQuerier
+
+	lock      sync.Mutex
+	started   bool
+	stopped   bool
+	events    EventSource
+	reg       fab.Registration
+	stop      chan struct{}
+	done      chan struct{}
+	err       error
+	nextBlock uint64
+}
+
+// New returns a synchronizer of the given sink with the world state of the channel. The
+// synchronizer receives (full) block events, so the caller must have sufficient privileges.
+func New(channelProvider context.ChannelProvider, sink Sink, opts ...Option) (*Synchronizer, error) {
+	channelContext, err := channelProvider()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create channel context")
+	}
+	if channelContext.ChannelService() == nil {
+		return nil, errors.New("channel service not initialized")
+	}
+
+	ledgerClient, err := ledger.New(channelProvider)
+	if err != nil {
+		return nil, errors.WithMessage(err, "ledger client creation failed")
+	}
+
+	newEventSource := func(fromBlock uint64) (EventSource, error) {
+		return channelContext.ChannelService().EventService(
+			client.WithBlockEvents(),
+			deliverclient.WithSeekType(seek.FromBlock),
+			deliverclient.WithBlockNum(fromBlock),
+		)
+	}
+
+	return newSynchronizer(sink, newEventSource, ledgerClient, opts...)
+}
+
+func newSynchronizer(sink Sink, newEventSource func(fromBlock uint64) (EventSource, error), blockQuerier BlockQuerier, opts ...Option) (*Synchronizer, error) {
+	if sink == nil {
+		return nil, errors.New("sink is required")
+	}
+
+	s := &Synchronizer{
+		sink:           sink,
+		newEventSource: newEventSource,
+		blockQuerier:   blockQuerier,
+		stop:           make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.checkpointer == nil {
+		if checkpointer, ok := sink.(Checkpointer); ok {
+			s.checkpointer = checkpointer
+			s.sinkCheckpoints = true
+		} else {
+			s.checkpointer = &memCheckpointer{}
+		}
+	}
+
+	return s, nil
+}
+
+// Start starts synchronizing from the block following the last checkpointed block, or from the
+// start block (see WithStartBlock) if no block was checkpointed yet
+func (s *Synchronizer) Start() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.started {
+		return errors.New("synchronizer already started")
+	}
+
+	lastBlock, ok, err := s.checkpointer.LastBlock()
+	if err != nil {
+		return errors.WithMessage(err, "failed to load checkpoint")
+	}
+	s.nextBlock = s.startBlock
+	if ok && lastBlock+1 > s.startBlock {
+		s.nextBlock = lastBlock + 1
+	}
+
+	events, err := s.newEventSource(s.nextBlock)
+	if err != nil {
+		return errors.WithMessage(err, "event service creation failed")
+	}
+	reg, eventch, err := events.RegisterBlockEvent()
+	if err != nil {
+		return errors.WithMessage(err, "failed to register for block events")
+	}
+
+	logger.Debugf("Starting synchronization from block %d", s.nextBlock)
+
+	s.started = true
+	s.events = events
+	s.reg = reg
+	go s.run(eventch)
+
+	return nil
+}
+
+// Stop stops synchronizing and waits for the block being applied, if any
+func (s *Synchronizer) Stop() {
+	s.lock.Lock()
+	if !s.started || s.stopped {
+		s.lock.Unlock()
+		return
+	}
+	s.stopped = true
+	close(s.stop)
+	s.events.Unregister(s.reg)
+	s.lock.Unlock()
+
+	<-s.done
+}
+
+// Done returns a channel which is closed when the synchronizer stops, either because Stop was
+// called or because of an error (see Err)
+func (s *Synchronizer) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns the error which stopped the synchronizer, if any
+func (s *Synchronizer) Err() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.err
+}
+
+func (s *Synchronizer) run(eventch <-chan *fab.BlockEvent) {
+	defer close(s.done)
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case event, ok := <-eventch:
+			if !ok {
+				select {
+				case <-s.stop:
+				default:
+					s.fail(errors.New("block event channel closed"))
+				}
+				return
+			}
+			if err := s.process(event.Block); err != nil {
+				s.fail(err)
+				return
+			}
+		}
+	}
+}
+
+func (s *Synchronizer) fail(err error) {
+	logger.Errorf("Synchronization stopped: %s", err)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.err = err
+}
+
+// process applies the given block, after the blocks which are missing before it, if any
+func (s *Synchronizer) process(block *cb.Block) error {
+	if block == nil || block.Header == nil {
+		return errors.New("received block without header")
+	}
+
+	number := block.Header.Number
+	if number < s.nextBlock {
+		logger.Debugf("Skipping block %d which was already applied", number)
+		return nil
+	}
+
+	if number > s.nextBlock {
+		logger.Warnf("Blocks %d to %d are missing from the block events; querying them from the ledger", s.nextBlock, number-1)
+	}
+	for s.nextBlock < number {
+		missing, err := s.blockQuerier.QueryBlock(s.nextBlock)
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("failed to query missing block %d", s.nextBlock))
+		}
+		if err := s.apply(missing); err != nil {
+			return err
+		}
+	}
+
+	return s.apply(block)
+}
+
+// apply projects the block into the sink and checkpoints it
+func (s *Synchronizer) apply(block *cb.Block) error {
+	projection, err := s.projector.project(block)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed to project block %d", s.nextBlock))
+	}
+	if projection.Number != s.nextBlock {
+		return errors.Errorf("expecting block %d but got block %d", s.nextBlock, projection.Number)
+	}
+
+	if err := s.sink.Apply(projection); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed to apply block %d", projection.Number))
+	}
+	if !s.sinkCheckpoints {
+		if err := s.checkpointer.SetLastBlock(projection.Number); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("failed to checkpoint block %d", projection.Number))
+		}
+	}
+
+	s.nextBlock = projection.Number + 1
+	return nil
+}
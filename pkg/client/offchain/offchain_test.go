@@ -0,0 +1,279 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package offchain
+
+import (
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/ledger"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/keyvaluestore"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/test/fixturegen"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+func TestSynchronizer(t *testing.T) {
+	blocks := newTestBlocks(t)
+
+	events := newMockEventSource()
+	querier := &mockBlockQuerier{blocks: blocks}
+	sink := &mockSink{}
+
+	s, err := newSynchronizer(sink, events.newEventSource, querier, WithNamespaces("mycc"))
+	require.NoError(t, err)
+	require.NoError(t, s.Start())
+	assert.Error(t, s.Start(), "expecting error when starting twice")
+	assert.EqualValues(t, 0, events.fromBlock)
+
+	events.publish(blocks[0])
+	events.publish(blocks[1])
+	events.publish(blocks[1]) // replayed
+	events.publish(blocks[3]) // block 2 is missing
+	sink.waitFor(t, 4)
+
+	applied := sink.applied()
+	require.Len(t, applied, 4)
+	for i, block := range applied {
+		assert.EqualValues(t, i, block.Number)
+	}
+	assert.Empty(t, applied[0].Transactions, "expecting no writes for config block")
+
+	require.Len(t, applied[1].Transactions, 1)
+	tx := applied[1].Transactions[0]
+	assert.False(t, tx.Timestamp.IsZero())
+	assert.Equal(t, "mycc", tx.ChaincodeID)
+	assert.Equal(t, pb.TxValidationCode_VALID, tx.ValidationCode)
+	assert.Equal(t, []*Write{{Namespace: "mycc", Key: "a", Value: []byte("1")}}, tx.Writes, "expecting writes of othercc to be filtered")
+	require.Len(t, tx.ChaincodeEvents, 1)
+	assert.Equal(t, &fab.CCEvent{TxID: tx.TxID, ChaincodeID: "mycc", EventName: "set", Payload: []byte("a"), BlockNumber: 1}, tx.ChaincodeEvents[0])
+
+	require.Len(t, applied[2].Transactions, 2)
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, applied[2].Transactions[0].ValidationCode)
+	assert.Empty(t, applied[2].Transactions[0].Writes, "expecting no writes for invalid transaction")
+	assert.EqualValues(t, 1, applied[2].Transactions[1].TxNum)
+	assert.Equal(t, []*Write{{Namespace: "mycc", Key: "a", IsDelete: true}}, applied[2].Transactions[1].Writes)
+	assert.NotEmpty(t, applied[2].DataHash)
+	assert.Equal(t, []uint64{2}, querier.queried)
+
+	s.Stop()
+	assert.True(t, events.unregistered)
+	assert.NoError(t, s.Err())
+	s.Stop()
+}
+
+func TestSynchronizerCheckpoint(t *testing.T) {
+	blocks := newTestBlocks(t)
+
+	path, err := ioutil.TempDir("", "offchain")
+	require.NoError(t, err)
+	defer os.RemoveAll(path)
+
+	store, err := keyvaluestore.New(&keyvaluestore.FileKeyValueStoreOptions{Path: path})
+	require.NoError(t, err)
+	checkpointer, err := NewKVCheckpointer(store, "mychannel")
+	require.NoError(t, err)
+
+	_, ok, err := checkpointer.LastBlock()
+	require.NoError(t, err)
+	assert.False(t, ok)
+	require.NoError(t, checkpointer.SetLastBlock(1))
+
+	events := newMockEventSource()
+	sink := &mockSink{}
+	s, err := newSynchronizer(sink, events.newEventSource, &mockBlockQuerier{blocks: blocks}, WithCheckpointer(checkpointer))
+	require.NoError(t, err)
+	require.NoError(t, s.Start())
+	defer s.Stop()
+	assert.EqualValues(t, 2, events.fromBlock)
+
+	events.publish(blocks[1])
+	events.publish(blocks[2])
+	sink.waitFor(t, 1)
+	assert.EqualValues(t, 2, sink.applied()[0].Number)
+
+	lastBlock, ok, err := checkpointer.LastBlock()
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, 2, lastBlock)
+}
+
+func TestSynchronizerStartBlock(t *testing.T) {
+	events := newMockEventSource()
+	s, err := newSynchronizer(&mockSink{}, events.newEventSource, &mockBlockQuerier{}, WithStartBlock(10))
+	require.NoError(t, err)
+	require.NoError(t, s.Start())
+	defer s.Stop()
+	assert.EqualValues(t, 10, events.fromBlock)
+}
+
+func TestSynchronizerSinkError(t *testing.T) {
+	blocks := newTestBlocks(t)
+
+	events := newMockEventSource()
+	sink := &mockSink{err: errors.New("database unavailable")}
+	s, err := newSynchronizer(sink, events.newEventSource, &mockBlockQuerier{blocks: blocks})
+	require.NoError(t, err)
+	require.NoError(t, s.Start())
+	defer s.Stop()
+
+	events.publish(blocks[0])
+	select {
+	case <-s.Done():
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for synchronizer to stop")
+	}
+	require.Error(t, s.Err())
+	assert.Contains(t, s.Err().Error(), "database unavailable")
+}
+
+func TestSynchronizerPrivateData(t *testing.T) {
+	blocks := newTestBlocks(t)
+
+	kvRWSet, err := proto.Marshal(&kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{{Key: "secret", Value: []byte("42")}}})
+	require.NoError(t, err)
+	privateData := func(blockNum uint64) (map[uint64]*rwset.TxPvtReadWriteSet, error) {
+		if blockNum != 1 {
+			return nil, nil
+		}
+		return map[uint64]*rwset.TxPvtReadWriteSet{
+			0: {NsPvtRwset: []*rwset.NsPvtReadWriteSet{{
+				Namespace:          "mycc",
+				CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{{CollectionName: "coll1", Rwset: kvRWSet}},
+			}}},
+		}, nil
+	}
+
+	events := newMockEventSource()
+	sink := &mockSink{}
+	s, err := newSynchronizer(sink, events.newEventSource, &mockBlockQuerier{blocks: blocks}, WithNamespaces("mycc"), WithPrivateData(privateData), WithStartBlock(1))
+	require.NoError(t, err)
+	require.NoError(t, s.Start())
+	defer s.Stop()
+
+	events.publish(blocks[1])
+	sink.waitFor(t, 1)
+
+	tx := sink.applied()[0].Transactions[0]
+	require.Len(t, tx.Writes, 2)
+	assert.Equal(t, &Write{Namespace: "mycc", Collection: "coll1", Key: "secret", Value: []byte("42")}, tx.Writes[1])
+}
+
+// newTestBlocks returns a config block followed by three blocks of transactions
+func newTestBlocks(t *testing.T) []*cb.Block {
+	gen, err := fixturegen.New("mychannel")
+	require.NoError(t, err)
+
+	configBlock, err := gen.ConfigBlock()
+	require.NoError(t, err)
+
+	tx1, err := gen.Transaction("mycc", [][]byte{[]byte("set")}, fixturegen.WithWrite("a", []byte("1")), fixturegen.WithChaincodeEvent("set", []byte("a")))
+	require.NoError(t, err)
+	tx2, err := gen.Transaction("othercc", [][]byte{[]byte("set")}, fixturegen.WithWrite("b", []byte("2")))
+	require.NoError(t, err)
+	block1, err := gen.Block(tx1, tx2)
+	require.NoError(t, err)
+
+	tx3, err := gen.Transaction("mycc", [][]byte{[]byte("set")}, fixturegen.WithWrite("a", []byte("3")), fixturegen.WithValidationCode(pb.TxValidationCode_MVCC_READ_CONFLICT))
+	require.NoError(t, err)
+	tx4, err := gen.Transaction("mycc", [][]byte{[]byte("delete")}, fixturegen.WithWrite("a", nil))
+	require.NoError(t, err)
+	block2, err := gen.Block(tx3, tx4)
+	require.NoError(t, err)
+
+	tx5, err := gen.Transaction("mycc", [][]byte{[]byte("get")}, fixturegen.WithRead("a", 2, 1))
+	require.NoError(t, err)
+	block3, err := gen.Block(tx5)
+	require.NoError(t, err)
+
+	return []*cb.Block{configBlock, block1, block2, block3}
+}
+
+type mockEventSource struct {
+	eventch      chan *fab.BlockEvent
+	fromBlock    uint64
+	unregistered bool
+}
+
+func newMockEventSource() *mockEventSource {
+	return &mockEventSource{eventch: make(chan *fab.BlockEvent, 10)}
+}
+
+func (m *mockEventSource) newEventSource(fromBlock uint64) (EventSource, error) {
+	m.fromBlock = fromBlock
+	return m, nil
+}
+
+func (m *mockEventSource) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
+	return m, m.eventch, nil
+}
+
+func (m *mockEventSource) Unregister(reg fab.Registration) {
+	m.unregistered = true
+	close(m.eventch)
+}
+
+func (m *mockEventSource) publish(block *cb.Block) {
+	m.eventch <- &fab.BlockEvent{Block: block}
+}
+
+type mockBlockQuerier struct {
+	blocks  []*cb.Block
+	queried []uint64
+}
+
+func (m *mockBlockQuerier) QueryBlock(blockNumber uint64, options ...ledger.RequestOption) (*cb.Block, error) {
+	m.queried = append(m.queried, blockNumber)
+	if blockNumber >= uint64(len(m.blocks)) {
+		return nil, errors.Errorf("block %d not found", blockNumber)
+	}
+	return m.blocks[blockNumber], nil
+}
+
+type mockSink struct {
+	lock   sync.Mutex
+	blocks []*Block
+	err    error
+}
+
+func (m *mockSink) Apply(block *Block) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+	m.blocks = append(m.blocks, block)
+	return nil
+}
+
+func (m *mockSink) applied() []*Block {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return append([]*Block{}, m.blocks...)
+}
+
+func (m *mockSink) waitFor(t *testing.T, n int) {
+	deadline := time.Now().Add(time.Second)
+	for len(m.applied()) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d blocks to be applied", n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,46 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package offchain
+
+// Option describes a functional parameter for the New constructor
+type Option func(*Synchronizer)
+
+// WithCheckpointer sets the checkpointer of the synchronizer. By default, sinks which implement
+// Checkpointer record their own checkpoint; the checkpoint of other sinks is kept in memory only.
+func WithCheckpointer(checkpointer Checkpointer) Option {
+	return func(s *Synchronizer) {
+		s.checkpointer = checkpointer
+		s.sinkCheckpoints = false
+	}
+}
+
+// WithStartBlock sets the block from which to synchronize if no block was checkpointed yet.
+// The default is the genesis block.
+func WithStartBlock(blockNum uint64) Option {
+	return func(s *Synchronizer) {
+		s.startBlock = blockNum
+	}
+}
+
+// WithNamespaces restricts the synchronization to the writes of the given chaincodes. By default,
+// the writes of all chaincodes (including the system chaincodes) are synchronized.
+func WithNamespaces(namespaces ...string) Option {
+	return func(s *Synchronizer) {
+		s.projector.namespaces = make(map[string]struct{})
+		for _, namespace := range namespaces {
+			s.projector.namespaces[namespace] = struct{}{}
+		}
+	}
+}
+
+// WithPrivateData sets the provider of the private data of the blocks, so that the writes of
+// private data collections are synchronized along with the writes of the world state
+func WithPrivateData(provider PrivateDataProvider) Option {
+	return func(s *Synchronizer) {
+		s.projector.privateData = provider
+	}
+}
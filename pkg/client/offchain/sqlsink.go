@@ -0,0 +1,214 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package offchain
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultSQLStateTable      = "offchain_state"
+	defaultSQLCheckpointTable = "offchain_checkpoint"
+	defaultCheckpointName     = "default"
+)
+
+// SQLSink projects the writes into a SQL table and records the checkpoint in the same database
+// transaction, so that each block is applied exactly once. The tables must be created beforehand,
+// e.g. (the column types depend on the database):
+//
+//  CREATE TABLE offchain_state (
+//      namespace  VARCHAR(255)  NOT NULL,
+//      collection VARCHAR(255)  NOT NULL,
+//      state_key  VARCHAR(1024) NOT NULL,
+//      value      BLOB,
+//      block_num  BIGINT        NOT NULL,
+//      tx_num     BIGINT        NOT NULL,
+//      tx_id      VARCHAR(255)  NOT NULL,
+//      PRIMARY KEY (namespace, collection, state_key)
+//  );
+//  CREATE TABLE offchain_checkpoint (
+//      name      VARCHAR(255) NOT NULL PRIMARY KEY,
+//      block_num BIGINT       NOT NULL
+//  );
+//
+// The collection of the world state is the empty string. The driver of the database is imported
+// by the application.
+type SQLSink struct {
+	db              *sql.DB
+	stateTable      string
+	checkpointTable string
+	checkpointName  string
+	placeholder     func(n int) string
+}
+
+// SQLOption describes a functional parameter for the NewSQLSink constructor
+type SQLOption func(*SQLSink)
+
+// WithSQLTables sets the names of the state table and of the checkpoint table
+func WithSQLTables(stateTable, checkpointTable string) SQLOption {
+	return func(s *SQLSink) {
+		s.stateTable = stateTable
+		s.checkpointTable = checkpointTable
+	}
+}
+
+// WithSQLCheckpointName sets the name of the checkpoint row, so that several synchronizers may
+// share the checkpoint table
+func WithSQLCheckpointName(name string) SQLOption {
+	return func(s *SQLSink) {
+		s.checkpointName = name
+	}
+}
+
+// WithSQLDollarPlaceholders makes the sink use numbered placeholders ($1, $2...), as expected by
+// PostgreSQL drivers, instead of question marks
+func WithSQLDollarPlaceholders() SQLOption {
+	return func(s *SQLSink) {
+		s.placeholder = func(n int) string { return fmt.Sprintf("$%d", n) }
+	}
+}
+
+// NewSQLSink returns a sink which projects the writes into the given database
+func NewSQLSink(db *sql.DB, opts ...SQLOption) (*SQLSink, error) {
+	if db == nil {
+		return nil, errors.New("database is required")
+	}
+
+	s := &SQLSink{
+		db:              db,
+		stateTable:      defaultSQLStateTable,
+		checkpointTable: defaultSQLCheckpointTable,
+		checkpointName:  defaultCheckpointName,
+		placeholder:     func(int) string { return "?" },
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.stateTable == "" || s.checkpointTable == "" {
+		return nil, errors.New("table names are required")
+	}
+	return s, nil
+}
+
+// Apply projects the writes of the block and records the block as checkpoint in one database
+// transaction. Blocks which aren't after the checkpoint are ignored.
+func (s *SQLSink) Apply(block *Block) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+
+	if err := s.apply(tx, block); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			logger.Warnf("Rollback of block %d failed: %s", block.Number, rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrapf(err, "failed to commit block %d", block.Number)
+	}
+	return nil
+}
+
+func (s *SQLSink) apply(tx *sql.Tx, block *Block) error {
+	lastBlock, ok, err := s.lastBlock(tx)
+	if err != nil {
+		return err
+	}
+	if ok && block.Number <= lastBlock {
+		logger.Debugf("Ignoring block %d which isn't after checkpoint %d", block.Number, lastBlock)
+		return nil
+	}
+
+	deleteStmt := s.query("DELETE FROM %s WHERE namespace = %s AND collection = %s AND state_key = %s", s.stateTable, 1, 2, 3)
+	insertStmt := s.query("INSERT INTO %s (namespace, collection, state_key, value, block_num, tx_num, tx_id) VALUES (%s, %s, %s, %s, %s, %s, %s)", s.stateTable, 1, 2, 3, 4, 5, 6, 7)
+
+	for _, t := range block.Transactions {
+		for _, w := range t.Writes {
+			if _, err := tx.Exec(deleteStmt, w.Namespace, w.Collection, w.Key); err != nil {
+				return errors.Wrapf(err, "failed to delete key [%s] of namespace [%s]", w.Key, w.Namespace)
+			}
+			if w.IsDelete {
+				continue
+			}
+			if _, err := tx.Exec(insertStmt, w.Namespace, w.Collection, w.Key, w.Value, int64(block.Number), int64(t.TxNum), t.TxID); err != nil {
+				return errors.Wrapf(err, "failed to insert key [%s] of namespace [%s]", w.Key, w.Namespace)
+			}
+		}
+	}
+
+	return s.setLastBlock(tx, block.Number, ok)
+}
+
+// LastBlock returns the number of the last block which was applied, or false if none was applied
+func (s *SQLSink) LastBlock() (uint64, bool, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, false, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			logger.Debugf("Rollback of checkpoint query failed: %s", err)
+		}
+	}()
+	return s.lastBlock(tx)
+}
+
+// SetLastBlock records the number of the last block which was applied
+func (s *SQLSink) SetLastBlock(blockNum uint64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	_, ok, err := s.lastBlock(tx)
+	if err == nil {
+		err = s.setLastBlock(tx, blockNum, ok)
+	}
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			logger.Warnf("Rollback of checkpoint failed: %s", rbErr)
+		}
+		return err
+	}
+	return errors.Wrap(tx.Commit(), "failed to commit checkpoint")
+}
+
+func (s *SQLSink) lastBlock(tx *sql.Tx) (uint64, bool, error) {
+	var blockNum int64
+	err := tx.QueryRow(s.query("SELECT block_num FROM %s WHERE name = %s", s.checkpointTable, 1), s.checkpointName).Scan(&blockNum)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "failed to query checkpoint [%s]", s.checkpointName)
+	}
+	return uint64(blockNum), true, nil
+}
+
+func (s *SQLSink) setLastBlock(tx *sql.Tx, blockNum uint64, exists bool) error {
+	var err error
+	if exists {
+		_, err = tx.Exec(s.query("UPDATE %s SET block_num = %s WHERE name = %s", s.checkpointTable, 1, 2), int64(blockNum), s.checkpointName)
+	} else {
+		_, err = tx.Exec(s.query("INSERT INTO %s (name, block_num) VALUES (%s, %s)", s.checkpointTable, 1, 2), s.checkpointName, int64(blockNum))
+	}
+	return errors.Wrapf(err, "failed to store checkpoint [%s]", s.checkpointName)
+}
+
+// query formats the statement with the given table and placeholders
+func (s *SQLSink) query(format, table string, placeholders ...int) string {
+	args := []interface{}{table}
+	for _, n := range placeholders {
+		args = append(args, s.placeholder(n))
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,292 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package offchain
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLSink(t *testing.T) {
+	db, fake := openFakeDB(t)
+	defer db.Close()
+
+	sink, err := NewSQLSink(db)
+	require.NoError(t, err)
+
+	_, ok, err := sink.LastBlock()
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	block := &Block{Number: 1, Transactions: []*Transaction{{
+		TxID: "tx1",
+		Writes: []*Write{
+			{Namespace: "mycc", Key: "a", Value: []byte("1")},
+			{Namespace: "mycc", Collection: "coll1", Key: "b", Value: []byte("2")},
+		},
+	}}}
+	require.NoError(t, sink.Apply(block))
+	assert.Equal(t, map[string]string{"mycc//a": "1", "mycc/coll1/b": "2"}, fake.values())
+
+	lastBlock, ok, err := sink.LastBlock()
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, 1, lastBlock)
+
+	// Replayed blocks are ignored
+	block.Transactions[0].Writes[0].Value = []byte("replayed")
+	require.NoError(t, sink.Apply(block))
+	assert.Equal(t, "1", fake.values()["mycc//a"])
+
+	block = &Block{Number: 2, Transactions: []*Transaction{{
+		TxID: "tx2",
+		Writes: []*Write{
+			{Namespace: "mycc", Key: "a", IsDelete: true},
+			{Namespace: "mycc", Key: "c", Value: []byte("3")},
+		},
+	}}}
+	require.NoError(t, sink.Apply(block))
+	assert.Equal(t, map[string]string{"mycc/coll1/b": "2", "mycc//c": "3"}, fake.values())
+
+	require.NoError(t, sink.SetLastBlock(5))
+	lastBlock, _, err = sink.LastBlock()
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, lastBlock)
+}
+
+func TestSQLSinkRollback(t *testing.T) {
+	db, fake := openFakeDB(t)
+	defer db.Close()
+
+	sink, err := NewSQLSink(db, WithSQLTables("state", "checkpoint"), WithSQLCheckpointName("mychannel"))
+	require.NoError(t, err)
+
+	fake.failKey = "b"
+	block := &Block{Number: 1, Transactions: []*Transaction{{
+		TxID: "tx1",
+		Writes: []*Write{
+			{Namespace: "mycc", Key: "a", Value: []byte("1")},
+			{Namespace: "mycc", Key: "b", Value: []byte("2")},
+		},
+	}}}
+	require.Error(t, sink.Apply(block))
+	assert.Empty(t, fake.values(), "expecting writes to be rolled back")
+
+	_, ok, err := sink.LastBlock()
+	require.NoError(t, err)
+	assert.False(t, ok, "expecting checkpoint to be rolled back")
+}
+
+func TestSQLSinkPlaceholders(t *testing.T) {
+	db, fake := openFakeDB(t)
+	defer db.Close()
+
+	sink, err := NewSQLSink(db, WithSQLDollarPlaceholders())
+	require.NoError(t, err)
+	require.NoError(t, sink.SetLastBlock(1))
+
+	for _, stmt := range fake.statements() {
+		assert.NotContains(t, stmt, "?")
+	}
+	assert.Contains(t, fake.statements(), "INSERT INTO offchain_checkpoint (name, block_num) VALUES ($1, $2)")
+
+	_, err = NewSQLSink(nil)
+	assert.Error(t, err)
+}
+
+const fakeDriverName = "offchain-fake"
+
+var (
+	fakeDBsLock sync.Mutex
+	fakeDBs     = make(map[string]*fakeDB)
+)
+
+func init() {
+	sql.Register(fakeDriverName, fakeDriver{})
+}
+
+// openFakeDB opens a fake database which understands the statements of the SQL sink
+func openFakeDB(t *testing.T) (*sql.DB, *fakeDB) {
+	fake := &fakeDB{state: make(map[string]string), checkpoints: make(map[string]int64)}
+
+	fakeDBsLock.Lock()
+	fakeDBs[t.Name()] = fake
+	fakeDBsLock.Unlock()
+
+	db, err := sql.Open(fakeDriverName, t.Name())
+	require.NoError(t, err)
+	return db, fake
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	fakeDBsLock.Lock()
+	defer fakeDBsLock.Unlock()
+
+	fake, ok := fakeDBs[name]
+	if !ok {
+		return nil, errors.Errorf("unknown database [%s]", name)
+	}
+	return &fakeConn{db: fake}, nil
+}
+
+type fakeDB struct {
+	lock        sync.Mutex
+	state       map[string]string
+	checkpoints map[string]int64
+	stmts       []string
+	failKey     string
+}
+
+func (db *fakeDB) values() map[string]string {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	values := make(map[string]string)
+	for k, v := range db.state {
+		values[k] = v
+	}
+	return values
+}
+
+func (db *fakeDB) statements() []string {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	return append([]string{}, db.stmts...)
+}
+
+type fakeConn struct {
+	db          *fakeDB
+	state       map[string]string
+	checkpoints map[string]int64
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+// Begin takes a snapshot of the database, which is restored on rollback
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.db.lock.Lock()
+	defer c.db.lock.Unlock()
+
+	c.state = make(map[string]string)
+	for k, v := range c.db.state {
+		c.state[k] = v
+	}
+	c.checkpoints = make(map[string]int64)
+	for k, v := range c.db.checkpoints {
+		c.checkpoints[k] = v
+	}
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	c.db.lock.Lock()
+	defer c.db.lock.Unlock()
+
+	c.db.state = c.state
+	c.db.checkpoints = c.checkpoints
+	return nil
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	db := s.conn.db
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.stmts = append(db.stmts, s.query)
+
+	switch {
+	case strings.HasPrefix(s.query, "DELETE FROM"):
+		delete(db.state, stateKey(args))
+	case strings.HasPrefix(s.query, "INSERT INTO") && len(args) == 7:
+		if args[2].(string) == db.failKey {
+			return nil, errors.New("insert failed")
+		}
+		db.state[stateKey(args)] = string(args[3].([]byte))
+	case strings.HasPrefix(s.query, "INSERT INTO"):
+		db.checkpoints[args[0].(string)] = args[1].(int64)
+	case strings.HasPrefix(s.query, "UPDATE"):
+		db.checkpoints[args[1].(string)] = args[0].(int64)
+	default:
+		return nil, errors.Errorf("unexpected statement [%s]", s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	db := s.conn.db
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.stmts = append(db.stmts, s.query)
+
+	if !strings.HasPrefix(s.query, "SELECT block_num FROM") {
+		return nil, errors.Errorf("unexpected query [%s]", s.query)
+	}
+	rows := &fakeRows{}
+	if blockNum, ok := db.checkpoints[args[0].(string)]; ok {
+		rows.values = []driver.Value{blockNum}
+	}
+	return rows, nil
+}
+
+func stateKey(args []driver.Value) string {
+	return args[0].(string) + "/" + args[1].(string) + "/" + args[2].(string)
+}
+
+type fakeRows struct {
+	values []driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"block_num"}
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	dest[0] = r.values[0]
+	r.values = nil
+	return nil
+}
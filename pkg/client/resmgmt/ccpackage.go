@@ -0,0 +1,97 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// CreateSignedCCPackage creates a chaincode package which is endorsed by the identity of the client as an owner
+// of the chaincode. The instantiation policy is signed along with the package. Other owners add their endorsements
+// with SignCCPackage and the package is then installed with the SignedPackage of InstallCCRequest.
+func (rc *Client) CreateSignedCCPackage(req InstallCCRequest, instantiationPolicy *common.SignaturePolicyEnvelope) (*common.Envelope, error) {
+	pkg, err := resource.CreateSignedChaincodePackage(rc.ctx, api.InstallChaincodeRequest{Name: req.Name, Path: req.Path, Version: req.Version, Package: req.Package}, instantiationPolicy)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create signed chaincode package")
+	}
+	return pkg, nil
+}
+
+// SignCCPackage adds the endorsement of the identity of the client to the given signed chaincode package,
+// which was created with CreateSignedCCPackage
+func (rc *Client) SignCCPackage(pkg *common.Envelope) (*common.Envelope, error) {
+	signedPkg, err := resource.SignChaincodePackage(rc.ctx, pkg)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to sign chaincode package")
+	}
+	return signedPkg, nil
+}
+
+// VerifyCCPackage verifies the endorsements of the owners of the given signed chaincode package against the MSPs
+// of the channel, which ensures that the package was created and approved by members of the channel and wasn't
+// altered since. It should be called on packages received from other organizations before they are installed.
+// The deployment spec of the package is returned if the endorsements are valid.
+func (rc *Client) VerifyCCPackage(channelID string, pkg *common.Envelope) (*pb.ChaincodeDeploymentSpec, error) {
+	chCtx, err := contextImpl.NewChannel(
+		func() (context.Client, error) {
+			return rc.ctx, nil
+		},
+		channelID,
+	)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create channel context")
+	}
+
+	membership, err := chCtx.ChannelService().Membership()
+	if err != nil {
+		return nil, errors.WithMessage(err, "membership creation failed")
+	}
+
+	cds, err := resource.VerifySignedChaincodePackage(pkg, membership)
+	if err != nil {
+		return nil, errors.WithMessage(err, "chaincode package verification failed")
+	}
+	return cds, nil
+}
+
+// resolveSignedPackage fills the chaincode ID of the request from its signed package, if any, and checks
+// that the chaincode ID of the request matches the package
+func resolveSignedPackage(req InstallCCRequest) (InstallCCRequest, error) {
+	if req.SignedPackage == nil {
+		return req, nil
+	}
+
+	_, cds, err := resource.UnmarshalSignedChaincodePackage(req.SignedPackage)
+	if err != nil {
+		return req, errors.WithMessage(err, "invalid signed chaincode package")
+	}
+
+	ccID := cds.ChaincodeSpec.ChaincodeId
+	for _, field := range []struct {
+		name     string
+		value    *string
+		expected string
+	}{
+		{"name", &req.Name, ccID.Name},
+		{"path", &req.Path, ccID.Path},
+		{"version", &req.Version, ccID.Version},
+	} {
+		if *field.value == "" {
+			*field.value = field.expected
+		} else if *field.value != field.expected {
+			return req, errors.Errorf("chaincode %s [%s] doesn't match the %s [%s] of the signed package", field.name, *field.value, field.name, field.expected)
+		}
+	}
+	return req, nil
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	"github.com/pkg/errors"
 )
@@ -68,6 +70,18 @@ func WithTargetFilter(targetFilter fab.TargetFilter) RequestOption {
 	}
 }
 
+// WithIdentity signs the request with the given identity instead of the identity of the client,
+// e.g. for a service which acts on behalf of its users
+func WithIdentity(identity msp.SigningIdentity) RequestOption {
+	return func(ctx context.Client, opts *requestOptions) error {
+		if identity == nil {
+			return errors.New("identity is nil")
+		}
+		opts.Identity = identity
+		return nil
+	}
+}
+
 //WithTimeout encapsulates key value pairs of timeout type, timeout duration to Options
 //if not provided, default timeout configuration from config will be used
 func WithTimeout(timeoutType fab.TimeoutType, timeout time.Duration) RequestOption {
@@ -80,9 +94,20 @@ func WithTimeout(timeoutType fab.TimeoutType, timeout time.Duration) RequestOpti
 	}
 }
 
+// WithCallTimeout overrides the configured timeouts of the given types for this call only, e.g.
+// WithCallTimeout(map[fab.TimeoutType]time.Duration{fab.ResMgmt: 10 * time.Second, fab.PeerResponse: 5 * time.Second}).
+// Timeouts which aren't given are taken from the configuration.
+func WithCallTimeout(timeouts map[fab.TimeoutType]time.Duration) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		o.Timeouts = contextImpl.OverrideTimeouts(o.Timeouts, timeouts)
+		return nil
+	}
+}
+
 // WithOrdererURL allows an orderer to be specified for the request.
 // The orderer will be looked-up based on the url argument.
-// A default orderer implementation will be used.
+// A default orderer implementation will be used. For requests on an existing
+// channel the orderer trusts the TLS root certificates of the channel's MSPs.
 func WithOrdererURL(url string) RequestOption {
 	return func(ctx context.Client, opts *requestOptions) error {
 
@@ -91,12 +116,8 @@ func WithOrdererURL(url string) RequestOption {
 			return errors.Wrapf(err, "orderer not found for url : %s", url)
 		}
 
-		orderer, err := ctx.InfraProvider().CreateOrdererFromConfig(ordererCfg)
-		if err != nil {
-			return errors.WithMessage(err, "creating orderer from config failed")
-		}
-
-		return WithOrderer(orderer)(ctx, opts)
+		opts.OrdererConfig = ordererCfg
+		return nil
 	}
 }
 
@@ -123,3 +144,16 @@ func WithRetry(retryOpt retry.Opts) RequestOption {
 		return nil
 	}
 }
+
+// WithRetryPolicy sets the retry options to the registered retry policy with the given name
+// (see retry.RegisterPolicy and the retryPolicies section of the configuration)
+func WithRetryPolicy(name string) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		retryOpts, err := retry.Policy(name)
+		if err != nil {
+			return err
+		}
+		o.Retry = retryOpts
+		return nil
+	}
+}
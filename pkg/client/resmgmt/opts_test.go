@@ -92,3 +92,18 @@ func TestTimeoutOptions(t *testing.T) {
 	assert.True(t, opts.Timeouts[fab.Query] == 45*time.Second, "timeout value by type didn't match with one supplied")
 
 }
+
+func TestCallTimeoutOptions(t *testing.T) {
+	opts := requestOptions{}
+
+	options := []RequestOption{WithTimeout(fab.PeerResponse, 20*time.Second),
+		WithCallTimeout(map[fab.TimeoutType]time.Duration{fab.ResMgmt: 5 * time.Second, fab.PeerResponse: 10 * time.Second})}
+
+	for _, option := range options {
+		assert.NoError(t, option(nil, &opts))
+	}
+
+	assert.Equal(t, 5*time.Second, opts.Timeouts[fab.ResMgmt], "call timeout should be set")
+	assert.Equal(t, 10*time.Second, opts.Timeouts[fab.PeerResponse], "call timeout should override timeout")
+	assert.Len(t, opts.Timeouts, 2)
+}
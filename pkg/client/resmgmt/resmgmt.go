@@ -15,6 +15,8 @@ import (
 	"os"
 	"time"
 
+	"github.com/golang/protobuf/proto"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/verifier"
@@ -29,6 +31,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/ccpackager/lifecycle"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
@@ -43,6 +46,9 @@ type InstallCCRequest struct {
 	Path    string
 	Version string
 	Package *api.CCPackage
+	// SignedPackage is a chaincode package endorsed by the owners of the chaincode (see CreateSignedCCPackage),
+	// which is installed in place of Package. Name, path and version are taken from the package if they are empty.
+	SignedPackage *common.Envelope
 }
 
 // InstallCCResponse contains install chaincode response status
@@ -52,6 +58,30 @@ type InstallCCResponse struct {
 	Info   string
 }
 
+// LifecycleInstallCCRequest contains the parameters of the installation of a chaincode package with the
+// new chaincode lifecycle (Fabric 2.x)
+type LifecycleInstallCCRequest struct {
+	// Label is the label of the package
+	Label string
+	// Package is the chaincode package (see the lifecycle ccpackager)
+	Package []byte
+}
+
+// LifecycleInstallCCResponse contains the response of a peer to the installation of a chaincode package
+// with the new chaincode lifecycle
+type LifecycleInstallCCResponse struct {
+	Target    string
+	Status    int32
+	PackageID string
+	Info      string
+}
+
+// LifecycleInstalledCC is a chaincode package installed on a peer with the new chaincode lifecycle
+type LifecycleInstalledCC struct {
+	PackageID string
+	Label     string
+}
+
 // InstantiateCCRequest contains instantiate chaincode request parameters
 type InstantiateCCRequest struct {
 	Name       string
@@ -87,9 +117,11 @@ type requestOptions struct {
 	Targets       []fab.Peer                        // target peers
 	TargetFilter  fab.TargetFilter                  // target filter
 	Orderer       fab.Orderer                       // use specific orderer
+	OrdererConfig *fab.OrdererConfig                // use orderer created from this config
 	Timeouts      map[fab.TimeoutType]time.Duration //timeout options for resmgmt operations
 	ParentContext reqContext.Context                //parent grpc context for resmgmt operations
 	Retry         retry.Opts
+	Identity      msp.SigningIdentity //identity which signs the request instead of the identity of the client
 }
 
 //SaveChannelRequest used to save channel request
@@ -186,6 +218,7 @@ func (rc *Client) JoinChannel(channelID string, options ...RequestOption) error
 	if err != nil {
 		return errors.WithMessage(err, "failed to get opts for JoinChannel")
 	}
+	rc = rc.requestClient(opts)
 
 	//resolve timeouts
 	rc.resolveTimeouts(&opts)
@@ -337,7 +370,12 @@ func (rc *Client) InstallCC(req InstallCCRequest, options ...RequestOption) ([]I
 	// For each peer query if chaincode installed. If cc is installed treat as success with message 'already installed'.
 	// If cc is not installed try to install, and if that fails add to the list with error and peer name.
 
-	err := checkRequiredInstallCCParams(req)
+	req, err := resolveSignedPackage(req)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkRequiredInstallCCParams(req)
 	if err != nil {
 		return nil, err
 	}
@@ -346,6 +384,7 @@ func (rc *Client) InstallCC(req InstallCCRequest, options ...RequestOption) ([]I
 	if err != nil {
 		return nil, errors.WithMessage(err, "failed to get opts for InstallCC")
 	}
+	rc = rc.requestClient(opts)
 
 	//resolve timeouts
 	rc.resolveTimeouts(&opts)
@@ -396,7 +435,7 @@ func (rc *Client) InstallCC(req InstallCCRequest, options ...RequestOption) ([]I
 }
 
 func (rc *Client) sendIntallCCRequest(req InstallCCRequest, reqCtx reqContext.Context, newTargets []fab.Peer, responses []InstallCCResponse) []InstallCCResponse {
-	icr := api.InstallChaincodeRequest{Name: req.Name, Path: req.Path, Version: req.Version, Package: req.Package}
+	icr := api.InstallChaincodeRequest{Name: req.Name, Path: req.Path, Version: req.Version, Package: req.Package, SignedPackage: req.SignedPackage}
 	transactionProposalResponse, _, _ := resource.InstallChaincode(reqCtx, icr, peer.PeersToTxnProcessors(newTargets))
 	for _, v := range transactionProposalResponse {
 		logger.Debugf("Install chaincode '%s' endorser '%s' returned ProposalResponse status:%v", req.Name, v.Endorser, v.Status)
@@ -439,12 +478,142 @@ func (rc *Client) adjustTargets(targets []fab.Peer, req InstallCCRequest, retry
 }
 
 func checkRequiredInstallCCParams(req InstallCCRequest) error {
-	if req.Name == "" || req.Version == "" || req.Path == "" || req.Package == nil {
+	if req.Name == "" || req.Version == "" || req.Path == "" || (req.Package == nil && req.SignedPackage == nil) {
 		return errors.New("Chaincode name, version, path and chaincode package are required")
 	}
 	return nil
 }
 
+// LifecycleInstallCC installs a chaincode package with the _lifecycle system chaincode on the peers (all peers
+// of the organization of the client or the peers given with WithTargets or WithTargetFilter), which must support
+// the new chaincode lifecycle (Fabric 2.x). The ID of the package is returned with the response of each peer and
+// is used to approve a chaincode definition. Peers which already have the package are skipped.
+func (rc *Client) LifecycleInstallCC(req LifecycleInstallCCRequest, options ...RequestOption) ([]LifecycleInstallCCResponse, error) {
+	if req.Label == "" || len(req.Package) == 0 {
+		return nil, errors.New("package label and chaincode package are required")
+	}
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get opts for LifecycleInstallCC")
+	}
+	rc = rc.requestClient(opts)
+
+	//resolve timeouts
+	rc.resolveTimeouts(&opts)
+
+	//set parent request context for overall timeout
+	parentReqCtx, parentReqCancel := contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeout(opts.Timeouts[fab.ResMgmt]), contextImpl.WithParent(opts.ParentContext))
+	parentReqCtx = reqContext.WithValue(parentReqCtx, contextImpl.ReqContextTimeoutOverrides, opts.Timeouts)
+	defer parentReqCancel()
+
+	//Default targets when targets are not provided in options
+	defaultTargets, err := rc.resolveDefaultTargets(&opts)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get default targets for LifecycleInstallCC")
+	}
+
+	targets, err := rc.calculateTargets(defaultTargets, opts.TargetFilter)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to determine target peers for LifecycleInstallCC")
+	}
+
+	if len(targets) == 0 {
+		return nil, errors.WithStack(status.New(status.ClientStatus, status.NoPeersFound.ToInt32(), "no targets available", nil))
+	}
+
+	packageID := lifecycle.ComputePackageID(req.Label, req.Package)
+	responses, newTargets, errs := rc.adjustLifecycleTargets(targets, packageID, opts.Retry, parentReqCtx)
+
+	if len(newTargets) == 0 {
+		// The package is already installed on all targets and/or
+		// we are unable to verify if it's installed on target(s)
+		return responses, errs.ToError()
+	}
+
+	reqCtx, cancel := contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeoutType(fab.ResMgmt), contextImpl.WithParent(parentReqCtx))
+	defer cancel()
+
+	installResponses, _, err := resource.LifecycleInstallChaincode(reqCtx, req.Package, peer.PeersToTxnProcessors(newTargets), resource.WithRetry(opts.Retry))
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, v := range installResponses {
+		logger.Debugf("Install chaincode package '%s' endorser '%s' returned ProposalResponse status:%v", packageID, v.Endorser, v.Status)
+		responses = append(responses, LifecycleInstallCCResponse{Target: v.Endorser, Status: v.Status, PackageID: v.PackageID})
+	}
+
+	return responses, errs.ToError()
+}
+
+// adjustLifecycleTargets returns the targets on which the package with the given ID isn't installed yet
+func (rc *Client) adjustLifecycleTargets(targets []fab.Peer, packageID string, retry retry.Opts, parentReqCtx reqContext.Context) ([]LifecycleInstallCCResponse, []fab.Peer, multi.Errors) {
+	errs := multi.Errors{}
+
+	responses := make([]LifecycleInstallCCResponse, 0)
+
+	newTargets := make([]fab.Peer, 0)
+	for _, target := range targets {
+		installed, err := rc.isPackageInstalled(parentReqCtx, packageID, target, retry)
+		if err != nil {
+			errs = append(errs, errors.Errorf("unable to verify if chaincode package is installed on %s. Got error: %s", target.URL(), err.Error()))
+			continue
+		}
+		if installed {
+			responses = append(responses, LifecycleInstallCCResponse{Target: target.URL(), PackageID: packageID, Info: "already installed"})
+		} else {
+			newTargets = append(newTargets, target)
+		}
+	}
+
+	return responses, newTargets, errs
+}
+
+func (rc *Client) isPackageInstalled(parentReqCtx reqContext.Context, packageID string, target fab.ProposalProcessor, retryOpts retry.Opts) (bool, error) {
+	reqCtx, cancel := contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeoutType(fab.PeerResponse), contextImpl.WithParent(parentReqCtx))
+	defer cancel()
+
+	installed, err := resource.LifecycleQueryInstalledChaincodes(reqCtx, target, resource.WithRetry(retryOpts))
+	if err != nil {
+		return false, err
+	}
+	for _, cc := range installed {
+		if cc.PackageID == packageID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// LifecycleQueryInstalledCC queries the chaincode packages installed on a peer with the new chaincode lifecycle.
+// Valid option is WithTargets with a single target.
+func (rc *Client) LifecycleQueryInstalledCC(options ...RequestOption) ([]LifecycleInstalledCC, error) {
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, err
+	}
+	rc = rc.requestClient(opts)
+
+	if len(opts.Targets) != 1 {
+		return nil, errors.New("only one target is supported")
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.PeerResponse)
+	defer cancel()
+
+	installed, err := resource.LifecycleQueryInstalledChaincodes(reqCtx, opts.Targets[0], resource.WithRetry(opts.Retry))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]LifecycleInstalledCC, len(installed))
+	for i, cc := range installed {
+		result[i] = LifecycleInstalledCC{PackageID: cc.PackageID, Label: cc.Label}
+	}
+	return result, nil
+}
+
 // InstantiateCC instantiates chaincode using default settings
 func (rc *Client) InstantiateCC(channelID string, req InstantiateCCRequest, options ...RequestOption) (InstantiateCCResponse, error) {
 
@@ -452,6 +621,7 @@ func (rc *Client) InstantiateCC(channelID string, req InstantiateCCRequest, opti
 	if err != nil {
 		return InstantiateCCResponse{}, errors.WithMessage(err, "failed to get opts for InstantiateCC")
 	}
+	rc = rc.requestClient(opts)
 
 	reqCtx, cancel := rc.createRequestContext(opts, fab.ResMgmt)
 	defer cancel()
@@ -467,6 +637,7 @@ func (rc *Client) UpgradeCC(channelID string, req UpgradeCCRequest, options ...R
 	if err != nil {
 		return UpgradeCCResponse{}, errors.WithMessage(err, "failed to get opts for UpgradeCC")
 	}
+	rc = rc.requestClient(opts)
 
 	reqCtx, cancel := rc.createRequestContext(opts, fab.ResMgmt)
 	defer cancel()
@@ -483,6 +654,7 @@ func (rc *Client) QueryInstalledChaincodes(options ...RequestOption) (*pb.Chainc
 	if err != nil {
 		return nil, err
 	}
+	rc = rc.requestClient(opts)
 
 	if len(opts.Targets) != 1 {
 		return nil, errors.New("only one target is supported")
@@ -502,6 +674,7 @@ func (rc *Client) QueryInstantiatedChaincodes(channelID string, options ...Reque
 	if err != nil {
 		return nil, err
 	}
+	rc = rc.requestClient(opts)
 
 	chCtx, err := contextImpl.NewChannel(
 		func() (context.Client, error) {
@@ -569,6 +742,7 @@ func (rc *Client) QueryChannels(options ...RequestOption) (*pb.ChannelQueryRespo
 	if err != nil {
 		return nil, err
 	}
+	rc = rc.requestClient(opts)
 
 	if len(opts.Targets) != 1 {
 		return nil, errors.New("only one target is supported")
@@ -668,6 +842,10 @@ func (rc *Client) sendCCProposal(reqCtx reqContext.Context, ccProposalType chain
 	if err != nil {
 		return fab.EmptyTransactionID, errors.WithMessage(err, "get channel config failed")
 	}
+	if chconfig.IsCapabilitySupported(chConfig, fab.ApplicationGroupKey, fab.V2_0Capability) {
+		// Channels with V2_0 application capabilities manage chaincodes with the new chaincode lifecycle
+		return fab.EmptyTransactionID, errors.Errorf("legacy chaincode instantiate/upgrade is not supported on channel [%s] with %s application capabilities", channelID, fab.V2_0Capability)
+	}
 	transactor, err := rc.ctx.InfraProvider().CreateChannelTransactor(reqCtx, chConfig)
 	if err != nil {
 		return fab.EmptyTransactionID, errors.WithMessage(err, "get channel transactor failed")
@@ -774,6 +952,7 @@ func (rc *Client) SaveChannel(req SaveChannelRequest, options ...RequestOption)
 	if err != nil {
 		return SaveChannelResponse{}, err
 	}
+	rc = rc.requestClient(opts)
 
 	if req.ChannelConfigPath != "" {
 		configReader, err1 := os.Open(req.ChannelConfigPath)
@@ -806,7 +985,7 @@ func (rc *Client) SaveChannel(req SaveChannelRequest, options ...RequestOption)
 		return SaveChannelResponse{}, errors.WithMessage(err, "failed to find orderer for request")
 	}
 
-	configSignatures, err := rc.getConfigSignatures(req, chConfig)
+	configSignatures, err := rc.getConfigSignatures(req.SigningIdentities, chConfig)
 	if err != nil {
 		return SaveChannelResponse{}, err
 	}
@@ -829,6 +1008,93 @@ func (rc *Client) SaveChannel(req SaveChannelRequest, options ...RequestOption)
 	return SaveChannelResponse{TransactionID: txID}, nil
 }
 
+// UpdateChannelConfig updates the configuration of the channel in one call: the current configuration is fetched
+// from the orderer and passed to the modify function, which changes it in place (e.g. adds an organization or
+// changes the batch size), then the config update is computed, signed by the given identities (or the identity of
+// the client if none are given) and submitted to the orderer. The signers must satisfy the modification policies
+// of the modified elements of the configuration.
+// Valid request options are WithOrdererURL, WithOrderer, WithTimeout, WithRetry and WithIdentity
+func (rc *Client) UpdateChannelConfig(channelID string, modify func(config *common.Config) error, signers []msp.SigningIdentity, options ...RequestOption) (SaveChannelResponse, error) {
+	if channelID == "" || modify == nil {
+		return SaveChannelResponse{}, errors.New("must provide channel ID and modify function")
+	}
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return SaveChannelResponse{}, err
+	}
+	rc = rc.requestClient(opts)
+
+	orderer, err := rc.requestChannelOrderer(&opts, channelID)
+	if err != nil {
+		return SaveChannelResponse{}, errors.WithMessage(err, "failed to find orderer for request")
+	}
+
+	original, err := rc.currentConfig(channelID, orderer, opts)
+	if err != nil {
+		return SaveChannelResponse{}, err
+	}
+
+	updated := proto.Clone(original).(*common.Config)
+	if err = modify(updated); err != nil {
+		return SaveChannelResponse{}, errors.WithMessage(err, "modifying channel config failed")
+	}
+
+	configUpdate, err := resource.ComputeConfigUpdate(channelID, original, updated)
+	if err != nil {
+		return SaveChannelResponse{}, errors.WithMessage(err, "computing channel config update failed")
+	}
+	chConfig, err := proto.Marshal(configUpdate)
+	if err != nil {
+		return SaveChannelResponse{}, errors.Wrap(err, "marshal channel config update failed")
+	}
+
+	configSignatures, err := rc.getConfigSignatures(signers, chConfig)
+	if err != nil {
+		return SaveChannelResponse{}, err
+	}
+
+	request := api.CreateChannelRequest{
+		Name:       channelID,
+		Orderer:    orderer,
+		Config:     chConfig,
+		Signatures: configSignatures,
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.OrdererResponse)
+	defer cancel()
+
+	txID, err := resource.CreateChannel(reqCtx, request, resource.WithRetry(opts.Retry))
+	if err != nil {
+		return SaveChannelResponse{}, errors.WithMessage(err, "update channel config failed")
+	}
+
+	return SaveChannelResponse{TransactionID: txID}, nil
+}
+
+// currentConfig returns the configuration of the channel from its last config block
+func (rc *Client) currentConfig(channelID string, orderer fab.Orderer, opts requestOptions) (*common.Config, error) {
+	reqCtx, cancel := rc.createRequestContext(opts, fab.OrdererResponse)
+	defer cancel()
+
+	block, err := resource.LastConfigFromOrderer(reqCtx, channelID, orderer, resource.WithRetry(opts.Retry))
+	if err != nil {
+		return nil, errors.WithMessage(err, "config block retrieval failed")
+	}
+
+	if len(block.GetData().GetData()) == 0 {
+		return nil, errors.New("config block doesn't contain any transaction")
+	}
+	configEnvelope, err := resource.CreateConfigEnvelope(block.Data.Data[0])
+	if err != nil {
+		return nil, errors.WithMessage(err, "extracting channel config failed")
+	}
+	if configEnvelope.Config == nil {
+		return nil, errors.New("config block doesn't contain channel config")
+	}
+	return configEnvelope.Config, nil
+}
+
 func (rc *Client) validateSaveChannelRequest(req SaveChannelRequest) error {
 
 	if req.ChannelID == "" || req.ChannelConfig == nil {
@@ -837,14 +1103,14 @@ func (rc *Client) validateSaveChannelRequest(req SaveChannelRequest) error {
 	return nil
 }
 
-func (rc *Client) getConfigSignatures(req SaveChannelRequest, chConfig []byte) ([]*common.ConfigSignature, error) {
+func (rc *Client) getConfigSignatures(signingIdentities []msp.SigningIdentity, chConfig []byte) ([]*common.ConfigSignature, error) {
 
 	// Signing user has to belong to one of configured channel organisations
 	// In case that order org is one of channel orgs we can use context user
 	var signers []msp.SigningIdentity
 
-	if len(req.SigningIdentities) > 0 {
-		for _, id := range req.SigningIdentities {
+	if len(signingIdentities) > 0 {
+		for _, id := range signingIdentities {
 			if id != nil {
 				signers = append(signers, id)
 			}
@@ -890,8 +1156,9 @@ func (rc *Client) QueryConfigFromOrderer(channelID string, options ...RequestOpt
 	if err != nil {
 		return nil, err
 	}
+	rc = rc.requestClient(opts)
 
-	orderer, err := rc.requestOrderer(&opts, channelID)
+	orderer, err := rc.requestChannelOrderer(&opts, channelID)
 	if err != nil {
 		return nil, errors.WithMessage(err, "failed to find orderer for request")
 	}
@@ -908,14 +1175,69 @@ func (rc *Client) QueryConfigFromOrderer(channelID string, options ...RequestOpt
 
 }
 
+// QueryConfigBlockFromOrderer returns the current configuration block of the channel from orderer
+// Valid request option is WithOrdererID
+// If orderer id is not provided orderer will be defaulted to channel orderer (if configured) or random orderer from config
+func (rc *Client) QueryConfigBlockFromOrderer(channelID string, options ...RequestOption) (*common.Block, error) {
+	return rc.queryBlockFromOrderer(channelID, options, func(reqCtx reqContext.Context, orderer fab.Orderer, opts ...resource.Opt) (*common.Block, error) {
+		return resource.LastConfigFromOrderer(reqCtx, channelID, orderer, opts...)
+	})
+}
+
+// QueryNewestBlockFromOrderer returns the newest block of the channel from orderer
+// Valid request option is WithOrdererID
+// If orderer id is not provided orderer will be defaulted to channel orderer (if configured) or random orderer from config
+func (rc *Client) QueryNewestBlockFromOrderer(channelID string, options ...RequestOption) (*common.Block, error) {
+	return rc.queryBlockFromOrderer(channelID, options, func(reqCtx reqContext.Context, orderer fab.Orderer, opts ...resource.Opt) (*common.Block, error) {
+		return resource.NewestBlockFromOrderer(reqCtx, channelID, orderer, opts...)
+	})
+}
+
+// QueryBlockFromOrderer returns the block with the given number of the channel from orderer
+// Valid request option is WithOrdererID
+// If orderer id is not provided orderer will be defaulted to channel orderer (if configured) or random orderer from config
+func (rc *Client) QueryBlockFromOrderer(channelID string, blockNumber uint64, options ...RequestOption) (*common.Block, error) {
+	return rc.queryBlockFromOrderer(channelID, options, func(reqCtx reqContext.Context, orderer fab.Orderer, opts ...resource.Opt) (*common.Block, error) {
+		return resource.BlockFromOrderer(reqCtx, channelID, orderer, blockNumber, opts...)
+	})
+}
+
+type blockRetriever func(reqCtx reqContext.Context, orderer fab.Orderer, opts ...resource.Opt) (*common.Block, error)
+
+func (rc *Client) queryBlockFromOrderer(channelID string, options []RequestOption, retrieve blockRetriever) (*common.Block, error) {
+	if channelID == "" {
+		return nil, errors.New("must provide channel ID")
+	}
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, err
+	}
+	rc = rc.requestClient(opts)
+
+	orderer, err := rc.requestChannelOrderer(&opts, channelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to find orderer for request")
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.OrdererResponse)
+	defer cancel()
+
+	block, err := retrieve(reqCtx, orderer, resource.WithRetry(opts.Retry))
+	if err != nil {
+		return nil, errors.WithMessage(err, "block retrieval failed")
+	}
+	return block, nil
+}
+
 func (rc *Client) requestOrderer(opts *requestOptions, channelID string) (fab.Orderer, error) {
 	if opts.Orderer != nil {
 		return opts.Orderer, nil
 	}
 
-	ordererCfg, err := rc.ordererConfig(channelID)
+	ordererCfg, err := rc.requestOrdererConfig(opts, channelID)
 	if err != nil {
-		return nil, errors.WithMessage(err, "orderer not found")
+		return nil, err
 	}
 
 	orderer, err := rc.ctx.InfraProvider().CreateOrdererFromConfig(ordererCfg)
@@ -926,6 +1248,37 @@ func (rc *Client) requestOrderer(opts *requestOptions, channelID string) (fab.Or
 
 }
 
+// requestChannelOrderer returns the orderer for a request on an existing channel. Connections to
+// the orderer trust the TLS root certificates of the channel's MSPs.
+func (rc *Client) requestChannelOrderer(opts *requestOptions, channelID string) (fab.Orderer, error) {
+	if opts.Orderer != nil {
+		return opts.Orderer, nil
+	}
+
+	ordererCfg, err := rc.requestOrdererConfig(opts, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	orderer, err := rc.ctx.InfraProvider().CreateChannelOrdererFromConfig(rc.ctx, channelID, ordererCfg)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create orderer from config")
+	}
+	return orderer, nil
+}
+
+func (rc *Client) requestOrdererConfig(opts *requestOptions, channelID string) (*fab.OrdererConfig, error) {
+	if opts.OrdererConfig != nil {
+		return opts.OrdererConfig, nil
+	}
+
+	ordererCfg, err := rc.ordererConfig(channelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "orderer not found")
+	}
+	return ordererCfg, nil
+}
+
 func (rc *Client) ordererConfig(channelID string) (*fab.OrdererConfig, error) {
 	orderers, err := rc.ctx.EndpointConfig().ChannelOrderers(channelID)
 
@@ -947,6 +1300,49 @@ func (rc *Client) ordererConfig(channelID string) (*fab.OrdererConfig, error) {
 	return &orderers[randomNumber], nil
 }
 
+// WithIdentity returns a client which shares the configuration and the connections of this client and
+// whose requests are signed by the given identity, e.g. for a service which acts on behalf of its users.
+// The identity of a single request may be set with the WithIdentity request option instead.
+func (rc *Client) WithIdentity(identity msp.SigningIdentity) *Client {
+	providers := context.Providers(rc.ctx)
+	if c, ok := rc.ctx.(*contextImpl.Client); ok {
+		providers = c.Providers
+	}
+
+	clone := *rc
+	clone.ctx = &contextImpl.Client{Providers: providers, SigningIdentity: identity}
+
+	localCtxProvider := rc.localCtxProvider
+	clone.localCtxProvider = func() (context.Local, error) {
+		local, err := localCtxProvider()
+		if err != nil {
+			return nil, err
+		}
+		return &localContext{Client: clone.ctx, local: local}, nil
+	}
+	return &clone
+}
+
+// requestClient returns the client which performs a request, i.e. this client unless the request
+// is signed by another identity
+func (rc *Client) requestClient(opts requestOptions) *Client {
+	if opts.Identity == nil {
+		return rc
+	}
+	return rc.WithIdentity(opts.Identity)
+}
+
+// localContext is the local context of a client whose requests are signed by another identity
+type localContext struct {
+	context.Client
+	local context.Local
+}
+
+// LocalDiscoveryService returns the local discovery service of the original local context
+func (c *localContext) LocalDiscoveryService() fab.DiscoveryService {
+	return c.local.LocalDiscoveryService()
+}
+
 // prepareRequestOpts prepares request options
 func (rc *Client) prepareRequestOpts(options ...RequestOption) (requestOptions, error) {
 	opts := requestOptions{}
@@ -973,7 +1369,9 @@ func (rc *Client) createRequestContext(opts requestOptions, defaultTimeoutType f
 		opts.Timeouts[defaultTimeoutType] = rc.ctx.EndpointConfig().Timeout(defaultTimeoutType)
 	}
 
-	return contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeout(opts.Timeouts[defaultTimeoutType]), contextImpl.WithParent(opts.ParentContext))
+	reqCtx, cancel := contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeout(opts.Timeouts[defaultTimeoutType]), contextImpl.WithParent(opts.ParentContext))
+	//Add timeout overrides here as a value so that they're used by the requests to the peers and orderers
+	return reqContext.WithValue(reqCtx, contextImpl.ReqContextTimeoutOverrides, opts.Timeouts), cancel
 }
 
 //resolveTimeouts sets default for timeouts from config if not provided through opts
@@ -121,6 +121,117 @@ func TestJoinChannelSuccess(t *testing.T) {
 
 }
 
+func TestQueryBlockFromOrderer(t *testing.T) {
+	ctx := setupTestContext("test", "Org1MSP")
+
+	orderer := fcmocks.NewMockOrderer("", nil)
+	defer orderer.Close()
+	orderer.EnqueueForSendDeliver(fcmocks.NewSimpleMockBlock())
+	orderer.EnqueueForSendDeliver(common.Status_SUCCESS)
+	orderer.EnqueueForSendDeliver(fcmocks.NewSimpleMockBlock())
+	orderer.EnqueueForSendDeliver(common.Status_SUCCESS)
+
+	setupCustomOrderer(ctx, orderer)
+
+	rc := setupResMgmtClient(t, ctx)
+
+	if _, err := rc.QueryBlockFromOrderer("mychannel", 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rc.QueryNewestBlockFromOrderer("mychannel"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rc.QueryBlockFromOrderer("", 0); err == nil {
+		t.Fatal("Should have failed for empty channel ID")
+	}
+}
+
+func TestUpdateChannelConfig(t *testing.T) {
+	ctx := setupTestContext("test", "Org1MSP")
+
+	broadcastListener := make(chan *fab.SignedEnvelope)
+	orderer := fcmocks.NewMockOrderer("", broadcastListener)
+	defer orderer.Close()
+	builder := &fcmocks.MockConfigBlockBuilder{
+		MockConfigGroupBuilder: fcmocks.MockConfigGroupBuilder{
+			ModPolicy:      "Admins",
+			MSPNames:       []string{"Org1MSP"},
+			OrdererAddress: "localhost:9999",
+		},
+	}
+	orderer.EnqueueForSendDeliver(builder.Build())
+	orderer.EnqueueForSendDeliver(common.Status_SUCCESS)
+	orderer.EnqueueForSendDeliver(builder.Build())
+	orderer.EnqueueForSendDeliver(common.Status_SUCCESS)
+
+	rc := setupResMgmtClient(t, ctx)
+
+	addresses, err := proto.Marshal(&common.OrdererAddresses{Addresses: []string{"orderer2.example.com:7050"}})
+	assert.NoError(t, err)
+	resp, err := rc.UpdateChannelConfig("mychannel", func(config *common.Config) error {
+		config.ChannelGroup.Values["OrdererAddresses"].Value = addresses
+		return nil
+	}, nil, WithOrderer(orderer))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, resp.TransactionID)
+
+	envelope := <-orderer.BroadcastQueue
+	payload := &common.Payload{}
+	assert.NoError(t, proto.Unmarshal(envelope.Payload, payload))
+	configUpdateEnvelope := &common.ConfigUpdateEnvelope{}
+	assert.NoError(t, proto.Unmarshal(payload.Data, configUpdateEnvelope))
+	assert.Len(t, configUpdateEnvelope.Signatures, 1, "expecting config update to be signed by the client")
+	configUpdate := &common.ConfigUpdate{}
+	assert.NoError(t, proto.Unmarshal(configUpdateEnvelope.ConfigUpdate, configUpdate))
+	assert.Equal(t, "mychannel", configUpdate.ChannelId)
+	assert.Equal(t, addresses, configUpdate.WriteSet.Values["OrdererAddresses"].Value)
+	assert.Equal(t, uint64(1), configUpdate.WriteSet.Values["OrdererAddresses"].Version)
+
+	_, err = rc.UpdateChannelConfig("", func(config *common.Config) error { return nil }, nil, WithOrderer(orderer))
+	assert.Error(t, err, "expecting error without channel ID")
+
+	orderer.EnqueueForSendDeliver(builder.Build())
+	orderer.EnqueueForSendDeliver(common.Status_SUCCESS)
+	orderer.EnqueueForSendDeliver(builder.Build())
+	orderer.EnqueueForSendDeliver(common.Status_SUCCESS)
+	_, err = rc.UpdateChannelConfig("mychannel", func(config *common.Config) error { return nil }, nil, WithOrderer(orderer))
+	assert.Error(t, err, "expecting error for unmodified config")
+
+	orderer.EnqueueForSendDeliver(builder.Build())
+	orderer.EnqueueForSendDeliver(common.Status_SUCCESS)
+	orderer.EnqueueForSendDeliver(&common.Block{Header: &common.BlockHeader{}})
+	orderer.EnqueueForSendDeliver(common.Status_SUCCESS)
+	_, err = rc.UpdateChannelConfig("mychannel", func(config *common.Config) error { return nil }, nil, WithOrderer(orderer))
+	assert.Error(t, err, "expecting error for config block without transactions")
+}
+
+func TestWithIdentity(t *testing.T) {
+	ctx := setupTestContext("test", "Org1MSP")
+	peer1 := fcmocks.NewMockPeer("peer1", "peer1.example.com:7051")
+	rc := setupResMgmtClientWithLocalPeers(t, ctx, []fab.Peer{peer1})
+	user1 := mspmocks.NewMockSigningIdentity("user1", "Org1MSP")
+
+	user1Client := rc.WithIdentity(user1)
+	assert.Equal(t, "user1", user1Client.ctx.Identifier().ID)
+	assert.Equal(t, "test", rc.ctx.Identifier().ID, "identity of the original client shouldn't change")
+	assert.Equal(t, "user1", user1Client.WithIdentity(user1).ctx.Identifier().ID)
+
+	localCtx, err := user1Client.localCtxProvider()
+	assert.NoError(t, err)
+	assert.Equal(t, "user1", localCtx.Identifier().ID)
+	peers, err := localCtx.LocalDiscoveryService().GetPeers()
+	assert.NoError(t, err)
+	assert.Len(t, peers, 1, "expecting local discovery of the original client")
+
+	opts, err := rc.prepareRequestOpts(WithIdentity(user1))
+	assert.NoError(t, err)
+	assert.Equal(t, "user1", rc.requestClient(opts).ctx.Identifier().ID)
+	assert.True(t, rc.requestClient(requestOptions{}) == rc, "expecting client without request identity")
+
+	_, err = rc.prepareRequestOpts(WithIdentity(nil))
+	assert.Error(t, err, "expecting error for nil identity")
+}
+
 func TestWithFilterOption(t *testing.T) {
 	ctx := setupTestContext("test", "Org1MSP")
 	rc := setupResMgmtClient(t, ctx, getDefaultTargetFilterOption())
@@ -429,6 +540,41 @@ func TestQueryInstalledChaincodes(t *testing.T) {
 
 }
 
+func TestLifecycleInstallCC(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+
+	_, err := rc.LifecycleInstallCC(LifecycleInstallCCRequest{Package: []byte("package")})
+	assert.Error(t, err, "expecting error without label")
+
+	// The package isn't installed on the peer, so it's installed
+	peer1 := fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, MockMSP: "Org1MSP", Status: http.StatusOK}
+	responses, err := rc.LifecycleInstallCC(LifecycleInstallCCRequest{Label: "mycc_1", Package: []byte("package")}, WithTargets(&peer1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(responses) != 1 {
+		t.Fatalf("Expecting one response, got %d", len(responses))
+	}
+	assert.Equal(t, peer1.MockURL, responses[0].Target)
+	assert.Equal(t, int32(http.StatusOK), responses[0].Status)
+	assert.Empty(t, responses[0].Info)
+	assert.Equal(t, 2, peer1.ProcessProposalCalls, "expecting query and install proposals")
+}
+
+func TestLifecycleQueryInstalledCC(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+
+	_, err := rc.LifecycleQueryInstalledCC()
+	assert.Error(t, err, "expecting error without target")
+
+	peer := &fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, MockMSP: "Org1MSP", Status: http.StatusOK}
+	installed, err := rc.LifecycleQueryInstalledCC(WithTargets(peer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, installed)
+}
+
 func TestQueryInstantiatedChaincodes(t *testing.T) {
 	rc := setupDefaultResMgmtClient(t)
 
@@ -664,6 +810,37 @@ func TestInstallCCRequiredParameters(t *testing.T) {
 
 }
 
+func TestInstallSignedCC(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+
+	peer1 := fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com",
+		Status: http.StatusOK, MockRoles: []string{}, MockCert: nil, MockMSP: "Org1MSP"}
+
+	pkg, err := rc.CreateSignedCCPackage(InstallCCRequest{Name: "ID", Version: "v0", Path: "path", Package: &api.CCPackage{Type: 1, Code: []byte("code")}}, cauthdsl.SignedByMspMember("Org1MSP"))
+	if err != nil {
+		t.Fatalf("Failed to create signed chaincode package: %s", err)
+	}
+	pkg, err = rc.SignCCPackage(pkg)
+	if err != nil {
+		t.Fatalf("Failed to sign chaincode package: %s", err)
+	}
+
+	// Name, path and version are taken from the signed package
+	responses, err := rc.InstallCC(InstallCCRequest{SignedPackage: pkg}, WithTargets(&peer1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(responses) != 1 || responses[0].Status != http.StatusOK {
+		t.Fatalf("Should have one successful response")
+	}
+
+	_, err = rc.InstallCC(InstallCCRequest{Name: "ID", Version: "v1", SignedPackage: pkg}, WithTargets(&peer1))
+	assert.Error(t, err, "expecting error for version which doesn't match the signed package")
+
+	_, err = rc.InstallCC(InstallCCRequest{Name: "ID", Version: "v0", Path: "path", SignedPackage: &common.Envelope{}}, WithTargets(&peer1))
+	assert.Error(t, err, "expecting error for invalid signed package")
+}
+
 func TestInstallCCWithOptsRequiredParameters(t *testing.T) {
 
 	rc := setupDefaultResMgmtClient(t)
@@ -1175,6 +1352,36 @@ func TestUpgradeCCDiscoveryError(t *testing.T) {
 
 }
 
+func TestInstantiateCCV2Capability(t *testing.T) {
+	ctx := setupTestContext("Admin", "Org1MSP")
+	ctx.SetEndpointConfig(getNetworkConfig(t))
+
+	rc := setupResMgmtClient(t, ctx)
+
+	// Channels with V2_0 application capabilities don't support legacy instantiate/upgrade
+	chService, err := ctx.ChannelProvider().ChannelService(ctx, "mychannel")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chService.(*fcmocks.MockChannelService).SetCapabilities(fab.ApplicationGroupKey, fab.V2_0Capability)
+	ctx.ChannelProvider().(*fcmocks.MockChannelProvider).SetCustomChannelService(chService)
+
+	peer1, _ := peer.New(fcmocks.NewMockEndpointConfig(), peer.WithURL("127.0.0.1:0"))
+
+	ccPolicy := cauthdsl.SignedByMspMember("Org1MSP")
+	instantiateReq := InstantiateCCRequest{Name: "name", Version: "version", Path: "path", Policy: ccPolicy}
+	_, err = rc.InstantiateCC("mychannel", instantiateReq, WithTargets(peer1))
+	if err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Fatalf("Should have failed to instantiate cc on channel with V2_0 capabilities: %v", err)
+	}
+
+	upgradeReq := UpgradeCCRequest{Name: "name", Version: "version", Path: "path", Policy: ccPolicy}
+	_, err = rc.UpgradeCC("mychannel", upgradeReq, WithTargets(peer1))
+	if err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Fatalf("Should have failed to upgrade cc on channel with V2_0 capabilities: %v", err)
+	}
+}
+
 func TestCCProposal(t *testing.T) {
 
 	ctx := setupTestContext("Admin", "Org1MSP")
@@ -0,0 +1,94 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package token
+
+import (
+	"encoding/json"
+	"regexp"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+// TransferEvent is a transfer of tokens set as chaincode event by the token chaincode. Issuances
+// are transfers from an empty account and redemptions are transfers to an empty account.
+type TransferEvent struct {
+	// TxID is the ID of the transaction of the transfer
+	TxID string
+	// BlockNumber is the number of the block in which the transfer was committed
+	BlockNumber uint64
+	// From is the account from which the tokens were transferred
+	From string
+	// To is the account to which the tokens were transferred
+	To string
+	// Amount is the amount of fungible tokens which were transferred
+	Amount uint64
+	// TokenID is the ID of the non-fungible token which was transferred
+	TokenID string
+}
+
+// transferPayload is the JSON payload of the transfer events of the ERC-20 and ERC-721 samples
+type transferPayload struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Value   uint64 `json:"value"`
+	TokenID string `json:"tokenId"`
+}
+
+// ParseTransferEvent decodes the transfer of the given chaincode event
+func ParseTransferEvent(event *fab.CCEvent) (*TransferEvent, error) {
+	if event == nil {
+		return nil, errors.New("chaincode event is required")
+	}
+	if len(event.Payload) == 0 {
+		return nil, errors.Errorf("chaincode event [%s] of transaction [%s] has no payload (filtered events aren't supported)", event.EventName, event.TxID)
+	}
+
+	payload := transferPayload{}
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return nil, errors.Wrapf(err, "invalid payload for chaincode event [%s] of transaction [%s]", event.EventName, event.TxID)
+	}
+
+	return &TransferEvent{
+		TxID:        event.TxID,
+		BlockNumber: event.BlockNumber,
+		From:        payload.From,
+		To:          payload.To,
+		Amount:      payload.Value,
+		TokenID:     payload.TokenID,
+	}, nil
+}
+
+// RegisterTransferEvent registers for the transfer events of the token chaincode. Events which
+// may not be decoded are logged and dropped. The event channel is closed by Unregister.
+func (c *Client) RegisterTransferEvent() (fab.Registration, <-chan *TransferEvent, error) {
+	reg, ccEvents, err := c.channel.RegisterChaincodeEvent(c.chaincodeID, "^"+regexp.QuoteMeta(c.functions.TransferEvent)+"$")
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to register for transfer events")
+	}
+
+	eventch := make(chan *TransferEvent, cap(ccEvents))
+	go func() {
+		defer close(eventch)
+		for ccEvent := range ccEvents {
+			event, err := ParseTransferEvent(ccEvent)
+			if err != nil {
+				logger.Warnf("Dropping transfer event: %s", err)
+				continue
+			}
+			eventch <- event
+		}
+	}()
+
+	return reg, eventch, nil
+}
+
+// Unregister removes the given registration and closes its event channel
+func (c *Client) Unregister(reg fab.Registration) {
+	c.channel.UnregisterChaincodeEvent(reg)
+}
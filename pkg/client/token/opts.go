@@ -0,0 +1,63 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package token
+
+// Functions are the names of the functions of a token chaincode, and of its transfer event
+type Functions struct {
+	Issue       string
+	Transfer    string
+	Redeem      string
+	Balance     string
+	IssueNFT    string
+	TransferNFT string
+	RedeemNFT   string
+	OwnerOf     string
+	// TransferEvent is the name of the chaincode event set by transfers, issuances and redemptions
+	TransferEvent string
+}
+
+// DefaultFunctions returns the names of the functions of the ERC-20 and ERC-721 token chaincodes
+// of the Fabric samples
+func DefaultFunctions() Functions {
+	return Functions{
+		Issue:         "Mint",
+		Transfer:      "Transfer",
+		Redeem:        "Burn",
+		Balance:       "BalanceOf",
+		IssueNFT:      "MintWithTokenURI",
+		TransferNFT:   "TransferFrom",
+		RedeemNFT:     "Burn",
+		OwnerOf:       "OwnerOf",
+		TransferEvent: "Transfer",
+	}
+}
+
+// ClientOption describes a functional parameter for the New constructor
+type ClientOption func(*Client) error
+
+// WithFunctions overrides the names of the functions of the token chaincode. Names which are
+// left empty keep their default value (see DefaultFunctions).
+func WithFunctions(functions Functions) ClientOption {
+	return func(c *Client) error {
+		merge(&c.functions.Issue, functions.Issue)
+		merge(&c.functions.Transfer, functions.Transfer)
+		merge(&c.functions.Redeem, functions.Redeem)
+		merge(&c.functions.Balance, functions.Balance)
+		merge(&c.functions.IssueNFT, functions.IssueNFT)
+		merge(&c.functions.TransferNFT, functions.TransferNFT)
+		merge(&c.functions.RedeemNFT, functions.RedeemNFT)
+		merge(&c.functions.OwnerOf, functions.OwnerOf)
+		merge(&c.functions.TransferEvent, functions.TransferEvent)
+		return nil
+	}
+}
+
+func merge(name *string, override string) {
+	if override != "" {
+		*name = override
+	}
+}
@@ -0,0 +1,194 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package token enables issuing, transferring and redeeming tokens, and querying balances, on
+// token chaincodes which follow the ERC-20 (fungible tokens) and ERC-721 (non-fungible tokens)
+// conventions of the Fabric token samples. The token client takes care of the encoding of the
+// chaincode arguments and of the decoding of the responses and of the transfer events.
+//  Basic Flow:
+//  1) Prepare channel client
+//  2) Create token client for the token chaincode
+//  3) Issue, transfer, redeem tokens and query balances
+//
+//  chClient, err := channel.New(channelProvider)
+//  ...
+//  tokens, err := token.New(chClient, "token_erc20")
+//  ...
+//  txID, err := tokens.Transfer("recipient", 100)
+package token
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+// ChannelClient is the interface of the channel client used by the token client. It is implemented
+// by channel.Client and by the mock channel client (see mockchannel.Client).
+type ChannelClient interface {
+	Query(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+	Execute(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+	RegisterChaincodeEvent(chainCodeID string, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error)
+	UnregisterChaincodeEvent(registration fab.Registration)
+}
+
+// Client enables access to the tokens of a token chaincode
+type Client struct {
+	channel     ChannelClient
+	chaincodeID string
+	functions   Functions
+}
+
+// New returns a token client for the given token chaincode
+func New(channelClient ChannelClient, chaincodeID string, opts ...ClientOption) (*Client, error) {
+	if channelClient == nil {
+		return nil, errors.New("channel client is required")
+	}
+	if chaincodeID == "" {
+		return nil, errors.New("chaincode ID is required")
+	}
+
+	client := &Client{
+		channel:     channelClient,
+		chaincodeID: chaincodeID,
+		functions:   DefaultFunctions(),
+	}
+
+	for _, opt := range opts {
+		if err := opt(client); err != nil {
+			return nil, err
+		}
+	}
+
+	return client, nil
+}
+
+// Issue mints the given amount of fungible tokens to the account of the caller
+func (c *Client) Issue(amount uint64, options ...channel.RequestOption) (fab.TransactionID, error) {
+	return c.execute(c.functions.Issue, []string{formatAmount(amount)}, options...)
+}
+
+// Transfer transfers the given amount of fungible tokens from the account of the caller to the
+// given account
+func (c *Client) Transfer(to string, amount uint64, options ...channel.RequestOption) (fab.TransactionID, error) {
+	if to == "" {
+		return "", errors.New("recipient is required")
+	}
+	return c.execute(c.functions.Transfer, []string{to, formatAmount(amount)}, options...)
+}
+
+// Redeem burns the given amount of fungible tokens from the account of the caller
+func (c *Client) Redeem(amount uint64, options ...channel.RequestOption) (fab.TransactionID, error) {
+	return c.execute(c.functions.Redeem, []string{formatAmount(amount)}, options...)
+}
+
+// Balance returns the number of tokens held by the given account. For non-fungible token
+// chaincodes, this is the number of tokens owned by the account.
+func (c *Client) Balance(account string, options ...channel.RequestOption) (uint64, error) {
+	if account == "" {
+		return 0, errors.New("account is required")
+	}
+	payload, err := c.query(c.functions.Balance, []string{account}, options...)
+	if err != nil {
+		return 0, err
+	}
+	return parseAmount(payload)
+}
+
+// IssueNFT mints the non-fungible token with the given ID and URI to the account of the caller
+func (c *Client) IssueNFT(tokenID, uri string, options ...channel.RequestOption) (fab.TransactionID, error) {
+	if tokenID == "" {
+		return "", errors.New("token ID is required")
+	}
+	return c.execute(c.functions.IssueNFT, []string{tokenID, uri}, options...)
+}
+
+// TransferNFT transfers the non-fungible token with the given ID from one account to another
+func (c *Client) TransferNFT(from, to, tokenID string, options ...channel.RequestOption) (fab.TransactionID, error) {
+	if from == "" || to == "" {
+		return "", errors.New("sender and recipient are required")
+	}
+	if tokenID == "" {
+		return "", errors.New("token ID is required")
+	}
+	return c.execute(c.functions.TransferNFT, []string{from, to, tokenID}, options...)
+}
+
+// RedeemNFT burns the non-fungible token with the given ID
+func (c *Client) RedeemNFT(tokenID string, options ...channel.RequestOption) (fab.TransactionID, error) {
+	if tokenID == "" {
+		return "", errors.New("token ID is required")
+	}
+	return c.execute(c.functions.RedeemNFT, []string{tokenID}, options...)
+}
+
+// OwnerOf returns the account which owns the non-fungible token with the given ID
+func (c *Client) OwnerOf(tokenID string, options ...channel.RequestOption) (string, error) {
+	if tokenID == "" {
+		return "", errors.New("token ID is required")
+	}
+	payload, err := c.query(c.functions.OwnerOf, []string{tokenID}, options...)
+	if err != nil {
+		return "", err
+	}
+	return unquote(payload), nil
+}
+
+func (c *Client) execute(fcn string, args []string, options ...channel.RequestOption) (fab.TransactionID, error) {
+	response, err := c.channel.Execute(c.request(fcn, args), options...)
+	if err != nil {
+		return "", errors.WithMessage(err, fmt.Sprintf("%s failed on chaincode [%s]", fcn, c.chaincodeID))
+	}
+	return response.TransactionID, nil
+}
+
+func (c *Client) query(fcn string, args []string, options ...channel.RequestOption) ([]byte, error) {
+	response, err := c.channel.Query(c.request(fcn, args), options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("%s failed on chaincode [%s]", fcn, c.chaincodeID))
+	}
+	return response.Payload, nil
+}
+
+func (c *Client) request(fcn string, args []string) channel.Request {
+	request := channel.Request{ChaincodeID: c.chaincodeID, Fcn: fcn}
+	for _, arg := range args {
+		request.Args = append(request.Args, []byte(arg))
+	}
+	return request
+}
+
+// formatAmount encodes amounts as decimal strings, as expected by the token chaincodes
+func formatAmount(amount uint64) string {
+	return strconv.FormatUint(amount, 10)
+}
+
+// parseAmount decodes an amount returned by a token chaincode. Amounts are returned either as
+// decimal numbers or as JSON strings containing a decimal number.
+func parseAmount(payload []byte) (uint64, error) {
+	amount, err := strconv.ParseUint(unquote(payload), 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid amount [%s]", payload)
+	}
+	return amount, nil
+}
+
+// unquote trims the surrounding white space and JSON quotes of a chaincode response
+func unquote(payload []byte) string {
+	s := strings.TrimSpace(string(payload))
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		return unquoted
+	}
+	return s
+}
@@ -0,0 +1,173 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/test/mockchannel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+const ccID = "token"
+
+func TestNew(t *testing.T) {
+	_, err := New(nil, ccID)
+	assert.Error(t, err)
+
+	_, err = New(mockchannel.New(), "")
+	assert.Error(t, err)
+
+	client, err := New(mockchannel.New(), ccID, WithFunctions(Functions{Issue: "Issue", TransferEvent: "Transferred"}))
+	require.NoError(t, err)
+	assert.Equal(t, "Issue", client.functions.Issue)
+	assert.Equal(t, "Transferred", client.functions.TransferEvent)
+	assert.Equal(t, "Burn", client.functions.Redeem)
+}
+
+func TestFungible(t *testing.T) {
+	chClient := mockchannel.New()
+	client, err := New(chClient, ccID)
+	require.NoError(t, err)
+
+	chClient.OnExecute(ccID, "Mint").Return(nil)
+	chClient.OnExecute(ccID, "Transfer").Return(nil)
+	chClient.OnExecute(ccID, "Burn").Return(nil)
+	chClient.OnQuery(ccID, "BalanceOf").Return([]byte("70"))
+
+	_, err = client.Issue(100)
+	require.NoError(t, err)
+	_, err = client.Transfer("bob", 20)
+	require.NoError(t, err)
+	_, err = client.Redeem(10)
+	require.NoError(t, err)
+	balance, err := client.Balance("alice")
+	require.NoError(t, err)
+	assert.EqualValues(t, 70, balance)
+
+	calls := chClient.Calls()
+	require.Len(t, calls, 4)
+	assert.Equal(t, [][]byte{[]byte("100")}, calls[0].Request.Args)
+	assert.Equal(t, [][]byte{[]byte("bob"), []byte("20")}, calls[1].Request.Args)
+	assert.Equal(t, [][]byte{[]byte("10")}, calls[2].Request.Args)
+	assert.Equal(t, [][]byte{[]byte("alice")}, calls[3].Request.Args)
+
+	_, err = client.Transfer("", 20)
+	assert.Error(t, err)
+	_, err = client.Balance("")
+	assert.Error(t, err)
+}
+
+func TestNonFungible(t *testing.T) {
+	chClient := mockchannel.New()
+	client, err := New(chClient, ccID)
+	require.NoError(t, err)
+
+	chClient.OnExecute(ccID, "MintWithTokenURI").Return(nil)
+	chClient.OnExecute(ccID, "TransferFrom").Return(nil)
+	chClient.OnExecute(ccID, "Burn").Return(nil)
+	chClient.OnQuery(ccID, "OwnerOf").Return([]byte(`"bob"`))
+
+	_, err = client.IssueNFT("101", "https://example.com/nft/101")
+	require.NoError(t, err)
+	_, err = client.TransferNFT("alice", "bob", "101")
+	require.NoError(t, err)
+	owner, err := client.OwnerOf("101")
+	require.NoError(t, err)
+	assert.Equal(t, "bob", owner)
+	_, err = client.RedeemNFT("101")
+	require.NoError(t, err)
+
+	calls := chClient.Calls()
+	require.Len(t, calls, 4)
+	assert.Equal(t, [][]byte{[]byte("101"), []byte("https://example.com/nft/101")}, calls[0].Request.Args)
+	assert.Equal(t, [][]byte{[]byte("alice"), []byte("bob"), []byte("101")}, calls[1].Request.Args)
+	assert.Equal(t, [][]byte{[]byte("101")}, calls[3].Request.Args)
+
+	_, err = client.IssueNFT("", "")
+	assert.Error(t, err)
+	_, err = client.TransferNFT("alice", "", "101")
+	assert.Error(t, err)
+}
+
+func TestErrors(t *testing.T) {
+	chClient := mockchannel.New()
+	client, err := New(chClient, ccID)
+	require.NoError(t, err)
+
+	chClient.OnExecute(ccID, "Transfer").ReturnError(errors.New("insufficient funds"))
+	chClient.OnQuery(ccID, "BalanceOf").Return([]byte("many"))
+
+	_, err = client.Transfer("bob", 20)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "insufficient funds")
+
+	_, err = client.Balance("alice")
+	assert.Error(t, err)
+}
+
+func TestParseAmount(t *testing.T) {
+	for _, payload := range []string{"42", " 42\n", `"42"`} {
+		amount, err := parseAmount([]byte(payload))
+		require.NoError(t, err)
+		assert.EqualValues(t, 42, amount)
+	}
+	_, err := parseAmount([]byte("-1"))
+	assert.Error(t, err)
+}
+
+func TestTransferEvents(t *testing.T) {
+	chClient := mockchannel.New()
+	client, err := New(chClient, ccID)
+	require.NoError(t, err)
+
+	reg, eventch, err := client.RegisterTransferEvent()
+	require.NoError(t, err)
+
+	chClient.PublishChaincodeEvent(&fab.CCEvent{ChaincodeID: ccID, EventName: "Transfer", TxID: "tx1", Payload: []byte("invalid")})
+	chClient.PublishChaincodeEvent(&fab.CCEvent{ChaincodeID: ccID, EventName: "Approval", TxID: "tx2", Payload: []byte(`{}`)})
+	chClient.PublishChaincodeEvent(&fab.CCEvent{ChaincodeID: ccID, EventName: "Transfer", TxID: "tx3", BlockNumber: 5, Payload: []byte(`{"from":"alice","to":"bob","value":20}`)})
+	chClient.PublishChaincodeEvent(&fab.CCEvent{ChaincodeID: ccID, EventName: "Transfer", TxID: "tx4", Payload: []byte(`{"from":"bob","to":"carol","tokenId":"101"}`)})
+
+	event := nextEvent(t, eventch)
+	assert.Equal(t, &TransferEvent{TxID: "tx3", BlockNumber: 5, From: "alice", To: "bob", Amount: 20}, event)
+	event = nextEvent(t, eventch)
+	assert.Equal(t, &TransferEvent{TxID: "tx4", From: "bob", To: "carol", TokenID: "101"}, event)
+
+	client.Unregister(reg)
+	select {
+	case _, ok := <-eventch:
+		assert.False(t, ok, "expecting event channel to be closed")
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event channel to be closed")
+	}
+}
+
+func TestParseTransferEvent(t *testing.T) {
+	_, err := ParseTransferEvent(nil)
+	assert.Error(t, err)
+	_, err = ParseTransferEvent(&fab.CCEvent{EventName: "Transfer"})
+	assert.Error(t, err, "expecting error for filtered event")
+}
+
+func nextEvent(t *testing.T, eventch <-chan *TransferEvent) *TransferEvent {
+	select {
+	case event := <-eventch:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for transfer event")
+		return nil
+	}
+}
+
+var _ ChannelClient = (*channel.Client)(nil)
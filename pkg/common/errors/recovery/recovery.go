@@ -0,0 +1,57 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package recovery converts the panics of user-supplied callbacks (e.g. event filters, handlers
+// and hooks) into errors, so that a panicking callback doesn't kill the goroutine of the SDK which
+// invoked it, such as the event dispatcher.
+package recovery
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is the error of a recovered panic
+type PanicError struct {
+	// Value is the value which was passed to panic
+	Value interface{}
+	// Stack is the stack trace of the goroutine at the time of the panic
+	Stack []byte
+}
+
+// Error returns the value of the panic
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("recovered from panic: %v", e.Value)
+}
+
+// Err returns the value of the panic if it is an error (e.g. a runtime error), otherwise nil
+func (e *PanicError) Err() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Call invokes the given function and returns a PanicError if it panics, otherwise nil
+func Call(f func()) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = &PanicError{Value: p, Stack: debug.Stack()}
+		}
+	}()
+
+	f()
+	return nil
+}
+
+// CallE invokes the given function and returns its error, or a PanicError if it panics
+func CallE(f func() error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = &PanicError{Value: p, Stack: debug.Stack()}
+		}
+	}()
+
+	return f()
+}
@@ -0,0 +1,48 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package recovery
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCall(t *testing.T) {
+	called := false
+	assert.NoError(t, Call(func() { called = true }))
+	assert.True(t, called)
+
+	err := Call(func() { panic("handler failed") })
+	panicErr, ok := err.(*PanicError)
+	if assert.True(t, ok, "Expected panic error got %+v", err) {
+		assert.Equal(t, "handler failed", panicErr.Value)
+		assert.NotEmpty(t, panicErr.Stack)
+		assert.Equal(t, "recovered from panic: handler failed", panicErr.Error())
+		assert.Nil(t, panicErr.Err())
+	}
+}
+
+func TestCallE(t *testing.T) {
+	testErr := errors.New("handler error")
+	assert.Equal(t, testErr, CallE(func() error { return testErr }))
+	assert.NoError(t, CallE(func() error { return nil }))
+
+	// Runtime errors may be inspected with Err
+	err := CallE(func() error {
+		var m map[string]int
+		m["key"] = 1
+		return nil
+	})
+	panicErr, ok := errors.Cause(errors.WithMessage(err, "handler failed")).(*PanicError)
+	if assert.True(t, ok, "Expected panic error got %+v", err) {
+		_, ok = panicErr.Err().(runtime.Error)
+		assert.True(t, ok, "Expected runtime error got %+v", panicErr.Err())
+	}
+}
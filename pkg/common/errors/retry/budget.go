@@ -0,0 +1,65 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package retry
+
+import (
+	"sync"
+	"time"
+)
+
+// Budget limits the total number of retries within a sliding time window. A budget is shared
+// by the retry handlers of a client (see Opts.Budget), so that the retries of many failing
+// requests don't amplify an outage of the peers or orderers.
+type Budget struct {
+	maxRetries int
+	window     time.Duration
+	lock       sync.Mutex
+	retries    []time.Time
+	now        func() time.Time
+}
+
+// NewBudget returns a budget which allows up to maxRetries retries within any window of the given duration
+func NewBudget(maxRetries int, window time.Duration) *Budget {
+	return &Budget{
+		maxRetries: maxRetries,
+		window:     window,
+		now:        time.Now,
+	}
+}
+
+// Withdraw records a retry and returns true if the budget allows another retry, otherwise it returns false
+func (b *Budget) Withdraw() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	now := b.now()
+	b.expire(now)
+	if len(b.retries) >= b.maxRetries {
+		return false
+	}
+	b.retries = append(b.retries, now)
+	return true
+}
+
+// Remaining returns the number of retries which the budget allows in the current window
+func (b *Budget) Remaining() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.expire(b.now())
+	return b.maxRetries - len(b.retries)
+}
+
+// expire removes the retries which are outside of the window. The caller must hold the lock.
+func (b *Budget) expire(now time.Time) {
+	cutoff := now.Add(-b.window)
+	i := 0
+	for i < len(b.retries) && !b.retries[i].After(cutoff) {
+		i++
+	}
+	b.retries = b.retries[i:]
+}
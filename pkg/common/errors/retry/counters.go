@@ -0,0 +1,38 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package retry
+
+import (
+	"sync/atomic"
+)
+
+// Counters counts the retry behavior of the retry handlers which share the counters (see
+// Opts.Counters), so that applications may monitor and alert on the retries of an operation.
+// The zero value is ready to use.
+type Counters struct {
+	retries int64
+	giveUps int64
+}
+
+// Retries returns the number of retries made
+func (c *Counters) Retries() int64 {
+	return atomic.LoadInt64(&c.retries)
+}
+
+// GiveUps returns the number of retryable errors which weren't retried because the attempts or
+// the budget were exhausted
+func (c *Counters) GiveUps() int64 {
+	return atomic.LoadInt64(&c.giveUps)
+}
+
+func (c *Counters) addRetry() {
+	atomic.AddInt64(&c.retries, 1)
+}
+
+func (c *Counters) addGiveUp() {
+	atomic.AddInt64(&c.giveUps, 1)
+}
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package retry
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
@@ -56,6 +57,15 @@ var DefaultChannelOpts = Opts{
 	RetryableCodes: ChannelClientRetryableCodes,
 }
 
+// DefaultCAOpts default retry options for the requests to the Fabric CA (see CAPolicy)
+var DefaultCAOpts = Opts{
+	Attempts:       DefaultAttempts,
+	InitialBackoff: DefaultInitialBackoff,
+	MaxBackoff:     DefaultMaxBackoff,
+	BackoffFactor:  DefaultBackoffFactor,
+	RetryableCodes: CARetryableCodes,
+}
+
 // DefaultResMgmtOpts default retry options for the resource management client
 var DefaultResMgmtOpts = Opts{
 	Attempts:       ResMgmtDefaultAttempts,
@@ -123,6 +133,16 @@ var ResMgmtDefaultRetryableCodes = map[status.Group][]status.Code{
 	},
 }
 
+// ConflictRetryableCodes are the codes of the transactions which were invalidated by read conflicts
+// with concurrent transactions (see status.Conflict), which may succeed if they are endorsed and
+// submitted again
+var ConflictRetryableCodes = map[status.Group][]status.Code{
+	status.EventServerStatus: {
+		status.Code(pb.TxValidationCode_MVCC_READ_CONFLICT),
+		status.Code(pb.TxValidationCode_PHANTOM_READ_CONFLICT),
+	},
+}
+
 // ChannelClientRetryableCodes are the suggested codes that should be treated as
 // transient by fabric-sdk-go/pkg/client/channel.Client
 var ChannelClientRetryableCodes = map[status.Group][]status.Code{
@@ -158,3 +178,18 @@ var ChannelClientRetryableCodes = map[status.Group][]status.Code{
 var ChannelConfigRetryableCodes = map[status.Group][]status.Code{
 	status.EndorserClientStatus: {status.EndorsementMismatch},
 }
+
+// CARetryableCodes are the suggested codes that should be treated as transient
+// for the requests to the Fabric CA, e.g. of a busy CA
+var CARetryableCodes = map[status.Group][]status.Code{
+	status.HTTPTransportStatus: {
+		status.Code(http.StatusTooManyRequests),
+		status.Code(http.StatusBadGateway),
+		status.Code(http.StatusServiceUnavailable),
+		status.Code(http.StatusGatewayTimeout),
+	},
+	status.ClientStatus: {
+		status.ConnectionFailed,
+		status.Timeout,
+	},
+}
@@ -7,7 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package retry
 
 import (
+	reqContext "context"
+	"fmt"
+	"time"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 )
 
@@ -20,11 +25,26 @@ type Invocation func() (interface{}, error)
 // a retry attempt.
 type BeforeRetryHandler func(error)
 
+// breakerHandler is implemented by the handlers which consult a circuit breaker
+type breakerHandler interface {
+	allow() bool
+	succeeded()
+}
+
+// deadlineHandler is implemented by the handlers which don't schedule retries that can't complete
+// before the deadline of the request
+type deadlineHandler interface {
+	setDeadline(deadline time.Time, attemptTimeout time.Duration)
+	deadlineExceeded() bool
+}
+
 // RetryableInvoker manages invocations that could return
 // errors and retries the invocation on transient errors.
 type RetryableInvoker struct {
-	handler     Handler
-	beforeRetry BeforeRetryHandler
+	handler        Handler
+	beforeRetry    BeforeRetryHandler
+	ctx            reqContext.Context
+	attemptTimeout time.Duration
 }
 
 // InvokerOpt is an invoker option
@@ -37,6 +57,23 @@ func WithBeforeRetry(beforeRetry BeforeRetryHandler) InvokerOpt {
 	}
 }
 
+// WithContext specifies the context of the request. Retries which can't complete before the
+// deadline of the context (see WithAttemptTimeout) aren't attempted.
+func WithContext(ctx reqContext.Context) InvokerOpt {
+	return func(invoker *RetryableInvoker) {
+		invoker.ctx = ctx
+	}
+}
+
+// WithAttemptTimeout specifies the time that an attempt may take, which is used to determine whether
+// a retry can complete before the deadline of the request. If not specified, the longest duration of
+// the previous attempts is used.
+func WithAttemptTimeout(timeout time.Duration) InvokerOpt {
+	return func(invoker *RetryableInvoker) {
+		invoker.attemptTimeout = timeout
+	}
+}
+
 // NewInvoker creates a new RetryableInvoker
 func NewInvoker(handler Handler, opts ...InvokerOpt) *RetryableInvoker {
 	invoker := &RetryableInvoker{
@@ -53,6 +90,8 @@ func NewInvoker(handler Handler, opts ...InvokerOpt) *RetryableInvoker {
 func (ri *RetryableInvoker) Invoke(invocation Invocation) (interface{}, error) {
 	attemptNum := 0
 	var lastErr error
+	var attemptErrs []interface{}
+	var longestAttempt time.Duration
 
 	for {
 		attemptNum++
@@ -60,8 +99,23 @@ func (ri *RetryableInvoker) Invoke(invocation Invocation) (interface{}, error) {
 			logger.Debugf("Retry attempt #%d on error [%s]", attemptNum, lastErr)
 		}
 
+		if breaker, ok := ri.handler.(breakerHandler); ok && !breaker.allow() {
+			if lastErr != nil {
+				logger.Debugf("Circuit breaker is open - not retrying [%s]", lastErr)
+				return nil, lastErr
+			}
+			return nil, status.New(status.ClientStatus, status.CircuitBreakerOpen.ToInt32(), "circuit breaker is open", nil)
+		}
+
+		start := time.Now()
 		retval, err := invocation()
+		if d := time.Since(start); d > longestAttempt {
+			longestAttempt = d
+		}
 		if err == nil {
+			if breaker, ok := ri.handler.(breakerHandler); ok {
+				breaker.succeeded()
+			}
 			if attemptNum > 1 {
 				logger.Debugf("Success on attempt #%d after error [%s]", attemptNum, lastErr)
 			}
@@ -69,7 +123,14 @@ func (ri *RetryableInvoker) Invoke(invocation Invocation) (interface{}, error) {
 		}
 
 		logger.Debugf("Failed with err [%s] on attempt #%d. Checking if retry is warranted...", err, attemptNum)
+		attemptErrs = append(attemptErrs, err)
+		deadline, hasDeadline := ri.setDeadline(longestAttempt)
 		if !ri.resolveRetry(err) {
+			if hasDeadline && ri.handler.(deadlineHandler).deadlineExceeded() {
+				logger.Debugf("... retry for err [%s] can't complete before the deadline [%s]", err, deadline)
+				return nil, status.New(status.ClientStatus, status.Timeout.ToInt32(),
+					fmt.Sprintf("retry attempt #%d can't complete before the deadline [%s]: %s", attemptNum+1, deadline, err), attemptErrs)
+			}
 			if lastErr != nil && lastErr.Error() != err.Error() {
 				logger.Debugf("... retry for err [%s] is NOT warranted after %d attempt(s). Previous error [%s]", err, lastErr)
 			} else {
@@ -82,6 +143,24 @@ func (ri *RetryableInvoker) Invoke(invocation Invocation) (interface{}, error) {
 	}
 }
 
+// setDeadline sets the deadline of the request on the handler, if any, and returns the deadline
+func (ri *RetryableInvoker) setDeadline(longestAttempt time.Duration) (time.Time, bool) {
+	h, ok := ri.handler.(deadlineHandler)
+	if !ok || ri.ctx == nil {
+		return time.Time{}, false
+	}
+	deadline, ok := ri.ctx.Deadline()
+	if !ok {
+		return time.Time{}, false
+	}
+	attemptTimeout := ri.attemptTimeout
+	if attemptTimeout == 0 {
+		attemptTimeout = longestAttempt
+	}
+	h.setDeadline(deadline, attemptTimeout)
+	return deadline, true
+}
+
 func (ri *RetryableInvoker) resolveRetry(err error) bool {
 	errs, ok := err.(multi.Errors)
 	if !ok {
@@ -7,10 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package retry
 
 import (
+	reqContext "context"
 	"testing"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/circuitbreaker"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -102,3 +104,109 @@ func TestInvokeWithBeforeRetry(t *testing.T) {
 	assert.Equal(t, 2, attempt)
 	assert.Equal(t, 1, beforeRetryHandlerCalled)
 }
+
+func TestInvokeWithBreaker(t *testing.T) {
+	breaker := circuitbreaker.NewRegistry(circuitbreaker.Config{FailureThreshold: 2, CoolDown: 50 * time.Millisecond}).Get("test")
+	r := New(Opts{
+		Attempts:       5,
+		BackoffFactor:  1,
+		InitialBackoff: 1 * time.Millisecond,
+		MaxBackoff:     1 * time.Millisecond,
+		Breaker:        breaker,
+	})
+	transientErr := status.New(status.EndorserClientStatus, status.EndorsementMismatch.ToInt32(), "", nil)
+
+	// The breaker trips before the attempts are exhausted
+	attempt := 0
+	_, err := NewInvoker(r).Invoke(
+		func() (interface{}, error) {
+			attempt++
+			return nil, transientErr
+		},
+	)
+	assert.Equal(t, transientErr, err)
+	assert.Equal(t, 2, attempt)
+	assert.Equal(t, circuitbreaker.Open, breaker.State())
+
+	// Invocations fail fast while the breaker is open
+	attempt = 0
+	_, err = NewInvoker(r).Invoke(
+		func() (interface{}, error) {
+			attempt++
+			return "invoked", nil
+		},
+	)
+	s, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.EqualValues(t, status.CircuitBreakerOpen, s.Code)
+	assert.Equal(t, 0, attempt)
+
+	// A successful trial closes the circuit after the cool-down
+	time.Sleep(50 * time.Millisecond)
+	resp, err := NewInvoker(r).Invoke(
+		func() (interface{}, error) {
+			return "invoked", nil
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "invoked", resp)
+	assert.Equal(t, circuitbreaker.Closed, breaker.State())
+}
+
+func TestInvokeWithDeadline(t *testing.T) {
+	r := New(Opts{
+		Attempts:       5,
+		BackoffFactor:  1,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+	})
+	transientErr := status.New(status.EndorserClientStatus, status.EndorsementMismatch.ToInt32(), "", nil)
+
+	ctx, cancel := reqContext.WithTimeout(reqContext.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// The attempts fail after 30ms, may take up to 40ms and the backoff is 10ms, so the third
+	// attempt can't complete before the deadline
+	attempt := 0
+	_, err := NewInvoker(r, WithContext(ctx), WithAttemptTimeout(40*time.Millisecond)).Invoke(
+		func() (interface{}, error) {
+			attempt++
+			time.Sleep(30 * time.Millisecond)
+			return nil, transientErr
+		},
+	)
+	assert.Equal(t, 2, attempt)
+
+	s, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.ClientStatus, s.Group)
+	assert.EqualValues(t, status.Timeout, s.Code)
+	assert.Equal(t, []interface{}{transientErr, transientErr}, s.Details, "Expected the errors of the attempts in the details")
+
+	// The duration of the previous attempts is used if no attempt timeout is given
+	ctx, cancel = reqContext.WithTimeout(reqContext.Background(), 100*time.Millisecond)
+	defer cancel()
+	attempt = 0
+	_, err = NewInvoker(New(Opts{Attempts: 5, BackoffFactor: 1}), WithContext(ctx)).Invoke(
+		func() (interface{}, error) {
+			attempt++
+			time.Sleep(60 * time.Millisecond)
+			return nil, transientErr
+		},
+	)
+	assert.Equal(t, 1, attempt)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.EqualValues(t, status.Timeout, s.Code)
+
+	// Retries aren't limited without a deadline
+	attempt = 0
+	_, err = NewInvoker(New(Opts{Attempts: 2, BackoffFactor: 1}), WithContext(reqContext.Background())).Invoke(
+		func() (interface{}, error) {
+			attempt++
+			return nil, transientErr
+		},
+	)
+	assert.Equal(t, transientErr, err)
+	assert.Equal(t, 3, attempt)
+}
@@ -0,0 +1,94 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package retry
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+)
+
+// Names of the policies which are registered by default
+const (
+	// DefaultPolicy is the name of the policy with DefaultOpts
+	DefaultPolicy = "default"
+	// ChannelPolicy is the name of the policy with DefaultChannelOpts
+	ChannelPolicy = "channel"
+	// ResMgmtPolicy is the name of the policy with DefaultResMgmtOpts
+	ResMgmtPolicy = "resmgmt"
+	// CAPolicy is the name of the policy with DefaultCAOpts
+	CAPolicy = "ca"
+)
+
+// registry holds the named retry policies, which may be referenced by name from the
+// SDK configuration (e.g. retryPolicy: aggressive) and the client options instead
+// of constructing the retry options at every call site.
+var registry = struct {
+	sync.RWMutex
+	policies map[string]Opts
+}{
+	policies: map[string]Opts{
+		DefaultPolicy: DefaultOpts,
+		ChannelPolicy: DefaultChannelOpts,
+		ResMgmtPolicy: DefaultResMgmtOpts,
+		CAPolicy:      DefaultCAOpts,
+	},
+}
+
+// RegisterPolicy registers the retry policy with the given name, replacing any policy with the
+// same name. Names are case insensitive. If no retryable codes are given, the policy defaults
+// to DefaultRetryableCodes.
+func RegisterPolicy(name string, opts Opts) {
+	if len(opts.RetryableCodes) == 0 {
+		opts.RetryableCodes = DefaultRetryableCodes
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.policies[strings.ToLower(name)] = copyOpts(opts)
+}
+
+// AddRetryableCodes adds custom retryable codes of the given group to the registered policy with the given name
+func AddRetryableCodes(name string, group status.Group, codes ...status.Code) error {
+	registry.Lock()
+	defer registry.Unlock()
+
+	opts, ok := registry.policies[strings.ToLower(name)]
+	if !ok {
+		return errors.Errorf("retry policy [%s] not found", name)
+	}
+	opts = copyOpts(opts)
+	opts.RetryableCodes[group] = append(opts.RetryableCodes[group], codes...)
+	registry.policies[strings.ToLower(name)] = opts
+	return nil
+}
+
+// Policy returns the options of the registered retry policy with the given name
+func Policy(name string) (Opts, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	opts, ok := registry.policies[strings.ToLower(name)]
+	if !ok {
+		return Opts{}, errors.Errorf("retry policy [%s] not found", name)
+	}
+	return copyOpts(opts), nil
+}
+
+// copyOpts returns a copy of the options which doesn't share the retryable codes with the given options
+func copyOpts(opts Opts) Opts {
+	codes := make(map[status.Group][]status.Code, len(opts.RetryableCodes))
+	for group, groupCodes := range opts.RetryableCodes {
+		codes[group] = append([]status.Code{}, groupCodes...)
+	}
+	opts.RetryableCodes = codes
+	return opts
+}
@@ -0,0 +1,52 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package retry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+func TestPolicyRegistry(t *testing.T) {
+	opts, err := Policy(ChannelPolicy)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultChannelOpts.Attempts, opts.Attempts)
+
+	_, err = Policy("aggressive")
+	assert.Error(t, err)
+
+	RegisterPolicy("Aggressive", Opts{Attempts: 10, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond, BackoffFactor: 1.5})
+	opts, err = Policy("aggressive")
+	require.NoError(t, err)
+	assert.Equal(t, 10, opts.Attempts)
+	assert.Equal(t, len(DefaultRetryableCodes), len(opts.RetryableCodes), "expecting default retryable codes")
+
+	// Custom codes are only added to the given policy
+	invalidCode := status.Code(pb.TxValidationCode_INVALID_OTHER_REASON)
+	require.NoError(t, AddRetryableCodes("aggressive", status.EventServerStatus, invalidCode))
+	assert.Error(t, AddRetryableCodes("unknown", status.EventServerStatus, invalidCode))
+
+	opts, err = Policy("aggressive")
+	require.NoError(t, err)
+	assert.Contains(t, opts.RetryableCodes[status.EventServerStatus], invalidCode)
+	assert.NotContains(t, DefaultRetryableCodes[status.EventServerStatus], invalidCode)
+
+	handler := New(opts)
+	assert.True(t, handler.Required(status.New(status.EventServerStatus, int32(invalidCode), "", nil)))
+
+	// Modifying returned options doesn't modify the registered policy
+	opts.RetryableCodes[status.EventServerStatus] = nil
+	opts, err = Policy("aggressive")
+	require.NoError(t, err)
+	assert.Contains(t, opts.RetryableCodes[status.EventServerStatus], invalidCode)
+}
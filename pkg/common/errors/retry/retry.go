@@ -8,9 +8,12 @@ SPDX-License-Identifier: Apache-2.0
 package retry
 
 import (
+	"math/rand"
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/recovery"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/circuitbreaker"
 )
 
 // Opts defines the retry parameters
@@ -29,6 +32,26 @@ type Opts struct {
 	// RetryableCodes defines the status codes, mapped by group, returned by fabric-sdk-go
 	// that warrant a retry. This will default to retry.DefaultRetryableCodes.
 	RetryableCodes map[status.Group][]status.Code
+	// Jitter enables full jitter: the backoff of a retry attempt is a random duration between
+	// zero and the backoff computed from the other options, so that clients which fail at the
+	// same time (e.g. during a peer outage) don't retry in lockstep.
+	Jitter bool
+	// Budget limits the total number of retries made by all of the handlers which share the budget
+	// (e.g. all of the requests of a client) within a time window. Retries are unlimited if nil.
+	Budget *Budget
+	// OnRetry is invoked before each retry attempt with the number of the attempt (starting at 1),
+	// the backoff before the attempt and the error which warranted the retry
+	OnRetry func(attempt int, backoff time.Duration, err error)
+	// OnGiveUp is invoked when a retryable error isn't retried because the attempts or the budget
+	// are exhausted, with the number of retries made and the error
+	OnGiveUp func(retries int, err error)
+	// Counters counts the retries and give-ups of all of the handlers which share the counters
+	// (e.g. all of the requests of an operation). Nothing is counted if nil.
+	Counters *Counters
+	// Breaker is the circuit breaker shared by the handlers (e.g. all of the requests which use a
+	// retry policy). Retryable errors are recorded as failures of the breaker and, once the circuit
+	// is open, are no longer retried. No breaker is consulted if nil.
+	Breaker *circuitbreaker.Breaker
 }
 
 // Handler retry handler interface decides whether a retry is required for the given
@@ -41,6 +64,10 @@ type Handler interface {
 type impl struct {
 	opts    Opts
 	retries int
+	// deadline of the request and the time that an attempt may take (see RetryableInvoker)
+	deadline       time.Time
+	attemptTimeout time.Duration
+	exceeded       bool
 }
 
 // New retry Handler with the given opts
@@ -66,18 +93,87 @@ func WithAttempts(attempts int) Handler {
 // Required determines if retry is required for the given error
 // Note: backoffs are implemented behind this interface
 func (i *impl) Required(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok || !i.isRetryable(s.Group, s.Code) {
+		// Errors which don't warrant a retry aren't failures of the kind the breaker guards against
+		i.succeeded()
+		return false
+	}
+
+	if i.opts.Breaker != nil {
+		i.opts.Breaker.Failure()
+		if i.opts.Breaker.State() == circuitbreaker.Open {
+			logger.Debugf("Circuit breaker is open - not retrying [%s]", err)
+			i.giveUp(err)
+			return false
+		}
+	}
 	if i.retries == i.opts.Attempts {
+		i.giveUp(err)
 		return false
 	}
 
-	s, ok := status.FromError(err)
-	if ok && i.isRetryable(s.Group, s.Code) {
-		time.Sleep(i.backoffPeriod())
-		i.retries++
-		return true
+	backoff := i.backoffPeriod()
+	if !i.deadline.IsZero() && time.Now().Add(backoff+i.attemptTimeout).After(i.deadline) {
+		logger.Debugf("Retry can't complete before the deadline - not retrying [%s]", err)
+		i.exceeded = true
+		i.giveUp(err)
+		return false
+	}
+	if i.opts.Budget != nil && !i.opts.Budget.Withdraw() {
+		logger.Debugf("Retry budget exhausted - not retrying [%s]", err)
+		i.giveUp(err)
+		return false
 	}
 
-	return false
+	i.retries++
+	if i.opts.Counters != nil {
+		i.opts.Counters.addRetry()
+	}
+	if i.opts.OnRetry != nil {
+		if hookErr := recovery.Call(func() { i.opts.OnRetry(i.retries, backoff, err) }); hookErr != nil {
+			logger.Warnf("OnRetry hook failed: %s", hookErr)
+		}
+	}
+	time.Sleep(backoff)
+	return true
+}
+
+// giveUp reports that the given retryable error isn't retried
+func (i *impl) giveUp(err error) {
+	if i.opts.Counters != nil {
+		i.opts.Counters.addGiveUp()
+	}
+	if i.opts.OnGiveUp != nil {
+		if hookErr := recovery.Call(func() { i.opts.OnGiveUp(i.retries, err) }); hookErr != nil {
+			logger.Warnf("OnGiveUp hook failed: %s", hookErr)
+		}
+	}
+}
+
+// allow returns true if the breaker, if any, allows an attempt
+func (i *impl) allow() bool {
+	return i.opts.Breaker == nil || i.opts.Breaker.Allow()
+}
+
+// succeeded records a success with the breaker, if any
+func (i *impl) succeeded() {
+	if i.opts.Breaker != nil {
+		i.opts.Breaker.Success()
+	}
+}
+
+// setDeadline sets the deadline of the request and the time that an attempt may take
+func (i *impl) setDeadline(deadline time.Time, attemptTimeout time.Duration) {
+	i.deadline = deadline
+	i.attemptTimeout = attemptTimeout
+	i.exceeded = false
+}
+
+// deadlineExceeded returns true if the last retry wasn't attempted because it couldn't complete
+// before the deadline
+func (i *impl) deadlineExceeded() bool {
+	return i.exceeded
 }
 
 // backoffPeriod calculates the backoff duration based on the provided opts
@@ -89,6 +185,9 @@ func (i *impl) backoffPeriod() time.Duration {
 	if backoff > max {
 		backoff = max
 	}
+	if i.opts.Jitter {
+		backoff = rand.Float64() * backoff
+	}
 
 	return time.Duration(backoff)
 }
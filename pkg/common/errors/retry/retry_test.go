@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/circuitbreaker"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	"github.com/stretchr/testify/assert"
 )
@@ -63,3 +64,122 @@ func TestBackoffPeriod(t *testing.T) {
 	i.retries = 3
 	assert.Equal(t, testMaxBackoff, i.backoffPeriod(), "Expected max backoff")
 }
+
+func TestJitter(t *testing.T) {
+	r := New(Opts{
+		Attempts:       10,
+		BackoffFactor:  2,
+		InitialBackoff: time.Second,
+		MaxBackoff:     10 * time.Second,
+		Jitter:         true,
+	})
+	i := r.(*impl)
+	i.retries = 2
+	distinct := make(map[time.Duration]bool)
+	for j := 0; j < 20; j++ {
+		backoff := i.backoffPeriod()
+		assert.True(t, backoff >= 0 && backoff <= 4*time.Second, "Expected jittered backoff between zero and the exponential backoff")
+		distinct[backoff] = true
+	}
+	assert.True(t, len(distinct) > 1, "Expected random backoffs")
+}
+
+func TestBudget(t *testing.T) {
+	transientErr := status.New(status.EndorserClientStatus, status.EndorsementMismatch.ToInt32(), "", nil)
+	budget := NewBudget(3, time.Minute)
+	opts := Opts{Attempts: 5, BackoffFactor: 1, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Budget: budget}
+
+	// The budget is shared by the handlers
+	r1, r2 := New(opts), New(opts)
+	assert.True(t, r1.Required(transientErr))
+	assert.True(t, r2.Required(transientErr))
+	assert.True(t, r1.Required(transientErr))
+	assert.Equal(t, 0, budget.Remaining())
+	assert.False(t, r2.Required(transientErr), "Expected retry to not be required after exhausting the budget")
+
+	// Retries are allowed again once they fall out of the window
+	now := time.Now()
+	budget.now = func() time.Time { return now.Add(time.Minute + time.Second) }
+	assert.Equal(t, 3, budget.Remaining())
+	assert.True(t, r2.Required(transientErr))
+	assert.Equal(t, 2, budget.Remaining())
+}
+
+func TestRetryHooks(t *testing.T) {
+	transientErr := status.New(status.EndorserClientStatus, status.EndorsementMismatch.ToInt32(), "", nil)
+	nonTransientErr := status.New(status.EndorserServerStatus, int32(common.Status_BAD_REQUEST), "", nil)
+
+	var retryAttempts []int
+	var giveUps []int
+	counters := &Counters{}
+	opts := Opts{
+		Attempts:       2,
+		BackoffFactor:  2,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Second,
+		OnRetry: func(attempt int, backoff time.Duration, err error) {
+			assert.Equal(t, transientErr, err)
+			assert.Equal(t, time.Duration(attempt)*time.Millisecond, backoff)
+			retryAttempts = append(retryAttempts, attempt)
+		},
+		OnGiveUp: func(retries int, err error) {
+			assert.Equal(t, transientErr, err)
+			giveUps = append(giveUps, retries)
+		},
+		Counters: counters,
+	}
+
+	r := New(opts)
+	assert.False(t, r.Required(nonTransientErr))
+	assert.True(t, r.Required(transientErr))
+	assert.True(t, r.Required(transientErr))
+	assert.False(t, r.Required(transientErr))
+	assert.Equal(t, []int{1, 2}, retryAttempts)
+	assert.Equal(t, []int{2}, giveUps)
+
+	// The counters are shared by the handlers
+	opts.OnRetry, opts.OnGiveUp = nil, nil
+	opts.Budget = NewBudget(0, time.Minute)
+	assert.False(t, New(opts).Required(transientErr))
+	assert.EqualValues(t, 2, counters.Retries())
+	assert.EqualValues(t, 2, counters.GiveUps())
+}
+
+func TestRetryBreaker(t *testing.T) {
+	transientErr := status.New(status.EndorserClientStatus, status.EndorsementMismatch.ToInt32(), "", nil)
+	nonTransientErr := status.New(status.EndorserServerStatus, int32(common.Status_BAD_REQUEST), "", nil)
+
+	breaker := circuitbreaker.NewRegistry(circuitbreaker.Config{FailureThreshold: 3, CoolDown: time.Minute}).Get("test")
+	counters := &Counters{}
+	opts := Opts{Attempts: 10, BackoffFactor: 1, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond,
+		Breaker: breaker, Counters: counters}
+
+	// Errors which don't warrant a retry reset the failures
+	r := New(opts)
+	assert.True(t, r.Required(transientErr))
+	assert.True(t, r.Required(transientErr))
+	assert.False(t, r.Required(nonTransientErr))
+
+	// The breaker trips once the failure threshold is reached, regardless of the remaining attempts
+	r = New(opts)
+	assert.True(t, r.Required(transientErr))
+	assert.True(t, r.Required(transientErr))
+	assert.False(t, r.Required(transientErr), "Expected retry to not be required once the breaker is open")
+	assert.Equal(t, circuitbreaker.Open, breaker.State())
+	assert.EqualValues(t, 4, counters.Retries())
+	assert.EqualValues(t, 1, counters.GiveUps())
+}
+
+func TestPanickingHooks(t *testing.T) {
+	transientErr := status.New(status.EndorserClientStatus, status.EndorsementMismatch.ToInt32(), "", nil)
+	r := New(Opts{
+		Attempts:       1,
+		BackoffFactor:  1,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		OnRetry:        func(attempt int, backoff time.Duration, err error) { panic("OnRetry failed") },
+		OnGiveUp:       func(retries int, err error) { panic("OnGiveUp failed") },
+	})
+	assert.True(t, r.Required(transientErr))
+	assert.False(t, r.Required(transientErr))
+}
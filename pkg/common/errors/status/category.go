@@ -0,0 +1,139 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package status
+
+import (
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// Category is a stable classification of the errors returned by fabric-sdk-go. Unlike
+// the messages of the errors, which may change from release to release, categories
+// may be relied upon by SDK users to handle errors:
+//
+//  if status.IsCategory(err, status.Connection) {
+//      // retry with another peer
+//  }
+//
+//  if s, ok := status.FromError(err); ok && s.Category() == status.Endorsement {
+//      // inspect s.Code and s.Details
+//  }
+type Category string
+
+const (
+	// Uncategorized is the category of errors which don't fall in any of the other categories
+	Uncategorized Category = "UNCATEGORIZED"
+
+	// Connection is the category of errors connecting or talking to peers, orderers, CAs and
+	// event services, including timeouts
+	Connection Category = "CONNECTION"
+
+	// Endorsement is the category of errors endorsing proposals, including chaincode errors
+	// and mismatched or missing endorsements
+	Endorsement Category = "ENDORSEMENT"
+
+	// Ordering is the category of errors broadcasting transactions to the ordering service
+	Ordering Category = "ORDERING"
+
+	// Validation is the category of transactions which were committed as invalid
+	Validation Category = "VALIDATION"
+
+	// Conflict is the category of transactions which were committed as invalid because they read
+	// state which was modified by a concurrent transaction (MVCC and phantom read conflicts). Such
+	// transactions may succeed if they are endorsed and submitted again. Conflicts are also
+	// matched by IsCategory(err, Validation).
+	Conflict Category = "CONFLICT"
+
+	// Config is the category of configuration errors, e.g. no peers or entity matchers found
+	Config Category = "CONFIG"
+
+	// Crypto is the category of certificate, signature and enrollment errors
+	Crypto Category = "CRYPTO"
+)
+
+// Category returns the category of the status
+func (s *Status) Category() Category {
+	switch s.Group {
+	case EndorserClientStatus, OrdererClientStatus, ClientStatus:
+		if c, ok := codeCategory[ToSDKStatusCode(s.Code)]; ok {
+			return c
+		}
+	case EventServerStatus:
+		if c, ok := validationCodeCategory[ToTransactionValidationCode(s.Code)]; ok {
+			return c
+		}
+	}
+	if c, ok := groupCategory[s.Group]; ok {
+		return c
+	}
+	return Uncategorized
+}
+
+// IsCategory returns true if the given error carries a status of the given category. The
+// causes of wrapped errors (see errors.Cause) and the errors contained in multi errors are
+// searched for the status.
+func IsCategory(err error, c Category) bool {
+	return walk(err, func(err error) bool {
+		s, ok := err.(*Status)
+		return ok && s.inCategory(c)
+	})
+}
+
+// CategoryOf returns the category of the given error, or Uncategorized if the error doesn't
+// carry a status. The category of the first status found by IsCategory is returned.
+func CategoryOf(err error) Category {
+	category := Uncategorized
+	walk(err, func(err error) bool {
+		s, ok := err.(*Status)
+		if ok {
+			category = s.Category()
+		}
+		return ok
+	})
+	return category
+}
+
+// inCategory returns true if the status falls in the given category
+func (s *Status) inCategory(c Category) bool {
+	category := s.Category()
+	return category == c || (category == Conflict && c == Validation)
+}
+
+// codeCategory maps the codes of the client status groups to categories
+var codeCategory = map[Code]Category{
+	ConnectionFailed:                     Connection,
+	Timeout:                              Connection,
+	CircuitBreakerOpen:                   Connection,
+	EndorsementMismatch:                  Endorsement,
+	MissingEndorsement:                   Endorsement,
+	PrematureChaincodeExecution:          Endorsement,
+	EmptyCert:                            Crypto,
+	SignatureVerificationFailed:          Crypto,
+	NoPeersFound:                         Config,
+	NoMatchingCertificateAuthorityEntity: Config,
+	NoMatchingPeerEntity:                 Config,
+	NoMatchingOrdererEntity:              Config,
+	NoMatchingChannelEntity:              Config,
+}
+
+// validationCodeCategory maps the transaction validation codes of EventServerStatus to categories
+var validationCodeCategory = map[pb.TxValidationCode]Category{
+	pb.TxValidationCode_MVCC_READ_CONFLICT:    Conflict,
+	pb.TxValidationCode_PHANTOM_READ_CONFLICT: Conflict,
+}
+
+// groupCategory maps the status groups to categories
+var groupCategory = map[Group]Category{
+	GRPCTransportStatus:  Connection,
+	HTTPTransportStatus:  Connection,
+	EndorserServerStatus: Endorsement,
+	EndorserClientStatus: Endorsement,
+	ChaincodeStatus:      Endorsement,
+	OrdererServerStatus:  Ordering,
+	OrdererClientStatus:  Ordering,
+	EventServerStatus:    Validation,
+	FabricCAServerStatus: Crypto,
+}
@@ -0,0 +1,73 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package status
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	grpccodes "google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+func TestCategory(t *testing.T) {
+	tests := []struct {
+		status   *Status
+		category Category
+	}{
+		{NewFromGRPCStatus(grpcstatus.New(grpccodes.Unavailable, "unavailable")), Connection},
+		{New(EndorserClientStatus, ConnectionFailed.ToInt32(), "", nil), Connection},
+		{New(OrdererClientStatus, Timeout.ToInt32(), "", nil), Connection},
+		{New(EndorserServerStatus, int32(common.Status_INTERNAL_SERVER_ERROR), "", nil), Endorsement},
+		{New(EndorserClientStatus, EndorsementMismatch.ToInt32(), "", nil), Endorsement},
+		{NewFromExtractedChaincodeError(500, "chaincode error"), Endorsement},
+		{New(OrdererServerStatus, int32(common.Status_SERVICE_UNAVAILABLE), "", nil), Ordering},
+		{New(OrdererClientStatus, Unknown.ToInt32(), "", nil), Ordering},
+		{New(EventServerStatus, int32(pb.TxValidationCode_MVCC_READ_CONFLICT), "", nil), Conflict},
+		{New(EventServerStatus, int32(pb.TxValidationCode_PHANTOM_READ_CONFLICT), "", nil), Conflict},
+		{New(EventServerStatus, int32(pb.TxValidationCode_BAD_RWSET), "", nil), Validation},
+		{New(ClientStatus, NoPeersFound.ToInt32(), "", nil), Config},
+		{New(ClientStatus, NoMatchingPeerEntity.ToInt32(), "", nil), Config},
+		{New(EndorserClientStatus, SignatureVerificationFailed.ToInt32(), "", nil), Crypto},
+		{New(FabricCAServerStatus, 20, "", nil), Crypto},
+		{New(ClientStatus, Unknown.ToInt32(), "", nil), Uncategorized},
+		{New(UnknownStatus, 0, "", nil), Uncategorized},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.category, test.status.Category(), "unexpected category of %s", test.status)
+	}
+}
+
+func TestIsCategory(t *testing.T) {
+	s := New(EndorserClientStatus, EndorsementMismatch.ToInt32(), "mismatch", nil)
+	err := errors.WithMessage(errors.Wrap(s, "wrapped"), "execute failed")
+
+	assert.True(t, IsCategory(err, Endorsement))
+	assert.False(t, IsCategory(err, Connection))
+	assert.Equal(t, Endorsement, CategoryOf(err))
+
+	err = multi.New(errors.New("other"), errors.Wrap(NewFromGRPCStatus(grpcstatus.New(grpccodes.DeadlineExceeded, "timeout")), "wrapped"))
+	assert.True(t, IsCategory(err, Connection))
+	assert.True(t, IsCategory(errors.WithMessage(err, "execute failed"), Connection))
+	assert.Equal(t, Connection, CategoryOf(err))
+
+	// Conflicts are also validation failures
+	err = errors.Wrap(New(EventServerStatus, int32(pb.TxValidationCode_MVCC_READ_CONFLICT), "", nil), "commit failed")
+	assert.True(t, IsCategory(err, Conflict))
+	assert.True(t, IsCategory(err, Validation))
+	assert.False(t, IsCategory(New(EventServerStatus, int32(pb.TxValidationCode_BAD_RWSET), "", nil), Conflict))
+
+	assert.False(t, IsCategory(errors.New("other"), Uncategorized))
+	assert.False(t, IsCategory(nil, Uncategorized))
+	assert.Equal(t, Uncategorized, CategoryOf(errors.New("other")))
+	assert.Equal(t, Uncategorized, CategoryOf(nil))
+}
@@ -0,0 +1,114 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package status
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// ChaincodeErrorThreshold is the lowest status of a chaincode response which denotes an
+// error (see shim.ERRORTHRESHOLD)
+const ChaincodeErrorThreshold = 400
+
+// ChaincodeError is an error returned by the chaincode itself (e.g. shim.Error), as opposed
+// to a failure of the SDK, the network or the peers. It may be extracted from the errors
+// returned by the SDK with ChaincodeErrorOf:
+//
+//  if ccErr, ok := status.ChaincodeErrorOf(err); ok {
+//      // inspect ccErr.Status, ccErr.Message and ccErr.Payload
+//  }
+type ChaincodeError struct {
+	// Status is the status of the chaincode response
+	Status int32
+	// Message is the message of the chaincode response
+	Message string
+	// Payload is the payload of the chaincode response, if any
+	Payload []byte
+}
+
+// Error returns the status and the message of the chaincode error
+func (e *ChaincodeError) Error() string {
+	return fmt.Sprintf("chaincode error (status: %d, message: %s)", e.Status, e.Message)
+}
+
+// NewChaincodeError returns the chaincode error of the given chaincode response, or nil if
+// the status of the response is below ChaincodeErrorThreshold
+func NewChaincodeError(response *pb.Response) *ChaincodeError {
+	if response == nil || response.Status < ChaincodeErrorThreshold {
+		return nil
+	}
+	return &ChaincodeError{Status: response.Status, Message: response.Message, Payload: response.Payload}
+}
+
+// NewChaincodeErrorFromProposalResponse returns the chaincode error of the given proposal
+// response, or nil if the chaincode didn't return an error. The response of the chaincode
+// action in the payload takes precedence over the response of the peer, since peers respond
+// with status 200 to proposals which they endorsed.
+func NewChaincodeErrorFromProposalResponse(res *pb.ProposalResponse) *ChaincodeError {
+	if res == nil {
+		return nil
+	}
+	if ccResponse := chaincodeActionResponse(res.Payload); ccResponse != nil {
+		return NewChaincodeError(ccResponse)
+	}
+	return NewChaincodeError(res.Response)
+}
+
+// ChaincodeErrorOf returns the chaincode error of the given error, or false if the error
+// wasn't returned by the chaincode. The causes of wrapped errors (see errors.Cause) and the
+// errors contained in multi errors are searched for the chaincode error.
+func ChaincodeErrorOf(err error) (*ChaincodeError, bool) {
+	var ccErr *ChaincodeError
+	found := walk(err, func(err error) bool {
+		switch e := err.(type) {
+		case *ChaincodeError:
+			ccErr = e
+		case *Status:
+			ccErr = e.chaincodeError()
+		}
+		return ccErr != nil
+	})
+	return ccErr, found
+}
+
+// chaincodeError returns the chaincode error of the status, or nil if the status wasn't
+// returned by the chaincode
+func (s *Status) chaincodeError() *ChaincodeError {
+	switch s.Group {
+	case ChaincodeStatus:
+		return &ChaincodeError{Status: s.Code, Message: s.Message}
+	case EndorserServerStatus:
+		// See NewFromProposalResponse for the details
+		if s.Code < ChaincodeErrorThreshold || len(s.Details) < 2 {
+			return nil
+		}
+		payload, _ := s.Details[1].([]byte)
+		return &ChaincodeError{Status: s.Code, Message: s.Message, Payload: payload}
+	}
+	return nil
+}
+
+// chaincodeActionResponse returns the chaincode response of the chaincode action in the
+// given proposal response payload, or nil if it may not be decoded
+func chaincodeActionResponse(payload []byte) *pb.Response {
+	if len(payload) == 0 {
+		return nil
+	}
+	prp := &pb.ProposalResponsePayload{}
+	if err := proto.Unmarshal(payload, prp); err != nil {
+		return nil
+	}
+	action := &pb.ChaincodeAction{}
+	if err := proto.Unmarshal(prp.Extension, action); err != nil {
+		return nil
+	}
+	return action.Response
+}
@@ -0,0 +1,91 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package status
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	grpccodes "google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+func TestNewChaincodeError(t *testing.T) {
+	assert.Nil(t, NewChaincodeError(nil))
+	assert.Nil(t, NewChaincodeError(&pb.Response{Status: 200, Payload: []byte("value")}))
+
+	ccErr := NewChaincodeError(&pb.Response{Status: 404, Message: "not found", Payload: []byte("key")})
+	require.NotNil(t, ccErr)
+	assert.EqualValues(t, 404, ccErr.Status)
+	assert.Equal(t, "not found", ccErr.Message)
+	assert.Equal(t, []byte("key"), ccErr.Payload)
+	assert.Equal(t, "chaincode error (status: 404, message: not found)", ccErr.Error())
+}
+
+func TestNewChaincodeErrorFromProposalResponse(t *testing.T) {
+	assert.Nil(t, NewChaincodeErrorFromProposalResponse(nil))
+
+	// Endorsed proposal with an error returned by the chaincode
+	res := &pb.ProposalResponse{
+		Response: &pb.Response{Status: 200},
+		Payload:  proposalResponsePayload(t, &pb.Response{Status: 409, Message: "conflict", Payload: []byte("key")}),
+	}
+	ccErr := NewChaincodeErrorFromProposalResponse(res)
+	require.NotNil(t, ccErr)
+	assert.EqualValues(t, 409, ccErr.Status)
+	assert.Equal(t, "conflict", ccErr.Message)
+	assert.Equal(t, []byte("key"), ccErr.Payload)
+
+	// Endorsed proposal with a successful chaincode response
+	res.Payload = proposalResponsePayload(t, &pb.Response{Status: 200})
+	assert.Nil(t, NewChaincodeErrorFromProposalResponse(res))
+
+	// Proposal response without a chaincode action
+	res = &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: "failed"}}
+	ccErr = NewChaincodeErrorFromProposalResponse(res)
+	require.NotNil(t, ccErr)
+	assert.EqualValues(t, 500, ccErr.Status)
+}
+
+func TestChaincodeErrorOf(t *testing.T) {
+	tests := []struct {
+		err     error
+		ccErr   *ChaincodeError
+		isCCErr bool
+	}{
+		{NewFromExtractedChaincodeError(500, "failed"), &ChaincodeError{Status: 500, Message: "failed"}, true},
+		{errors.Wrap(NewFromExtractedChaincodeError(500, "failed"), "invoke failed"), &ChaincodeError{Status: 500, Message: "failed"}, true},
+		{New(EndorserServerStatus, 500, "failed", []interface{}{"peer1", []byte("payload")}), &ChaincodeError{Status: 500, Message: "failed", Payload: []byte("payload")}, true},
+		{multi.Errors{errors.New("error"), NewFromExtractedChaincodeError(400, "invalid")}, &ChaincodeError{Status: 400, Message: "invalid"}, true},
+		{errors.WithMessage(multi.Errors{errors.New("error"), errors.Wrap(NewFromExtractedChaincodeError(400, "invalid"), "wrapped")}, "invoke failed"), &ChaincodeError{Status: 400, Message: "invalid"}, true},
+		{&ChaincodeError{Status: 400, Message: "invalid"}, &ChaincodeError{Status: 400, Message: "invalid"}, true},
+		{New(EndorserServerStatus, 200, "", []interface{}{"peer1", []byte("payload")}), nil, false},
+		{New(EndorserClientStatus, ConnectionFailed.ToInt32(), "failed", nil), nil, false},
+		{NewFromGRPCStatus(grpcstatus.New(grpccodes.Unavailable, "unavailable")), nil, false},
+		{errors.New("error"), nil, false},
+		{nil, nil, false},
+	}
+	for _, test := range tests {
+		ccErr, ok := ChaincodeErrorOf(test.err)
+		assert.Equal(t, test.isCCErr, ok, "unexpected result for [%v]", test.err)
+		assert.Equal(t, test.ccErr, ccErr, "unexpected chaincode error for [%v]", test.err)
+	}
+}
+
+func proposalResponsePayload(t *testing.T, response *pb.Response) []byte {
+	extension, err := proto.Marshal(&pb.ChaincodeAction{Response: response})
+	require.NoError(t, err)
+	payload, err := proto.Marshal(&pb.ProposalResponsePayload{Extension: extension})
+	require.NoError(t, err)
+	return payload
+}
@@ -8,6 +8,9 @@ package status
 
 import (
 	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
 
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
@@ -63,6 +66,10 @@ const (
 
 	// NoMatchingChannelEntity is if entityMatchers are unable to find any matchingChannel
 	NoMatchingChannelEntity Code = 25
+
+	// CircuitBreakerOpen is returned when a request is rejected by an open circuit breaker after
+	// persistent failures
+	CircuitBreakerOpen Code = 26
 )
 
 // CodeName maps the codes in this packages to human-readable strings
@@ -83,6 +90,7 @@ var CodeName = map[int32]string{
 	23: "NO_MATCHING_ORDERER_ENTITY",
 	24: "PREMATURE_CHAINCODE_EXECUTION",
 	25: "NO_MATCHING_CHANNEL_ENTITY",
+	26: "CIRCUIT_BREAKER_OPEN",
 }
 
 // ToInt32 cast to int32
@@ -127,3 +135,39 @@ func ToFabricCommonStatusCode(c int32) common.Status {
 func ToTransactionValidationCode(c int32) pb.TxValidationCode {
 	return pb.TxValidationCode(c)
 }
+
+// ParseCode returns the code of the given group with the given name or number. Names are the names
+// of the codes of the group: gRPC codes for GRPCTransportStatus (e.g. "Unavailable"), common.Status
+// names for EndorserServerStatus and OrdererServerStatus (e.g. "SERVICE_UNAVAILABLE"), transaction
+// validation codes for EventServerStatus (e.g. "MVCC_READ_CONFLICT") and the names in CodeName for
+// the client status groups (e.g. "ENDORSEMENT_MISMATCH"). The codes of the other groups must be numbers.
+func ParseCode(group Group, name string) (Code, error) {
+	if c, err := strconv.ParseInt(name, 10, 32); err == nil {
+		return Code(c), nil
+	}
+
+	upper := strings.ToUpper(name)
+	switch group {
+	case GRPCTransportStatus:
+		for c := grpcCodes.OK; c <= grpcCodes.Unauthenticated; c++ {
+			if strings.EqualFold(c.String(), name) {
+				return Code(c), nil
+			}
+		}
+	case EndorserServerStatus, OrdererServerStatus:
+		if c, ok := common.Status_value[upper]; ok {
+			return Code(c), nil
+		}
+	case EventServerStatus:
+		if c, ok := pb.TxValidationCode_value[upper]; ok {
+			return Code(c), nil
+		}
+	case EndorserClientStatus, OrdererClientStatus, ClientStatus:
+		for c, codeName := range CodeName {
+			if codeName == upper {
+				return Code(c), nil
+			}
+		}
+	}
+	return Unknown, errors.Errorf("unknown code [%s] of status group [%s]", name, group)
+}
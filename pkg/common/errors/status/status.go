@@ -11,11 +11,14 @@ package status
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	grpcstatus "google.golang.org/grpc/status"
 )
 
@@ -31,6 +34,11 @@ type Status struct {
 	Message string
 	// Details any additional status details
 	Details []interface{}
+	// Metadata is the metadata (headers and trailers) returned by the server along with the
+	// gRPC error from which the status was created, if any
+	Metadata metadata.MD
+
+	grpcStatus *grpcstatus.Status
 }
 
 // Group of status to help users infer status codes from various components
@@ -99,6 +107,23 @@ func (g Group) String() string {
 	return UnknownStatus.String()
 }
 
+// ParseGroup returns the group with the given name. The name is matched against the names in
+// GroupName and the names of the group constants, ignoring case and spaces
+// (e.g. "Endorser Server Status" or "EndorserServerStatus").
+func ParseGroup(name string) (Group, error) {
+	normalized := normalizeGroupName(name)
+	for g, groupName := range GroupName {
+		if normalizeGroupName(groupName) == normalized {
+			return Group(g), nil
+		}
+	}
+	return UnknownStatus, errors.Errorf("unknown status group [%s]", name)
+}
+
+func normalizeGroupName(name string) string {
+	return strings.ToLower(strings.Replace(name, " ", "", -1))
+}
+
 // FromError returns a Status representing err if available,
 // otherwise it returns nil, false.
 func FromError(err error) (s *Status, ok bool) {
@@ -113,12 +138,47 @@ func FromError(err error) (s *Status, ok bool) {
 		return s, true
 	}
 	if m, ok := unwrappedErr.(multi.Errors); ok {
-		return New(ClientStatus, MultipleErrors.ToInt32(), m.Error(), nil), true
+		// The individual errors are kept in the details so that their statuses aren't lost
+		details := make([]interface{}, len(m))
+		for i, err := range m {
+			details[i] = err
+		}
+		return New(ClientStatus, MultipleErrors.ToInt32(), m.Error(), details), true
 	}
 
 	return nil, false
 }
 
+// causer is implemented by the errors of github.com/pkg/errors which wrap another error
+type causer interface {
+	Cause() error
+}
+
+// walk calls the given function with the given error, each of its causes and each of the
+// errors contained in multi errors, until the function returns true. It returns true if the
+// function returned true for any of the errors.
+func walk(err error, fn func(error) bool) bool {
+	for err != nil {
+		if fn(err) {
+			return true
+		}
+		if m, ok := err.(multi.Errors); ok {
+			for _, err := range m {
+				if walk(err, fn) {
+					return true
+				}
+			}
+			return false
+		}
+		c, ok := err.(causer)
+		if !ok {
+			return false
+		}
+		err = c.Cause()
+	}
+	return false
+}
+
 func (s *Status) Error() string {
 	return fmt.Sprintf("%s Code: (%d) %s. Description: %s", s.Group.String(), s.Code, s.codeString(), s.Message)
 }
@@ -164,7 +224,45 @@ func NewFromGRPCStatus(s *grpcstatus.Status) *Status {
 	}
 
 	return &Status{Group: GRPCTransportStatus, Code: s.Proto().Code,
-		Message: s.Message(), Details: details}
+		Message: s.Message(), Details: details, grpcStatus: s}
+}
+
+// FromStreamError returns a status for the given error received from the gRPC stream, which carries
+// the gRPC status (including the details supplied by the server) and the trailer of the stream.
+// Errors which aren't gRPC errors (e.g. io.EOF) are returned as is.
+func FromStreamError(err error, stream grpc.ClientStream) error {
+	rpcStatus, ok := grpcstatus.FromError(err)
+	if !ok {
+		return err
+	}
+	return NewFromGRPCStatus(rpcStatus).WithMetadata(stream.Trailer())
+}
+
+// WithGRPCStatus sets the gRPC status from which the status was created (e.g. the gRPC status from
+// which a chaincode error was extracted) and returns the status
+func (s *Status) WithGRPCStatus(grpcStatus *grpcstatus.Status) *Status {
+	s.grpcStatus = grpcStatus
+	return s
+}
+
+// WithMetadata merges the given metadata (e.g. the headers and trailers returned by a server along
+// with a gRPC error) into the metadata of the status and returns the status
+func (s *Status) WithMetadata(mds ...metadata.MD) *Status {
+	for _, md := range mds {
+		for key, values := range md {
+			if s.Metadata == nil {
+				s.Metadata = metadata.MD{}
+			}
+			s.Metadata[key] = append(s.Metadata[key], values...)
+		}
+	}
+	return s
+}
+
+// GRPCStatus returns the gRPC status, including the details supplied by the server, from which the
+// status was created, or nil if the status wasn't created from a gRPC error
+func (s *Status) GRPCStatus() *grpcstatus.Status {
+	return s.grpcStatus
 }
 
 // NewFromExtractedChaincodeError returns Status when a chaincode error occurs
@@ -15,7 +15,9 @@ import (
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
 	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	grpcstatus "google.golang.org/grpc/status"
 )
 
@@ -74,6 +76,7 @@ func TestFromError(t *testing.T) {
 	assert.Equal(t, ClientStatus, s.Group)
 	assert.EqualValues(t, MultipleErrors.ToInt32(), s.Code)
 	assert.Equal(t, errs.Error(), s.Message)
+	assert.Equal(t, []interface{}{errs[0]}, s.Details)
 }
 
 func TestStatusToError(t *testing.T) {
@@ -123,3 +126,76 @@ func TestChaincodeStatus(t *testing.T) {
 	assert.Equal(t, "key not found", s.Message)
 	assert.Equal(t, int32(500), s.Code)
 }
+
+func TestParseGroupAndCode(t *testing.T) {
+	g, err := ParseGroup("EndorserServerStatus")
+	assert.NoError(t, err)
+	assert.Equal(t, EndorserServerStatus, g)
+	g, err = ParseGroup("gRPC Transport Status")
+	assert.NoError(t, err)
+	assert.Equal(t, GRPCTransportStatus, g)
+	_, err = ParseGroup("NoSuchStatus")
+	assert.Error(t, err)
+
+	tests := []struct {
+		group Group
+		name  string
+		code  int32
+	}{
+		{GRPCTransportStatus, "Unavailable", int32(grpccodes.Unavailable)},
+		{EndorserServerStatus, "service_unavailable", int32(common.Status_SERVICE_UNAVAILABLE)},
+		{EventServerStatus, "MVCC_READ_CONFLICT", int32(pb.TxValidationCode_MVCC_READ_CONFLICT)},
+		{ClientStatus, "NO_PEERS_FOUND", NoPeersFound.ToInt32()},
+		{FabricCAServerStatus, "20", 20},
+	}
+	for _, test := range tests {
+		code, err := ParseCode(test.group, test.name)
+		assert.NoError(t, err)
+		assert.EqualValues(t, test.code, code)
+	}
+
+	_, err = ParseCode(FabricCAServerStatus, "UNAVAILABLE")
+	assert.Error(t, err)
+}
+
+type mockClientStream struct {
+	grpc.ClientStream
+	trailer metadata.MD
+}
+
+func (m *mockClientStream) Trailer() metadata.MD {
+	return m.trailer
+}
+
+func TestGRPCStatusAndMetadata(t *testing.T) {
+	rpcStatus := grpcstatus.New(grpccodes.Unavailable, "unavailable")
+	s := NewFromGRPCStatus(rpcStatus)
+	assert.Equal(t, rpcStatus, s.GRPCStatus())
+	assert.Nil(t, s.Metadata)
+
+	s.WithMetadata(metadata.Pairs("key1", "value1"), nil, metadata.Pairs("key1", "value2", "key2", "value3"))
+	assert.Equal(t, []string{"value1", "value2"}, s.Metadata["key1"])
+	assert.Equal(t, []string{"value3"}, s.Metadata["key2"])
+
+	s = New(ChaincodeStatus, 500, "chaincode error", nil).WithGRPCStatus(rpcStatus)
+	assert.Equal(t, rpcStatus, s.GRPCStatus())
+
+	s = New(EndorserClientStatus, ConnectionFailed.ToInt32(), "test", nil)
+	assert.Nil(t, s.GRPCStatus())
+}
+
+func TestFromStreamError(t *testing.T) {
+	stream := &mockClientStream{trailer: metadata.Pairs("key", "value")}
+
+	err := FromStreamError(grpcstatus.New(grpccodes.Unavailable, "unavailable").Err(), stream)
+	s, ok := FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, GRPCTransportStatus, s.Group)
+	assert.EqualValues(t, grpccodes.Unavailable, s.Code)
+	assert.Equal(t, "unavailable", s.Message)
+	assert.Equal(t, []string{"value"}, s.Metadata["key"])
+	assert.NotNil(t, s.GRPCStatus())
+
+	testErr := fmt.Errorf("EOF")
+	assert.Equal(t, testErr, FromStreamError(testErr, stream))
+}
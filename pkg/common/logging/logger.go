@@ -89,6 +89,16 @@ func GetLevel(module string) Level {
 	return Level(modlog.GetLevel(module))
 }
 
+//GetLevels - getting log levels of the modules for which a level is set, e.g. by the
+//config of the SDK. Levels may be changed at runtime with SetLevel.
+func GetLevels() map[string]Level {
+	levels := make(map[string]Level)
+	for module, level := range modlog.GetLevels() {
+		levels[module] = Level(level)
+	}
+	return levels
+}
+
 //IsEnabledFor - Check if given log level is enabled for given module
 func IsEnabledFor(module string, level Level) bool {
 	return modlog.IsEnabledFor(module, api.Level(level))
@@ -140,6 +140,18 @@ func TestLoggerSetting(t *testing.T) {
 	assert.True(t, loggerProviderInstance != nil, "Logger is supposed to be initialized now")
 }
 
+func TestGetLevels(t *testing.T) {
+	SetLevel("module-xyz-levels1", DEBUG)
+	SetLevel("module-xyz-levels2", WARNING)
+
+	levels := GetLevels()
+	assert.Equal(t, DEBUG, levels["module-xyz-levels1"])
+	assert.Equal(t, WARNING, levels["module-xyz-levels2"])
+
+	levels["module-xyz-levels1"] = ERROR
+	assert.Equal(t, DEBUG, GetLevel("module-xyz-levels1"), "changing the returned levels is not supposed to change the level of the module")
+}
+
 func resetLoggerInstance() {
 	loggerProviderInstance = nil
 	loggerProviderOnce = sync.Once{}
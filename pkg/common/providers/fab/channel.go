@@ -8,6 +8,7 @@ package fab
 
 import (
 	reqContext "context"
+	"crypto/x509"
 
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	mspCfg "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
@@ -35,16 +36,101 @@ type ChannelCfg interface {
 	AnchorPeers() []*OrgAnchorPeer
 	Orderers() []string
 	Versions() *Versions
+	HasCapability(group ConfigGroupKey, capability string) bool
+	Capabilities(group ConfigGroupKey) []string
+	ConsensusType() string
+	Consenters() []*Consenter
 }
 
+// Consenter is a consenter of a BFT ordering service, as listed in the Orderers value of the Orderer config group
+type Consenter struct {
+	ID    uint32
+	Host  string
+	Port  uint32
+	MSPID string
+	// Identity is the (PEM encoded) certificate with which the consenter signs blocks
+	Identity []byte
+}
+
+// ConfigGroupKey is the key of a config group in the channel configuration
+type ConfigGroupKey string
+
+const (
+	// ChannelGroupKey is the key of the Channel config group
+	ChannelGroupKey ConfigGroupKey = ""
+	// OrdererGroupKey is the key of the Orderer config group
+	OrdererGroupKey ConfigGroupKey = "Orderer"
+	// ApplicationGroupKey is the key of the Application config group
+	ApplicationGroupKey ConfigGroupKey = "Application"
+)
+
+const (
+	// V1_1Capability indicates that Fabric 1.1 features are enabled
+	V1_1Capability = "V1_1"
+	// V1_2Capability indicates that Fabric 1.2 features are enabled
+	V1_2Capability = "V1_2"
+	// V1_3Capability indicates that Fabric 1.3 features are enabled
+	V1_3Capability = "V1_3"
+	// V1_4_2Capability indicates that Fabric 1.4.2 features are enabled
+	V1_4_2Capability = "V1_4_2"
+	// V1_4_3Capability indicates that Fabric 1.4.3 features are enabled
+	V1_4_3Capability = "V1_4_3"
+	// V2_0Capability indicates that Fabric 2.0 features are enabled
+	V2_0Capability = "V2_0"
+)
+
+const (
+	// BFTConsensusType is the consensus type of a BFT ordering service
+	BFTConsensusType = "BFT"
+	// SmartBFTConsensusType is the consensus type of a SmartBFT ordering service
+	SmartBFTConsensusType = "smartbft"
+)
+
 // ChannelMembership helps identify a channel's members
 type ChannelMembership interface {
 	// Validate if the given ID was issued by the channel's members
 	Validate(serializedID []byte) error
 	// Verify the given signature
 	Verify(serializedID []byte, msg []byte, sig []byte) error
+	// Role returns the role of the given ID as classified by the MSP which issued it
+	Role(serializedID []byte) (MemberRole, error)
+	// MSPIDs returns the IDs of the channel's MSPs
+	MSPIDs() ([]string, error)
+	// MSPInfo returns the certificates of the given MSP of the channel
+	MSPInfo(mspID string) (*MSPInfo, error)
+	// TLSCACertPool returns the channel's TLS CA cert pool, which contains the configured TLS root
+	// certificates and the TLS certificates of the channel's MSPs. Given certificates are added to the pool.
+	TLSCACertPool(certs ...*x509.Certificate) (*x509.CertPool, error)
+}
+
+// MSPInfo contains the (PEM encoded) certificates of a channel MSP
+type MSPInfo struct {
+	ID                   string
+	RootCerts            [][]byte
+	IntermediateCerts    [][]byte
+	TLSRootCerts         [][]byte
+	TLSIntermediateCerts [][]byte
+	AdminCerts           [][]byte
 }
 
+// MemberRole is the role of a channel member's identity. If NodeOUs are enabled in the
+// MSP configuration, identities are classified as clients, peers or orderers by their OU.
+type MemberRole string
+
+const (
+	// MemberRoleMember is the role of an identity which is a member of the MSP but
+	// isn't classified as an admin, peer or client
+	MemberRoleMember MemberRole = "member"
+	// MemberRoleAdmin is the role of an admin of the MSP
+	MemberRoleAdmin MemberRole = "admin"
+	// MemberRolePeer is the role of an identity classified as a peer
+	MemberRolePeer MemberRole = "peer"
+	// MemberRoleClient is the role of an identity classified as a client
+	MemberRoleClient MemberRole = "client"
+	// MemberRoleOrderer is the role of an identity classified as an orderer
+	MemberRoleOrderer MemberRole = "orderer"
+)
+
 // Versions ...
 type Versions struct {
 	ReadSet  *common.ConfigGroup
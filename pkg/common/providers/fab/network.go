@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package fab
 
 import (
+	"time"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
@@ -24,6 +26,34 @@ type NetworkConfig struct {
 	Peers                  map[string]PeerConfig
 	CertificateAuthorities map[string]msp.CAConfig
 	EntityMatchers         map[string][]MatchConfig
+	// RetryPolicies are named retry policies which are registered with the retry package when the
+	// configuration is loaded, so that they may be referenced by name by the channels and the clients
+	RetryPolicies map[string]RetryPolicyConfig
+}
+
+// RetryPolicyConfig defines a named retry policy
+type RetryPolicyConfig struct {
+	Attempts       int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	BackoffFactor  float64
+	// Jitter enables full jitter of the backoffs (see retry.Opts)
+	Jitter bool
+	// RetryableCodes are the codes, mapped by status group name (e.g. EventServerStatus), which warrant
+	// a retry. Codes may be given by name (e.g. MVCC_READ_CONFLICT) or by number. If no codes are given,
+	// the policy defaults to retry.DefaultRetryableCodes.
+	RetryableCodes map[string][]string
+	// CircuitBreaker configures the circuit breaker shared by the users of the policy (see retry.Opts)
+	CircuitBreaker CircuitBreakerConfig
+}
+
+// CircuitBreakerConfig defines the circuit breaker of a retry policy
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive retryable failures which trip the breaker.
+	// The policy has no breaker if zero.
+	FailureThreshold int
+	// CoolDown is the period for which an open circuit rejects requests before a trial request is allowed
+	CoolDown time.Duration
 }
 
 // ChannelNetworkConfig provides the definition of channels for the network
@@ -41,6 +71,25 @@ type ChannelNetworkConfig struct {
 type ChannelPolicies struct {
 	//Policy for querying channel block
 	QueryChannelConfig QueryChannelConfigPolicy
+	//Policy for selecting peers
+	Selection SelectionPolicy
+	// RetryPolicy is the name of the retry policy used by the channel client when no retry options
+	// are given for a request
+	RetryPolicy string
+}
+
+// SelectionPolicy defines opts for the selection of peers
+type SelectionPolicy struct {
+	// Balancer is the load-balancing strategy used to choose among equivalent peers:
+	// RoundRobin, Random, LeastOutstanding, LowestLatency, Nearest or Weighted
+	Balancer string
+	// ProbeInterval is the interval at which the round-trip time to the channel's (configured and
+	// discovered) peers is probed in the background. Probing is disabled if zero.
+	ProbeInterval time.Duration
+	// ResultCacheTTL is the time for which the peer groups which satisfy the endorsement policies of
+	// a set of chaincodes are cached, so that they aren't resolved for each transaction. The cached
+	// peer groups are resolved again when the channel's peers change. Caching is disabled if zero.
+	ResultCacheTTL time.Duration
 }
 
 //QueryChannelConfigPolicy defines opts for channelConfigBlock
@@ -48,6 +97,8 @@ type QueryChannelConfigPolicy struct {
 	MinResponses int
 	MaxTargets   int
 	RetryOpts    retry.Opts
+	// RetryPolicy is the name of a retry policy which is used instead of RetryOpts
+	RetryPolicy string
 }
 
 // PeerChannelConfig defines the peer capabilities
@@ -56,6 +107,12 @@ type PeerChannelConfig struct {
 	ChaincodeQuery bool
 	LedgerQuery    bool
 	EventSource    bool
+	// Weight is the relative weight of the peer when the Weighted balancer is used
+	Weight int
+	// Standby indicates that the peer is a hot-standby for the other (primary) peers of the channel.
+	// A connection to a standby peer is kept open and the peer is only selected as a target when
+	// none of the primary peers are available (e.g. while they're drained during a rolling restart).
+	Standby bool
 }
 
 // ChannelPeer combines channel peer info with raw peerConfig info
@@ -77,6 +134,9 @@ type OrganizationConfig struct {
 	Users                  map[string]endpoint.TLSKeyPair
 	Peers                  []string
 	CertificateAuthorities []string
+	// RevocationList contains the paths (or PEMs) of certificate revocation lists which are enforced
+	// for the organization's MSP in addition to the revocation lists in the channel config
+	RevocationList []endpoint.TLSConfig
 }
 
 // OrdererConfig defines an orderer configuration
@@ -84,6 +144,10 @@ type OrdererConfig struct {
 	URL         string
 	GRPCOptions map[string]interface{}
 	TLSCACerts  endpoint.TLSConfig
+	// TLSCertPins are the SHA-256 fingerprints of which at least one must match a TLS certificate presented by the orderer
+	TLSCertPins []string
+	// Operations is the operations service endpoint of the orderer
+	Operations OperationsConfig
 }
 
 // PeerConfig defines a peer configuration
@@ -92,6 +156,16 @@ type PeerConfig struct {
 	EventURL    string
 	GRPCOptions map[string]interface{}
 	TLSCACerts  endpoint.TLSConfig
+	// TLSCertPins are the SHA-256 fingerprints of which at least one must match a TLS certificate presented by the peer
+	TLSCertPins []string
+	// Operations is the operations service endpoint of the peer
+	Operations OperationsConfig
+}
+
+// OperationsConfig defines the operations service endpoint (health, version and metrics) of a peer or orderer
+type OperationsConfig struct {
+	URL        string
+	TLSCACerts endpoint.TLSConfig
 }
 
 // MatchConfig contains match pattern and substitution pattern
@@ -23,6 +23,16 @@ type ProposalSender interface {
 	SendTransactionProposal(*TransactionProposal, []ProposalProcessor) ([]*TransactionProposalResponse, error)
 }
 
+// ProposalCompletion is called with the responses received so far while a proposal is sent to
+// several targets. It returns true if no more responses are needed.
+type ProposalCompletion func(responses []*TransactionProposalResponse) bool
+
+// CompletingProposalSender is implemented by proposal senders which can return as soon as enough
+// responses are received, cancelling the proposals still in flight
+type CompletingProposalSender interface {
+	SendTransactionProposalUntil(*TransactionProposal, []ProposalProcessor, ProposalCompletion) ([]*TransactionProposalResponse, error)
+}
+
 // TransactionID provides the identifier of a Fabric transaction proposal.
 type TransactionID string
 
@@ -46,6 +56,9 @@ type ChaincodeInvokeRequest struct {
 	TransientMap map[string][]byte
 	Fcn          string
 	Args         [][]byte
+	// Headers are application headers (e.g. correlation IDs) which are included in the
+	// transaction as decorations of the chaincode input
+	Headers map[string][]byte
 }
 
 // TransactionProposal contains a marashalled transaction proposal.
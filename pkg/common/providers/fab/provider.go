@@ -36,7 +36,9 @@ type InfraProvider interface {
 	CreateChannelMembership(ctx ClientContext, channelID string) (ChannelMembership, error)
 	CreateEventService(ctx ClientContext, channelID string, opts ...options.Opt) (EventService, error)
 	CreatePeerFromConfig(peerCfg *NetworkPeer) (Peer, error)
+	CreateChannelPeerFromConfig(ctx ClientContext, channelID string, peerCfg *NetworkPeer) (Peer, error)
 	CreateOrdererFromConfig(cfg *OrdererConfig) (Orderer, error)
+	CreateChannelOrdererFromConfig(ctx ClientContext, channelID string, cfg *OrdererConfig) (Orderer, error)
 	CommManager() CommManager
 	Close()
 }
@@ -142,6 +144,11 @@ const (
 	DiscoveryResponse
 	// DiscoveryServiceRefresh discovery service refresh interval
 	DiscoveryServiceRefresh
+	// DNSSRVRefresh is the interval after which DNS SRV names of peers and orderers are resolved again
+	DNSSRVRefresh
+	// ChannelConfigMaxStaleness is the age after which a cached channel configuration which couldn't be
+	// refreshed is no longer used (zero if a stale channel configuration is used until it's refreshed)
+	ChannelConfigMaxStaleness
 )
 
 // EventServiceType specifies the type of event service to use
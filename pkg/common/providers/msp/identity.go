@@ -14,6 +14,9 @@ import (
 var (
 	// ErrUserNotFound indicates the user was not found
 	ErrUserNotFound = errors.New("user not found")
+
+	// ErrUserRevoked indicates the enrollment certificate of the user was revoked
+	ErrUserRevoked = errors.New("user revoked")
 )
 
 // IdentityManager provides management of identities in Fabric network
@@ -21,6 +24,15 @@ type IdentityManager interface {
 	GetSigningIdentity(name string) (SigningIdentity, error)
 }
 
+// IdentityLookup is implemented by the identity managers which can enumerate their users
+// and resolve users by enrollment certificate
+type IdentityLookup interface {
+	// ListUsers returns the IDs of the users known to the identity manager
+	ListUsers() ([]string, error)
+	// GetSigningIdentityByCert returns the signing identity of the user with the given enrollment certificate
+	GetSigningIdentityByCert(cert []byte) (SigningIdentity, error)
+}
+
 // Identity represents a Fabric client identity
 type Identity interface {
 
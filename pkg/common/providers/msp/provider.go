@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"time"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
 	logApi "github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
@@ -23,7 +25,7 @@ type IdentityManagerProvider interface {
 	IdentityManager(orgName string) (IdentityManager, bool)
 }
 
-//IdentityConfig contains identity configurations
+// IdentityConfig contains identity configurations
 type IdentityConfig interface {
 	Client() (*ClientConfig, error)
 	CAConfig(org string) (*CAConfig, error)
@@ -41,6 +43,7 @@ type ClientConfig struct {
 	CryptoConfig    CCType
 	TLSCerts        endpoint.MutualTLSConfig
 	CredentialStore CredentialStoreType
+	Proxy           endpoint.ProxyConfig
 }
 
 // CCType defines the path to crypto keys and certs
@@ -68,6 +71,31 @@ type CAConfig struct {
 	TLSCACerts endpoint.MutualTLSConfig
 	Registrar  EnrollCredentials
 	CAName     string
+	Proxy      endpoint.ProxyConfig
+	// TLSCertPins are the SHA-256 fingerprints of which at least one must match a TLS certificate presented by the CA
+	TLSCertPins []string
+	// TLSClientKeySKI is the hex-encoded SKI of the TLS client key in the crypto suite (e.g. a PKCS11 HSM).
+	// The key is used instead of the key of TLSCACerts.Client, which doesn't need to be configured.
+	TLSClientKeySKI string
+	// Operations configures the timeouts and the retry policies of the requests to the CA, by operation
+	Operations CAOperationsConfig
+}
+
+// CAOperationsConfig configures the requests of each operation of the CA
+type CAOperationsConfig struct {
+	Enroll   CAOperationConfig
+	Reenroll CAOperationConfig
+	Register CAOperationConfig
+	Revoke   CAOperationConfig
+}
+
+// CAOperationConfig configures the requests of an operation of the CA
+type CAOperationConfig struct {
+	// Timeout is the timeout of a request (no timeout if zero)
+	Timeout time.Duration
+	// RetryPolicy is the name of the retry policy (see retryPolicies) of the operation, e.g. "ca".
+	// The failed requests aren't retried if empty.
+	RetryPolicy string
 }
 
 // Providers represents a provider of MSP service.
@@ -20,6 +20,27 @@ type UserStore interface {
 	Load(IdentityIdentifier) (*UserData, error)
 }
 
+// UserLister is implemented by the user stores which can list their users
+type UserLister interface {
+	// List returns the identifiers of the users of the given MSP
+	// (or of all users if the MSP ID is empty)
+	List(mspID string) ([]IdentityIdentifier, error)
+}
+
+// UserDeleter is implemented by the user stores which can delete users
+type UserDeleter interface {
+	Delete(IdentityIdentifier) error
+}
+
+// UserRevocationStore is implemented by the user stores which can mark users as revoked
+type UserRevocationStore interface {
+	// MarkRevoked marks the stored enrollment certificate of the user as revoked.
+	// Storing a new enrollment certificate for the user clears the mark.
+	MarkRevoked(IdentityIdentifier) error
+	// IsRevoked returns true if the stored enrollment certificate of the user is marked as revoked
+	IsRevoked(IdentityIdentifier) (bool, error)
+}
+
 // PrivKeyKey is a composite key for accessing a private key in the key store
 type PrivKeyKey struct {
 	ID    string
@@ -418,6 +418,18 @@ func RequestClientContext(ctx reqContext.Context) (context.Client, bool) {
 	return clientContext, ok
 }
 
+// OverrideTimeouts returns the given timeouts (allocated if nil) with the timeouts of the given types
+// replaced by the overrides. It backs the WithCallTimeout request options of the clients.
+func OverrideTimeouts(timeouts, overrides map[fab.TimeoutType]time.Duration) map[fab.TimeoutType]time.Duration {
+	if timeouts == nil {
+		timeouts = make(map[fab.TimeoutType]time.Duration, len(overrides))
+	}
+	for timeoutType, timeout := range overrides {
+		timeouts[timeoutType] = timeout
+	}
+	return timeouts
+}
+
 // requestTimeoutOverrides extracts the timeout from timeout override map from the request-scoped context.
 func requestTimeoutOverride(ctx reqContext.Context, timeoutType fab.TimeoutType) time.Duration {
 	timeoutOverrides, ok := ctx.Value(ReqContextTimeoutOverrides).(map[fab.TimeoutType]time.Duration)
@@ -0,0 +1,58 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+const (
+	// GZIPCompression is the name of the built-in gzip compressor
+	GZIPCompression = "gzip"
+)
+
+// CompressorFactory creates the compressor used for outgoing messages and
+// the decompressor used for incoming messages of a GRPC connection
+type CompressorFactory func() (grpc.Compressor, grpc.Decompressor)
+
+var compressorsLock sync.RWMutex
+var compressors = map[string]CompressorFactory{
+	GZIPCompression: func() (grpc.Compressor, grpc.Decompressor) {
+		return grpc.NewGZIPCompressor(), grpc.NewGZIPDecompressor()
+	},
+}
+
+// RegisterCompressor registers a custom compressor under the given name. The compressor
+// may then be selected for peer and orderer connections with the 'compression' GRPC option.
+func RegisterCompressor(name string, factory CompressorFactory) {
+	compressorsLock.Lock()
+	defer compressorsLock.Unlock()
+
+	compressors[name] = factory
+}
+
+// CompressionDialOptions returns the GRPC dial options which enable the compressor with
+// the given name on a connection. No options are returned if the name is empty or "none".
+func CompressionDialOptions(name string) ([]grpc.DialOption, error) {
+	if name == "" || name == "none" {
+		return nil, nil
+	}
+
+	compressorsLock.RLock()
+	factory, ok := compressors[name]
+	compressorsLock.RUnlock()
+
+	if !ok {
+		return nil, errors.Errorf("unsupported compression [%s]", name)
+	}
+
+	compressor, decompressor := factory()
+	return []grpc.DialOption{grpc.WithCompressor(compressor), grpc.WithDecompressor(decompressor)}, nil
+}
@@ -0,0 +1,48 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"testing"
+
+	"google.golang.org/grpc"
+)
+
+func TestCompressionDialOptions(t *testing.T) {
+	opts, err := CompressionDialOptions("")
+	if err != nil || len(opts) != 0 {
+		t.Fatalf("expected no dial options without compression")
+	}
+
+	opts, err = CompressionDialOptions(GZIPCompression)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(opts) != 2 {
+		t.Fatalf("expected compressor and decompressor dial options but got %d options", len(opts))
+	}
+
+	if _, err = CompressionDialOptions("unknown"); err == nil {
+		t.Fatal("expected error for unsupported compression")
+	}
+}
+
+func TestRegisterCompressor(t *testing.T) {
+	invoked := false
+	RegisterCompressor("custom", func() (grpc.Compressor, grpc.Decompressor) {
+		invoked = true
+		return grpc.NewGZIPCompressor(), grpc.NewGZIPDecompressor()
+	})
+
+	opts, err := CompressionDialOptions("custom")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !invoked || len(opts) != 2 {
+		t.Fatal("expected custom compressor to be used")
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+const (
+	// DefaultDialer is the name under which the dialer used for peers and orderers
+	// that don't select a dialer with the 'dialer' GRPC option may be registered
+	DefaultDialer = "default"
+)
+
+// Dialer establishes the network connection to the given address of a peer or orderer.
+// Custom dialers may be used to connect through VPN tunnels, unix sockets or in-memory
+// pipes (in tests).
+type Dialer func(address string, timeout time.Duration) (net.Conn, error)
+
+// DialerProvider is implemented by endpoint configs which hold custom dialers
+type DialerProvider interface {
+	Dialer(name string) (Dialer, bool)
+}
+
+// Dialers holds the custom dialers of an endpoint config by name.
+//
+// This component has been designed to be safe for concurrency.
+type Dialers struct {
+	lock    sync.RWMutex
+	dialers map[string]Dialer
+}
+
+// RegisterDialer registers a custom dialer under the given name. The dialer may then be selected
+// for peer and orderer connections with the 'dialer' GRPC option. A dialer registered under the
+// name DefaultDialer is used for all connections which don't select a dialer.
+func (d *Dialers) RegisterDialer(name string, dialer Dialer) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.dialers == nil {
+		d.dialers = make(map[string]Dialer)
+	}
+	d.dialers[name] = dialer
+}
+
+// Dialer returns the dialer registered under the given name
+func (d *Dialers) Dialer(name string) (Dialer, bool) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	dialer, ok := d.dialers[name]
+	return dialer, ok
+}
+
+// DialerDialOption returns the GRPC dial option which establishes connections with the dialer
+// registered with the given endpoint config under the given name (or the default dialer if the
+// name is empty). Nil is returned if the name is empty and no default dialer is registered.
+func DialerDialOption(config fab.EndpointConfig, name string) (grpc.DialOption, error) {
+	provider, ok := config.(DialerProvider)
+
+	if name == "" {
+		if ok {
+			if dialer, ok := provider.Dialer(DefaultDialer); ok {
+				return grpc.WithDialer(dialer), nil
+			}
+		}
+		return nil, nil
+	}
+
+	if !ok {
+		return nil, errors.Errorf("dialer [%s] is not registered", name)
+	}
+	dialer, ok := provider.Dialer(name)
+	if !ok {
+		return nil, errors.Errorf("dialer [%s] is not registered", name)
+	}
+	return grpc.WithDialer(dialer), nil
+}
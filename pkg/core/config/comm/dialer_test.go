@@ -0,0 +1,58 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/test/mockfab"
+)
+
+type dialerConfig struct {
+	mockfab.MockEndpointConfig
+	Dialers
+}
+
+func TestDialerDialOption(t *testing.T) {
+	config := &dialerConfig{}
+
+	opt, err := DialerDialOption(config, "")
+	if err != nil || opt != nil {
+		t.Fatal("expected no dial option without dialer")
+	}
+
+	if _, err = DialerDialOption(config, "pipe"); err == nil {
+		t.Fatal("expected error for unregistered dialer")
+	}
+
+	config.RegisterDialer("pipe", func(address string, timeout time.Duration) (net.Conn, error) {
+		client, _ := net.Pipe()
+		return client, nil
+	})
+
+	opt, err = DialerDialOption(config, "pipe")
+	if err != nil || opt == nil {
+		t.Fatalf("expected dial option for registered dialer: %v", err)
+	}
+
+	// Dialers are registered with a config and aren't visible to other configs
+	if _, err = DialerDialOption(&dialerConfig{}, "pipe"); err == nil {
+		t.Fatal("expected error for dialer registered with another config")
+	}
+
+	config.RegisterDialer(DefaultDialer, func(address string, timeout time.Duration) (net.Conn, error) {
+		client, _ := net.Pipe()
+		return client, nil
+	})
+
+	opt, err = DialerDialOption(config, "")
+	if err != nil || opt == nil {
+		t.Fatalf("expected dial option for default dialer: %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"google.golang.org/grpc/grpclog"
+)
+
+const grpcLoggerModule = "fabsdk/grpc"
+
+var grpcDiagnosticsOnce sync.Once
+
+// EnableGRPCDiagnostics routes the log output of the GRPC library to the SDK logger (module "fabsdk/grpc")
+// and logs a warning, including the likely cause, whenever a server closes a connection with
+// GOAWAY (ENHANCE_YOUR_CALM) because the keep-alive policy of the server was violated.
+//
+// The endpoint config calls this function if 'client.grpc.diagnostics' is enabled. Since the
+// GRPC logger is global, it only takes effect once and should be enabled before any connections
+// are established.
+func EnableGRPCDiagnostics() {
+	grpcDiagnosticsOnce.Do(func() {
+		grpclog.SetLoggerV2(&grpcLogger{logger: logging.NewLogger(grpcLoggerModule)})
+	})
+}
+
+// grpcLogger implements grpclog.LoggerV2
+type grpcLogger struct {
+	logger *logging.Logger
+}
+
+func (l *grpcLogger) Info(args ...interface{}) {
+	l.log(fmt.Sprint(args...))
+}
+
+func (l *grpcLogger) Infoln(args ...interface{}) {
+	l.log(fmt.Sprint(args...))
+}
+
+func (l *grpcLogger) Infof(format string, args ...interface{}) {
+	l.log(fmt.Sprintf(format, args...))
+}
+
+func (l *grpcLogger) Warning(args ...interface{}) {
+	l.logger.Warn(args...)
+}
+
+func (l *grpcLogger) Warningln(args ...interface{}) {
+	l.logger.Warnln(args...)
+}
+
+func (l *grpcLogger) Warningf(format string, args ...interface{}) {
+	l.logger.Warnf(format, args...)
+}
+
+func (l *grpcLogger) Error(args ...interface{}) {
+	l.logger.Error(args...)
+}
+
+func (l *grpcLogger) Errorln(args ...interface{}) {
+	l.logger.Errorln(args...)
+}
+
+func (l *grpcLogger) Errorf(format string, args ...interface{}) {
+	l.logger.Errorf(format, args...)
+}
+
+func (l *grpcLogger) Fatal(args ...interface{}) {
+	l.logger.Fatal(args...)
+}
+
+func (l *grpcLogger) Fatalln(args ...interface{}) {
+	l.logger.Fatalln(args...)
+}
+
+func (l *grpcLogger) Fatalf(format string, args ...interface{}) {
+	l.logger.Fatalf(format, args...)
+}
+
+// V returns true for all verbosity levels up to 2 since GRPC only reports
+// GOAWAY frames at that level
+func (l *grpcLogger) V(level int) bool {
+	return level <= 2
+}
+
+func (l *grpcLogger) log(msg string) {
+	if isEnhanceYourCalm(msg) {
+		l.logger.Warnf("Connection closed by server with GOAWAY (ENHANCE_YOUR_CALM): keep-alive pings are sent more often than permitted by the server. Increase keep-alive-time to at least the server's minimum interval (%s by default) or disable keep-alive-permit. [%s]", DefaultKeepAliveMinServerInterval, msg)
+		return
+	}
+	l.logger.Debug(msg)
+}
+
+func isEnhanceYourCalm(msg string) bool {
+	msg = strings.ToLower(msg)
+	return strings.Contains(msg, "enhanceyourcalm") || strings.Contains(msg, "enhance_your_calm") || strings.Contains(msg, "too_many_pings")
+}
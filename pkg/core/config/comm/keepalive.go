@@ -0,0 +1,90 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"time"
+
+	"github.com/spf13/cast"
+	"google.golang.org/grpc/keepalive"
+)
+
+const (
+	// DefaultKeepAliveMinServerInterval is the minimum interval between client keep-alive pings
+	// permitted by default by Fabric peers (peer.keepalive.minInterval) and orderers
+	// (General.Keepalive.ServerMinInterval). Servers close the connections of clients which ping
+	// more often with GOAWAY (ENHANCE_YOUR_CALM).
+	DefaultKeepAliveMinServerInterval = 60 * time.Second
+
+	// defaultKeepAliveTimeout is the GRPC default for the keep-alive timeout
+	defaultKeepAliveTimeout = 20 * time.Second
+)
+
+// KeepAliveEnforcementPolicy is the keep-alive enforcement policy of the server
+type KeepAliveEnforcementPolicy struct {
+	// MinTime is the minimum interval between keep-alive pings permitted by the server
+	MinTime time.Duration
+	// PermitWithoutStream is true if the server permits keep-alive pings when there are no active streams
+	PermitWithoutStream bool
+}
+
+// KeepAliveParams returns the keep-alive parameters defined by the given GRPC options of a peer
+// or orderer ('keep-alive-time', 'keep-alive-timeout' and 'keep-alive-permit'), validated against
+// the enforcement policy of the server ('keep-alive-server-min-interval' and 'keep-alive-server-permit',
+// which default to the Fabric defaults of 60s and true respectively).
+func KeepAliveParams(grpcOptions map[string]interface{}) keepalive.ClientParameters {
+	var kap keepalive.ClientParameters
+	if kaTime, ok := grpcOptions["keep-alive-time"]; ok {
+		kap.Time = cast.ToDuration(kaTime)
+	}
+	if kaTimeout, ok := grpcOptions["keep-alive-timeout"]; ok {
+		kap.Timeout = cast.ToDuration(kaTimeout)
+	}
+	if kaPermit, ok := grpcOptions["keep-alive-permit"]; ok {
+		kap.PermitWithoutStream = cast.ToBool(kaPermit)
+	}
+
+	policy := KeepAliveEnforcementPolicy{
+		MinTime:             DefaultKeepAliveMinServerInterval,
+		PermitWithoutStream: true,
+	}
+	if minTime, ok := grpcOptions["keep-alive-server-min-interval"]; ok {
+		policy.MinTime = cast.ToDuration(minTime)
+	}
+	if permit, ok := grpcOptions["keep-alive-server-permit"]; ok {
+		policy.PermitWithoutStream = cast.ToBool(permit)
+	}
+
+	return ValidateKeepAliveParams(kap, policy)
+}
+
+// ValidateKeepAliveParams adjusts the given keep-alive parameters so that they comply with the
+// given enforcement policy of the server. Without adjustment the server would consider the pings
+// abusive and close the connection with GOAWAY (ENHANCE_YOUR_CALM). A warning is logged for each
+// parameter that was adjusted.
+func ValidateKeepAliveParams(kap keepalive.ClientParameters, policy KeepAliveEnforcementPolicy) keepalive.ClientParameters {
+	if kap.Time <= 0 {
+		// Keep-alive is disabled
+		return kap
+	}
+
+	if kap.Time < policy.MinTime {
+		logger.Warnf("keep-alive-time [%s] is less than the minimum interval [%s] permitted by the server - using [%s] to avoid the connection being closed by the server", kap.Time, policy.MinTime, policy.MinTime)
+		kap.Time = policy.MinTime
+	}
+
+	if kap.Timeout <= 0 {
+		kap.Timeout = defaultKeepAliveTimeout
+	}
+
+	if kap.PermitWithoutStream && !policy.PermitWithoutStream {
+		logger.Warn("keep-alive-permit is not permitted by the server - disabling keep-alive pings without active streams to avoid the connection being closed by the server")
+		kap.PermitWithoutStream = false
+	}
+
+	return kap
+}
@@ -0,0 +1,60 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKeepAliveParams(t *testing.T) {
+	kap := KeepAliveParams(map[string]interface{}{})
+	if kap.Time != 0 || kap.Timeout != 0 || kap.PermitWithoutStream {
+		t.Fatalf("expected keep-alive to be disabled by default but got %+v", kap)
+	}
+
+	kap = KeepAliveParams(map[string]interface{}{
+		"keep-alive-time":    "90s",
+		"keep-alive-timeout": 5 * time.Second,
+		"keep-alive-permit":  true,
+	})
+	if kap.Time != 90*time.Second || kap.Timeout != 5*time.Second || !kap.PermitWithoutStream {
+		t.Fatalf("unexpected keep-alive params %+v", kap)
+	}
+
+	kap = KeepAliveParams(map[string]interface{}{
+		"keep-alive-time": 10 * time.Second,
+	})
+	if kap.Time != DefaultKeepAliveMinServerInterval {
+		t.Fatalf("expected keep-alive-time to be raised to %s but got %s", DefaultKeepAliveMinServerInterval, kap.Time)
+	}
+	if kap.Timeout != defaultKeepAliveTimeout {
+		t.Fatalf("expected default keep-alive-timeout but got %s", kap.Timeout)
+	}
+
+	kap = KeepAliveParams(map[string]interface{}{
+		"keep-alive-time":                10 * time.Second,
+		"keep-alive-permit":              true,
+		"keep-alive-server-min-interval": "5s",
+		"keep-alive-server-permit":       false,
+	})
+	if kap.Time != 10*time.Second {
+		t.Fatalf("expected keep-alive-time to be kept but got %s", kap.Time)
+	}
+	if kap.PermitWithoutStream {
+		t.Fatal("expected keep-alive-permit to be disabled")
+	}
+}
+
+func TestIsEnhanceYourCalm(t *testing.T) {
+	if !isEnhanceYourCalm("Client received GoAway with http2.ErrCodeEnhanceYourCalm.") {
+		t.Fatal("expected ENHANCE_YOUR_CALM to be detected")
+	}
+	if isEnhanceYourCalm("pickfirstBalancer: HandleSubConnStateChange") {
+		t.Fatal("unexpected ENHANCE_YOUR_CALM detection")
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const sha256PinPrefix = "sha256:"
+
+// CertificateFingerprint returns the SHA-256 fingerprint of the given DER encoded
+// certificate as a lower-case hex string
+func CertificateFingerprint(der []byte) string {
+	hash := sha256.Sum256(der)
+	return hex.EncodeToString(hash[:])
+}
+
+// VerifyCertificatePins verifies that at least one of the certificates of the verified chains (the server
+// certificate or one of the issuers that it was verified against) matches one of the given SHA-256
+// fingerprints. Certificates that the server presented but which aren't part of a verified chain are
+// ignored since anybody can append them. If chain verification is skipped (no verified chains) then only
+// the server certificate is matched. Pins are hex encoded and may contain colons and an optional
+// 'sha256:' prefix. Nil is returned if no pins are given.
+func VerifyCertificatePins(rawCerts [][]byte, verifiedChains [][]*x509.Certificate, pins []string) error {
+	if len(pins) == 0 {
+		return nil
+	}
+
+	normalized := make(map[string]bool)
+	for _, pin := range pins {
+		p, err := normalizePin(pin)
+		if err != nil {
+			return err
+		}
+		normalized[p] = true
+	}
+
+	if len(verifiedChains) == 0 {
+		if len(rawCerts) > 0 && normalized[CertificateFingerprint(rawCerts[0])] {
+			return nil
+		}
+	}
+
+	for _, chain := range verifiedChains {
+		for _, cert := range chain {
+			if normalized[CertificateFingerprint(cert.Raw)] {
+				return nil
+			}
+		}
+	}
+
+	if len(rawCerts) > 0 {
+		logger.Warnf("Server certificate with fingerprint [%s] does not match any of the pinned certificates", CertificateFingerprint(rawCerts[0]))
+	}
+	return errors.New("server certificate does not match any of the pinned certificates")
+}
+
+// CertificatePinVerifier returns a function for tls.Config.VerifyPeerCertificate which verifies
+// the certificates presented by the server against the given pins
+func CertificatePinVerifier(pins []string) func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+		return VerifyCertificatePins(rawCerts, verifiedChains, pins)
+	}
+}
+
+func normalizePin(pin string) (string, error) {
+	p := strings.ToLower(strings.TrimSpace(pin))
+	p = strings.TrimPrefix(p, sha256PinPrefix)
+	p = strings.Replace(p, ":", "", -1)
+
+	if b, err := hex.DecodeString(p); err != nil || len(b) != sha256.Size {
+		return "", errors.Errorf("invalid SHA-256 certificate pin [%s]", pin)
+	}
+	return p, nil
+}
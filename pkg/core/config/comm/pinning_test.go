@@ -0,0 +1,69 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"crypto/x509"
+	"strings"
+	"testing"
+)
+
+func TestVerifyCertificatePins(t *testing.T) {
+	leaf := &x509.Certificate{Raw: []byte("leaf certificate")}
+	issuer := &x509.Certificate{Raw: []byte("issuer certificate")}
+	rawCerts := [][]byte{leaf.Raw, issuer.Raw}
+	verifiedChains := [][]*x509.Certificate{{leaf, issuer}}
+
+	if err := VerifyCertificatePins(rawCerts, verifiedChains, nil); err != nil {
+		t.Fatalf("expected no error without pins: %s", err)
+	}
+
+	if err := VerifyCertificatePins(rawCerts, verifiedChains, []string{CertificateFingerprint(leaf.Raw)}); err != nil {
+		t.Fatalf("expected leaf pin to match: %s", err)
+	}
+
+	// Pins may be upper-case with colons and prefix
+	fingerprint := strings.ToUpper(CertificateFingerprint(issuer.Raw))
+	var colonSeparated []string
+	for i := 0; i < len(fingerprint); i += 2 {
+		colonSeparated = append(colonSeparated, fingerprint[i:i+2])
+	}
+	if err := VerifyCertificatePins(rawCerts, verifiedChains, []string{"sha256:" + strings.Join(colonSeparated, ":")}); err != nil {
+		t.Fatalf("expected issuer pin to match: %s", err)
+	}
+
+	if err := VerifyCertificatePins(rawCerts, verifiedChains, []string{CertificateFingerprint([]byte("other"))}); err == nil {
+		t.Fatal("expected error for pin mismatch")
+	}
+
+	if err := VerifyCertificatePins(rawCerts, verifiedChains, []string{"invalid"}); err == nil {
+		t.Fatal("expected error for invalid pin")
+	}
+}
+
+func TestVerifyCertificatePinsUntrustedChain(t *testing.T) {
+	leaf := &x509.Certificate{Raw: []byte("leaf certificate")}
+	issuer := &x509.Certificate{Raw: []byte("issuer certificate")}
+	pinned := []byte("pinned certificate")
+
+	// The server appends the pinned certificate to the chain it presents but the
+	// chain that was verified doesn't include it
+	rawCerts := [][]byte{leaf.Raw, issuer.Raw, pinned}
+	verifiedChains := [][]*x509.Certificate{{leaf, issuer}}
+
+	if err := VerifyCertificatePins(rawCerts, verifiedChains, []string{CertificateFingerprint(pinned)}); err == nil {
+		t.Fatal("expected error for pinned certificate which isn't part of a verified chain")
+	}
+
+	// Without chain verification only the server certificate is matched
+	if err := VerifyCertificatePins(rawCerts, nil, []string{CertificateFingerprint(pinned)}); err == nil {
+		t.Fatal("expected error for pinned certificate which isn't the server certificate")
+	}
+	if err := VerifyCertificatePins(rawCerts, nil, []string{CertificateFingerprint(leaf.Raw)}); err != nil {
+		t.Fatalf("expected server certificate pin to match without chain verification: %s", err)
+	}
+}
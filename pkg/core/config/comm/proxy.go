@@ -0,0 +1,161 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+	"github.com/pkg/errors"
+	"golang.org/x/net/proxy"
+	"google.golang.org/grpc"
+)
+
+var logger = logging.NewLogger("fabsdk/core")
+
+// ProxyDialOption returns a GRPC dial option which tunnels the connection to the given target
+// through the proxy resolved from the given proxy configuration. Nil is returned if the target
+// is to be accessed directly.
+func ProxyDialOption(target string, cfg endpoint.ProxyConfig) (grpc.DialOption, error) {
+	proxyURL, err := cfg.ResolveProxy(target)
+	if err != nil {
+		return nil, err
+	}
+	if proxyURL == nil {
+		return nil, nil
+	}
+
+	dialer, err := ProxyDialer(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	return grpc.WithDialer(dialer), nil
+}
+
+// ProxyDialer returns a GRPC compatible dialer which establishes connections through the given
+// HTTP (CONNECT) or SOCKS5 proxy
+func ProxyDialer(proxyURL *url.URL) (func(address string, timeout time.Duration) (net.Conn, error), error) {
+	switch proxyURL.Scheme {
+	case "socks5", "socks5h":
+		var auth *proxy.Auth
+		if proxyURL.User != nil {
+			password, _ := proxyURL.User.Password()
+			auth = &proxy.Auth{User: proxyURL.User.Username(), Password: password}
+		}
+		return func(address string, timeout time.Duration) (net.Conn, error) {
+			dialer, err := proxy.SOCKS5("tcp", proxyAddress(proxyURL), auth, &net.Dialer{Timeout: timeout})
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to create SOCKS5 dialer")
+			}
+			return dialer.Dial("tcp", address)
+		}, nil
+	case "http", "https":
+		return func(address string, timeout time.Duration) (net.Conn, error) {
+			return dialHTTPConnect(proxyURL, address, timeout)
+		}, nil
+	default:
+		return nil, errors.Errorf("unsupported proxy scheme [%s]", proxyURL.Scheme)
+	}
+}
+
+func dialHTTPConnect(proxyURL *url.URL, address string, timeout time.Duration) (net.Conn, error) {
+	conn, err := net.DialTimeout("tcp", proxyAddress(proxyURL), timeout)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to proxy [%s]", proxyURL.Host)
+	}
+
+	if proxyURL.Scheme == "https" {
+		conn = tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname()})
+	}
+
+	if timeout > 0 {
+		if err = conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+			closeQuietly(conn)
+			return nil, err
+		}
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Host: address},
+		Host:   address,
+		Header: make(http.Header),
+	}
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	if err = req.Write(conn); err != nil {
+		closeQuietly(conn)
+		return nil, errors.Wrap(err, "failed to send CONNECT request to proxy")
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		closeQuietly(conn)
+		return nil, errors.Wrap(err, "failed to read CONNECT response from proxy")
+	}
+	closeQuietly(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		closeQuietly(conn)
+		return nil, errors.Errorf("proxy [%s] refused connection to [%s]: %s", proxyURL.Host, address, resp.Status)
+	}
+
+	if err = conn.SetDeadline(time.Time{}); err != nil {
+		closeQuietly(conn)
+		return nil, err
+	}
+
+	if reader.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, reader: reader}, nil
+	}
+	return conn, nil
+}
+
+func proxyAddress(proxyURL *url.URL) string {
+	if proxyURL.Port() != "" {
+		return proxyURL.Host
+	}
+
+	switch proxyURL.Scheme {
+	case "https":
+		return net.JoinHostPort(proxyURL.Hostname(), "443")
+	case "socks5", "socks5h":
+		return net.JoinHostPort(proxyURL.Hostname(), "1080")
+	default:
+		return net.JoinHostPort(proxyURL.Hostname(), "80")
+	}
+}
+
+// bufferedConn reads any data which the proxy sent along with the CONNECT
+// response before reading from the underlying connection
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+func closeQuietly(c io.Closer) {
+	if err := c.Close(); err != nil {
+		logger.Debugf("unable to close proxy connection [%s]", err)
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+)
+
+func TestProxyDialOptionDirect(t *testing.T) {
+	opt, err := ProxyDialOption("localhost:7051", endpoint.ProxyConfig{URL: "http://proxy.example.com:3128", NoProxy: "localhost"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if opt != nil {
+		t.Fatal("expected no dial option for a target which bypasses the proxy")
+	}
+}
+
+func TestProxyDialerHTTPConnect(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	defer listener.Close()
+
+	requests := make(chan *http.Request, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		req, err := http.ReadRequest(bufio.NewReader(conn))
+		if err != nil {
+			return
+		}
+		requests <- req
+		if _, err := conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n")); err != nil {
+			return
+		}
+	}()
+
+	dialer, err := ProxyDialer(&url.URL{Scheme: "http", Host: listener.Addr().String(), User: url.UserPassword("user", "pass")})
+	if err != nil {
+		t.Fatalf("unexpected error creating dialer: %s", err)
+	}
+
+	conn, err := dialer("peer0.org1.example.com:7051", time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error dialing through proxy: %s", err)
+	}
+	defer conn.Close()
+
+	req := <-requests
+	if req.Method != http.MethodConnect || req.Host != "peer0.org1.example.com:7051" {
+		t.Fatalf("unexpected CONNECT request: %s %s", req.Method, req.Host)
+	}
+	if req.Header.Get("Proxy-Authorization") == "" {
+		t.Fatal("expected proxy authorization header")
+	}
+}
+
+func TestProxyDialerHTTPConnectRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		if _, err := http.ReadRequest(bufio.NewReader(conn)); err != nil {
+			return
+		}
+		if _, err := conn.Write([]byte("HTTP/1.1 403 Forbidden\r\nContent-Length: 0\r\n\r\n")); err != nil {
+			return
+		}
+	}()
+
+	dialer, err := ProxyDialer(&url.URL{Scheme: "http", Host: listener.Addr().String()})
+	if err != nil {
+		t.Fatalf("unexpected error creating dialer: %s", err)
+	}
+
+	if _, err := dialer("peer0.org1.example.com:7051", time.Second); err == nil {
+		t.Fatal("expected error when proxy refuses the connection")
+	}
+}
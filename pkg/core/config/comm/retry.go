@@ -0,0 +1,372 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+const (
+	defaultRetryInitialBackoff = 100 * time.Millisecond
+	defaultRetryMaxBackoff     = 2 * time.Second
+	defaultRetryBackoffFactor  = 2.0
+)
+
+// RetryPolicy defines how unary GRPC calls which fail with one of the retryable status codes
+// are retried at the transport level. Unlike retry.Opts, which re-run an entire operation
+// (e.g. collecting endorsements), the transport-level policy re-sends the same request over
+// the connection so that transient failures are absorbed without involving the caller.
+//
+// If HedgingDelay is set then the call is hedged instead: if no response was received within
+// the delay then the request is sent again (up to MaxAttempts requests in flight) and the first
+// successful response is used. Hedging should only be enabled for idempotent calls.
+//
+// For streaming calls (e.g. /orderer.AtomicBroadcast/Broadcast) only the opening of the stream is
+// retried; messages sent or received on an established stream are not retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts (including the original request).
+	// The policy is disabled if less than 2.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum delay between retries
+	MaxBackoff time.Duration
+	// BackoffFactor is the factor by which the backoff is multiplied after each retry
+	BackoffFactor float64
+	// RetryableCodes are the GRPC status codes which cause the call to be retried (default: UNAVAILABLE)
+	RetryableCodes []codes.Code
+	// Methods restricts the policy to the given full method names (e.g. /protos.Endorser/ProcessProposal).
+	// The policy applies to all unary methods if empty.
+	Methods []string
+	// HedgingDelay enables hedging if greater than zero
+	HedgingDelay time.Duration
+	// CodePolicies overrides the number of attempts and the backoff for specific status codes.
+	// The codes are retryable even if they're not included in RetryableCodes.
+	CodePolicies map[codes.Code]CodePolicy
+}
+
+// CodePolicy defines how calls that fail with a specific GRPC status code are retried.
+// Unset fields default to the values of the enclosing RetryPolicy.
+type CodePolicy struct {
+	// MaxAttempts is the maximum number of attempts (including the original request)
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum delay between retries
+	MaxBackoff time.Duration
+}
+
+// RetryPolicyFromOptions returns the transport-level retry policy defined by the given GRPC options
+// of a peer or orderer, i.e. 'retry-attempts', 'retry-initial-backoff', 'retry-max-backoff',
+// 'retry-backoff-factor', 'retry-codes', 'retry-methods', 'hedging-delay' and 'retry-code-policies'.
+// The 'retry-code-policies' option maps status codes to their own 'attempts', 'initial-backoff'
+// and 'max-backoff', for example:
+//
+//   retry-code-policies:
+//     RESOURCE_EXHAUSTED:
+//       attempts: 5
+//       initial-backoff: 1s
+func RetryPolicyFromOptions(grpcOptions map[string]interface{}) (RetryPolicy, error) {
+	policy := RetryPolicy{
+		InitialBackoff: defaultRetryInitialBackoff,
+		MaxBackoff:     defaultRetryMaxBackoff,
+		BackoffFactor:  defaultRetryBackoffFactor,
+		RetryableCodes: []codes.Code{codes.Unavailable},
+	}
+
+	if attempts, ok := grpcOptions["retry-attempts"]; ok {
+		policy.MaxAttempts = cast.ToInt(attempts)
+	}
+	if backoff, ok := grpcOptions["retry-initial-backoff"]; ok {
+		policy.InitialBackoff = cast.ToDuration(backoff)
+	}
+	if backoff, ok := grpcOptions["retry-max-backoff"]; ok {
+		policy.MaxBackoff = cast.ToDuration(backoff)
+	}
+	if factor, ok := grpcOptions["retry-backoff-factor"]; ok {
+		policy.BackoffFactor = cast.ToFloat64(factor)
+	}
+	if methods, ok := grpcOptions["retry-methods"]; ok {
+		policy.Methods = cast.ToStringSlice(methods)
+	}
+	if delay, ok := grpcOptions["hedging-delay"]; ok {
+		policy.HedgingDelay = cast.ToDuration(delay)
+	}
+	if retryCodes, ok := grpcOptions["retry-codes"]; ok {
+		var err error
+		policy.RetryableCodes, err = parseCodes(cast.ToStringSlice(retryCodes))
+		if err != nil {
+			return RetryPolicy{}, err
+		}
+	}
+	if codePolicies, ok := grpcOptions["retry-code-policies"]; ok {
+		var err error
+		policy.CodePolicies, err = parseCodePolicies(cast.ToStringMap(codePolicies))
+		if err != nil {
+			return RetryPolicy{}, err
+		}
+	}
+
+	return policy, nil
+}
+
+func parseCodePolicies(values map[string]interface{}) (map[codes.Code]CodePolicy, error) {
+	policies := make(map[codes.Code]CodePolicy, len(values))
+	for name, value := range values {
+		code, err := parseCode(name)
+		if err != nil {
+			return nil, err
+		}
+
+		opts := cast.ToStringMap(value)
+		policies[code] = CodePolicy{
+			MaxAttempts:    cast.ToInt(opts["attempts"]),
+			InitialBackoff: cast.ToDuration(opts["initial-backoff"]),
+			MaxBackoff:     cast.ToDuration(opts["max-backoff"]),
+		}
+	}
+	return policies, nil
+}
+
+// RetryDialOptions returns the GRPC dial options which apply the given retry policy to
+// unary calls and to the opening of streams. Nil is returned if the policy is disabled.
+func RetryDialOptions(policy RetryPolicy) []grpc.DialOption {
+	if !policy.enabled() {
+		return nil
+	}
+	return []grpc.DialOption{
+		grpc.WithUnaryInterceptor(policy.unaryInterceptor),
+		grpc.WithStreamInterceptor(policy.streamInterceptor),
+	}
+}
+
+func (p RetryPolicy) enabled() bool {
+	if p.MaxAttempts >= 2 {
+		return true
+	}
+	for _, cp := range p.CodePolicies {
+		if cp.MaxAttempts >= 2 {
+			return true
+		}
+	}
+	return false
+}
+
+func (p RetryPolicy) unaryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if !p.appliesTo(method) {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	if p.HedgingDelay > 0 {
+		return p.invokeHedged(ctx, method, req, reply, cc, invoker, opts...)
+	}
+	return p.retry(ctx, method, func() error {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	})
+}
+
+func (p RetryPolicy) streamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if !p.appliesTo(method) {
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+
+	var stream grpc.ClientStream
+	err := p.retry(ctx, method, func() error {
+		var err error
+		stream, err = streamer(ctx, desc, cc, method, opts...)
+		return err
+	})
+	return stream, err
+}
+
+func (p RetryPolicy) retry(ctx context.Context, method string, invoke func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := invoke()
+		if err == nil {
+			return nil
+		}
+
+		maxAttempts, backoff, retryable := p.forAttempt(err, attempt)
+		if !retryable || attempt >= maxAttempts {
+			return err
+		}
+
+		logger.Debugf("Retrying GRPC call [%s] in %s (attempt %d of %d): %s", method, backoff, attempt+1, maxAttempts, err)
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// forAttempt returns the maximum number of attempts and the backoff before the next attempt
+// for a call that failed with the given error, taking the policy of the status code into account
+func (p RetryPolicy) forAttempt(err error, attempt int) (maxAttempts int, backoff time.Duration, retryable bool) {
+	code := grpcstatus.Code(err)
+
+	maxAttempts, initialBackoff, maxBackoff := p.MaxAttempts, p.InitialBackoff, p.MaxBackoff
+	cp, ok := p.CodePolicies[code]
+	if ok {
+		if cp.MaxAttempts > 0 {
+			maxAttempts = cp.MaxAttempts
+		}
+		if cp.InitialBackoff > 0 {
+			initialBackoff = cp.InitialBackoff
+		}
+		if cp.MaxBackoff > 0 {
+			maxBackoff = cp.MaxBackoff
+		}
+	} else if !p.isRetryable(code) {
+		return 0, 0, false
+	}
+
+	backoff = initialBackoff
+	for i := 1; i < attempt; i++ {
+		backoff = time.Duration(float64(backoff) * p.BackoffFactor)
+		if maxBackoff > 0 && backoff > maxBackoff {
+			backoff = maxBackoff
+			break
+		}
+	}
+	return maxAttempts, backoff, true
+}
+
+type hedgedResult struct {
+	reply interface{}
+	err   error
+	index int
+}
+
+func (p RetryPolicy) invokeHedged(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	results := make(chan hedgedResult, p.MaxAttempts)
+	var cancels []context.CancelFunc
+	pending := 0
+
+	// Cancel the attempts that are still in flight and wait for them to complete
+	defer func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+		for ; pending > 0; pending-- {
+			<-results
+		}
+	}()
+
+	send := func() {
+		attemptCtx, cancel := context.WithCancel(ctx)
+		attemptReply := reflect.New(reflect.TypeOf(reply).Elem()).Interface()
+		index := len(cancels)
+		cancels = append(cancels, cancel)
+		pending++
+		go func() {
+			err := invoker(attemptCtx, method, req, attemptReply, cc, opts...)
+			results <- hedgedResult{reply: attemptReply, err: err, index: index}
+		}()
+	}
+
+	send()
+	hedge := time.After(p.HedgingDelay)
+
+	var lastErr error
+	for {
+		select {
+		case result := <-results:
+			pending--
+			cancels[result.index]()
+			if result.err == nil {
+				reflect.ValueOf(reply).Elem().Set(reflect.ValueOf(result.reply).Elem())
+				return nil
+			}
+			lastErr = result.err
+			maxAttempts, _, retryable := p.forAttempt(result.err, len(cancels))
+			if !retryable {
+				return result.err
+			}
+			if len(cancels) < maxAttempts && len(cancels) < p.MaxAttempts {
+				logger.Debugf("Sending hedged GRPC call [%s] after error (attempt %d of %d): %s", method, len(cancels)+1, p.MaxAttempts, result.err)
+				send()
+				hedge = time.After(p.HedgingDelay)
+			} else if pending == 0 {
+				return lastErr
+			}
+		case <-hedge:
+			if len(cancels) < p.MaxAttempts {
+				logger.Debugf("Sending hedged GRPC call [%s] (attempt %d of %d)", method, len(cancels)+1, p.MaxAttempts)
+				send()
+				hedge = time.After(p.HedgingDelay)
+			} else {
+				hedge = nil
+			}
+		case <-ctx.Done():
+			if lastErr != nil {
+				return lastErr
+			}
+			return ctx.Err()
+		}
+	}
+}
+
+func (p RetryPolicy) appliesTo(method string) bool {
+	if len(p.Methods) == 0 {
+		return true
+	}
+	for _, m := range p.Methods {
+		// Method names may have been lower-cased by the config backend
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p RetryPolicy) isRetryable(code codes.Code) bool {
+	for _, c := range p.RetryableCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+func parseCodes(names []string) ([]codes.Code, error) {
+	var result []codes.Code
+	for _, name := range names {
+		code, err := parseCode(name)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, code)
+	}
+	return result, nil
+}
+
+// parseCode parses a GRPC status code given either by number or by name
+// (e.g. UNAVAILABLE, Unavailable, DEADLINE_EXCEEDED or DeadlineExceeded)
+func parseCode(name string) (codes.Code, error) {
+	if n, err := strconv.Atoi(name); err == nil {
+		return codes.Code(n), nil
+	}
+
+	normalized := strings.ToUpper(strings.Replace(name, "_", "", -1))
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		if strings.ToUpper(c.String()) == normalized {
+			return c, nil
+		}
+	}
+	return 0, errors.Errorf("invalid GRPC status code [%s]", name)
+}
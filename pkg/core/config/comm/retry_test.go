@@ -0,0 +1,209 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+const testMethod = "/protos.Endorser/ProcessProposal"
+
+type testReply struct {
+	Value string
+}
+
+func TestRetryPolicyFromOptions(t *testing.T) {
+	policy, err := RetryPolicyFromOptions(map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if RetryDialOptions(policy) != nil {
+		t.Fatal("expected retry to be disabled by default")
+	}
+
+	policy, err = RetryPolicyFromOptions(map[string]interface{}{
+		"retry-attempts":        3,
+		"retry-initial-backoff": "10ms",
+		"retry-codes":           []interface{}{"UNAVAILABLE", "deadline_exceeded", "8"},
+		"retry-methods":         []interface{}{"/protos.endorser/processproposal"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if policy.MaxAttempts != 3 || policy.InitialBackoff != 10*time.Millisecond {
+		t.Fatalf("unexpected policy: %+v", policy)
+	}
+	expectedCodes := []codes.Code{codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted}
+	for i, c := range expectedCodes {
+		if policy.RetryableCodes[i] != c {
+			t.Fatalf("expected code %s but got %s", c, policy.RetryableCodes[i])
+		}
+	}
+	if !policy.appliesTo(testMethod) || policy.appliesTo("/protos.Endorser/Other") {
+		t.Fatal("expected policy to apply to configured methods only")
+	}
+	if RetryDialOptions(policy) == nil {
+		t.Fatal("expected retry dial option")
+	}
+
+	if _, err = RetryPolicyFromOptions(map[string]interface{}{"retry-codes": []interface{}{"INVALID"}}); err == nil {
+		t.Fatal("expected error for invalid status code")
+	}
+}
+
+func TestRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, BackoffFactor: 2, RetryableCodes: []codes.Code{codes.Unavailable}}
+
+	var attempts int32
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			return grpcstatus.Error(codes.Unavailable, "unavailable")
+		}
+		reply.(*testReply).Value = "ok"
+		return nil
+	}
+
+	reply := &testReply{}
+	if err := policy.unaryInterceptor(context.Background(), testMethod, nil, reply, nil, invoker); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if attempts != 3 || reply.Value != "ok" {
+		t.Fatalf("expected success after 3 attempts but got %d attempts", attempts)
+	}
+
+	attempts = 0
+	failing := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		atomic.AddInt32(&attempts, 1)
+		return grpcstatus.Error(codes.PermissionDenied, "denied")
+	}
+	if err := policy.unaryInterceptor(context.Background(), testMethod, nil, reply, nil, failing); err == nil {
+		t.Fatal("expected error")
+	}
+	if attempts != 1 {
+		t.Fatalf("expected non-retryable error not to be retried but got %d attempts", attempts)
+	}
+}
+
+func TestHedging(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 2, HedgingDelay: 10 * time.Millisecond, RetryableCodes: []codes.Code{codes.Unavailable}}
+
+	var attempts int32
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			// The first request hangs until it's cancelled
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		reply.(*testReply).Value = "hedged"
+		return nil
+	}
+
+	reply := &testReply{}
+	if err := policy.unaryInterceptor(context.Background(), testMethod, nil, reply, nil, invoker); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if reply.Value != "hedged" {
+		t.Fatalf("expected response of hedged request but got [%s]", reply.Value)
+	}
+}
+
+func TestHedgingCancelsLosingAttempts(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, HedgingDelay: 5 * time.Millisecond, RetryableCodes: []codes.Code{codes.Unavailable}}
+
+	var attempts, completed int32
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		defer atomic.AddInt32(&completed, 1)
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			// The first two requests hang until they're cancelled
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		reply.(*testReply).Value = "hedged"
+		return nil
+	}
+
+	reply := &testReply{}
+	if err := policy.unaryInterceptor(context.Background(), testMethod, nil, reply, nil, invoker); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if reply.Value != "hedged" {
+		t.Fatalf("expected response of hedged request but got [%s]", reply.Value)
+	}
+	if atomic.LoadInt32(&completed) != 3 {
+		t.Fatalf("expected the losing attempts to be cancelled and drained but %d of 3 attempts completed", completed)
+	}
+}
+
+func TestRetryCodePolicies(t *testing.T) {
+	policy, err := RetryPolicyFromOptions(map[string]interface{}{
+		"retry-initial-backoff": "1ms",
+		"retry-code-policies": map[string]interface{}{
+			"resource_exhausted": map[string]interface{}{"attempts": 4, "initial-backoff": "2ms"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if RetryDialOptions(policy) == nil {
+		t.Fatal("expected retry to be enabled by the code policy")
+	}
+
+	var attempts int32
+	exhausted := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		atomic.AddInt32(&attempts, 1)
+		return grpcstatus.Error(codes.ResourceExhausted, "exhausted")
+	}
+	if err := policy.unaryInterceptor(context.Background(), testMethod, nil, &testReply{}, nil, exhausted); err == nil {
+		t.Fatal("expected error")
+	}
+	if attempts != 4 {
+		t.Fatalf("expected 4 attempts for RESOURCE_EXHAUSTED but got %d", attempts)
+	}
+
+	// UNAVAILABLE is retryable by default but the policy has a single attempt
+	attempts = 0
+	unavailable := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		atomic.AddInt32(&attempts, 1)
+		return grpcstatus.Error(codes.Unavailable, "unavailable")
+	}
+	if err := policy.unaryInterceptor(context.Background(), testMethod, nil, &testReply{}, nil, unavailable); err == nil {
+		t.Fatal("expected error")
+	}
+	if attempts != 1 {
+		t.Fatalf("expected 1 attempt for UNAVAILABLE but got %d", attempts)
+	}
+
+	if _, err = RetryPolicyFromOptions(map[string]interface{}{"retry-code-policies": map[string]interface{}{"INVALID": nil}}); err == nil {
+		t.Fatal("expected error for invalid status code")
+	}
+}
+
+func TestRetryStream(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, BackoffFactor: 2, RetryableCodes: []codes.Code{codes.Unavailable}}
+
+	var attempts int32
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			return nil, grpcstatus.Error(codes.Unavailable, "unavailable")
+		}
+		return nil, nil
+	}
+
+	if _, err := policy.streamInterceptor(context.Background(), &grpc.StreamDesc{}, nil, "/orderer.AtomicBroadcast/Broadcast", streamer); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected stream to be opened after 3 attempts but got %d attempts", attempts)
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"io"
 	"strings"
 
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cast"
 	"github.com/spf13/viper"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
@@ -19,12 +21,15 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 )
 
+var logger = logging.NewLogger("fabsdk/core")
+
 var logModules = [...]string{"fabsdk", "fabsdk/client", "fabsdk/core", "fabsdk/fab", "fabsdk/common",
 	"fabsdk/msp", "fabsdk/util", "fabsdk/context"}
 
 type options struct {
 	envPrefix    string
 	templatePath string
+	watchLogging bool
 }
 
 const (
@@ -45,28 +50,40 @@ func FromReader(in io.Reader, configType string, opts ...Option) core.ConfigProv
 // FromFile reads from named config file
 func FromFile(name string, opts ...Option) core.ConfigProvider {
 	return func() (core.ConfigBackend, error) {
-		backend, err := newBackend(opts...)
+		backend, err := newFileBackend(name, opts...)
 		if err != nil {
 			return nil, err
 		}
 
-		if name == "" {
-			return nil, errors.New("filename is required")
+		setLogLevel(backend)
+
+		if backend.opts.watchLogging {
+			watchLogLevels(name, opts...)
 		}
 
-		// create new viper
-		backend.configViper.SetConfigFile(name)
+		return backend, nil
+	}
+}
 
-		// If a config file is found, read it in.
-		err = backend.configViper.MergeInConfig()
-		if err != nil {
-			return nil, errors.Wrap(err, "loading config file failed")
-		}
+func newFileBackend(name string, opts ...Option) (*defConfigBackend, error) {
+	backend, err := newBackend(opts...)
+	if err != nil {
+		return nil, err
+	}
 
-		setLogLevel(backend)
+	if name == "" {
+		return nil, errors.New("filename is required")
+	}
 
-		return backend, nil
+	// create new viper
+	backend.configViper.SetConfigFile(name)
+
+	// If a config file is found, read it in.
+	err = backend.configViper.MergeInConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "loading config file failed")
 	}
+	return backend, nil
 }
 
 // FromRaw will initialize the configs from a byte array
@@ -108,6 +125,16 @@ func WithEnvPrefix(prefix string) Option {
 	}
 }
 
+// WithLoggingWatch watches the config file loaded with FromFile and sets the log levels
+// of the modules again whenever the file changes, which allows the log levels to be
+// changed without restarting the application. Other changes of the config are ignored.
+func WithLoggingWatch() Option {
+	return func(opts *options) error {
+		opts.watchLogging = true
+		return nil
+	}
+}
+
 func newBackend(opts ...Option) (*defConfigBackend, error) {
 	o := options{
 		envPrefix: cmdRoot,
@@ -147,18 +174,71 @@ func newViper(cmdRootPrefix string) *viper.Viper {
 
 // setLogLevel will set the log level of the client
 func setLogLevel(backend core.ConfigBackend) {
+	if err := applyLogLevels(backend); err != nil {
+		panic(err)
+	}
+}
+
+// applyLogLevels sets the log level of the SDK modules to client.logging.level, unless
+// another level is set for the module in client.logging.modules, e.g.
+//  client:
+//    logging:
+//      level: info
+//      modules:
+//        fabsdk/fab: debug
+func applyLogLevels(backend core.ConfigBackend) error {
 	loggingLevelString, _ := backend.Lookup("client.logging.level")
 	logLevel := logging.INFO
 	if loggingLevelString != nil {
 		var err error
 		logLevel, err = logging.LogLevel(loggingLevelString.(string))
 		if err != nil {
-			panic(err)
+			return err
 		}
 	}
 
-	// TODO: allow separate settings for each
+	levels := make(map[string]logging.Level)
 	for _, logModule := range logModules {
-		logging.SetLevel(logModule, logLevel)
+		levels[logModule] = logLevel
+	}
+
+	moduleLevels, ok := backend.Lookup("client.logging.modules")
+	if ok {
+		moduleLevelStrings, ok := moduleLevels.(map[string]interface{})
+		if !ok {
+			return errors.Errorf("invalid client.logging.modules: %v", moduleLevels)
+		}
+		for logModule, levelString := range moduleLevelStrings {
+			level, err := logging.LogLevel(cast.ToString(levelString))
+			if err != nil {
+				return errors.WithMessage(err, "invalid log level for module "+logModule)
+			}
+			levels[logModule] = level
+		}
 	}
+
+	for logModule, level := range levels {
+		logging.SetLevel(logModule, level)
+	}
+	return nil
+}
+
+// watchLogLevels watches the config file with a viper of its own and, whenever the file changes, sets the log
+// levels from a new backend loaded from the file (and the template config). The backend returned by FromFile is
+// never modified, so that it may be read while the file is reloaded.
+func watchLogLevels(name string, opts ...Option) {
+	watcher := viper.New()
+	watcher.SetConfigFile(name)
+	watcher.OnConfigChange(func(e fsnotify.Event) {
+		backend, err := newFileBackend(name, opts...)
+		if err == nil {
+			err = applyLogLevels(backend)
+		}
+		if err != nil {
+			logger.Warnf("Failed to set log levels from changed config file %s: %s", e.Name, err)
+			return
+		}
+		logger.Infof("Log levels set from changed config file %s", e.Name)
+	})
+	watcher.WatchConfig()
 }
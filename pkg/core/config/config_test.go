@@ -9,8 +9,11 @@ package config
 import (
 	"bytes"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
@@ -52,6 +55,72 @@ func TestFromReaderSuccess(t *testing.T) {
 	}
 }
 
+func TestModuleLogLevels(t *testing.T) {
+	defer logging.SetLevel("fabsdk/fab", logging.INFO)
+	defer logging.SetLevel("fabsdk/msp", logging.INFO)
+
+	_, err := FromRaw([]byte(`
+client:
+  logging:
+    level: warning
+    modules:
+      fabsdk/fab: debug
+`), configType)()
+	assert.NoError(t, err)
+	assert.Equal(t, logging.DEBUG, logging.GetLevel("fabsdk/fab"))
+	assert.Equal(t, logging.WARNING, logging.GetLevel("fabsdk/msp"))
+	assert.Equal(t, logging.DEBUG, logging.GetLevels()["fabsdk/fab"])
+
+	assert.Panics(t, func() {
+		FromRaw([]byte(`
+client:
+  logging:
+    modules:
+      fabsdk/fab: invalid
+`), configType)()
+	}, "expecting panic for invalid module log level")
+}
+
+func TestLoggingWatch(t *testing.T) {
+	defer logging.SetLevel("fabsdk/fab", logging.INFO)
+
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	configFile := filepath.Join(dir, "config.yaml")
+	writeConfig := func(level string) {
+		config := "client:\n  organization: " + level + "\n  logging:\n    level: info\n    modules:\n      fabsdk/fab: " + level + "\n"
+		if err := ioutil.WriteFile(configFile, []byte(config), 0600); err != nil {
+			t.Fatalf("Failed to write config file: %s", err)
+		}
+	}
+
+	writeConfig("warning")
+	backend, err := FromFile(configFile, WithLoggingWatch())()
+	if err != nil {
+		t.Fatalf("Failed to initialize config. Error: %s", err)
+	}
+	assert.Equal(t, logging.WARNING, logging.GetLevel("fabsdk/fab"))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for logging.GetLevel("fabsdk/fab") != logging.DEBUG {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expecting log level to be set from changed config file")
+		}
+		// The file is written again until the change is seen, since the file is watched asynchronously
+		writeConfig("debug")
+		// The backend may be read while the config file is reloaded
+		backend.Lookup("client.organization")
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	organization, _ := backend.Lookup("client.organization")
+	assert.Equal(t, "warning", organization, "expecting changes other than the log levels to be ignored")
+}
+
 func loadConfigBytesFromFile(t *testing.T, filePath string) ([]byte, error) {
 	// read test config file into bytes array
 	f, err := os.Open(filePath)
@@ -0,0 +1,120 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endpoint
+
+import (
+	"net"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ProxyConfig contains the proxy settings for outgoing GRPC and HTTP connections
+type ProxyConfig struct {
+	// URL of the proxy. The supported schemes are http and https (HTTP CONNECT) and socks5.
+	// If not set then the HTTPS_PROXY, HTTP_PROXY and ALL_PROXY environment variables are used.
+	URL string
+	// NoProxy is a comma-separated list of host names, domain suffixes, IP addresses or CIDRs
+	// which are accessed directly. If not set then the NO_PROXY environment variable is used.
+	NoProxy string
+}
+
+// ResolveProxy returns the URL of the proxy to be used for connecting to the given target
+// address (host:port). Nil is returned if the target should be accessed directly.
+func (cfg ProxyConfig) ResolveProxy(target string) (*url.URL, error) {
+	noProxy := cfg.NoProxy
+	if noProxy == "" {
+		noProxy = getFirstEnv("NO_PROXY", "no_proxy")
+	}
+	if bypassProxy(hostOf(ToAddress(target)), noProxy) {
+		return nil, nil
+	}
+
+	proxy := cfg.URL
+	if proxy == "" {
+		proxy = getFirstEnv("HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy", "ALL_PROXY", "all_proxy")
+	}
+	if proxy == "" {
+		return nil, nil
+	}
+
+	return parseProxyURL(proxy)
+}
+
+func parseProxyURL(proxy string) (*url.URL, error) {
+	if !strings.Contains(proxy, "://") {
+		proxy = "http://" + proxy
+	}
+
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid proxy URL [%s]", proxy)
+	}
+
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, errors.Errorf("unsupported proxy scheme [%s]", proxyURL.Scheme)
+	}
+
+	if proxyURL.Host == "" {
+		return nil, errors.Errorf("proxy host not specified in [%s]", proxy)
+	}
+
+	return proxyURL, nil
+}
+
+func bypassProxy(host string, noProxy string) bool {
+	if host == "" || noProxy == "" {
+		return false
+	}
+
+	host = strings.ToLower(host)
+	ip := net.ParseIP(host)
+
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+		if ip != nil {
+			if _, cidr, err := net.ParseCIDR(entry); err == nil && cidr.Contains(ip) {
+				return true
+			}
+		}
+		if h, _, err := net.SplitHostPort(entry); err == nil {
+			entry = h
+		}
+		if host == entry || strings.HasSuffix(host, "."+strings.TrimPrefix(entry, ".")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hostOf(address string) string {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+	return host
+}
+
+func getFirstEnv(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,121 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endpoint
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	srvScheme       = "srv://"
+	srvSchemeSuffix = "+srv://"
+)
+
+// lookupSRV is overridden by unit tests
+var lookupSRV = func(name string) ([]*net.SRV, error) {
+	_, addrs, err := net.LookupSRV("", "", name)
+	return addrs, err
+}
+
+// IsSRV returns true if the given URL refers to a DNS SRV name rather than to a single
+// host, for example grpcs+srv://_peer._tcp.peers.org1.example.com. The URL schemes
+// grpcs+srv and grpc+srv select TLS and plain connections respectively, whereas srv
+// falls back to the 'allow-insecure' setting as with URLs that have no protocol.
+func IsSRV(url string) bool {
+	url = strings.ToLower(url)
+	return strings.HasPrefix(url, srvScheme) || strings.Contains(url, srvSchemeSuffix)
+}
+
+// SRVResolver resolves DNS SRV names into the set of endpoint URLs of the SRV targets.
+// Resolved records are cached for the given refresh interval after which the name is
+// resolved again on the next access. If re-resolution fails then the stale records are
+// used until the DNS server is reachable again.
+//
+// This component has been designed to be safe for concurrency.
+type SRVResolver struct {
+	refreshInterval time.Duration
+	lock            sync.Mutex
+	entries         map[string]*srvEntry
+}
+
+type srvEntry struct {
+	urls    []string
+	expires time.Time
+}
+
+// NewSRVResolver returns a new SRV resolver which caches resolved records for the given interval
+func NewSRVResolver(refreshInterval time.Duration) *SRVResolver {
+	return &SRVResolver{
+		refreshInterval: refreshInterval,
+		entries:         make(map[string]*srvEntry),
+	}
+}
+
+// Resolve returns the endpoint URLs of the targets of the given SRV URL ordered by
+// priority (targets of equal priority are randomized by weight). The scheme of the
+// returned URLs is the scheme of the SRV URL without the '+srv' suffix.
+func (r *SRVResolver) Resolve(url string) ([]string, error) {
+	if !IsSRV(url) {
+		return nil, errors.Errorf("not a DNS SRV URL [%s]", url)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	entry, ok := r.entries[url]
+	if ok && time.Now().Before(entry.expires) {
+		return entry.urls, nil
+	}
+
+	urls, err := resolveSRV(url)
+	if err != nil {
+		if ok {
+			// Keep using the stale records
+			entry.expires = time.Now().Add(r.refreshInterval)
+			return entry.urls, nil
+		}
+		return nil, err
+	}
+
+	r.entries[url] = &srvEntry{urls: urls, expires: time.Now().Add(r.refreshInterval)}
+	return urls, nil
+}
+
+func resolveSRV(url string) ([]string, error) {
+	scheme, name := splitSRV(url)
+
+	addrs, err := lookupSRV(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "DNS SRV lookup failed for [%s]", name)
+	}
+	if len(addrs) == 0 {
+		return nil, errors.Errorf("no DNS SRV records found for [%s]", name)
+	}
+
+	urls := make([]string, len(addrs))
+	for i, addr := range addrs {
+		urls[i] = scheme + net.JoinHostPort(strings.TrimSuffix(addr.Target, "."), strconv.Itoa(int(addr.Port)))
+	}
+	return urls, nil
+}
+
+// splitSRV returns the scheme of the target URLs (e.g. "grpcs://" or "" if not specified)
+// and the DNS name to be looked up
+func splitSRV(url string) (string, string) {
+	lower := strings.ToLower(url)
+	if strings.HasPrefix(lower, srvScheme) {
+		return "", url[len(srvScheme):]
+	}
+	i := strings.Index(lower, srvSchemeSuffix)
+	return lower[:i] + "://", url[i+len(srvSchemeSuffix):]
+}
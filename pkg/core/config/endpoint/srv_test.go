@@ -0,0 +1,82 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endpoint
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestIsSRV(t *testing.T) {
+	if !IsSRV("grpcs+srv://_peer._tcp.org1.example.com") {
+		t.Fatal("expected grpcs+srv URL to be a DNS SRV URL")
+	}
+	if !IsSRV("srv://_peer._tcp.org1.example.com") {
+		t.Fatal("expected srv URL to be a DNS SRV URL")
+	}
+	if IsSRV("grpcs://peer0.org1.example.com:7051") {
+		t.Fatal("expected grpcs URL not to be a DNS SRV URL")
+	}
+}
+
+func TestSRVResolver(t *testing.T) {
+	origLookup := lookupSRV
+	defer func() { lookupSRV = origLookup }()
+
+	lookups := 0
+	fail := false
+	lookupSRV = func(name string) ([]*net.SRV, error) {
+		lookups++
+		if fail {
+			return nil, errors.New("DNS unavailable")
+		}
+		if name != "_peer._tcp.org1.example.com" {
+			t.Fatalf("unexpected SRV name [%s]", name)
+		}
+		return []*net.SRV{
+			{Target: "peer0.org1.example.com.", Port: 7051},
+			{Target: "peer1.org1.example.com.", Port: 8051},
+		}, nil
+	}
+
+	resolver := NewSRVResolver(50 * time.Millisecond)
+	expected := []string{"grpcs://peer0.org1.example.com:7051", "grpcs://peer1.org1.example.com:8051"}
+
+	urls, err := resolver.Resolve("grpcs+srv://_peer._tcp.org1.example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(urls, expected) {
+		t.Fatalf("unexpected URLs: %v", urls)
+	}
+
+	if _, err = resolver.Resolve("grpcs+srv://_peer._tcp.org1.example.com"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if lookups != 1 {
+		t.Fatalf("expected cached records to be used but got %d lookups", lookups)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	fail = true
+
+	urls, err = resolver.Resolve("grpcs+srv://_peer._tcp.org1.example.com")
+	if err != nil {
+		t.Fatalf("expected stale records to be used but got error: %s", err)
+	}
+	if lookups != 2 || !reflect.DeepEqual(urls, expected) {
+		t.Fatalf("expected stale records to be returned after failed re-resolution")
+	}
+
+	if _, err = resolver.Resolve("grpcs+srv://_peer._tcp.org2.example.com"); err == nil {
+		t.Fatal("expected error for unresolvable SRV name")
+	}
+}
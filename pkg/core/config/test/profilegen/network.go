@@ -0,0 +1,154 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package profilegen
+
+import (
+	"path/filepath"
+)
+
+// section is a section of the connection profile
+type section map[string]interface{}
+
+// networkConfig returns the connection profile, in the layout of config_test.yaml
+func (p *Profile) networkConfig() section {
+	return section{
+		"version":                "1.0.0",
+		"client":                 p.clientConfig(),
+		"channels":               p.channelsConfig(),
+		"organizations":          p.orgsConfig(),
+		"orderers":               p.orderersConfig(),
+		"peers":                  p.peersConfig(),
+		"certificateAuthorities": p.caConfig(),
+	}
+}
+
+func (p *Profile) clientConfig() section {
+	return section{
+		"organization": p.clientOrg,
+		"logging":      section{"level": "info"},
+		"credentialStore": section{
+			"path":        filepath.Join(p.storePath, "state-store"),
+			"cryptoStore": section{"path": filepath.Join(p.storePath, "msp")},
+		},
+		"BCCSP": section{
+			"security": section{
+				"enabled":       true,
+				"default":       section{"provider": "SW"},
+				"hashAlgorithm": "SHA2",
+				"softVerify":    true,
+				"level":         256,
+			},
+		},
+		"tlsCerts": section{
+			"systemCertPool": false,
+			"client": section{
+				"key":  section{"pem": string(p.clientTLSKey)},
+				"cert": section{"pem": string(p.clientTLSCert)},
+			},
+		},
+	}
+}
+
+func (p *Profile) channelsConfig() section {
+	var orderers []string
+	for _, o := range p.orderers {
+		orderers = append(orderers, o.name)
+	}
+
+	channels := section{}
+	for _, ch := range p.channels {
+		peers := section{}
+		for _, peer := range ch.peers {
+			peers[peer] = section{
+				"endorsingPeer":  true,
+				"chaincodeQuery": true,
+				"ledgerQuery":    true,
+				"eventSource":    true,
+			}
+		}
+		channels[ch.name] = section{"orderers": orderers, "peers": peers}
+	}
+	return channels
+}
+
+func (p *Profile) orgsConfig() section {
+	orgs := section{}
+	for _, o := range p.orgs {
+		users := section{}
+		for _, u := range o.users {
+			users[u.name] = section{
+				"key":  section{"pem": string(u.key)},
+				"cert": section{"pem": string(u.cert)},
+			}
+		}
+		var peers, cas []string
+		for _, peer := range o.peers {
+			peers = append(peers, peer.name)
+		}
+		for _, ca := range o.cas {
+			cas = append(cas, ca.name)
+		}
+		orgs[o.name] = section{
+			"mspid":                  o.mspID,
+			"users":                  users,
+			"peers":                  peers,
+			"certificateAuthorities": cas,
+		}
+	}
+	return orgs
+}
+
+func (p *Profile) orderersConfig() section {
+	orderers := section{}
+	for _, o := range p.orderers {
+		orderers[o.name] = p.grpcNodeConfig(o)
+	}
+	return orderers
+}
+
+func (p *Profile) peersConfig() section {
+	peers := section{}
+	for _, o := range p.orgs {
+		for _, peer := range o.peers {
+			peers[peer.name] = p.grpcNodeConfig(peer)
+		}
+	}
+	return peers
+}
+
+func (p *Profile) grpcNodeConfig(n *node) section {
+	return section{
+		"url": n.url,
+		"grpcOptions": section{
+			"ssl-target-name-override": n.name,
+			"fail-fast":                false,
+			"allow-insecure":           false,
+		},
+		"tlsCACerts": section{"pem": string(p.tlsCA.certPEM)},
+	}
+}
+
+func (p *Profile) caConfig() section {
+	cas := section{}
+	for _, o := range p.orgs {
+		for _, ca := range o.cas {
+			cas[ca.name] = section{
+				"url": "https://" + ca.url,
+				"tlsCACerts": section{
+					"pem": []string{string(p.tlsCA.certPEM)},
+					"client": section{
+						"key":  section{"pem": string(p.clientTLSKey)},
+						"cert": section{"pem": string(p.clientTLSCert)},
+					},
+				},
+				"registrar": section{"enrollId": "admin", "enrollSecret": "adminpw"},
+				"caName":    ca.name,
+			}
+		}
+	}
+	return cas
+}
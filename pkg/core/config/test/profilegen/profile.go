@@ -0,0 +1,331 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package profilegen builds complete connection profiles in memory for tests, so that test setups
+// don't need to copy config_test.yaml variants across packages.
+//
+// The certificates of the profile are issued by test CAs which are generated with the profile: the
+// TLS certificates of the peers, orderers, CAs and the client are issued by a TLS CA, and the
+// enrollment certificates of the users are issued by the CA of their organization. All of the
+// certificates and keys are embedded in the profile, so no files are needed.
+//
+// Basic flow:
+//
+//  profile, err := profilegen.New(
+//      profilegen.WithOrg("org1", "Org1MSP", profilegen.WithPeer("peer0.org1.example.com", "localhost:7051"), profilegen.WithUser("User1")),
+//      profilegen.WithOrderer("orderer.example.com", "localhost:7050"),
+//      profilegen.WithChannel("mychannel", "peer0.org1.example.com"),
+//  )
+//  sdk, err := fabsdk.New(profile.ConfigProvider())
+//
+//  // Serve TLS for the peer with the certificate issued for it
+//  cert, key := profile.TLSCert("peer0.org1.example.com")
+//
+// If no organization is given, the profile has the organization org1 (Org1MSP) with the peer
+// peer0.org1.example.com, the CA ca.org1.example.com and the users Admin and User1. If no orderer
+// is given, the profile has the orderer orderer.example.com. If no channel is given, the profile
+// has the channel mychannel which is joined by all of the peers.
+package profilegen
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+)
+
+const configType = "json"
+
+// Profile is a connection profile with its certificates and keys
+type Profile struct {
+	clientOrg string
+	storePath string
+	orgs      []*org
+	orderers  []*node
+	channels  []*channel
+
+	tlsCA         *testCA
+	clientTLSCert []byte
+	clientTLSKey  []byte
+	raw           []byte
+}
+
+type org struct {
+	name  string
+	mspID string
+	peers []*node
+	cas   []*node
+	users []*user
+	ca    *testCA
+}
+
+type node struct {
+	name    string
+	url     string
+	tlsCert []byte
+	tlsKey  []byte
+}
+
+type user struct {
+	name string
+	cert []byte
+	key  []byte
+}
+
+type channel struct {
+	name  string
+	peers []string
+}
+
+// Option is an option of a profile
+type Option func(*Profile)
+
+// OrgOption is an option of an organization of a profile
+type OrgOption func(*org)
+
+// WithOrg adds an organization to the profile
+func WithOrg(name, mspID string, opts ...OrgOption) Option {
+	return func(p *Profile) {
+		o := &org{name: name, mspID: mspID}
+		for _, opt := range opts {
+			opt(o)
+		}
+		p.orgs = append(p.orgs, o)
+	}
+}
+
+// WithPeer adds a peer with the given name and URL (host:port) to the organization
+func WithPeer(name, url string) OrgOption {
+	return func(o *org) {
+		o.peers = append(o.peers, &node{name: name, url: url})
+	}
+}
+
+// WithCA adds a certificate authority with the given name and URL (host:port) to the organization
+func WithCA(name, url string) OrgOption {
+	return func(o *org) {
+		o.cas = append(o.cas, &node{name: name, url: url})
+	}
+}
+
+// WithUser adds a user with an enrollment certificate issued by the organization's CA to the organization
+func WithUser(name string) OrgOption {
+	return func(o *org) {
+		o.users = append(o.users, &user{name: name})
+	}
+}
+
+// WithOrderer adds an orderer with the given name and URL (host:port) to the profile
+func WithOrderer(name, url string) Option {
+	return func(p *Profile) {
+		p.orderers = append(p.orderers, &node{name: name, url: url})
+	}
+}
+
+// WithChannel adds a channel which is served by all of the orderers and joined by the given peers
+func WithChannel(name string, peers ...string) Option {
+	return func(p *Profile) {
+		p.channels = append(p.channels, &channel{name: name, peers: peers})
+	}
+}
+
+// WithClientOrg sets the organization of the client. The default is the first organization.
+func WithClientOrg(name string) Option {
+	return func(p *Profile) {
+		p.clientOrg = name
+	}
+}
+
+// WithStorePath sets the directory of the credential and crypto stores of the client.
+// The default is a directory in the temp directory of the OS.
+func WithStorePath(path string) Option {
+	return func(p *Profile) {
+		p.storePath = path
+	}
+}
+
+// New generates a connection profile with the given options
+func New(opts ...Option) (*Profile, error) {
+	p := &Profile{storePath: filepath.Join(os.TempDir(), "profilegen")}
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.setDefaults()
+
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	if err := p.generateCerts(); err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(p.networkConfig())
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal connection profile failed")
+	}
+	p.raw = raw
+	return p, nil
+}
+
+// Bytes returns the connection profile in JSON
+func (p *Profile) Bytes() []byte {
+	return p.raw
+}
+
+// ConfigProvider returns a config provider which loads the connection profile
+func (p *Profile) ConfigProvider() core.ConfigProvider {
+	return config.FromRaw(p.raw, configType)
+}
+
+// TLSCACert returns the PEM encoded certificate of the CA which issued the TLS certificates
+func (p *Profile) TLSCACert() []byte {
+	return p.tlsCA.certPEM
+}
+
+// TLSCert returns the PEM encoded TLS certificate and private key of the peer, orderer or CA with
+// the given name, or nil if there is no such node
+func (p *Profile) TLSCert(name string) (cert []byte, key []byte) {
+	for _, n := range p.nodes() {
+		if n.name == name {
+			return n.tlsCert, n.tlsKey
+		}
+	}
+	return nil, nil
+}
+
+// ClientTLSCert returns the PEM encoded client TLS certificate and private key of the profile
+func (p *Profile) ClientTLSCert() (cert []byte, key []byte) {
+	return p.clientTLSCert, p.clientTLSKey
+}
+
+// CACert returns the PEM encoded certificate of the CA of the given organization, or nil if there is no such organization
+func (p *Profile) CACert(orgName string) []byte {
+	if o := p.org(orgName); o != nil {
+		return o.ca.certPEM
+	}
+	return nil
+}
+
+// UserCert returns the PEM encoded enrollment certificate and private key of the given user of the given
+// organization, or nil if there is no such user
+func (p *Profile) UserCert(orgName, userName string) (cert []byte, key []byte) {
+	o := p.org(orgName)
+	if o == nil {
+		return nil, nil
+	}
+	for _, u := range o.users {
+		if u.name == userName {
+			return u.cert, u.key
+		}
+	}
+	return nil, nil
+}
+
+func (p *Profile) setDefaults() {
+	if len(p.orgs) == 0 {
+		WithOrg("org1", "Org1MSP",
+			WithPeer("peer0.org1.example.com", "peer0.org1.example.com:7051"),
+			WithCA("ca.org1.example.com", "ca.org1.example.com:7054"),
+			WithUser("Admin"),
+			WithUser("User1"),
+		)(p)
+	}
+	if len(p.orderers) == 0 {
+		WithOrderer("orderer.example.com", "orderer.example.com:7050")(p)
+	}
+	if len(p.channels) == 0 {
+		var peers []string
+		for _, o := range p.orgs {
+			for _, peer := range o.peers {
+				peers = append(peers, peer.name)
+			}
+		}
+		WithChannel("mychannel", peers...)(p)
+	}
+	if p.clientOrg == "" {
+		p.clientOrg = p.orgs[0].name
+	}
+}
+
+func (p *Profile) validate() error {
+	if p.org(p.clientOrg) == nil {
+		return errors.Errorf("client organization [%s] not found", p.clientOrg)
+	}
+	peers := make(map[string]bool)
+	for _, o := range p.orgs {
+		for _, peer := range o.peers {
+			peers[peer.name] = true
+		}
+	}
+	for _, ch := range p.channels {
+		for _, peer := range ch.peers {
+			if !peers[peer] {
+				return errors.Errorf("peer [%s] of channel [%s] not found", peer, ch.name)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *Profile) generateCerts() error {
+	var err error
+	if p.tlsCA, err = newTestCA("tlsca"); err != nil {
+		return errors.WithMessage(err, "generate TLS CA failed")
+	}
+	if p.clientTLSCert, p.clientTLSKey, err = p.tlsCA.issue("client", nil, x509.ExtKeyUsageClientAuth); err != nil {
+		return errors.WithMessage(err, "generate client TLS certificate failed")
+	}
+	for _, n := range p.nodes() {
+		if n.tlsCert, n.tlsKey, err = p.tlsCA.issue(n.name, hosts(n), x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth); err != nil {
+			return errors.WithMessage(err, "generate TLS certificate failed")
+		}
+	}
+	for _, o := range p.orgs {
+		if o.ca, err = newTestCA("ca." + o.name); err != nil {
+			return errors.WithMessage(err, "generate organization CA failed")
+		}
+		for _, u := range o.users {
+			if u.cert, u.key, err = o.ca.issue(u.name+"@"+o.name, nil); err != nil {
+				return errors.WithMessage(err, "generate user certificate failed")
+			}
+		}
+	}
+	return nil
+}
+
+func (p *Profile) org(name string) *org {
+	for _, o := range p.orgs {
+		if o.name == name {
+			return o
+		}
+	}
+	return nil
+}
+
+// nodes returns the peers, orderers and CAs of the profile
+func (p *Profile) nodes() []*node {
+	nodes := append([]*node{}, p.orderers...)
+	for _, o := range p.orgs {
+		nodes = append(nodes, o.peers...)
+		nodes = append(nodes, o.cas...)
+	}
+	return nodes
+}
+
+// hosts returns the host names for which the TLS certificate of the node is issued
+func hosts(n *node) []string {
+	hosts := []string{n.name, "localhost", "127.0.0.1"}
+	if host, _, err := net.SplitHostPort(n.url); err == nil && host != n.name {
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
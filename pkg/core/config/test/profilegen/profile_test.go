@@ -0,0 +1,111 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package profilegen
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	fabImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab"
+	mspImpl "github.com/hyperledger/fabric-sdk-go/pkg/msp"
+)
+
+func TestDefaultProfile(t *testing.T) {
+	profile, err := New()
+	require.NoError(t, err)
+
+	backend, err := profile.ConfigProvider()()
+	require.NoError(t, err)
+	endpointConfig, err := fabImpl.ConfigFromBackend(backend)
+	require.NoError(t, err)
+
+	peers, err := endpointConfig.ChannelPeers("mychannel")
+	require.NoError(t, err)
+	require.Len(t, peers, 1)
+	assert.Equal(t, "peer0.org1.example.com:7051", peers[0].URL)
+	assert.Equal(t, "Org1MSP", peers[0].MSPID)
+
+	ordererConfig, err := endpointConfig.OrdererConfig("orderer.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "orderer.example.com:7050", ordererConfig.URL)
+
+	clientCerts, err := endpointConfig.TLSClientCerts()
+	require.NoError(t, err)
+	assert.Len(t, clientCerts, 1)
+
+	identityConfig, err := mspImpl.ConfigFromBackend(backend)
+	require.NoError(t, err)
+	caConfig, err := identityConfig.CAConfig("org1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://ca.org1.example.com:7054", caConfig.URL)
+
+	cert, key := profile.UserCert("org1", "User1")
+	assert.NotEmpty(t, cert)
+	assert.NotEmpty(t, key)
+	verify(t, profile.CACert("org1"), cert, "")
+}
+
+func TestCustomProfile(t *testing.T) {
+	profile, err := New(
+		WithOrg("org1", "Org1MSP", WithPeer("peer0.org1.example.com", "localhost:7051"), WithUser("User1")),
+		WithOrg("org2", "Org2MSP", WithPeer("peer0.org2.example.com", "localhost:8051")),
+		WithOrderer("orderer.example.com", "localhost:7050"),
+		WithChannel("channel1", "peer0.org1.example.com", "peer0.org2.example.com"),
+		WithChannel("channel2", "peer0.org2.example.com"),
+		WithClientOrg("org2"),
+	)
+	require.NoError(t, err)
+
+	backend, err := profile.ConfigProvider()()
+	require.NoError(t, err)
+	endpointConfig, err := fabImpl.ConfigFromBackend(backend)
+	require.NoError(t, err)
+
+	peers, err := endpointConfig.ChannelPeers("channel1")
+	require.NoError(t, err)
+	assert.Len(t, peers, 2)
+	peers, err = endpointConfig.ChannelPeers("channel2")
+	require.NoError(t, err)
+	require.Len(t, peers, 1)
+	assert.Equal(t, "Org2MSP", peers[0].MSPID)
+
+	// The TLS certificates are valid for the names and the URLs of the nodes
+	cert, key := profile.TLSCert("peer0.org2.example.com")
+	_, err = tls.X509KeyPair(cert, key)
+	require.NoError(t, err)
+	verify(t, profile.TLSCACert(), cert, "peer0.org2.example.com")
+	verify(t, profile.TLSCACert(), cert, "localhost")
+
+	cert, _ = profile.TLSCert("unknown")
+	assert.Nil(t, cert)
+}
+
+func TestInvalidProfile(t *testing.T) {
+	_, err := New(WithClientOrg("unknown"))
+	assert.Error(t, err)
+
+	_, err = New(WithChannel("mychannel", "unknown"))
+	assert.Error(t, err)
+}
+
+func verify(t *testing.T, caCertPEM, certPEM []byte, host string) {
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(caCertPEM))
+
+	block, _ := pem.Decode(certPEM)
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+
+	_, err = cert.Verify(x509.VerifyOptions{Roots: pool, DNSName: host, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}})
+	assert.NoError(t, err)
+}
@@ -34,6 +34,16 @@ func (l *ModuleLevels) SetLevel(module string, level api.Level) {
 	l.levels[module] = level
 }
 
+// Levels returns a copy of the log levels which are set, by module. The default
+// log level is returned for the empty module, if it's set.
+func (l *ModuleLevels) Levels() map[string]api.Level {
+	levels := make(map[string]api.Level, len(l.levels))
+	for module, level := range l.levels {
+		levels[module] = level
+	}
+	return levels
+}
+
 // IsEnabledFor will return true if logging is enabled for the given module.
 func (l *ModuleLevels) IsEnabledFor(module string, level api.Level) bool {
 	return level <= l.GetLevel(module)
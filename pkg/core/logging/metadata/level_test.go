@@ -45,6 +45,10 @@ func TestLogLevels(t *testing.T) {
 	assert.True(t, mlevel.IsEnabledFor("module-xyz-warning", api.ERROR))
 	assert.True(t, mlevel.IsEnabledFor("module-xyz-warning", api.WARNING))
 
+	//Run levels check
+	assert.Equal(t, map[string]api.Level{"module-xyz-info": api.INFO, "module-xyz-debug": api.DEBUG,
+		"module-xyz-error": api.ERROR, "module-xyz-warning": api.WARNING}, mlevel.Levels())
+
 	//Run default log level check --> which is info currently
 	assert.True(t, mlevel.IsEnabledFor("module-xyz-random-module", api.INFO))
 	assert.False(t, mlevel.IsEnabledFor("module-xyz-random-module", api.DEBUG))
@@ -92,6 +92,13 @@ func GetLevel(module string) api.Level {
 	return moduleLevels.GetLevel(module)
 }
 
+//GetLevels - getting log levels of the modules for which a level is set
+func GetLevels() map[string]api.Level {
+	rwmutex.RLock()
+	defer rwmutex.RUnlock()
+	return moduleLevels.Levels()
+}
+
 //IsEnabledFor - Check if given log level is enabled for given module
 func IsEnabledFor(module string, level api.Level) bool {
 	rwmutex.RLock()
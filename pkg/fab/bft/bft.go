@@ -0,0 +1,43 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package bft provides support for BFT (e.g. SmartBFT) ordering services, which
+// tolerate up to f arbitrarily faulty orderers out of n = 3f+1 consenters.
+package bft
+
+import (
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+var logger = logging.NewLogger("fabsdk/fab")
+
+// IsBFT returns true if the channel is ordered by a BFT ordering service
+func IsBFT(cfg fab.ChannelCfg) bool {
+	consensusType := cfg.ConsensusType()
+	return strings.EqualFold(consensusType, fab.BFTConsensusType) || strings.EqualFold(consensusType, fab.SmartBFTConsensusType)
+}
+
+// MaxFaulty returns the maximum number of faulty consenters that a BFT ordering
+// service with the given number of consenters can tolerate
+func MaxFaulty(consenters int) int {
+	if consenters <= 0 {
+		return 0
+	}
+	return (consenters - 1) / 3
+}
+
+// Quorum returns the number of consenters which must agree on a decision (e.g. sign a block)
+// in a BFT ordering service with the given number of consenters, i.e. ceil((n+f+1)/2)
+func Quorum(consenters int) int {
+	if consenters <= 0 {
+		return 0
+	}
+	f := MaxFaulty(consenters)
+	return (consenters + f + 2) / 2
+}
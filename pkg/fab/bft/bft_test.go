@@ -0,0 +1,50 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+)
+
+func TestIsBFT(t *testing.T) {
+	cfg := mocks.NewMockChannelCfg("mychannel")
+	assert.False(t, IsBFT(cfg))
+
+	cfg.MockConsensusType = "etcdraft"
+	assert.False(t, IsBFT(cfg))
+
+	cfg.MockConsensusType = "BFT"
+	assert.True(t, IsBFT(cfg))
+
+	cfg.MockConsensusType = "SmartBFT"
+	assert.True(t, IsBFT(cfg))
+}
+
+func TestQuorum(t *testing.T) {
+	tests := []struct {
+		consenters int
+		maxFaulty  int
+		quorum     int
+	}{
+		{0, 0, 0},
+		{1, 0, 1},
+		{3, 0, 2},
+		{4, 1, 3},
+		{5, 1, 4},
+		{7, 2, 5},
+		{10, 3, 7},
+	}
+
+	for _, test := range tests {
+		assert.Equalf(t, test.maxFaulty, MaxFaulty(test.consenters), "unexpected max faulty for %d consenters", test.consenters)
+		assert.Equalf(t, test.quorum, Quorum(test.consenters), "unexpected quorum for %d consenters", test.consenters)
+	}
+}
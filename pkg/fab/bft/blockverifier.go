@@ -0,0 +1,146 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+)
+
+// BlockVerifier verifies that a block was signed by a quorum of the consenters of a BFT ordering service.
+// With a single signer, a faulty orderer could forge blocks; with a quorum, at least one correct
+// consenter vouches for the block.
+type BlockVerifier struct {
+	membership fab.ChannelMembership
+	consenters []*fab.Consenter
+}
+
+// NewBlockVerifier returns a block verifier for an ordering service with the given consenters.
+// Only the signatures of the consenters count towards the quorum. The signers of a block are
+// validated and their signatures are verified using the channel membership.
+func NewBlockVerifier(membership fab.ChannelMembership, consenters []*fab.Consenter) *BlockVerifier {
+	return &BlockVerifier{membership: membership, consenters: consenters}
+}
+
+// Verify returns an error if the block isn't signed by a quorum of distinct consenters
+func (v *BlockVerifier) Verify(block *common.Block) error {
+	if len(v.consenters) == 0 {
+		return errors.New("the consenters of the ordering service are unknown")
+	}
+	if block.Header == nil {
+		return errors.New("block header is nil")
+	}
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(common.BlockMetadataIndex_SIGNATURES) {
+		return errors.Errorf("block [%d] has no signatures metadata", block.Header.Number)
+	}
+
+	metadata := &common.Metadata{}
+	if err := proto.Unmarshal(block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES], metadata); err != nil {
+		return errors.Wrapf(err, "unmarshal signatures metadata of block [%d] failed", block.Header.Number)
+	}
+
+	headerBytes, err := blockHeaderBytes(block.Header)
+	if err != nil {
+		return err
+	}
+
+	signers := make(map[uint32]struct{})
+	for _, signature := range metadata.Signatures {
+		signer, err := v.verifySignature(metadata.Value, headerBytes, signature)
+		if err != nil {
+			logger.Warnf("Ignoring signature of block [%d]: %s", block.Header.Number, err)
+			continue
+		}
+		signers[signer.ID] = struct{}{}
+	}
+
+	quorum := Quorum(len(v.consenters))
+	if len(signers) < quorum {
+		return errors.Errorf("block [%d] is signed by %d valid consenters but a quorum of %d is required", block.Header.Number, len(signers), quorum)
+	}
+
+	logger.Debugf("Block [%d] is signed by %d valid consenters (quorum: %d)", block.Header.Number, len(signers), quorum)
+	return nil
+}
+
+// verifySignature verifies the given signature and returns the consenter which signed the block
+func (v *BlockVerifier) verifySignature(value, headerBytes []byte, signature *common.MetadataSignature) (*fab.Consenter, error) {
+	signatureHeader := &common.SignatureHeader{}
+	if err := proto.Unmarshal(signature.SignatureHeader, signatureHeader); err != nil {
+		return nil, errors.Wrap(err, "unmarshal signature header failed")
+	}
+	if len(signatureHeader.Creator) == 0 {
+		return nil, errors.New("signature header has no creator")
+	}
+
+	signer, err := v.consenter(signatureHeader.Creator)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := v.membership.Validate(signatureHeader.Creator); err != nil {
+		return nil, errors.WithMessage(err, "signer is not valid")
+	}
+
+	msg := util.ConcatenateBytes(value, signature.SignatureHeader, headerBytes)
+	if err := v.membership.Verify(signatureHeader.Creator, msg, signature.Signature); err != nil {
+		return nil, errors.WithMessage(err, "signature is not valid")
+	}
+
+	return signer, nil
+}
+
+// consenter returns the consenter with the given serialized identity
+func (v *BlockVerifier) consenter(serializedID []byte) (*fab.Consenter, error) {
+	id := &mb.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedID, id); err != nil {
+		return nil, errors.Wrap(err, "unmarshal signer identity failed")
+	}
+	for _, c := range v.consenters {
+		if c.MSPID == id.Mspid && bytes.Equal(certBytes(c.Identity), certBytes(id.IdBytes)) {
+			return c, nil
+		}
+	}
+	return nil, errors.Errorf("signer of MSP [%s] is not a consenter", id.Mspid)
+}
+
+// certBytes returns the DER bytes of a PEM encoded certificate (or the given bytes if they aren't PEM encoded)
+func certBytes(cert []byte) []byte {
+	if block, _ := pem.Decode(cert); block != nil {
+		return block.Bytes
+	}
+	return cert
+}
+
+type asn1Header struct {
+	Number       *big.Int
+	PreviousHash []byte
+	DataHash     []byte
+}
+
+// blockHeaderBytes returns the ASN.1 encoding of the block header, which is what the orderers sign
+func blockHeaderBytes(header *common.BlockHeader) ([]byte, error) {
+	bytes, err := asn1.Marshal(asn1Header{
+		Number:       new(big.Int).SetUint64(header.Number),
+		PreviousHash: header.PreviousHash,
+		DataHash:     header.DataHash,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal block header failed")
+	}
+	return bytes, nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+)
+
+const ordererMSPID = "OrdererMSP"
+
+// testConsenters are the consenters orderer1 to orderer4, whose identities are their names
+var testConsenters = []*fab.Consenter{
+	{ID: 1, MSPID: ordererMSPID, Identity: []byte("orderer1")},
+	{ID: 2, MSPID: ordererMSPID, Identity: []byte("orderer2")},
+	{ID: 3, MSPID: ordererMSPID, Identity: []byte("orderer3")},
+	{ID: 4, MSPID: ordererMSPID, Identity: []byte("orderer4")},
+}
+
+// testMembership "signs" a message by prefixing it with the ID of the signer
+type testMembership struct {
+	*mocks.MockMembership
+}
+
+func (m *testMembership) Verify(serializedID []byte, msg []byte, sig []byte) error {
+	if !bytes.Equal(sig, sign(serializedID, msg)) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+func sign(id, msg []byte) []byte {
+	return append(append([]byte{}, id...), msg...)
+}
+
+func newTestBlock(t *testing.T, signers ...string) *common.Block {
+	block := &common.Block{
+		Header:   &common.BlockHeader{Number: 10, PreviousHash: []byte("prev"), DataHash: []byte("data")},
+		Metadata: &common.BlockMetadata{Metadata: make([][]byte, len(common.BlockMetadataIndex_name))},
+	}
+
+	headerBytes, err := blockHeaderBytes(block.Header)
+	require.NoError(t, err)
+
+	metadata := &common.Metadata{Value: []byte("value")}
+	for _, signer := range signers {
+		creator, err := proto.Marshal(&mb.SerializedIdentity{Mspid: ordererMSPID, IdBytes: []byte(signer)})
+		require.NoError(t, err)
+		signatureHeader, err := proto.Marshal(&common.SignatureHeader{Creator: creator, Nonce: []byte("nonce-" + signer)})
+		require.NoError(t, err)
+		metadata.Signatures = append(metadata.Signatures, &common.MetadataSignature{
+			SignatureHeader: signatureHeader,
+			Signature:       sign(creator, util.ConcatenateBytes(metadata.Value, signatureHeader, headerBytes)),
+		})
+	}
+
+	block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES], err = proto.Marshal(metadata)
+	require.NoError(t, err)
+	return block
+}
+
+func TestBlockVerifier(t *testing.T) {
+	verifier := NewBlockVerifier(&testMembership{MockMembership: mocks.NewMockMembership()}, testConsenters)
+
+	assert.NoError(t, verifier.Verify(newTestBlock(t, "orderer1", "orderer2", "orderer3")))
+	assert.NoError(t, verifier.Verify(newTestBlock(t, "orderer1", "orderer2", "orderer3", "orderer4")))
+
+	err := verifier.Verify(newTestBlock(t, "orderer1", "orderer2"))
+	assert.Error(t, err, "expected error for block which isn't signed by a quorum")
+
+	err = verifier.Verify(newTestBlock(t, "orderer1", "orderer2", "orderer2"))
+	assert.Error(t, err, "expected signatures of the same consenter to be counted once")
+
+	err = verifier.Verify(newTestBlock(t, "orderer1", "orderer2", "orderer5", "peer1"))
+	assert.Error(t, err, "expected signatures of valid identities which aren't consenters not to count")
+
+	block := newTestBlock(t, "orderer1", "orderer2", "orderer3")
+	block.Header.Number = 11
+	assert.Error(t, verifier.Verify(block), "expected error for block whose header doesn't match the signatures")
+
+	block = newTestBlock(t, "orderer1", "orderer2", "orderer3")
+	block.Metadata = nil
+	assert.Error(t, verifier.Verify(block), "expected error for block without metadata")
+
+	membership := mocks.NewMockMembership()
+	membership.ValidateErr = errors.New("invalid identity")
+	verifier = NewBlockVerifier(&testMembership{MockMembership: membership}, testConsenters)
+	assert.Error(t, verifier.Verify(newTestBlock(t, "orderer1", "orderer2", "orderer3")), "expected error for signers which aren't valid")
+
+	verifier = NewBlockVerifier(&testMembership{MockMembership: mocks.NewMockMembership()}, nil)
+	assert.Error(t, verifier.Verify(newTestBlock(t, "orderer1", "orderer2", "orderer3")), "expected error without consenters")
+}
+
+func TestBlockVerifierQuorumOfConsenters(t *testing.T) {
+	// The quorum is computed from the consenters (n = 7, f = 2, quorum = 5), not from the orderer endpoints
+	consenters := append([]*fab.Consenter{}, testConsenters...)
+	for _, name := range []string{"orderer5", "orderer6", "orderer7"} {
+		consenters = append(consenters, &fab.Consenter{ID: uint32(len(consenters) + 1), MSPID: ordererMSPID, Identity: []byte(name)})
+	}
+	verifier := NewBlockVerifier(&testMembership{MockMembership: mocks.NewMockMembership()}, consenters)
+
+	assert.Error(t, verifier.Verify(newTestBlock(t, "orderer1", "orderer2", "orderer3", "orderer4")))
+	assert.NoError(t, verifier.Verify(newTestBlock(t, "orderer1", "orderer2", "orderer3", "orderer4", "orderer7")))
+}
@@ -0,0 +1,130 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package lifecycle creates the chaincode packages which are installed with the _lifecycle system
+// chaincode of peers which support the new chaincode lifecycle (Fabric 2.x):
+//
+//  ccPkg, err := gopackager.NewCCPackage("github.com/example_cc", goPath)
+//  ...
+//  pkg, err := lifecycle.NewCCPackage(&lifecycle.Descriptor{Path: "github.com/example_cc", Type: ccPkg.Type, Label: "example_cc_1", Code: ccPkg.Code})
+//  ...
+//  packageID := lifecycle.ComputePackageID("example_cc_1", pkg)
+package lifecycle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+const (
+	metadataFile = "metadata.json"
+	codeFile     = "code.tar.gz"
+)
+
+// labelRegexp is the format of the labels accepted by the peers
+var labelRegexp = regexp.MustCompile(`^[[:alnum:]][[:alnum:]_.+-]*$`)
+
+// Descriptor describes a chaincode package
+type Descriptor struct {
+	// Path is the path of the chaincode
+	Path string
+	// Type is the type of the chaincode
+	Type pb.ChaincodeSpec_Type
+	// Label is the label of the package, which is part of the package ID
+	Label string
+	// Code is the gzipped tar with the code of the chaincode, e.g. the code of a package created
+	// with the gopackager
+	Code []byte
+}
+
+type packageMetadata struct {
+	Path  string `json:"path"`
+	Type  string `json:"type"`
+	Label string `json:"label"`
+}
+
+// NewCCPackage returns the chaincode package with the given descriptor, i.e. a gzipped tar holding the
+// metadata of the package and the code of the chaincode
+func NewCCPackage(desc *Descriptor) ([]byte, error) {
+	if err := validate(desc); err != nil {
+		return nil, err
+	}
+
+	metadata, err := json.Marshal(&packageMetadata{
+		Path:  desc.Path,
+		Type:  strings.ToLower(desc.Type.String()),
+		Label: desc.Label,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal package metadata")
+	}
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	if err := writeFile(tw, metadataFile, metadata); err != nil {
+		return nil, err
+	}
+	if err := writeFile(tw, codeFile, desc.Code); err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to close package")
+	}
+	if err := gw.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to close package")
+	}
+	return buf.Bytes(), nil
+}
+
+// ComputePackageID returns the ID of the package with the given label, as computed by the peers
+func ComputePackageID(label string, ccPackage []byte) string {
+	hash := sha256.Sum256(ccPackage)
+	return label + ":" + hex.EncodeToString(hash[:])
+}
+
+func validate(desc *Descriptor) error {
+	if desc == nil {
+		return errors.New("package descriptor is required")
+	}
+	if desc.Path == "" {
+		return errors.New("chaincode path is required")
+	}
+	if !labelRegexp.MatchString(desc.Label) {
+		return errors.Errorf("invalid package label [%s]: expecting %s", desc.Label, labelRegexp)
+	}
+	if len(desc.Code) == 0 {
+		return errors.New("chaincode code is required")
+	}
+	return nil
+}
+
+func writeFile(tw *tar.Writer, name string, content []byte) error {
+	header := &tar.Header{
+		Name:    name,
+		Size:    int64(len(content)),
+		Mode:    0100644,
+		ModTime: time.Time{},
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return errors.Wrapf(err, "failed to write header of %s", name)
+	}
+	if _, err := tw.Write(content); err != nil {
+		return errors.Wrapf(err, "failed to write %s", name)
+	}
+	return nil
+}
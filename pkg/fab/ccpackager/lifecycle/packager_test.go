@@ -0,0 +1,69 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+func TestNewCCPackage(t *testing.T) {
+	code := []byte("code")
+	pkg, err := NewCCPackage(&Descriptor{Path: "github.com/example_cc", Type: pb.ChaincodeSpec_GOLANG, Label: "example_cc_1.0", Code: code})
+	require.NoError(t, err)
+
+	files := readPackage(t, pkg)
+	assert.Equal(t, code, files[codeFile])
+
+	metadata := &packageMetadata{}
+	require.NoError(t, json.Unmarshal(files[metadataFile], metadata))
+	assert.Equal(t, packageMetadata{Path: "github.com/example_cc", Type: "golang", Label: "example_cc_1.0"}, *metadata)
+
+	pkg2, err := NewCCPackage(&Descriptor{Path: "github.com/example_cc", Type: pb.ChaincodeSpec_GOLANG, Label: "example_cc_1.0", Code: code})
+	require.NoError(t, err)
+	assert.Equal(t, ComputePackageID("example_cc_1.0", pkg), ComputePackageID("example_cc_1.0", pkg2), "expecting deterministic package")
+	assert.Regexp(t, "^example_cc_1.0:[0-9a-f]{64}$", ComputePackageID("example_cc_1.0", pkg))
+}
+
+func TestNewCCPackageInvalid(t *testing.T) {
+	_, err := NewCCPackage(nil)
+	assert.Error(t, err, "expecting error without descriptor")
+	_, err = NewCCPackage(&Descriptor{Type: pb.ChaincodeSpec_GOLANG, Label: "cc", Code: []byte("code")})
+	assert.Error(t, err, "expecting error without path")
+	_, err = NewCCPackage(&Descriptor{Path: "cc", Type: pb.ChaincodeSpec_GOLANG, Label: "cc:1", Code: []byte("code")})
+	assert.Error(t, err, "expecting error for invalid label")
+	_, err = NewCCPackage(&Descriptor{Path: "cc", Type: pb.ChaincodeSpec_GOLANG, Label: "cc"})
+	assert.Error(t, err, "expecting error without code")
+}
+
+func readPackage(t *testing.T, pkg []byte) map[string][]byte {
+	gr, err := gzip.NewReader(bytes.NewReader(pkg))
+	require.NoError(t, err)
+	tr := tar.NewReader(gr)
+
+	files := make(map[string][]byte)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		files[header.Name], err = ioutil.ReadAll(tr)
+		require.NoError(t, err)
+	}
+	return files
+}
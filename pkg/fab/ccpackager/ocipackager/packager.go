@@ -0,0 +1,176 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package ocipackager pulls chaincode packages from OCI registries, so that chaincode published
+// by a CI pipeline can be installed without copying the package to the admin host:
+//
+//  ccPkg, err := ocipackager.NewCCPackage("registry.example.com/chaincode/mycc@sha256:...",
+//      ocipackager.WithBasicAuth(user, password))
+//  ...
+//  _, err = resMgmtClient.InstallCC(resmgmt.InstallCCRequest{Name: "mycc", Path: "github.com/example/mycc", Version: "1.0", Package: ccPkg})
+//
+// The artifact must contain a single layer with the code of the chaincode as a gzipped tar (as produced
+// by the gopackager) or a layer of the chaincode media type. The manifest is verified against the digest
+// of the reference (or of WithDigest) and the layer is verified against the digest of the manifest.
+package ocipackager
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+var logger = logging.NewLogger("fabsdk/fab")
+
+const (
+	// MediaTypeChaincodeLayer is the media type of the layer which contains the code of the chaincode
+	MediaTypeChaincodeLayer = "application/vnd.hyperledger.fabric.chaincode.layer.v1.tar+gzip"
+	// AnnotationChaincodeType is the manifest annotation with the type of the chaincode
+	// (GOLANG, NODE, CAR or JAVA). GOLANG is assumed if the annotation is missing.
+	AnnotationChaincodeType = "org.hyperledger.fabric.chaincode.type"
+
+	defaultMaxSize = 100 * 1024 * 1024
+	defaultTimeout = time.Minute
+)
+
+type options struct {
+	httpClient *http.Client
+	insecure   bool
+	username   string
+	password   string
+	token      string
+	digest     string
+	maxSize    int64
+}
+
+func (o *options) scheme() string {
+	if o.insecure {
+		return "http"
+	}
+	return "https"
+}
+
+// Option describes a functional parameter for the NewCCPackage function
+type Option func(*options)
+
+// WithHTTPClient sets the HTTP client used to connect to the registry (e.g. with custom TLS settings)
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = client
+	}
+}
+
+// WithInsecure connects to the registry with plain HTTP instead of HTTPS
+func WithInsecure() Option {
+	return func(o *options) {
+		o.insecure = true
+	}
+}
+
+// WithBasicAuth authenticates to the registry (or to its token service) with the given credentials
+func WithBasicAuth(username, password string) Option {
+	return func(o *options) {
+		o.username = username
+		o.password = password
+	}
+}
+
+// WithToken authenticates to the registry with the given bearer token
+func WithToken(token string) Option {
+	return func(o *options) {
+		o.token = token
+	}
+}
+
+// WithDigest sets the expected digest (sha256:<hex>) of the manifest, which pins an artifact
+// which is referenced by tag
+func WithDigest(digest string) Option {
+	return func(o *options) {
+		o.digest = digest
+	}
+}
+
+// WithMaxSize sets the maximum size of the chaincode package (100MB by default)
+func WithMaxSize(size int64) Option {
+	return func(o *options) {
+		o.maxSize = size
+	}
+}
+
+// NewCCPackage pulls the chaincode package of the given reference
+// (<registry>/<repository>[:<tag>|@<digest>]) from the OCI registry
+func NewCCPackage(ref string, opts ...Option) (*api.CCPackage, error) {
+	o := &options{
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		maxSize:    defaultMaxSize,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	r, err := parseReference(ref)
+	if err != nil {
+		return nil, err
+	}
+	if o.digest != "" && r.digest != "" && o.digest != r.digest {
+		return nil, errors.Errorf("digest [%s] doesn't match digest of reference [%s]", o.digest, ref)
+	}
+
+	client := &registryClient{opts: o, ref: r, token: o.token}
+	m, err := client.manifest()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get manifest of "+r.String())
+	}
+
+	layer, err := chaincodeLayer(m)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid chaincode artifact "+r.String())
+	}
+	code, err := client.blob(layer)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get chaincode layer of "+r.String())
+	}
+
+	ccType, err := chaincodeType(m)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("Pulled chaincode package %s (%s, %d bytes)", r, layer.Digest, len(code))
+	return &api.CCPackage{Type: ccType, Code: code}, nil
+}
+
+// chaincodeLayer returns the layer of the manifest which contains the chaincode
+func chaincodeLayer(m *manifest) (descriptor, error) {
+	for _, layer := range m.Layers {
+		if layer.MediaType == MediaTypeChaincodeLayer {
+			return layer, nil
+		}
+	}
+	if len(m.Layers) == 1 {
+		return m.Layers[0], nil
+	}
+	return descriptor{}, errors.Errorf("expecting a single layer or a layer of media type %s but found %d layers", MediaTypeChaincodeLayer, len(m.Layers))
+}
+
+// chaincodeType returns the chaincode type of the annotation of the manifest
+func chaincodeType(m *manifest) (pb.ChaincodeSpec_Type, error) {
+	name, ok := m.Annotations[AnnotationChaincodeType]
+	if !ok {
+		return pb.ChaincodeSpec_GOLANG, nil
+	}
+	ccType, ok := pb.ChaincodeSpec_Type_value[strings.ToUpper(name)]
+	if !ok {
+		return pb.ChaincodeSpec_UNDEFINED, errors.Errorf("unsupported chaincode type [%s]", name)
+	}
+	return pb.ChaincodeSpec_Type(ccType), nil
+}
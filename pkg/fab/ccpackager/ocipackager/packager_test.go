@@ -0,0 +1,192 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ocipackager
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+var testCode = []byte("chaincode tar.gz")
+
+func TestNewCCPackage(t *testing.T) {
+	registry := newFakeRegistry(t, map[string]string{AnnotationChaincodeType: "node"})
+	server := httptest.NewServer(registry)
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	ccPkg, err := NewCCPackage(host+"/chaincode/mycc:1.0", WithInsecure())
+	require.NoError(t, err)
+	assert.Equal(t, pb.ChaincodeSpec_NODE, ccPkg.Type)
+	assert.Equal(t, testCode, ccPkg.Code)
+
+	ccPkg, err = NewCCPackage(host+"/chaincode/mycc@"+registry.manifestDigest, WithInsecure())
+	require.NoError(t, err)
+	assert.Equal(t, testCode, ccPkg.Code)
+
+	_, err = NewCCPackage(host+"/chaincode/mycc:1.0", WithInsecure(), WithDigest(registry.manifestDigest))
+	require.NoError(t, err)
+
+	_, err = NewCCPackage(host+"/chaincode/mycc:2.0", WithInsecure())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+}
+
+func TestNewCCPackageDigestVerification(t *testing.T) {
+	registry := newFakeRegistry(t, nil)
+	server := httptest.NewServer(registry)
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	otherDigest := digest([]byte("other"))
+	_, err := NewCCPackage(host+"/chaincode/mycc:1.0", WithInsecure(), WithDigest(otherDigest))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "digest mismatch")
+
+	_, err = NewCCPackage(host+"/chaincode/mycc@"+registry.manifestDigest, WithInsecure(), WithDigest(otherDigest))
+	require.Error(t, err)
+
+	// Registry serves a tampered layer
+	registry.blobs[registry.layerDigest] = []byte("chaincode tar.gx")
+	_, err = NewCCPackage(host+"/chaincode/mycc:1.0", WithInsecure())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "blob verification failed")
+
+	_, err = NewCCPackage(host+"/chaincode/mycc:1.0", WithInsecure(), WithMaxSize(4))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds maximum package size")
+}
+
+func TestNewCCPackageAuth(t *testing.T) {
+	registry := newFakeRegistry(t, nil)
+	server := httptest.NewServer(registry)
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+	registry.realm = server.URL + "/token"
+	registry.username = "ci"
+	registry.password = "secret"
+
+	ccPkg, err := NewCCPackage(host+"/chaincode/mycc:1.0", WithInsecure(), WithBasicAuth("ci", "secret"))
+	require.NoError(t, err)
+	assert.Equal(t, testCode, ccPkg.Code)
+	assert.Equal(t, "scope=repository%3Achaincode%2Fmycc%3Apull&service=fake-registry", registry.tokenQuery)
+
+	_, err = NewCCPackage(host+"/chaincode/mycc:1.0", WithInsecure(), WithBasicAuth("ci", "wrong"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "registry authentication failed")
+
+	_, err = NewCCPackage(host+"/chaincode/mycc:1.0", WithInsecure(), WithToken(registry.token))
+	require.NoError(t, err)
+}
+
+func TestParseReference(t *testing.T) {
+	d := digest([]byte("manifest"))
+
+	r, err := parseReference("registry.example.com:5000/chaincode/mycc")
+	require.NoError(t, err)
+	assert.Equal(t, &reference{registry: "registry.example.com:5000", repository: "chaincode/mycc", tag: "latest"}, r)
+
+	r, err = parseReference("registry.example.com/mycc@" + d)
+	require.NoError(t, err)
+	assert.Equal(t, &reference{registry: "registry.example.com", repository: "mycc", digest: d}, r)
+	assert.Equal(t, d, r.manifestReference())
+
+	for _, ref := range []string{"mycc", "/mycc", "registry.example.com/", "registry.example.com/mycc@md5:abc", "registry.example.com/mycc:bad/tag"} {
+		_, err := parseReference(ref)
+		assert.Error(t, err, "expecting error for reference [%s]", ref)
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	params := parseChallenge(`Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:mycc:pull,push"`)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.example.com/token",
+		"service": "registry.example.com",
+		"scope":   "repository:mycc:pull,push",
+	}, params)
+	assert.Empty(t, parseChallenge(`Basic realm="registry"`))
+}
+
+// fakeRegistry serves a single chaincode artifact, tagged 1.0, in the chaincode/mycc repository
+type fakeRegistry struct {
+	manifest       []byte
+	manifestDigest string
+	layerDigest    string
+	blobs          map[string][]byte
+
+	// Token authentication, if realm is set
+	realm      string
+	username   string
+	password   string
+	token      string
+	tokenQuery string
+}
+
+func newFakeRegistry(t *testing.T, annotations map[string]string) *fakeRegistry {
+	layerDigest := digest(testCode)
+	manifest, err := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     mediaTypeOCIManifest,
+		"config":        map[string]interface{}{"mediaType": "application/vnd.oci.image.config.v1+json", "digest": digest([]byte("{}")), "size": 2},
+		"layers":        []map[string]interface{}{{"mediaType": MediaTypeChaincodeLayer, "digest": layerDigest, "size": len(testCode)}},
+		"annotations":   annotations,
+	})
+	require.NoError(t, err)
+
+	return &fakeRegistry{
+		manifest:       manifest,
+		manifestDigest: digest(manifest),
+		layerDigest:    layerDigest,
+		blobs:          map[string][]byte{layerDigest: testCode},
+		token:          "pull-token",
+	}
+}
+
+func (r *fakeRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/token" {
+		if user, password, ok := req.BasicAuth(); !ok || user != r.username || password != r.password {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		r.tokenQuery = req.URL.RawQuery
+		fmt.Fprintf(w, `{"token":"%s"}`, r.token)
+		return
+	}
+
+	if r.realm != "" && req.Header.Get("Authorization") != "Bearer "+r.token {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s",service="fake-registry"`, r.realm))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	const prefix = "/v2/chaincode/mycc"
+	switch req.URL.Path {
+	case prefix + "/manifests/1.0", prefix + "/manifests/" + r.manifestDigest:
+		w.Header().Set("Content-Type", mediaTypeOCIManifest)
+		w.Write(r.manifest)
+	case prefix + "/blobs/" + r.layerDigest:
+		w.Write(r.blobs[r.layerDigest])
+	default:
+		http.Error(w, `{"errors":[{"code":"MANIFEST_UNKNOWN"}]}`, http.StatusNotFound)
+	}
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
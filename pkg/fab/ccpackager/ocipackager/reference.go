@@ -0,0 +1,78 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ocipackager
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const defaultTag = "latest"
+
+var (
+	digestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+	tagPattern    = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+)
+
+// reference is a parsed reference to an artifact in an OCI registry,
+// e.g. registry.example.com:5000/chaincode/mycc:1.0 or registry.example.com/mycc@sha256:...
+type reference struct {
+	registry   string
+	repository string
+	tag        string
+	digest     string
+}
+
+// parseReference parses a reference of the form <registry>/<repository>[:<tag>|@<digest>].
+// The tag defaults to "latest".
+func parseReference(ref string) (*reference, error) {
+	i := strings.Index(ref, "/")
+	if i <= 0 {
+		return nil, errors.Errorf("invalid reference [%s]: registry is required", ref)
+	}
+	r := &reference{registry: ref[:i]}
+	name := ref[i+1:]
+
+	if i := strings.Index(name, "@"); i >= 0 {
+		r.digest = name[i+1:]
+		name = name[:i]
+		if !digestPattern.MatchString(r.digest) {
+			return nil, errors.Errorf("invalid reference [%s]: unsupported digest", ref)
+		}
+	} else if i := strings.LastIndex(name, ":"); i >= 0 {
+		r.tag = name[i+1:]
+		name = name[:i]
+		if !tagPattern.MatchString(r.tag) {
+			return nil, errors.Errorf("invalid reference [%s]: invalid tag", ref)
+		}
+	} else {
+		r.tag = defaultTag
+	}
+
+	if name == "" {
+		return nil, errors.Errorf("invalid reference [%s]: repository is required", ref)
+	}
+	r.repository = name
+	return r, nil
+}
+
+// manifestReference returns the tag or digest with which the manifest is requested
+func (r *reference) manifestReference() string {
+	if r.digest != "" {
+		return r.digest
+	}
+	return r.tag
+}
+
+func (r *reference) String() string {
+	if r.digest != "" {
+		return r.registry + "/" + r.repository + "@" + r.digest
+	}
+	return r.registry + "/" + r.repository + ":" + r.tag
+}
@@ -0,0 +1,267 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ocipackager
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Media types of OCI image manifests and of Docker schema 2 manifests, which are also accepted
+const (
+	mediaTypeOCIManifest    = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeDockerManifest = "application/vnd.docker.distribution.manifest.v2+json"
+)
+
+// maxManifestSize is the maximum size of a manifest
+const maxManifestSize = 4 * 1024 * 1024
+
+// manifest is the subset of an OCI image manifest used by the packager
+type manifest struct {
+	MediaType   string            `json:"mediaType"`
+	Layers      []descriptor      `json:"layers"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// descriptor describes a blob of an OCI artifact
+type descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// registryClient pulls manifests and blobs with the OCI distribution API
+type registryClient struct {
+	opts  *options
+	ref   *reference
+	token string
+}
+
+// manifest returns the manifest of the reference, verified against the digest of the reference
+// (or of the WithDigest option)
+func (c *registryClient) manifest() (*manifest, error) {
+	resp, err := c.get("/manifests/"+c.ref.manifestReference(), mediaTypeOCIManifest+", "+mediaTypeDockerManifest)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := readAll(resp.Body, maxManifestSize)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to read manifest")
+	}
+
+	for _, expected := range []string{c.ref.digest, c.opts.digest} {
+		if expected != "" {
+			if err := verifyDigest(expected, data); err != nil {
+				return nil, errors.WithMessage(err, "manifest verification failed")
+			}
+		}
+	}
+
+	m := &manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal manifest")
+	}
+	return m, nil
+}
+
+// blob returns the content of the blob, verified against the size and digest of its descriptor
+func (c *registryClient) blob(desc descriptor) ([]byte, error) {
+	if desc.Size > c.opts.maxSize {
+		return nil, errors.Errorf("blob size %d exceeds maximum package size %d", desc.Size, c.opts.maxSize)
+	}
+
+	resp, err := c.get("/blobs/"+desc.Digest, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := readAll(resp.Body, c.opts.maxSize)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to read blob")
+	}
+	if int64(len(data)) != desc.Size {
+		return nil, errors.Errorf("blob size %d doesn't match descriptor size %d", len(data), desc.Size)
+	}
+	if err := verifyDigest(desc.Digest, data); err != nil {
+		return nil, errors.WithMessage(err, "blob verification failed")
+	}
+	return data, nil
+}
+
+// get sends a GET request to the repository, authenticating with a bearer token if requested by the registry
+func (c *registryClient) get(path, accept string) (*http.Response, error) {
+	resp, err := c.do(path, accept)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized && c.token == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if c.token, err = c.fetchToken(challenge); err != nil {
+			return nil, errors.WithMessage(err, "registry authentication failed")
+		}
+		if resp, err = c.do(path, accept); err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := readAll(resp.Body, 1024)
+		resp.Body.Close()
+		return nil, errors.Errorf("GET %s failed with status %d: %s", path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+func (c *registryClient) do(path, accept string) (*http.Response, error) {
+	u := fmt.Sprintf("%s://%s/v2/%s%s", c.opts.scheme(), c.ref.registry, c.ref.repository, path)
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	switch {
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	case c.opts.username != "":
+		req.SetBasicAuth(c.opts.username, c.opts.password)
+	}
+
+	resp, err := c.opts.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "GET %s failed", u)
+	}
+	return resp, nil
+}
+
+// fetchToken obtains a pull token from the authorization service of the given Bearer challenge
+func (c *registryClient) fetchToken(challenge string) (string, error) {
+	params := parseChallenge(challenge)
+	realm, ok := params["realm"]
+	if !ok {
+		return "", errors.Errorf("unsupported authentication challenge [%s]", challenge)
+	}
+
+	query := url.Values{}
+	if service, ok := params["service"]; ok {
+		query.Set("service", service)
+	}
+	scope, ok := params["scope"]
+	if !ok {
+		scope = "repository:" + c.ref.repository + ":pull"
+	}
+	query.Set("scope", scope)
+
+	req, err := http.NewRequest(http.MethodGet, realm+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create token request")
+	}
+	if c.opts.username != "" {
+		req.SetBasicAuth(c.opts.username, c.opts.password)
+	}
+	resp, err := c.opts.httpClient.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "token request failed")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("token request failed with status %d", resp.StatusCode)
+	}
+
+	var tokenResp struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", errors.Wrap(err, "failed to decode token response")
+	}
+	if tokenResp.Token != "" {
+		return tokenResp.Token, nil
+	}
+	if tokenResp.AccessToken != "" {
+		return tokenResp.AccessToken, nil
+	}
+	return "", errors.New("token response doesn't contain a token")
+}
+
+// parseChallenge parses the parameters of a Bearer challenge, e.g.
+// Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:mycc:pull"
+func parseChallenge(challenge string) map[string]string {
+	params := make(map[string]string)
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return params
+	}
+	rest := challenge[len("bearer "):]
+	for rest != "" {
+		rest = strings.TrimLeft(rest, " ,")
+		i := strings.Index(rest, "=")
+		if i < 0 {
+			break
+		}
+		key := strings.TrimSpace(rest[:i])
+		rest = rest[i+1:]
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				break
+			}
+			value = rest[1 : end+1]
+			rest = rest[end+2:]
+		} else {
+			end := strings.Index(rest, ",")
+			if end < 0 {
+				end = len(rest)
+			}
+			value = rest[:end]
+			rest = rest[end:]
+		}
+		params[key] = value
+	}
+	return params
+}
+
+// verifyDigest verifies the SHA-256 digest of the data
+func verifyDigest(digest string, data []byte) error {
+	if !digestPattern.MatchString(digest) {
+		return errors.Errorf("unsupported digest [%s]", digest)
+	}
+	sum := sha256.Sum256(data)
+	if actual := "sha256:" + hex.EncodeToString(sum[:]); actual != digest {
+		return errors.Errorf("digest mismatch: expected [%s] but got [%s]", digest, actual)
+	}
+	return nil
+}
+
+// readAll reads at most max bytes from the reader
+func readAll(r io.Reader, max int64) ([]byte, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, max+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > max {
+		return nil, errors.Errorf("content exceeds maximum size %d", max)
+	}
+	return data, nil
+}
@@ -46,14 +46,14 @@ func NewCacheKey(context Context, chConfigRef *lazyref.Reference, channelID stri
 }
 
 // NewRefCache a cache of membership references that refreshed with the
-// given interval
-func NewRefCache(refresh time.Duration) *lazycache.Cache {
+// given interval. The given options are applied to the cached memberships.
+func NewRefCache(refresh time.Duration, opts ...Option) *lazycache.Cache {
 	initializer := func(key lazycache.Key) (interface{}, error) {
 		ck, ok := key.(CacheKey)
 		if !ok {
 			return nil, errors.New("unexpected cache key")
 		}
-		return NewRef(refresh, ck.Context(), ck.ChConfigRef()), nil
+		return NewRef(refresh, ck.Context(), ck.ChConfigRef(), opts...), nil
 	}
 
 	return lazycache.New("Membership_Cache", initializer)
@@ -8,6 +8,7 @@ package membership
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -15,7 +16,9 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazyref"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -102,3 +105,81 @@ func TestMembershipCacheBad(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), testErr.Error())
 }
+
+func TestRefreshOnConfigUpdate(t *testing.T) {
+	testChannelID := "test"
+	goodMSPID := "GoodMSP"
+
+	var lock sync.Mutex
+	blockNum := uint64(1)
+	mspConfigs := []*mb.MSPConfig{buildMSPConfig("OtherMSP", []byte(validRootCA))}
+
+	chConfigRef := lazyref.New(func() (interface{}, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		cfg := mocks.NewMockChannelCfg(testChannelID)
+		cfg.MockMSPs = mspConfigs
+		cfg.MockBlockNumber = blockNum
+		return cfg, nil
+	})
+
+	ref := NewRef(time.Hour, Context{Providers: mocks.NewMockProviderContext(), EndpointConfig: mocks.NewMockEndpointConfig()}, chConfigRef)
+	defer ref.Close()
+
+	eventService := newConfigEventService()
+	ref.ListenForConfigUpdates(func() (fab.EventService, error) { return eventService, nil })
+	// A second listener isn't started
+	ref.ListenForConfigUpdates(func() (fab.EventService, error) { return nil, fmt.Errorf("unexpected call") })
+
+	endorser, err := proto.Marshal(&mb.SerializedIdentity{Mspid: goodMSPID, IdBytes: []byte(certPem)})
+	require.NoError(t, err)
+	assert.NotNil(t, ref.Validate(endorser), "expected validation to fail for unknown MSP")
+
+	// The organization is added to the channel
+	lock.Lock()
+	blockNum = 2
+	mspConfigs = append(mspConfigs, buildMSPConfig(goodMSPID, []byte(validRootCA)))
+	lock.Unlock()
+
+	// Blocks without config transactions don't refresh the membership
+	eventService.eventch <- newFilteredBlockEvent(testChannelID, 2, common.HeaderType_ENDORSER_TRANSACTION)
+	assert.NotNil(t, ref.Validate(endorser))
+
+	eventService.eventch <- newFilteredBlockEvent(testChannelID, 2, common.HeaderType_CONFIG)
+	assert.True(t, waitFor(func() bool { return ref.Validate(endorser) == nil }), "expected membership to be refreshed after config update")
+}
+
+type configEventService struct {
+	*mocks.MockEventService
+	eventch chan *fab.FilteredBlockEvent
+}
+
+func newConfigEventService() *configEventService {
+	return &configEventService{MockEventService: mocks.NewMockEventService(), eventch: make(chan *fab.FilteredBlockEvent)}
+}
+
+func (s *configEventService) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
+	return nil, s.eventch, nil
+}
+
+func newFilteredBlockEvent(channelID string, blockNum uint64, txType common.HeaderType) *fab.FilteredBlockEvent {
+	return &fab.FilteredBlockEvent{
+		FilteredBlock: &pb.FilteredBlock{
+			ChannelId: channelID,
+			Number:    blockNum,
+			FilteredTransactions: []*pb.FilteredTransaction{
+				{Type: txType, TxValidationCode: pb.TxValidationCode_VALID},
+			},
+		},
+	}
+}
+
+func waitFor(condition func() bool) bool {
+	for i := 0; i < 100; i++ {
+		if condition() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package membership
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// EventServiceProvider returns the event service of the channel
+type EventServiceProvider func() (fab.EventService, error)
+
+// ListenForConfigUpdates registers for filtered block events with the event service returned by the
+// given provider and refreshes the channel config and the membership whenever a config block is
+// committed on the channel, so that organization additions/removals and CA rotations are picked up
+// without waiting for the refresh interval. Only one listener is started per reference; the listener
+// is stopped when the reference is closed. If the listener fails, it may be started again.
+func (ref *Ref) ListenForConfigUpdates(provider EventServiceProvider) {
+	ref.listenerLock.Lock()
+	defer ref.listenerLock.Unlock()
+
+	if ref.listening || ref.closed {
+		return
+	}
+
+	ref.listening = true
+	go ref.listen(provider)
+}
+
+// Close stops the config update listener and closes the reference
+func (ref *Ref) Close() {
+	ref.listenerLock.Lock()
+	if !ref.closed {
+		ref.closed = true
+		close(ref.done)
+	}
+	ref.listenerLock.Unlock()
+
+	ref.Reference.Close()
+}
+
+func (ref *Ref) listen(provider EventServiceProvider) {
+	defer ref.setListening(false)
+
+	eventService, err := provider()
+	if err != nil {
+		logger.Warnf("Unable to listen for config updates: failed to get event service: %s", err)
+		return
+	}
+
+	reg, eventch, err := eventService.RegisterFilteredBlockEvent()
+	if err != nil {
+		logger.Warnf("Unable to listen for config updates: failed to register for filtered block events: %s", err)
+		return
+	}
+	defer eventService.Unregister(reg)
+
+	logger.Debugf("Listening for config updates...")
+
+	for {
+		select {
+		case event, ok := <-eventch:
+			if !ok {
+				logger.Debugf("Event channel closed - no longer listening for config updates")
+				return
+			}
+			if isConfigBlock(event.FilteredBlock) {
+				ref.refresh(event.FilteredBlock)
+			}
+		case <-ref.done:
+			logger.Debugf("Reference closed - no longer listening for config updates")
+			return
+		}
+	}
+}
+
+// refresh refreshes the channel config reference and then the membership
+func (ref *Ref) refresh(block *pb.FilteredBlock) {
+	logger.Debugf("Config block %d committed on channel [%s] - refreshing membership", block.Number, block.ChannelId)
+
+	if err := ref.chConfigRef.Refresh(); err != nil {
+		logger.Warnf("Failed to refresh channel config after config update: %s", err)
+		return
+	}
+	if err := ref.Refresh(); err != nil {
+		logger.Warnf("Failed to refresh membership after config update: %s", err)
+	}
+}
+
+func (ref *Ref) setListening(listening bool) {
+	ref.listenerLock.Lock()
+	defer ref.listenerLock.Unlock()
+	ref.listening = listening
+}
+
+func isConfigBlock(block *pb.FilteredBlock) bool {
+	if block == nil {
+		return false
+	}
+	for _, tx := range block.FilteredTransactions {
+		if tx.Type == common.HeaderType_CONFIG && tx.TxValidationCode == pb.TxValidationCode_VALID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package membership
+
+import (
+	"crypto/x509"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/certexpiry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/sdkevents"
+)
+
+// channelEndpointConfig overrides the TLS CA cert pool of an endpoint config
+// with the TLS CA cert pool of a channel
+type channelEndpointConfig struct {
+	fab.EndpointConfig
+	membership fab.ChannelMembership
+}
+
+// NewChannelEndpointConfig returns an endpoint config which uses the TLS CA cert pool of the given
+// channel membership, i.e. connections established with this config trust the TLS root certificates
+// of the channel's MSPs in addition to the configured TLS root certificates.
+func NewChannelEndpointConfig(config fab.EndpointConfig, membership fab.ChannelMembership) fab.EndpointConfig {
+	return &channelEndpointConfig{EndpointConfig: config, membership: membership}
+}
+
+// TLSCACertPool returns the TLS CA cert pool of the channel. If a cert is provided, the cert is added to the pool.
+func (c *channelEndpointConfig) TLSCACertPool(certs ...*x509.Certificate) *x509.CertPool {
+	pool, err := c.membership.TLSCACertPool(certs...)
+	if err != nil {
+		logger.Warnf("Failed to get TLS CA cert pool of channel, using configured cert pool: %s", err)
+		return c.EndpointConfig.TLSCACertPool(certs...)
+	}
+	return pool
+}
+
+// Dialer returns the custom dialer registered under the given name with the wrapped endpoint config
+func (c *channelEndpointConfig) Dialer(name string) (comm.Dialer, bool) {
+	provider, ok := c.EndpointConfig.(comm.DialerProvider)
+	if !ok {
+		return nil, false
+	}
+	return provider.Dialer(name)
+}
+
+// CertExpiryTracker returns the certificate expiry tracker of the wrapped endpoint config
+func (c *channelEndpointConfig) CertExpiryTracker() *certexpiry.Tracker {
+	return certexpiry.TrackerFor(c.EndpointConfig)
+}
+
+// EventBus returns the event bus of the wrapped endpoint config
+func (c *channelEndpointConfig) EventBus() *sdkevents.Bus {
+	return sdkevents.BusFor(c.EndpointConfig)
+}
@@ -0,0 +1,413 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package membership
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-amcl/amcl/FP256BN"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/idemix"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/msp"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+)
+
+// Attributes of the credentials issued by an idemix MSP
+const (
+	idemixAttributeNameOU               = "OU"
+	idemixAttributeNameRole             = "Role"
+	idemixAttributeNameEnrollmentID     = "EnrollmentID"
+	idemixAttributeNameRevocationHandle = "RevocationHandle"
+)
+
+// idemixRHIndex is the index of the revocation handle attribute
+const idemixRHIndex = 3
+
+// idemixDisclosure discloses the OU and the role of an identity and hides its enrollment ID
+// and revocation handle
+var idemixDisclosure = []byte{1, 1, 0, 0}
+
+// Roles as encoded in the role attribute of an idemix credential
+const (
+	idemixRoleMember = 1
+	idemixRoleAdmin  = 2
+	idemixRoleClient = 4
+	idemixRolePeer   = 8
+)
+
+func init() {
+	idemix.SetLogger(idemix.LogFunc(logger.Debugf))
+}
+
+// idemixMSPConfig is IdemixMSPConfig including the revocation public key and epoch (fields 4
+// and 5) which aren't included in the pinned protos
+type idemixMSPConfig struct {
+	Name         string `protobuf:"bytes,1,opt,name=name"`
+	IPk          []byte `protobuf:"bytes,2,opt,name=ipk,proto3"`
+	RevocationPk []byte `protobuf:"bytes,4,opt,name=revocation_pk,proto3"`
+	Epoch        int64  `protobuf:"varint,5,opt,name=epoch"`
+}
+
+func (m *idemixMSPConfig) Reset()         { *m = idemixMSPConfig{} }
+func (m *idemixMSPConfig) String() string { return proto.CompactTextString(m) }
+func (*idemixMSPConfig) ProtoMessage()    {}
+
+// idemixMSP is a verifying-only idemix MSP. Identities of idemix MSPs are validated by verifying
+// the proof that their pseudonym was derived from a credential of the issuer, and their
+// signatures are verified against their pseudonym.
+type idemixMSP struct {
+	name         string
+	ipkBytes     []byte
+	ipk          *idemix.IssuerPublicKey
+	revocationPK *ecdsa.PublicKey
+	epoch        int
+}
+
+func newIdemixMSP() *idemixMSP {
+	return &idemixMSP{}
+}
+
+// Setup the MSP instance according to configuration information
+func (m *idemixMSP) Setup(config *mb.MSPConfig) error {
+	if config == nil {
+		return errors.New("setup error: nil conf reference")
+	}
+	if msp.ProviderType(config.Type) != msp.IDEMIX {
+		return errors.Errorf("setup error: config is not of type IDEMIX")
+	}
+
+	idemixConfig := &idemixMSPConfig{}
+	if err := proto.Unmarshal(config.Config, idemixConfig); err != nil {
+		return errors.Wrap(err, "unmarshal IdemixMSPConfig from config failed")
+	}
+	if idemixConfig.Name == "" {
+		return errors.New("MSP Configuration missing name")
+	}
+	if len(idemixConfig.IPk) == 0 {
+		return errors.Errorf("MSP Configuration missing issuer public key for idemix MSP [%s]", idemixConfig.Name)
+	}
+
+	ipk, err := newIssuerPublicKey(idemixConfig.IPk)
+	if err != nil {
+		return errors.WithMessage(err, "invalid issuer public key for idemix MSP ["+idemixConfig.Name+"]")
+	}
+
+	revocationPK, err := newRevocationPublicKey(idemixConfig.RevocationPk)
+	if err != nil {
+		return errors.WithMessage(err, "invalid revocation public key for idemix MSP ["+idemixConfig.Name+"]")
+	}
+
+	m.name = idemixConfig.Name
+	m.ipkBytes = idemixConfig.IPk
+	m.ipk = ipk
+	m.revocationPK = revocationPK
+	m.epoch = int(idemixConfig.Epoch)
+	return nil
+}
+
+func newIssuerPublicKey(raw []byte) (*idemix.IssuerPublicKey, error) {
+	ipk := &idemix.IssuerPublicKey{}
+	if err := proto.Unmarshal(raw, ipk); err != nil {
+		return nil, errors.Wrap(err, "unmarshal issuer public key failed")
+	}
+
+	// SetHash and Check panic on malformed points
+	if err := recoverIdemix(ipk.SetHash); err != nil {
+		return nil, errors.WithMessage(err, "setting the hash of the issuer public key failed")
+	}
+	if err := recoverIdemix(ipk.Check); err != nil {
+		return nil, err
+	}
+
+	expected := []string{idemixAttributeNameOU, idemixAttributeNameRole, idemixAttributeNameEnrollmentID, idemixAttributeNameRevocationHandle}
+	if len(ipk.AttributeNames) != len(expected) {
+		return nil, errors.New("issuer public key must have attributes OU, Role, EnrollmentID and RevocationHandle")
+	}
+	for i, name := range expected {
+		if ipk.AttributeNames[i] != name {
+			return nil, errors.New("issuer public key must have attributes OU, Role, EnrollmentID and RevocationHandle")
+		}
+	}
+	return ipk, nil
+}
+
+func newRevocationPublicKey(raw []byte) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("failed to decode revocation public key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse revocation public key")
+	}
+	ecdsaKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.Errorf("expected an ECDSA revocation public key, got %T", key)
+	}
+	return ecdsaKey, nil
+}
+
+// GetVersion returns the version of this MSP
+func (m *idemixMSP) GetVersion() msp.MSPVersion {
+	return msp.MSPv1_1
+}
+
+// GetType returns the provider type
+func (m *idemixMSP) GetType() msp.ProviderType {
+	return msp.IDEMIX
+}
+
+// GetIdentifier returns the provider identifier
+func (m *idemixMSP) GetIdentifier() (string, error) {
+	return m.name, nil
+}
+
+// GetSigningIdentity is not supported by verifying MSPs
+func (m *idemixMSP) GetSigningIdentity(identifier *msp.IdentityIdentifier) (msp.SigningIdentity, error) {
+	return nil, errors.New("no signing identity for idemix MSP")
+}
+
+// GetDefaultSigningIdentity is not supported by verifying MSPs
+func (m *idemixMSP) GetDefaultSigningIdentity() (msp.SigningIdentity, error) {
+	return nil, errors.New("no default signing identity for idemix MSP")
+}
+
+// GetTLSRootCerts returns nil since idemix MSPs have no TLS root certificates
+func (m *idemixMSP) GetTLSRootCerts() [][]byte {
+	return nil
+}
+
+// GetTLSIntermediateCerts returns nil since idemix MSPs have no TLS intermediate certificates
+func (m *idemixMSP) GetTLSIntermediateCerts() [][]byte {
+	return nil
+}
+
+// DeserializeIdentity deserializes an idemix identity of this MSP
+func (m *idemixMSP) DeserializeIdentity(serializedID []byte) (msp.Identity, error) {
+	sID := &mb.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedID, sID); err != nil {
+		return nil, errors.Wrap(err, "could not deserialize a SerializedIdentity")
+	}
+	if sID.Mspid != m.name {
+		return nil, errors.Errorf("expected MSP ID %s, received %s", m.name, sID.Mspid)
+	}
+
+	idemixID := &mb.SerializedIdemixIdentity{}
+	if err := proto.Unmarshal(sID.IdBytes, idemixID); err != nil {
+		return nil, errors.Wrap(err, "could not deserialize a SerializedIdemixIdentity")
+	}
+	if len(idemixID.NymX) == 0 || len(idemixID.NymY) == 0 {
+		return nil, errors.New("idemix identity is missing the pseudonym")
+	}
+	if len(idemixID.NymX) != int(FP256BN.MODBYTES) || len(idemixID.NymY) != int(FP256BN.MODBYTES) {
+		return nil, errors.New("idemix identity has an invalid pseudonym")
+	}
+
+	ou := &mb.OrganizationUnit{}
+	if err := proto.Unmarshal(idemixID.OU, ou); err != nil {
+		return nil, errors.Wrap(err, "cannot deserialize the OU of the identity")
+	}
+	role := &mb.MSPRole{}
+	if err := proto.Unmarshal(idemixID.Role, role); err != nil {
+		return nil, errors.Wrap(err, "cannot deserialize the role of the identity")
+	}
+
+	return &idemixIdentity{
+		msp:          m,
+		serializedID: serializedID,
+		idemixID:     idemixID,
+		nym:          FP256BN.NewECPbigs(FP256BN.FromBytes(idemixID.NymX), FP256BN.FromBytes(idemixID.NymY)),
+		ou:           ou,
+		role:         role,
+	}, nil
+}
+
+// IsWellFormed checks if the given identity can be deserialized into an idemix identity
+func (m *idemixMSP) IsWellFormed(identity *mb.SerializedIdentity) error {
+	idemixID := &mb.SerializedIdemixIdentity{}
+	if err := proto.Unmarshal(identity.IdBytes, idemixID); err != nil {
+		return errors.Wrap(err, "could not deserialize a SerializedIdemixIdentity")
+	}
+	if len(idemixID.NymX) == 0 || len(idemixID.NymY) == 0 {
+		return errors.New("idemix identity is missing the pseudonym")
+	}
+	return nil
+}
+
+// Validate verifies the proof of the identity that its pseudonym was derived from a credential
+// of the issuer with the disclosed OU and role
+func (m *idemixMSP) Validate(id msp.Identity) error {
+	identity, ok := id.(*idemixIdentity)
+	if !ok {
+		return errors.Errorf("identity type %T is not recognized", id)
+	}
+	if identity.msp != m {
+		return errors.New("the supplied identity does not belong to this msp")
+	}
+
+	sig := &idemix.Signature{}
+	if err := proto.Unmarshal(identity.idemixID.Proof, sig); err != nil {
+		return errors.Wrap(err, "could not deserialize the proof of the identity")
+	}
+
+	// The proof is only bound to the identity by the pseudonym it was created for
+	if sig.Nym == nil || !identity.nym.Equals(idemix.EcpFromProto(sig.Nym)) {
+		return errors.New("the proof of the identity is for a different pseudonym")
+	}
+
+	attributeValues := []*FP256BN.BIG{
+		idemix.HashModOrder([]byte(identity.ou.OrganizationalUnitIdentifier)),
+		FP256BN.NewBIGint(idemixRoleFromMSPRole(identity.role.Role)),
+		nil,
+		nil,
+	}
+	return recoverIdemix(func() error {
+		return sig.Ver(idemixDisclosure, m.ipk, nil, attributeValues, idemixRHIndex, m.revocationPK, m.epoch)
+	})
+}
+
+// SatisfiesPrincipal checks whether the identity matches the given principal
+func (m *idemixMSP) SatisfiesPrincipal(id msp.Identity, principal *mb.MSPPrincipal) error {
+	if err := m.Validate(id); err != nil {
+		return errors.WithMessage(err, "identity is not valid with respect to this MSP")
+	}
+	identity := id.(*idemixIdentity)
+
+	switch principal.PrincipalClassification {
+	case mb.MSPPrincipal_ROLE:
+		mspRole := &mb.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, mspRole); err != nil {
+			return errors.Wrap(err, "could not unmarshal MSPRole from principal")
+		}
+		if mspRole.MspIdentifier != m.name {
+			return errors.Errorf("the identity is a member of a different MSP (expected %s, got %s)", mspRole.MspIdentifier, m.name)
+		}
+
+		switch mspRole.Role {
+		case mb.MSPRole_MEMBER:
+			return nil
+		case mb.MSPRole_ADMIN:
+			if identity.role.Role != mb.MSPRole_ADMIN {
+				return errors.New("user is not an admin")
+			}
+			return nil
+		case mb.MSPRole_CLIENT:
+			// Idemix identities can only be used by clients
+			return nil
+		case mb.MSPRole_PEER:
+			return errors.New("idemix MSP only supports client use, so it cannot satisfy an MSPRole PEER principal")
+		default:
+			return errors.Errorf("invalid MSP role type %d", int32(mspRole.Role))
+		}
+
+	case mb.MSPPrincipal_IDENTITY:
+		if !bytes.Equal(identity.serializedID, principal.Principal) {
+			return errors.New("the identities do not match")
+		}
+		return nil
+
+	case mb.MSPPrincipal_ORGANIZATION_UNIT:
+		ou := &mb.OrganizationUnit{}
+		if err := proto.Unmarshal(principal.Principal, ou); err != nil {
+			return errors.Wrap(err, "could not unmarshal OU from principal")
+		}
+		if ou.MspIdentifier != m.name {
+			return errors.Errorf("the identity is a member of a different MSP (expected %s, got %s)", ou.MspIdentifier, m.name)
+		}
+		if ou.OrganizationalUnitIdentifier != identity.ou.OrganizationalUnitIdentifier {
+			return errors.New("user is not part of the desired organizational unit")
+		}
+		return nil
+
+	default:
+		return errors.Errorf("invalid principal type %d", int32(principal.PrincipalClassification))
+	}
+}
+
+// idemixIdentity is an identity issued by an idemix MSP
+type idemixIdentity struct {
+	msp          *idemixMSP
+	serializedID []byte
+	idemixID     *mb.SerializedIdemixIdentity
+	nym          *FP256BN.ECP
+	ou           *mb.OrganizationUnit
+	role         *mb.MSPRole
+}
+
+// ExpiresAt returns the zero time since idemix identities have no expiry
+func (id *idemixIdentity) ExpiresAt() time.Time {
+	return time.Time{}
+}
+
+// GetIdentifier returns the identifier of the identity
+func (id *idemixIdentity) GetIdentifier() *msp.IdentityIdentifier {
+	return &msp.IdentityIdentifier{Mspid: id.msp.name, Id: string(append(append([]byte{}, id.idemixID.NymX...), id.idemixID.NymY...))}
+}
+
+// GetMSPIdentifier returns the MSP ID of the identity
+func (id *idemixIdentity) GetMSPIdentifier() string {
+	return id.msp.name
+}
+
+// Validate verifies the proof of the identity
+func (id *idemixIdentity) Validate() error {
+	return id.msp.Validate(id)
+}
+
+// GetOrganizationalUnits returns the OU of the identity certified by the issuer public key
+func (id *idemixIdentity) GetOrganizationalUnits() []*msp.OUIdentifier {
+	return []*msp.OUIdentifier{{CertifiersIdentifier: id.msp.ipkBytes, OrganizationalUnitIdentifier: id.ou.OrganizationalUnitIdentifier}}
+}
+
+// Verify verifies the given signature of the message against the pseudonym of the identity
+func (id *idemixIdentity) Verify(msg []byte, sig []byte) error {
+	nymSig := &idemix.NymSignature{}
+	if err := proto.Unmarshal(sig, nymSig); err != nil {
+		return errors.Wrap(err, "could not deserialize the idemix signature")
+	}
+	return recoverIdemix(func() error {
+		return nymSig.Ver(id.nym, id.msp.ipk, msg)
+	})
+}
+
+// Serialize returns the serialized identity
+func (id *idemixIdentity) Serialize() ([]byte, error) {
+	return id.serializedID, nil
+}
+
+// SatisfiesPrincipal checks whether the identity matches the given principal
+func (id *idemixIdentity) SatisfiesPrincipal(principal *mb.MSPPrincipal) error {
+	return id.msp.SatisfiesPrincipal(id, principal)
+}
+
+func idemixRoleFromMSPRole(role mb.MSPRole_MSPRoleType) int {
+	switch role {
+	case mb.MSPRole_ADMIN:
+		return idemixRoleAdmin
+	case mb.MSPRole_CLIENT:
+		return idemixRoleClient
+	case mb.MSPRole_PEER:
+		return idemixRolePeer
+	default:
+		return idemixRoleMember
+	}
+}
+
+// recoverIdemix calls the given idemix function and turns a panic on malformed input into an error
+func recoverIdemix(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("idemix failure [%s]", r)
+		}
+	}()
+	return fn()
+}
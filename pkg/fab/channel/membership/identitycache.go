@@ -0,0 +1,79 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package membership
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"sync"
+	"time"
+
+	"github.com/golang/groupcache/lru"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/msp"
+)
+
+// identityCacheSize is the maximum number of deserialized identities cached per channel membership
+const identityCacheSize = 1000
+
+// identityCache is an LRU cache of deserialized (and possibly validated) identities
+// keyed by the hash of the serialized identity.
+//
+// This component has been designed to be safe for concurrency.
+type identityCache struct {
+	lock  sync.Mutex
+	cache *lru.Cache
+}
+
+type cachedIdentity struct {
+	identity    msp.Identity
+	certificate *x509.Certificate
+	validated   bool
+}
+
+func newIdentityCache(size int) *identityCache {
+	return &identityCache{cache: lru.New(size)}
+}
+
+// get returns the cached identity for the given serialized identity. Identities
+// which have expired since they were cached are removed from the cache.
+func (c *identityCache) get(serializedID []byte) (*cachedIdentity, bool) {
+	key := identityKey(serializedID)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	value, ok := c.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	entry := value.(*cachedIdentity)
+	if isExpired(entry.identity) {
+		c.cache.Remove(key)
+		return nil, false
+	}
+	return entry, true
+}
+
+func (c *identityCache) put(serializedID []byte, entry *cachedIdentity) {
+	key := identityKey(serializedID)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.cache.Add(key, entry)
+}
+
+func identityKey(serializedID []byte) string {
+	hash := sha256.Sum256(serializedID)
+	return string(hash[:])
+}
+
+func isExpired(identity msp.Identity) bool {
+	expiresAt := identity.ExpiresAt()
+	return !expiresAt.IsZero() && time.Now().After(expiresAt)
+}
@@ -9,6 +9,8 @@ package membership
 import (
 	"crypto/x509"
 	"encoding/pem"
+	"sort"
+	"sync"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/msp"
@@ -16,6 +18,8 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/certexpiry"
 	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
 	"github.com/pkg/errors"
 )
@@ -24,6 +28,17 @@ var logger = logging.NewLogger("fabsdk/fab")
 
 type identityImpl struct {
 	mspManager msp.MSPManager
+	ordererOUs map[string]*ordererOU
+	mspErr     error
+	mspOnce    sync.Once
+	newManager func() (msp.MSPManager, map[string]*ordererOU, error)
+	identities *identityCache
+	mspInfos   map[string]*fab.MSPInfo
+	idemixMSPs map[string]bool
+	config     fab.EndpointConfig
+	tlsCerts   []*x509.Certificate
+	channelID  string
+	validators *Validators
 }
 
 // Context holds the providers
@@ -32,31 +47,130 @@ type Context struct {
 	EndpointConfig fab.EndpointConfig
 }
 
+// Option is an option of the channel membership
+type Option func(opts *options)
+
+type options struct {
+	lazy       bool
+	validators *Validators
+}
+
+// WithLazyMSPManager defers the setup of the channel's MSPs until an identity is first validated,
+// verified or classified, so that creating the membership of a channel with many organizations
+// doesn't set up MSPs which may never be used. Errors in the MSP configuration are then returned
+// by Validate, Verify and Role instead of New.
+func WithLazyMSPManager() Option {
+	return func(opts *options) {
+		opts.lazy = true
+	}
+}
+
+// WithValidators sets the validators which are invoked, in addition to the MSP validation, when
+// an identity is validated. Validators which are registered later on also apply.
+func WithValidators(validators *Validators) Option {
+	return func(opts *options) {
+		opts.validators = validators
+	}
+}
+
 // New member identity
-func New(ctx Context, cfg fab.ChannelCfg) (fab.ChannelMembership, error) {
-	m, err := createMSPManager(ctx, cfg)
+func New(ctx Context, cfg fab.ChannelCfg, opts ...Option) (fab.ChannelMembership, error) {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	i := &identityImpl{
+		newManager: func() (msp.MSPManager, map[string]*ordererOU, error) { return createMSPManager(ctx, cfg) },
+		identities: newIdentityCache(identityCacheSize),
+		config:     ctx.EndpointConfig,
+		channelID:  cfg.ID(),
+		validators: o.validators,
+	}
+	if !o.lazy {
+		if _, err := i.manager(); err != nil {
+			return nil, err
+		}
+	}
+
+	infos, err := loadMSPInfos(cfg.MSPs())
 	if err != nil {
-		return nil, err
+		return nil, errors.WithMessage(err, "load MSP infos from config failed")
 	}
-	return &identityImpl{mspManager: m}, nil
+	checkCertExpiry(certexpiry.TrackerFor(ctx.EndpointConfig), infos)
+	i.mspInfos = infos
+	i.idemixMSPs = idemixMSPIDs(cfg.MSPs())
+	i.tlsCerts = tlsCertsFromInfos(infos)
+
+	return i, nil
+}
+
+// manager returns the MSP manager of the channel, which is set up on first use
+func (i *identityImpl) manager() (msp.MSPManager, error) {
+	i.mspOnce.Do(func() {
+		i.mspManager, i.ordererOUs, i.mspErr = i.newManager()
+		i.newManager = nil
+	})
+	return i.mspManager, i.mspErr
 }
 
 func (i *identityImpl) Validate(serializedID []byte) error {
-	err := areCertDatesValid(serializedID)
+	// Only the MSP validation is cached, the validators are invoked on every validation
+	if entry, ok := i.identities.get(serializedID); ok && entry.validated {
+		return i.runValidators(entry.identity, serializedID, entry.certificate)
+	}
+
+	sID := &mb.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedID, sID); err != nil {
+		return errors.Wrap(err, "could not deserialize a SerializedIdentity")
+	}
+
+	// Idemix identities have no certificate, the dates of X.509 certificates are checked
+	// before the identity is deserialized
+	var cert *x509.Certificate
+	if !i.idemixMSPs[sID.Mspid] {
+		var err error
+		cert, err = areCertDatesValid(certexpiry.TrackerFor(i.config), sID)
+		if err != nil {
+			logger.Errorf("Cert error %v", err)
+			return err
+		}
+	}
+
+	id, err := i.deserialize(serializedID)
 	if err != nil {
-		logger.Errorf("Cert error %v", err)
 		return err
 	}
 
-	id, err := i.mspManager.DeserializeIdentity(serializedID)
-	if err != nil {
+	if err := i.validate(id, serializedID); err != nil {
 		return err
 	}
-	return id.Validate()
+
+	i.identities.put(serializedID, &cachedIdentity{identity: id, certificate: cert, validated: true})
+	return i.runValidators(id, serializedID, cert)
+}
+
+// runValidators invokes the validators of the membership for the given identity
+func (i *identityImpl) runValidators(id msp.Identity, serializedID []byte, cert *x509.Certificate) error {
+	return i.validators.run(&IdentityInfo{ChannelID: i.channelID, MSPID: id.GetMSPIdentifier(), SerializedID: serializedID, Certificate: cert})
+}
+
+// validate validates the given identity with its MSP. Identities which carry the orderer OU
+// are validated without enforcing the client and peer NodeOUs.
+func (i *identityImpl) validate(id msp.Identity, serializedID []byte) error {
+	err := id.Validate()
+	if err == nil {
+		return nil
+	}
+
+	if ou, ok := i.ordererOUs[id.GetMSPIdentifier()]; ok && ou.matches(id) {
+		return ou.validate(serializedID)
+	}
+	return err
 }
 
 func (i *identityImpl) Verify(serializedID []byte, msg []byte, sig []byte) error {
-	id, err := i.mspManager.DeserializeIdentity(serializedID)
+	id, err := i.deserialize(serializedID)
 	if err != nil {
 		return err
 	}
@@ -64,102 +178,414 @@ func (i *identityImpl) Verify(serializedID []byte, msg []byte, sig []byte) error
 	return id.Verify(msg, sig)
 }
 
-func areCertDatesValid(serializedID []byte) error {
+// Role validates the given ID and returns its role as classified by the MSP which issued it.
+// Peer and client roles are only assigned if NodeOUs are enabled in the MSP configuration and
+// the channel has V1_1 (or later) capabilities. The orderer role is only assigned if the channel
+// has V1_4_3 (or later) capabilities and the orderer OU is configured.
+func (i *identityImpl) Role(serializedID []byte) (fab.MemberRole, error) {
+	if err := i.Validate(serializedID); err != nil {
+		return "", err
+	}
 
-	sID := &mb.SerializedIdentity{}
-	err := proto.Unmarshal(serializedID, sID)
+	id, err := i.deserialize(serializedID)
 	if err != nil {
-		return errors.Wrap(err, "could not deserialize a SerializedIdentity")
+		return "", err
+	}
+
+	mspManager, err := i.manager()
+	if err != nil {
+		return "", err
+	}
+	msps, err := mspManager.GetMSPs()
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to get MSPs")
+	}
+	mspID := id.GetMSPIdentifier()
+	m, ok := msps[mspID]
+	if !ok {
+		return "", errors.Errorf("MSP [%s] not found", mspID)
+	}
+
+	for _, r := range memberRoles {
+		if r.role == fab.MemberRoleOrderer {
+			if ou, ok := i.ordererOUs[mspID]; ok && ou.matches(id) {
+				return r.role, nil
+			}
+			continue
+		}
+
+		principal, err := rolePrincipal(mspID, r.mspRole)
+		if err != nil {
+			return "", err
+		}
+		if err := m.SatisfiesPrincipal(id, principal); err == nil {
+			return r.role, nil
+		}
+	}
+	return fab.MemberRoleMember, nil
+}
+
+// memberRoles are the roles checked (in order) when classifying an identity. The pinned MSP
+// protos have no orderer role so orderers are classified by the orderer OU.
+var memberRoles = []struct {
+	mspRole mb.MSPRole_MSPRoleType
+	role    fab.MemberRole
+}{
+	{mspRole: mb.MSPRole_ADMIN, role: fab.MemberRoleAdmin},
+	{role: fab.MemberRoleOrderer},
+	{mspRole: mb.MSPRole_PEER, role: fab.MemberRolePeer},
+	{mspRole: mb.MSPRole_CLIENT, role: fab.MemberRoleClient},
+}
+
+func rolePrincipal(mspID string, role mb.MSPRole_MSPRoleType) (*mb.MSPPrincipal, error) {
+	roleBytes, err := proto.Marshal(&mb.MSPRole{MspIdentifier: mspID, Role: role})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal MSPRole failed")
+	}
+	return &mb.MSPPrincipal{PrincipalClassification: mb.MSPPrincipal_ROLE, Principal: roleBytes}, nil
+}
+
+// MSPIDs returns the sorted IDs of the channel's MSPs
+func (i *identityImpl) MSPIDs() ([]string, error) {
+	mspIDs := make([]string, 0, len(i.mspInfos))
+	for mspID := range i.mspInfos {
+		mspIDs = append(mspIDs, mspID)
+	}
+	sort.Strings(mspIDs)
+	return mspIDs, nil
+}
+
+// MSPInfo returns a copy of the certificates of the given MSP
+func (i *identityImpl) MSPInfo(mspID string) (*fab.MSPInfo, error) {
+	info, ok := i.mspInfos[mspID]
+	if !ok {
+		return nil, errors.Errorf("MSP [%s] not found", mspID)
 	}
 
+	return &fab.MSPInfo{
+		ID:                   info.ID,
+		RootCerts:            copyCerts(info.RootCerts),
+		IntermediateCerts:    copyCerts(info.IntermediateCerts),
+		TLSRootCerts:         copyCerts(info.TLSRootCerts),
+		TLSIntermediateCerts: copyCerts(info.TLSIntermediateCerts),
+		AdminCerts:           copyCerts(info.AdminCerts),
+	}, nil
+}
+
+// TLSCACertPool returns a new cert pool which contains the configured TLS root certificates, the
+// TLS root and intermediate certificates of the channel's MSPs and the given certificates.
+// The certificates of the channel's MSPs are not added to the configured cert pool so that
+// they can't authorize connections on other channels.
+func (i *identityImpl) TLSCACertPool(certs ...*x509.Certificate) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	if i.config != nil {
+		pool = i.config.TLSCACertPool()
+	}
+
+	for _, cert := range i.tlsCerts {
+		pool.AddCert(cert)
+	}
+	for _, cert := range certs {
+		if cert != nil {
+			pool.AddCert(cert)
+		}
+	}
+	return pool, nil
+}
+
+// deserialize returns the deserialized identity from the cache or, if not cached,
+// deserializes the identity and adds it to the cache
+func (i *identityImpl) deserialize(serializedID []byte) (msp.Identity, error) {
+	if entry, ok := i.identities.get(serializedID); ok {
+		return entry.identity, nil
+	}
+
+	mspManager, err := i.manager()
+	if err != nil {
+		return nil, err
+	}
+	id, err := mspManager.DeserializeIdentity(serializedID)
+	if err != nil {
+		return nil, err
+	}
+
+	i.identities.put(serializedID, &cachedIdentity{identity: id})
+	return id, nil
+}
+
+// areCertDatesValid validates the dates of the certificate of the given identity and returns the certificate.
+// The certificate is reported to the given tracker if it is about to expire.
+func areCertDatesValid(tracker *certexpiry.Tracker, sID *mb.SerializedIdentity) (*x509.Certificate, error) {
+
 	bl, _ := pem.Decode(sID.IdBytes)
 	if bl == nil {
-		return errors.New("could not decode the PEM structure")
+		return nil, errors.New("could not decode the PEM structure")
 	}
 	cert, err := x509.ParseCertificate(bl.Bytes)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	tracker.Check(certexpiry.MembershipSource, sID.Mspid, cert)
 	err = verifier.ValidateCertificateDates(cert)
 	if err != nil {
 		logger.Warnf("Certificate error '%v' for cert '%v'", err, cert.SerialNumber)
-		return err
+		return nil, err
 	}
-	return nil
+	return cert, nil
 }
 
-func createMSPManager(ctx Context, cfg fab.ChannelCfg) (msp.MSPManager, error) {
+func createMSPManager(ctx Context, cfg fab.ChannelCfg) (msp.MSPManager, map[string]*ordererOU, error) {
 	mspManager := msp.NewMSPManager()
+	var ordererOUs map[string]*ordererOU
 	if len(cfg.MSPs()) > 0 {
-		msps, err := loadMSPs(cfg.MSPs(), ctx.CryptoSuite())
+		crls, err := externalCRLs(ctx.EndpointConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		version := mspVersion(cfg)
+		msps, err := loadMSPs(cfg.MSPs(), version, crls, ctx.CryptoSuite())
 		if err != nil {
-			return nil, errors.WithMessage(err, "load MSPs from config failed")
+			return nil, nil, errors.WithMessage(err, "load MSPs from config failed")
 		}
 
 		if err := mspManager.Setup(msps); err != nil {
-			return nil, errors.WithMessage(err, "MSPManager Setup failed")
+			return nil, nil, errors.WithMessage(err, "MSPManager Setup failed")
 		}
 
-		for _, msp := range msps {
-			for _, cert := range msp.GetTLSRootCerts() {
-				addCertsToConfig(ctx.EndpointConfig, cert)
+		if ordererOUsSupported(cfg) {
+			ordererOUs, err = loadOrdererOUs(cfg.MSPs(), version, crls, ctx.CryptoSuite())
+			if err != nil {
+				return nil, nil, errors.WithMessage(err, "load orderer OUs from config failed")
 			}
+		}
+	}
+
+	return mspManager, ordererOUs, nil
+}
 
-			for _, cert := range msp.GetTLSIntermediateCerts() {
-				addCertsToConfig(ctx.EndpointConfig, cert)
+// externalCRLs returns the certificate revocation lists configured for organizations in the
+// network config (in addition to the CRLs in the channel config) mapped by MSP ID
+func externalCRLs(config fab.EndpointConfig) (map[string][][]byte, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	networkConfig, err := config.NetworkConfig()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get network config")
+	}
+	if networkConfig == nil {
+		return nil, nil
+	}
+
+	crls := make(map[string][][]byte)
+	for orgName, org := range networkConfig.Organizations {
+		for _, crl := range org.RevocationList {
+			crlBytes, err := crl.Bytes()
+			if err != nil {
+				return nil, errors.WithMessage(err, "failed to load revocation list")
 			}
+			if len(crlBytes) == 0 {
+				continue
+			}
+			logger.Debugf("Loaded external revocation list for organization [%s], MSP [%s]", orgName, org.MSPID)
+			crls[org.MSPID] = append(crls[org.MSPID], crlBytes)
 		}
 	}
-
-	return mspManager, nil
+	return crls, nil
 }
 
-func loadMSPs(mspConfigs []*mb.MSPConfig, cs core.CryptoSuite) ([]msp.MSP, error) {
-	logger.Debugf("loadMSPs - start number of msps=%d", len(mspConfigs))
+func loadMSPs(mspConfigs []*mb.MSPConfig, version msp.MSPVersion, crls map[string][][]byte, cs core.CryptoSuite) ([]msp.MSP, error) {
+	logger.Debugf("loadMSPs - start number of msps=%d, MSP version=%d", len(mspConfigs), version)
+
+	// The MSPs are set up concurrently since setting up an MSP (parsing and validating its
+	// certificates and CRLs) is expensive and channels may have many organizations
+	msps := make([]msp.MSP, len(mspConfigs))
+	errs := make([]error, len(mspConfigs))
+	var wg sync.WaitGroup
+	for i, config := range mspConfigs {
+		wg.Add(1)
+		go func(i int, config *mb.MSPConfig) {
+			defer wg.Done()
+			msps[i], errs[i] = loadMSP(config, version, crls, cs)
+		}(i, config)
+	}
+	wg.Wait()
 
-	msps := []msp.MSP{}
-	for _, config := range mspConfigs {
-		mspType := msp.ProviderType(config.Type)
-		if mspType != msp.FABRIC {
-			return nil, errors.Errorf("MSP type not supported: %v", mspType)
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
 		}
-		if len(config.Config) == 0 {
-			return nil, errors.Errorf("MSP configuration missing the payload in the 'Config' property")
+	}
+
+	logger.Debugf("loadMSPs - loaded %d MSPs", len(msps))
+	return msps, nil
+}
+
+// loadMSP returns the MSP set up with the given config
+func loadMSP(config *mb.MSPConfig, version msp.MSPVersion, crls map[string][][]byte, cs core.CryptoSuite) (msp.MSP, error) {
+	if len(config.Config) == 0 {
+		return nil, errors.Errorf("MSP configuration missing the payload in the 'Config' property")
+	}
+
+	mspType := msp.ProviderType(config.Type)
+	if mspType == msp.IDEMIX {
+		newMSP := newIdemixMSP()
+		if err := newMSP.Setup(config); err != nil {
+			return nil, errors.Wrap(err, "configure idemix MSP failed")
 		}
+		logger.Debugf("loadMSPs - adding idemix msp=%s", newMSP.name)
+		return newMSP, nil
+	}
+	if mspType != msp.FABRIC {
+		return nil, errors.Errorf("MSP type not supported: %v", mspType)
+	}
 
-		fabricConfig, err := getFabricConfig(config)
+	fabricConfig, err := getFabricConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if extraCRLs, ok := crls[fabricConfig.Name]; ok {
+		config, err = withRevocationList(config, fabricConfig, extraCRLs)
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		// get the application org names
-		orgUnits := fabricConfig.OrganizationalUnitIdentifiers
-		for _, orgUnit := range orgUnits {
-			logger.Debugf("loadMSPs - found org of :: %s", orgUnit.OrganizationalUnitIdentifier)
+	// get the application org names
+	orgUnits := fabricConfig.OrganizationalUnitIdentifiers
+	for _, orgUnit := range orgUnits {
+		logger.Debugf("loadMSPs - found org of :: %s", orgUnit.OrganizationalUnitIdentifier)
+	}
+
+	// TODO: Do something with orgs
+	newMSP, err := msp.NewBccspMsp(version, cs)
+	if err != nil {
+		return nil, errors.Wrap(err, "instantiate MSP failed")
+	}
+
+	if err := newMSP.Setup(config); err != nil {
+		return nil, errors.Wrap(err, "configure MSP failed")
+	}
+
+	mspID, err1 := newMSP.GetIdentifier()
+	if err1 != nil {
+		return nil, errors.Wrap(err1, "failed to get identifier")
+	}
+	logger.Debugf("loadMSPs - adding msp=%s", mspID)
+
+	return newMSP, nil
+}
+
+// loadMSPInfos returns the certificates of the given MSPs mapped by MSP ID. Idemix MSPs
+// have no certificates.
+func loadMSPInfos(mspConfigs []*mb.MSPConfig) (map[string]*fab.MSPInfo, error) {
+	infos := make(map[string]*fab.MSPInfo)
+	for _, config := range mspConfigs {
+		if msp.ProviderType(config.Type) == msp.IDEMIX {
+			idemixConfig := &mb.IdemixMSPConfig{}
+			if err := proto.Unmarshal(config.Config, idemixConfig); err != nil {
+				return nil, errors.Wrap(err, "unmarshal IdemixMSPConfig from config failed")
+			}
+			infos[idemixConfig.Name] = &fab.MSPInfo{ID: idemixConfig.Name}
+			continue
 		}
 
-		// TODO: Do something with orgs
-		// TODO: Configure MSP version (rather than MSP 1.0)
-		newMSP, err := msp.NewBccspMsp(msp.MSPv1_0, cs)
+		fabricConfig, err := getFabricConfig(config)
 		if err != nil {
-			return nil, errors.Wrap(err, "instantiate MSP failed")
+			return nil, err
 		}
-
-		if err := newMSP.Setup(config); err != nil {
-			return nil, errors.Wrap(err, "configure MSP failed")
+		infos[fabricConfig.Name] = &fab.MSPInfo{
+			ID:                   fabricConfig.Name,
+			RootCerts:            fabricConfig.RootCerts,
+			IntermediateCerts:    fabricConfig.IntermediateCerts,
+			TLSRootCerts:         fabricConfig.TlsRootCerts,
+			TLSIntermediateCerts: fabricConfig.TlsIntermediateCerts,
+			AdminCerts:           fabricConfig.Admins,
 		}
+	}
+	return infos, nil
+}
 
-		mspID, err1 := newMSP.GetIdentifier()
-		if err1 != nil {
-			return nil, errors.Wrap(err1, "failed to get identifier")
+// idemixMSPIDs returns the IDs of the idemix MSPs among the given MSPs
+func idemixMSPIDs(mspConfigs []*mb.MSPConfig) map[string]bool {
+	ids := make(map[string]bool)
+	for _, config := range mspConfigs {
+		if msp.ProviderType(config.Type) != msp.IDEMIX {
+			continue
+		}
+		idemixConfig := &mb.IdemixMSPConfig{}
+		if err := proto.Unmarshal(config.Config, idemixConfig); err == nil {
+			ids[idemixConfig.Name] = true
 		}
-		logger.Debugf("loadMSPs - adding msp=%s", mspID)
+	}
+	return ids
+}
 
-		msps = append(msps, newMSP)
+func copyCerts(certs [][]byte) [][]byte {
+	if certs == nil {
+		return nil
+	}
+	copied := make([][]byte, len(certs))
+	for i, cert := range certs {
+		copied[i] = append([]byte(nil), cert...)
 	}
+	return copied
+}
 
-	logger.Debugf("loadMSPs - loaded %d MSPs", len(msps))
-	return msps, nil
+// withRevocationList returns a copy of the given MSP config which includes the given
+// revocation lists in addition to the revocation lists of the channel config
+func withRevocationList(config *mb.MSPConfig, fabricConfig *mb.FabricMSPConfig, crls [][]byte) (*mb.MSPConfig, error) {
+	logger.Debugf("loadMSPs - adding %d external revocation lists to msp=%s", len(crls), fabricConfig.Name)
+
+	fabricConfig.RevocationList = append(fabricConfig.RevocationList, crls...)
+	fabricConfigBytes, err := proto.Marshal(fabricConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal FabricMSPConfig failed")
+	}
+	return &mb.MSPConfig{Type: config.Type, Config: fabricConfigBytes}, nil
+}
+
+// channelMSPVersions maps (from the highest level down) the channel capabilities to the MSP
+// version which Fabric uses on channels with these capabilities
+var channelMSPVersions = []struct {
+	capability string
+	version    string
+}{
+	{capability: fab.V1_4_3Capability, version: "MSPv1_4_3"},
+	{capability: fab.V1_3Capability, version: "MSPv1_3"},
+	{capability: fab.V1_1Capability, version: "MSPv1_1"},
+}
+
+// supportedMSPVersions are the MSP versions implemented by the MSP package
+var supportedMSPVersions = map[string]msp.MSPVersion{
+	"MSPv1_0": msp.MSPv1_0,
+	"MSPv1_1": msp.MSPv1_1,
+}
+
+// channelMSPVersion returns the name of the MSP version which Fabric uses on the given channel
+func channelMSPVersion(cfg fab.ChannelCfg) string {
+	for _, v := range channelMSPVersions {
+		if chconfig.IsCapabilitySupported(cfg, fab.ChannelGroupKey, v.capability) {
+			return v.version
+		}
+	}
+	return "MSPv1_0"
+}
+
+// mspVersion returns the MSP version that corresponds to the capabilities of the channel.
+// Channels with V1_1 (or later) capabilities use MSPv1_1 so that NodeOU validation rules apply.
+// MSPv1_3 (V1_3 capabilities) and MSPv1_4_3 (V1_4_3 capabilities) are not implemented by the
+// MSP package, so channels with these capabilities fall back to MSPv1_1 and a warning is logged.
+func mspVersion(cfg fab.ChannelCfg) msp.MSPVersion {
+	required := channelMSPVersion(cfg)
+	if version, ok := supportedMSPVersions[required]; ok {
+		return version
+	}
+	logger.Warnf("The capabilities of channel [%s] require %s which is not supported - using MSPv1_1, the validation rules of later MSP versions are not applied", cfg.ID(), required)
+	return msp.MSPv1_1
 }
 
 func getFabricConfig(config *mb.MSPConfig) (*mb.FabricMSPConfig, error) {
@@ -184,8 +610,34 @@ func getFabricConfig(config *mb.MSPConfig) (*mb.FabricMSPConfig, error) {
 	return fabricConfig, nil
 }
 
-//addCertsToConfig adds cert bytes to config TLSCACertPool
-func addCertsToConfig(config fab.EndpointConfig, pemCerts []byte) {
+// checkCertExpiry reports the certificates of the given MSPs which are about to expire to the given tracker
+func checkCertExpiry(tracker *certexpiry.Tracker, infos map[string]*fab.MSPInfo) {
+	for mspID, info := range infos {
+		for _, certs := range [][][]byte{info.RootCerts, info.IntermediateCerts, info.TLSRootCerts, info.TLSIntermediateCerts, info.AdminCerts} {
+			for _, pemCerts := range certs {
+				tracker.CheckPEM(certexpiry.MembershipSource, mspID, pemCerts)
+			}
+		}
+	}
+}
+
+// tlsCertsFromInfos returns the parsed TLS root and intermediate certificates of the given MSPs
+func tlsCertsFromInfos(infos map[string]*fab.MSPInfo) []*x509.Certificate {
+	var certs []*x509.Certificate
+	for _, info := range infos {
+		for _, pemCerts := range info.TLSRootCerts {
+			certs = append(certs, parseCerts(pemCerts)...)
+		}
+		for _, pemCerts := range info.TLSIntermediateCerts {
+			certs = append(certs, parseCerts(pemCerts)...)
+		}
+	}
+	return certs
+}
+
+// parseCerts returns the certificates in the given PEM bytes
+func parseCerts(pemCerts []byte) []*x509.Certificate {
+	var certs []*x509.Certificate
 	for len(pemCerts) > 0 {
 		var block *pem.Block
 		block, pemCerts = pem.Decode(pemCerts)
@@ -204,6 +656,7 @@ func addCertsToConfig(config fab.EndpointConfig, pemCerts []byte) {
 		if err != nil {
 			logger.Warn("%v", err)
 		}
-		_ = config.TLSCACertPool(cert)
+		certs = append(certs, cert)
 	}
+	return certs
 }
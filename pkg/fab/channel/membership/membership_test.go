@@ -15,6 +15,7 @@ import (
 	"encoding/asn1"
 	"log"
 	"math/big"
+	"net"
 	"strings"
 	"testing"
 	"time"
@@ -24,6 +25,13 @@ import (
 	"encoding/pem"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-amcl/amcl"
+	"github.com/hyperledger/fabric-amcl/amcl/FP256BN"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/idemix"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
 	"github.com/stretchr/testify/assert"
@@ -82,6 +90,54 @@ func TestRevokedCertificate(t *testing.T) {
 
 }
 
+func TestExternalRevocationList(t *testing.T) {
+	mspID := "GoodMSP"
+	ctx := mocks.NewMockProviderContext()
+
+	config := &mockNetworkConfig{
+		EndpointConfig: mocks.NewMockEndpointConfig(),
+		networkConfig: &fab.NetworkConfig{
+			Organizations: map[string]fab.OrganizationConfig{
+				"org2": {MSPID: mspID, RevocationList: []endpoint.TLSConfig{{Pem: newCRL}}},
+			},
+		},
+	}
+	crls, err := externalCRLs(config)
+	assert.Nil(t, err)
+	assert.Len(t, crls[mspID], 1)
+
+	// MSP config without revocation list
+	fabricConfig := buildfabricMSPConfig(mspID, []byte(orgTwoCA))
+	fabricConfig.RevocationList = nil
+	mspConfigs := []*mb.MSPConfig{{Config: marshalOrPanic(fabricConfig)}}
+
+	revoked, err := proto.Marshal(&mb.SerializedIdentity{Mspid: mspID, IdBytes: []byte(org2RevokedCert)})
+	assert.Nil(t, err)
+
+	msps, err := loadMSPs(mspConfigs, msp.MSPv1_0, nil, ctx.CryptoSuite())
+	assert.Nil(t, err)
+	id, err := msps[0].DeserializeIdentity(revoked)
+	assert.Nil(t, err)
+	assert.Nil(t, id.Validate(), "expected certificate not to be revoked without revocation list")
+
+	msps, err = loadMSPs(mspConfigs, msp.MSPv1_0, crls, ctx.CryptoSuite())
+	assert.Nil(t, err)
+	id, err = msps[0].DeserializeIdentity(revoked)
+	assert.Nil(t, err)
+	err = id.Validate()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "The certificate has been revoked")
+}
+
+type mockNetworkConfig struct {
+	fab.EndpointConfig
+	networkConfig *fab.NetworkConfig
+}
+
+func (c *mockNetworkConfig) NetworkConfig() (*fab.NetworkConfig, error) {
+	return c.networkConfig, nil
+}
+
 //TestExpiredCertificate
 func TestCertificateDates(t *testing.T) {
 	var err error
@@ -118,6 +174,65 @@ func TestCertificateDates(t *testing.T) {
 	}
 }
 
+func TestMSPVersion(t *testing.T) {
+	tests := []struct {
+		name         string
+		capabilities map[fab.ConfigGroupKey]map[string]bool
+		required     string
+		expected     msp.MSPVersion
+	}{
+		{"no capabilities", nil, "MSPv1_0", msp.MSPv1_0},
+		{"application capabilities only", map[fab.ConfigGroupKey]map[string]bool{fab.ApplicationGroupKey: {fab.V1_1Capability: true}}, "MSPv1_0", msp.MSPv1_0},
+		{"unknown capability", map[fab.ConfigGroupKey]map[string]bool{fab.ChannelGroupKey: {"V0_9": true}}, "MSPv1_0", msp.MSPv1_0},
+		{fab.V1_1Capability, map[fab.ConfigGroupKey]map[string]bool{fab.ChannelGroupKey: {fab.V1_1Capability: true}}, "MSPv1_1", msp.MSPv1_1},
+		{fab.V1_2Capability, map[fab.ConfigGroupKey]map[string]bool{fab.ChannelGroupKey: {fab.V1_2Capability: true}}, "MSPv1_1", msp.MSPv1_1},
+		{fab.V1_3Capability, map[fab.ConfigGroupKey]map[string]bool{fab.ChannelGroupKey: {fab.V1_3Capability: true}}, "MSPv1_3", msp.MSPv1_1},
+		{fab.V1_4_2Capability, map[fab.ConfigGroupKey]map[string]bool{fab.ChannelGroupKey: {fab.V1_4_2Capability: true}}, "MSPv1_3", msp.MSPv1_1},
+		{fab.V1_4_3Capability, map[fab.ConfigGroupKey]map[string]bool{fab.ChannelGroupKey: {fab.V1_4_3Capability: true}}, "MSPv1_4_3", msp.MSPv1_1},
+		{fab.V2_0Capability, map[fab.ConfigGroupKey]map[string]bool{fab.ChannelGroupKey: {fab.V2_0Capability: true}}, "MSPv1_4_3", msp.MSPv1_1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := mocks.NewMockChannelCfg("")
+			cfg.MockCapabilities = test.capabilities
+			assert.Equal(t, test.required, channelMSPVersion(cfg))
+			assert.Equal(t, test.expected, mspVersion(cfg))
+		})
+	}
+}
+
+// TestMSPVersionOfMembership validates that the MSPs of the channel are set up with the MSP
+// version of each capability level
+func TestMSPVersionOfMembership(t *testing.T) {
+	tests := []struct {
+		capability string
+		expected   msp.MSPVersion
+	}{
+		{"", msp.MSPv1_0},
+		{fab.V1_1Capability, msp.MSPv1_1},
+		{fab.V1_3Capability, msp.MSPv1_1},
+		{fab.V1_4_3Capability, msp.MSPv1_1},
+	}
+
+	ctx := mocks.NewMockProviderContext()
+	for _, test := range tests {
+		t.Run("capability "+test.capability, func(t *testing.T) {
+			cfg := mocks.NewMockChannelCfg("")
+			if test.capability != "" {
+				cfg.MockCapabilities = map[fab.ConfigGroupKey]map[string]bool{fab.ChannelGroupKey: {test.capability: true}}
+			}
+			cfg.MockMSPs = []*mb.MSPConfig{buildMSPConfig("GoodMSP", []byte(validRootCA))}
+			m, err := New(Context{Providers: ctx}, cfg)
+			assert.Nil(t, err)
+
+			msps, err := m.(*identityImpl).mspManager.GetMSPs()
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, msps["GoodMSP"].GetVersion())
+		})
+	}
+}
+
 func TestNewMembership(t *testing.T) {
 	goodMSPID := "GoodMSP"
 	badMSPID := "BadMSP"
@@ -153,6 +268,58 @@ func TestNewMembership(t *testing.T) {
 	assert.NotNil(t, m.Verify(badEndorser, []byte("test"), []byte("test1")))
 }
 
+func TestLoadMSPs(t *testing.T) {
+	ctx := mocks.NewMockProviderContext()
+
+	var configs []*mb.MSPConfig
+	for i := 0; i < 30; i++ {
+		configs = append(configs, buildMSPConfig(fmt.Sprintf("Org%dMSP", i), []byte(validRootCA)))
+	}
+	msps, err := loadMSPs(configs, msp.MSPv1_0, nil, ctx.CryptoSuite())
+	assert.Nil(t, err)
+	assert.Len(t, msps, len(configs))
+	for i, m := range msps {
+		mspID, err := m.GetIdentifier()
+		assert.Nil(t, err)
+		assert.Equal(t, fmt.Sprintf("Org%dMSP", i), mspID, "MSPs should be returned in the order of their configs")
+	}
+
+	configs[10] = buildMSPConfig("BadMSP", []byte("invalid"))
+	_, err = loadMSPs(configs, msp.MSPv1_0, nil, ctx.CryptoSuite())
+	assert.NotNil(t, err)
+}
+
+func TestLazyMSPManager(t *testing.T) {
+	mspID := "GoodMSP"
+	ctx := mocks.NewMockProviderContext()
+	cfg := mocks.NewMockChannelCfg("")
+
+	endorser, err := proto.Marshal(&mb.SerializedIdentity{Mspid: mspID, IdBytes: []byte(certPem)})
+	assert.Nil(t, err)
+
+	cfg.MockMSPs = []*mb.MSPConfig{buildMSPConfig(mspID, []byte(validRootCA))}
+	m, err := New(Context{Providers: ctx}, cfg, WithLazyMSPManager())
+	assert.Nil(t, err)
+	assert.Nil(t, m.(*identityImpl).mspManager, "MSPs shouldn't be set up before first use")
+
+	mspIDs, err := m.MSPIDs()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{mspID}, mspIDs)
+	assert.Nil(t, m.(*identityImpl).mspManager, "MSPs aren't needed for the MSP IDs")
+
+	assert.Nil(t, m.Validate(endorser))
+	assert.NotNil(t, m.(*identityImpl).mspManager)
+
+	// Invalid MSP configs are reported on first use
+	cfg.MockMSPs = []*mb.MSPConfig{buildMSPConfig(mspID, []byte("invalid"))}
+	m, err = New(Context{Providers: ctx}, cfg, WithLazyMSPManager())
+	assert.Nil(t, err)
+	assert.NotNil(t, m.Validate(endorser))
+	assert.NotNil(t, m.Verify(endorser, []byte("test"), []byte("test1")))
+	_, err = m.Role(endorser)
+	assert.NotNil(t, err)
+}
+
 func buildMSPConfig(name string, root []byte) *mb.MSPConfig {
 	return &mb.MSPConfig{
 		Type:   0,
@@ -361,3 +528,508 @@ func generateSelfSignedCert(t *testing.T, now time.Time) string {
 	return encodeCertToMemory(newCert)
 
 }
+
+func TestIdentityCache(t *testing.T) {
+	mspID := "GoodMSP"
+
+	ctx := mocks.NewMockProviderContext()
+	cfg := mocks.NewMockChannelCfg("")
+	cfg.MockMSPs = []*mb.MSPConfig{buildMSPConfig(mspID, []byte(validRootCA))}
+	m, err := New(Context{Providers: ctx}, cfg)
+	assert.Nil(t, err)
+
+	endorser, err := proto.Marshal(&mb.SerializedIdentity{Mspid: mspID, IdBytes: []byte(certPem)})
+	assert.Nil(t, err)
+
+	// Count the deserializations by the MSP manager to tell cache hits from misses
+	impl := m.(*identityImpl)
+	mspManager := &countingMSPManager{MSPManager: impl.mspManager}
+	impl.mspManager = mspManager
+
+	_, ok := impl.identities.get(endorser)
+	assert.False(t, ok, "expected identity not to be cached")
+
+	_, err = impl.deserialize(endorser)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, mspManager.deserialized, "expected a cache miss")
+	entry, ok := impl.identities.get(endorser)
+	assert.True(t, ok, "expected deserialized identity to be cached")
+	assert.False(t, entry.validated)
+
+	assert.Nil(t, m.Verify(endorser, []byte("message"), []byte("signature")))
+	assert.Equal(t, 1, mspManager.deserialized, "expected verification to hit the cache")
+
+	assert.Nil(t, m.Validate(endorser))
+	assert.Equal(t, 1, mspManager.deserialized, "expected validation to hit the cache")
+	entry, ok = impl.identities.get(endorser)
+	assert.True(t, ok, "expected validated identity to be cached")
+	assert.True(t, entry.validated)
+
+	// Validation is served from the cache
+	assert.Nil(t, m.Validate(endorser))
+	assert.Equal(t, 1, mspManager.deserialized, "expected validation to hit the cache")
+
+	// Other identities miss the cache and identities which fail deserialization are not cached
+	other, err := proto.Marshal(&mb.SerializedIdentity{Mspid: mspID, IdBytes: []byte(generateSelfSignedCert(t, time.Now()))})
+	assert.Nil(t, err)
+	assert.NotNil(t, m.Validate(other))
+	assert.Equal(t, 2, mspManager.deserialized, "expected a cache miss")
+	_, ok = impl.identities.get(other)
+	assert.False(t, ok, "expected invalid identity not to be cached")
+}
+
+// countingMSPManager counts the identities deserialized by the wrapped MSP manager
+type countingMSPManager struct {
+	msp.MSPManager
+	deserialized int
+}
+
+func (m *countingMSPManager) DeserializeIdentity(serializedID []byte) (msp.Identity, error) {
+	m.deserialized++
+	return m.MSPManager.DeserializeIdentity(serializedID)
+}
+
+func TestRole(t *testing.T) {
+	mspID := "GoodMSP"
+	ctx := mocks.NewMockProviderContext()
+	cfg := mocks.NewMockChannelCfg("")
+
+	endorser, err := proto.Marshal(&mb.SerializedIdentity{Mspid: mspID, IdBytes: []byte(certPem)})
+	assert.Nil(t, err)
+
+	// Without NodeOUs identities are only classified as members or admins
+	cfg.MockMSPs = []*mb.MSPConfig{buildMSPConfig(mspID, []byte(validRootCA))}
+	m, err := New(Context{Providers: ctx}, cfg)
+	assert.Nil(t, err)
+	role, err := m.Role(endorser)
+	assert.Nil(t, err)
+	assert.Equal(t, fab.MemberRoleMember, role)
+
+	fabricConfig := buildfabricMSPConfig(mspID, []byte(validRootCA))
+	fabricConfig.Admins = [][]byte{[]byte(certPem)}
+	cfg.MockMSPs = []*mb.MSPConfig{{Config: marshalOrPanic(fabricConfig)}}
+	m, err = New(Context{Providers: ctx}, cfg)
+	assert.Nil(t, err)
+	role, err = m.Role(endorser)
+	assert.Nil(t, err)
+	assert.Equal(t, fab.MemberRoleAdmin, role)
+
+	// NodeOUs are only honored on channels with V1_1 (or later) capabilities
+	caCert, caKey := generateCACert(t)
+	fabricConfig = buildfabricMSPConfig(mspID, []byte(caCert))
+	fabricConfig.RevocationList = nil
+	fabricConfig.FabricNodeOUs = &mb.FabricNodeOUs{
+		Enable:             true,
+		ClientOUIdentifier: &mb.FabricOUIdentifier{OrganizationalUnitIdentifier: "client"},
+		PeerOUIdentifier:   &mb.FabricOUIdentifier{OrganizationalUnitIdentifier: "peer"},
+	}
+	cfg.MockMSPs = []*mb.MSPConfig{{Config: marshalOrPanic(fabricConfig)}}
+	m, err = New(Context{Providers: ctx}, cfg)
+	assert.Nil(t, err)
+
+	peerID, err := proto.Marshal(&mb.SerializedIdentity{Mspid: mspID, IdBytes: []byte(generateSignedCert(t, caCert, caKey, "peer"))})
+	assert.Nil(t, err)
+	role, err = m.Role(peerID)
+	assert.Nil(t, err)
+	assert.Equal(t, fab.MemberRoleMember, role)
+
+	cfg.MockCapabilities = map[fab.ConfigGroupKey]map[string]bool{fab.ChannelGroupKey: {fab.V1_1Capability: true}}
+	m, err = New(Context{Providers: ctx}, cfg)
+	assert.Nil(t, err)
+
+	for ou, expected := range map[string]fab.MemberRole{"peer": fab.MemberRolePeer, "client": fab.MemberRoleClient} {
+		id, err := proto.Marshal(&mb.SerializedIdentity{Mspid: mspID, IdBytes: []byte(generateSignedCert(t, caCert, caKey, ou))})
+		assert.Nil(t, err)
+		role, err := m.Role(id)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, role)
+	}
+
+	// Identities without a NodeOU are invalid
+	id, err := proto.Marshal(&mb.SerializedIdentity{Mspid: mspID, IdBytes: []byte(generateSignedCert(t, caCert, caKey, "other"))})
+	assert.Nil(t, err)
+	assert.NotNil(t, m.Validate(id))
+	_, err = m.Role(id)
+	assert.NotNil(t, err)
+}
+
+func TestOrdererRole(t *testing.T) {
+	mspID := "GoodMSP"
+	ctx := mocks.NewMockProviderContext()
+	cfg := mocks.NewMockChannelCfg("")
+
+	caCert, caKey := generateCACert(t)
+	fabricConfig := buildfabricMSPConfig(mspID, []byte(caCert))
+	fabricConfig.RevocationList = nil
+	fabricConfig.FabricNodeOUs = &mb.FabricNodeOUs{
+		Enable:             true,
+		ClientOUIdentifier: &mb.FabricOUIdentifier{OrganizationalUnitIdentifier: "client"},
+		PeerOUIdentifier:   &mb.FabricOUIdentifier{OrganizationalUnitIdentifier: "peer"},
+	}
+
+	// The orderer OU identifier isn't known to the pinned protos so it's merged into the config
+	ordererOU := marshalOrPanic(&fabricMSPConfigNodeOUs{FabricNodeOUs: &fabricNodeOUs{
+		Enable:              true,
+		OrdererOUIdentifier: &mb.FabricOUIdentifier{OrganizationalUnitIdentifier: "orderer"},
+	}})
+	cfg.MockMSPs = []*mb.MSPConfig{{Config: append(marshalOrPanic(fabricConfig), ordererOU...)}}
+
+	ordererID, err := proto.Marshal(&mb.SerializedIdentity{Mspid: mspID, IdBytes: []byte(generateSignedCert(t, caCert, caKey, "orderer"))})
+	assert.Nil(t, err)
+
+	// The orderer OU is only honored on channels with V1_4_3 (or later) capabilities
+	cfg.MockCapabilities = map[fab.ConfigGroupKey]map[string]bool{fab.ChannelGroupKey: {fab.V1_4_2Capability: true}}
+	m, err := New(Context{Providers: ctx}, cfg)
+	assert.Nil(t, err)
+	assert.NotNil(t, m.Validate(ordererID))
+
+	cfg.MockCapabilities = map[fab.ConfigGroupKey]map[string]bool{fab.ChannelGroupKey: {fab.V1_4_3Capability: true}}
+	m, err = New(Context{Providers: ctx}, cfg)
+	assert.Nil(t, err)
+	role, err := m.Role(ordererID)
+	assert.Nil(t, err)
+	assert.Equal(t, fab.MemberRoleOrderer, role)
+
+	peerID, err := proto.Marshal(&mb.SerializedIdentity{Mspid: mspID, IdBytes: []byte(generateSignedCert(t, caCert, caKey, "peer"))})
+	assert.Nil(t, err)
+	role, err = m.Role(peerID)
+	assert.Nil(t, err)
+	assert.Equal(t, fab.MemberRolePeer, role)
+
+	// Identities without a NodeOU are still invalid
+	id, err := proto.Marshal(&mb.SerializedIdentity{Mspid: mspID, IdBytes: []byte(generateSignedCert(t, caCert, caKey, "other"))})
+	assert.Nil(t, err)
+	assert.NotNil(t, m.Validate(id))
+}
+
+func generateCACert(t *testing.T) (string, *ecdsa.PrivateKey) {
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca.securekey.com", Organization: []string{"SK"}},
+		NotBefore:             time.Now().Add(-1 * time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		SignatureAlgorithm:    x509.ECDSAWithSHA256,
+		SubjectKeyId:          []byte{1, 2, 3, 4},
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	certRaw, err := x509.CreateCertificate(rand.Reader, &template, &template, &k.PublicKey, k)
+	assert.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certRaw})), k
+}
+
+func generateSignedCert(t *testing.T, caCert string, caKey *ecdsa.PrivateKey, ou string) string {
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	bl, _ := pem.Decode([]byte(caCert))
+	parent, err := x509.ParseCertificate(bl.Bytes)
+	assert.NoError(t, err)
+
+	template := x509.Certificate{
+		SerialNumber:       big.NewInt(2),
+		Subject:            pkix.Name{CommonName: ou + ".securekey.com", OrganizationalUnit: []string{ou}},
+		NotBefore:          time.Now().Add(-1 * time.Hour),
+		NotAfter:           time.Now().Add(24 * time.Hour),
+		SignatureAlgorithm: x509.ECDSAWithSHA256,
+		KeyUsage:           x509.KeyUsageDigitalSignature,
+		AuthorityKeyId:     parent.SubjectKeyId,
+	}
+	certRaw, err := x509.CreateCertificate(rand.Reader, &template, parent, &k.PublicKey, caKey)
+	assert.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certRaw}))
+}
+
+func TestIdemixMSP(t *testing.T) {
+	fabricMSPID := "GoodMSP"
+	idemixMSPID := "IdemixMSP"
+
+	ctx := mocks.NewMockProviderContext()
+	cfg := mocks.NewMockChannelCfg("")
+
+	// Idemix MSP without issuer public key
+	idemixConfig := &mb.IdemixMSPConfig{Name: idemixMSPID}
+	cfg.MockMSPs = []*mb.MSPConfig{{Type: int32(msp.IDEMIX), Config: marshalOrPanic(idemixConfig)}}
+	_, err := New(Context{Providers: ctx}, cfg)
+	assert.NotNil(t, err)
+
+	// Idemix MSP with invalid issuer public key
+	idemixConfig.IPk = []byte("issuer public key")
+	cfg.MockMSPs = []*mb.MSPConfig{{Type: int32(msp.IDEMIX), Config: marshalOrPanic(idemixConfig)}}
+	_, err = New(Context{Providers: ctx}, cfg)
+	assert.NotNil(t, err)
+
+	issuer := newTestIdemixIssuer(t, idemixMSPID)
+	cfg.MockMSPs = []*mb.MSPConfig{
+		buildMSPConfig(fabricMSPID, []byte(validRootCA)),
+		issuer.mspConfig(t),
+	}
+	m, err := New(Context{Providers: ctx}, cfg)
+	assert.Nil(t, err)
+
+	endorser, err := proto.Marshal(&mb.SerializedIdentity{Mspid: fabricMSPID, IdBytes: []byte(certPem)})
+	assert.Nil(t, err)
+	assert.Nil(t, m.Validate(endorser))
+
+	user := issuer.newUser(t, "org1.department1", mb.MSPRole_MEMBER)
+	idemixID := user.serialize(t, "org1.department1", mb.MSPRole_MEMBER)
+	id, err := m.(*identityImpl).deserialize(idemixID)
+	assert.Nil(t, err)
+	assert.Equal(t, idemixMSPID, id.GetMSPIdentifier())
+
+	assert.Nil(t, m.Validate(idemixID))
+	assert.Nil(t, m.Verify(idemixID, []byte("message"), user.sign(t, []byte("message"))))
+	assert.NotNil(t, m.Verify(idemixID, []byte("other message"), user.sign(t, []byte("message"))))
+	assert.NotNil(t, m.Verify(idemixID, []byte("message"), []byte("signature")))
+
+	// Idemix identities can only be used by clients
+	role, err := m.Role(idemixID)
+	assert.Nil(t, err)
+	assert.Equal(t, fab.MemberRoleClient, role)
+
+	admin := issuer.newUser(t, "org1.department1", mb.MSPRole_ADMIN)
+	role, err = m.Role(admin.serialize(t, "org1.department1", mb.MSPRole_ADMIN))
+	assert.Nil(t, err)
+	assert.Equal(t, fab.MemberRoleAdmin, role)
+
+	// Identities which claim an OU or role their credential wasn't issued for are invalid
+	assert.NotNil(t, m.Validate(user.serialize(t, "org1.department2", mb.MSPRole_MEMBER)))
+	assert.NotNil(t, m.Validate(user.serialize(t, "org1.department1", mb.MSPRole_ADMIN)))
+
+	// The proof of an identity can't be used for another pseudonym
+	other := issuer.newUser(t, "org1.department1", mb.MSPRole_MEMBER)
+	other.proof = user.proof
+	assert.NotNil(t, m.Validate(other.serialize(t, "org1.department1", mb.MSPRole_MEMBER)))
+
+	// Identities of another issuer are invalid
+	otherIssuer := newTestIdemixIssuer(t, idemixMSPID)
+	assert.NotNil(t, m.Validate(otherIssuer.newUser(t, "org1.department1", mb.MSPRole_MEMBER).serialize(t, "org1.department1", mb.MSPRole_MEMBER)))
+
+	// Idemix identity without pseudonym
+	idBytes := marshalOrPanic(&mb.SerializedIdemixIdentity{Proof: []byte("proof")})
+	idemixID, err = proto.Marshal(&mb.SerializedIdentity{Mspid: idemixMSPID, IdBytes: idBytes})
+	assert.Nil(t, err)
+	assert.NotNil(t, m.Validate(idemixID))
+}
+
+type testIdemixIssuer struct {
+	mspID         string
+	key           *idemix.IssuerKey
+	revocationKey *ecdsa.PrivateKey
+	rng           *amcl.RAND
+}
+
+func newTestIdemixIssuer(t *testing.T, mspID string) *testIdemixIssuer {
+	rng, err := idemix.GetRand()
+	assert.NoError(t, err)
+	key, err := idemix.NewIssuerKey([]string{"OU", "Role", "EnrollmentID", "RevocationHandle"}, rng)
+	assert.NoError(t, err)
+	revocationKey, err := idemix.GenerateLongTermRevocationKey()
+	assert.NoError(t, err)
+	return &testIdemixIssuer{mspID: mspID, key: key, revocationKey: revocationKey, rng: rng}
+}
+
+func (i *testIdemixIssuer) mspConfig(t *testing.T) *mb.MSPConfig {
+	revocationPK, err := x509.MarshalPKIXPublicKey(&i.revocationKey.PublicKey)
+	assert.NoError(t, err)
+	config := &idemixMSPConfig{
+		Name:         i.mspID,
+		IPk:          marshalOrPanic(i.key.Ipk),
+		RevocationPk: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: revocationPK}),
+	}
+	return &mb.MSPConfig{Type: int32(msp.IDEMIX), Config: marshalOrPanic(config)}
+}
+
+type testIdemixUser struct {
+	issuer *testIdemixIssuer
+	sk     *FP256BN.BIG
+	nym    *FP256BN.ECP
+	rNym   *FP256BN.BIG
+	proof  []byte
+}
+
+// newUser issues a credential with the given OU and role and creates the proof of the identity
+func (i *testIdemixIssuer) newUser(t *testing.T, ou string, role mb.MSPRole_MSPRoleType) *testIdemixUser {
+	sk := idemix.RandModOrder(i.rng)
+	credRequest := idemix.NewCredRequest(sk, idemix.BigToBytes(idemix.RandModOrder(i.rng)), i.key.Ipk, i.rng)
+	attrs := []*FP256BN.BIG{
+		idemix.HashModOrder([]byte(ou)),
+		FP256BN.NewBIGint(idemixRoleFromMSPRole(role)),
+		idemix.HashModOrder([]byte("enrollmentID")),
+		idemix.RandModOrder(i.rng),
+	}
+	cred, err := idemix.NewCredential(i.key, credRequest, attrs, i.rng)
+	assert.NoError(t, err)
+
+	cri, err := idemix.CreateCRI(i.revocationKey, nil, 0, idemix.ALG_NO_REVOCATION, i.rng)
+	assert.NoError(t, err)
+
+	nym, rNym := idemix.MakeNym(sk, i.key.Ipk, i.rng)
+	sig, err := idemix.NewSignature(cred, sk, nym, rNym, i.key.Ipk, idemixDisclosure, nil, idemixRHIndex, cri, i.rng)
+	assert.NoError(t, err)
+
+	return &testIdemixUser{issuer: i, sk: sk, nym: nym, rNym: rNym, proof: marshalOrPanic(sig)}
+}
+
+// serialize returns the serialized identity of the user claiming the given OU and role
+func (u *testIdemixUser) serialize(t *testing.T, ou string, role mb.MSPRole_MSPRoleType) []byte {
+	idBytes := marshalOrPanic(&mb.SerializedIdemixIdentity{
+		NymX:  idemix.BigToBytes(u.nym.GetX()),
+		NymY:  idemix.BigToBytes(u.nym.GetY()),
+		OU:    marshalOrPanic(&mb.OrganizationUnit{MspIdentifier: u.issuer.mspID, OrganizationalUnitIdentifier: ou}),
+		Role:  marshalOrPanic(&mb.MSPRole{MspIdentifier: u.issuer.mspID, Role: role}),
+		Proof: u.proof,
+	})
+	serializedID, err := proto.Marshal(&mb.SerializedIdentity{Mspid: u.issuer.mspID, IdBytes: idBytes})
+	assert.NoError(t, err)
+	return serializedID
+}
+
+func (u *testIdemixUser) sign(t *testing.T, msg []byte) []byte {
+	sig, err := idemix.NewNymSignature(u.sk, u.nym, u.rNym, u.issuer.key.Ipk, msg, u.issuer.rng)
+	assert.NoError(t, err)
+	return marshalOrPanic(sig)
+}
+
+func TestMSPInfo(t *testing.T) {
+	ctx := mocks.NewMockProviderContext()
+	cfg := mocks.NewMockChannelCfg("")
+
+	fabricConfig := buildfabricMSPConfig("Org1MSP", []byte(validRootCA))
+	fabricConfig.Admins = [][]byte{[]byte(certPem)}
+	fabricConfig.TlsRootCerts = [][]byte{[]byte(validRootCA)}
+	cfg.MockMSPs = []*mb.MSPConfig{
+		{Config: marshalOrPanic(fabricConfig)},
+		buildMSPConfig("Org0MSP", []byte(validRootCA)),
+		newTestIdemixIssuer(t, "IdemixMSP").mspConfig(t),
+	}
+	m, err := New(Context{Providers: ctx}, cfg)
+	assert.Nil(t, err)
+
+	mspIDs, err := m.MSPIDs()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"IdemixMSP", "Org0MSP", "Org1MSP"}, mspIDs)
+
+	info, err := m.MSPInfo("Org1MSP")
+	assert.Nil(t, err)
+	assert.Equal(t, "Org1MSP", info.ID)
+	assert.Equal(t, [][]byte{[]byte(validRootCA)}, info.RootCerts)
+	assert.Equal(t, [][]byte{[]byte(validRootCA)}, info.TLSRootCerts)
+	assert.Equal(t, [][]byte{[]byte(certPem)}, info.AdminCerts)
+	assert.Empty(t, info.IntermediateCerts)
+
+	// Modifying the returned info doesn't affect the membership
+	info.RootCerts[0][0] = 'x'
+	info, err = m.MSPInfo("Org1MSP")
+	assert.Nil(t, err)
+	assert.Equal(t, [][]byte{[]byte(validRootCA)}, info.RootCerts)
+
+	info, err = m.MSPInfo("IdemixMSP")
+	assert.Nil(t, err)
+	assert.Empty(t, info.RootCerts)
+
+	_, err = m.MSPInfo("UnknownMSP")
+	assert.NotNil(t, err)
+}
+
+func TestTLSCACertPool(t *testing.T) {
+	ctx := mocks.NewMockProviderContext()
+	cfg := mocks.NewMockChannelCfg("")
+	config := mocks.NewMockEndpointConfig()
+
+	fabricConfig := buildfabricMSPConfig("Org1MSP", []byte(validRootCA))
+	fabricConfig.TlsRootCerts = [][]byte{[]byte(validRootCA)}
+	cfg.MockMSPs = []*mb.MSPConfig{{Config: marshalOrPanic(fabricConfig)}}
+	m, err := New(Context{Providers: ctx, EndpointConfig: config}, cfg)
+	assert.Nil(t, err)
+
+	rootCA := parseCerts([]byte(validRootCA))
+	assert.Len(t, rootCA, 1)
+
+	pool, err := m.TLSCACertPool()
+	assert.Nil(t, err)
+	assert.Contains(t, pool.Subjects(), rootCA[0].RawSubject)
+
+	// The certs of the channel are only added to the channel's pool
+	assert.NotContains(t, config.TLSCACertPool().Subjects(), rootCA[0].RawSubject)
+
+	// The channel endpoint config uses the pool of the channel
+	chConfig := NewChannelEndpointConfig(config, m)
+	assert.Contains(t, chConfig.TLSCACertPool().Subjects(), rootCA[0].RawSubject)
+
+	// The custom dialers of the configured endpoint config are used for the channel
+	dialerConfig := &dialerEndpointConfig{EndpointConfig: config}
+	dialerConfig.RegisterDialer("test", func(address string, timeout time.Duration) (net.Conn, error) { return nil, nil })
+	_, ok := NewChannelEndpointConfig(dialerConfig, m).(comm.DialerProvider).Dialer("test")
+	assert.True(t, ok)
+	_, ok = chConfig.(comm.DialerProvider).Dialer("test")
+	assert.False(t, ok)
+
+	// Channels without TLS certs only trust the configured certs
+	cfg.MockMSPs = []*mb.MSPConfig{buildMSPConfig("Org2MSP", []byte(validRootCA))}
+	m, err = New(Context{Providers: ctx, EndpointConfig: config}, cfg)
+	assert.Nil(t, err)
+	pool, err = m.TLSCACertPool()
+	assert.Nil(t, err)
+	assert.NotContains(t, pool.Subjects(), rootCA[0].RawSubject)
+}
+
+type dialerEndpointConfig struct {
+	fab.EndpointConfig
+	comm.Dialers
+}
+
+func TestValidators(t *testing.T) {
+	mspID := "GoodMSP"
+
+	ctx := mocks.NewMockProviderContext()
+	cfg := mocks.NewMockChannelCfg("mychannel")
+	cfg.MockMSPs = []*mb.MSPConfig{buildMSPConfig(mspID, []byte(validRootCA))}
+
+	endorser, err := proto.Marshal(&mb.SerializedIdentity{Mspid: mspID, IdBytes: []byte(certPem)})
+	assert.Nil(t, err)
+
+	var validated []*IdentityInfo
+	validators := NewValidators()
+	validators.Register("test", func(identity *IdentityInfo) error {
+		validated = append(validated, identity)
+		return nil
+	})
+
+	m, err := New(Context{Providers: ctx}, cfg, WithValidators(validators))
+	assert.Nil(t, err)
+	assert.Nil(t, m.Validate(endorser))
+	assert.Len(t, validated, 1)
+	assert.Equal(t, "mychannel", validated[0].ChannelID)
+	assert.Equal(t, mspID, validated[0].MSPID)
+	assert.NotNil(t, validated[0].Certificate)
+	assert.Equal(t, "peer0.org1.example.com", validated[0].Certificate.Subject.CommonName)
+
+	// Validators are invoked for identities whose MSP validation is cached
+	assert.Nil(t, m.Validate(endorser))
+	assert.Len(t, validated, 2)
+	assert.Equal(t, validated[0], validated[1])
+
+	// Validators registered later on apply to existing memberships
+	validators.Register("deny", func(identity *IdentityInfo) error {
+		return fmt.Errorf("identity not allowed")
+	})
+	err = m.Validate(endorser)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "identity rejected by validator [deny]")
+
+	validators.Unregister("deny")
+	assert.Nil(t, m.Validate(endorser))
+
+	// Validators only apply to the memberships they are set for
+	m, err = New(Context{Providers: ctx}, cfg)
+	assert.Nil(t, err)
+	validators.Register("deny", func(identity *IdentityInfo) error {
+		return fmt.Errorf("identity not allowed")
+	})
+	assert.Nil(t, m.Validate(endorser))
+}
@@ -0,0 +1,137 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package membership
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/pem"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+)
+
+// fabricNodeOUs is FabricNodeOUs including the admin and orderer OU identifiers (fields 4 and 5)
+// which were added in Fabric 1.4.3 and which aren't included in the pinned protos
+type fabricNodeOUs struct {
+	Enable              bool                   `protobuf:"varint,1,opt,name=Enable"`
+	ClientOUIdentifier  *mb.FabricOUIdentifier `protobuf:"bytes,2,opt,name=clientOUIdentifier"`
+	PeerOUIdentifier    *mb.FabricOUIdentifier `protobuf:"bytes,3,opt,name=peerOUIdentifier"`
+	AdminOUIdentifier   *mb.FabricOUIdentifier `protobuf:"bytes,4,opt,name=adminOUIdentifier"`
+	OrdererOUIdentifier *mb.FabricOUIdentifier `protobuf:"bytes,5,opt,name=ordererOUIdentifier"`
+}
+
+func (m *fabricNodeOUs) Reset()         { *m = fabricNodeOUs{} }
+func (m *fabricNodeOUs) String() string { return proto.CompactTextString(m) }
+func (*fabricNodeOUs) ProtoMessage()    {}
+
+// fabricMSPConfigNodeOUs decodes only the NodeOUs of a FabricMSPConfig
+type fabricMSPConfigNodeOUs struct {
+	FabricNodeOUs *fabricNodeOUs `protobuf:"bytes,11,opt,name=FabricNodeOUs"`
+}
+
+func (m *fabricMSPConfigNodeOUs) Reset()         { *m = fabricMSPConfigNodeOUs{} }
+func (m *fabricMSPConfigNodeOUs) String() string { return proto.CompactTextString(m) }
+func (*fabricMSPConfigNodeOUs) ProtoMessage()    {}
+
+// ordererOU classifies the identities of an MSP which carry the orderer OU. The pinned MSP
+// implementation only knows the client and peer OUs and therefore rejects orderer identities
+// if NodeOUs are enabled, so orderer identities are validated with a copy of the MSP which
+// doesn't enforce NodeOUs.
+type ordererOU struct {
+	identifier          string
+	certifierIdentifier []byte
+	msp                 msp.MSP
+}
+
+// matches returns true if the given identity carries the orderer OU
+func (o *ordererOU) matches(id msp.Identity) bool {
+	for _, ou := range id.GetOrganizationalUnits() {
+		if ou.OrganizationalUnitIdentifier != o.identifier {
+			continue
+		}
+		if len(o.certifierIdentifier) == 0 || bytes.Equal(ou.CertifiersIdentifier, o.certifierIdentifier) {
+			return true
+		}
+	}
+	return false
+}
+
+// validate validates the given serialized identity which carries the orderer OU
+func (o *ordererOU) validate(serializedID []byte) error {
+	id, err := o.msp.DeserializeIdentity(serializedID)
+	if err != nil {
+		return err
+	}
+	return id.Validate()
+}
+
+// ordererOUsSupported returns true if the capabilities of the channel enable the admin and orderer
+// OUs, i.e. if the channel has V1_4_3 (or later) capabilities
+func ordererOUsSupported(cfg fab.ChannelCfg) bool {
+	return chconfig.IsCapabilitySupported(cfg, fab.ChannelGroupKey, fab.V1_4_3Capability)
+}
+
+// loadOrdererOUs returns the orderer OUs of the given MSPs mapped by MSP ID. Only MSPs which have
+// NodeOUs enabled and an orderer OU identifier configured are included.
+func loadOrdererOUs(mspConfigs []*mb.MSPConfig, version msp.MSPVersion, crls map[string][][]byte, cs core.CryptoSuite) (map[string]*ordererOU, error) {
+	ous := make(map[string]*ordererOU)
+	for _, config := range mspConfigs {
+		if msp.ProviderType(config.Type) != msp.FABRIC {
+			continue
+		}
+
+		nodeOUs := &fabricMSPConfigNodeOUs{}
+		if err := proto.Unmarshal(config.Config, nodeOUs); err != nil {
+			return nil, errors.Wrap(err, "unmarshal FabricNodeOUs from config failed")
+		}
+		if nodeOUs.FabricNodeOUs == nil || !nodeOUs.FabricNodeOUs.Enable || nodeOUs.FabricNodeOUs.OrdererOUIdentifier == nil {
+			continue
+		}
+
+		fabricConfig, err := getFabricConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		ou, err := newOrdererOU(config, fabricConfig, nodeOUs.FabricNodeOUs.OrdererOUIdentifier, version, crls, cs)
+		if err != nil {
+			return nil, err
+		}
+		logger.Debugf("loadOrdererOUs - orderer OU [%s] enabled for msp=%s", ou.identifier, fabricConfig.Name)
+		ous[fabricConfig.Name] = ou
+	}
+	return ous, nil
+}
+
+func newOrdererOU(config *mb.MSPConfig, fabricConfig *mb.FabricMSPConfig, identifier *mb.FabricOUIdentifier, version msp.MSPVersion, crls map[string][][]byte, cs core.CryptoSuite) (*ordererOU, error) {
+	ou := &ordererOU{identifier: identifier.OrganizationalUnitIdentifier}
+
+	if len(identifier.Certificate) > 0 {
+		bl, _ := pem.Decode(identifier.Certificate)
+		if bl == nil {
+			return nil, errors.Errorf("invalid certificate of orderer OU identifier of msp=%s", fabricConfig.Name)
+		}
+		hash := sha256.Sum256(bl.Bytes)
+		ou.certifierIdentifier = hash[:]
+	}
+
+	fabricConfig.FabricNodeOUs = nil
+	fabricConfigBytes, err := proto.Marshal(fabricConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal FabricMSPConfig failed")
+	}
+	ou.msp, err = loadMSP(&mb.MSPConfig{Type: config.Type, Config: fabricConfigBytes}, version, crls, cs)
+	if err != nil {
+		return nil, errors.WithMessage(err, "load MSP for orderer OU failed")
+	}
+	return ou, nil
+}
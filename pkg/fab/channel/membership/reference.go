@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package membership
 
 import (
+	"crypto/x509"
+	"sync"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
@@ -19,16 +21,25 @@ type Ref struct {
 	*lazyref.Reference
 	chConfigRef *lazyref.Reference
 	context     Context
+	opts        []Option
 	// Note: the following variables are only accessed from Ref.initializer which is synchronized
 	configBlockNumber uint64
 	mem               fab.ChannelMembership
+
+	listenerLock sync.Mutex
+	listening    bool
+	closed       bool
+	done         chan struct{}
 }
 
-// NewRef returns a new membership reference
-func NewRef(refresh time.Duration, context Context, chConfigRef *lazyref.Reference) *Ref {
+// NewRef returns a new membership reference. The given options are applied to the membership
+// created for each channel config.
+func NewRef(refresh time.Duration, context Context, chConfigRef *lazyref.Reference, opts ...Option) *Ref {
 	ref := &Ref{
 		chConfigRef: chConfigRef,
 		context:     context,
+		opts:        opts,
+		done:        make(chan struct{}),
 	}
 
 	ref.Reference = lazyref.New(
@@ -57,6 +68,42 @@ func (ref *Ref) Verify(serializedID []byte, msg []byte, sig []byte) error {
 	return membership.Verify(serializedID, msg, sig)
 }
 
+// Role calls role on the underlying reference
+func (ref *Ref) Role(serializedID []byte) (fab.MemberRole, error) {
+	membership, err := ref.get()
+	if err != nil {
+		return "", err
+	}
+	return membership.Role(serializedID)
+}
+
+// MSPIDs calls MSPIDs on the underlying reference
+func (ref *Ref) MSPIDs() ([]string, error) {
+	membership, err := ref.get()
+	if err != nil {
+		return nil, err
+	}
+	return membership.MSPIDs()
+}
+
+// MSPInfo calls MSPInfo on the underlying reference
+func (ref *Ref) MSPInfo(mspID string) (*fab.MSPInfo, error) {
+	membership, err := ref.get()
+	if err != nil {
+		return nil, err
+	}
+	return membership.MSPInfo(mspID)
+}
+
+// TLSCACertPool calls TLSCACertPool on the underlying reference
+func (ref *Ref) TLSCACertPool(certs ...*x509.Certificate) (*x509.CertPool, error) {
+	membership, err := ref.get()
+	if err != nil {
+		return nil, err
+	}
+	return membership.TLSCACertPool(certs...)
+}
+
 func (ref *Ref) get() (fab.ChannelMembership, error) {
 	m, err := ref.Get()
 	if err != nil {
@@ -83,7 +130,7 @@ func (ref *Ref) initializer() lazyref.Initializer {
 		// Membership is refreshed only if we have a newer config block
 		if ref.mem == nil || cfg.BlockNumber() > ref.configBlockNumber {
 			logger.Debugf("Creating membership for channel [%s]...", cfg.ID())
-			ref.mem, err = New(ref.context, cfg)
+			ref.mem, err = New(ref.context, cfg, ref.opts...)
 			if err != nil {
 				return nil, err
 			}
@@ -0,0 +1,95 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package membership
+
+import (
+	"crypto/x509"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// IdentityInfo contains the identity passed to validators
+type IdentityInfo struct {
+	ChannelID    string
+	MSPID        string
+	SerializedID []byte
+	// Certificate is the X.509 certificate of the identity (nil for idemix identities)
+	Certificate *x509.Certificate
+}
+
+// Validator performs additional validation (e.g. OCSP checks, attribute policies or allow-lists)
+// of an identity which has been successfully validated by the channel's MSPs. Validators are
+// invoked each time an identity is validated, including identities whose MSP validation is
+// cached, so validators which perform expensive checks should cache their results.
+type Validator func(identity *IdentityInfo) error
+
+type namedValidator struct {
+	name      string
+	validator Validator
+}
+
+// Validators holds the identity validators of the channel memberships created by a provider.
+//
+// This component has been designed to be safe for concurrency.
+type Validators struct {
+	lock       sync.RWMutex
+	validators []namedValidator
+}
+
+// NewValidators returns an empty set of validators
+func NewValidators() *Validators {
+	return &Validators{}
+}
+
+// Register registers an identity validator under the given name. Validators are invoked
+// in the order in which they were registered; a validator registered under an existing name
+// replaces that validator.
+func (v *Validators) Register(name string, validator Validator) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	for i, nv := range v.validators {
+		if nv.name == name {
+			v.validators[i].validator = validator
+			return
+		}
+	}
+	v.validators = append(v.validators, namedValidator{name: name, validator: validator})
+}
+
+// Unregister removes the validator registered under the given name
+func (v *Validators) Unregister(name string) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	for i, nv := range v.validators {
+		if nv.name == name {
+			v.validators = append(v.validators[:i], v.validators[i+1:]...)
+			return
+		}
+	}
+}
+
+// run invokes the validators and returns the error of the first one that fails
+func (v *Validators) run(identity *IdentityInfo) error {
+	if v == nil {
+		return nil
+	}
+
+	v.lock.RLock()
+	registered := append([]namedValidator(nil), v.validators...)
+	v.lock.RUnlock()
+
+	for _, nv := range registered {
+		if err := nv.validator(identity); err != nil {
+			logger.Debugf("Identity of MSP [%s] rejected by validator [%s] on channel [%s]: %s", identity.MSPID, nv.name, identity.ChannelID, err)
+			return errors.WithMessage(err, "identity rejected by validator ["+nv.name+"]")
+		}
+	}
+	return nil
+}
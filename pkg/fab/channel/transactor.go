@@ -18,6 +18,7 @@ import (
 
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/bft"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
 )
 
@@ -26,6 +27,7 @@ type Transactor struct {
 	reqCtx    reqContext.Context
 	ChannelID string
 	orderers  []fab.Orderer
+	bft       bool
 }
 
 // NewTransactor returns a Transactor for the current context and channel config.
@@ -49,6 +51,7 @@ func NewTransactor(reqCtx reqContext.Context, cfg fab.ChannelCfg) (*Transactor,
 		reqCtx:    reqCtx,
 		ChannelID: cfg.ID(),
 		orderers:  orderers,
+		bft:       bft.IsBFT(cfg),
 	}
 	return &t, nil
 }
@@ -85,7 +88,7 @@ func orderersFromChannelCfg(ctx context.Client, cfg fab.ChannelCfg) ([]fab.Order
 			logger.Debugf("Created a new OrdererConfig with URL as [%s]", target)
 		}
 
-		o, err := ctx.InfraProvider().CreateOrdererFromConfig(&oCfg)
+		o, err := ctx.InfraProvider().CreateChannelOrdererFromConfig(ctx, cfg.ID(), &oCfg)
 		if err != nil {
 			return nil, errors.WithMessage(err, "failed to create orderer from config")
 		}
@@ -138,6 +141,20 @@ func (t *Transactor) SendTransactionProposal(proposal *fab.TransactionProposal,
 	return txn.SendProposal(reqCtx, proposal, targets)
 }
 
+// SendTransactionProposalUntil sends a TransactionProposal to the target peers and returns as soon as
+// done returns true for the responses received so far, cancelling the proposals still in flight.
+func (t *Transactor) SendTransactionProposalUntil(proposal *fab.TransactionProposal, targets []fab.ProposalProcessor, done fab.ProposalCompletion) ([]*fab.TransactionProposalResponse, error) {
+	ctx, ok := contextImpl.RequestClientContext(t.reqCtx)
+	if !ok {
+		return nil, errors.New("failed get client context from reqContext for SendTransactionProposalUntil")
+	}
+
+	reqCtx, cancel := contextImpl.NewRequest(ctx, contextImpl.WithTimeoutType(fab.PeerResponse), contextImpl.WithParent(t.reqCtx))
+	defer cancel()
+
+	return txn.SendProposalUntil(reqCtx, proposal, targets, done)
+}
+
 // CreateTransaction create a transaction with proposal response.
 // TODO: should this be removed as it is purely a wrapper?
 func (t *Transactor) CreateTransaction(request fab.TransactionRequest) (*fab.Transaction, error) {
@@ -154,5 +171,8 @@ func (t *Transactor) SendTransaction(tx *fab.Transaction) (*fab.TransactionRespo
 	reqCtx, cancel := contextImpl.NewRequest(ctx, contextImpl.WithTimeoutType(fab.OrdererResponse), contextImpl.WithParent(t.reqCtx))
 	defer cancel()
 
+	if t.bft {
+		return txn.SendBFT(reqCtx, tx, t.orderers)
+	}
 	return txn.Send(reqCtx, tx, t.orderers)
 }
@@ -7,7 +7,6 @@ SPDX-License-Identifier: Apache-2.0
 package chconfig
 
 import (
-	"crypto/sha256"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
@@ -35,22 +34,18 @@ type cacheKey struct {
 	pvdr      Provider
 }
 
-// NewCacheKey returns a new CacheKey
+// NewCacheKey returns a new CacheKey. The channel config is the same for all of the members of a
+// channel, so the key is the channel and the MSP of the identity of the context: the clients of an
+// organization (e.g. the resource management, channel and event clients of different users) share
+// the channel config, which is queried with the context of the first client.
 func NewCacheKey(ctx fab.ClientContext, pvdr Provider, channelID string) (CacheKey, error) {
-	identity, err := ctx.Serialize()
-	if err != nil {
-		return nil, err
+	identifier := ctx.Identifier()
+	if identifier == nil {
+		return nil, errors.New("identity of context has no identifier")
 	}
 
-	h := sha256.New()
-	_, err = h.Write(identity)
-	if err != nil {
-		return nil, err
-	}
-	hash := h.Sum([]byte(channelID))
-
 	return &cacheKey{
-		key:       string(hash),
+		key:       channelID + "/" + identifier.MSPID,
 		channelID: channelID,
 		context:   ctx,
 		pvdr:      pvdr,
@@ -59,13 +54,13 @@ func NewCacheKey(ctx fab.ClientContext, pvdr Provider, channelID string) (CacheK
 
 // NewRefCache a cache of channel config references that refreshed with the
 // given interval
-func NewRefCache(refresh time.Duration) *lazycache.Cache {
+func NewRefCache(refresh time.Duration, opts ...RefOption) *lazycache.Cache {
 	initializer := func(key lazycache.Key) (interface{}, error) {
 		ck, ok := key.(CacheKey)
 		if !ok {
 			return nil, errors.New("unexpected cache key")
 		}
-		return NewRef(refresh, ck.Provider(), ck.ChannelID(), ck.Context()), nil
+		return NewRef(refresh, ck.Provider(), ck.ChannelID(), ck.Context(), opts...), nil
 	}
 
 	return lazycache.New("Channel_Cfg_Cache", initializer)
@@ -7,7 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package chconfig
 
 import (
+	reqContext "context"
 	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -67,6 +69,64 @@ func TestChannelConfigCacheBad(t *testing.T) {
 	assert.Contains(t, err.Error(), badProviderErrMessage)
 }
 
+func TestChannelConfigCacheKey(t *testing.T) {
+	key1, err := NewCacheKey(mocks.NewMockContext(mspmocks.NewMockSigningIdentity("user1", "Org1MSP")), mockProvider, "test")
+	assert.Nil(t, err)
+	key2, err := NewCacheKey(mocks.NewMockContext(mspmocks.NewMockSigningIdentity("user2", "Org1MSP")), mockProvider, "test")
+	assert.Nil(t, err)
+	assert.Equal(t, key1.String(), key2.String(), "users of the same organization should share the channel config")
+
+	key3, err := NewCacheKey(mocks.NewMockContext(mspmocks.NewMockSigningIdentity("user1", "Org2MSP")), mockProvider, "test")
+	assert.Nil(t, err)
+	assert.NotEqual(t, key1.String(), key3.String())
+	key4, err := NewCacheKey(mocks.NewMockContext(mspmocks.NewMockSigningIdentity("user1", "Org1MSP")), mockProvider, "other")
+	assert.Nil(t, err)
+	assert.NotEqual(t, key1.String(), key4.String())
+}
+
+func TestChannelConfigMaxStaleness(t *testing.T) {
+	clientCtx := mocks.NewMockContext(mspmocks.NewMockSigningIdentity("user", "user"))
+	chConfig := &failingChannelConfig{}
+	provider := func(channelID string) (fab.ChannelConfig, error) {
+		return chConfig, nil
+	}
+
+	ref := NewRef(time.Hour, provider, "test", clientCtx, WithMaxStaleness(50*time.Millisecond))
+	unboundedRef := NewRef(time.Hour, provider, "test", clientCtx)
+	defer ref.Close()
+	defer unboundedRef.Close()
+
+	_, err := ref.Get()
+	assert.Nil(t, err)
+	_, err = unboundedRef.Get()
+	assert.Nil(t, err)
+
+	atomic.StoreInt32(&chConfig.failing, 1)
+	_, err = ref.Get()
+	assert.Nil(t, err, "channel config isn't stale yet")
+
+	time.Sleep(100 * time.Millisecond)
+	_, err = ref.Get()
+	assert.NotNil(t, err, "expected error for stale channel config")
+	_, err = unboundedRef.Get()
+	assert.Nil(t, err, "stale channel config should be returned without max staleness")
+
+	atomic.StoreInt32(&chConfig.failing, 0)
+	_, err = ref.Get()
+	assert.Nil(t, err)
+}
+
+type failingChannelConfig struct {
+	failing int32
+}
+
+func (c *failingChannelConfig) Query(reqCtx reqContext.Context) (fab.ChannelCfg, error) {
+	if atomic.LoadInt32(&c.failing) == 1 {
+		return nil, fmt.Errorf(badProviderErrMessage)
+	}
+	return mocks.NewMockChannelCfg("test"), nil
+}
+
 type badKey struct {
 	s string
 }
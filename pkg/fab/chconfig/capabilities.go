@@ -0,0 +1,72 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chconfig
+
+import (
+	"sort"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+// capabilityLevels contains the known capability levels in ascending order. Enabling
+// a capability level also enables the features of all of the lower levels.
+var capabilityLevels = []string{
+	fab.V1_1Capability,
+	fab.V1_2Capability,
+	fab.V1_3Capability,
+	fab.V1_4_2Capability,
+	fab.V1_4_3Capability,
+	fab.V2_0Capability,
+}
+
+// IsCapabilitySupported returns true if the features of the given capability level are enabled in the
+// given config group, i.e. if the capability or any higher capability level is enabled.
+func IsCapabilitySupported(cfg fab.ChannelCfg, group fab.ConfigGroupKey, capability string) bool {
+	level := capabilityLevel(capability)
+	if level < 0 {
+		// Not a capability level - it must be enabled explicitly
+		return cfg.HasCapability(group, capability)
+	}
+
+	for _, c := range capabilityLevels[level:] {
+		if cfg.HasCapability(group, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// HighestCapability returns the highest known capability level which is enabled in the
+// given config group or an empty string if no capability levels are enabled.
+func HighestCapability(cfg fab.ChannelCfg, group fab.ConfigGroupKey) string {
+	for i := len(capabilityLevels) - 1; i >= 0; i-- {
+		if cfg.HasCapability(group, capabilityLevels[i]) {
+			return capabilityLevels[i]
+		}
+	}
+	return ""
+}
+
+func capabilityLevel(capability string) int {
+	for i, c := range capabilityLevels {
+		if c == capability {
+			return i
+		}
+	}
+	return -1
+}
+
+func capabilityNames(capabilities map[string]bool) []string {
+	var names []string
+	for capability, enabled := range capabilities {
+		if enabled {
+			names = append(names, capability)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,40 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+func TestCapabilities(t *testing.T) {
+	cfg := &ChannelCfg{
+		capabilities: map[fab.ConfigGroupKey]map[string]bool{
+			fab.ChannelGroupKey:     {fab.V1_3Capability: true},
+			fab.ApplicationGroupKey: {fab.V1_2Capability: true, fab.V1_4_3Capability: true, "CustomCapability": true},
+		},
+	}
+
+	assert.Equal(t, []string{fab.V1_3Capability}, cfg.Capabilities(fab.ChannelGroupKey))
+	assert.Equal(t, []string{"CustomCapability", fab.V1_2Capability, fab.V1_4_3Capability}, cfg.Capabilities(fab.ApplicationGroupKey))
+	assert.Empty(t, cfg.Capabilities(fab.OrdererGroupKey))
+
+	assert.True(t, IsCapabilitySupported(cfg, fab.ChannelGroupKey, fab.V1_1Capability), "expected V1_1 to be implied by V1_3")
+	assert.True(t, IsCapabilitySupported(cfg, fab.ChannelGroupKey, fab.V1_3Capability))
+	assert.False(t, IsCapabilitySupported(cfg, fab.ChannelGroupKey, fab.V1_4_2Capability))
+	assert.True(t, IsCapabilitySupported(cfg, fab.ApplicationGroupKey, fab.V1_4_2Capability))
+	assert.False(t, IsCapabilitySupported(cfg, fab.ApplicationGroupKey, fab.V2_0Capability))
+	assert.True(t, IsCapabilitySupported(cfg, fab.ApplicationGroupKey, "CustomCapability"))
+	assert.False(t, IsCapabilitySupported(cfg, fab.OrdererGroupKey, fab.V1_1Capability))
+
+	assert.Equal(t, fab.V1_3Capability, HighestCapability(cfg, fab.ChannelGroupKey))
+	assert.Equal(t, fab.V1_4_3Capability, HighestCapability(cfg, fab.ApplicationGroupKey))
+	assert.Equal(t, "", HighestCapability(cfg, fab.OrdererGroupKey))
+}
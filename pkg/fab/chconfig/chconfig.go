@@ -9,6 +9,7 @@ package chconfig
 import (
 	reqContext "context"
 	"math/rand"
+	"strings"
 
 	"github.com/golang/protobuf/proto"
 
@@ -24,6 +25,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	ab "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 	"github.com/pkg/errors"
 )
@@ -38,6 +40,9 @@ const (
 	defaultMaxTargets   = 2
 )
 
+// consentersKey is the key of the config value of the Orderer group which lists the consenters of a BFT ordering service
+const consentersKey = "Orderers"
+
 // Opts contains options for retrieving channel configuration
 type Opts struct {
 	Orderer      fab.Orderer // if configured, channel config will be retrieved from this orderer
@@ -64,12 +69,15 @@ type ChannelConfig struct {
 
 // ChannelCfg contains channel configuration
 type ChannelCfg struct {
-	id          string
-	blockNumber uint64
-	msps        []*mb.MSPConfig
-	anchorPeers []*fab.OrgAnchorPeer
-	orderers    []string
-	versions    *fab.Versions
+	id            string
+	blockNumber   uint64
+	msps          []*mb.MSPConfig
+	anchorPeers   []*fab.OrgAnchorPeer
+	orderers      []string
+	versions      *fab.Versions
+	capabilities  map[fab.ConfigGroupKey]map[string]bool
+	consensusType string
+	consenters    []*fab.Consenter
 }
 
 // NewChannelCfg creates channel cfg
@@ -108,6 +116,26 @@ func (cfg *ChannelCfg) Versions() *fab.Versions {
 	return cfg.versions
 }
 
+// HasCapability indicates whether or not the given capability is enabled in the given config group
+func (cfg *ChannelCfg) HasCapability(group fab.ConfigGroupKey, capability string) bool {
+	return cfg.capabilities[group][capability]
+}
+
+// ConsensusType returns the consensus type of the ordering service (e.g. "etcdraft" or "BFT")
+func (cfg *ChannelCfg) ConsensusType() string {
+	return cfg.consensusType
+}
+
+// Consenters returns the consenters of a BFT ordering service (empty for other ordering services)
+func (cfg *ChannelCfg) Consenters() []*fab.Consenter {
+	return cfg.consenters
+}
+
+// Capabilities returns the capabilities which are enabled in the given config group
+func (cfg *ChannelCfg) Capabilities(group fab.ConfigGroupKey) []string {
+	return capabilityNames(cfg.capabilities[group])
+}
+
 // New channel config implementation
 func New(channelID string, options ...Option) (*ChannelConfig, error) {
 	opts, err := prepareOpts(options...)
@@ -162,7 +190,7 @@ func (c *ChannelConfig) queryPeers(reqCtx reqContext.Context) (*ChannelCfg, erro
 		retryHandler = overrideRetryHandler
 	}
 
-	block, err := retry.NewInvoker(retryHandler).Invoke(
+	block, err := retry.NewInvoker(retryHandler, retry.WithContext(reqCtx)).Invoke(
 		func() (interface{}, error) {
 			return l.QueryConfigBlock(reqCtx, targets, &channel.TransactionProposalResponseVerifier{MinResponses: c.opts.MinResponses})
 		},
@@ -249,6 +277,14 @@ func (c *ChannelConfig) resolveMinResponsesOptsFromConfig(chSdkCfg *fab.ChannelN
 func (c *ChannelConfig) resolveRetryOptsFromConfig(chSdkCfg *fab.ChannelNetworkConfig) {
 
 	if c.opts.RetryOpts.RetryableCodes == nil {
+		if chSdkCfg != nil && chSdkCfg.Policies.QueryChannelConfig.RetryPolicy != "" {
+			retryOpts, err := retry.Policy(chSdkCfg.Policies.QueryChannelConfig.RetryPolicy)
+			if err == nil {
+				c.opts.RetryOpts = retryOpts
+				return
+			}
+			logger.Warnf("Using the retry options of the channel config query policy: %s", err)
+		}
 		if chSdkCfg != nil && &chSdkCfg.Policies != nil && &chSdkCfg.Policies.QueryChannelConfig != nil {
 			c.opts.RetryOpts = chSdkCfg.Policies.QueryChannelConfig.RetryOpts
 		}
@@ -343,12 +379,13 @@ func extractConfig(channelID string, block *common.Block) (*ChannelCfg, error) {
 	}
 
 	config := &ChannelCfg{
-		id:          channelID,
-		blockNumber: block.Header.Number,
-		msps:        []*mb.MSPConfig{},
-		anchorPeers: []*fab.OrgAnchorPeer{},
-		orderers:    []string{},
-		versions:    versions,
+		id:           channelID,
+		blockNumber:  block.Header.Number,
+		msps:         []*mb.MSPConfig{},
+		anchorPeers:  []*fab.OrgAnchorPeer{},
+		orderers:     []string{},
+		versions:     versions,
+		capabilities: make(map[fab.ConfigGroupKey]map[string]bool),
 	}
 
 	err = loadConfig(config, config.versions.Channel, group, "base", "")
@@ -532,6 +569,65 @@ func loadOrdererAddressesKey(configValue *common.ConfigValue, configItems *Chann
 
 }
 
+func loadCapabilities(configValue *common.ConfigValue, configItems *ChannelCfg, groupName string) error {
+	capabilities := &common.Capabilities{}
+	err := proto.Unmarshal(configValue.Value, capabilities)
+	if err != nil {
+		return errors.Wrap(err, "unmarshal capabilities from config failed")
+	}
+
+	// The group name is "base" for the channel group or "base.Orderer" and "base.Application" for sub-groups
+	group := fab.ConfigGroupKey(strings.TrimPrefix(strings.TrimPrefix(groupName, "base"), "."))
+
+	logger.Debugf("loadConfigValue - %s   - Capabilities :: %v", groupName, capabilities.Capabilities)
+
+	groupCapabilities := make(map[string]bool)
+	for capability := range capabilities.Capabilities {
+		groupCapabilities[capability] = true
+	}
+	if configItems.capabilities == nil {
+		configItems.capabilities = make(map[fab.ConfigGroupKey]map[string]bool)
+	}
+	configItems.capabilities[group] = groupCapabilities
+	return nil
+}
+
+func loadConsensusType(configValue *common.ConfigValue, configItems *ChannelCfg, groupName string) error {
+	consensusType := &ab.ConsensusType{}
+	err := proto.Unmarshal(configValue.Value, consensusType)
+	if err != nil {
+		return errors.Wrap(err, "unmarshal ConsensusType from config failed")
+	}
+
+	logger.Debugf("loadConfigValue - %s   - Consensus type value :: %s", groupName, consensusType.Type)
+
+	configItems.consensusType = consensusType.Type
+	return nil
+}
+
+func loadConsenters(configValue *common.ConfigValue, configItems *ChannelCfg, groupName string) error {
+	orderers := &consenterMapping{}
+	err := proto.Unmarshal(configValue.Value, orderers)
+	if err != nil {
+		return errors.Wrap(err, "unmarshal consenters from config failed")
+	}
+
+	var consenters []*fab.Consenter
+	for _, c := range orderers.Consenters {
+		logger.Debugf("loadConfigValue - %s   - Consenter :: %d %s %s:%d", groupName, c.ID, c.MSPID, c.Host, c.Port)
+		consenters = append(consenters, &fab.Consenter{
+			ID:       c.ID,
+			Host:     c.Host,
+			Port:     c.Port,
+			MSPID:    c.MSPID,
+			Identity: c.Identity,
+		})
+	}
+
+	configItems.consenters = consenters
+	return nil
+}
+
 func loadConfigValue(configItems *ChannelCfg, key string, versionsValue *common.ConfigValue, configValue *common.ConfigValue, groupName string, org string) error {
 	logger.Debugf("loadConfigValue - %s - START value name: %s", groupName, key)
 	logger.Debugf("loadConfigValue - %s   - version: %d", groupName, configValue.Version)
@@ -548,15 +644,14 @@ func loadConfigValue(configItems *ChannelCfg, key string, versionsValue *common.
 		if err := loadMSPKey(configValue, configItems, groupName); err != nil {
 			return err
 		}
-	//case channelConfig.ConsensusTypeKey:
-	//	consensusType := &ab.ConsensusType{}
-	//	err := proto.Unmarshal(configValue.Value, consensusType)
-	//	if err != nil {
-	//		return errors.Wrap(err, "unmarshal ConsensusType from config failed")
-	//	}
-	//
-	//	logger.Debugf("loadConfigValue - %s   - Consensus type value :: %s", groupName, consensusType.Type)
-	//	// TODO: Do something with this value
+	case channelConfig.ConsensusTypeKey:
+		if err := loadConsensusType(configValue, configItems, groupName); err != nil {
+			return err
+		}
+	case consentersKey:
+		if err := loadConsenters(configValue, configItems, groupName); err != nil {
+			return err
+		}
 	//case channelConfig.BatchSizeKey:
 	//	batchSize := &ab.BatchSize{}
 	//	err := proto.Unmarshal(configValue.Value, batchSize)
@@ -619,6 +714,11 @@ func loadConfigValue(configItems *ChannelCfg, key string, versionsValue *common.
 			return err
 		}
 
+	case channelConfig.CapabilitiesKey:
+		if err := loadCapabilities(configValue, configItems, groupName); err != nil {
+			return err
+		}
+
 	default:
 		logger.Debugf("loadConfigValue - %s   - value: %s", groupName, configValue.Value)
 	}
@@ -646,3 +746,27 @@ func randomMaxTargets(targets []fab.ProposalProcessor, max int) []fab.ProposalPr
 	}
 	return targets[:max]
 }
+
+// consenterMapping is the Orderers config value of the Orderer group in which newer (e.g. BFT) ordering
+// services list their consenters
+type consenterMapping struct {
+	Consenters []*consenter `protobuf:"bytes,1,rep,name=consenter_mapping,json=consenterMapping" json:"consenter_mapping,omitempty"`
+}
+
+func (m *consenterMapping) Reset()         { *m = consenterMapping{} }
+func (m *consenterMapping) String() string { return proto.CompactTextString(m) }
+func (*consenterMapping) ProtoMessage()    {}
+
+type consenter struct {
+	ID            uint32 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	Host          string `protobuf:"bytes,2,opt,name=host" json:"host,omitempty"`
+	Port          uint32 `protobuf:"varint,3,opt,name=port" json:"port,omitempty"`
+	MSPID         string `protobuf:"bytes,4,opt,name=msp_id,json=mspId" json:"msp_id,omitempty"`
+	Identity      []byte `protobuf:"bytes,5,opt,name=identity,proto3" json:"identity,omitempty"`
+	ClientTLSCert []byte `protobuf:"bytes,6,opt,name=client_tls_cert,json=clientTlsCert,proto3" json:"client_tls_cert,omitempty"`
+	ServerTLSCert []byte `protobuf:"bytes,7,opt,name=server_tls_cert,json=serverTlsCert,proto3" json:"server_tls_cert,omitempty"`
+}
+
+func (m *consenter) Reset()         { *m = consenter{} }
+func (m *consenter) String() string { return proto.CompactTextString(m) }
+func (*consenter) ProtoMessage()    {}
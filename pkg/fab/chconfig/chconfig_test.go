@@ -23,6 +23,7 @@ import (
 	"strings"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -51,6 +52,33 @@ func TestChannelConfigWithPeer(t *testing.T) {
 	if cfg.ID() != channelID {
 		t.Fatalf("Channel name error. Expecting %s, got %s ", channelID, cfg.ID())
 	}
+
+	if cfg.ConsensusType() != "sample-Consensus-Type" {
+		t.Fatalf("Consensus type error. Expecting %s, got %s ", "sample-Consensus-Type", cfg.ConsensusType())
+	}
+}
+
+func TestLoadConsenters(t *testing.T) {
+	value, err := proto.Marshal(&consenterMapping{
+		Consenters: []*consenter{
+			{ID: 1, Host: "orderer1.example.com", Port: 7050, MSPID: "OrdererMSP", Identity: []byte("cert1")},
+			{ID: 2, Host: "orderer2.example.com", Port: 7050, MSPID: "OrdererMSP", Identity: []byte("cert2")},
+		},
+	})
+	assert.NoError(t, err)
+
+	cfg := NewChannelCfg(channelID)
+	err = loadConfigValue(cfg, consentersKey, &common.ConfigValue{}, &common.ConfigValue{Value: value}, "base.Orderer", "")
+	assert.NoError(t, err)
+
+	consenters := cfg.Consenters()
+	if assert.Len(t, consenters, 2) {
+		assert.Equal(t, &fab.Consenter{ID: 1, Host: "orderer1.example.com", Port: 7050, MSPID: "OrdererMSP", Identity: []byte("cert1")}, consenters[0])
+		assert.EqualValues(t, 2, consenters[1].ID)
+	}
+
+	err = loadConfigValue(cfg, consentersKey, &common.ConfigValue{}, &common.ConfigValue{Value: []byte("invalid")}, "base.Orderer", "")
+	assert.Error(t, err)
 }
 
 func TestChannelConfigWithPeerWithRetries(t *testing.T) {
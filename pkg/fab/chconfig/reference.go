@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package chconfig
 
 import (
+	"sync/atomic"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
@@ -17,19 +18,38 @@ import (
 
 // Ref channel configuration lazy reference
 type Ref struct {
+	// lastRefreshed is the time (in Unix nanoseconds) at which the channel config was last queried successfully
+	lastRefreshed int64
 	*lazyref.Reference
-	pvdr      Provider
-	ctx       fab.ClientContext
-	channelID string
+	pvdr         Provider
+	ctx          fab.ClientContext
+	channelID    string
+	maxStaleness time.Duration
 }
 
-// NewRef returns a new channel config reference
-func NewRef(refresh time.Duration, pvdr Provider, channel string, ctx fab.ClientContext) *Ref {
+// RefOption is an option of a channel config reference
+type RefOption func(ref *Ref)
+
+// WithMaxStaleness sets the age after which a channel config which couldn't be refreshed is no longer
+// returned by Get, which queries the channel config instead. Zero (the default) means that the last
+// channel config is returned until it's refreshed.
+func WithMaxStaleness(maxStaleness time.Duration) RefOption {
+	return func(ref *Ref) {
+		ref.maxStaleness = maxStaleness
+	}
+}
+
+// NewRef returns a new channel config reference. The channel config is queried when the reference
+// is created and then refreshed in the background with the given interval.
+func NewRef(refresh time.Duration, pvdr Provider, channel string, ctx fab.ClientContext, opts ...RefOption) *Ref {
 	cfgRef := &Ref{
 		pvdr:      pvdr,
 		ctx:       ctx,
 		channelID: channel,
 	}
+	for _, opt := range opts {
+		opt(cfgRef)
+	}
 
 	cfgRef.Reference = lazyref.New(
 		cfgRef.initializer(),
@@ -39,6 +59,23 @@ func NewRef(refresh time.Duration, pvdr Provider, channel string, ctx fab.Client
 	return cfgRef
 }
 
+// Get returns the channel config. If the channel config is older than the maximum staleness then it's
+// queried again, and an error is returned if the query fails.
+func (ref *Ref) Get() (interface{}, error) {
+	if ref.maxStaleness > 0 && ref.isStale() {
+		logger.Debugf("Channel config of [%s] is stale - refreshing", ref.channelID)
+		if err := ref.Reference.Refresh(); err != nil {
+			return nil, errors.WithMessage(err, "channel config of ["+ref.channelID+"] is stale and couldn't be refreshed")
+		}
+	}
+	return ref.Reference.Get()
+}
+
+func (ref *Ref) isStale() bool {
+	lastRefreshed := atomic.LoadInt64(&ref.lastRefreshed)
+	return lastRefreshed != 0 && time.Since(time.Unix(0, lastRefreshed)) > ref.maxStaleness
+}
+
 func (ref *Ref) initializer() lazyref.Initializer {
 	return func() (interface{}, error) {
 		chConfigProvider, err := ref.pvdr(ref.channelID)
@@ -54,6 +91,7 @@ func (ref *Ref) initializer() lazyref.Initializer {
 			return nil, err
 		}
 
+		atomic.StoreInt64(&ref.lastRefreshed, time.Now().UnixNano())
 		return chConfig, nil
 	}
 }
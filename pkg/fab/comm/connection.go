@@ -122,6 +122,33 @@ func newDialOpts(config fab.EndpointConfig, url string, params *params) ([]grpc.
 
 	dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(grpc.FailFast(params.failFast)))
 
+	// A custom dialer takes precedence over the proxy
+	dialerOpt, err := comm.DialerDialOption(config, params.dialer)
+	if err != nil {
+		return nil, err
+	}
+	if dialerOpt != nil {
+		logger.Debugf("Connecting to [%s] with custom dialer", url)
+		dialOpts = append(dialOpts, dialerOpt)
+	} else {
+		proxyOpt, err := comm.ProxyDialOption(url, params.proxy)
+		if err != nil {
+			return nil, err
+		}
+		if proxyOpt != nil {
+			logger.Debugf("Connecting to [%s] through proxy", url)
+			dialOpts = append(dialOpts, proxyOpt)
+		}
+	}
+
+	compressionOpts, err := comm.CompressionDialOptions(params.compression)
+	if err != nil {
+		return nil, err
+	}
+	dialOpts = append(dialOpts, compressionOpts...)
+
+	dialOpts = append(dialOpts, comm.RetryDialOptions(params.retryPolicy)...)
+
 	if endpoint.AttemptSecured(url, params.insecure) {
 		tlsConfig, err := comm.TLSConfig(params.certificate, params.hostOverride, config)
 		if err != nil {
@@ -129,7 +156,10 @@ func newDialOpts(config fab.EndpointConfig, url string, params *params) ([]grpc.
 		}
 		//verify if certificate was expired or not yet valid
 		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
-			return verifier.VerifyPeerCertificate(rawCerts, verifiedChains)
+			if err := verifier.VerifyPeerCertificate(rawCerts, verifiedChains); err != nil {
+				return err
+			}
+			return comm.VerifyCertificatePins(rawCerts, verifiedChains, params.certPins)
 		}
 
 		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
@@ -139,8 +169,8 @@ func newDialOpts(config fab.EndpointConfig, url string, params *params) ([]grpc.
 		dialOpts = append(dialOpts, grpc.WithInsecure())
 	}
 
-	dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxCallRecvMsgSize),
-		grpc.MaxCallSendMsgSize(maxCallSendMsgSize)))
+	dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(params.maxRecvMsgSize),
+		grpc.MaxCallSendMsgSize(params.maxSendMsgSize)))
 
 	return dialOpts, nil
 }
@@ -13,6 +13,8 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
 	"github.com/spf13/cast"
 	"google.golang.org/grpc/keepalive"
 )
@@ -24,12 +26,21 @@ type params struct {
 	failFast        bool
 	insecure        bool
 	connectTimeout  time.Duration
+	proxy           endpoint.ProxyConfig
+	compression     string
+	retryPolicy     comm.RetryPolicy
+	dialer          string
+	certPins        []string
+	maxRecvMsgSize  int
+	maxSendMsgSize  int
 }
 
 func defaultParams() *params {
 	return &params{
 		failFast:       true,
 		connectTimeout: 3 * time.Second,
+		maxRecvMsgSize: maxCallRecvMsgSize,
+		maxSendMsgSize: maxCallSendMsgSize,
 	}
 }
 
@@ -88,6 +99,61 @@ func WithInsecure() options.Opt {
 	}
 }
 
+// WithProxy sets the proxy configuration used for establishing the connection
+func WithProxy(value endpoint.ProxyConfig) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(proxySetter); ok {
+			setter.SetProxy(value)
+		}
+	}
+}
+
+// WithCompression sets the name of the compressor used for messages sent over the connection
+func WithCompression(value string) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(compressionSetter); ok {
+			setter.SetCompression(value)
+		}
+	}
+}
+
+// WithRetryPolicy sets the transport-level retry policy applied to unary calls over the connection
+func WithRetryPolicy(value comm.RetryPolicy) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(retryPolicySetter); ok {
+			setter.SetRetryPolicy(value)
+		}
+	}
+}
+
+// WithDialer sets the name of the registered dialer used to establish the connection
+func WithDialer(value string) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(dialerSetter); ok {
+			setter.SetDialer(value)
+		}
+	}
+}
+
+// WithCertificatePins sets the SHA-256 fingerprints of which at least one must match
+// a TLS certificate presented by the server
+func WithCertificatePins(value []string) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(certPinsSetter); ok {
+			setter.SetCertificatePins(value)
+		}
+	}
+}
+
+// WithMaxMsgSize sets the maximum size (in bytes) of messages received and sent over the connection
+func WithMaxMsgSize(recv, send int) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(maxMsgSizeSetter); ok {
+			setter.SetMaxMsgSize(recv, send)
+		}
+	}
+}
+
 func (p *params) SetHostOverride(value string) {
 	logger.Debugf("HostOverride: %s", value)
 	p.hostOverride = value
@@ -122,6 +188,37 @@ func (p *params) SetInsecure(value bool) {
 	p.insecure = value
 }
 
+func (p *params) SetProxy(value endpoint.ProxyConfig) {
+	logger.Debugf("Proxy: %#v", value)
+	p.proxy = value
+}
+
+func (p *params) SetCompression(value string) {
+	logger.Debugf("Compression: %s", value)
+	p.compression = value
+}
+
+func (p *params) SetRetryPolicy(value comm.RetryPolicy) {
+	logger.Debugf("RetryPolicy: %+v", value)
+	p.retryPolicy = value
+}
+
+func (p *params) SetDialer(value string) {
+	logger.Debugf("Dialer: %s", value)
+	p.dialer = value
+}
+
+func (p *params) SetCertificatePins(value []string) {
+	logger.Debugf("CertificatePins: %v", value)
+	p.certPins = value
+}
+
+func (p *params) SetMaxMsgSize(recv, send int) {
+	logger.Debugf("MaxMsgSize: recv %d, send %d", recv, send)
+	p.maxRecvMsgSize = recv
+	p.maxSendMsgSize = send
+}
+
 type hostOverrideSetter interface {
 	SetHostOverride(value string)
 }
@@ -146,6 +243,30 @@ type connectTimeoutSetter interface {
 	SetConnectTimeout(value time.Duration)
 }
 
+type proxySetter interface {
+	SetProxy(value endpoint.ProxyConfig)
+}
+
+type compressionSetter interface {
+	SetCompression(value string)
+}
+
+type retryPolicySetter interface {
+	SetRetryPolicy(value comm.RetryPolicy)
+}
+
+type dialerSetter interface {
+	SetDialer(value string)
+}
+
+type certPinsSetter interface {
+	SetCertificatePins(value []string)
+}
+
+type maxMsgSizeSetter interface {
+	SetMaxMsgSize(recv, send int)
+}
+
 // OptsFromPeerConfig returns a set of connection options from the given peer config
 func OptsFromPeerConfig(peerCfg *fab.PeerConfig) ([]options.Opt, error) {
 	certificate, err := peerCfg.TLSCACerts.TLSCert()
@@ -157,11 +278,22 @@ func OptsFromPeerConfig(peerCfg *fab.PeerConfig) ([]options.Opt, error) {
 		}
 	}
 
+	retryPolicy, err := comm.RetryPolicyFromOptions(peerCfg.GRPCOptions)
+	if err != nil {
+		return nil, err
+	}
+
 	opts := []options.Opt{
 		WithHostOverride(getServerNameOverride(peerCfg)),
 		WithFailFast(getFailFast(peerCfg)),
 		WithKeepAliveParams(getKeepAliveOptions(peerCfg)),
 		WithCertificate(certificate),
+		WithProxy(getProxyConfig(peerCfg)),
+		WithCompression(getCompression(peerCfg)),
+		WithRetryPolicy(retryPolicy),
+		WithDialer(getDialer(peerCfg)),
+		WithCertificatePins(peerCfg.TLSCertPins),
+		WithMaxMsgSize(getMaxRecvMsgSize(peerCfg), getMaxSendMsgSize(peerCfg)),
 	}
 	if isInsecureAllowed(peerCfg) {
 		opts = append(opts, WithInsecure())
@@ -185,17 +317,46 @@ func getFailFast(peerCfg *fab.PeerConfig) bool {
 }
 
 func getKeepAliveOptions(peerCfg *fab.PeerConfig) keepalive.ClientParameters {
-	var kap keepalive.ClientParameters
-	if kaTime, ok := peerCfg.GRPCOptions["keep-alive-time"]; ok {
-		kap.Time = cast.ToDuration(kaTime)
+	return comm.KeepAliveParams(peerCfg.GRPCOptions)
+}
+
+func getProxyConfig(peerCfg *fab.PeerConfig) endpoint.ProxyConfig {
+	var proxy endpoint.ProxyConfig
+	if proxyURL, ok := peerCfg.GRPCOptions["proxy-url"].(string); ok {
+		proxy.URL = proxyURL
+	}
+	if noProxy, ok := peerCfg.GRPCOptions["no-proxy"].(string); ok {
+		proxy.NoProxy = noProxy
+	}
+	return proxy
+}
+
+func getCompression(peerCfg *fab.PeerConfig) string {
+	if compression, ok := peerCfg.GRPCOptions["compression"].(string); ok {
+		return compression
 	}
-	if kaTimeout, ok := peerCfg.GRPCOptions["keep-alive-timeout"]; ok {
-		kap.Timeout = cast.ToDuration(kaTimeout)
+	return ""
+}
+
+func getDialer(peerCfg *fab.PeerConfig) string {
+	if dialer, ok := peerCfg.GRPCOptions["dialer"].(string); ok {
+		return dialer
 	}
-	if kaPermit, ok := peerCfg.GRPCOptions["keep-alive-permit"]; ok {
-		kap.PermitWithoutStream = cast.ToBool(kaPermit)
+	return ""
+}
+
+func getMaxRecvMsgSize(peerCfg *fab.PeerConfig) int {
+	if size, ok := peerCfg.GRPCOptions["max-recv-msg-size"]; ok {
+		return cast.ToInt(size)
+	}
+	return maxCallRecvMsgSize
+}
+
+func getMaxSendMsgSize(peerCfg *fab.PeerConfig) int {
+	if size, ok := peerCfg.GRPCOptions["max-send-msg-size"]; ok {
+		return cast.ToInt(size)
 	}
-	return kap
+	return maxCallSendMsgSize
 }
 
 func isInsecureAllowed(peerCfg *fab.PeerConfig) bool {
@@ -14,6 +14,8 @@ import (
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/sdkevents"
 )
 
 const (
@@ -26,7 +28,11 @@ const (
 // When connections has its usages closed for longer than "idleTime", the connection is closed and removed
 // from the connection cache. Callers must release connections by calling the "ReleaseConn" method.
 // The Close method will flush all remaining open connections. This component should be considered
-// unusable after calling Close.
+// unusable after calling Close. Connections to pinned targets (see Pin) are not closed when idle.
+//
+// Connections are cached by target only, so the endorser and the deliver client of a peer multiplex their
+// calls and streams over a single connection. The dial options of the first caller are used to create the
+// connection; options which may differ between callers should be passed as call options.
 //
 // This component has been designed to be safe for concurrency.
 type CachingConnector struct {
@@ -34,6 +40,8 @@ type CachingConnector struct {
 	sweepTime     time.Duration
 	idleTime      time.Duration
 	index         map[*grpc.ClientConn]*cachedConn
+	pinned        map[string]bool
+	eventBus      *sdkevents.Bus
 	lock          sync.Mutex
 	waitgroup     sync.WaitGroup
 	janitorChan   chan *cachedConn
@@ -47,6 +55,7 @@ type cachedConn struct {
 	open      int
 	lastOpen  time.Time
 	lastClose time.Time
+	pinned    bool
 }
 
 // NewCachingConnector creates a GRPC connection cache. The cache is governed by
@@ -55,6 +64,8 @@ func NewCachingConnector(sweepTime time.Duration, idleTime time.Duration) *Cachi
 	cc := CachingConnector{
 		conns:         sync.Map{},
 		index:         map[*grpc.ClientConn]*cachedConn{},
+		pinned:        map[string]bool{},
+		eventBus:      sdkevents.DefaultBus(),
 		janitorChan:   make(chan *cachedConn),
 		janitorDone:   make(chan bool),
 		janitorClosed: make(chan bool, 1),
@@ -118,6 +129,45 @@ func (cc *CachingConnector) DialContext(ctx context.Context, target string, opts
 	return c.conn, nil
 }
 
+// SetEventBus sets the bus on which the state changes of the connections are published (see package
+// sdkevents), by default the default bus
+func (cc *CachingConnector) SetEventBus(bus *sdkevents.Bus) {
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+	cc.eventBus = bus
+}
+
+// Pin keeps connections to the given target open even when they're idle, so that requests to the
+// target (e.g. a hot-standby peer) can be sent without the latency of establishing a connection.
+func (cc *CachingConnector) Pin(target string) {
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+
+	logger.Debugf("Pin [%s]", target)
+	cc.pinned[target] = true
+
+	if cc.janitorDone == nil {
+		return
+	}
+	if connRaw, ok := cc.conns.Load(target); ok {
+		c := connRaw.(*cachedConn)
+		c.pinned = true
+		cc.updateJanitor(c)
+	}
+}
+
+// IsAvailable returns false if the cached connection to the given target is failing or has been shut
+// down, e.g. because the server is draining its connections. If there is no cached connection to the
+// target then its availability is unknown and true is returned.
+func (cc *CachingConnector) IsAvailable(target string) bool {
+	connRaw, ok := cc.conns.Load(target)
+	if !ok {
+		return true
+	}
+	state := connRaw.(*cachedConn).conn.GetState()
+	return state != connectivity.TransientFailure && state != connectivity.Shutdown
+}
+
 // ReleaseConn notifies the cache that the connection is no longer in use.
 func (cc *CachingConnector) ReleaseConn(conn *grpc.ClientConn) {
 	cc.lock.Lock()
@@ -190,10 +240,13 @@ func (cc *CachingConnector) createConn(ctx context.Context, target string, opts
 	cconn = &cachedConn{
 		target: target,
 		conn:   conn,
+		pinned: cc.pinned[target],
 	}
 	cc.conns.Store(target, cconn)
 	cc.index[conn] = cconn
 
+	go monitorConnState(cc.eventBus, target, conn)
+
 	return cconn, nil
 }
 
@@ -227,6 +280,29 @@ func waitConn(ctx context.Context, conn *grpc.ClientConn, targetState connectivi
 	return nil
 }
 
+// monitorConnState publishes the changes of the connection between the ready and the failing
+// state until the connection is shut down
+func monitorConnState(bus *sdkevents.Bus, target string, conn *grpc.ClientConn) {
+	ready := false
+	for {
+		state := conn.GetState()
+		switch {
+		case state == connectivity.Ready && !ready:
+			ready = true
+			bus.Publish(&sdkevents.Event{Type: sdkevents.ConnectionUp, Target: target})
+		case state == connectivity.TransientFailure && ready:
+			// Idle connections are reconnected on their next use and connections are shut down
+			// when they're no longer used, so neither is reported as down
+			ready = false
+			bus.Publish(&sdkevents.Event{Type: sdkevents.ConnectionDown, Target: target, Err: errors.Errorf("connection state is %s", state)})
+		}
+		if state == connectivity.Shutdown {
+			return
+		}
+		conn.WaitForStateChange(context.Background(), state)
+	}
+}
+
 func (cc *CachingConnector) shutdownConn(cconn *cachedConn) {
 	cc.lock.Lock()
 	defer cc.lock.Unlock()
@@ -368,7 +444,7 @@ func sweep(conns map[string]*cachedConn, idleTime time.Duration) []string {
 	rm := make([]string, 0, len(conns))
 	now := time.Now()
 	for _, c := range conns {
-		if c.open == 0 && now.After(c.lastClose.Add(idleTime)) {
+		if c.open == 0 && !c.pinned && now.After(c.lastClose.Add(idleTime)) {
 			logger.Debugf("connection janitor closing connection [%s]", c.target)
 			rm = append(rm, c.target)
 		} else if c.conn.GetState() == connectivity.Shutdown {
@@ -14,6 +14,8 @@ import (
 	"time"
 	"unsafe"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fabmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
@@ -176,6 +178,69 @@ func TestConnectorShouldSweep(t *testing.T) {
 	assert.NotEqual(t, unsafe.Pointer(conn1), unsafe.Pointer(conn4), "connections should be different due to disconnect")
 }
 
+func TestConnectorPinned(t *testing.T) {
+	connector := NewCachingConnector(shortSweepTime, shortIdleTime)
+	defer connector.Close()
+
+	assert.True(t, connector.IsAvailable(endorserAddr[0]), "target without connection should be available")
+
+	ctx, cancel := context.WithTimeout(context.Background(), normalTimeout)
+	conn1, err := connector.DialContext(ctx, endorserAddr[0], grpc.WithInsecure())
+	cancel()
+	assert.Nil(t, err, "DialContext should have succeeded")
+	connector.Pin(endorserAddr[0])
+
+	connector.Pin(endorserAddr[1])
+	ctx, cancel = context.WithTimeout(context.Background(), normalTimeout)
+	conn2, err := connector.DialContext(ctx, endorserAddr[1], grpc.WithInsecure())
+	cancel()
+	assert.Nil(t, err, "DialContext should have succeeded")
+
+	connector.ReleaseConn(conn1)
+	connector.ReleaseConn(conn2)
+	time.Sleep(shortIdleTime * 3)
+	assert.NotEqual(t, connectivity.Shutdown, conn1.GetState(), "pinned connection should not be shutdown")
+	assert.NotEqual(t, connectivity.Shutdown, conn2.GetState(), "pinned connection should not be shutdown")
+	assert.True(t, connector.IsAvailable(endorserAddr[0]), "pinned connection should be available")
+
+	ctx, cancel = context.WithTimeout(context.Background(), normalTimeout)
+	conn3, err := connector.DialContext(ctx, endorserAddr[0], grpc.WithInsecure())
+	cancel()
+	assert.Nil(t, err, "DialContext should have succeeded")
+	assert.Equal(t, unsafe.Pointer(conn1), unsafe.Pointer(conn3), "connections should match")
+}
+
+func TestConnectorSharedByEndorserAndDeliver(t *testing.T) {
+	connector := NewCachingConnector(normalSweepTime, normalIdleTime)
+	defer connector.Close()
+
+	// The deliver client connects with the URL of the peer
+	clientCtx := newMockContext()
+	clientCtx.SetCustomInfraProvider(&cachingInfraProvider{connector: connector})
+	deliverConn, err := NewConnection(clientCtx, "grpc://"+endorserAddr[0], WithFailFast(false))
+	assert.Nil(t, err, "NewConnection should have succeeded")
+	defer deliverConn.Close()
+
+	// The endorser dials the address of the peer, with its own options
+	ctx, cancel := context.WithTimeout(context.Background(), normalTimeout)
+	endorserConn, err := connector.DialContext(ctx, endorserAddr[0], grpc.WithInsecure(), grpc.WithDefaultCallOptions(grpc.FailFast(true)))
+	cancel()
+	assert.Nil(t, err, "DialContext should have succeeded")
+	defer connector.ReleaseConn(endorserConn)
+
+	assert.Equal(t, unsafe.Pointer(deliverConn.ClientConn()), unsafe.Pointer(endorserConn), "endorser and deliver client should share the connection")
+}
+
+// cachingInfraProvider returns the given caching connector as comm manager
+type cachingInfraProvider struct {
+	fabmocks.MockInfraProvider
+	connector *CachingConnector
+}
+
+func (p *cachingInfraProvider) CommManager() fab.CommManager {
+	return p.connector
+}
+
 func TestConnectorConcurrent(t *testing.T) {
 	const goroutines = 50
 
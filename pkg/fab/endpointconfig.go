@@ -18,16 +18,21 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/cryptoutil"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/lookup"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/certexpiry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/circuitbreaker"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/pathvar"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/sdkevents"
 	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
 )
@@ -52,6 +57,7 @@ const (
 	defaultChannelConfigRefreshInterval   = time.Minute * 90
 	defaultChannelMemshpRefreshInterval   = time.Second * 60
 	defaultDiscoveryRefreshInterval       = time.Second * 10
+	defaultDNSSRVRefreshInterval          = time.Second * 30
 
 	defaultCacheSweepInterval = time.Second * 15
 )
@@ -60,14 +66,22 @@ const (
 func ConfigFromBackend(coreBackend core.ConfigBackend) (fab.EndpointConfig, error) {
 
 	config := &EndpointConfig{
-		backend:        lookup.New(coreBackend),
-		tlsCertsByName: make(map[string][]int),
+		backend:           lookup.New(coreBackend),
+		tlsCertsByName:    make(map[string][]int),
+		certExpiryTracker: certexpiry.NewTracker(certexpiry.DefaultWarningThreshold),
+		eventBus:          sdkevents.NewBus(),
 	}
 
 	if err := config.cacheNetworkConfiguration(); err != nil {
 		return nil, errors.WithMessage(err, "network configuration load failed")
 	}
 
+	config.srvResolver = endpoint.NewSRVResolver(config.Timeout(fab.DNSSRVRefresh))
+
+	if config.backend.GetBool("client.grpc.diagnostics") {
+		comm.EnableGRPCDiagnostics()
+	}
+
 	if err := config.loadSystemCertPool(); err != nil {
 		return nil, errors.WithMessage(err, "system cert pool load failed")
 	}
@@ -99,6 +113,10 @@ type EndpointConfig struct {
 	channelMatchers     map[int]*regexp.Regexp
 	tlsCertsByName      map[string][]int
 	certPoolLock        sync.Mutex
+	srvResolver         *endpoint.SRVResolver
+	certExpiryTracker   *certexpiry.Tracker
+	eventBus            *sdkevents.Bus
+	comm.Dialers
 }
 
 // Timeout reads timeouts for the given timeout type, if type is not found in the config
@@ -168,7 +186,7 @@ func (c *EndpointConfig) OrderersConfig() ([]fab.OrdererConfig, error) {
 			return nil, errors.Errorf("Orderer has no certs configured. Make sure TLSCACerts.Pem or TLSCACerts.Path is set for %s", orderer.URL)
 		}
 
-		orderers = append(orderers, orderer)
+		orderers = append(orderers, c.resolveOrdererSRV(orderer)...)
 	}
 
 	return orderers, nil
@@ -195,6 +213,14 @@ func (c *EndpointConfig) OrdererConfig(name string) (*fab.OrdererConfig, error)
 		orderer.TLSCACerts.Path = pathvar.Subst(orderer.TLSCACerts.Path)
 	}
 
+	if endpoint.IsSRV(orderer.URL) {
+		resolved := c.resolveOrdererSRV(orderer)
+		if len(resolved) == 0 {
+			return nil, errors.Errorf("unable to resolve DNS SRV URL [%s] of orderer [%s]", orderer.URL, name)
+		}
+		orderer = resolved[0]
+	}
+
 	return &orderer, nil
 }
 
@@ -224,7 +250,7 @@ func (c *EndpointConfig) PeersConfig(org string) ([]fab.PeerConfig, error) {
 			p.TLSCACerts.Path = pathvar.Subst(p.TLSCACerts.Path)
 		}
 
-		peers = append(peers, p)
+		peers = append(peers, c.resolvePeerSRV(p)...)
 	}
 	return peers, nil
 }
@@ -243,12 +269,7 @@ func (c *EndpointConfig) PeerConfig(nameOrURL string) (*fab.PeerConfig, error) {
 	if ok {
 		matchPeerConfig = &peerConfig
 	} else {
-		for _, staticPeerConfig := range networkConfig.Peers {
-			if strings.EqualFold(staticPeerConfig.URL, nameOrURL) {
-				matchPeerConfig = &staticPeerConfig
-				break
-			}
-		}
+		matchPeerConfig = c.findPeerConfigByURL(networkConfig, nameOrURL)
 	}
 
 	//Not found through config lookup by name or URL, try matcher now
@@ -268,9 +289,41 @@ func (c *EndpointConfig) PeerConfig(nameOrURL string) (*fab.PeerConfig, error) {
 		matchPeerConfig.TLSCACerts.Path = pathvar.Subst(peerConfig.TLSCACerts.Path)
 	}
 
+	if endpoint.IsSRV(matchPeerConfig.URL) {
+		resolved := c.resolvePeerSRV(*matchPeerConfig)
+		if len(resolved) == 0 {
+			return nil, errors.Errorf("unable to resolve DNS SRV URL [%s] of peer [%s]", matchPeerConfig.URL, nameOrURL)
+		}
+		matchPeerConfig = &resolved[0]
+	}
+
 	return matchPeerConfig, nil
 }
 
+// findPeerConfigByURL returns the configuration of the peer with the given URL. The targets
+// of peers that are configured with a DNS SRV URL are also taken into consideration.
+func (c *EndpointConfig) findPeerConfigByURL(networkConfig *fab.NetworkConfig, url string) *fab.PeerConfig {
+	for _, staticPeerConfig := range networkConfig.Peers {
+		if strings.EqualFold(staticPeerConfig.URL, url) {
+			return &staticPeerConfig
+		}
+	}
+
+	address := endpoint.ToAddress(url)
+	for _, staticPeerConfig := range networkConfig.Peers {
+		if !endpoint.IsSRV(staticPeerConfig.URL) {
+			continue
+		}
+		for _, resolved := range c.resolvePeerSRV(staticPeerConfig) {
+			if strings.EqualFold(endpoint.ToAddress(resolved.URL), address) {
+				return &resolved
+			}
+		}
+	}
+
+	return nil
+}
+
 // NetworkConfig returns the network configuration defined in the config file
 func (c *EndpointConfig) NetworkConfig() (*fab.NetworkConfig, error) {
 	if c.networkConfigCached {
@@ -307,8 +360,10 @@ func (c *EndpointConfig) NetworkPeers() ([]fab.NetworkPeer, error) {
 			return nil, errors.Errorf("failed to retrieve msp id for peer %s", name)
 		}
 
-		netPeer := fab.NetworkPeer{PeerConfig: p, MSPID: mspID}
-		netPeers = append(netPeers, netPeer)
+		for _, resolved := range c.resolvePeerSRV(p) {
+			netPeer := fab.NetworkPeer{PeerConfig: resolved, MSPID: mspID}
+			netPeers = append(netPeers, netPeer)
+		}
 	}
 
 	return netPeers, nil
@@ -425,11 +480,13 @@ func (c *EndpointConfig) ChannelPeers(name string) ([]fab.ChannelPeer, error) {
 			return nil, errors.Errorf("failed to retrieve msp id for peer %s", peerName)
 		}
 
-		networkPeer := fab.NetworkPeer{PeerConfig: p, MSPID: mspID}
+		for _, resolved := range c.resolvePeerSRV(p) {
+			networkPeer := fab.NetworkPeer{PeerConfig: resolved, MSPID: mspID}
 
-		peer := fab.ChannelPeer{PeerChannelConfig: chPeerConfig, NetworkPeer: networkPeer}
+			peer := fab.ChannelPeer{PeerChannelConfig: chPeerConfig, NetworkPeer: networkPeer}
 
-		peers = append(peers, peer)
+			peers = append(peers, peer)
+		}
 	}
 
 	return peers, nil
@@ -444,7 +501,20 @@ func (c *EndpointConfig) ChannelOrderers(name string) ([]fab.OrdererConfig, erro
 		return nil, errors.Errorf("Unable to retrieve channel config: %s", err)
 	}
 
+	networkConfig, err := c.NetworkConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	for _, chOrderer := range channel.Orderers {
+		if orderer, ok := networkConfig.Orderers[strings.ToLower(chOrderer)]; ok && endpoint.IsSRV(orderer.URL) {
+			if orderer.TLSCACerts.Path != "" {
+				orderer.TLSCACerts.Path = pathvar.Subst(orderer.TLSCACerts.Path)
+			}
+			orderers = append(orderers, c.resolveOrdererSRV(orderer)...)
+			continue
+		}
+
 		orderer, err := c.OrdererConfig(chOrderer)
 		if err != nil || orderer == nil {
 			return nil, errors.Errorf("unable to retrieve orderer config: %s", err)
@@ -479,6 +549,17 @@ func (c *EndpointConfig) TLSCACertPool(certs ...*x509.Certificate) *x509.CertPoo
 	return tlsCertPool
 }
 
+// CertExpiryTracker returns the tracker to which the channel MSP certificates and the local
+// signing certificates of the SDK instance using this config are reported
+func (c *EndpointConfig) CertExpiryTracker() *certexpiry.Tracker {
+	return c.certExpiryTracker
+}
+
+// EventBus returns the bus on which the SDK instance using this config publishes its events
+func (c *EndpointConfig) EventBus() *sdkevents.Bus {
+	return c.eventBus
+}
+
 // EventServiceType returns the type of event service client to use
 func (c *EndpointConfig) EventServiceType() fab.EventServiceType {
 	etype := c.backend.GetString("client.eventService.type")
@@ -634,6 +715,9 @@ func (c *EndpointConfig) getTimeout(tType fab.TimeoutType) time.Duration { //nol
 		if timeout == 0 {
 			timeout = defaultChannelConfigRefreshInterval
 		}
+	case fab.ChannelConfigMaxStaleness:
+		// No default: a stale channel configuration is used until it's refreshed
+		timeout = c.backend.GetDuration("client.global.cache.channelConfigMaxStaleness")
 	case fab.ChannelMembershipRefresh:
 		timeout = c.backend.GetDuration("client.global.cache.channelMembership")
 		if timeout == 0 {
@@ -645,6 +729,12 @@ func (c *EndpointConfig) getTimeout(tType fab.TimeoutType) time.Duration { //nol
 			timeout = defaultDiscoveryRefreshInterval
 		}
 
+	case fab.DNSSRVRefresh:
+		timeout = c.backend.GetDuration("client.global.cache.dnsSRV")
+		if timeout == 0 {
+			timeout = defaultDNSSRVRefreshInterval
+		}
+
 	case fab.CacheSweepInterval: // EXPERIMENTAL - do we need this to be configurable?
 		timeout = c.backend.GetDuration("client.cache.interval.sweep")
 		if timeout == 0 {
@@ -703,11 +793,92 @@ func (c *EndpointConfig) cacheNetworkConfiguration() error {
 		return errors.WithMessage(err, "failed to parse 'entityMatchers' config item to networkConfig.EntityMatchers type")
 	}
 
+	err = c.backend.UnmarshalKey("retryPolicies", &networkConfig.RetryPolicies)
+	logger.Debugf("Retry policies are: %+v", networkConfig.RetryPolicies)
+	if err != nil {
+		return errors.WithMessage(err, "failed to parse 'retryPolicies' config item to networkConfig.RetryPolicies type")
+	}
+	if err := registerRetryPolicies(networkConfig.RetryPolicies); err != nil {
+		return errors.WithMessage(err, "failed to register retry policies")
+	}
+
+	setProxyDefaults(&networkConfig)
+
 	c.networkConfig = &networkConfig
 	c.networkConfigCached = true
 	return nil
 }
 
+// registerRetryPolicies registers the configured retry policies with the retry package
+func registerRetryPolicies(policies map[string]fab.RetryPolicyConfig) error {
+	for name, policy := range policies {
+		opts := retry.Opts{
+			Attempts:       policy.Attempts,
+			InitialBackoff: policy.InitialBackoff,
+			MaxBackoff:     policy.MaxBackoff,
+			BackoffFactor:  policy.BackoffFactor,
+			Jitter:         policy.Jitter,
+		}
+		if policy.CircuitBreaker.FailureThreshold > 0 {
+			breakers := circuitbreaker.NewRegistry(circuitbreaker.Config{
+				FailureThreshold: policy.CircuitBreaker.FailureThreshold,
+				CoolDown:         policy.CircuitBreaker.CoolDown,
+			})
+			opts.Breaker = breakers.Get(name)
+		}
+		if len(policy.RetryableCodes) > 0 {
+			opts.RetryableCodes = make(map[status.Group][]status.Code)
+		}
+		for groupName, codeNames := range policy.RetryableCodes {
+			group, err := status.ParseGroup(groupName)
+			if err != nil {
+				return errors.Wrapf(err, "invalid retryable codes of retry policy [%s]", name)
+			}
+			for _, codeName := range codeNames {
+				code, err := status.ParseCode(group, codeName)
+				if err != nil {
+					return errors.Wrapf(err, "invalid retryable codes of retry policy [%s]", name)
+				}
+				opts.RetryableCodes[group] = append(opts.RetryableCodes[group], code)
+			}
+		}
+		retry.RegisterPolicy(name, opts)
+	}
+	return nil
+}
+
+// setProxyDefaults applies the client proxy settings to the peers and orderers
+// which don't specify their own proxy settings in 'grpcOptions'
+func setProxyDefaults(networkConfig *fab.NetworkConfig) {
+	proxy := networkConfig.Client.Proxy
+	if proxy.URL == "" && proxy.NoProxy == "" {
+		return
+	}
+
+	for name, peerConfig := range networkConfig.Peers {
+		peerConfig.GRPCOptions = withProxyDefaults(peerConfig.GRPCOptions, proxy)
+		networkConfig.Peers[name] = peerConfig
+	}
+
+	for name, ordererConfig := range networkConfig.Orderers {
+		ordererConfig.GRPCOptions = withProxyDefaults(ordererConfig.GRPCOptions, proxy)
+		networkConfig.Orderers[name] = ordererConfig
+	}
+}
+
+func withProxyDefaults(grpcOptions map[string]interface{}, proxy endpoint.ProxyConfig) map[string]interface{} {
+	if grpcOptions == nil {
+		grpcOptions = make(map[string]interface{})
+	}
+	if _, ok := grpcOptions["proxy-url"]; !ok && proxy.URL != "" {
+		grpcOptions["proxy-url"] = proxy.URL
+	}
+	if _, ok := grpcOptions["no-proxy"]; !ok && proxy.NoProxy != "" {
+		grpcOptions["no-proxy"] = proxy.NoProxy
+	}
+	return grpcOptions
+}
+
 func (c *EndpointConfig) getPortIfPresent(url string) (int, bool) {
 	s := strings.Split(url, ":")
 	if len(s) > 1 {
@@ -926,6 +1097,50 @@ func (c *EndpointConfig) tryMatchingChannelConfig(channelName string) (*fab.Chan
 	return &channelConfig, mappedChannelName, nil
 }
 
+// resolvePeerSRV returns a peer config for each target of the DNS SRV URL of the given peer.
+// The given peer config is returned as is if it's not configured with a DNS SRV URL.
+func (c *EndpointConfig) resolvePeerSRV(peerConfig fab.PeerConfig) []fab.PeerConfig {
+	if !endpoint.IsSRV(peerConfig.URL) {
+		return []fab.PeerConfig{peerConfig}
+	}
+
+	urls, err := c.srvResolver.Resolve(peerConfig.URL)
+	if err != nil {
+		logger.Warnf("Unable to resolve DNS SRV URL of peer [%s]: %s", peerConfig.URL, err)
+		return nil
+	}
+
+	peers := make([]fab.PeerConfig, len(urls))
+	for i, url := range urls {
+		peers[i] = peerConfig
+		peers[i].URL = url
+		peers[i].GRPCOptions = copyPropertiesMap(peerConfig.GRPCOptions)
+	}
+	return peers
+}
+
+// resolveOrdererSRV returns an orderer config for each target of the DNS SRV URL of the given orderer.
+// The given orderer config is returned as is if it's not configured with a DNS SRV URL.
+func (c *EndpointConfig) resolveOrdererSRV(ordererConfig fab.OrdererConfig) []fab.OrdererConfig {
+	if !endpoint.IsSRV(ordererConfig.URL) {
+		return []fab.OrdererConfig{ordererConfig}
+	}
+
+	urls, err := c.srvResolver.Resolve(ordererConfig.URL)
+	if err != nil {
+		logger.Warnf("Unable to resolve DNS SRV URL of orderer [%s]: %s", ordererConfig.URL, err)
+		return nil
+	}
+
+	orderers := make([]fab.OrdererConfig, len(urls))
+	for i, url := range urls {
+		orderers[i] = ordererConfig
+		orderers[i].URL = url
+		orderers[i].GRPCOptions = copyPropertiesMap(ordererConfig.GRPCOptions)
+	}
+	return orderers
+}
+
 func copyPropertiesMap(origMap map[string]interface{}) map[string]interface{} {
 	newMap := make(map[string]interface{}, len(origMap))
 	for k, v := range origMap {
@@ -1088,9 +1303,16 @@ func (c *EndpointConfig) containsCert(newCert *x509.Certificate) bool {
 	return false
 }
 
+// getCertPool returns a new cert pool (or a copy of the system cert pool) so that certs
+// added to the pool returned by TLSCACertPool don't modify the configured cert pool
 func (c *EndpointConfig) getCertPool() *x509.CertPool {
 	if c.systemCertPool != nil {
-		return c.systemCertPool
+		systemCertPool, err := x509.SystemCertPool()
+		if err != nil {
+			logger.Warnf("Failed to copy system cert pool: %s", err)
+			return c.systemCertPool
+		}
+		return systemCertPool
 	}
 
 	return x509.NewCertPool()
@@ -22,14 +22,19 @@ import (
 
 	"reflect"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/circuitbreaker"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/pathvar"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	grpcCodes "google.golang.org/grpc/codes"
 )
 
 const (
@@ -345,6 +350,10 @@ func checkDefaultTimeout(endpointConfig fab.EndpointConfig, t *testing.T, errStr
 	if t1 != defaultChannelMemshpRefreshInterval {
 		t.Fatalf(errStr, "ChannelMembershipRefresh", t1)
 	}
+	t1 = endpointConfig.Timeout(fab.ChannelConfigMaxStaleness)
+	if t1 != 0 {
+		t.Fatalf(errStr, "ChannelConfigMaxStaleness", t1)
+	}
 }
 
 func TestOrdererConfig(t *testing.T) {
@@ -1243,6 +1252,56 @@ func TestPeerChannelConfig(t *testing.T) {
 
 }
 
+func TestRetryPolicies(t *testing.T) {
+	backend := getCustomBackend()
+	backend.KeyValueMap["retryPolicies"] = map[string]interface{}{
+		"endpointconfig-test": map[string]interface{}{
+			"attempts":       7,
+			"initialBackoff": "10ms",
+			"maxBackoff":     "1s",
+			"backoffFactor":  1.5,
+			"jitter":         true,
+			"retryableCodes": map[string]interface{}{
+				"EventServerStatus":   []interface{}{"MVCC_READ_CONFLICT", "12"},
+				"GRPCTransportStatus": []interface{}{"Unavailable"},
+			},
+			"circuitBreaker": map[string]interface{}{
+				"failureThreshold": 3,
+				"coolDown":         "30s",
+			},
+		},
+	}
+
+	_, err := ConfigFromBackend(backend)
+	require.NoError(t, err)
+
+	opts, err := retry.Policy("endpointconfig-test")
+	require.NoError(t, err)
+	assert.Equal(t, 7, opts.Attempts)
+	assert.Equal(t, 10*time.Millisecond, opts.InitialBackoff)
+	assert.Equal(t, time.Second, opts.MaxBackoff)
+	assert.Equal(t, 1.5, opts.BackoffFactor)
+	assert.True(t, opts.Jitter)
+	assert.Equal(t, []status.Code{status.Code(pb.TxValidationCode_MVCC_READ_CONFLICT), 12}, opts.RetryableCodes[status.EventServerStatus])
+	assert.Equal(t, []status.Code{status.Code(grpcCodes.Unavailable)}, opts.RetryableCodes[status.GRPCTransportStatus])
+	assert.Len(t, opts.RetryableCodes, 2)
+	require.NotNil(t, opts.Breaker)
+	assert.Equal(t, circuitbreaker.Closed, opts.Breaker.State())
+
+	// The breaker is shared by the users of the policy
+	other, err := retry.Policy("endpointconfig-test")
+	require.NoError(t, err)
+	assert.True(t, opts.Breaker == other.Breaker)
+
+	backend.KeyValueMap["retryPolicies"] = map[string]interface{}{
+		"invalid": map[string]interface{}{
+			"retryableCodes": map[string]interface{}{"EventServerStatus": []interface{}{"UNKNOWN_CODE"}},
+		},
+	}
+	_, err = ConfigFromBackend(backend)
+	assert.Error(t, err, "expecting error for invalid retryable code")
+}
+
 func tamperPeerChannelConfig(backend *mocks.MockConfigBackend) {
 	channelsMap := backend.KeyValueMap["channels"]
 	orgChannel := map[string]interface{}{
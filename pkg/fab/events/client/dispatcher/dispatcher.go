@@ -15,7 +15,9 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/endpoint"
 	esdispatcher "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/dispatcher"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/sdkevents"
 	"github.com/pkg/errors"
 )
 
@@ -33,6 +35,8 @@ type Dispatcher struct {
 	connection             api.Connection
 	connectionRegistration *ConnectionReg
 	connectionProvider     api.ConnectionProvider
+	peerURL                string
+	disconnected           bool
 }
 
 // New creates a new dispatcher
@@ -126,12 +130,23 @@ func (ed *Dispatcher) HandleConnectEvent(e esdispatcher.Event) {
 	}
 
 	ed.connection = conn
+	ed.peerURL = endpointURL(peer)
 
 	go ed.connection.Receive(eventch)
 
 	evt.ErrCh <- nil
 }
 
+// endpointURL returns the event URL of the given endpoint, if any, otherwise the URL of its peer
+func endpointURL(peer fab.Peer) string {
+	if ep, ok := peer.(*endpoint.EventEndpoint); ok {
+		if ep.EventURL() != "" || ep.Peer == nil {
+			return ep.EventURL()
+		}
+	}
+	return peer.URL()
+}
+
 // HandleDisconnectEvent disconnects from the event server
 func (ed *Dispatcher) HandleDisconnectEvent(e esdispatcher.Event) {
 	evt := e.(*DisconnectEvent)
@@ -168,6 +183,11 @@ func (ed *Dispatcher) HandleConnectedEvent(e esdispatcher.Event) {
 
 	logger.Debugf("Handling connected event: %v", evt)
 
+	if ed.disconnected {
+		ed.disconnected = false
+		sdkevents.BusFor(ed.context.EndpointConfig()).Publish(&sdkevents.Event{Type: sdkevents.DeliverReconnected, Target: ed.peerURL, ChannelID: ed.chConfig.ID()})
+	}
+
 	if ed.connectionRegistration != nil && ed.connectionRegistration.Eventch != nil {
 		select {
 		case ed.connectionRegistration.Eventch <- NewConnectionEvent(true, nil):
@@ -183,6 +203,9 @@ func (ed *Dispatcher) HandleDisconnectedEvent(e esdispatcher.Event) {
 
 	logger.Debugf("Disconnecting from event server: %s", evt.Err)
 
+	ed.disconnected = true
+	sdkevents.BusFor(ed.context.EndpointConfig()).Publish(&sdkevents.Event{Type: sdkevents.DeliverDisconnected, Target: ed.peerURL, ChannelID: ed.chConfig.ID(), Err: evt.Err})
+
 	if ed.connection != nil {
 		ed.connection.Close()
 		ed.connection = nil
@@ -14,6 +14,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/crypto"
 	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	fabcontext "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
@@ -129,7 +130,7 @@ func (c *DeliverConnection) Receive(eventch chan<- interface{}) {
 
 		if err != nil {
 			logger.Warnf("Received error from stream: [%s]. Sending disconnected event.", err)
-			eventch <- clientdisp.NewDisconnectedEvent(err)
+			eventch <- clientdisp.NewDisconnectedEvent(status.FromStreamError(err, stream))
 			break
 		}
 
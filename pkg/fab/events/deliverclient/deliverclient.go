@@ -15,6 +15,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	fabcontext "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/bft"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/api"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client"
 	deliverconn "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/connection"
@@ -55,6 +56,15 @@ func New(context fabcontext.Client, chConfig fab.ChannelCfg, opts ...options.Opt
 	params := defaultParams()
 	options.Apply(params, opts)
 
+	if bft.IsBFT(chConfig) {
+		verifier, err := newBFTBlockVerifier(context, chConfig)
+		if err != nil {
+			return nil, err
+		}
+		// Prepend the option so that a verifier specified by the caller takes precedence
+		opts = append([]options.Opt{WithBlockVerifier(verifier)}, opts...)
+	}
+
 	// Use a context that returns a custom Discovery Provider which
 	// produces event endpoints containing additional GRPC options.
 	deliverCtx := newDeliverContext(context)
@@ -76,6 +86,23 @@ func New(context fabcontext.Client, chConfig fab.ChannelCfg, opts ...options.Opt
 	return client, nil
 }
 
+// newBFTBlockVerifier returns a verifier which checks that blocks are signed by a quorum of the consenters
+func newBFTBlockVerifier(context fabcontext.Client, chConfig fab.ChannelCfg) (dispatcher.BlockVerifier, error) {
+	chService, err := context.ChannelProvider().ChannelService(context, chConfig.ID())
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to get channel service")
+	}
+	membership, err := chService.Membership()
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to get channel membership")
+	}
+	consenters := chConfig.Consenters()
+	if len(consenters) == 0 {
+		return nil, errors.Errorf("no consenters found in the config of BFT channel [%s]", chConfig.ID())
+	}
+	return bft.NewBlockVerifier(membership, consenters), nil
+}
+
 func (c *Client) seek() error {
 	logger.Debugf("Sending seek request....")
 
@@ -34,12 +34,17 @@ type dsConnection interface {
 // This also avoids the need for synchronization.
 type Dispatcher struct {
 	clientdisp.Dispatcher
+	params
 }
 
 // New returns a new deliver dispatcher
 func New(context fabcontext.Client, chConfig fab.ChannelCfg, connectionProvider api.ConnectionProvider, opts ...options.Opt) *Dispatcher {
+	params := &params{}
+	options.Apply(params, opts)
+
 	return &Dispatcher{
 		Dispatcher: *clientdisp.New(context, chConfig, connectionProvider, opts...),
+		params:     *params,
 	}
 }
 
@@ -78,7 +83,7 @@ func (ed *Dispatcher) handleEvent(e esdispatcher.Event) {
 	case *pb.DeliverResponse_Status:
 		ed.handleDeliverResponseStatus(response)
 	case *pb.DeliverResponse_Block:
-		ed.HandleBlock(response.Block, delevent.SourceURL)
+		ed.handleBlock(response.Block, delevent.SourceURL)
 	case *pb.DeliverResponse_FilteredBlock:
 		ed.HandleFilteredBlock(response.FilteredBlock, delevent.SourceURL)
 	default:
@@ -86,6 +91,19 @@ func (ed *Dispatcher) handleEvent(e esdispatcher.Event) {
 	}
 }
 
+func (ed *Dispatcher) handleBlock(block *cb.Block, sourceURL string) {
+	if ed.blockVerifier != nil {
+		if err := ed.blockVerifier.Verify(block); err != nil {
+			// The block isn't published (and the last block number isn't updated) so that
+			// the block is requested again after reconnecting, possibly to a different peer
+			logger.Warnf("Verification of block from [%s] failed: %s. Disconnecting...", sourceURL, err)
+			ed.disconnect(errors.WithMessage(err, "block verification failed"))
+			return
+		}
+	}
+	ed.HandleBlock(block, sourceURL)
+}
+
 func (ed *Dispatcher) handleDeliverResponseStatus(evt *pb.DeliverResponse_Status) {
 	logger.Debugf("Got deliver response status event: %#v", evt)
 
@@ -95,6 +113,10 @@ func (ed *Dispatcher) handleDeliverResponseStatus(evt *pb.DeliverResponse_Status
 
 	logger.Warnf("Got deliver response status event: %#v. Disconnecting...", evt)
 
+	ed.disconnect(errors.Errorf("got error status from deliver server: %s", evt.Status))
+}
+
+func (ed *Dispatcher) disconnect(cause error) {
 	errch := make(chan error, 1)
 	ed.Dispatcher.HandleDisconnectEvent(&clientdisp.DisconnectEvent{
 		Errch: errch,
@@ -105,7 +127,7 @@ func (ed *Dispatcher) handleDeliverResponseStatus(evt *pb.DeliverResponse_Status
 	}
 
 	ed.Dispatcher.HandleDisconnectedEvent(&clientdisp.DisconnectedEvent{
-		Err: errors.Errorf("got error status from deliver server: %s", evt.Status),
+		Err: cause,
 	})
 }
 
@@ -20,6 +20,7 @@ import (
 	servicemocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/mocks"
 	fabmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	"github.com/pkg/errors"
 )
 
@@ -217,6 +218,78 @@ func TestBlockEvents(t *testing.T) {
 	}
 }
 
+type rejectingVerifier struct{}
+
+func (v *rejectingVerifier) Verify(block *cb.Block) error {
+	return errors.Errorf("block [%d] is not signed by a quorum", block.Header.Number)
+}
+
+func TestBlockVerificationFailure(t *testing.T) {
+	channelID := "testchannel"
+	ledger := servicemocks.NewMockLedger(delivermocks.BlockEventFactory, sourceURL)
+
+	dispatcher := New(
+		fabmocks.NewMockContextWithCustomDiscovery(
+			mspmocks.NewMockSigningIdentity("user1", "Org1MSP"),
+			clientmocks.NewDiscoveryProvider(peer1, peer2),
+		),
+		fabmocks.NewMockChannelCfg(channelID),
+		clientmocks.NewProviderFactory().Provider(
+			delivermocks.NewConnection(
+				clientmocks.WithLedger(ledger),
+			),
+		),
+	)
+	dispatcher.SetBlockVerifier(&rejectingVerifier{})
+	if err := dispatcher.Start(); err != nil {
+		t.Fatalf("Error starting dispatcher: %s", err)
+	}
+
+	dispatcherEventch, err := dispatcher.EventCh()
+	if err != nil {
+		t.Fatalf("Error getting event channel from dispatcher: %s", err)
+	}
+
+	// Register connection event
+	errch := make(chan error)
+	regch := make(chan fab.Registration)
+	conneventch := make(chan *clientdisp.ConnectionEvent, 5)
+	dispatcherEventch <- clientdisp.NewRegisterConnectionEvent(conneventch, regch, errch)
+	checkErrorFromReg(errch, t, regch)
+
+	// Connect
+	dispatcherEventch <- clientdisp.NewConnectEvent(errch)
+	if err := <-errch; err != nil {
+		t.Fatalf("Error connecting: %s", err)
+	}
+
+	// Register for block events
+	eventch := make(chan *fab.BlockEvent, 10)
+	dispatcherEventch <- esdispatcher.NewRegisterBlockEvent(blockfilter.AcceptAny, eventch, regch, errch)
+	checkErrorFromReg(errch, t, regch)
+
+	// Produce block - it should fail verification and cause a disconnect
+	ledger.NewBlock(channelID)
+
+	for disconnected := false; !disconnected; {
+		select {
+		case event := <-conneventch:
+			disconnected = !event.Connected
+		case <-eventch:
+			t.Fatalf("block which failed verification should not be published")
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for disconnected event")
+		}
+	}
+
+	// Stop
+	stopResp := make(chan error)
+	dispatcherEventch <- esdispatcher.NewStopEvent(stopResp)
+	if err := <-stopResp; err != nil {
+		t.Fatalf("Error stopping dispatcher: %s", err)
+	}
+}
+
 func checkBlockEvents(eventch chan *fab.BlockEvent, t *testing.T) {
 	select {
 	case event, ok := <-eventch:
@@ -0,0 +1,26 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dispatcher
+
+import (
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+// BlockVerifier verifies the blocks received from the event server
+type BlockVerifier interface {
+	Verify(block *cb.Block) error
+}
+
+type params struct {
+	blockVerifier BlockVerifier
+}
+
+// SetBlockVerifier sets the verifier of received blocks
+func (p *params) SetBlockVerifier(value BlockVerifier) {
+	logger.Debugf("BlockVerifier: %#v", value)
+	p.blockVerifier = value
+}
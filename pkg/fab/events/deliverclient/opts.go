@@ -11,6 +11,7 @@ import (
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/dispatcher"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
 )
 
@@ -48,6 +49,18 @@ func WithBlockNum(value uint64) options.Opt {
 	}
 }
 
+// WithBlockVerifier specifies the verifier of the blocks received from the peer. Blocks which fail
+// verification aren't published and the client reconnects. Note that filtered blocks can't be verified.
+// By default, the blocks of channels which are ordered by a BFT ordering service are verified to be
+// signed by a quorum of the consenters.
+func WithBlockVerifier(value dispatcher.BlockVerifier) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(blockVerifierSetter); ok {
+			setter.SetBlockVerifier(value)
+		}
+	}
+}
+
 type seekTypeSetter interface {
 	SetSeekType(value seek.Type)
 }
@@ -56,6 +69,10 @@ type fromBlockSetter interface {
 	SetFromBlock(value uint64)
 }
 
+type blockVerifierSetter interface {
+	SetBlockVerifier(value dispatcher.BlockVerifier)
+}
+
 func (p *params) PermitBlockEvents() {
 	logger.Debugf("PermitBlockEvents")
 	p.connProvider = deliverProvider
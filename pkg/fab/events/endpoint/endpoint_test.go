@@ -40,7 +40,7 @@ func TestEndpoint(t *testing.T) {
 	expectedKeepAliveTime := time.Second
 	expectedKeepAliveTimeout := time.Second
 	expectedKeepAlivePermit := true
-	expectedNumOpts := 6
+	expectedNumOpts := 12
 
 	config := fabmocks.NewMockEndpointConfig()
 	peer := fabmocks.NewMockPeer("p1", "localhost:7051")
@@ -19,6 +19,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	logging "github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	comm "github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
@@ -104,12 +105,13 @@ func (c *EventHubConnection) Send(emsg *pb.Event) error {
 func (c *EventHubConnection) Receive(eventch chan<- interface{}) {
 	for {
 		logger.Debugf("Listening for events...")
-		if c.EventHubStream() == nil {
+		stream := c.EventHubStream()
+		if stream == nil {
 			logger.Warnf("The stream has closed. Terminating loop.")
 			break
 		}
 
-		in, err := c.EventHubStream().Recv()
+		in, err := stream.Recv()
 
 		if c.Closed() {
 			logger.Debugf("The connection has closed. Terminating loop.")
@@ -124,7 +126,7 @@ func (c *EventHubConnection) Receive(eventch chan<- interface{}) {
 
 		if err != nil {
 			logger.Errorf("Received error from stream: [%s]. Sending disconnected event.", err)
-			eventch <- clientdisp.NewDisconnectedEvent(err)
+			eventch <- clientdisp.NewDisconnectedEvent(status.FromStreamError(err, stream))
 			break
 		}
 		logger.Debugf("Got event %#v", in)
@@ -0,0 +1,101 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package namespacefilter
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+var logger = logging.NewLogger("eventservice/blockfilter")
+
+// New returns a block filter that filters out blocks that don't contain
+// endorser transactions which invoke the given namespace(s) (chaincode IDs)
+func New(namespaces ...string) fab.BlockFilter {
+	return func(block *cb.Block) bool {
+		return hasNamespace(block, namespaces...)
+	}
+}
+
+func hasNamespace(block *cb.Block, namespaces ...string) bool {
+	for i := 0; i < len(block.Data.Data); i++ {
+		env, err := utils.ExtractEnvelope(block, i)
+		if err != nil {
+			logger.Errorf("error extracting envelope from block: %s", err)
+			continue
+		}
+		payload, err := utils.ExtractPayload(env)
+		if err != nil {
+			logger.Errorf("error extracting payload from block: %s", err)
+			continue
+		}
+		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			logger.Errorf("error extracting channel header: %s", err)
+			continue
+		}
+		if cb.HeaderType(chdr.Type) != cb.HeaderType_ENDORSER_TRANSACTION {
+			continue
+		}
+		txNamespaces, err := Namespaces(payload)
+		if err != nil {
+			logger.Errorf("error extracting namespaces of transaction [%s]: %s", chdr.TxId, err)
+			continue
+		}
+		if Contains(txNamespaces, namespaces...) {
+			return true
+		}
+	}
+	return false
+}
+
+// Namespaces returns the namespaces (chaincode IDs) invoked by the actions of the endorser transaction in the payload
+func Namespaces(payload *cb.Payload) ([]string, error) {
+	tx, err := utils.GetTransaction(payload.Data)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling transaction payload")
+	}
+
+	var namespaces []string
+	for _, action := range tx.Actions {
+		chaincodeActionPayload, err := utils.GetChaincodeActionPayload(action.Payload)
+		if err != nil {
+			return nil, errors.Wrap(err, "error unmarshalling chaincode action payload")
+		}
+		if chaincodeActionPayload.Action == nil {
+			continue
+		}
+		propRespPayload, err := utils.GetProposalResponsePayload(chaincodeActionPayload.Action.ProposalResponsePayload)
+		if err != nil {
+			return nil, errors.Wrap(err, "error unmarshalling response payload")
+		}
+		chaincodeAction, err := utils.GetChaincodeAction(propRespPayload.Extension)
+		if err != nil {
+			return nil, errors.Wrap(err, "error unmarshalling chaincode action")
+		}
+		if chaincodeAction.ChaincodeId != nil && chaincodeAction.ChaincodeId.Name != "" {
+			namespaces = append(namespaces, chaincodeAction.ChaincodeId.Name)
+		}
+	}
+	return namespaces, nil
+}
+
+// Contains returns true if any of the given namespaces is one of the namespaces
+func Contains(namespaces []string, candidates ...string) bool {
+	for _, ns := range namespaces {
+		for _, candidate := range candidates {
+			if ns == candidate {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package namespacefilter
+
+import (
+	"testing"
+
+	servicemocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/mocks"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+func TestNamespaceBlockFilter(t *testing.T) {
+	filter := New("mycc1", "mycc2")
+
+	if !filter(servicemocks.NewBlock("somechannel", servicemocks.NewTransactionWithCCEvent("txid", pb.TxValidationCode_VALID, "mycc1", "event", nil))) {
+		t.Fatalf("expecting block filter to accept block with transaction of mycc1")
+	}
+	if !filter(servicemocks.NewBlock("somechannel",
+		servicemocks.NewTransactionWithCCEvent("txid1", pb.TxValidationCode_VALID, "othercc", "event", nil),
+		servicemocks.NewTransactionWithCCEvent("txid2", pb.TxValidationCode_VALID, "mycc2", "event", nil),
+	)) {
+		t.Fatalf("expecting block filter to accept block with transaction of mycc2")
+	}
+	if filter(servicemocks.NewBlock("somechannel", servicemocks.NewTransactionWithCCEvent("txid", pb.TxValidationCode_VALID, "othercc", "event", nil))) {
+		t.Fatalf("expecting block filter to reject block without transactions of the namespaces")
+	}
+	if filter(servicemocks.NewBlock("somechannel", servicemocks.NewTransaction("txid", pb.TxValidationCode_VALID, cb.HeaderType_CONFIG))) {
+		t.Fatalf("expecting block filter to reject block with header type %s", cb.HeaderType_CONFIG)
+	}
+}
@@ -10,10 +10,12 @@ import (
 	"math"
 	"reflect"
 	"regexp"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/recovery"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
@@ -42,6 +44,8 @@ type HandlerRegistry map[reflect.Type]Handler
 // and events originating from the channel event service. All events are processed in a single Go routine
 // in order to avoid any race conditions and to ensure that events are processed in the order in which they are received.
 // This also avoids the need for synchronization.
+// The events may be sent to the registered consumers by workers (see WithDispatcherWorkers and WithDedicatedWorkers),
+// so that a slow consumer doesn't delay the processing of the events.
 type Dispatcher struct {
 	params
 	handlers                   map[reflect.Type]Handler
@@ -52,6 +56,8 @@ type Dispatcher struct {
 	ccRegistrations            map[string]*ChaincodeReg
 	state                      int32
 	lastBlockNum               uint64
+	workers                    []*worker
+	nextWorker                 int
 }
 
 // New creates a new Dispatcher.
@@ -104,6 +110,7 @@ func (ed *Dispatcher) Start() error {
 	}
 
 	ed.RegisterHandlers()
+	ed.startWorkers()
 
 	go func() {
 		for {
@@ -121,7 +128,10 @@ func (ed *Dispatcher) Start() error {
 
 			if handler, ok := ed.handlers[reflect.TypeOf(e)]; ok {
 				logger.Debugf("Dispatching event: %v", reflect.TypeOf(e))
-				handler(e)
+				// A panicking handler must not stop the delivery of the events
+				if err := recovery.Call(func() { handler(e) }); err != nil {
+					logger.Errorf("Handler for %s failed: %s\n%s", reflect.TypeOf(e), err, err.(*recovery.PanicError).Stack)
+				}
 			} else {
 				logger.Errorf("Handler not found for: %s", reflect.TypeOf(e))
 			}
@@ -153,7 +163,8 @@ func (ed *Dispatcher) updateLastBlockNum(blockNum uint64) error {
 // The listener will receive a 'closed' event to indicate that the channel has been closed.
 func (ed *Dispatcher) clearBlockRegistrations() {
 	for _, reg := range ed.blockRegistrations {
-		close(reg.Eventch)
+		reg := reg
+		ed.closeEventch(reg.worker, func() { close(reg.Eventch) })
 	}
 	ed.blockRegistrations = nil
 }
@@ -162,7 +173,8 @@ func (ed *Dispatcher) clearBlockRegistrations() {
 // The listener will receive a 'closed' event to indicate that the channel has been closed.
 func (ed *Dispatcher) clearFilteredBlockRegistrations() {
 	for _, reg := range ed.filteredBlockRegistrations {
-		close(reg.Eventch)
+		reg := reg
+		ed.closeEventch(reg.worker, func() { close(reg.Eventch) })
 	}
 	ed.filteredBlockRegistrations = nil
 }
@@ -172,7 +184,8 @@ func (ed *Dispatcher) clearFilteredBlockRegistrations() {
 func (ed *Dispatcher) clearTxRegistrations() {
 	for _, reg := range ed.txRegistrations {
 		logger.Debugf("Closing TX registration event channel for TxID [%s].", reg.TxID)
-		close(reg.Eventch)
+		reg := reg
+		ed.closeEventch(reg.worker, func() { close(reg.Eventch) })
 	}
 	ed.txRegistrations = make(map[string]*TxStatusReg)
 }
@@ -182,7 +195,8 @@ func (ed *Dispatcher) clearTxRegistrations() {
 func (ed *Dispatcher) clearChaincodeRegistrations() {
 	for _, reg := range ed.ccRegistrations {
 		logger.Debugf("Closing chaincode registration event channel for CC ID [%s] and event filter [%s].", reg.ChaincodeID, reg.EventFilter)
-		close(reg.Eventch)
+		reg := reg
+		ed.closeEventch(reg.worker, func() { close(reg.Eventch) })
 	}
 	ed.ccRegistrations = make(map[string]*ChaincodeReg)
 }
@@ -204,6 +218,7 @@ func (ed *Dispatcher) HandleStopEvent(e Event) {
 	ed.clearFilteredBlockRegistrations()
 	ed.clearTxRegistrations()
 	ed.clearChaincodeRegistrations()
+	ed.stopWorkers()
 
 	event.ErrCh <- nil
 }
@@ -211,12 +226,14 @@ func (ed *Dispatcher) HandleStopEvent(e Event) {
 func (ed *Dispatcher) handleRegisterBlockEvent(e Event) {
 	event := e.(*RegisterBlockEvent)
 
+	event.Reg.worker = ed.assignWorker(false)
 	ed.blockRegistrations = append(ed.blockRegistrations, event.Reg)
 	event.RegCh <- event.Reg
 }
 
 func (ed *Dispatcher) handleRegisterFilteredBlockEvent(e Event) {
 	event := e.(*RegisterFilteredBlockEvent)
+	event.Reg.worker = ed.assignWorker(false)
 	ed.filteredBlockRegistrations = append(ed.filteredBlockRegistrations, event.Reg)
 	event.RegCh <- event.Reg
 }
@@ -233,6 +250,7 @@ func (ed *Dispatcher) handleRegisterCCEvent(e Event) {
 			event.ErrCh <- errors.Wrapf(err, "error compiling regular expression for event filter [%s]", event.Reg.EventFilter)
 		} else {
 			event.Reg.EventRegExp = regExp
+			event.Reg.worker = ed.assignWorker(ed.dedicatedWorkerCCIDs[event.Reg.ChaincodeID])
 			ed.ccRegistrations[key] = event.Reg
 			event.RegCh <- event.Reg
 		}
@@ -245,6 +263,7 @@ func (ed *Dispatcher) handleRegisterTxStatusEvent(e Event) {
 	if _, exists := ed.txRegistrations[event.Reg.TxID]; exists {
 		event.ErrCh <- errors.Errorf("registration already exists for TX ID [%s]", event.Reg.TxID)
 	} else {
+		event.Reg.worker = ed.assignWorker(false)
 		ed.txRegistrations[event.Reg.TxID] = event.Reg
 		event.RegCh <- event.Reg
 	}
@@ -307,9 +326,22 @@ func (ed *Dispatcher) HandleBlock(block *cb.Block, sourceURL string) {
 	}
 
 	ed.publishBlockEvents(block, sourceURL)
+
+	// Converting the block requires unmarshalling every transaction of the block, so it's
+	// only done if there's someone to publish the filtered block, Tx Status or CC events to
+	if !ed.hasFilteredBlockConsumers() {
+		logger.Debugf("No filtered block, Tx Status or CC registrations - not converting block #%d", block.Header.Number)
+		return
+	}
 	ed.publishFilteredBlockEvents(toFilteredBlock(block), sourceURL)
 }
 
+// hasFilteredBlockConsumers returns true if there are registrations for the events
+// which are published from filtered blocks
+func (ed *Dispatcher) hasFilteredBlockConsumers() bool {
+	return len(ed.filteredBlockRegistrations) > 0 || len(ed.txRegistrations) > 0 || len(ed.ccRegistrations) > 0
+}
+
 // HandleFilteredBlock handles a filtered block event
 func (ed *Dispatcher) HandleFilteredBlock(fblock *pb.FilteredBlock, sourceURL string) {
 	logger.Debugf("Handling filtered block event - Block #%d", fblock.Number)
@@ -329,7 +361,7 @@ func (ed *Dispatcher) unregisterBlockEvents(registration *BlockReg) error {
 			// Move the 0'th item to i and then delete the 0'th item
 			ed.blockRegistrations[i] = ed.blockRegistrations[0]
 			ed.blockRegistrations = ed.blockRegistrations[1:]
-			close(reg.Eventch)
+			ed.closeEventch(reg.worker, func() { close(reg.Eventch) })
 			return nil
 		}
 	}
@@ -342,7 +374,7 @@ func (ed *Dispatcher) unregisterFilteredBlockEvents(registration *FilteredBlockR
 			// Move the 0'th item to i and then delete the 0'th item
 			ed.filteredBlockRegistrations[i] = ed.filteredBlockRegistrations[0]
 			ed.filteredBlockRegistrations = ed.filteredBlockRegistrations[1:]
-			close(reg.Eventch)
+			ed.closeEventch(reg.worker, func() { close(reg.Eventch) })
 			return nil
 		}
 	}
@@ -357,7 +389,7 @@ func (ed *Dispatcher) unregisterCCEvents(registration *ChaincodeReg) error {
 	}
 
 	logger.Debugf("Unregistering CC event for CC ID [%s] and event filter [%s]...", registration.ChaincodeID, registration.EventFilter)
-	close(reg.Eventch)
+	ed.closeEventch(reg.worker, func() { close(reg.Eventch) })
 	delete(ed.ccRegistrations, key)
 	return nil
 }
@@ -369,33 +401,41 @@ func (ed *Dispatcher) unregisterTXEvents(registration *TxStatusReg) error {
 	}
 
 	logger.Debugf("Unregistering Tx Status event for TxID [%s]...", registration.TxID)
-	close(reg.Eventch)
+	ed.closeEventch(reg.worker, func() { close(reg.Eventch) })
 	delete(ed.txRegistrations, registration.TxID)
 	return nil
 }
 
 func (ed *Dispatcher) publishBlockEvents(block *cb.Block, sourceURL string) {
 	for _, reg := range ed.blockRegistrations {
-		if !reg.Filter(block) {
+		var accepted bool
+		if err := recovery.Call(func() { accepted = reg.Filter(block) }); err != nil {
+			logger.Errorf("Block filter failed - not sending block event for block #%d: %s", block.Header.Number, err)
+			continue
+		}
+		if !accepted {
 			logger.Debugf("Not sending block event for block #%d since it was filtered out.", block.Header.Number)
 			continue
 		}
 
-		if ed.eventConsumerTimeout < 0 {
-			select {
-			case reg.Eventch <- NewBlockEvent(block, sourceURL):
-			default:
-				logger.Warnf("Unable to send to block event channel.")
-			}
-		} else if ed.eventConsumerTimeout == 0 {
-			reg.Eventch <- NewBlockEvent(block, sourceURL)
-		} else {
-			select {
-			case reg.Eventch <- NewBlockEvent(block, sourceURL):
-			case <-time.After(ed.eventConsumerTimeout):
-				logger.Warnf("Timed out sending block event.")
+		reg := reg
+		ed.send(reg.worker, func() {
+			if ed.eventConsumerTimeout < 0 {
+				select {
+				case reg.Eventch <- NewBlockEvent(block, sourceURL):
+				default:
+					logger.Warnf("Unable to send to block event channel.")
+				}
+			} else if ed.eventConsumerTimeout == 0 {
+				reg.Eventch <- NewBlockEvent(block, sourceURL)
+			} else {
+				select {
+				case reg.Eventch <- NewBlockEvent(block, sourceURL):
+				case <-time.After(ed.eventConsumerTimeout):
+					logger.Warnf("Timed out sending block event.")
+				}
 			}
-		}
+		})
 	}
 }
 
@@ -429,21 +469,24 @@ func (ed *Dispatcher) publishFilteredBlockEvents(fblock *pb.FilteredBlock, sourc
 
 func checkFilteredBlockRegistrations(ed *Dispatcher, fblock *pb.FilteredBlock, sourceURL string) {
 	for _, reg := range ed.filteredBlockRegistrations {
-		if ed.eventConsumerTimeout < 0 {
-			select {
-			case reg.Eventch <- NewFilteredBlockEvent(fblock, sourceURL):
-			default:
-				logger.Warnf("Unable to send to filtered block event channel.")
-			}
-		} else if ed.eventConsumerTimeout == 0 {
-			reg.Eventch <- NewFilteredBlockEvent(fblock, sourceURL)
-		} else {
-			select {
-			case reg.Eventch <- NewFilteredBlockEvent(fblock, sourceURL):
-			case <-time.After(ed.eventConsumerTimeout):
-				logger.Warnf("Timed out sending filtered block event.")
+		reg := reg
+		ed.send(reg.worker, func() {
+			if ed.eventConsumerTimeout < 0 {
+				select {
+				case reg.Eventch <- NewFilteredBlockEvent(fblock, sourceURL):
+				default:
+					logger.Warnf("Unable to send to filtered block event channel.")
+				}
+			} else if ed.eventConsumerTimeout == 0 {
+				reg.Eventch <- NewFilteredBlockEvent(fblock, sourceURL)
+			} else {
+				select {
+				case reg.Eventch <- NewFilteredBlockEvent(fblock, sourceURL):
+				case <-time.After(ed.eventConsumerTimeout):
+					logger.Warnf("Timed out sending filtered block event.")
+				}
 			}
-		}
+		})
 	}
 }
 
@@ -452,21 +495,23 @@ func (ed *Dispatcher) publishTxStatusEvents(tx *pb.FilteredTransaction, blockNum
 	if reg, ok := ed.txRegistrations[tx.Txid]; ok {
 		logger.Debugf("Sending Tx Status event for TxID [%s] to registrant...", tx.Txid)
 
-		if ed.eventConsumerTimeout < 0 {
-			select {
-			case reg.Eventch <- NewTxStatusEvent(tx.Txid, tx.TxValidationCode, blockNum, sourceURL):
-			default:
-				logger.Warnf("Unable to send to Tx Status event channel.")
-			}
-		} else if ed.eventConsumerTimeout == 0 {
-			reg.Eventch <- NewTxStatusEvent(tx.Txid, tx.TxValidationCode, blockNum, sourceURL)
-		} else {
-			select {
-			case reg.Eventch <- NewTxStatusEvent(tx.Txid, tx.TxValidationCode, blockNum, sourceURL):
-			case <-time.After(ed.eventConsumerTimeout):
-				logger.Warnf("Timed out sending Tx Status event.")
+		ed.send(reg.worker, func() {
+			if ed.eventConsumerTimeout < 0 {
+				select {
+				case reg.Eventch <- NewTxStatusEvent(tx.Txid, tx.TxValidationCode, blockNum, sourceURL):
+				default:
+					logger.Warnf("Unable to send to Tx Status event channel.")
+				}
+			} else if ed.eventConsumerTimeout == 0 {
+				reg.Eventch <- NewTxStatusEvent(tx.Txid, tx.TxValidationCode, blockNum, sourceURL)
+			} else {
+				select {
+				case reg.Eventch <- NewTxStatusEvent(tx.Txid, tx.TxValidationCode, blockNum, sourceURL):
+				case <-time.After(ed.eventConsumerTimeout):
+					logger.Warnf("Timed out sending Tx Status event.")
+				}
 			}
-		}
+		})
 	}
 }
 
@@ -476,21 +521,24 @@ func (ed *Dispatcher) publishCCEvents(ccEvent *pb.ChaincodeEvent, blockNum uint6
 		if reg.ChaincodeID == ccEvent.ChaincodeId && reg.EventRegExp.MatchString(ccEvent.EventName) {
 			logger.Debugf("... matched CCEvent[%s,%s] against Reg[%s,%s]", ccEvent.ChaincodeId, ccEvent.EventName, reg.ChaincodeID, reg.EventFilter)
 
-			if ed.eventConsumerTimeout < 0 {
-				select {
-				case reg.Eventch <- NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, ccEvent.Payload, blockNum, sourceURL):
-				default:
-					logger.Warnf("Unable to send to CC event channel.")
+			reg := reg
+			ed.send(reg.worker, func() {
+				if ed.eventConsumerTimeout < 0 {
+					select {
+					case reg.Eventch <- NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, ccEvent.Payload, blockNum, sourceURL):
+					default:
+						logger.Warnf("Unable to send to CC event channel.")
+					}
+				} else if ed.eventConsumerTimeout == 0 {
+					reg.Eventch <- NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, ccEvent.Payload, blockNum, sourceURL)
+				} else {
+					select {
+					case reg.Eventch <- NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, ccEvent.Payload, blockNum, sourceURL):
+					case <-time.After(ed.eventConsumerTimeout):
+						logger.Warnf("Timed out sending CC event.")
+					}
 				}
-			} else if ed.eventConsumerTimeout == 0 {
-				reg.Eventch <- NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, ccEvent.Payload, blockNum, sourceURL)
-			} else {
-				select {
-				case reg.Eventch <- NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, ccEvent.Payload, blockNum, sourceURL):
-				case <-time.After(ed.eventConsumerTimeout):
-					logger.Warnf("Timed out sending CC event.")
-				}
-			}
+			})
 		}
 	}
 }
@@ -532,25 +580,60 @@ func toFilteredBlock(block *cb.Block) *pb.FilteredBlock {
 	}
 }
 
+// The intermediate messages which are unmarshalled while converting a block are pooled, since
+// none of them is referenced by the filtered block: unmarshalling copies strings and bytes and
+// the chaincode event is a new message.
+var (
+	envelopePool    = sync.Pool{New: func() interface{} { return &cb.Envelope{} }}
+	payloadPool     = sync.Pool{New: func() interface{} { return &cb.Payload{} }}
+	chHeaderPool    = sync.Pool{New: func() interface{} { return &cb.ChannelHeader{} }}
+	txPool          = sync.Pool{New: func() interface{} { return &pb.Transaction{} }}
+	ccActionPayPool = sync.Pool{New: func() interface{} { return &pb.ChaincodeActionPayload{} }}
+	respPayloadPool = sync.Pool{New: func() interface{} { return &pb.ProposalResponsePayload{} }}
+	ccActionPool    = sync.Pool{New: func() interface{} { return &pb.ChaincodeAction{} }}
+)
+
+// unmarshalPooled unmarshals data into a message of the pool. The returned function releases the message.
+func unmarshalPooled(pool *sync.Pool, data []byte) (proto.Message, func(), error) {
+	msg := pool.Get().(proto.Message)
+	release := func() {
+		msg.Reset()
+		pool.Put(msg)
+	}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		release()
+		return nil, nil, err
+	}
+	return msg, release, nil
+}
+
 func getFilteredTx(data []byte, txValidationCode pb.TxValidationCode) (*pb.FilteredTransaction, string, error) {
-	env, err := utils.GetEnvelopeFromBlock(data)
+	if data == nil {
+		return nil, "", errors.New("nil envelope")
+	}
+	msg, release, err := unmarshalPooled(&envelopePool, data)
 	if err != nil {
 		return nil, "", errors.Wrap(err, "error extracting Envelope from block")
 	}
-	if env == nil {
-		return nil, "", errors.New("nil envelope")
-	}
+	defer release()
+	env := msg.(*cb.Envelope)
 
-	payload, err := utils.GetPayload(env)
+	msg, release, err = unmarshalPooled(&payloadPool, env.Payload)
 	if err != nil {
 		return nil, "", errors.Wrap(err, "error extracting Payload from envelope")
 	}
+	defer release()
+	payload := msg.(*cb.Payload)
+	if payload.Header == nil {
+		return nil, "", errors.New("error extracting ChannelHeader from payload: missing header")
+	}
 
-	channelHeaderBytes := payload.Header.ChannelHeader
-	channelHeader := &cb.ChannelHeader{}
-	if err := proto.Unmarshal(channelHeaderBytes, channelHeader); err != nil {
+	msg, release, err = unmarshalPooled(&chHeaderPool, payload.Header.ChannelHeader)
+	if err != nil {
 		return nil, "", errors.Wrap(err, "error extracting ChannelHeader from payload")
 	}
+	defer release()
+	channelHeader := msg.(*cb.ChannelHeader)
 
 	filteredTx := &pb.FilteredTransaction{
 		Type:             cb.HeaderType(channelHeader.Type),
@@ -572,22 +655,40 @@ func getFilteredTransactionActions(data []byte) (*pb.FilteredTransaction_Transac
 	actions := &pb.FilteredTransaction_TransactionActions{
 		TransactionActions: &pb.FilteredTransactionActions{},
 	}
-	tx, err := utils.GetTransaction(data)
+	msg, release, err := unmarshalPooled(&txPool, data)
 	if err != nil {
 		return nil, errors.Wrap(err, "error unmarshalling transaction payload")
 	}
-	chaincodeActionPayload, err := utils.GetChaincodeActionPayload(tx.Actions[0].Payload)
+	defer release()
+	tx := msg.(*pb.Transaction)
+	if len(tx.Actions) == 0 {
+		return nil, errors.New("error unmarshalling transaction payload: no actions")
+	}
+
+	msg, release, err = unmarshalPooled(&ccActionPayPool, tx.Actions[0].Payload)
 	if err != nil {
 		return nil, errors.Wrap(err, "error unmarshalling chaincode action payload")
 	}
-	propRespPayload, err := utils.GetProposalResponsePayload(chaincodeActionPayload.Action.ProposalResponsePayload)
+	defer release()
+	chaincodeActionPayload := msg.(*pb.ChaincodeActionPayload)
+	if chaincodeActionPayload.Action == nil {
+		return nil, errors.New("error unmarshalling chaincode action payload: no action")
+	}
+
+	msg, release, err = unmarshalPooled(&respPayloadPool, chaincodeActionPayload.Action.ProposalResponsePayload)
 	if err != nil {
 		return nil, errors.Wrap(err, "error unmarshalling response payload")
 	}
-	ccAction, err := utils.GetChaincodeAction(propRespPayload.Extension)
+	defer release()
+	propRespPayload := msg.(*pb.ProposalResponsePayload)
+
+	msg, release, err = unmarshalPooled(&ccActionPool, propRespPayload.Extension)
 	if err != nil {
 		return nil, errors.Wrap(err, "error unmarshalling chaincode action")
 	}
+	defer release()
+	ccAction := msg.(*pb.ChaincodeAction)
+
 	ccEvent, err := utils.GetChaincodeEvents(ccAction.Events)
 	if err != nil {
 		return nil, errors.Wrap(err, "error getting chaincode events")
@@ -87,6 +87,88 @@ func TestBlockEvents(t *testing.T) {
 	}
 }
 
+func TestPanickingBlockFilter(t *testing.T) {
+	channelID := "testchannel"
+	dispatcher := New()
+	if err := dispatcher.Start(); err != nil {
+		t.Fatalf("Error starting dispatcher: %s", err)
+	}
+
+	dispatcherEventch, err := dispatcher.EventCh()
+	if err != nil {
+		t.Fatalf("Error getting event channel from dispatcher: %s", err)
+	}
+
+	panickingFilter := func(block *cb.Block) bool {
+		panic("filter failed")
+	}
+
+	regch := make(chan fab.Registration)
+	errch := make(chan error)
+	eventch1 := make(chan *fab.BlockEvent, 10)
+	eventch2 := make(chan *fab.BlockEvent, 10)
+	for _, reg := range []*RegisterBlockEvent{
+		NewRegisterBlockEvent(panickingFilter, eventch1, regch, errch),
+		NewRegisterBlockEvent(blockfilter.AcceptAny, eventch2, regch, errch),
+	} {
+		dispatcherEventch <- reg
+		select {
+		case <-regch:
+		case err := <-errch:
+			t.Fatalf("Error registering for block events: %s", err)
+		}
+	}
+
+	// The block is delivered to the other registrations and the dispatcher keeps running
+	blockProducer := servicemocks.NewBlockProducer()
+	for i := 0; i < 2; i++ {
+		dispatcherEventch <- NewBlockEvent(blockProducer.NewBlock(channelID), sourceURL)
+		select {
+		case <-eventch2:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for block event")
+		}
+	}
+	if len(eventch1) != 0 {
+		t.Fatalf("expecting no block events for the panicking filter")
+	}
+
+	stopResp := make(chan error)
+	dispatcherEventch <- NewStopEvent(stopResp)
+	if err := <-stopResp; err != nil {
+		t.Fatalf("Error stopping dispatcher: %s", err)
+	}
+}
+
+func TestPanickingHandler(t *testing.T) {
+	dispatcher := New()
+	// Handlers registered before Start take precedence over the default handlers
+	dispatcher.RegisterHandler(&fab.BlockEvent{}, func(e Event) {
+		panic("handler failed")
+	})
+	if err := dispatcher.Start(); err != nil {
+		t.Fatalf("Error starting dispatcher: %s", err)
+	}
+
+	dispatcherEventch, err := dispatcher.EventCh()
+	if err != nil {
+		t.Fatalf("Error getting event channel from dispatcher: %s", err)
+	}
+	dispatcherEventch <- &fab.BlockEvent{}
+
+	// The dispatcher keeps processing events
+	stopResp := make(chan error)
+	dispatcherEventch <- NewStopEvent(stopResp)
+	select {
+	case err := <-stopResp:
+		if err != nil {
+			t.Fatalf("Error stopping dispatcher: %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for dispatcher to stop")
+	}
+}
+
 func TestBlockEventsWithFilter(t *testing.T) {
 	channelID := "testchannel"
 	dispatcher := New()
@@ -717,6 +799,88 @@ func checkCCEventsFiltered(eventch1 chan *fab.CCEvent, t *testing.T, ccID1 strin
 	}
 }
 
+func TestDedicatedWorkers(t *testing.T) {
+	channelID := "testchannel"
+	slowCCID := "slowcc"
+	dispatcher := New(
+		WithEventConsumerTimeout(0),
+		WithDispatcherWorkers(2),
+		WithDedicatedWorkers(slowCCID),
+	)
+	if err := dispatcher.Start(); err != nil {
+		t.Fatalf("Error starting dispatcher: %s", err)
+	}
+
+	dispatcherEventch, err := dispatcher.EventCh()
+	if err != nil {
+		t.Fatalf("Error getting event channel from dispatcher: %s", err)
+	}
+
+	regch := make(chan fab.Registration)
+	errch := make(chan error)
+
+	// The consumer of the chaincode events doesn't receive them for now
+	cceventch := make(chan *fab.CCEvent)
+	dispatcherEventch <- NewRegisterChaincodeEvent(slowCCID, ".*", cceventch, regch, errch)
+	var ccreg fab.Registration
+	select {
+	case ccreg = <-regch:
+	case err := <-errch:
+		t.Fatalf("error registering for chaincode events: %s", err)
+	}
+
+	txID1 := "1234"
+	txID2 := "5678"
+	txeventch, dispatcherEventch, txreg := registerEvent(dispatcherEventch, txID2, regch, errch, t)
+
+	dispatcherEventch <- NewFilteredBlockEvent(servicemocks.NewBlockProducer().NewFilteredBlock(
+		channelID,
+		servicemocks.NewFilteredTxWithCCEvent(txID1, slowCCID, "event1"),
+		servicemocks.NewFilteredTx(txID2, pb.TxValidationCode_VALID),
+	), sourceURL)
+
+	// The Tx Status event isn't delayed by the consumer of the chaincode events
+	select {
+	case event, ok := <-txeventch:
+		if !ok {
+			t.Fatalf("unexpected closed channel")
+		}
+		checkTxStatusEvent(t, event, txID2, pb.TxValidationCode_VALID)
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for Tx Status event")
+	}
+
+	select {
+	case event, ok := <-cceventch:
+		if !ok {
+			t.Fatalf("unexpected closed channel")
+		}
+		if event.TxID != txID1 {
+			t.Fatalf("expecting chaincode event for TxID [%s] but got [%s]", txID1, event.TxID)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for chaincode event")
+	}
+
+	// The event channels are closed by the workers
+	dispatcherEventch <- NewUnregisterEvent(ccreg)
+	dispatcherEventch <- NewUnregisterEvent(txreg)
+	for _, closed := range []func() bool{
+		func() bool { _, ok := <-cceventch; return !ok },
+		func() bool { _, ok := <-txeventch; return !ok },
+	} {
+		if !closed() {
+			t.Fatalf("expecting closed event channel")
+		}
+	}
+
+	stopResp := make(chan error)
+	dispatcherEventch <- NewStopEvent(stopResp)
+	if err := <-stopResp; err != nil {
+		t.Fatalf("Error stopping dispatcher: %s", err)
+	}
+}
+
 func TestRegistrationInfo(t *testing.T) {
 	dispatcher := New()
 	if err := dispatcher.Start(); err != nil {
@@ -820,3 +984,45 @@ func checkCCEvent(t *testing.T, event *fab.CCEvent, expectedCCID string, expecte
 		t.Fatalf("expecting one of [%v] but received [%s]", expectedEventNames, event.EventName)
 	}
 }
+
+func TestToFilteredBlock(t *testing.T) {
+	channelID := "testchannel"
+	producer := servicemocks.NewBlockProducer()
+
+	block1 := producer.NewBlock(
+		channelID,
+		servicemocks.NewTransactionWithCCEvent("txid1", pb.TxValidationCode_VALID, "mycc1", "event1", []byte("payload1")),
+		servicemocks.NewTransaction("txid2", pb.TxValidationCode_MVCC_READ_CONFLICT, cb.HeaderType_ENDORSER_TRANSACTION),
+	)
+	block2 := producer.NewBlock(
+		channelID,
+		servicemocks.NewTransactionWithCCEvent("txid3", pb.TxValidationCode_VALID, "mycc2", "event2", []byte("payload2")),
+	)
+
+	// The messages used while converting block1 are reused for block2, which must not affect the first filtered block
+	fblock1 := toFilteredBlock(block1)
+	fblock2 := toFilteredBlock(block2)
+
+	if fblock1.ChannelId != channelID || len(fblock1.FilteredTransactions) != 2 {
+		t.Fatalf("unexpected filtered block: %#v", fblock1)
+	}
+	tx1 := fblock1.FilteredTransactions[0]
+	if tx1.Txid != "txid1" || tx1.TxValidationCode != pb.TxValidationCode_VALID {
+		t.Fatalf("unexpected filtered transaction: %#v", tx1)
+	}
+	ccEvent := tx1.GetTransactionActions().ChaincodeActions[0].ChaincodeEvent
+	if ccEvent.ChaincodeId != "mycc1" || ccEvent.EventName != "event1" || !bytes.Equal(ccEvent.Payload, []byte("payload1")) {
+		t.Fatalf("unexpected chaincode event: %#v", ccEvent)
+	}
+	if tx2 := fblock1.FilteredTransactions[1]; tx2.Txid != "txid2" || tx2.TxValidationCode != pb.TxValidationCode_MVCC_READ_CONFLICT {
+		t.Fatalf("unexpected filtered transaction: %#v", tx2)
+	}
+
+	if len(fblock2.FilteredTransactions) != 1 || fblock2.FilteredTransactions[0].Txid != "txid3" {
+		t.Fatalf("unexpected filtered block: %#v", fblock2)
+	}
+	ccEvent = fblock2.FilteredTransactions[0].GetTransactionActions().ChaincodeActions[0].ChaincodeEvent
+	if ccEvent.ChaincodeId != "mycc2" || !bytes.Equal(ccEvent.Payload, []byte("payload2")) {
+		t.Fatalf("unexpected chaincode event: %#v", ccEvent)
+	}
+}
@@ -15,6 +15,9 @@ import (
 type params struct {
 	eventConsumerBufferSize uint
 	eventConsumerTimeout    time.Duration
+	dispatcherWorkers       uint
+	handOffStrategy         HandOffStrategy
+	dedicatedWorkerCCIDs    map[string]bool
 }
 
 func defaultParams() *params {
@@ -45,6 +48,40 @@ func WithEventConsumerTimeout(value time.Duration) options.Opt {
 	}
 }
 
+// WithDispatcherWorkers sets the number of worker goroutines which send the events to the registered
+// consumers. Each registration is assigned to one of the workers, which sends its events in order.
+// If 0 (the default), the dispatcher sends the events itself, so that a consumer which doesn't receive
+// its events in time delays the events of all of the other consumers.
+// The size of the queue of each worker is the event consumer buffer size.
+func WithDispatcherWorkers(value uint) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(dispatcherWorkersSetter); ok {
+			setter.SetDispatcherWorkers(value)
+		}
+	}
+}
+
+// WithHandOffStrategy sets what the dispatcher does when the queue of the worker of a registration is full:
+// HandOffBlock (the default) waits for the worker and HandOffDrop drops the event.
+func WithHandOffStrategy(value HandOffStrategy) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(handOffStrategySetter); ok {
+			setter.SetHandOffStrategy(value)
+		}
+	}
+}
+
+// WithDedicatedWorkers gives each chaincode event registration of the given chaincodes a worker
+// of its own (whether or not there are shared workers), so that a slow (e.g. CPU-heavy) consumer of
+// their events only delays its own events and not, for instance, the transaction status events.
+func WithDedicatedWorkers(ccIDs ...string) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(dedicatedWorkersSetter); ok {
+			setter.SetDedicatedWorkers(ccIDs)
+		}
+	}
+}
+
 type eventConsumerBufferSizeSetter interface {
 	SetEventConsumerBufferSize(value uint)
 }
@@ -53,6 +90,18 @@ type eventEventConsumerTimeoutSetter interface {
 	SetEventConsumerTimeout(value time.Duration)
 }
 
+type dispatcherWorkersSetter interface {
+	SetDispatcherWorkers(value uint)
+}
+
+type handOffStrategySetter interface {
+	SetHandOffStrategy(value HandOffStrategy)
+}
+
+type dedicatedWorkersSetter interface {
+	SetDedicatedWorkers(ccIDs []string)
+}
+
 func (p *params) SetEventConsumerBufferSize(value uint) {
 	logger.Debugf("EventConsumerBufferSize: %d", value)
 	p.eventConsumerBufferSize = value
@@ -62,3 +111,21 @@ func (p *params) SetEventConsumerTimeout(value time.Duration) {
 	logger.Debugf("EventConsumerTimeout: %s", value)
 	p.eventConsumerTimeout = value
 }
+
+func (p *params) SetDispatcherWorkers(value uint) {
+	logger.Debugf("DispatcherWorkers: %d", value)
+	p.dispatcherWorkers = value
+}
+
+func (p *params) SetHandOffStrategy(value HandOffStrategy) {
+	logger.Debugf("HandOffStrategy: %d", value)
+	p.handOffStrategy = value
+}
+
+func (p *params) SetDedicatedWorkers(ccIDs []string) {
+	logger.Debugf("DedicatedWorkers: %v", ccIDs)
+	p.dedicatedWorkerCCIDs = make(map[string]bool)
+	for _, ccID := range ccIDs {
+		p.dedicatedWorkerCCIDs[ccID] = true
+	}
+}
@@ -16,11 +16,13 @@ import (
 type BlockReg struct {
 	Filter  fab.BlockFilter
 	Eventch chan<- *fab.BlockEvent
+	worker  *worker
 }
 
 // FilteredBlockReg contains the data for a filtered block registration
 type FilteredBlockReg struct {
 	Eventch chan<- *fab.FilteredBlockEvent
+	worker  *worker
 }
 
 // ChaincodeReg contains the data for a chaincode registration
@@ -29,10 +31,12 @@ type ChaincodeReg struct {
 	EventFilter string
 	EventRegExp *regexp.Regexp
 	Eventch     chan<- *fab.CCEvent
+	worker      *worker
 }
 
 // TxStatusReg contains the data for a transaction status registration
 type TxStatusReg struct {
 	TxID    string
 	Eventch chan<- *fab.TxStatusEvent
+	worker  *worker
 }
@@ -0,0 +1,113 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dispatcher
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/recovery"
+)
+
+// HandOffStrategy determines what the dispatcher does when the queue of the worker
+// which sends the events of a registration to its consumer is full
+type HandOffStrategy int
+
+const (
+	// HandOffBlock blocks the dispatcher until the worker accepts the event
+	HandOffBlock HandOffStrategy = iota
+	// HandOffDrop drops the event
+	HandOffDrop
+)
+
+// worker sends the events of its registrations to their consumers in the order in which
+// the dispatcher handed them off, so that a slow consumer doesn't delay the dispatcher
+type worker struct {
+	queue     chan func()
+	dedicated bool
+}
+
+func newWorker(queueSize uint, dedicated bool) *worker {
+	w := &worker{
+		queue:     make(chan func(), queueSize),
+		dedicated: dedicated,
+	}
+	go w.run()
+	return w
+}
+
+func (w *worker) run() {
+	for task := range w.queue {
+		if err := recovery.Call(task); err != nil {
+			logger.Errorf("Sending event failed: %s\n%s", err, err.(*recovery.PanicError).Stack)
+		}
+	}
+}
+
+// stop stops the worker once the tasks in its queue are done
+func (w *worker) stop() {
+	close(w.queue)
+}
+
+// startWorkers starts the shared workers
+func (ed *Dispatcher) startWorkers() {
+	for i := uint(0); i < ed.dispatcherWorkers; i++ {
+		ed.workers = append(ed.workers, newWorker(ed.eventConsumerBufferSize, false))
+	}
+}
+
+// stopWorkers stops the shared workers
+func (ed *Dispatcher) stopWorkers() {
+	for _, w := range ed.workers {
+		w.stop()
+	}
+	ed.workers = nil
+}
+
+// assignWorker returns the worker which sends the events of a new registration: a new worker
+// if the registration has a dedicated worker, or one of the shared workers in turn. Nil is
+// returned if there are no shared workers, in which case the dispatcher sends the events.
+func (ed *Dispatcher) assignWorker(dedicated bool) *worker {
+	if dedicated {
+		return newWorker(ed.eventConsumerBufferSize, true)
+	}
+	if len(ed.workers) == 0 {
+		return nil
+	}
+	w := ed.workers[ed.nextWorker%len(ed.workers)]
+	ed.nextWorker++
+	return w
+}
+
+// send sends an event to a consumer, either directly or by handing it off to the given worker
+func (ed *Dispatcher) send(w *worker, send func()) {
+	if w == nil {
+		send()
+		return
+	}
+
+	if ed.handOffStrategy == HandOffDrop {
+		select {
+		case w.queue <- send:
+		default:
+			logger.Warnf("Worker queue is full - dropping event.")
+		}
+		return
+	}
+	w.queue <- send
+}
+
+// closeEventch closes the event channel of a registration once the worker has sent the
+// events handed off to it. A dedicated worker is stopped.
+func (ed *Dispatcher) closeEventch(w *worker, closeEventch func()) {
+	if w == nil {
+		closeEventch()
+		return
+	}
+
+	w.queue <- closeEventch
+	if w.dedicated {
+		w.stop()
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/pkg/errors"
+)
+
+// Entry is a key-value pair of a batch
+type Entry struct {
+	Key   interface{}
+	Value interface{}
+}
+
+// BatchKVStore is implemented by key-value stores which support batch operations
+// (e.g. BoltDBKeyValueStore, LevelDBKeyValueStore and RedisKeyValueStore)
+type BatchKVStore interface {
+	core.KVStore
+
+	// LoadBatch returns the values for the given keys (in the same order).
+	// The value of a key which was not found is nil.
+	LoadBatch(keys []interface{}) ([]interface{}, error)
+
+	// StoreBatch sets the values of the given entries. Either all or none of the values are set.
+	StoreBatch(entries []Entry) error
+
+	// DeleteBatch deletes the values for the given keys.
+	DeleteBatch(keys []interface{}) error
+}
+
+// LoadBatch returns the values for the given keys (in the same order). The value of a key
+// which was not found is nil. If the store doesn't implement BatchKVStore then each value
+// is loaded separately.
+func LoadBatch(store core.KVStore, keys []interface{}) ([]interface{}, error) {
+	if batchStore, ok := store.(BatchKVStore); ok {
+		return batchStore.LoadBatch(keys)
+	}
+
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		value, err := store.Load(key)
+		if err != nil {
+			if err == core.ErrKeyValueNotFound {
+				continue
+			}
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// StoreBatch sets the values of the given entries. If the store doesn't implement
+// BatchKVStore then each value is stored separately (and the values which were
+// stored before a failure remain stored).
+func StoreBatch(store core.KVStore, entries []Entry) error {
+	if batchStore, ok := store.(BatchKVStore); ok {
+		return batchStore.StoreBatch(entries)
+	}
+
+	if err := validateEntries(entries); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := store.Store(entry.Key, entry.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteBatch deletes the values for the given keys. If the store doesn't
+// implement BatchKVStore then each value is deleted separately.
+func DeleteBatch(store core.KVStore, keys []interface{}) error {
+	if batchStore, ok := store.(BatchKVStore); ok {
+		return batchStore.DeleteBatch(keys)
+	}
+
+	for _, key := range keys {
+		if err := store.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateEntries(entries []Entry) error {
+	for _, entry := range entries {
+		if entry.Key == nil {
+			return errors.New("key is nil")
+		}
+		if entry.Value == nil {
+			return errors.New("value is nil")
+		}
+	}
+	return nil
+}
+
+func validateKeys(keys []interface{}) error {
+	for _, key := range keys {
+		if key == nil {
+			return errors.New("key is nil")
+		}
+	}
+	return nil
+}
+
+// serializeKeys validates and serializes the keys of a batch
+func serializeKeys(keySerializer KeySerializer, keys []interface{}) ([]string, error) {
+	if err := validateKeys(keys); err != nil {
+		return nil, err
+	}
+	serialized := make([]string, len(keys))
+	for i, key := range keys {
+		k, err := keySerializer(key)
+		if err != nil {
+			return nil, err
+		}
+		serialized[i] = k
+	}
+	return serialized, nil
+}
+
+// marshalEntries validates the entries of a batch and returns their serialized keys and marshalled values
+func marshalEntries(keySerializer KeySerializer, marshaller Marshaller, entries []Entry) ([]string, [][]byte, error) {
+	if err := validateEntries(entries); err != nil {
+		return nil, nil, err
+	}
+	keys := make([]string, len(entries))
+	values := make([][]byte, len(entries))
+	for i, entry := range entries {
+		k, err := keySerializer(entry.Key)
+		if err != nil {
+			return nil, nil, err
+		}
+		v, err := marshaller(entry.Value)
+		if err != nil {
+			return nil, nil, err
+		}
+		keys[i] = k
+		values[i] = v
+	}
+	return keys, values, nil
+}
@@ -0,0 +1,72 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"path"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var batchStorePath = "/tmp/testbatchkeyvaluestore"
+
+func TestBatch(t *testing.T) {
+	require.NoError(t, cleanup(batchStorePath))
+	defer cleanup(batchStorePath)
+
+	fileStore, err := New(&FileKeyValueStoreOptions{Path: path.Join(batchStorePath, "files")})
+	require.NoError(t, err)
+
+	boltStore, err := NewBoltDB(&BoltDBKeyValueStoreOptions{Path: path.Join(batchStorePath, "bolt.db"), Namespace: "batch"})
+	require.NoError(t, err)
+	defer boltStore.Close()
+
+	levelStore, err := NewLevelDB(&LevelDBKeyValueStoreOptions{Path: path.Join(batchStorePath, "leveldb")})
+	require.NoError(t, err)
+	defer levelStore.Close()
+
+	redisStore := newRedis(&RedisKeyValueStoreOptions{Address: "localhost:6379", Namespace: "batch"}, newMockRedisClient())
+
+	t.Run("File", func(t *testing.T) { testBatch(t, fileStore) })
+	t.Run("BoltDB", func(t *testing.T) { testBatch(t, boltStore) })
+	t.Run("LevelDB", func(t *testing.T) { testBatch(t, levelStore) })
+	t.Run("Redis", func(t *testing.T) { testBatch(t, redisStore) })
+}
+
+func testBatch(t *testing.T, store core.KVStore) {
+	assert.EqualError(t, StoreBatch(store, []Entry{{Key: "key1", Value: []byte("value1")}, {Key: nil, Value: []byte("value2")}}), "key is nil")
+	assert.EqualError(t, StoreBatch(store, []Entry{{Key: "key1", Value: []byte("value1")}, {Key: "key2"}}), "value is nil")
+	_, err := store.Load("key1")
+	assert.Equal(t, core.ErrKeyValueNotFound, err, "expected invalid batch not to be stored")
+
+	require.NoError(t, StoreBatch(store, []Entry{
+		{Key: "key1", Value: []byte("value1")},
+		{Key: "key2", Value: []byte("value2")},
+		{Key: "key3", Value: []byte("value3")},
+	}))
+
+	values, err := LoadBatch(store, []interface{}{"key3", "missing", "key1"})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{[]byte("value3"), nil, []byte("value1")}, values)
+
+	require.NoError(t, DeleteBatch(store, []interface{}{"key1", "key3", "missing"}))
+
+	values, err = LoadBatch(store, []interface{}{"key1", "key2", "key3"})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{nil, []byte("value2"), nil}, values)
+
+	assert.EqualError(t, DeleteBatch(store, []interface{}{nil}), "key is nil")
+
+	values, err = LoadBatch(store, nil)
+	require.NoError(t, err)
+	assert.Empty(t, values)
+	assert.NoError(t, StoreBatch(store, nil))
+	assert.NoError(t, DeleteBatch(store, nil))
+}
@@ -0,0 +1,400 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	bolt "github.com/coreos/bbolt"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/pkg/errors"
+)
+
+const boltDBOpenTimeout = 5 * time.Second
+
+// BoltDBKeyValueStore stores values in a bucket of a single-file bbolt database.
+// Each namespace (e.g. the user store, the key store or event checkpoints) is
+// stored in its own bucket so that several stores may share one database file.
+// KeySerializer maps a key to a unique key within the bucket.
+// Marshaller and Unmarshaller serialize/de-serialize a value
+// to and from the byte array that is stored in the bucket.
+//
+// The database file is locked by the process which opens it. Stores of the same
+// file within one process share the database, which is safe for concurrent access.
+type BoltDBKeyValueStore struct {
+	path          string
+	bucket        []byte
+	db            *bolt.DB
+	keySerializer KeySerializer
+	marshaller    Marshaller
+	unmarshaller  Unmarshaller
+	closeOnce     sync.Once
+}
+
+// BoltDBKeyValueStoreOptions allow overriding store defaults
+type BoltDBKeyValueStoreOptions struct {
+	// Database file path, mandatory
+	Path string
+	// Namespace is the name of the bucket, mandatory
+	Namespace string
+	// Optional. If not provided, the key (which must be a string) is used as is.
+	KeySerializer KeySerializer
+	// Optional. If not provided, default Marshaller is used.
+	Marshaller Marshaller
+	// Optional. If not provided, default Unmarshaller is used.
+	Unmarshaller Unmarshaller
+}
+
+// NewBoltDB opens (or creates) the bbolt database at the path of the given options and returns
+// a new instance of BoltDBKeyValueStore which stores values in the bucket of the given namespace
+func NewBoltDB(opts *BoltDBKeyValueStoreOptions) (*BoltDBKeyValueStore, error) {
+	if opts == nil {
+		return nil, errors.New("BoltDBKeyValueStoreOptions is nil")
+	}
+	if opts.Path == "" {
+		return nil, errors.New("BoltDBKeyValueStore path is empty")
+	}
+	if opts.Namespace == "" {
+		return nil, errors.New("BoltDBKeyValueStore namespace is empty")
+	}
+	if opts.KeySerializer == nil {
+		// Default key serializer
+		opts.KeySerializer = func(key interface{}) (string, error) {
+			keyString, ok := key.(string)
+			if !ok {
+				return "", errors.New("converting key to string failed")
+			}
+			return keyString, nil
+		}
+	}
+	if opts.Marshaller == nil {
+		opts.Marshaller = defaultMarshaller
+	}
+	if opts.Unmarshaller == nil {
+		opts.Unmarshaller = defaultUnmarshaller
+	}
+
+	path, err := filepath.Abs(opts.Path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid BoltDBKeyValueStore path [%s]", opts.Path)
+	}
+
+	db, err := boltDBs.open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	bucket := []byte(opts.Namespace)
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucket)
+		return err
+	})
+	if err != nil {
+		boltDBs.close(path)
+		return nil, errors.Wrapf(err, "creating bucket [%s] failed", opts.Namespace)
+	}
+
+	return &BoltDBKeyValueStore{
+		path:          path,
+		bucket:        bucket,
+		db:            db,
+		keySerializer: opts.KeySerializer,
+		marshaller:    opts.Marshaller,
+		unmarshaller:  opts.Unmarshaller,
+	}, nil
+}
+
+// GetPath returns the database file path
+func (s *BoltDBKeyValueStore) GetPath() string {
+	return s.path
+}
+
+// Load returns the value stored in the store for a key.
+// If a value for the key was not found, returns (nil, ErrNotFound)
+func (s *BoltDBKeyValueStore) Load(key interface{}) (interface{}, error) {
+	dbKey, err := s.keySerializer(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var valueBytes []byte
+	err = s.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(s.bucket).Get([]byte(dbKey))
+		if value != nil {
+			// The value is only valid for the life of the transaction
+			valueBytes = append([]byte{}, value...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading value for key [%s] failed", dbKey)
+	}
+	if valueBytes == nil {
+		return nil, core.ErrKeyValueNotFound
+	}
+	return s.unmarshaller(valueBytes)
+}
+
+// Store sets the value for the key.
+func (s *BoltDBKeyValueStore) Store(key interface{}, value interface{}) error {
+	if key == nil {
+		return errors.New("key is nil")
+	}
+	if value == nil {
+		return errors.New("value is nil")
+	}
+	dbKey, err := s.keySerializer(key)
+	if err != nil {
+		return err
+	}
+	valueBytes, err := s.marshaller(value)
+	if err != nil {
+		return err
+	}
+	if valueBytes == nil {
+		// bbolt doesn't distinguish nil from empty values
+		valueBytes = []byte{}
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).Put([]byte(dbKey), valueBytes)
+	})
+}
+
+// Delete deletes the value for a key.
+func (s *BoltDBKeyValueStore) Delete(key interface{}) error {
+	if key == nil {
+		return errors.New("key is nil")
+	}
+	dbKey, err := s.keySerializer(key)
+	if err != nil {
+		return err
+	}
+	// Deleting a key which doesn't exist is not an error
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).Delete([]byte(dbKey))
+	})
+}
+
+// LoadBatch returns the values for the given keys (in the same order) which are loaded
+// in a single transaction. The value of a key which was not found is nil.
+func (s *BoltDBKeyValueStore) LoadBatch(keys []interface{}) ([]interface{}, error) {
+	dbKeys, err := serializeKeys(s.keySerializer, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	valueBytes := make([][]byte, len(dbKeys))
+	err = s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.bucket)
+		for i, dbKey := range dbKeys {
+			if value := b.Get([]byte(dbKey)); value != nil {
+				// The value is only valid for the life of the transaction
+				valueBytes[i] = append([]byte{}, value...)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "loading batch failed")
+	}
+
+	values := make([]interface{}, len(dbKeys))
+	for i, value := range valueBytes {
+		if value == nil {
+			continue
+		}
+		if values[i], err = s.unmarshaller(value); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// StoreBatch sets the values of the given entries in a single transaction.
+func (s *BoltDBKeyValueStore) StoreBatch(entries []Entry) error {
+	dbKeys, valueBytes, err := marshalEntries(s.keySerializer, s.marshaller, entries)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.bucket)
+		for i, dbKey := range dbKeys {
+			value := valueBytes[i]
+			if value == nil {
+				value = []byte{}
+			}
+			if err := b.Put([]byte(dbKey), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteBatch deletes the values for the given keys in a single transaction.
+func (s *BoltDBKeyValueStore) DeleteBatch(keys []interface{}) error {
+	dbKeys, err := serializeKeys(s.keySerializer, keys)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.bucket)
+		for _, dbKey := range dbKeys {
+			if err := b.Delete([]byte(dbKey)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Keys returns the (serialized) keys of the values in the bucket
+func (s *BoltDBKeyValueStore) Keys() ([]string, error) {
+	var keys []string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).ForEach(func(k, v []byte) error {
+			keys = append(keys, string(k))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing keys failed")
+	}
+	return keys, nil
+}
+
+// CompareAndSwap sets the value for the key to newValue only if the current value
+// equals oldValue. If oldValue is nil then the value is only set if the key doesn't exist.
+func (s *BoltDBKeyValueStore) CompareAndSwap(key interface{}, oldValue interface{}, newValue interface{}) (bool, error) {
+	if key == nil {
+		return false, errors.New("key is nil")
+	}
+	if newValue == nil {
+		return false, errors.New("value is nil")
+	}
+	dbKey, err := s.keySerializer(key)
+	if err != nil {
+		return false, err
+	}
+	oldBytes, err := marshalExpected(s.marshaller, oldValue)
+	if err != nil {
+		return false, err
+	}
+	newBytes, err := s.marshaller(newValue)
+	if err != nil {
+		return false, err
+	}
+	if newBytes == nil {
+		newBytes = []byte{}
+	}
+
+	swapped := false
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.bucket)
+		if !bytesMatch(b.Get([]byte(dbKey)), oldBytes) {
+			return nil
+		}
+		swapped = true
+		return b.Put([]byte(dbKey), newBytes)
+	})
+	return swapped, err
+}
+
+// CompareAndDelete deletes the value for the key only if the current value equals oldValue.
+func (s *BoltDBKeyValueStore) CompareAndDelete(key interface{}, oldValue interface{}) (bool, error) {
+	if key == nil {
+		return false, errors.New("key is nil")
+	}
+	if oldValue == nil {
+		return false, errors.New("value is nil")
+	}
+	dbKey, err := s.keySerializer(key)
+	if err != nil {
+		return false, err
+	}
+	oldBytes, err := marshalExpected(s.marshaller, oldValue)
+	if err != nil {
+		return false, err
+	}
+
+	deleted := false
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.bucket)
+		if !bytesMatch(b.Get([]byte(dbKey)), oldBytes) {
+			return nil
+		}
+		deleted = true
+		return b.Delete([]byte(dbKey))
+	})
+	return deleted, err
+}
+
+// Close releases the database. The database is closed when all stores which share it have been closed.
+func (s *BoltDBKeyValueStore) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		err = boltDBs.close(s.path)
+	})
+	return err
+}
+
+// boltDBs holds the databases opened by this process since a database file
+// can only be opened once (the file is locked by bbolt)
+var boltDBs = &boltDBRegistry{dbs: make(map[string]*sharedBoltDB)}
+
+type sharedBoltDB struct {
+	db       *bolt.DB
+	refCount int
+}
+
+type boltDBRegistry struct {
+	lock sync.Mutex
+	dbs  map[string]*sharedBoltDB
+}
+
+func (r *boltDBRegistry) open(path string) (*bolt.DB, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if shared, ok := r.dbs[path]; ok {
+		shared.refCount++
+		return shared.db, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), newDirMode); err != nil {
+		return nil, errors.Wrapf(err, "creating directory for BoltDB database [%s] failed", path)
+	}
+
+	db, err := bolt.Open(path, newFileMode, &bolt.Options{Timeout: boltDBOpenTimeout})
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening BoltDB database [%s] failed", path)
+	}
+
+	r.dbs[path] = &sharedBoltDB{db: db, refCount: 1}
+	return db, nil
+}
+
+func (r *boltDBRegistry) close(path string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	shared, ok := r.dbs[path]
+	if !ok {
+		return nil
+	}
+
+	shared.refCount--
+	if shared.refCount > 0 {
+		return nil
+	}
+
+	delete(r.dbs, path)
+	return shared.db.Close()
+}
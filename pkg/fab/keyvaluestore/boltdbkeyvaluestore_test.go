@@ -0,0 +1,120 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"fmt"
+	"path"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var boltDBDir = "/tmp/testboltdbkeyvaluestore"
+
+func TestBoltDBKVS(t *testing.T) {
+	require.NoError(t, cleanup(boltDBDir))
+	defer cleanup(boltDBDir)
+
+	dbPath := path.Join(boltDBDir, "store.db")
+	store, err := NewBoltDB(&BoltDBKeyValueStoreOptions{Path: dbPath, Namespace: "users"})
+	require.NoError(t, err)
+
+	assert.EqualError(t, store.Store(nil, []byte("1234")), "key is nil")
+	assert.EqualError(t, store.Store("key", nil), "value is nil")
+
+	require.NoError(t, store.Store("key1", []byte("value1")))
+	value, err := store.Load("key1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value1"), value)
+
+	storeKeys, err := store.Keys()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key1"}, storeKeys)
+
+	// Namespaces are separated
+	keys, err := NewBoltDB(&BoltDBKeyValueStoreOptions{Path: dbPath, Namespace: "keys"})
+	require.NoError(t, err)
+	_, err = keys.Load("key1")
+	assert.Equal(t, core.ErrKeyValueNotFound, err)
+	require.NoError(t, keys.Close())
+
+	// Values are persisted when the database is reopened
+	require.NoError(t, store.Close())
+	require.NoError(t, store.Close(), "closing the store again shouldn't fail")
+	store, err = NewBoltDB(&BoltDBKeyValueStoreOptions{Path: dbPath, Namespace: "users"})
+	require.NoError(t, err)
+	defer store.Close()
+
+	value, err = store.Load("key1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value1"), value)
+
+	require.NoError(t, store.Delete("key1"))
+	_, err = store.Load("key1")
+	assert.Equal(t, core.ErrKeyValueNotFound, err)
+	assert.NoError(t, store.Delete("key1"), "deleting a non-existing key shouldn't fail")
+
+	// Empty values
+	require.NoError(t, store.Store("empty-string", []byte("")))
+	value, err = store.Load("empty-string")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{}, value)
+}
+
+func TestBoltDBKVSConcurrency(t *testing.T) {
+	require.NoError(t, cleanup(boltDBDir))
+	defer cleanup(boltDBDir)
+
+	dbPath := path.Join(boltDBDir, "store.db")
+	concurrency := 10
+
+	var wg sync.WaitGroup
+	errch := make(chan error, concurrency)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			store, err := NewBoltDB(&BoltDBKeyValueStoreOptions{Path: dbPath, Namespace: fmt.Sprintf("ns%d", i%2)})
+			if err != nil {
+				errch <- err
+				return
+			}
+			defer store.Close()
+
+			key := fmt.Sprintf("key%d", i)
+			if err := store.Store(key, []byte(key)); err != nil {
+				errch <- err
+				return
+			}
+			if _, err := store.Load(key); err != nil {
+				errch <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errch)
+
+	for err := range errch {
+		t.Fatalf("concurrent access failed: %s", err)
+	}
+	assert.Empty(t, boltDBs.dbs, "expected database to be closed")
+}
+
+func TestCreateNewBoltDBKeyValueStore(t *testing.T) {
+	_, err := NewBoltDB(nil)
+	assert.EqualError(t, err, "BoltDBKeyValueStoreOptions is nil")
+
+	_, err = NewBoltDB(&BoltDBKeyValueStoreOptions{Namespace: "users"})
+	assert.EqualError(t, err, "BoltDBKeyValueStore path is empty")
+
+	_, err = NewBoltDB(&BoltDBKeyValueStoreOptions{Path: path.Join(boltDBDir, "store.db")})
+	assert.EqualError(t, err, "BoltDBKeyValueStore namespace is empty")
+}
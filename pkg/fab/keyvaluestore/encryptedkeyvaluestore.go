@@ -0,0 +1,186 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/pkg/errors"
+)
+
+// encryptionFormatV1 identifies values encrypted with AES-GCM (followed by the nonce and the ciphertext)
+const encryptionFormatV1 byte = 1
+
+// KeyProvider returns an AES key (16, 24 or 32 bytes), e.g. a data key which was
+// derived from or decrypted by a key management service
+type KeyProvider func() ([]byte, error)
+
+// EncryptedKeyValueStore wraps a key-value store and encrypts values with AES-GCM
+// before they are stored in the underlying store (and decrypts them when they are
+// loaded), which adds at-rest encryption to any store implementation.
+//
+// The serialized key is authenticated along with the value, which prevents encrypted values
+// from being moved to another key.
+//
+// The underlying store must accept []byte values (i.e. use the default Marshaller and Unmarshaller).
+// Keys are passed to the underlying store as is.
+type EncryptedKeyValueStore struct {
+	store         core.KVStore
+	aead          cipher.AEAD
+	keySerializer KeySerializer
+	marshaller    Marshaller
+	unmarshaller  Unmarshaller
+}
+
+// EncryptedKeyValueStoreOptions allow overriding store defaults
+type EncryptedKeyValueStoreOptions struct {
+	// AES key (16, 24 or 32 bytes). Either Key or KeyProvider is mandatory.
+	Key []byte
+	// KeyProvider is invoked once, when the store is created, to retrieve the AES key.
+	KeyProvider KeyProvider
+	// Optional. Serializes the key which is authenticated along with the value.
+	// If not provided, the key (which must be a string) is used as is.
+	KeySerializer KeySerializer
+	// Optional. If not provided, default Marshaller is used.
+	Marshaller Marshaller
+	// Optional. If not provided, default Unmarshaller is used.
+	Unmarshaller Unmarshaller
+}
+
+// NewEncrypted returns a new instance of EncryptedKeyValueStore which
+// stores values in the given store, encrypted with the key of the given options
+func NewEncrypted(store core.KVStore, opts *EncryptedKeyValueStoreOptions) (*EncryptedKeyValueStore, error) {
+	if store == nil {
+		return nil, errors.New("store is nil")
+	}
+	if opts == nil {
+		return nil, errors.New("EncryptedKeyValueStoreOptions is nil")
+	}
+
+	key := opts.Key
+	if opts.KeyProvider != nil {
+		if key != nil {
+			return nil, errors.New("EncryptedKeyValueStore key and key provider are mutually exclusive")
+		}
+		var err error
+		key, err = opts.KeyProvider()
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to retrieve EncryptedKeyValueStore key")
+		}
+	}
+	if len(key) == 0 {
+		return nil, errors.New("EncryptedKeyValueStore key is empty")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid EncryptedKeyValueStore key")
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create AES-GCM cipher")
+	}
+
+	if opts.KeySerializer == nil {
+		// Default key serializer
+		opts.KeySerializer = func(key interface{}) (string, error) {
+			keyString, ok := key.(string)
+			if !ok {
+				return "", errors.New("converting key to string failed")
+			}
+			return keyString, nil
+		}
+	}
+	if opts.Marshaller == nil {
+		opts.Marshaller = defaultMarshaller
+	}
+	if opts.Unmarshaller == nil {
+		opts.Unmarshaller = defaultUnmarshaller
+	}
+
+	return &EncryptedKeyValueStore{
+		store:         store,
+		aead:          aead,
+		keySerializer: opts.KeySerializer,
+		marshaller:    opts.Marshaller,
+		unmarshaller:  opts.Unmarshaller,
+	}, nil
+}
+
+// Store encrypts the value and stores it for the key.
+func (s *EncryptedKeyValueStore) Store(key interface{}, value interface{}) error {
+	if key == nil {
+		return errors.New("key is nil")
+	}
+	if value == nil {
+		return errors.New("value is nil")
+	}
+	additionalData, err := s.additionalData(key)
+	if err != nil {
+		return err
+	}
+	valueBytes, err := s.marshaller(value)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return errors.Wrap(err, "failed to generate nonce")
+	}
+
+	encrypted := append([]byte{encryptionFormatV1}, nonce...)
+	encrypted = s.aead.Seal(encrypted, nonce, valueBytes, additionalData)
+
+	return s.store.Store(key, encrypted)
+}
+
+// Load returns the decrypted value stored in the store for a key.
+// If a value for the key was not found, returns (nil, ErrNotFound)
+func (s *EncryptedKeyValueStore) Load(key interface{}) (interface{}, error) {
+	additionalData, err := s.additionalData(key)
+	if err != nil {
+		return nil, err
+	}
+	value, err := s.store.Load(key)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, ok := value.([]byte)
+	if !ok {
+		return nil, errors.Errorf("unexpected value type: %T", value)
+	}
+
+	nonceSize := s.aead.NonceSize()
+	if len(encrypted) < 1+nonceSize || encrypted[0] != encryptionFormatV1 {
+		return nil, errors.New("value is not encrypted or has an unsupported format")
+	}
+	nonce := encrypted[1 : 1+nonceSize]
+
+	valueBytes, err := s.aead.Open(nil, nonce, encrypted[1+nonceSize:], additionalData)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt value")
+	}
+	return s.unmarshaller(valueBytes)
+}
+
+// Delete deletes the value for a key.
+func (s *EncryptedKeyValueStore) Delete(key interface{}) error {
+	return s.store.Delete(key)
+}
+
+func (s *EncryptedKeyValueStore) additionalData(key interface{}) ([]byte, error) {
+	k, err := s.keySerializer(key)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(k), nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var encryptedStorePath = "/tmp/testencryptedkeyvaluestore"
+
+var testEncryptionKey = bytes.Repeat([]byte{1}, 32)
+
+func TestEncryptedKVS(t *testing.T) {
+	require.NoError(t, cleanup(encryptedStorePath))
+	defer cleanup(encryptedStorePath)
+
+	store, err := New(&FileKeyValueStoreOptions{Path: encryptedStorePath})
+	require.NoError(t, err)
+
+	encrypted, err := NewEncrypted(store, &EncryptedKeyValueStoreOptions{Key: testEncryptionKey})
+	require.NoError(t, err)
+
+	assert.EqualError(t, encrypted.Store(nil, []byte("1234")), "key is nil")
+	assert.EqualError(t, encrypted.Store("key", nil), "value is nil")
+
+	require.NoError(t, encrypted.Store("key1", []byte("secret")))
+
+	raw, err := store.Load("key1")
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(raw.([]byte), []byte("secret")), "expected value to be encrypted at rest")
+
+	value, err := encrypted.Load("key1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), value)
+
+	// Wrong key
+	other, err := NewEncrypted(store, &EncryptedKeyValueStoreOptions{Key: bytes.Repeat([]byte{2}, 32)})
+	require.NoError(t, err)
+	_, err = other.Load("key1")
+	assert.Error(t, err, "expected decryption with another key to fail")
+
+	// Unencrypted value
+	require.NoError(t, store.Store("plain", []byte("plain")))
+	_, err = encrypted.Load("plain")
+	assert.Error(t, err)
+
+	require.NoError(t, encrypted.Delete("key1"))
+	_, err = encrypted.Load("key1")
+	assert.Equal(t, core.ErrKeyValueNotFound, err)
+}
+
+func TestEncryptedKVSKeyBinding(t *testing.T) {
+	require.NoError(t, cleanup(encryptedStorePath))
+	defer cleanup(encryptedStorePath)
+
+	store, err := New(&FileKeyValueStoreOptions{Path: encryptedStorePath})
+	require.NoError(t, err)
+
+	encrypted, err := NewEncrypted(store, &EncryptedKeyValueStoreOptions{
+		KeyProvider: func() ([]byte, error) {
+			return testEncryptionKey, nil
+		},
+		KeySerializer: func(key interface{}) (string, error) {
+			return key.(string), nil
+		},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, encrypted.Store("key1", []byte("secret")))
+	value, err := encrypted.Load("key1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), value)
+
+	// Move the encrypted value to another key
+	raw, err := store.Load("key1")
+	require.NoError(t, err)
+	require.NoError(t, store.Store("key2", raw))
+
+	_, err = encrypted.Load("key2")
+	assert.Error(t, err, "expected value bound to another key to be rejected")
+
+	// The key is bound by default
+	defaultEncrypted, err := NewEncrypted(store, &EncryptedKeyValueStoreOptions{Key: testEncryptionKey})
+	require.NoError(t, err)
+
+	require.NoError(t, defaultEncrypted.Store("key3", []byte("secret")))
+	raw, err = store.Load("key3")
+	require.NoError(t, err)
+	require.NoError(t, store.Store("key4", raw))
+
+	_, err = defaultEncrypted.Load("key4")
+	assert.Error(t, err, "expected value bound to another key to be rejected")
+
+	assert.EqualError(t, defaultEncrypted.Store(1, []byte("secret")), "converting key to string failed")
+}
+
+func TestCreateNewEncryptedKeyValueStore(t *testing.T) {
+	store := newRedis(&RedisKeyValueStoreOptions{Address: "localhost:6379"}, newMockRedisClient())
+
+	_, err := NewEncrypted(nil, &EncryptedKeyValueStoreOptions{Key: testEncryptionKey})
+	assert.EqualError(t, err, "store is nil")
+
+	_, err = NewEncrypted(store, nil)
+	assert.EqualError(t, err, "EncryptedKeyValueStoreOptions is nil")
+
+	_, err = NewEncrypted(store, &EncryptedKeyValueStoreOptions{})
+	assert.EqualError(t, err, "EncryptedKeyValueStore key is empty")
+
+	_, err = NewEncrypted(store, &EncryptedKeyValueStoreOptions{Key: []byte("short")})
+	assert.Error(t, err)
+
+	keyProvider := func() ([]byte, error) { return testEncryptionKey, nil }
+	_, err = NewEncrypted(store, &EncryptedKeyValueStoreOptions{Key: testEncryptionKey, KeyProvider: keyProvider})
+	assert.EqualError(t, err, "EncryptedKeyValueStore key and key provider are mutually exclusive")
+
+	_, err = NewEncrypted(store, &EncryptedKeyValueStoreOptions{
+		KeyProvider: func() ([]byte, error) { return nil, errors.New("KMS unavailable") },
+	})
+	assert.EqualError(t, err, "failed to retrieve EncryptedKeyValueStore key: KMS unavailable")
+}
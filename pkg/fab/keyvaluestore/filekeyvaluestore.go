@@ -10,6 +10,8 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
+	"sort"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/pkg/errors"
@@ -29,6 +31,12 @@ type Marshaller func(value interface{}) ([]byte, error)
 // Unmarshaller unmarshals a value from a byte array
 type Unmarshaller func(value []byte) (interface{}, error)
 
+// KeyLister is implemented by key-value stores which are able to list their keys
+type KeyLister interface {
+	// Keys returns the (serialized) keys of the stored values
+	Keys() ([]string, error)
+}
+
 // FileKeyValueStore stores each value into a separate file.
 // KeySerializer maps a key to a unique file path (raletive to the store path)
 // ValueSerializer and ValueDeserializer serializes/de-serializes a value
@@ -179,3 +187,32 @@ func (fkvs *FileKeyValueStore) Delete(key interface{}) error {
 	}
 	return os.Remove(file)
 }
+
+// Keys returns the paths (relative to the store path) of the files in the store,
+// which are the keys of the stored values if the default key serializer is used.
+func (fkvs *FileKeyValueStore) Keys() ([]string, error) {
+	var keys []string
+	err := filepath.Walk(fkvs.path, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && file == fkvs.path {
+				// Nothing has been stored yet
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		key, err := filepath.Rel(fkvs.path, file)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, filepath.ToSlash(key))
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing files in [%s] failed", fkvs.path)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
@@ -0,0 +1,155 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/pkg/errors"
+)
+
+// ErrLeaseHeld is returned when a lease is held by another owner
+var ErrLeaseHeld = errors.New("lease is held by another owner")
+
+// CASKVStore is implemented by key-value stores which support atomic compare-and-swap
+// operations (e.g. BoltDBKeyValueStore, LevelDBKeyValueStore and RedisKeyValueStore).
+// Values are compared by their marshalled bytes.
+type CASKVStore interface {
+	core.KVStore
+
+	// CompareAndSwap sets the value for the key to newValue only if the current value
+	// equals oldValue. If oldValue is nil then the value is only set if the key doesn't exist.
+	// Returns true if the value was set.
+	CompareAndSwap(key interface{}, oldValue interface{}, newValue interface{}) (bool, error)
+
+	// CompareAndDelete deletes the value for the key only if the current value equals oldValue.
+	// Returns true if the value was deleted.
+	CompareAndDelete(key interface{}, oldValue interface{}) (bool, error)
+}
+
+// Lease is stored under the key of a lease. Multiple SDK instances which share a store
+// may use leases to elect a single instance to perform a task (e.g. writing event
+// checkpoints or renewing certificates). Since the expiry is determined by the clock of
+// the instance which acquired the lease, the clocks of the instances should be synchronized
+// and the TTL should be much larger than the expected clock skew.
+type Lease struct {
+	Owner  string    `json:"owner"`
+	Expiry time.Time `json:"expiry"`
+}
+
+// AcquireLease acquires (or renews) the lease stored under the given key on behalf of the
+// given owner for the given TTL. Returns ErrLeaseHeld if the lease is held by another owner.
+// The store must accept []byte values (i.e. use the default Marshaller and Unmarshaller).
+func AcquireLease(store CASKVStore, key interface{}, owner string, ttl time.Duration) (*Lease, error) {
+	if owner == "" {
+		return nil, errors.New("lease owner is empty")
+	}
+	if ttl <= 0 {
+		return nil, errors.New("lease TTL must be greater than zero")
+	}
+
+	current, currentBytes, err := loadLease(store, key)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if current != nil && current.Owner != owner && now.Before(current.Expiry) {
+		return nil, ErrLeaseHeld
+	}
+
+	lease := &Lease{Owner: owner, Expiry: now.Add(ttl)}
+	leaseBytes, err := json.Marshal(lease)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal lease failed")
+	}
+
+	var oldValue interface{}
+	if currentBytes != nil {
+		oldValue = currentBytes
+	}
+	swapped, err := store.CompareAndSwap(key, oldValue, leaseBytes)
+	if err != nil {
+		return nil, err
+	}
+	if !swapped {
+		// The lease was modified concurrently
+		return nil, ErrLeaseHeld
+	}
+	return lease, nil
+}
+
+// ReleaseLease releases the lease stored under the given key if it is held by the given owner.
+// Returns ErrLeaseHeld if the lease is held by another owner.
+func ReleaseLease(store CASKVStore, key interface{}, owner string) error {
+	current, currentBytes, err := loadLease(store, key)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return nil
+	}
+	if current.Owner != owner {
+		return ErrLeaseHeld
+	}
+
+	deleted, err := store.CompareAndDelete(key, currentBytes)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrLeaseHeld
+	}
+	return nil
+}
+
+func loadLease(store core.KVStore, key interface{}) (*Lease, []byte, error) {
+	value, err := store.Load(key)
+	if err != nil {
+		if err == core.ErrKeyValueNotFound {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	leaseBytes, ok := value.([]byte)
+	if !ok {
+		return nil, nil, errors.Errorf("unexpected lease type: %T", value)
+	}
+	lease := &Lease{}
+	if err := json.Unmarshal(leaseBytes, lease); err != nil {
+		return nil, nil, errors.Wrap(err, "unmarshal lease failed")
+	}
+	return lease, leaseBytes, nil
+}
+
+// marshalExpected marshals the expected value of a compare operation. A nil value
+// (which means that the key must not exist) is returned as nil.
+func marshalExpected(marshaller Marshaller, value interface{}) ([]byte, error) {
+	if value == nil {
+		return nil, nil
+	}
+	valueBytes, err := marshaller(value)
+	if err != nil {
+		return nil, err
+	}
+	if valueBytes == nil {
+		valueBytes = []byte{}
+	}
+	return valueBytes, nil
+}
+
+// bytesMatch returns true if the current value equals the expected value,
+// where a nil expected value matches a non-existent (nil) current value only
+func bytesMatch(current, expected []byte) bool {
+	if expected == nil || current == nil {
+		return expected == nil && current == nil
+	}
+	return bytes.Equal(current, expected)
+}
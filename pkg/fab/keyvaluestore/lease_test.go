@@ -0,0 +1,146 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var casStorePath = "/tmp/testcaskeyvaluestore"
+
+func TestCompareAndSwap(t *testing.T) {
+	require.NoError(t, cleanup(casStorePath))
+	defer cleanup(casStorePath)
+
+	boltStore, err := NewBoltDB(&BoltDBKeyValueStoreOptions{Path: path.Join(casStorePath, "bolt.db"), Namespace: "cas"})
+	require.NoError(t, err)
+	defer boltStore.Close()
+
+	levelStore, err := NewLevelDB(&LevelDBKeyValueStoreOptions{Path: path.Join(casStorePath, "leveldb")})
+	require.NoError(t, err)
+	defer levelStore.Close()
+
+	redisStore := newRedis(&RedisKeyValueStoreOptions{Address: "localhost:6379"}, newMockRedisClient())
+
+	t.Run("BoltDB", func(t *testing.T) { testCompareAndSwap(t, boltStore) })
+	t.Run("LevelDB", func(t *testing.T) { testCompareAndSwap(t, levelStore) })
+	t.Run("Redis", func(t *testing.T) { testCompareAndSwap(t, redisStore) })
+}
+
+func testCompareAndSwap(t *testing.T, store CASKVStore) {
+	_, err := store.CompareAndSwap(nil, nil, []byte("v1"))
+	assert.EqualError(t, err, "key is nil")
+	_, err = store.CompareAndSwap("key", nil, nil)
+	assert.EqualError(t, err, "value is nil")
+
+	// Create if not exists
+	swapped, err := store.CompareAndSwap("key", nil, []byte("v1"))
+	require.NoError(t, err)
+	assert.True(t, swapped)
+
+	swapped, err = store.CompareAndSwap("key", nil, []byte("v2"))
+	require.NoError(t, err)
+	assert.False(t, swapped, "expected swap to fail since the key exists")
+
+	// Swap if current value matches
+	swapped, err = store.CompareAndSwap("key", []byte("other"), []byte("v2"))
+	require.NoError(t, err)
+	assert.False(t, swapped, "expected swap to fail since the value doesn't match")
+
+	swapped, err = store.CompareAndSwap("key", []byte("v1"), []byte("v2"))
+	require.NoError(t, err)
+	assert.True(t, swapped)
+
+	value, err := store.Load("key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v2"), value)
+
+	swapped, err = store.CompareAndSwap("missing", []byte("v1"), []byte("v2"))
+	require.NoError(t, err)
+	assert.False(t, swapped, "expected swap of non-existent key to fail")
+
+	// Delete if current value matches
+	deleted, err := store.CompareAndDelete("key", []byte("v1"))
+	require.NoError(t, err)
+	assert.False(t, deleted)
+
+	deleted, err = store.CompareAndDelete("key", []byte("v2"))
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	_, err = store.Load("key")
+	assert.Equal(t, core.ErrKeyValueNotFound, err)
+}
+
+func TestLease(t *testing.T) {
+	require.NoError(t, cleanup(casStorePath))
+	defer cleanup(casStorePath)
+
+	store, err := NewBoltDB(&BoltDBKeyValueStoreOptions{Path: path.Join(casStorePath, "bolt.db"), Namespace: "leases"})
+	require.NoError(t, err)
+	defer store.Close()
+
+	_, err = AcquireLease(store, "checkpoint", "", time.Minute)
+	assert.EqualError(t, err, "lease owner is empty")
+	_, err = AcquireLease(store, "checkpoint", "instance1", 0)
+	assert.EqualError(t, err, "lease TTL must be greater than zero")
+
+	lease, err := AcquireLease(store, "checkpoint", "instance1", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "instance1", lease.Owner)
+
+	_, err = AcquireLease(store, "checkpoint", "instance2", time.Minute)
+	assert.Equal(t, ErrLeaseHeld, err)
+
+	// Renew
+	renewed, err := AcquireLease(store, "checkpoint", "instance1", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, renewed.Expiry.Before(lease.Expiry))
+
+	assert.Equal(t, ErrLeaseHeld, ReleaseLease(store, "checkpoint", "instance2"))
+	require.NoError(t, ReleaseLease(store, "checkpoint", "instance1"))
+	require.NoError(t, ReleaseLease(store, "checkpoint", "instance1"), "releasing a released lease shouldn't fail")
+
+	_, err = AcquireLease(store, "checkpoint", "instance2", time.Millisecond)
+	require.NoError(t, err)
+
+	// Expired lease may be taken over
+	time.Sleep(10 * time.Millisecond)
+	lease, err = AcquireLease(store, "checkpoint", "instance1", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "instance1", lease.Owner)
+}
+
+func TestLeaseConcurrency(t *testing.T) {
+	store := newRedis(&RedisKeyValueStoreOptions{Address: "localhost:6379"}, newMockRedisClient())
+
+	concurrency := 10
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	var owners []string
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(owner string) {
+			defer wg.Done()
+			if _, err := AcquireLease(store, "renewal", owner, time.Minute); err == nil {
+				lock.Lock()
+				owners = append(owners, owner)
+				lock.Unlock()
+			}
+		}(string(rune('a' + i)))
+	}
+	wg.Wait()
+
+	assert.Len(t, owners, 1, "expected exactly one owner to acquire the lease")
+}
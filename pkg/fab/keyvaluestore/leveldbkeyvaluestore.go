@@ -0,0 +1,302 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// LevelDBKeyValueStore stores values in a LevelDB database.
+// KeySerializer maps a key to a unique database key.
+// Marshaller and Unmarshaller serialize/de-serialize a value
+// to and from the byte array that is stored in the database.
+//
+// Unlike FileKeyValueStore, which stores each value into a separate file,
+// all values are stored in a single database which performs well for stores
+// with a large number of entries. The database may only be opened by one
+// process at a time and must be closed when it is no longer needed.
+type LevelDBKeyValueStore struct {
+	path          string
+	db            *leveldb.DB
+	keySerializer KeySerializer
+	marshaller    Marshaller
+	unmarshaller  Unmarshaller
+	// writeLock serializes writes so that compare-and-swap operations are atomic
+	writeLock sync.Mutex
+}
+
+// LevelDBKeyValueStoreOptions allow overriding store defaults
+type LevelDBKeyValueStoreOptions struct {
+	// Database path, mandatory
+	Path string
+	// Optional. If not provided, the key (which must be a string) is used as is.
+	KeySerializer KeySerializer
+	// Optional. If not provided, default Marshaller is used.
+	Marshaller Marshaller
+	// Optional. If not provided, default Unmarshaller is used.
+	Unmarshaller Unmarshaller
+}
+
+// NewLevelDB opens (or creates) the LevelDB database at the path of the given options
+// and returns a new instance of LevelDBKeyValueStore
+func NewLevelDB(opts *LevelDBKeyValueStoreOptions) (*LevelDBKeyValueStore, error) {
+	if opts == nil {
+		return nil, errors.New("LevelDBKeyValueStoreOptions is nil")
+	}
+	if opts.Path == "" {
+		return nil, errors.New("LevelDBKeyValueStore path is empty")
+	}
+	if opts.KeySerializer == nil {
+		// Default key serializer
+		opts.KeySerializer = func(key interface{}) (string, error) {
+			keyString, ok := key.(string)
+			if !ok {
+				return "", errors.New("converting key to string failed")
+			}
+			return keyString, nil
+		}
+	}
+	if opts.Marshaller == nil {
+		opts.Marshaller = defaultMarshaller
+	}
+	if opts.Unmarshaller == nil {
+		opts.Unmarshaller = defaultUnmarshaller
+	}
+
+	db, err := leveldb.OpenFile(opts.Path, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening LevelDB database [%s] failed", opts.Path)
+	}
+
+	return &LevelDBKeyValueStore{
+		path:          opts.Path,
+		db:            db,
+		keySerializer: opts.KeySerializer,
+		marshaller:    opts.Marshaller,
+		unmarshaller:  opts.Unmarshaller,
+	}, nil
+}
+
+// GetPath returns the database path
+func (s *LevelDBKeyValueStore) GetPath() string {
+	return s.path
+}
+
+// Load returns the value stored in the store for a key.
+// If a value for the key was not found, returns (nil, ErrNotFound)
+func (s *LevelDBKeyValueStore) Load(key interface{}) (interface{}, error) {
+	dbKey, err := s.keySerializer(key)
+	if err != nil {
+		return nil, err
+	}
+	bytes, err := s.db.Get([]byte(dbKey), nil)
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			return nil, core.ErrKeyValueNotFound
+		}
+		return nil, errors.Wrapf(err, "loading value for key [%s] failed", dbKey)
+	}
+	return s.unmarshaller(bytes)
+}
+
+// Store sets the value for the key.
+func (s *LevelDBKeyValueStore) Store(key interface{}, value interface{}) error {
+	if key == nil {
+		return errors.New("key is nil")
+	}
+	if value == nil {
+		return errors.New("value is nil")
+	}
+	dbKey, err := s.keySerializer(key)
+	if err != nil {
+		return err
+	}
+	valueBytes, err := s.marshaller(value)
+	if err != nil {
+		return err
+	}
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+	return s.db.Put([]byte(dbKey), valueBytes, nil)
+}
+
+// Delete deletes the value for a key.
+func (s *LevelDBKeyValueStore) Delete(key interface{}) error {
+	if key == nil {
+		return errors.New("key is nil")
+	}
+	dbKey, err := s.keySerializer(key)
+	if err != nil {
+		return err
+	}
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+	// Deleting a key which doesn't exist is not an error
+	return s.db.Delete([]byte(dbKey), nil)
+}
+
+// LoadBatch returns the values for the given keys (in the same order) which are loaded
+// from a snapshot of the database. The value of a key which was not found is nil.
+func (s *LevelDBKeyValueStore) LoadBatch(keys []interface{}) ([]interface{}, error) {
+	dbKeys, err := serializeKeys(s.keySerializer, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := s.db.GetSnapshot()
+	if err != nil {
+		return nil, errors.Wrap(err, "loading batch failed")
+	}
+	defer snapshot.Release()
+
+	values := make([]interface{}, len(dbKeys))
+	for i, dbKey := range dbKeys {
+		bytes, err := snapshot.Get([]byte(dbKey), nil)
+		if err != nil {
+			if err == leveldb.ErrNotFound {
+				continue
+			}
+			return nil, errors.Wrapf(err, "loading value for key [%s] failed", dbKey)
+		}
+		if values[i], err = s.unmarshaller(bytes); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// StoreBatch sets the values of the given entries in a single (atomic) write.
+func (s *LevelDBKeyValueStore) StoreBatch(entries []Entry) error {
+	dbKeys, valueBytes, err := marshalEntries(s.keySerializer, s.marshaller, entries)
+	if err != nil {
+		return err
+	}
+	batch := new(leveldb.Batch)
+	for i, dbKey := range dbKeys {
+		batch.Put([]byte(dbKey), valueBytes[i])
+	}
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+	return s.db.Write(batch, nil)
+}
+
+// DeleteBatch deletes the values for the given keys in a single (atomic) write.
+func (s *LevelDBKeyValueStore) DeleteBatch(keys []interface{}) error {
+	dbKeys, err := serializeKeys(s.keySerializer, keys)
+	if err != nil {
+		return err
+	}
+	batch := new(leveldb.Batch)
+	for _, dbKey := range dbKeys {
+		batch.Delete([]byte(dbKey))
+	}
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+	return s.db.Write(batch, nil)
+}
+
+// Keys returns the (serialized) keys of the values in the database
+func (s *LevelDBKeyValueStore) Keys() ([]string, error) {
+	var keys []string
+	iter := s.db.NewIterator(nil, nil)
+	for iter.Next() {
+		keys = append(keys, string(iter.Key()))
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return nil, errors.Wrap(err, "listing keys failed")
+	}
+	return keys, nil
+}
+
+// CompareAndSwap sets the value for the key to newValue only if the current value
+// equals oldValue. If oldValue is nil then the value is only set if the key doesn't exist.
+func (s *LevelDBKeyValueStore) CompareAndSwap(key interface{}, oldValue interface{}, newValue interface{}) (bool, error) {
+	if key == nil {
+		return false, errors.New("key is nil")
+	}
+	if newValue == nil {
+		return false, errors.New("value is nil")
+	}
+	dbKey, err := s.keySerializer(key)
+	if err != nil {
+		return false, err
+	}
+	oldBytes, err := marshalExpected(s.marshaller, oldValue)
+	if err != nil {
+		return false, err
+	}
+	newBytes, err := s.marshaller(newValue)
+	if err != nil {
+		return false, err
+	}
+
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+
+	matches, err := s.currentMatches(dbKey, oldBytes)
+	if err != nil || !matches {
+		return false, err
+	}
+	if err := s.db.Put([]byte(dbKey), newBytes, nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CompareAndDelete deletes the value for the key only if the current value equals oldValue.
+func (s *LevelDBKeyValueStore) CompareAndDelete(key interface{}, oldValue interface{}) (bool, error) {
+	if key == nil {
+		return false, errors.New("key is nil")
+	}
+	if oldValue == nil {
+		return false, errors.New("value is nil")
+	}
+	dbKey, err := s.keySerializer(key)
+	if err != nil {
+		return false, err
+	}
+	oldBytes, err := marshalExpected(s.marshaller, oldValue)
+	if err != nil {
+		return false, err
+	}
+
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+
+	matches, err := s.currentMatches(dbKey, oldBytes)
+	if err != nil || !matches {
+		return false, err
+	}
+	if err := s.db.Delete([]byte(dbKey), nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *LevelDBKeyValueStore) currentMatches(dbKey string, expected []byte) (bool, error) {
+	current, err := s.db.Get([]byte(dbKey), nil)
+	if err == leveldb.ErrNotFound {
+		return expected == nil, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "loading value for key [%s] failed", dbKey)
+	}
+	if current == nil {
+		current = []byte{}
+	}
+	return bytesMatch(current, expected), nil
+}
+
+// Close closes the database
+func (s *LevelDBKeyValueStore) Close() error {
+	return s.db.Close()
+}
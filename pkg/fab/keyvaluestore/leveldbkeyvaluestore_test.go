@@ -0,0 +1,127 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/pkg/errors"
+)
+
+var levelDBPath = "/tmp/testleveldbkeyvaluestore"
+
+func TestDefaultLevelDBKVS(t *testing.T) {
+	testLevelDBKVS(t, nil)
+}
+
+func TestLevelDBKVSWithCustomKeySerializer(t *testing.T) {
+	keySerializer := func(key interface{}) (string, error) {
+		keyString, ok := key.(string)
+		if !ok {
+			return "", errors.New("converting key to string failed")
+		}
+		return fmt.Sprintf("mykeys/%s", keyString), nil
+	}
+	testLevelDBKVS(t, keySerializer)
+}
+
+func testLevelDBKVS(t *testing.T, keySerializer KeySerializer) {
+	if err := cleanup(levelDBPath); err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer cleanup(levelDBPath)
+
+	store, err := NewLevelDB(
+		&LevelDBKeyValueStoreOptions{
+			Path:          levelDBPath,
+			KeySerializer: keySerializer,
+		})
+	if err != nil {
+		t.Fatalf("NewLevelDB failed [%s]", err)
+	}
+
+	err = store.Store(nil, []byte("1234"))
+	if err == nil || err.Error() != "key is nil" {
+		t.Fatal("Store(nil, ...) should throw error")
+	}
+	err = store.Store("key", nil)
+	if err == nil || err.Error() != "value is nil" {
+		t.Fatal("Store(..., nil) should throw error")
+	}
+
+	key1 := "key1"
+	value1 := []byte("value1")
+	if err1 := store.Store(key1, value1); err1 != nil {
+		t.Fatalf("Store %s failed [%s]", key1, err1)
+	}
+	if err1 := checkLevelDBValue(store, key1, value1); err1 != nil {
+		t.Fatalf("checkLevelDBValue %s failed [%s]", key1, err1)
+	}
+
+	// Values are persisted when the database is reopened
+	if err1 := store.Close(); err1 != nil {
+		t.Fatalf("Close failed [%s]", err1)
+	}
+	store, err = NewLevelDB(&LevelDBKeyValueStoreOptions{Path: levelDBPath, KeySerializer: keySerializer})
+	if err != nil {
+		t.Fatalf("NewLevelDB failed [%s]", err)
+	}
+	defer store.Close()
+
+	if err1 := checkLevelDBValue(store, key1, value1); err1 != nil {
+		t.Fatalf("checkLevelDBValue %s failed [%s]", key1, err1)
+	}
+	if err1 := store.Delete(key1); err1 != nil {
+		t.Fatalf("Delete %s failed [%s]", key1, err1)
+	}
+	if err1 := checkLevelDBValue(store, key1, nil); err1 != nil {
+		t.Fatalf("checkLevelDBValue %s failed [%s]", key1, err1)
+	}
+
+	// Deleting a non-existing key doesn't fail
+	if err1 := store.Delete(key1); err1 != nil {
+		t.Fatalf("Delete %s failed [%s]", key1, err1)
+	}
+
+	checkNonExistingKey(store, t)
+
+	// Check empty string value
+	if err1 := store.Store("empty-string", []byte("")); err1 != nil {
+		t.Fatal("setting an empty string value shouldn't fail")
+	}
+	if err1 := checkLevelDBValue(store, "empty-string", []byte("")); err1 != nil {
+		t.Fatalf("checkLevelDBValue failed [%s]", err1)
+	}
+}
+
+func TestCreateNewLevelDBKeyValueStore(t *testing.T) {
+	_, err := NewLevelDB(&LevelDBKeyValueStoreOptions{Path: ""})
+	if err == nil || err.Error() != "LevelDBKeyValueStore path is empty" {
+		t.Fatal("Path validation on NewLevelDB is not working as expected")
+	}
+
+	_, err = NewLevelDB(nil)
+	if err == nil || err.Error() != "LevelDBKeyValueStoreOptions is nil" {
+		t.Fatal("Options validation on NewLevelDB is not working as expected")
+	}
+}
+
+func checkLevelDBValue(store core.KVStore, key interface{}, expected []byte) error {
+	v, err := store.Load(key)
+	if err != nil {
+		if err == core.ErrKeyValueNotFound && expected == nil {
+			return nil
+		}
+		return err
+	}
+	if expected == nil {
+		return errors.Errorf("value for key [%s] shouldn't exist", key)
+	}
+	return compare(v, expected)
+}
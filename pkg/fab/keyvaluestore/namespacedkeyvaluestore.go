@@ -0,0 +1,279 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/fab")
+
+// NamespaceSeparator separates the namespace from the key in the keys of the underlying store
+const NamespaceSeparator = "/"
+
+// expiryHeaderLen is the length of the expiry time which prefixes each stored value
+const expiryHeaderLen = 8
+
+// ExpiringKVStore is implemented by key-value stores which natively support
+// the expiration of entries (e.g. RedisKeyValueStore)
+type ExpiringKVStore interface {
+	core.KVStore
+
+	// StoreWithTTL sets the value for the key. The value expires after the given TTL.
+	// A TTL of zero means no expiration.
+	StoreWithTTL(key interface{}, value interface{}, ttl time.Duration) error
+}
+
+// NamespacedKeyValueStore wraps a key-value store and stores its values under a namespace
+// so that several stores (e.g. persistent user data and transient artifacts such as nonces
+// and cached discovery results) may share the same underlying store.
+//
+// Entries may be given a TTL. The expiry time is stored along with the value so that expired
+// entries are never returned, regardless of the underlying store. If the underlying store
+// implements ExpiringKVStore then the store also removes expired entries, otherwise expired
+// entries are removed when they are loaded or by PurgeExpired.
+//
+// The underlying store must accept []byte values (i.e. use the default Marshaller and Unmarshaller).
+type NamespacedKeyValueStore struct {
+	store         core.KVStore
+	namespace     string
+	ttl           time.Duration
+	keySerializer KeySerializer
+	marshaller    Marshaller
+	unmarshaller  Unmarshaller
+	now           func() time.Time
+}
+
+// NamespacedKeyValueStoreOptions allow overriding store defaults
+type NamespacedKeyValueStoreOptions struct {
+	// Namespace, mandatory
+	Namespace string
+	// Optional. Default TTL of the values stored with Store. If not provided, values don't expire.
+	TTL time.Duration
+	// Optional. If not provided, the key (which must be a string) is used as is.
+	KeySerializer KeySerializer
+	// Optional. If not provided, default Marshaller is used.
+	Marshaller Marshaller
+	// Optional. If not provided, default Unmarshaller is used.
+	Unmarshaller Unmarshaller
+}
+
+// NewNamespaced returns a new instance of NamespacedKeyValueStore which
+// stores values in the given store under the namespace of the given options
+func NewNamespaced(store core.KVStore, opts *NamespacedKeyValueStoreOptions) (*NamespacedKeyValueStore, error) {
+	if store == nil {
+		return nil, errors.New("store is nil")
+	}
+	if opts == nil {
+		return nil, errors.New("NamespacedKeyValueStoreOptions is nil")
+	}
+	if opts.Namespace == "" {
+		return nil, errors.New("NamespacedKeyValueStore namespace is empty")
+	}
+	if opts.TTL < 0 {
+		return nil, errors.New("NamespacedKeyValueStore TTL is negative")
+	}
+	if opts.KeySerializer == nil {
+		// Default key serializer
+		opts.KeySerializer = func(key interface{}) (string, error) {
+			keyString, ok := key.(string)
+			if !ok {
+				return "", errors.New("converting key to string failed")
+			}
+			return keyString, nil
+		}
+	}
+	if opts.Marshaller == nil {
+		opts.Marshaller = defaultMarshaller
+	}
+	if opts.Unmarshaller == nil {
+		opts.Unmarshaller = defaultUnmarshaller
+	}
+
+	return &NamespacedKeyValueStore{
+		store:         store,
+		namespace:     opts.Namespace,
+		ttl:           opts.TTL,
+		keySerializer: opts.KeySerializer,
+		marshaller:    opts.Marshaller,
+		unmarshaller:  opts.Unmarshaller,
+		now:           time.Now,
+	}, nil
+}
+
+// Namespace returns the namespace of the store
+func (s *NamespacedKeyValueStore) Namespace() string {
+	return s.namespace
+}
+
+// Store sets the value for the key. The value expires after the default TTL (if any).
+func (s *NamespacedKeyValueStore) Store(key interface{}, value interface{}) error {
+	return s.StoreWithTTL(key, value, s.ttl)
+}
+
+// StoreWithTTL sets the value for the key. The value expires after the given TTL.
+// A TTL of zero means no expiration.
+func (s *NamespacedKeyValueStore) StoreWithTTL(key interface{}, value interface{}, ttl time.Duration) error {
+	if key == nil {
+		return errors.New("key is nil")
+	}
+	if value == nil {
+		return errors.New("value is nil")
+	}
+	if ttl < 0 {
+		return errors.New("TTL is negative")
+	}
+	nsKey, err := s.namespacedKey(key)
+	if err != nil {
+		return err
+	}
+	valueBytes, err := s.marshaller(value)
+	if err != nil {
+		return err
+	}
+
+	var expiry time.Time
+	if ttl > 0 {
+		expiry = s.now().Add(ttl)
+	}
+
+	if expiringStore, ok := s.store.(ExpiringKVStore); ok && ttl > 0 {
+		return expiringStore.StoreWithTTL(nsKey, encodeExpiry(expiry, valueBytes), ttl)
+	}
+	return s.store.Store(nsKey, encodeExpiry(expiry, valueBytes))
+}
+
+// Load returns the value stored in the store for a key.
+// If a value for the key was not found or has expired, returns (nil, ErrNotFound)
+func (s *NamespacedKeyValueStore) Load(key interface{}) (interface{}, error) {
+	nsKey, err := s.namespacedKey(key)
+	if err != nil {
+		return nil, err
+	}
+	expiry, valueBytes, err := s.load(nsKey)
+	if err != nil {
+		return nil, err
+	}
+	if isExpired(expiry, s.now()) {
+		logger.Debugf("Value for key [%s] expired at %s", nsKey, expiry)
+		if err := s.store.Delete(nsKey); err != nil {
+			logger.Warnf("Failed to delete expired value for key [%s]: %s", nsKey, err)
+		}
+		return nil, core.ErrKeyValueNotFound
+	}
+	return s.unmarshaller(valueBytes)
+}
+
+// Delete deletes the value for a key.
+func (s *NamespacedKeyValueStore) Delete(key interface{}) error {
+	if key == nil {
+		return errors.New("key is nil")
+	}
+	nsKey, err := s.namespacedKey(key)
+	if err != nil {
+		return err
+	}
+	return s.store.Delete(nsKey)
+}
+
+// PurgeExpired deletes the expired values in the namespace. The expiry is read from the
+// stored values so values stored by other instances (or before a restart) are purged too.
+// It should be invoked periodically if the underlying store doesn't implement ExpiringKVStore,
+// in which case the underlying store must implement KeyLister and use the default key serializer.
+func (s *NamespacedKeyValueStore) PurgeExpired() error {
+	lister, ok := s.store.(KeyLister)
+	if !ok {
+		if _, ok := s.store.(ExpiringKVStore); ok {
+			// Expired values are removed by the underlying store
+			return nil
+		}
+		return errors.Errorf("unable to purge expired values: store of type %T doesn't list its keys", s.store)
+	}
+	keys, err := lister.Keys()
+	if err != nil {
+		return errors.WithMessage(err, "failed to list keys")
+	}
+
+	now := s.now()
+	prefix := s.namespace + NamespaceSeparator
+	for _, nsKey := range keys {
+		if !strings.HasPrefix(nsKey, prefix) {
+			continue
+		}
+		expiry, _, err := s.load(nsKey)
+		if err != nil {
+			if err == core.ErrKeyValueNotFound {
+				// Deleted in the meantime
+				continue
+			}
+			return err
+		}
+		if !isExpired(expiry, now) {
+			continue
+		}
+		if err := s.store.Delete(nsKey); err != nil {
+			return errors.WithMessage(err, "failed to delete expired value for key ["+nsKey+"]")
+		}
+	}
+	return nil
+}
+
+// load returns the expiry and the value stored for the namespaced key
+func (s *NamespacedKeyValueStore) load(nsKey string) (time.Time, []byte, error) {
+	value, err := s.store.Load(nsKey)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	valueBytes, ok := value.([]byte)
+	if !ok {
+		return time.Time{}, nil, errors.Errorf("unexpected value type for key [%s]: %T", nsKey, value)
+	}
+	expiry, valueBytes, err := decodeExpiry(valueBytes)
+	if err != nil {
+		return time.Time{}, nil, errors.WithMessage(err, "invalid value for key ["+nsKey+"]")
+	}
+	return expiry, valueBytes, nil
+}
+
+func (s *NamespacedKeyValueStore) namespacedKey(key interface{}) (string, error) {
+	k, err := s.keySerializer(key)
+	if err != nil {
+		return "", err
+	}
+	return s.namespace + NamespaceSeparator + k, nil
+}
+
+// encodeExpiry prefixes the value with the expiry time (zero if the value doesn't expire)
+func encodeExpiry(expiry time.Time, value []byte) []byte {
+	encoded := make([]byte, expiryHeaderLen+len(value))
+	if !expiry.IsZero() {
+		binary.BigEndian.PutUint64(encoded, uint64(expiry.UnixNano()))
+	}
+	copy(encoded[expiryHeaderLen:], value)
+	return encoded
+}
+
+func isExpired(expiry time.Time, now time.Time) bool {
+	return !expiry.IsZero() && !now.Before(expiry)
+}
+
+func decodeExpiry(encoded []byte) (time.Time, []byte, error) {
+	if len(encoded) < expiryHeaderLen {
+		return time.Time{}, nil, errors.New("value is too short")
+	}
+	var expiry time.Time
+	if nanos := binary.BigEndian.Uint64(encoded); nanos != 0 {
+		expiry = time.Unix(0, int64(nanos))
+	}
+	return expiry, encoded[expiryHeaderLen:], nil
+}
@@ -0,0 +1,164 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var namespacedStorePath = "/tmp/testnamespacedkeyvaluestore"
+
+func TestNamespacedKVS(t *testing.T) {
+	require.NoError(t, cleanup(namespacedStorePath))
+	defer cleanup(namespacedStorePath)
+
+	store, err := New(&FileKeyValueStoreOptions{Path: namespacedStorePath})
+	require.NoError(t, err)
+
+	users, err := NewNamespaced(store, &NamespacedKeyValueStoreOptions{Namespace: "users"})
+	require.NoError(t, err)
+	nonces, err := NewNamespaced(store, &NamespacedKeyValueStoreOptions{Namespace: "nonces"})
+	require.NoError(t, err)
+	assert.Equal(t, "users", users.Namespace())
+
+	assert.EqualError(t, users.Store(nil, []byte("1234")), "key is nil")
+	assert.EqualError(t, users.Store("key", nil), "value is nil")
+	assert.EqualError(t, users.StoreWithTTL("key", []byte("1234"), -time.Second), "TTL is negative")
+
+	require.NoError(t, users.Store("key1", []byte("user1")))
+	require.NoError(t, nonces.Store("key1", []byte("nonce1")))
+
+	value, err := users.Load("key1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("user1"), value)
+
+	value, err = nonces.Load("key1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("nonce1"), value)
+
+	_, err = store.Load("users/key1")
+	assert.NoError(t, err, "expected value to be stored under the namespace")
+
+	require.NoError(t, users.Delete("key1"))
+	_, err = users.Load("key1")
+	assert.Equal(t, core.ErrKeyValueNotFound, err)
+
+	_, err = nonces.Load("key1")
+	assert.NoError(t, err, "expected value in other namespace to remain")
+
+	// Empty value
+	require.NoError(t, users.Store("empty", []byte{}))
+	value, err = users.Load("empty")
+	require.NoError(t, err)
+	assert.Empty(t, value)
+}
+
+func TestNamespacedKVSTTL(t *testing.T) {
+	require.NoError(t, cleanup(namespacedStorePath))
+	defer cleanup(namespacedStorePath)
+
+	store, err := New(&FileKeyValueStoreOptions{Path: namespacedStorePath})
+	require.NoError(t, err)
+
+	nonces, err := NewNamespaced(store, &NamespacedKeyValueStoreOptions{Namespace: "nonces", TTL: time.Minute})
+	require.NoError(t, err)
+
+	now := time.Now()
+	nonces.now = func() time.Time { return now }
+
+	require.NoError(t, nonces.Store("key1", []byte("nonce1")))
+	require.NoError(t, nonces.Store("key2", []byte("nonce2")))
+	require.NoError(t, nonces.StoreWithTTL("key3", []byte("nonce3"), 0))
+
+	now = now.Add(30 * time.Second)
+	_, err = nonces.Load("key1")
+	assert.NoError(t, err)
+
+	now = now.Add(time.Minute)
+	_, err = nonces.Load("key1")
+	assert.Equal(t, core.ErrKeyValueNotFound, err, "expected value to be expired")
+	_, err = store.Load("nonces/key1")
+	assert.Equal(t, core.ErrKeyValueNotFound, err, "expected expired value to be deleted")
+
+	// The expiry is read from the stored values so a new instance purges values stored by another
+	purger, err := NewNamespaced(store, &NamespacedKeyValueStoreOptions{Namespace: "nonces"})
+	require.NoError(t, err)
+	purger.now = func() time.Time { return now }
+
+	require.NoError(t, purger.PurgeExpired())
+	_, err = store.Load("nonces/key2")
+	assert.Equal(t, core.ErrKeyValueNotFound, err, "expected expired value to be purged")
+
+	value, err := nonces.Load("key3")
+	require.NoError(t, err, "expected value without TTL not to expire")
+	assert.Equal(t, []byte("nonce3"), value)
+}
+
+func TestNamespacedKVSExpiringStore(t *testing.T) {
+	client := newMockRedisClient()
+	store := newRedis(&RedisKeyValueStoreOptions{Address: "localhost:6379"}, client)
+
+	nonces, err := NewNamespaced(store, &NamespacedKeyValueStoreOptions{Namespace: "nonces", TTL: time.Minute})
+	require.NoError(t, err)
+
+	require.NoError(t, nonces.Store("key1", []byte("nonce1")))
+	assert.Equal(t, time.Minute, client.expirations["nonces/key1"], "expected native TTL to be used")
+
+	value, err := nonces.Load("key1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("nonce1"), value)
+
+	assert.NoError(t, nonces.PurgeExpired(), "expected purge to be left to the store")
+}
+
+func TestNamespacedKVSPurgeExpiredOtherNamespace(t *testing.T) {
+	require.NoError(t, cleanup(namespacedStorePath))
+	defer cleanup(namespacedStorePath)
+
+	store, err := New(&FileKeyValueStoreOptions{Path: namespacedStorePath})
+	require.NoError(t, err)
+
+	now := time.Now()
+	nonces, err := NewNamespaced(store, &NamespacedKeyValueStoreOptions{Namespace: "nonces", TTL: time.Minute})
+	require.NoError(t, err)
+	nonces.now = func() time.Time { return now }
+	users, err := NewNamespaced(store, &NamespacedKeyValueStoreOptions{Namespace: "users", TTL: time.Minute})
+	require.NoError(t, err)
+	users.now = func() time.Time { return now }
+
+	require.NoError(t, nonces.Store("key1", []byte("nonce1")))
+	require.NoError(t, users.Store("key1", []byte("user1")))
+
+	now = now.Add(2 * time.Minute)
+	require.NoError(t, nonces.PurgeExpired())
+
+	_, err = store.Load("nonces/key1")
+	assert.Equal(t, core.ErrKeyValueNotFound, err, "expected expired value to be purged")
+	_, err = store.Load("users/key1")
+	assert.NoError(t, err, "expected value in other namespace not to be purged")
+}
+
+func TestCreateNewNamespacedKeyValueStore(t *testing.T) {
+	store := newRedis(&RedisKeyValueStoreOptions{Address: "localhost:6379"}, newMockRedisClient())
+
+	_, err := NewNamespaced(nil, &NamespacedKeyValueStoreOptions{Namespace: "users"})
+	assert.EqualError(t, err, "store is nil")
+
+	_, err = NewNamespaced(store, nil)
+	assert.EqualError(t, err, "NamespacedKeyValueStoreOptions is nil")
+
+	_, err = NewNamespaced(store, &NamespacedKeyValueStoreOptions{})
+	assert.EqualError(t, err, "NamespacedKeyValueStore namespace is empty")
+
+	_, err = NewNamespaced(store, &NamespacedKeyValueStoreOptions{Namespace: "users", TTL: -time.Second})
+	assert.EqualError(t, err, "NamespacedKeyValueStore TTL is negative")
+}
@@ -0,0 +1,460 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+	"github.com/pkg/errors"
+)
+
+// RedisKeyValueStore stores values in a Redis server so that multiple
+// (stateless) SDK instances may share the same store.
+// KeySerializer maps a key to a unique key within the namespace.
+// Marshaller and Unmarshaller serialize/de-serialize a value
+// to and from the byte array that is stored in Redis.
+type RedisKeyValueStore struct {
+	address       string
+	namespace     string
+	client        redisClient
+	keySerializer KeySerializer
+	marshaller    Marshaller
+	unmarshaller  Unmarshaller
+}
+
+// RedisKeyValueStoreOptions allow overriding store defaults
+type RedisKeyValueStoreOptions struct {
+	// Address (host:port) of the Redis server, mandatory
+	Address string
+	// Optional. Password used to authenticate with the Redis server.
+	Password string
+	// Optional. Database to select after connecting to the server.
+	DB int
+	// Optional. Namespace is prepended to all keys (separated by ':') so that
+	// several stores (e.g. users and keys) may share the same database.
+	Namespace string
+	// Optional. If provided, TLS is used to connect to the server. The root certificates
+	// are used to verify the server and the client key pair (if any) for client authentication.
+	TLS *endpoint.MutualTLSConfig
+	// Optional. If not provided, the key (which must be a string) is used as is.
+	KeySerializer KeySerializer
+	// Optional. If not provided, default Marshaller is used.
+	Marshaller Marshaller
+	// Optional. If not provided, default Unmarshaller is used.
+	Unmarshaller Unmarshaller
+}
+
+// compareAndSwapScript atomically sets the value of a key if its current value matches
+var compareAndSwapScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	redis.call("SET", KEYS[1], ARGV[2])
+	return 1
+end
+return 0`)
+
+// compareAndDeleteScript atomically deletes a key if its current value matches
+var compareAndDeleteScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// redisClient is the subset of Redis commands used by the store
+type redisClient interface {
+	get(key string) ([]byte, error)
+	set(key string, value []byte, expiration time.Duration) error
+	del(keys ...string) error
+	// mget returns the values of the keys (nil if a key doesn't exist)
+	mget(keys ...string) ([][]byte, error)
+	mset(keys []string, values [][]byte) error
+	// compareAndSwap sets the value if the current value equals oldValue
+	// (or if the key doesn't exist when oldValue is nil)
+	compareAndSwap(key string, oldValue, newValue []byte) (bool, error)
+	compareAndDelete(key string, oldValue []byte) (bool, error)
+	close() error
+}
+
+// NewRedis connects to the Redis server of the given options and returns
+// a new instance of RedisKeyValueStore
+func NewRedis(opts *RedisKeyValueStoreOptions) (*RedisKeyValueStore, error) {
+	if opts == nil {
+		return nil, errors.New("RedisKeyValueStoreOptions is nil")
+	}
+	if opts.Address == "" {
+		return nil, errors.New("RedisKeyValueStore address is empty")
+	}
+
+	var tlsConfig *tls.Config
+	if opts.TLS != nil {
+		var err error
+		tlsConfig, err = redisTLSConfig(opts.TLS)
+		if err != nil {
+			return nil, errors.WithMessage(err, "invalid RedisKeyValueStore TLS config")
+		}
+	}
+
+	client := &goRedisClient{
+		client: redis.NewClient(&redis.Options{
+			Addr:      opts.Address,
+			Password:  opts.Password,
+			DB:        opts.DB,
+			TLSConfig: tlsConfig,
+		}),
+	}
+
+	if err := client.client.Ping().Err(); err != nil {
+		client.close()
+		return nil, errors.Wrapf(err, "connecting to Redis server [%s] failed", opts.Address)
+	}
+
+	return newRedis(opts, client), nil
+}
+
+func newRedis(opts *RedisKeyValueStoreOptions, client redisClient) *RedisKeyValueStore {
+	if opts.KeySerializer == nil {
+		// Default key serializer
+		opts.KeySerializer = func(key interface{}) (string, error) {
+			keyString, ok := key.(string)
+			if !ok {
+				return "", errors.New("converting key to string failed")
+			}
+			return keyString, nil
+		}
+	}
+	if opts.Marshaller == nil {
+		opts.Marshaller = defaultMarshaller
+	}
+	if opts.Unmarshaller == nil {
+		opts.Unmarshaller = defaultUnmarshaller
+	}
+
+	return &RedisKeyValueStore{
+		address:       opts.Address,
+		namespace:     opts.Namespace,
+		client:        client,
+		keySerializer: opts.KeySerializer,
+		marshaller:    opts.Marshaller,
+		unmarshaller:  opts.Unmarshaller,
+	}
+}
+
+// GetAddress returns the address of the Redis server
+func (s *RedisKeyValueStore) GetAddress() string {
+	return s.address
+}
+
+// Load returns the value stored in the store for a key.
+// If a value for the key was not found, returns (nil, ErrNotFound)
+func (s *RedisKeyValueStore) Load(key interface{}) (interface{}, error) {
+	redisKey, err := s.redisKey(key)
+	if err != nil {
+		return nil, err
+	}
+	bytes, err := s.client.get(redisKey)
+	if err != nil {
+		if err == redis.Nil {
+			return nil, core.ErrKeyValueNotFound
+		}
+		return nil, errors.Wrapf(err, "loading value for key [%s] failed", redisKey)
+	}
+	return s.unmarshaller(bytes)
+}
+
+// Store sets the value for the key.
+func (s *RedisKeyValueStore) Store(key interface{}, value interface{}) error {
+	return s.StoreWithTTL(key, value, 0)
+}
+
+// StoreWithTTL sets the value for the key. The value expires (and is removed
+// by the server) after the given TTL. A TTL of zero means no expiration.
+func (s *RedisKeyValueStore) StoreWithTTL(key interface{}, value interface{}, ttl time.Duration) error {
+	if key == nil {
+		return errors.New("key is nil")
+	}
+	if value == nil {
+		return errors.New("value is nil")
+	}
+	redisKey, err := s.redisKey(key)
+	if err != nil {
+		return err
+	}
+	valueBytes, err := s.marshaller(value)
+	if err != nil {
+		return err
+	}
+	return s.client.set(redisKey, valueBytes, ttl)
+}
+
+// Delete deletes the value for a key.
+func (s *RedisKeyValueStore) Delete(key interface{}) error {
+	if key == nil {
+		return errors.New("key is nil")
+	}
+	redisKey, err := s.redisKey(key)
+	if err != nil {
+		return err
+	}
+	// Deleting a key which doesn't exist is not an error
+	return s.client.del(redisKey)
+}
+
+// LoadBatch returns the values for the given keys (in the same order) which are loaded
+// with a single command. The value of a key which was not found is nil.
+func (s *RedisKeyValueStore) LoadBatch(keys []interface{}) ([]interface{}, error) {
+	redisKeys, err := s.redisKeys(keys)
+	if err != nil {
+		return nil, err
+	}
+	if len(redisKeys) == 0 {
+		return []interface{}{}, nil
+	}
+	valueBytes, err := s.client.mget(redisKeys...)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading batch failed")
+	}
+
+	values := make([]interface{}, len(redisKeys))
+	for i, value := range valueBytes {
+		if value == nil {
+			continue
+		}
+		if values[i], err = s.unmarshaller(value); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// StoreBatch sets the values of the given entries with a single (atomic) command.
+func (s *RedisKeyValueStore) StoreBatch(entries []Entry) error {
+	keys, valueBytes, err := marshalEntries(s.keySerializer, s.marshaller, entries)
+	if err != nil || len(keys) == 0 {
+		return err
+	}
+	for i, key := range keys {
+		keys[i] = s.namespaced(key)
+	}
+	return s.client.mset(keys, valueBytes)
+}
+
+// DeleteBatch deletes the values for the given keys with a single command.
+func (s *RedisKeyValueStore) DeleteBatch(keys []interface{}) error {
+	redisKeys, err := s.redisKeys(keys)
+	if err != nil || len(redisKeys) == 0 {
+		return err
+	}
+	return s.client.del(redisKeys...)
+}
+
+// CompareAndSwap sets the value for the key to newValue only if the current value
+// equals oldValue. If oldValue is nil then the value is only set if the key doesn't exist.
+func (s *RedisKeyValueStore) CompareAndSwap(key interface{}, oldValue interface{}, newValue interface{}) (bool, error) {
+	if key == nil {
+		return false, errors.New("key is nil")
+	}
+	if newValue == nil {
+		return false, errors.New("value is nil")
+	}
+	redisKey, err := s.redisKey(key)
+	if err != nil {
+		return false, err
+	}
+	oldBytes, err := marshalExpected(s.marshaller, oldValue)
+	if err != nil {
+		return false, err
+	}
+	newBytes, err := s.marshaller(newValue)
+	if err != nil {
+		return false, err
+	}
+	swapped, err := s.client.compareAndSwap(redisKey, oldBytes, newBytes)
+	if err != nil {
+		return false, errors.Wrapf(err, "compare-and-swap for key [%s] failed", redisKey)
+	}
+	return swapped, nil
+}
+
+// CompareAndDelete deletes the value for the key only if the current value equals oldValue.
+func (s *RedisKeyValueStore) CompareAndDelete(key interface{}, oldValue interface{}) (bool, error) {
+	if key == nil {
+		return false, errors.New("key is nil")
+	}
+	if oldValue == nil {
+		return false, errors.New("value is nil")
+	}
+	redisKey, err := s.redisKey(key)
+	if err != nil {
+		return false, err
+	}
+	oldBytes, err := marshalExpected(s.marshaller, oldValue)
+	if err != nil {
+		return false, err
+	}
+	deleted, err := s.client.compareAndDelete(redisKey, oldBytes)
+	if err != nil {
+		return false, errors.Wrapf(err, "compare-and-delete for key [%s] failed", redisKey)
+	}
+	return deleted, nil
+}
+
+// Close closes the connection(s) to the Redis server
+func (s *RedisKeyValueStore) Close() error {
+	return s.client.close()
+}
+
+func (s *RedisKeyValueStore) redisKey(key interface{}) (string, error) {
+	k, err := s.keySerializer(key)
+	if err != nil {
+		return "", err
+	}
+	return s.namespaced(k), nil
+}
+
+func (s *RedisKeyValueStore) redisKeys(keys []interface{}) ([]string, error) {
+	redisKeys, err := serializeKeys(s.keySerializer, keys)
+	if err != nil {
+		return nil, err
+	}
+	for i, k := range redisKeys {
+		redisKeys[i] = s.namespaced(k)
+	}
+	return redisKeys, nil
+}
+
+func (s *RedisKeyValueStore) namespaced(key string) string {
+	if s.namespace == "" {
+		return key
+	}
+	return s.namespace + ":" + key
+}
+
+func redisTLSConfig(cfg *endpoint.MutualTLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+
+	pool := x509.NewCertPool()
+	numCerts := 0
+	for _, pemCert := range cfg.Pem {
+		if !pool.AppendCertsFromPEM([]byte(pemCert)) {
+			return nil, errors.New("failed to append root certificate from PEM")
+		}
+		numCerts++
+	}
+	if cfg.Path != "" {
+		for _, certPath := range strings.Split(cfg.Path, ",") {
+			pemCert, err := ioutil.ReadFile(strings.TrimSpace(certPath))
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to load root certificate from path %s", certPath)
+			}
+			if !pool.AppendCertsFromPEM(pemCert) {
+				return nil, errors.Errorf("failed to append root certificate from path %s", certPath)
+			}
+			numCerts++
+		}
+	}
+	if numCerts > 0 {
+		// Otherwise the system cert pool is used
+		tlsConfig.RootCAs = pool
+	}
+
+	certBytes, err := cfg.Client.Cert.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	keyBytes, err := cfg.Client.Key.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	if len(certBytes) > 0 || len(keyBytes) > 0 {
+		clientCert, err := tls.X509KeyPair(certBytes, keyBytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load client key pair")
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+
+	return tlsConfig, nil
+}
+
+// goRedisClient implements redisClient using the go-redis client
+type goRedisClient struct {
+	client *redis.Client
+}
+
+func (c *goRedisClient) get(key string) ([]byte, error) {
+	return c.client.Get(key).Bytes()
+}
+
+func (c *goRedisClient) set(key string, value []byte, expiration time.Duration) error {
+	return c.client.Set(key, value, expiration).Err()
+}
+
+func (c *goRedisClient) del(keys ...string) error {
+	return c.client.Del(keys...).Err()
+}
+
+func (c *goRedisClient) mget(keys ...string) ([][]byte, error) {
+	results, err := c.client.MGet(keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	values := make([][]byte, len(results))
+	for i, result := range results {
+		switch v := result.(type) {
+		case nil:
+		case string:
+			values[i] = []byte(v)
+		case []byte:
+			values[i] = v
+		default:
+			return nil, errors.Errorf("unexpected value type for key [%s]: %T", keys[i], result)
+		}
+	}
+	return values, nil
+}
+
+func (c *goRedisClient) mset(keys []string, values [][]byte) error {
+	pairs := make([]interface{}, 0, 2*len(keys))
+	for i, key := range keys {
+		pairs = append(pairs, key, values[i])
+	}
+	return c.client.MSet(pairs...).Err()
+}
+
+func (c *goRedisClient) compareAndSwap(key string, oldValue, newValue []byte) (bool, error) {
+	if oldValue == nil {
+		return c.client.SetNX(key, newValue, 0).Result()
+	}
+	return scriptSucceeded(compareAndSwapScript.Run(c.client, []string{key}, oldValue, newValue))
+}
+
+func (c *goRedisClient) compareAndDelete(key string, oldValue []byte) (bool, error) {
+	return scriptSucceeded(compareAndDeleteScript.Run(c.client, []string{key}, oldValue))
+}
+
+// scriptSucceeded returns true if the given script returned 1
+func scriptSucceeded(cmd *redis.Cmd) (bool, error) {
+	result, err := cmd.Result()
+	if err != nil {
+		return false, err
+	}
+	n, ok := result.(int64)
+	if !ok {
+		return false, errors.Errorf("unexpected script result type %T", result)
+	}
+	return n == 1, nil
+}
+
+func (c *goRedisClient) close() error {
+	return c.client.Close()
+}
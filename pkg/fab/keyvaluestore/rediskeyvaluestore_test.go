@@ -0,0 +1,206 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisKVS(t *testing.T) {
+	client := newMockRedisClient()
+	store := newRedis(&RedisKeyValueStoreOptions{Address: "localhost:6379", Namespace: "users"}, client)
+	assert.Equal(t, "localhost:6379", store.GetAddress())
+
+	assert.EqualError(t, store.Store(nil, []byte("1234")), "key is nil")
+	assert.EqualError(t, store.Store("key", nil), "value is nil")
+
+	_, err := store.Load("key1")
+	assert.Equal(t, core.ErrKeyValueNotFound, err)
+
+	require.NoError(t, store.Store("key1", []byte("value1")))
+	assert.Equal(t, []byte("value1"), client.values["users:key1"], "expected key to be namespaced")
+
+	value, err := store.Load("key1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value1"), value)
+
+	// Another namespace in the same database
+	keys := newRedis(&RedisKeyValueStoreOptions{Address: "localhost:6379", Namespace: "keys"}, client)
+	_, err = keys.Load("key1")
+	assert.Equal(t, core.ErrKeyValueNotFound, err)
+
+	require.NoError(t, store.Delete("key1"))
+	_, err = store.Load("key1")
+	assert.Equal(t, core.ErrKeyValueNotFound, err)
+
+	require.NoError(t, store.StoreWithTTL("key2", []byte("value2"), time.Minute))
+	assert.Equal(t, time.Minute, client.expirations["users:key2"])
+
+	require.NoError(t, store.Close())
+	assert.True(t, client.closed)
+}
+
+func TestCreateNewRedisKeyValueStore(t *testing.T) {
+	_, err := NewRedis(nil)
+	assert.EqualError(t, err, "RedisKeyValueStoreOptions is nil")
+
+	_, err = NewRedis(&RedisKeyValueStoreOptions{})
+	assert.EqualError(t, err, "RedisKeyValueStore address is empty")
+
+	_, err = NewRedis(&RedisKeyValueStoreOptions{Address: "localhost:6379", TLS: &endpoint.MutualTLSConfig{Pem: []string{"invalid"}}})
+	assert.Error(t, err)
+}
+
+func TestRedisTLSConfig(t *testing.T) {
+	certPEM, keyPEM := generateTLSKeyPair(t)
+
+	tlsConfig, err := redisTLSConfig(&endpoint.MutualTLSConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, tlsConfig.RootCAs, "expected system cert pool to be used")
+	assert.Empty(t, tlsConfig.Certificates)
+
+	tlsConfig, err = redisTLSConfig(&endpoint.MutualTLSConfig{
+		Pem: []string{certPEM},
+		Client: endpoint.TLSKeyPair{
+			Cert: endpoint.TLSConfig{Pem: certPEM},
+			Key:  endpoint.TLSConfig{Pem: keyPEM},
+		},
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, tlsConfig.RootCAs)
+	assert.Len(t, tlsConfig.Certificates, 1)
+
+	_, err = redisTLSConfig(&endpoint.MutualTLSConfig{Path: "/invalid/path/ca.pem"})
+	assert.Error(t, err)
+
+	_, err = redisTLSConfig(&endpoint.MutualTLSConfig{
+		Client: endpoint.TLSKeyPair{Cert: endpoint.TLSConfig{Pem: certPEM}},
+	})
+	assert.Error(t, err, "expected error for missing client key")
+}
+
+func generateTLSKeyPair(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "redis"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return string(certPEM), string(keyPEM)
+}
+
+type mockRedisClient struct {
+	lock        sync.RWMutex
+	values      map[string][]byte
+	expirations map[string]time.Duration
+	closed      bool
+}
+
+func newMockRedisClient() *mockRedisClient {
+	return &mockRedisClient{
+		values:      make(map[string][]byte),
+		expirations: make(map[string]time.Duration),
+	}
+}
+
+func (c *mockRedisClient) get(key string) ([]byte, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	value, ok := c.values[key]
+	if !ok {
+		return nil, redis.Nil
+	}
+	return value, nil
+}
+
+func (c *mockRedisClient) set(key string, value []byte, expiration time.Duration) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.values[key] = value
+	c.expirations[key] = expiration
+	return nil
+}
+
+func (c *mockRedisClient) del(keys ...string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, key := range keys {
+		delete(c.values, key)
+	}
+	return nil
+}
+
+func (c *mockRedisClient) mget(keys ...string) ([][]byte, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		values[i] = c.values[key]
+	}
+	return values, nil
+}
+
+func (c *mockRedisClient) mset(keys []string, values [][]byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for i, key := range keys {
+		c.values[key] = values[i]
+	}
+	return nil
+}
+
+func (c *mockRedisClient) compareAndSwap(key string, oldValue, newValue []byte) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if !bytesMatch(c.values[key], oldValue) {
+		return false, nil
+	}
+	c.values[key] = newValue
+	return true, nil
+}
+
+func (c *mockRedisClient) compareAndDelete(key string, oldValue []byte) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if !bytesMatch(c.values[key], oldValue) {
+		return false, nil
+	}
+	delete(c.values, key)
+	return true, nil
+}
+
+func (c *mockRedisClient) close() error {
+	c.closed = true
+	return nil
+}
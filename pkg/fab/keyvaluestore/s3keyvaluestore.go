@@ -0,0 +1,242 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/pkg/errors"
+)
+
+// S3KeyValueStore stores each value as an object in an S3 (or S3-compatible) bucket.
+// It requires no local disk and may therefore be used in serverless deployments.
+// KeySerializer maps a key to a unique object key (relative to the prefix).
+// Marshaller and Unmarshaller serialize/de-serialize a value
+// to and from the byte array that is stored in the object.
+type S3KeyValueStore struct {
+	bucket               string
+	prefix               string
+	serverSideEncryption string
+	sseKMSKeyID          string
+	client               s3Client
+	keySerializer        KeySerializer
+	marshaller           Marshaller
+	unmarshaller         Unmarshaller
+}
+
+// S3KeyValueStoreOptions allow overriding store defaults
+type S3KeyValueStoreOptions struct {
+	// Bucket name, mandatory
+	Bucket string
+	// Optional. Prefix of the object keys (e.g. "users/").
+	Prefix string
+	// Optional. Region of the bucket. If not provided, the region is
+	// taken from the environment (AWS_REGION) or shared config.
+	Region string
+	// Optional. Endpoint of an S3-compatible object storage service (e.g. "https://minio:9000").
+	Endpoint string
+	// Optional. Use path-style addressing (required by most S3-compatible services).
+	ForcePathStyle bool
+	// Optional. If not provided, credentials are resolved from the environment,
+	// shared credentials file or instance role.
+	AccessKeyID     string
+	SecretAccessKey string
+	// Optional. Server-side encryption algorithm for stored objects ("AES256" or "aws:kms").
+	ServerSideEncryption string
+	// Optional. ID of the KMS key used when ServerSideEncryption is "aws:kms".
+	SSEKMSKeyID string
+	// Optional. If not provided, the key (which must be a string) is used as is.
+	KeySerializer KeySerializer
+	// Optional. If not provided, default Marshaller is used.
+	Marshaller Marshaller
+	// Optional. If not provided, default Unmarshaller is used.
+	Unmarshaller Unmarshaller
+}
+
+// s3Client is the subset of the S3 API used by the store
+type s3Client interface {
+	GetObject(input *s3.GetObjectInput) (*s3.GetObjectOutput, error)
+	PutObject(input *s3.PutObjectInput) (*s3.PutObjectOutput, error)
+	DeleteObject(input *s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error)
+}
+
+// NewS3 returns a new instance of S3KeyValueStore
+func NewS3(opts *S3KeyValueStoreOptions) (*S3KeyValueStore, error) {
+	if opts == nil {
+		return nil, errors.New("S3KeyValueStoreOptions is nil")
+	}
+	if opts.Bucket == "" {
+		return nil, errors.New("S3KeyValueStore bucket is empty")
+	}
+	if opts.SSEKMSKeyID != "" && opts.ServerSideEncryption != s3.ServerSideEncryptionAwsKms {
+		return nil, errors.Errorf("S3KeyValueStore KMS key ID requires server-side encryption [%s]", s3.ServerSideEncryptionAwsKms)
+	}
+
+	cfg := aws.NewConfig()
+	if opts.Region != "" {
+		cfg = cfg.WithRegion(opts.Region)
+	}
+	if opts.Endpoint != "" {
+		cfg = cfg.WithEndpoint(opts.Endpoint)
+	}
+	if opts.ForcePathStyle {
+		cfg = cfg.WithS3ForcePathStyle(true)
+	}
+	if opts.AccessKeyID != "" {
+		cfg = cfg.WithCredentials(credentials.NewStaticCredentials(opts.AccessKeyID, opts.SecretAccessKey, ""))
+	}
+
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating S3 session failed")
+	}
+
+	return newS3(opts, s3.New(sess)), nil
+}
+
+func newS3(opts *S3KeyValueStoreOptions, client s3Client) *S3KeyValueStore {
+	if opts.KeySerializer == nil {
+		// Default key serializer
+		opts.KeySerializer = func(key interface{}) (string, error) {
+			keyString, ok := key.(string)
+			if !ok {
+				return "", errors.New("converting key to string failed")
+			}
+			return keyString, nil
+		}
+	}
+	if opts.Marshaller == nil {
+		opts.Marshaller = defaultMarshaller
+	}
+	if opts.Unmarshaller == nil {
+		opts.Unmarshaller = defaultUnmarshaller
+	}
+
+	return &S3KeyValueStore{
+		bucket:               opts.Bucket,
+		prefix:               opts.Prefix,
+		serverSideEncryption: opts.ServerSideEncryption,
+		sseKMSKeyID:          opts.SSEKMSKeyID,
+		client:               client,
+		keySerializer:        opts.KeySerializer,
+		marshaller:           opts.Marshaller,
+		unmarshaller:         opts.Unmarshaller,
+	}
+}
+
+// GetBucket returns the bucket name
+func (s *S3KeyValueStore) GetBucket() string {
+	return s.bucket
+}
+
+// Load returns the value stored in the store for a key.
+// If a value for the key was not found, returns (nil, ErrNotFound)
+func (s *S3KeyValueStore) Load(key interface{}) (interface{}, error) {
+	objectKey, err := s.objectKey(key)
+	if err != nil {
+		return nil, err
+	}
+	output, err := s.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(objectKey),
+	})
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, core.ErrKeyValueNotFound
+		}
+		return nil, errors.Wrapf(err, "loading object [%s] failed", objectKey)
+	}
+	defer output.Body.Close()
+
+	valueBytes, err := ioutil.ReadAll(output.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading object [%s] failed", objectKey)
+	}
+	return s.unmarshaller(valueBytes)
+}
+
+// Store sets the value for the key.
+func (s *S3KeyValueStore) Store(key interface{}, value interface{}) error {
+	if key == nil {
+		return errors.New("key is nil")
+	}
+	if value == nil {
+		return errors.New("value is nil")
+	}
+	objectKey, err := s.objectKey(key)
+	if err != nil {
+		return err
+	}
+	valueBytes, err := s.marshaller(value)
+	if err != nil {
+		return err
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(objectKey),
+		Body:   bytes.NewReader(valueBytes),
+	}
+	if s.serverSideEncryption != "" {
+		input.ServerSideEncryption = aws.String(s.serverSideEncryption)
+	}
+	if s.sseKMSKeyID != "" {
+		input.SSEKMSKeyId = aws.String(s.sseKMSKeyID)
+	}
+
+	if _, err := s.client.PutObject(input); err != nil {
+		return errors.Wrapf(err, "storing object [%s] failed", objectKey)
+	}
+	return nil
+}
+
+// Delete deletes the value for a key.
+func (s *S3KeyValueStore) Delete(key interface{}) error {
+	if key == nil {
+		return errors.New("key is nil")
+	}
+	objectKey, err := s.objectKey(key)
+	if err != nil {
+		return err
+	}
+	// Deleting an object which doesn't exist is not an error
+	_, err = s.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(objectKey),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "deleting object [%s] failed", objectKey)
+	}
+	return nil
+}
+
+func (s *S3KeyValueStore) objectKey(key interface{}) (string, error) {
+	k, err := s.keySerializer(key)
+	if err != nil {
+		return "", err
+	}
+	if s.prefix == "" {
+		return k, nil
+	}
+	return path.Join(s.prefix, k), nil
+}
+
+func isS3NotFound(err error) bool {
+	awsErr, ok := err.(awserr.Error)
+	if !ok {
+		return false
+	}
+	return awsErr.Code() == s3.ErrCodeNoSuchKey || awsErr.Code() == "NotFound"
+}
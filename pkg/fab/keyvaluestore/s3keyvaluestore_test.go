@@ -0,0 +1,118 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"bytes"
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestS3KVS(t *testing.T) {
+	client := newMockS3Client()
+	store := newS3(&S3KeyValueStoreOptions{
+		Bucket:               "fabric",
+		Prefix:               "users",
+		ServerSideEncryption: s3.ServerSideEncryptionAwsKms,
+		SSEKMSKeyID:          "key-id",
+	}, client)
+	assert.Equal(t, "fabric", store.GetBucket())
+
+	assert.EqualError(t, store.Store(nil, []byte("1234")), "key is nil")
+	assert.EqualError(t, store.Store("key", nil), "value is nil")
+
+	_, err := store.Load("key1")
+	assert.Equal(t, core.ErrKeyValueNotFound, err)
+
+	require.NoError(t, store.Store("key1", []byte("value1")))
+	object, ok := client.objects["users/key1"]
+	require.True(t, ok, "expected object key to be prefixed")
+	assert.Equal(t, s3.ServerSideEncryptionAwsKms, aws.StringValue(object.ServerSideEncryption))
+	assert.Equal(t, "key-id", aws.StringValue(object.SSEKMSKeyId))
+
+	value, err := store.Load("key1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value1"), value)
+
+	require.NoError(t, store.Delete("key1"))
+	_, err = store.Load("key1")
+	assert.Equal(t, core.ErrKeyValueNotFound, err)
+	assert.NoError(t, store.Delete("key1"), "deleting a non-existing key shouldn't fail")
+}
+
+func TestCreateNewS3KeyValueStore(t *testing.T) {
+	_, err := NewS3(nil)
+	assert.EqualError(t, err, "S3KeyValueStoreOptions is nil")
+
+	_, err = NewS3(&S3KeyValueStoreOptions{})
+	assert.EqualError(t, err, "S3KeyValueStore bucket is empty")
+
+	_, err = NewS3(&S3KeyValueStoreOptions{Bucket: "fabric", SSEKMSKeyID: "key-id", ServerSideEncryption: s3.ServerSideEncryptionAes256})
+	assert.Error(t, err)
+
+	store, err := NewS3(&S3KeyValueStoreOptions{
+		Bucket:          "fabric",
+		Region:          "us-east-1",
+		Endpoint:        "http://localhost:9000",
+		ForcePathStyle:  true,
+		AccessKeyID:     "access",
+		SecretAccessKey: "secret",
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, store)
+}
+
+type mockS3Client struct {
+	lock    sync.RWMutex
+	objects map[string]*s3.PutObjectInput
+	values  map[string][]byte
+}
+
+func newMockS3Client() *mockS3Client {
+	return &mockS3Client{
+		objects: make(map[string]*s3.PutObjectInput),
+		values:  make(map[string][]byte),
+	}
+}
+
+func (c *mockS3Client) GetObject(input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	value, ok := c.values[aws.StringValue(input.Key)]
+	if !ok {
+		return nil, awserr.New(s3.ErrCodeNoSuchKey, "not found", nil)
+	}
+	return &s3.GetObjectOutput{Body: ioutil.NopCloser(bytes.NewReader(value))}, nil
+}
+
+func (c *mockS3Client) PutObject(input *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+	value, err := ioutil.ReadAll(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.objects[aws.StringValue(input.Key)] = input
+	c.values[aws.StringValue(input.Key)] = value
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (c *mockS3Client) DeleteObject(input *s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.objects, aws.StringValue(input.Key))
+	delete(c.values, aws.StringValue(input.Key))
+	return &s3.DeleteObjectOutput{}, nil
+}
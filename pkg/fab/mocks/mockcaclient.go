@@ -23,7 +23,7 @@ func NewMockCAClient(orgName string, cryptoProvider core.CryptoSuite) (api.CACli
 }
 
 // Enroll enrolls a user with a Fabric network
-func (mgr *MockCAClient) Enroll(enrollmentID string, enrollmentSecret string) error {
+func (mgr *MockCAClient) Enroll(request *api.EnrollmentRequest) error {
 	return errors.New("not implemented")
 }
 
@@ -41,3 +41,13 @@ func (mgr *MockCAClient) Register(request *api.RegistrationRequest) (string, err
 func (mgr *MockCAClient) Revoke(request *api.RevocationRequest) (*api.RevocationResponse, error) {
 	return nil, errors.New("not implemented")
 }
+
+// GetCAInfo returns generic CA information
+func (mgr *MockCAClient) GetCAInfo() (*api.GetCAInfoResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+// GetIdemixIssuerKeys returns the public keys of the idemix issuer of the CA
+func (mgr *MockCAClient) GetIdemixIssuerKeys() (*api.IdemixIssuerKeys, error) {
+	return nil, errors.New("not implemented")
+}
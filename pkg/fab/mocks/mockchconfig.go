@@ -8,6 +8,7 @@ package mocks
 
 import (
 	reqContext "context"
+	"sort"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
@@ -16,13 +17,16 @@ import (
 
 // MockChannelCfg contains mock channel configuration
 type MockChannelCfg struct {
-	MockID          string
-	MockBlockNumber uint64
-	MockMSPs        []*msp.MSPConfig
-	MockAnchorPeers []*fab.OrgAnchorPeer
-	MockOrderers    []string
-	MockVersions    *fab.Versions
-	MockMembership  fab.ChannelMembership
+	MockID            string
+	MockBlockNumber   uint64
+	MockMSPs          []*msp.MSPConfig
+	MockAnchorPeers   []*fab.OrgAnchorPeer
+	MockOrderers      []string
+	MockVersions      *fab.Versions
+	MockMembership    fab.ChannelMembership
+	MockCapabilities  map[fab.ConfigGroupKey]map[string]bool
+	MockConsensusType string
+	MockConsenters    []*fab.Consenter
 }
 
 // NewMockChannelCfg ...
@@ -60,6 +64,33 @@ func (cfg *MockChannelCfg) Versions() *fab.Versions {
 	return cfg.MockVersions
 }
 
+// HasCapability returns true if the capability is enabled in the given group
+func (cfg *MockChannelCfg) HasCapability(group fab.ConfigGroupKey, capability string) bool {
+	return cfg.MockCapabilities[group][capability]
+}
+
+// ConsensusType returns the consensus type of the ordering service
+func (cfg *MockChannelCfg) ConsensusType() string {
+	return cfg.MockConsensusType
+}
+
+// Consenters returns the consenters of the BFT ordering service
+func (cfg *MockChannelCfg) Consenters() []*fab.Consenter {
+	return cfg.MockConsenters
+}
+
+// Capabilities returns the capabilities which are enabled in the given group
+func (cfg *MockChannelCfg) Capabilities(group fab.ConfigGroupKey) []string {
+	var capabilities []string
+	for capability, enabled := range cfg.MockCapabilities[group] {
+		if enabled {
+			capabilities = append(capabilities, capability)
+		}
+	}
+	sort.Strings(capabilities)
+	return capabilities
+}
+
 // MockChannelConfig mockcore query channel configuration
 type MockChannelConfig struct {
 	channelID string
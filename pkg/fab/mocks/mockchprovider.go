@@ -25,6 +25,7 @@ type MockChannelService struct {
 	channelID    string
 	transactor   fab.Transactor
 	mockOrderers []string
+	capabilities map[fab.ConfigGroupKey]map[string]bool
 }
 
 // NewMockChannelProvider returns a mock ChannelProvider
@@ -66,6 +67,17 @@ func (cs *MockChannelService) SetOrderers(orderers []string) {
 	cs.mockOrderers = orderers
 }
 
+// SetCapabilities sets the capabilities of the given config group of the mock channel config
+func (cs *MockChannelService) SetCapabilities(group fab.ConfigGroupKey, capabilities ...string) {
+	if cs.capabilities == nil {
+		cs.capabilities = make(map[fab.ConfigGroupKey]map[string]bool)
+	}
+	cs.capabilities[group] = make(map[string]bool)
+	for _, capability := range capabilities {
+		cs.capabilities[group][capability] = true
+	}
+}
+
 // EventService returns a mock event service
 func (cs *MockChannelService) EventService(opts ...options.Opt) (fab.EventService, error) {
 	return NewMockEventService(), nil
@@ -88,5 +100,5 @@ func (cs *MockChannelService) Membership() (fab.ChannelMembership, error) {
 
 //ChannelConfig returns channel config
 func (cs *MockChannelService) ChannelConfig() (fab.ChannelCfg, error) {
-	return &MockChannelCfg{MockID: cs.channelID, MockOrderers: cs.mockOrderers}, nil
+	return &MockChannelCfg{MockID: cs.channelID, MockOrderers: cs.mockOrderers, MockCapabilities: cs.capabilities}, nil
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
 	rwsetutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	kvrwset "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
@@ -23,12 +24,16 @@ import (
 type MockEndorserServer struct {
 	ProposalError error
 	AddkvWrite    bool
+	Trailer       metadata.MD
 }
 
 // ProcessProposal mock implementation that returns success if error is not set
 // error if it is
 func (m *MockEndorserServer) ProcessProposal(context context.Context,
 	proposal *pb.SignedProposal) (*pb.ProposalResponse, error) {
+	if m.Trailer != nil {
+		grpc.SetTrailer(context, m.Trailer) //nolint
+	}
 	if m.ProposalError == nil {
 		return &pb.ProposalResponse{Response: &pb.Response{
 			Status: 200,
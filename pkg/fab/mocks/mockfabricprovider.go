@@ -63,6 +63,11 @@ func (f *MockInfraProvider) CreatePeerFromConfig(peerCfg *fab.NetworkPeer) (fab.
 	return &MockPeer{}, nil
 }
 
+// CreateChannelPeerFromConfig returns a new default implementation of Peer based configuration
+func (f *MockInfraProvider) CreateChannelPeerFromConfig(ctx fab.ClientContext, channelID string, peerCfg *fab.NetworkPeer) (fab.Peer, error) {
+	return f.CreatePeerFromConfig(peerCfg)
+}
+
 // CreateOrdererFromConfig creates a default implementation of Orderer based on configuration.
 func (f *MockInfraProvider) CreateOrdererFromConfig(cfg *fab.OrdererConfig) (fab.Orderer, error) {
 	if f.customOrderer != nil {
@@ -72,6 +77,11 @@ func (f *MockInfraProvider) CreateOrdererFromConfig(cfg *fab.OrdererConfig) (fab
 	return &MockOrderer{}, nil
 }
 
+// CreateChannelOrdererFromConfig creates a default implementation of Orderer based on configuration.
+func (f *MockInfraProvider) CreateChannelOrdererFromConfig(ctx fab.ClientContext, channelID string, cfg *fab.OrdererConfig) (fab.Orderer, error) {
+	return f.CreateOrdererFromConfig(cfg)
+}
+
 //CommManager returns comm provider
 func (f *MockInfraProvider) CommManager() fab.CommManager {
 	return nil
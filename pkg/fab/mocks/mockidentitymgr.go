@@ -7,7 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package mocks
 
 import (
+	"bytes"
 	"fmt"
+	"sort"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
@@ -80,3 +82,23 @@ func (mgr *MockIdentityManager) GetSigningIdentity(id string) (msp.SigningIdenti
 	}
 	return si, nil
 }
+
+// ListUsers returns the IDs of the users of the identity manager
+func (mgr *MockIdentityManager) ListUsers() ([]string, error) {
+	var ids []string
+	for id := range mgr.users {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// GetSigningIdentityByCert returns the identity with the given enrollment certificate
+func (mgr *MockIdentityManager) GetSigningIdentityByCert(cert []byte) (msp.SigningIdentity, error) {
+	for _, si := range mgr.users {
+		if bytes.Equal(si.EnrollmentCertificate(), cert) {
+			return si, nil
+		}
+	}
+	return nil, msp.ErrUserNotFound
+}
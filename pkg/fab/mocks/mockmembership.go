@@ -6,10 +6,22 @@ SPDX-License-Identifier: Apache-2.0
 
 package mocks
 
+import (
+	"crypto/x509"
+	"sort"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
 // MockMembership mock member id
 type MockMembership struct {
 	ValidateErr error
 	VerifyErr   error
+	MemberRole  fab.MemberRole
+	RoleErr     error
+	MSPInfos    map[string]*fab.MSPInfo
+	CertPool    *x509.CertPool
 }
 
 // NewMockMembership new mock member id
@@ -26,3 +38,41 @@ func (m *MockMembership) Validate(serializedID []byte) error {
 func (m *MockMembership) Verify(serializedID []byte, msg []byte, sig []byte) error {
 	return m.VerifyErr
 }
+
+// Role returns the mock role (member by default)
+func (m *MockMembership) Role(serializedID []byte) (fab.MemberRole, error) {
+	if m.RoleErr != nil {
+		return "", m.RoleErr
+	}
+	if m.MemberRole == "" {
+		return fab.MemberRoleMember, nil
+	}
+	return m.MemberRole, nil
+}
+
+// MSPIDs returns the IDs of the mock MSPs
+func (m *MockMembership) MSPIDs() ([]string, error) {
+	var mspIDs []string
+	for mspID := range m.MSPInfos {
+		mspIDs = append(mspIDs, mspID)
+	}
+	sort.Strings(mspIDs)
+	return mspIDs, nil
+}
+
+// MSPInfo returns the mock MSP info for the given MSP
+func (m *MockMembership) MSPInfo(mspID string) (*fab.MSPInfo, error) {
+	info, ok := m.MSPInfos[mspID]
+	if !ok {
+		return nil, errors.Errorf("MSP [%s] not found", mspID)
+	}
+	return info, nil
+}
+
+// TLSCACertPool returns the mock cert pool (or a new cert pool if not set)
+func (m *MockMembership) TLSCACertPool(certs ...*x509.Certificate) (*x509.CertPool, error) {
+	if m.CertPool != nil {
+		return m.CertPool, nil
+	}
+	return x509.NewCertPool(), nil
+}
@@ -22,8 +22,6 @@ type MockOrderer struct {
 	OrdererURL        string
 	BroadcastListener chan *fab.SignedEnvelope
 	BroadcastErrors   chan error
-	Deliveries        chan *common.Block
-	DeliveryErrors    chan error
 	// These queues are used to detach the client, to avoid deadlocks
 	BroadcastQueue chan *fab.SignedEnvelope
 	DeliveryQueue  chan interface{}
@@ -35,8 +33,6 @@ func NewMockOrderer(url string, broadcastListener chan *fab.SignedEnvelope) *Moc
 		OrdererURL:        url,
 		BroadcastListener: broadcastListener,
 		BroadcastErrors:   make(chan error, 100),
-		Deliveries:        make(chan *common.Block, 1),
-		DeliveryErrors:    make(chan error, 1),
 		BroadcastQueue:    make(chan *fab.SignedEnvelope, 100),
 		DeliveryQueue:     make(chan interface{}, 100),
 	}
@@ -44,7 +40,6 @@ func NewMockOrderer(url string, broadcastListener chan *fab.SignedEnvelope) *Moc
 	if broadcastListener != nil {
 		go broadcast(o)
 	}
-	go delivery(o)
 	return o
 }
 
@@ -59,21 +54,21 @@ func broadcast(o *MockOrderer) {
 	}
 }
 
-func delivery(o *MockOrderer) {
+func delivery(o *MockOrderer, blocks chan *common.Block, errs chan error) {
+	defer close(blocks)
 	for {
 		value, ok := <-o.DeliveryQueue
 		if !ok {
-			close(o.Deliveries)
 			return
 		}
 		switch value.(type) {
 		case common.Status:
-			close(o.Deliveries)
 			return
 		case *common.Block:
-			o.Deliveries <- value.(*common.Block)
+			blocks <- value.(*common.Block)
 		case error:
-			o.DeliveryErrors <- value.(error)
+			errs <- value.(error)
+			return
 		default:
 			panic(fmt.Sprintf("Value not *common.Block nor error: %v", value))
 		}
@@ -102,7 +97,11 @@ func (o *MockOrderer) SendBroadcast(ctx reqContext.Context, envelope *fab.Signed
 
 // SendDeliver returns the channels for delivery of prepared mock values and errors (if any)
 func (o *MockOrderer) SendDeliver(ctx reqContext.Context, envelope *fab.SignedEnvelope) (chan *common.Block, chan error) {
-	return o.Deliveries, o.DeliveryErrors
+	// Each delivery reads the queue up to the next status, so that successive requests get their own blocks
+	blocks := make(chan *common.Block, 1)
+	errs := make(chan error, 1)
+	go delivery(o, blocks, errs)
+	return blocks, errs
 }
 
 // Close cleans up the instance and ends goroutines
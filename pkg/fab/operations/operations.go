@@ -0,0 +1,298 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operations
+
+import (
+	reqContext "context"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/pathvar"
+)
+
+var logger = logging.NewLogger("fabsdk/fab")
+
+const (
+	healthPath  = "/healthz"
+	versionPath = "/version"
+	metricsPath = "/metrics"
+
+	// StatusOK is the health status of a node which passed all of its health checks
+	StatusOK = "OK"
+)
+
+// HealthStatus is the health of a node as reported by its operations service
+type HealthStatus struct {
+	Status       string        `json:"status"`
+	Time         time.Time     `json:"time"`
+	FailedChecks []FailedCheck `json:"failed_checks,omitempty"`
+}
+
+// Healthy returns true if the node passed all of its health checks
+func (h *HealthStatus) Healthy() bool {
+	return h.Status == StatusOK
+}
+
+// FailedCheck is a health check which failed
+type FailedCheck struct {
+	Component string `json:"component"`
+	Reason    string `json:"reason"`
+}
+
+// VersionInfo is the version of a node as reported by its operations service
+type VersionInfo struct {
+	Version   string `json:"Version"`
+	CommitSHA string `json:"CommitSHA"`
+}
+
+// Client queries the operations service (health, version and metrics) of a peer or orderer
+type Client struct {
+	config     fab.EndpointConfig
+	url        string
+	serverName string
+	tlsCACert  *x509.Certificate
+	timeout    time.Duration
+	httpClient *http.Client
+}
+
+// Option describes a functional parameter for the New constructor
+type Option func(*Client) error
+
+// New returns a client of the operations service of a peer or orderer
+func New(config fab.EndpointConfig, opts ...Option) (*Client, error) {
+	client := &Client{
+		config:  config,
+		timeout: config.Timeout(fab.Query),
+	}
+
+	for _, opt := range opts {
+		err := opt(client)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if client.url == "" {
+		return nil, errors.New("operations URL is required")
+	}
+	if !strings.Contains(client.url, "://") {
+		client.url = "https://" + client.url
+	}
+	client.url = strings.TrimSuffix(client.url, "/")
+
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if endpoint.IsTLSEnabled(client.url) {
+		tlsConfig, err := comm.TLSConfig(client.tlsCACert, client.serverName, config)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	client.httpClient = &http.Client{
+		Transport: transport,
+		Timeout:   client.timeout,
+	}
+
+	return client, nil
+}
+
+// WithURL is a functional option for the operations.New constructor that configures the URL of the operations service.
+// If the URL has no protocol then https is used.
+func WithURL(url string) Option {
+	return func(c *Client) error {
+		c.url = url
+
+		return nil
+	}
+}
+
+// WithTLSCert is a functional option for the operations.New constructor that configures the TLS CA certificate of the operations service.
+func WithTLSCert(tlsCACert *x509.Certificate) Option {
+	return func(c *Client) error {
+		c.tlsCACert = tlsCACert
+
+		return nil
+	}
+}
+
+// WithServerName is a functional option for the operations.New constructor that configures the TLS server name override.
+func WithServerName(serverName string) Option {
+	return func(c *Client) error {
+		c.serverName = serverName
+
+		return nil
+	}
+}
+
+// WithTimeout is a functional option for the operations.New constructor that configures the timeout of requests.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		c.timeout = timeout
+
+		return nil
+	}
+}
+
+// FromOperationsConfig is a functional option for the operations.New constructor that configures the client from the given config.
+func FromOperationsConfig(operationsCfg *fab.OperationsConfig) Option {
+	return func(c *Client) error {
+		c.url = operationsCfg.URL
+
+		tlsCACerts := operationsCfg.TLSCACerts
+		if tlsCACerts.Path != "" {
+			tlsCACerts.Path = pathvar.Subst(tlsCACerts.Path)
+		}
+
+		var err error
+		c.tlsCACert, err = tlsCACerts.TLSCert()
+		if err != nil {
+			//Ignore empty cert errors,
+			errStatus, ok := err.(*status.Status)
+			if !ok || errStatus.Code != status.EmptyCert.ToInt32() {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// FromPeerConfig is a functional option for the operations.New constructor that configures the client
+// from the operations endpoint of the given peer. The TLS server name override of the peer's gRPC endpoint is also used.
+func FromPeerConfig(peerCfg *fab.PeerConfig) Option {
+	return func(c *Client) error {
+		c.serverName = getServerNameOverride(peerCfg.GRPCOptions)
+
+		return FromOperationsConfig(&peerCfg.Operations)(c)
+	}
+}
+
+// FromOrdererConfig is a functional option for the operations.New constructor that configures the client
+// from the operations endpoint of the given orderer. The TLS server name override of the orderer's gRPC endpoint is also used.
+func FromOrdererConfig(ordererCfg *fab.OrdererConfig) Option {
+	return func(c *Client) error {
+		c.serverName = getServerNameOverride(ordererCfg.GRPCOptions)
+
+		return FromOperationsConfig(&ordererCfg.Operations)(c)
+	}
+}
+
+func getServerNameOverride(grpcOptions map[string]interface{}) string {
+	serverHostOverride := ""
+	if str, ok := grpcOptions["ssl-target-name-override"].(string); ok {
+		serverHostOverride = str
+	}
+	return serverHostOverride
+}
+
+// URL returns the URL of the operations service
+func (c *Client) URL() string {
+	return c.url
+}
+
+// Health returns the health of the node. The status is returned even if the node is unhealthy.
+func (c *Client) Health(ctx reqContext.Context) (*HealthStatus, error) {
+	resp, err := c.get(ctx, healthPath)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp.Body)
+
+	// An unhealthy node responds with 503 and the failed checks
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		return nil, unexpectedResponse(resp)
+	}
+
+	health := &HealthStatus{}
+	if err := json.NewDecoder(resp.Body).Decode(health); err != nil {
+		return nil, errors.Wrap(err, "failed to decode health status")
+	}
+	return health, nil
+}
+
+// Version returns the version of the node
+func (c *Client) Version(ctx reqContext.Context) (*VersionInfo, error) {
+	resp, err := c.get(ctx, versionPath)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, unexpectedResponse(resp)
+	}
+
+	version := &VersionInfo{}
+	if err := json.NewDecoder(resp.Body).Decode(version); err != nil {
+		return nil, errors.Wrap(err, "failed to decode version")
+	}
+	return version, nil
+}
+
+// Metrics scrapes the Prometheus metrics of the node and returns the metric families by name.
+// The node must be configured with the Prometheus metrics provider.
+func (c *Client) Metrics(ctx reqContext.Context) (map[string]*dto.MetricFamily, error) {
+	resp, err := c.get(ctx, metricsPath)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, unexpectedResponse(resp)
+	}
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse metrics")
+	}
+	return families, nil
+}
+
+func (c *Client) get(ctx reqContext.Context, path string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, c.url+path, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+
+	resp, err := c.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrapf(err, "request to [%s] failed", c.url+path)
+	}
+	return resp, nil
+}
+
+func unexpectedResponse(resp *http.Response) error {
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		logger.Debugf("Failed to read response body: %s", err)
+	}
+	return errors.Errorf("request to [%s] failed with status %d: %s", resp.Request.URL, resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+func closeBody(body io.Closer) {
+	if err := body.Close(); err != nil {
+		logger.Debugf("Failed to close response body: %s", err)
+	}
+}
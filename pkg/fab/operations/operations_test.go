@@ -0,0 +1,154 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operations
+
+import (
+	reqContext "context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+)
+
+const testMetrics = `# HELP ledger_blockchain_height Height of the chain in blocks.
+# TYPE ledger_blockchain_height gauge
+ledger_blockchain_height{channel="mychannel"} 12
+`
+
+// mockConfig trusts the certificates which are passed to TLSCACertPool
+type mockConfig struct {
+	fab.EndpointConfig
+}
+
+func (c *mockConfig) TLSCACertPool(certs ...*x509.Certificate) *x509.CertPool {
+	pool := x509.NewCertPool()
+	for _, cert := range certs {
+		if cert != nil {
+			pool.AddCert(cert)
+		}
+	}
+	return pool
+}
+
+func newOperationsHandler(healthy bool) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(healthPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if healthy {
+			fmt.Fprint(w, `{"status":"OK","time":"2018-06-01T10:00:00Z"}`)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, `{"status":"Service Unavailable","time":"2018-06-01T10:00:00Z","failed_checks":[{"component":"docker","reason":"failed to connect to Docker daemon"}]}`)
+	})
+	mux.HandleFunc(versionPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"Version":"1.4.0","CommitSHA":"d700b43"}`)
+	})
+	mux.HandleFunc(metricsPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, testMetrics)
+	})
+	return mux
+}
+
+func TestOperations(t *testing.T) {
+	server := httptest.NewServer(newOperationsHandler(true))
+	defer server.Close()
+
+	client, err := New(mocks.NewMockEndpointConfig(), WithURL(server.URL))
+	require.NoError(t, err)
+	assert.Equal(t, server.URL, client.URL())
+
+	health, err := client.Health(reqContext.Background())
+	require.NoError(t, err)
+	assert.True(t, health.Healthy())
+	assert.Empty(t, health.FailedChecks)
+
+	version, err := client.Version(reqContext.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "1.4.0", version.Version)
+	assert.Equal(t, "d700b43", version.CommitSHA)
+
+	metrics, err := client.Metrics(reqContext.Background())
+	require.NoError(t, err)
+	family, ok := metrics["ledger_blockchain_height"]
+	require.True(t, ok, "expected ledger_blockchain_height metric")
+	require.Len(t, family.Metric, 1)
+	assert.EqualValues(t, 12, family.Metric[0].GetGauge().GetValue())
+}
+
+func TestUnhealthy(t *testing.T) {
+	server := httptest.NewServer(newOperationsHandler(false))
+	defer server.Close()
+
+	client, err := New(mocks.NewMockEndpointConfig(), WithURL(server.URL))
+	require.NoError(t, err)
+
+	health, err := client.Health(reqContext.Background())
+	require.NoError(t, err)
+	assert.False(t, health.Healthy())
+	require.Len(t, health.FailedChecks, 1)
+	assert.Equal(t, "docker", health.FailedChecks[0].Component)
+}
+
+func TestUnexpectedResponse(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	client, err := New(mocks.NewMockEndpointConfig(), WithURL(server.URL))
+	require.NoError(t, err)
+
+	_, err = client.Health(reqContext.Background())
+	assert.Error(t, err)
+	_, err = client.Version(reqContext.Background())
+	assert.Error(t, err)
+	_, err = client.Metrics(reqContext.Background())
+	assert.Error(t, err)
+}
+
+func TestOperationsTLS(t *testing.T) {
+	server := httptest.NewTLSServer(newOperationsHandler(true))
+	defer server.Close()
+
+	config := &mockConfig{EndpointConfig: mocks.NewMockEndpointConfig()}
+
+	client, err := New(config, WithURL(server.URL), WithTLSCert(server.Certificate()))
+	require.NoError(t, err)
+	health, err := client.Health(reqContext.Background())
+	require.NoError(t, err)
+	assert.True(t, health.Healthy())
+
+	// The server certificate isn't trusted without the TLS CA certificate
+	client, err = New(config, WithURL(server.URL))
+	require.NoError(t, err)
+	_, err = client.Health(reqContext.Background())
+	assert.Error(t, err)
+}
+
+func TestFromPeerConfig(t *testing.T) {
+	peerCfg := &fab.PeerConfig{
+		URL:         "peer0.org1.example.com:7051",
+		GRPCOptions: map[string]interface{}{"ssl-target-name-override": "peer0.org1.example.com"},
+		Operations: fab.OperationsConfig{
+			URL: "peer0.org1.example.com:9443",
+		},
+	}
+
+	client, err := New(mocks.NewMockEndpointConfig(), FromPeerConfig(peerCfg))
+	require.NoError(t, err)
+	assert.Equal(t, "https://peer0.org1.example.com:9443", client.URL())
+	assert.Equal(t, "peer0.org1.example.com", client.serverName)
+
+	_, err = New(mocks.NewMockEndpointConfig(), FromOrdererConfig(&fab.OrdererConfig{URL: "orderer.example.com:7050"}))
+	assert.Error(t, err, "expected error for orderer without operations URL")
+}
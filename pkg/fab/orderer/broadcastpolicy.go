@@ -0,0 +1,100 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package orderer
+
+import (
+	reqContext "context"
+	"time"
+
+	"github.com/spf13/cast"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+const (
+	defaultBroadcastInitialBackoff = 500 * time.Millisecond
+	defaultBroadcastMaxBackoff     = 5 * time.Second
+	defaultBroadcastBackoffFactor  = 2.0
+)
+
+// BroadcastPolicy defines the deadline of each attempt to broadcast an envelope to the orderer and how
+// failed attempts are retried. It is distinct from the retry options of a request (e.g. for endorsements)
+// so that a hung orderer stream doesn't consume the whole request deadline.
+type BroadcastPolicy struct {
+	// AttemptTimeout is the timeout of each attempt. If zero then an attempt is only bounded by the request deadline.
+	AttemptTimeout time.Duration
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum delay between retries
+	MaxBackoff time.Duration
+	// BackoffFactor is the factor by which the backoff is multiplied after each retry
+	BackoffFactor float64
+	// MaxElapsedTime is the time after which no more attempts are started. Failed attempts aren't retried if zero.
+	MaxElapsedTime time.Duration
+}
+
+// BroadcastPolicyFromOptions returns the broadcast policy defined by the given GRPC options of an orderer,
+// i.e. 'broadcast-attempt-timeout', 'broadcast-initial-backoff', 'broadcast-max-backoff',
+// 'broadcast-backoff-factor' and 'broadcast-max-elapsed-time'
+func BroadcastPolicyFromOptions(grpcOptions map[string]interface{}) BroadcastPolicy {
+	policy := BroadcastPolicy{
+		InitialBackoff: defaultBroadcastInitialBackoff,
+		MaxBackoff:     defaultBroadcastMaxBackoff,
+		BackoffFactor:  defaultBroadcastBackoffFactor,
+	}
+
+	if timeout, ok := grpcOptions["broadcast-attempt-timeout"]; ok {
+		policy.AttemptTimeout = cast.ToDuration(timeout)
+	}
+	if backoff, ok := grpcOptions["broadcast-initial-backoff"]; ok {
+		policy.InitialBackoff = cast.ToDuration(backoff)
+	}
+	if backoff, ok := grpcOptions["broadcast-max-backoff"]; ok {
+		policy.MaxBackoff = cast.ToDuration(backoff)
+	}
+	if factor, ok := grpcOptions["broadcast-backoff-factor"]; ok {
+		policy.BackoffFactor = cast.ToFloat64(factor)
+	}
+	if elapsed, ok := grpcOptions["broadcast-max-elapsed-time"]; ok {
+		policy.MaxElapsedTime = cast.ToDuration(elapsed)
+	}
+
+	return policy
+}
+
+// attemptContext returns the context of a single broadcast attempt
+func (p BroadcastPolicy) attemptContext(ctx reqContext.Context) (reqContext.Context, reqContext.CancelFunc) {
+	if p.AttemptTimeout <= 0 {
+		return reqContext.WithCancel(ctx)
+	}
+	return reqContext.WithTimeout(ctx, p.AttemptTimeout)
+}
+
+func (p BroadcastPolicy) nextBackoff(backoff time.Duration) time.Duration {
+	next := time.Duration(float64(backoff) * p.BackoffFactor)
+	if p.MaxBackoff > 0 && next > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return next
+}
+
+// isRetryableBroadcastError returns false if the orderer rejected the envelope, in which case
+// sending it again won't help. All other errors (e.g. connection failures, timed-out attempts
+// or an unavailable ordering service) are transient.
+func isRetryableBroadcastError(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok || s.Group != status.OrdererServerStatus {
+		return true
+	}
+	switch common.Status(s.Code) {
+	case common.Status_SERVICE_UNAVAILABLE, common.Status_INTERNAL_SERVER_ERROR:
+		return true
+	default:
+		return false
+	}
+}
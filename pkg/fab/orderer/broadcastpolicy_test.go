@@ -0,0 +1,190 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package orderer
+
+import (
+	reqContext "context"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	mocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+// flakyBroadcastServer hangs (until the stream is cancelled) on the first 'hang' attempts and then
+// replies with the status of 'responses' (one per attempt) or with SUCCESS once they're exhausted
+type flakyBroadcastServer struct {
+	mocks.MockBroadcastServer
+	hang      int32
+	responses []common.Status
+	attempts  int32
+}
+
+func (m *flakyBroadcastServer) Broadcast(server ab.AtomicBroadcast_BroadcastServer) error {
+	if _, err := server.Recv(); err != nil {
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+
+	attempt := atomic.AddInt32(&m.attempts, 1)
+	if attempt <= m.hang {
+		<-server.Context().Done()
+		return server.Context().Err()
+	}
+
+	response := &ab.BroadcastResponse{Status: common.Status_SUCCESS}
+	if i := int(attempt - m.hang - 1); i < len(m.responses) {
+		response.Status = m.responses[i]
+	}
+	return server.Send(response)
+}
+
+func startFlakyServer(t *testing.T, broadcastServer *flakyBroadcastServer) (*grpc.Server, string) {
+	lis, err := net.Listen("tcp", testOrdererURL)
+	require.NoError(t, err)
+
+	grpcServer := grpc.NewServer()
+	ab.RegisterAtomicBroadcastServer(grpcServer, broadcastServer)
+	go grpcServer.Serve(lis)
+
+	return grpcServer, lis.Addr().String()
+}
+
+func newFlakyOrderer(t *testing.T, addr string, policy BroadcastPolicy) *Orderer {
+	orderer, err := New(mocks.NewMockEndpointConfig(), WithURL("grpc://"+addr), WithInsecure(), WithBroadcastPolicy(policy))
+	require.NoError(t, err)
+	return orderer
+}
+
+func TestBroadcastPolicyFromOptions(t *testing.T) {
+	policy := BroadcastPolicyFromOptions(map[string]interface{}{})
+	assert.Equal(t, BroadcastPolicy{
+		InitialBackoff: defaultBroadcastInitialBackoff,
+		MaxBackoff:     defaultBroadcastMaxBackoff,
+		BackoffFactor:  defaultBroadcastBackoffFactor,
+	}, policy)
+
+	policy = BroadcastPolicyFromOptions(map[string]interface{}{
+		"broadcast-attempt-timeout":  "3s",
+		"broadcast-initial-backoff":  "100ms",
+		"broadcast-max-backoff":      "1s",
+		"broadcast-backoff-factor":   1.5,
+		"broadcast-max-elapsed-time": "10s",
+	})
+	assert.Equal(t, BroadcastPolicy{
+		AttemptTimeout: 3 * time.Second,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		BackoffFactor:  1.5,
+		MaxElapsedTime: 10 * time.Second,
+	}, policy)
+
+	assert.Equal(t, 150*time.Millisecond, policy.nextBackoff(100*time.Millisecond))
+	assert.Equal(t, time.Second, policy.nextBackoff(900*time.Millisecond))
+}
+
+func TestSendBroadcastAttemptTimeout(t *testing.T) {
+	broadcastServer := &flakyBroadcastServer{hang: 2}
+	grpcServer, addr := startFlakyServer(t, broadcastServer)
+	defer grpcServer.Stop()
+
+	orderer := newFlakyOrderer(t, addr, BroadcastPolicy{
+		AttemptTimeout: 200 * time.Millisecond,
+		InitialBackoff: 10 * time.Millisecond,
+		BackoffFactor:  2,
+		MaxElapsedTime: 10 * time.Second,
+	})
+
+	ctx, cancel := reqContext.WithTimeout(reqContext.Background(), 5*time.Second)
+	defer cancel()
+
+	begin := time.Now()
+	_, err := orderer.SendBroadcast(ctx, &fab.SignedEnvelope{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&broadcastServer.attempts))
+	assert.True(t, time.Since(begin) < 2*time.Second, "hung attempts should not consume the request deadline")
+}
+
+func TestSendBroadcastRetryUnavailable(t *testing.T) {
+	broadcastServer := &flakyBroadcastServer{responses: []common.Status{common.Status_SERVICE_UNAVAILABLE, common.Status_SERVICE_UNAVAILABLE}}
+	grpcServer, addr := startFlakyServer(t, broadcastServer)
+	defer grpcServer.Stop()
+
+	orderer := newFlakyOrderer(t, addr, BroadcastPolicy{
+		InitialBackoff: 10 * time.Millisecond,
+		BackoffFactor:  2,
+		MaxElapsedTime: 10 * time.Second,
+	})
+
+	_, err := orderer.SendBroadcast(reqContext.Background(), &fab.SignedEnvelope{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&broadcastServer.attempts))
+}
+
+func TestSendBroadcastNoRetry(t *testing.T) {
+	// The envelope is rejected - no retries
+	broadcastServer := &flakyBroadcastServer{responses: []common.Status{common.Status_BAD_REQUEST}}
+	grpcServer, addr := startFlakyServer(t, broadcastServer)
+	defer grpcServer.Stop()
+
+	orderer := newFlakyOrderer(t, addr, BroadcastPolicy{
+		InitialBackoff: 10 * time.Millisecond,
+		BackoffFactor:  2,
+		MaxElapsedTime: 10 * time.Second,
+	})
+
+	_, err := orderer.SendBroadcast(reqContext.Background(), &fab.SignedEnvelope{})
+	require.Error(t, err)
+	statusError, ok := status.FromError(err)
+	require.True(t, ok, "Expected status error")
+	assert.EqualValues(t, common.Status_BAD_REQUEST, status.ToOrdererStatusCode(statusError.Code))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&broadcastServer.attempts))
+
+	// Retries are disabled by default
+	broadcastServer = &flakyBroadcastServer{responses: []common.Status{common.Status_SERVICE_UNAVAILABLE}}
+	grpcServer2, addr := startFlakyServer(t, broadcastServer)
+	defer grpcServer2.Stop()
+
+	orderer = newFlakyOrderer(t, addr, BroadcastPolicyFromOptions(nil))
+
+	_, err = orderer.SendBroadcast(reqContext.Background(), &fab.SignedEnvelope{})
+	require.Error(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&broadcastServer.attempts))
+}
+
+func TestSendBroadcastMaxElapsedTime(t *testing.T) {
+	broadcastServer := &flakyBroadcastServer{hang: 100}
+	grpcServer, addr := startFlakyServer(t, broadcastServer)
+	defer grpcServer.Stop()
+
+	orderer := newFlakyOrderer(t, addr, BroadcastPolicy{
+		AttemptTimeout: 100 * time.Millisecond,
+		InitialBackoff: 50 * time.Millisecond,
+		BackoffFactor:  1,
+		MaxElapsedTime: 500 * time.Millisecond,
+	})
+
+	begin := time.Now()
+	_, err := orderer.SendBroadcast(reqContext.Background(), &fab.SignedEnvelope{})
+	require.Error(t, err)
+	elapsed := time.Since(begin)
+	assert.True(t, elapsed < 2*time.Second, "expected broadcast to give up after the max elapsed time but took %s", elapsed)
+	attempts := atomic.LoadInt32(&broadcastServer.attempts)
+	assert.True(t, attempts > 1 && attempts < 10, "unexpected number of attempts: %d", attempts)
+}
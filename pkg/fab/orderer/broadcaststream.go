@@ -0,0 +1,218 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package orderer
+
+import (
+	reqContext "context"
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	grpcstatus "google.golang.org/grpc/status"
+
+	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+// BroadcastResult is the outcome of an envelope submitted on a BroadcastStream
+type BroadcastResult struct {
+	// Status is the status returned by the orderer (nil if Err is set)
+	Status *common.Status
+	// Err is set if the orderer rejected the envelope or the stream failed
+	Err error
+}
+
+// BroadcastStream keeps a broadcast stream to the orderer open so that envelopes may be
+// submitted without waiting for the status of previously submitted envelopes.
+// The orderer replies to the envelopes of a stream in the order in which they were
+// received, which is how each status is correlated to its envelope.
+//
+// If the stream fails, all envelopes which are awaiting a status receive the error
+// and the stream may no longer be used. The stream must be closed when it is no longer needed.
+type BroadcastStream struct {
+	orderer *Orderer
+	ctx     reqContext.Context
+	cancel  reqContext.CancelFunc
+	conn    *grpc.ClientConn
+	client  ab.AtomicBroadcast_BroadcastClient
+	// sendLock serializes the sends on the stream, which may block on flow control.
+	// It is acquired before lock, which is never held while sending.
+	sendLock sync.Mutex
+	lock     sync.Mutex
+	pending  []chan *BroadcastResult
+	err      error
+	closing  bool
+	done     chan struct{}
+}
+
+// NewBroadcastStream opens a broadcast stream to the orderer. The stream
+// remains open until it is closed or the given context is done.
+func (o *Orderer) NewBroadcastStream(ctx reqContext.Context) (*BroadcastStream, error) {
+	conn, err := o.conn(ctx)
+	if err != nil {
+		rpcStatus, ok := grpcstatus.FromError(err)
+		if ok {
+			return nil, errors.WithMessage(status.NewFromGRPCStatus(rpcStatus), "connection failed")
+		}
+
+		return nil, status.New(status.OrdererClientStatus, status.ConnectionFailed.ToInt32(), err.Error(), nil)
+	}
+
+	streamCtx, cancel := reqContext.WithCancel(ctx)
+	broadcastClient, err := ab.NewAtomicBroadcastClient(conn).Broadcast(streamCtx)
+	if err != nil {
+		cancel()
+		o.releaseConn(ctx, conn)
+		rpcStatus, ok := grpcstatus.FromError(err)
+		if ok {
+			err = status.NewFromGRPCStatus(rpcStatus)
+		}
+		return nil, errors.Wrap(err, "NewAtomicBroadcastClient failed")
+	}
+
+	s := &BroadcastStream{
+		orderer: o,
+		ctx:     ctx,
+		cancel:  cancel,
+		conn:    conn,
+		client:  broadcastClient,
+		done:    make(chan struct{}),
+	}
+	go s.receive()
+
+	return s, nil
+}
+
+// Send submits the envelope to the orderer and returns a channel which receives
+// the result once the orderer has replied. Send doesn't wait for the reply.
+func (s *BroadcastStream) Send(envelope *fab.SignedEnvelope) (<-chan *BroadcastResult, error) {
+	s.sendLock.Lock()
+	defer s.sendLock.Unlock()
+
+	// The result is queued before the envelope is sent since the reply may be
+	// received before Send returns
+	result := make(chan *BroadcastResult, 1)
+	s.lock.Lock()
+	if s.err != nil {
+		s.lock.Unlock()
+		return nil, errors.WithMessage(s.err, "broadcast stream failed")
+	}
+	if s.closing {
+		s.lock.Unlock()
+		return nil, errors.New("broadcast stream is closed")
+	}
+	s.pending = append(s.pending, result)
+	s.lock.Unlock()
+
+	err := s.client.Send(&common.Envelope{
+		Payload:   envelope.Payload,
+		Signature: envelope.Signature,
+	})
+	if err != nil {
+		s.lock.Lock()
+		// The result is the last one queued since sends are serialized, unless
+		// the stream has failed in the meantime (in which case it was dequeued)
+		if n := len(s.pending); n > 0 && s.pending[n-1] == result {
+			s.pending = s.pending[:n-1]
+		}
+		s.lock.Unlock()
+		return nil, errors.Wrap(err, "failed to send envelope to orderer")
+	}
+
+	return result, nil
+}
+
+// Pending returns the number of submitted envelopes which are awaiting a status
+func (s *BroadcastStream) Pending() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.pending)
+}
+
+// Close closes the stream once the orderer has replied to the submitted envelopes (or the
+// context of the stream is done) and releases the connection. Envelopes which didn't
+// receive a status by then receive an error.
+func (s *BroadcastStream) Close() {
+	s.sendLock.Lock()
+	s.lock.Lock()
+	if s.closing {
+		s.lock.Unlock()
+		s.sendLock.Unlock()
+		<-s.done
+		return
+	}
+	s.closing = true
+	s.lock.Unlock()
+
+	if err := s.client.CloseSend(); err != nil {
+		logger.Debugf("unable to close broadcast client [%s]", err)
+	}
+	s.sendLock.Unlock()
+
+	select {
+	case <-s.done:
+	case <-s.ctx.Done():
+		s.cancel()
+		<-s.done
+	}
+
+	s.cancel()
+	s.orderer.releaseConn(s.ctx, s.conn)
+}
+
+func (s *BroadcastStream) receive() {
+	defer close(s.done)
+
+	for {
+		broadcastResponse, err := s.client.Recv()
+		if err != nil {
+			s.fail(err)
+			return
+		}
+
+		result := &BroadcastResult{}
+		if broadcastResponse.Status != common.Status_SUCCESS {
+			result.Err = status.New(status.OrdererServerStatus, int32(broadcastResponse.Status), broadcastResponse.Info, nil)
+		} else {
+			responseStatus := broadcastResponse.Status
+			result.Status = &responseStatus
+		}
+
+		s.lock.Lock()
+		if len(s.pending) == 0 {
+			s.lock.Unlock()
+			logger.Warnf("Received unexpected broadcast response from orderer [%s]: %s", s.orderer.url, broadcastResponse.Status)
+			continue
+		}
+		pending := s.pending[0]
+		s.pending = s.pending[1:]
+		s.lock.Unlock()
+
+		pending <- result
+	}
+}
+
+// fail delivers the error to all envelopes which are awaiting a status
+func (s *BroadcastStream) fail(err error) {
+	if err == io.EOF {
+		err = errors.New("broadcast stream closed by orderer")
+	} else {
+		err = errors.Wrap(status.FromStreamError(err, s.client), "broadcast recv failed")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.err = err
+	for _, pending := range s.pending {
+		pending <- &BroadcastResult{Err: err}
+	}
+	s.pending = nil
+}
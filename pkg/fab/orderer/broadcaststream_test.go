@@ -0,0 +1,202 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package orderer
+
+import (
+	reqContext "context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	mocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+// streamingBroadcastServer replies to each envelope of a broadcast stream. Envelopes with
+// the payload "bad" are rejected and the payload "fail" causes the stream to fail.
+type streamingBroadcastServer struct {
+	mocks.MockBroadcastServer
+	received chan struct{}
+}
+
+func (m *streamingBroadcastServer) Broadcast(server ab.AtomicBroadcast_BroadcastServer) error {
+	for {
+		envelope, err := server.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if m.received != nil {
+			<-m.received
+		}
+
+		response := &ab.BroadcastResponse{Status: common.Status_SUCCESS}
+		switch string(envelope.Payload) {
+		case "bad":
+			response = &ab.BroadcastResponse{Status: common.Status_BAD_REQUEST, Info: "bad envelope"}
+		case "fail":
+			return io.ErrUnexpectedEOF
+		}
+		if err := server.Send(response); err != nil {
+			return err
+		}
+	}
+}
+
+func startStreamingServer(t *testing.T, broadcastServer *streamingBroadcastServer) (*grpc.Server, string) {
+	lis, err := net.Listen("tcp", testOrdererURL)
+	require.NoError(t, err)
+
+	grpcServer := grpc.NewServer()
+	ab.RegisterAtomicBroadcastServer(grpcServer, broadcastServer)
+	go grpcServer.Serve(lis)
+
+	return grpcServer, lis.Addr().String()
+}
+
+func TestBroadcastStream(t *testing.T) {
+	grpcServer, addr := startStreamingServer(t, &streamingBroadcastServer{})
+	defer grpcServer.Stop()
+
+	orderer, err := New(mocks.NewMockEndpointConfig(), FromOrdererConfig(getGRPCOpts(addr, true, false, true)))
+	require.NoError(t, err)
+
+	ctx, cancel := reqContext.WithTimeout(reqContext.Background(), 15*time.Second)
+	defer cancel()
+
+	stream, err := orderer.NewBroadcastStream(ctx)
+	require.NoError(t, err)
+
+	payloads := []string{"a", "bad", "b", "c"}
+	var results []<-chan *BroadcastResult
+	for _, payload := range payloads {
+		result, err := stream.Send(&fab.SignedEnvelope{Payload: []byte(payload)})
+		require.NoError(t, err)
+		results = append(results, result)
+	}
+
+	for i, result := range results {
+		r := <-result
+		if payloads[i] == "bad" {
+			require.Error(t, r.Err)
+			statusError, ok := status.FromError(r.Err)
+			require.True(t, ok, "Expected status error")
+			assert.Equal(t, status.OrdererServerStatus, statusError.Group)
+			assert.EqualValues(t, common.Status_BAD_REQUEST, statusError.Code)
+			assert.Equal(t, "bad envelope", statusError.Message)
+			continue
+		}
+		require.NoError(t, r.Err)
+		assert.Equal(t, common.Status_SUCCESS, *r.Status)
+	}
+	assert.Equal(t, 0, stream.Pending())
+
+	stream.Close()
+
+	_, err = stream.Send(&fab.SignedEnvelope{Payload: []byte("d")})
+	assert.Error(t, err, "expected error sending on closed stream")
+}
+
+func TestBroadcastStreamBlockedSend(t *testing.T) {
+	received := make(chan struct{})
+	grpcServer, addr := startStreamingServer(t, &streamingBroadcastServer{received: received})
+	defer grpcServer.Stop()
+
+	orderer, err := New(mocks.NewMockEndpointConfig(), FromOrdererConfig(getGRPCOpts(addr, true, false, true)))
+	require.NoError(t, err)
+
+	ctx, cancel := reqContext.WithTimeout(reqContext.Background(), 15*time.Second)
+	defer cancel()
+
+	stream, err := orderer.NewBroadcastStream(ctx)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	first, err := stream.Send(&fab.SignedEnvelope{Payload: []byte("a")})
+	require.NoError(t, err)
+
+	// The orderer doesn't read the large envelope until it has replied to the first
+	// one, so sending it blocks on flow control
+	sent := make(chan error, 1)
+	go func() {
+		_, err := stream.Send(&fab.SignedEnvelope{Payload: make([]byte, 3*1024*1024)})
+		sent <- err
+	}()
+
+	pending := make(chan int, 1)
+	go func() {
+		for stream.Pending() < 2 {
+			time.Sleep(10 * time.Millisecond)
+		}
+		pending <- stream.Pending()
+	}()
+	select {
+	case n := <-pending:
+		assert.Equal(t, 2, n)
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream is locked while an envelope is being sent")
+	}
+
+	close(received)
+	require.NoError(t, (<-first).Err)
+	require.NoError(t, <-sent)
+}
+
+func TestBroadcastStreamFailure(t *testing.T) {
+	received := make(chan struct{})
+	grpcServer, addr := startStreamingServer(t, &streamingBroadcastServer{received: received})
+	defer grpcServer.Stop()
+
+	orderer, err := New(mocks.NewMockEndpointConfig(), FromOrdererConfig(getGRPCOpts(addr, true, false, true)))
+	require.NoError(t, err)
+
+	stream, err := orderer.NewBroadcastStream(reqContext.Background())
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var results []<-chan *BroadcastResult
+	for _, payload := range []string{"a", "fail", "b"} {
+		result, err := stream.Send(&fab.SignedEnvelope{Payload: []byte(payload)})
+		require.NoError(t, err)
+		results = append(results, result)
+	}
+	close(received)
+
+	r := <-results[0]
+	require.NoError(t, r.Err)
+
+	// The stream failed so the remaining envelopes receive the error
+	for _, result := range results[1:] {
+		r := <-result
+		assert.Error(t, r.Err)
+	}
+
+	_, err = stream.Send(&fab.SignedEnvelope{Payload: []byte("c")})
+	assert.Error(t, err, "expected error sending on failed stream")
+}
+
+func TestBroadcastStreamBadDial(t *testing.T) {
+	orderer, err := New(mocks.NewMockEndpointConfig(), FromOrdererConfig(getGRPCOpts(testOrdererURL+"Test", true, false, true)))
+	require.NoError(t, err)
+	orderer.dialTimeout = 15
+
+	_, err = orderer.NewBroadcastStream(reqContext.Background())
+	require.Error(t, err)
+	statusError, ok := status.FromError(err)
+	require.True(t, ok, "Expected status error")
+	assert.Equal(t, status.OrdererClientStatus, statusError.Group)
+}
@@ -48,6 +48,14 @@ type Orderer struct {
 	dialTimeout    time.Duration
 	failFast       bool
 	allowInsecure  bool
+	proxy          endpoint.ProxyConfig
+	compression    string
+	retryPolicy    comm.RetryPolicy
+	bcastPolicy    BroadcastPolicy
+	dialer         string
+	certPins       []string
+	maxRecvMsgSize int
+	maxSendMsgSize int
 	commManager    fab.CommManager
 }
 
@@ -57,8 +65,10 @@ type Option func(*Orderer) error
 // New Returns a Orderer instance
 func New(config fab.EndpointConfig, opts ...Option) (*Orderer, error) {
 	orderer := &Orderer{
-		config:      config,
-		commManager: &defCommManager{},
+		config:         config,
+		commManager:    &defCommManager{},
+		maxRecvMsgSize: maxCallRecvMsgSize,
+		maxSendMsgSize: maxCallSendMsgSize,
 	}
 
 	for _, opt := range opts {
@@ -73,6 +83,30 @@ func New(config fab.EndpointConfig, opts ...Option) (*Orderer, error) {
 		grpcOpts = append(grpcOpts, grpc.WithKeepaliveParams(orderer.kap))
 	}
 	grpcOpts = append(grpcOpts, grpc.WithDefaultCallOptions(grpc.FailFast(orderer.failFast)))
+
+	// A custom dialer takes precedence over the proxy
+	dialerOpt, err := comm.DialerDialOption(config, orderer.dialer)
+	if err != nil {
+		return nil, err
+	}
+	if dialerOpt == nil {
+		dialerOpt, err = comm.ProxyDialOption(orderer.url, orderer.proxy)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if dialerOpt != nil {
+		grpcOpts = append(grpcOpts, dialerOpt)
+	}
+
+	compressionOpts, err := comm.CompressionDialOptions(orderer.compression)
+	if err != nil {
+		return nil, err
+	}
+	grpcOpts = append(grpcOpts, compressionOpts...)
+
+	grpcOpts = append(grpcOpts, comm.RetryDialOptions(orderer.retryPolicy)...)
+
 	if endpoint.AttemptSecured(orderer.url, orderer.allowInsecure) {
 		//tls config
 		tlsConfig, err := comm.TLSConfig(orderer.tlsCACert, orderer.serverName, config)
@@ -80,7 +114,10 @@ func New(config fab.EndpointConfig, opts ...Option) (*Orderer, error) {
 			return nil, err
 		}
 		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
-			return verifier.VerifyPeerCertificate(rawCerts, verifiedChains)
+			if err := verifier.VerifyPeerCertificate(rawCerts, verifiedChains); err != nil {
+				return err
+			}
+			return comm.VerifyCertificatePins(rawCerts, verifiedChains, orderer.certPins)
 		}
 
 		grpcOpts = append(grpcOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
@@ -88,8 +125,8 @@ func New(config fab.EndpointConfig, opts ...Option) (*Orderer, error) {
 		grpcOpts = append(grpcOpts, grpc.WithInsecure())
 	}
 
-	grpcOpts = append(grpcOpts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxCallRecvMsgSize),
-		grpc.MaxCallSendMsgSize(maxCallSendMsgSize)))
+	grpcOpts = append(grpcOpts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(orderer.maxRecvMsgSize),
+		grpc.MaxCallSendMsgSize(orderer.maxSendMsgSize)))
 
 	orderer.dialTimeout = config.Timeout(fab.OrdererConnection)
 	orderer.url = endpoint.ToAddress(orderer.url)
@@ -134,6 +171,80 @@ func WithInsecure() Option {
 	}
 }
 
+// WithProxy is a functional option for the orderer.New constructor that configures the proxy used to connect to the orderer
+func WithProxy(proxy endpoint.ProxyConfig) Option {
+	return func(o *Orderer) error {
+		o.proxy = proxy
+
+		return nil
+	}
+}
+
+// WithCompression is a functional option for the orderer.New constructor that configures the name of the
+// compressor (e.g. "gzip") used for messages sent to the orderer
+func WithCompression(compression string) Option {
+	return func(o *Orderer) error {
+		o.compression = compression
+
+		return nil
+	}
+}
+
+// WithRetryPolicy is a functional option for the orderer.New constructor that configures the transport-level
+// retry policy applied to unary calls to the orderer
+func WithRetryPolicy(policy comm.RetryPolicy) Option {
+	return func(o *Orderer) error {
+		o.retryPolicy = policy
+
+		return nil
+	}
+}
+
+// WithBroadcastPolicy is a functional option for the orderer.New constructor that configures the per-attempt
+// timeout and the retries of broadcasts to the orderer
+func WithBroadcastPolicy(policy BroadcastPolicy) Option {
+	return func(o *Orderer) error {
+		o.bcastPolicy = policy
+
+		return nil
+	}
+}
+
+// WithDialer is a functional option for the orderer.New constructor that configures the name of the dialer
+// (registered with fabsdk.WithDialer) used to connect to the orderer
+func WithDialer(dialer string) Option {
+	return func(o *Orderer) error {
+		o.dialer = dialer
+
+		return nil
+	}
+}
+
+// WithCertificatePins is a functional option for the orderer.New constructor that configures the SHA-256
+// fingerprints of which at least one must match a TLS certificate presented by the orderer
+func WithCertificatePins(pins []string) Option {
+	return func(o *Orderer) error {
+		o.certPins = pins
+
+		return nil
+	}
+}
+
+// WithMaxMsgSize is a functional option for the orderer.New constructor that configures the maximum size
+// (in bytes) of messages received from and sent to the orderer. A size <= 0 keeps the default.
+func WithMaxMsgSize(recv, send int) Option {
+	return func(o *Orderer) error {
+		if recv > 0 {
+			o.maxRecvMsgSize = recv
+		}
+		if send > 0 {
+			o.maxSendMsgSize = send
+		}
+
+		return nil
+	}
+}
+
 // FromOrdererConfig is a functional option for the orderer.New constructor that configures a new orderer
 // from a apiconfig.OrdererConfig struct
 func FromOrdererConfig(ordererCfg *fab.OrdererConfig) Option {
@@ -165,6 +276,18 @@ func FromOrdererConfig(ordererCfg *fab.OrdererConfig) Option {
 		o.kap = getKeepAliveOptions(ordererCfg)
 		o.failFast = getFailFast(ordererCfg)
 		o.allowInsecure = isInsecureConnectionAllowed(ordererCfg)
+		o.proxy = getProxyConfig(ordererCfg)
+		o.compression = getCompression(ordererCfg)
+		o.dialer = getDialer(ordererCfg)
+		o.certPins = ordererCfg.TLSCertPins
+		o.maxRecvMsgSize = getMaxRecvMsgSize(ordererCfg)
+		o.maxSendMsgSize = getMaxSendMsgSize(ordererCfg)
+
+		o.retryPolicy, err = comm.RetryPolicyFromOptions(ordererCfg.GRPCOptions)
+		if err != nil {
+			return err
+		}
+		o.bcastPolicy = BroadcastPolicyFromOptions(ordererCfg.GRPCOptions)
 
 		return nil
 	}
@@ -201,18 +324,50 @@ func getFailFast(ordererCfg *fab.OrdererConfig) bool {
 }
 
 func getKeepAliveOptions(ordererCfg *fab.OrdererConfig) keepalive.ClientParameters {
+	return comm.KeepAliveParams(ordererCfg.GRPCOptions)
+}
+
+func getProxyConfig(ordererCfg *fab.OrdererConfig) endpoint.ProxyConfig {
+	var proxy endpoint.ProxyConfig
+	if proxyURL, ok := ordererCfg.GRPCOptions["proxy-url"].(string); ok {
+		proxy.URL = proxyURL
+	}
+	if noProxy, ok := ordererCfg.GRPCOptions["no-proxy"].(string); ok {
+		proxy.NoProxy = noProxy
+	}
+	return proxy
+}
+
+func getCompression(ordererCfg *fab.OrdererConfig) string {
+	if compression, ok := ordererCfg.GRPCOptions["compression"].(string); ok {
+		return compression
+	}
+	return ""
+}
 
-	var kap keepalive.ClientParameters
-	if kaTime, ok := ordererCfg.GRPCOptions["keep-alive-time"].(time.Duration); ok {
-		kap.Time = cast.ToDuration(kaTime)
+func getDialer(ordererCfg *fab.OrdererConfig) string {
+	if dialer, ok := ordererCfg.GRPCOptions["dialer"].(string); ok {
+		return dialer
 	}
-	if kaTimeout, ok := ordererCfg.GRPCOptions["keep-alive-timeout"].(time.Duration); ok {
-		kap.Timeout = cast.ToDuration(kaTimeout)
+	return ""
+}
+
+func getMaxRecvMsgSize(ordererCfg *fab.OrdererConfig) int {
+	if size, ok := ordererCfg.GRPCOptions["max-recv-msg-size"]; ok {
+		if size := cast.ToInt(size); size > 0 {
+			return size
+		}
 	}
-	if kaPermit, ok := ordererCfg.GRPCOptions["keep-alive-permit"].(time.Duration); ok {
-		kap.PermitWithoutStream = cast.ToBool(kaPermit)
+	return maxCallRecvMsgSize
+}
+
+func getMaxSendMsgSize(ordererCfg *fab.OrdererConfig) int {
+	if size, ok := ordererCfg.GRPCOptions["max-send-msg-size"]; ok {
+		if size := cast.ToInt(size); size > 0 {
+			return size
+		}
 	}
-	return kap
+	return maxCallSendMsgSize
 }
 
 func isInsecureConnectionAllowed(ordererCfg *fab.OrdererConfig) bool {
@@ -252,7 +407,38 @@ func (o *Orderer) URL() string {
 }
 
 // SendBroadcast Send the created transaction to Orderer.
+// Each attempt is bounded by the attempt timeout of the broadcast policy and attempts which fail
+// with a transient error are retried with exponential backoff until the max elapsed time.
 func (o *Orderer) SendBroadcast(ctx reqContext.Context, envelope *fab.SignedEnvelope) (*common.Status, error) {
+	begin := time.Now()
+	backoff := o.bcastPolicy.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		broadcastStatus, err := o.sendBroadcastAttempt(ctx, envelope)
+		if err == nil || ctx.Err() != nil || !isRetryableBroadcastError(err) {
+			return broadcastStatus, err
+		}
+		if time.Since(begin)+backoff >= o.bcastPolicy.MaxElapsedTime {
+			if attempt > 1 {
+				logger.Debugf("Giving up broadcast to orderer [%s] after %d attempts", o.url, attempt)
+			}
+			return nil, err
+		}
+
+		logger.Debugf("Retrying broadcast to orderer [%s] in %s (attempt %d): %s", o.url, backoff, attempt+1, err)
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, err
+		}
+		backoff = o.bcastPolicy.nextBackoff(backoff)
+	}
+}
+
+func (o *Orderer) sendBroadcastAttempt(ctx reqContext.Context, envelope *fab.SignedEnvelope) (*common.Status, error) {
+	ctx, cancel := o.bcastPolicy.attemptContext(ctx)
+	defer cancel()
+
 	conn, err := o.conn(ctx)
 	if err != nil {
 		rpcStatus, ok := grpcstatus.FromError(err)
@@ -301,11 +487,7 @@ func broadcastStream(broadcastClient ab.AtomicBroadcast_BroadcastClient, respons
 
 	broadcastResponse, err := broadcastClient.Recv()
 	if err != nil {
-		rpcStatus, ok := grpcstatus.FromError(err)
-		if ok {
-			err = status.NewFromGRPCStatus(rpcStatus)
-		}
-		errs <- errors.Wrap(err, "broadcast recv failed")
+		errs <- errors.Wrap(status.FromStreamError(err, broadcastClient), "broadcast recv failed")
 		return
 	}
 
@@ -377,7 +559,7 @@ func blockStream(deliverClient ab.AtomicBroadcast_DeliverClient, responses chan
 	for {
 		response, err := deliverClient.Recv()
 		if err != nil {
-			errs <- errors.Wrap(err, "recv from ordering service failed")
+			errs <- errors.Wrap(status.FromStreamError(err, deliverClient), "recv from ordering service failed")
 			return
 		}
 		// Assert response type
@@ -378,6 +378,29 @@ func TestGetKeepAliveOptions(t *testing.T) {
 
 }
 
+func TestGetMaxMsgSize(t *testing.T) {
+	ordererConfig := &fab.OrdererConfig{
+		GRPCOptions: make(map[string]interface{}),
+	}
+	assert.EqualValues(t, maxCallRecvMsgSize, getMaxRecvMsgSize(ordererConfig))
+	assert.EqualValues(t, maxCallSendMsgSize, getMaxSendMsgSize(ordererConfig))
+
+	ordererConfig.GRPCOptions["max-recv-msg-size"] = 200 * 1024 * 1024
+	ordererConfig.GRPCOptions["max-send-msg-size"] = "1048576"
+	assert.EqualValues(t, 200*1024*1024, getMaxRecvMsgSize(ordererConfig))
+	assert.EqualValues(t, 1024*1024, getMaxSendMsgSize(ordererConfig))
+
+	ordererConfig.GRPCOptions["max-recv-msg-size"] = 0
+	ordererConfig.GRPCOptions["max-send-msg-size"] = "invalid"
+	assert.EqualValues(t, maxCallRecvMsgSize, getMaxRecvMsgSize(ordererConfig))
+	assert.EqualValues(t, maxCallSendMsgSize, getMaxSendMsgSize(ordererConfig))
+
+	o, err := New(mocks.NewMockEndpointConfig(), WithURL("grpc://"+testOrdererURL), WithInsecure(), WithMaxMsgSize(0, -1))
+	assert.Nil(t, err)
+	assert.EqualValues(t, maxCallRecvMsgSize, o.maxRecvMsgSize)
+	assert.EqualValues(t, maxCallSendMsgSize, o.maxSendMsgSize)
+}
+
 func TestFailFast(t *testing.T) {
 	grpcOpts := make(map[string]interface{})
 	ordererConfig := &fab.OrdererConfig{
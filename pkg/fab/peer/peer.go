@@ -11,6 +11,7 @@ import (
 
 	"crypto/x509"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cast"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/keepalive"
@@ -19,6 +20,8 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
 )
 
 var logger = logging.NewLogger("fabsdk/fab")
@@ -26,16 +29,23 @@ var logger = logging.NewLogger("fabsdk/fab")
 // Peer represents a node in the target blockchain network to which
 // HFC sends endorsement proposals, transaction ordering or query requests.
 type Peer struct {
-	config      fab.EndpointConfig
-	certificate *x509.Certificate
-	serverName  string
-	processor   fab.ProposalProcessor
-	mspID       string
-	url         string
-	kap         keepalive.ClientParameters
-	failFast    bool
-	inSecure    bool
-	commManager fab.CommManager
+	config         fab.EndpointConfig
+	certificate    *x509.Certificate
+	serverName     string
+	processor      fab.ProposalProcessor
+	mspID          string
+	url            string
+	kap            keepalive.ClientParameters
+	failFast       bool
+	inSecure       bool
+	proxy          endpoint.ProxyConfig
+	compression    string
+	retryPolicy    comm.RetryPolicy
+	dialer         string
+	certPins       []string
+	maxRecvMsgSize int
+	maxSendMsgSize int
+	commManager    fab.CommManager
 }
 
 // Option describes a functional parameter for the New constructor
@@ -44,8 +54,10 @@ type Option func(*Peer) error
 // New Returns a new Peer instance
 func New(config fab.EndpointConfig, opts ...Option) (*Peer, error) {
 	peer := &Peer{
-		config:      config,
-		commManager: &defCommManager{},
+		config:         config,
+		commManager:    &defCommManager{},
+		maxRecvMsgSize: maxCallRecvMsgSize,
+		maxSendMsgSize: maxCallSendMsgSize,
 	}
 
 	for _, opt := range opts {
@@ -66,6 +78,13 @@ func New(config fab.EndpointConfig, opts ...Option) (*Peer, error) {
 			kap:                peer.kap,
 			failFast:           peer.failFast,
 			allowInsecure:      peer.inSecure,
+			proxy:              peer.proxy,
+			compression:        peer.compression,
+			retryPolicy:        peer.retryPolicy,
+			dialer:             peer.dialer,
+			certPins:           peer.certPins,
+			maxRecvMsgSize:     peer.maxRecvMsgSize,
+			maxSendMsgSize:     peer.maxSendMsgSize,
 			commManager:        peer.commManager,
 		}
 		processor, err := newPeerEndorser(&endorseRequest)
@@ -115,6 +134,66 @@ func WithInsecure() Option {
 	}
 }
 
+// WithProxy is a functional option for the peer.New constructor that configures the proxy used to connect to the peer
+func WithProxy(proxy endpoint.ProxyConfig) Option {
+	return func(p *Peer) error {
+		p.proxy = proxy
+
+		return nil
+	}
+}
+
+// WithCompression is a functional option for the peer.New constructor that configures the name of the
+// compressor (e.g. "gzip") used for messages sent to the peer
+func WithCompression(compression string) Option {
+	return func(p *Peer) error {
+		p.compression = compression
+
+		return nil
+	}
+}
+
+// WithRetryPolicy is a functional option for the peer.New constructor that configures the transport-level
+// retry policy applied to calls to the peer
+func WithRetryPolicy(policy comm.RetryPolicy) Option {
+	return func(p *Peer) error {
+		p.retryPolicy = policy
+
+		return nil
+	}
+}
+
+// WithDialer is a functional option for the peer.New constructor that configures the name of the dialer
+// (registered with fabsdk.WithDialer) used to connect to the peer
+func WithDialer(dialer string) Option {
+	return func(p *Peer) error {
+		p.dialer = dialer
+
+		return nil
+	}
+}
+
+// WithCertificatePins is a functional option for the peer.New constructor that configures the SHA-256
+// fingerprints of which at least one must match a TLS certificate presented by the peer
+func WithCertificatePins(pins []string) Option {
+	return func(p *Peer) error {
+		p.certPins = pins
+
+		return nil
+	}
+}
+
+// WithMaxMsgSize is a functional option for the peer.New constructor that configures the maximum size
+// (in bytes) of messages received from and sent to the peer
+func WithMaxMsgSize(recv, send int) Option {
+	return func(p *Peer) error {
+		p.maxRecvMsgSize = recv
+		p.maxSendMsgSize = send
+
+		return nil
+	}
+}
+
 // WithMSPID is a functional option for the peer.New constructor that configures the peer's msp ID
 func WithMSPID(mspID string) Option {
 	return func(p *Peer) error {
@@ -155,6 +234,17 @@ func FromPeerConfig(peerCfg *fab.NetworkPeer) Option {
 		p.mspID = peerCfg.MSPID
 		p.kap = getKeepAliveOptions(peerCfg)
 		p.failFast = getFailFast(peerCfg)
+		p.proxy = getProxyConfig(peerCfg)
+		p.compression = getCompression(peerCfg)
+		p.dialer = getDialer(peerCfg)
+		p.certPins = peerCfg.TLSCertPins
+		p.maxRecvMsgSize = getMaxRecvMsgSize(peerCfg)
+		p.maxSendMsgSize = getMaxSendMsgSize(peerCfg)
+
+		p.retryPolicy, err = comm.RetryPolicyFromOptions(peerCfg.GRPCOptions)
+		if err != nil {
+			return err
+		}
 		return nil
 	}
 }
@@ -178,18 +268,50 @@ func getFailFast(peerCfg *fab.NetworkPeer) bool {
 }
 
 func getKeepAliveOptions(peerCfg *fab.NetworkPeer) keepalive.ClientParameters {
+	return comm.KeepAliveParams(peerCfg.GRPCOptions)
+}
+
+func getProxyConfig(peerCfg *fab.NetworkPeer) endpoint.ProxyConfig {
+	var proxy endpoint.ProxyConfig
+	if proxyURL, ok := peerCfg.GRPCOptions["proxy-url"].(string); ok {
+		proxy.URL = proxyURL
+	}
+	if noProxy, ok := peerCfg.GRPCOptions["no-proxy"].(string); ok {
+		proxy.NoProxy = noProxy
+	}
+	return proxy
+}
+
+func getCompression(peerCfg *fab.NetworkPeer) string {
+	if compression, ok := peerCfg.GRPCOptions["compression"].(string); ok {
+		return compression
+	}
+	return ""
+}
 
-	var kap keepalive.ClientParameters
-	if kaTime, ok := peerCfg.GRPCOptions["keep-alive-time"]; ok {
-		kap.Time = cast.ToDuration(kaTime)
+func getDialer(peerCfg *fab.NetworkPeer) string {
+	if dialer, ok := peerCfg.GRPCOptions["dialer"].(string); ok {
+		return dialer
 	}
-	if kaTimeout, ok := peerCfg.GRPCOptions["keep-alive-timeout"]; ok {
-		kap.Timeout = cast.ToDuration(kaTimeout)
+	return ""
+}
+
+func getMaxRecvMsgSize(peerCfg *fab.NetworkPeer) int {
+	if size, ok := peerCfg.GRPCOptions["max-recv-msg-size"]; ok {
+		if size := cast.ToInt(size); size > 0 {
+			return size
+		}
 	}
-	if kaPermit, ok := peerCfg.GRPCOptions["keep-alive-permit"]; ok {
-		kap.PermitWithoutStream = cast.ToBool(kaPermit)
+	return maxCallRecvMsgSize
+}
+
+func getMaxSendMsgSize(peerCfg *fab.NetworkPeer) int {
+	if size, ok := peerCfg.GRPCOptions["max-send-msg-size"]; ok {
+		if size := cast.ToInt(size); size > 0 {
+			return size
+		}
 	}
-	return kap
+	return maxCallSendMsgSize
 }
 
 func isInsecureConnectionAllowed(peerCfg *fab.NetworkPeer) bool {
@@ -209,6 +331,16 @@ func WithPeerProcessor(processor fab.ProposalProcessor) Option {
 	}
 }
 
+// WithCommManager is a functional option for the peer.New constructor that configures the comm manager
+// which is used to connect to the peer if the request context doesn't provide one
+func WithCommManager(commManager fab.CommManager) Option {
+	return func(p *Peer) error {
+		p.commManager = commManager
+
+		return nil
+	}
+}
+
 // MSPID gets the Peer mspID.
 func (p *Peer) MSPID() string {
 	return p.mspID
@@ -222,9 +354,27 @@ func (p *Peer) URL() string {
 
 // ProcessTransactionProposal sends the created proposal to peer for endorsement.
 func (p *Peer) ProcessTransactionProposal(ctx reqContext.Context, proposal fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
+	done := peerStats.start(p.url)
+	defer done()
+
 	return p.processor.ProcessTransactionProposal(ctx, proposal)
 }
 
+// Warm opens a connection to the peer and, if the comm manager supports it, keeps the connection open
+// even when it's idle. Requests can then be sent to the peer (e.g. a hot-standby peer) without the
+// latency of establishing a connection.
+func (p *Peer) Warm(ctx reqContext.Context) error {
+	w, ok := p.processor.(warmer)
+	if !ok {
+		return errors.Errorf("proposal processor of peer [%s] doesn't support warm connections", p.url)
+	}
+	return w.warm(ctx)
+}
+
+type warmer interface {
+	warm(ctx reqContext.Context) error
+}
+
 func (p *Peer) String() string {
 	return p.url
 }
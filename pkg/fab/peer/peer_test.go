@@ -222,6 +222,34 @@ func TestPeerOptions(t *testing.T) {
 	}
 }
 
+// TestMaxMsgSize validates that unset, non-positive and unparsable message sizes fall back to the defaults
+func TestMaxMsgSize(t *testing.T) {
+	networkPeer := &fab.NetworkPeer{
+		PeerConfig: fab.PeerConfig{GRPCOptions: make(map[string]interface{})},
+	}
+	assertMsgSizes(t, maxCallRecvMsgSize, maxCallSendMsgSize, getMaxRecvMsgSize(networkPeer), getMaxSendMsgSize(networkPeer))
+
+	networkPeer.GRPCOptions["max-recv-msg-size"] = 200 * 1024 * 1024
+	networkPeer.GRPCOptions["max-send-msg-size"] = "1048576"
+	assertMsgSizes(t, 200*1024*1024, 1024*1024, getMaxRecvMsgSize(networkPeer), getMaxSendMsgSize(networkPeer))
+
+	networkPeer.GRPCOptions["max-recv-msg-size"] = 0
+	networkPeer.GRPCOptions["max-send-msg-size"] = "invalid"
+	assertMsgSizes(t, maxCallRecvMsgSize, maxCallSendMsgSize, getMaxRecvMsgSize(networkPeer), getMaxSendMsgSize(networkPeer))
+
+	endorseReq := &peerEndorserRequest{maxRecvMsgSize: -1}
+	assertMsgSizes(t, maxCallRecvMsgSize, maxCallSendMsgSize, endorseReq.recvMsgSize(), endorseReq.sendMsgSize())
+}
+
+func assertMsgSizes(t *testing.T, expectedRecv, expectedSend, recv, send int) {
+	if recv != expectedRecv {
+		t.Fatalf("Expected max recv message size %d but got %d", expectedRecv, recv)
+	}
+	if send != expectedSend {
+		t.Fatalf("Expected max send message size %d but got %d", expectedSend, send)
+	}
+}
+
 // TestNewPeerSecured validates that insecure option
 func TestNewPeerSecured(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
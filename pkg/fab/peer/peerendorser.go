@@ -18,6 +18,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
 	grpcstatus "google.golang.org/grpc/status"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/verifier"
@@ -39,6 +40,7 @@ const (
 // peerEndorser enables access to a GRPC-based endorser for running transaction proposal simulations
 type peerEndorser struct {
 	grpcDialOption []grpc.DialOption
+	callOptions    []grpc.CallOption
 	target         string
 	dialTimeout    time.Duration
 	commManager    fab.CommManager
@@ -52,6 +54,13 @@ type peerEndorserRequest struct {
 	kap                keepalive.ClientParameters
 	failFast           bool
 	allowInsecure      bool
+	proxy              endpoint.ProxyConfig
+	compression        string
+	dialer             string
+	certPins           []string
+	maxRecvMsgSize     int
+	maxSendMsgSize     int
+	retryPolicy        comm.RetryPolicy
 	commManager        fab.CommManager
 }
 
@@ -67,6 +76,29 @@ func newPeerEndorser(endorseReq *peerEndorserRequest) (*peerEndorser, error) {
 	}
 	grpcOpts = append(grpcOpts, grpc.WithDefaultCallOptions(grpc.FailFast(endorseReq.failFast)))
 
+	// A custom dialer takes precedence over the proxy
+	dialerOpt, err := comm.DialerDialOption(endorseReq.config, endorseReq.dialer)
+	if err != nil {
+		return nil, err
+	}
+	if dialerOpt == nil {
+		dialerOpt, err = comm.ProxyDialOption(endorseReq.target, endorseReq.proxy)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if dialerOpt != nil {
+		grpcOpts = append(grpcOpts, dialerOpt)
+	}
+
+	compressionOpts, err := comm.CompressionDialOptions(endorseReq.compression)
+	if err != nil {
+		return nil, err
+	}
+	grpcOpts = append(grpcOpts, compressionOpts...)
+
+	grpcOpts = append(grpcOpts, comm.RetryDialOptions(endorseReq.retryPolicy)...)
+
 	if endpoint.AttemptSecured(endorseReq.target, endorseReq.allowInsecure) {
 		tlsConfig, err := comm.TLSConfig(endorseReq.certificate, endorseReq.serverHostOverride, endorseReq.config)
 		if err != nil {
@@ -74,20 +106,24 @@ func newPeerEndorser(endorseReq *peerEndorserRequest) (*peerEndorser, error) {
 		}
 		//verify if certificate was expired or not yet valid
 		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
-			return verifier.VerifyPeerCertificate(rawCerts, verifiedChains)
+			if err := verifier.VerifyPeerCertificate(rawCerts, verifiedChains); err != nil {
+				return err
+			}
+			return comm.VerifyCertificatePins(rawCerts, verifiedChains, endorseReq.certPins)
 		}
 		grpcOpts = append(grpcOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 	} else {
 		grpcOpts = append(grpcOpts, grpc.WithInsecure())
 	}
 
-	grpcOpts = append(grpcOpts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxCallRecvMsgSize),
-		grpc.MaxCallSendMsgSize(maxCallSendMsgSize)))
+	grpcOpts = append(grpcOpts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(endorseReq.recvMsgSize()),
+		grpc.MaxCallSendMsgSize(endorseReq.sendMsgSize())))
 
 	timeout := endorseReq.config.Timeout(fab.EndorserConnection)
 
 	pc := &peerEndorser{
 		grpcDialOption: grpcOpts,
+		callOptions:    endorserCallOptions(endorseReq),
 		target:         endpoint.ToAddress(endorseReq.target),
 		dialTimeout:    timeout,
 		commManager:    endorseReq.commManager,
@@ -96,6 +132,33 @@ func newPeerEndorser(endorseReq *peerEndorserRequest) (*peerEndorser, error) {
 	return pc, nil
 }
 
+// endorserCallOptions returns the options which are passed to each endorsement call. The connection
+// to a peer is shared with the deliver client (the comm manager caches connections by target), so
+// the default call options of the connection are those of whichever client dialed it first.
+func endorserCallOptions(endorseReq *peerEndorserRequest) []grpc.CallOption {
+	return []grpc.CallOption{
+		grpc.FailFast(endorseReq.failFast),
+		grpc.MaxCallRecvMsgSize(endorseReq.recvMsgSize()),
+		grpc.MaxCallSendMsgSize(endorseReq.sendMsgSize()),
+	}
+}
+
+// recvMsgSize returns the maximum size of a received message, falling back to the default if none was set
+func (endorseReq *peerEndorserRequest) recvMsgSize() int {
+	if endorseReq.maxRecvMsgSize <= 0 {
+		return maxCallRecvMsgSize
+	}
+	return endorseReq.maxRecvMsgSize
+}
+
+// sendMsgSize returns the maximum size of a sent message, falling back to the default if none was set
+func (endorseReq *peerEndorserRequest) sendMsgSize() int {
+	if endorseReq.maxSendMsgSize <= 0 {
+		return maxCallSendMsgSize
+	}
+	return endorseReq.maxSendMsgSize
+}
+
 // ProcessTransactionProposal sends the transaction proposal to a peer and returns the response.
 func (p *peerEndorser) ProcessTransactionProposal(ctx reqContext.Context, request fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
 	logger.Debugf("Processing proposal using endorser: %s", p.target)
@@ -136,6 +199,33 @@ func (p *peerEndorser) releaseConn(ctx reqContext.Context, conn *grpc.ClientConn
 	commManager.ReleaseConn(conn)
 }
 
+// connPinner is implemented by comm managers which can keep idle connections open
+type connPinner interface {
+	Pin(target string)
+}
+
+func (p *peerEndorser) warm(ctx reqContext.Context) error {
+	commManager, ok := context.RequestCommManager(ctx)
+	if !ok {
+		commManager = p.commManager
+	}
+
+	if pinner, ok := commManager.(connPinner); ok {
+		pinner.Pin(p.target)
+	} else {
+		logger.Debugf("Comm manager doesn't support pinning connections - connection to [%s] may be closed when idle", p.target)
+	}
+
+	conn, err := p.conn(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "connection failed")
+	}
+	p.releaseConn(ctx, conn)
+
+	logger.Debugf("Connection to [%s] is warm", p.target)
+	return nil
+}
+
 func (p *peerEndorser) sendProposal(ctx reqContext.Context, proposal fab.ProcessProposalRequest) (*pb.ProposalResponse, error) {
 	conn, err := p.conn(ctx)
 	if err != nil {
@@ -147,25 +237,30 @@ func (p *peerEndorser) sendProposal(ctx reqContext.Context, proposal fab.Process
 	}
 	defer p.releaseConn(ctx, conn)
 
+	// The header and trailer may carry details of the error supplied by the peer
+	var header, trailer metadata.MD
 	endorserClient := pb.NewEndorserClient(conn)
-	resp, err := endorserClient.ProcessProposal(ctx, proposal.SignedProposal)
+	callOpts := append([]grpc.CallOption{grpc.Header(&header), grpc.Trailer(&trailer)}, p.callOptions...)
+	resp, err := endorserClient.ProcessProposal(ctx, proposal.SignedProposal, callOpts...)
 
 	if err != nil {
 		logger.Errorf("process proposal failed [%s]", err)
 		rpcStatus, ok := grpcstatus.FromError(err)
 
 		if ok {
+			var s *status.Status
 			code, message, extractErr := extractChaincodeError(rpcStatus)
 			if extractErr != nil {
 				code, message1, extractErr := extractPrematureExecutionError(rpcStatus)
 				if extractErr != nil {
-					err = status.NewFromGRPCStatus(rpcStatus)
+					s = status.NewFromGRPCStatus(rpcStatus)
 				} else {
-					err = status.New(status.EndorserClientStatus, code, message1, nil)
+					s = status.New(status.EndorserClientStatus, code, message1, nil).WithGRPCStatus(rpcStatus)
 				}
 			} else {
-				err = status.NewFromExtractedChaincodeError(code, message)
+				s = status.NewFromExtractedChaincodeError(code, message).WithGRPCStatus(rpcStatus)
 			}
+			err = s.WithMetadata(header, trailer)
 		}
 	}
 	return resp, err
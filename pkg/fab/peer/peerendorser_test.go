@@ -20,6 +20,7 @@ import (
 	"google.golang.org/grpc"
 	grpcCodes "google.golang.org/grpc/codes"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
 	grpcstatus "google.golang.org/grpc/status"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
@@ -217,10 +218,13 @@ func startEndorserServer(t *testing.T, grpcServer *grpc.Server) (*mocks.MockEndo
 }
 
 func startEndorserServerWithError(t *testing.T, grpcServer *grpc.Server, testErr error) (*mocks.MockEndorserServer, string) {
+	return startMockEndorserServer(t, grpcServer, &mocks.MockEndorserServer{ProposalError: testErr})
+}
+
+func startMockEndorserServer(t *testing.T, grpcServer *grpc.Server, endorserServer *mocks.MockEndorserServer) (*mocks.MockEndorserServer, string) {
 	lis, err := net.Listen("tcp", testAddress)
 	addr := lis.Addr().String()
 
-	endorserServer := &mocks.MockEndorserServer{ProposalError: testErr}
 	pb.RegisterEndorserServer(grpcServer, endorserServer)
 	if err != nil {
 		t.Logf("Error starting test server %s", err)
@@ -285,3 +289,26 @@ func TestExtractPrematureExecError(t *testing.T) {
 	assert.EqualValues(t, int32(status.PrematureChaincodeExecution), code, "Expected premature execution error")
 	assert.EqualValues(t, "premature execution - chaincode (somecc:v1) launched and waiting for registration", message, "Invalid message")
 }
+
+func TestEndorserRPCErrorMetadata(t *testing.T) {
+	testErrorMessage := "RPC error condition"
+
+	grpcServer := grpc.NewServer()
+	defer grpcServer.Stop()
+	_, addr := startMockEndorserServer(t, grpcServer, &mocks.MockEndorserServer{
+		ProposalError: fmt.Errorf(testErrorMessage),
+		Trailer:       metadata.Pairs("retry-after", "5"),
+	})
+
+	_, err := testProcessProposal(t, "grpc://"+addr)
+	statusError, ok := status.FromError(err)
+	assert.True(t, ok, "Expected status error on failed connection")
+	assert.Equal(t, status.GRPCTransportStatus, statusError.Group)
+	assert.Equal(t, []string{"5"}, statusError.Metadata["retry-after"])
+
+	rpcStatus := statusError.GRPCStatus()
+	if assert.NotNil(t, rpcStatus) {
+		assert.Equal(t, grpcCodes.Unknown, rpcStatus.Code())
+		assert.Equal(t, testErrorMessage, rpcStatus.Message())
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+)
+
+// probeFunc measures the round-trip time to the given address
+type probeFunc func(address string, timeout time.Duration) (time.Duration, error)
+
+// Prober measures the round-trip time (RTT) to a set of peers in the background. The RTT is
+// recorded in the peer statistics (see GetStats) so that load-balancing strategies can prefer
+// nearby peers. A probe measures the time taken to establish a TCP connection to the peer,
+// which approximates the network round-trip time without involving the peer's TLS handshake.
+type Prober struct {
+	interval  time.Duration
+	timeout   time.Duration
+	probe     probeFunc
+	registry  *statsRegistry
+	lock      sync.RWMutex
+	addresses map[string]struct{}
+	started   bool
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewProber returns a prober which probes its peers at the given interval. Probes which don't
+// complete within the given timeout fail and aren't recorded.
+func NewProber(interval, timeout time.Duration) *Prober {
+	return &Prober{
+		interval:  interval,
+		timeout:   timeout,
+		probe:     probeTCP,
+		registry:  peerStats,
+		addresses: make(map[string]struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Add adds the peers with the given URLs to the set of probed peers. If the prober
+// has been started then new peers are probed immediately.
+func (p *Prober) Add(urls ...string) {
+	var added []string
+
+	p.lock.Lock()
+	for _, url := range urls {
+		address := endpoint.ToAddress(url)
+		if _, ok := p.addresses[address]; ok {
+			continue
+		}
+		p.addresses[address] = struct{}{}
+		added = append(added, address)
+	}
+	started := p.started
+	p.lock.Unlock()
+
+	if started && len(added) > 0 {
+		go p.probeAll(added)
+	}
+}
+
+// Start starts probing the peers in the background
+func (p *Prober) Start() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.started {
+		return
+	}
+	p.started = true
+
+	logger.Debugf("Starting RTT prober with interval %s", p.interval)
+	go p.run()
+}
+
+// Close stops probing the peers
+func (p *Prober) Close() {
+	p.closeOnce.Do(func() {
+		close(p.done)
+	})
+}
+
+func (p *Prober) run() {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.probeAll(p.snapshot())
+
+		select {
+		case <-ticker.C:
+		case <-p.done:
+			logger.Debugf("RTT prober stopped")
+			return
+		}
+	}
+}
+
+func (p *Prober) snapshot() []string {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	addresses := make([]string, 0, len(p.addresses))
+	for address := range p.addresses {
+		addresses = append(addresses, address)
+	}
+	return addresses
+}
+
+// probeAll probes the given addresses concurrently and waits for the probes to complete
+func (p *Prober) probeAll(addresses []string) {
+	var wg sync.WaitGroup
+	wg.Add(len(addresses))
+	for _, address := range addresses {
+		go func(address string) {
+			defer wg.Done()
+
+			rtt, err := p.probe(address, p.timeout)
+			if err != nil {
+				logger.Debugf("RTT probe of [%s] failed: %s", address, err)
+				return
+			}
+
+			logger.Debugf("RTT probe of [%s]: %s", address, rtt)
+			p.registry.recordRTT(address, rtt)
+		}(address)
+	}
+	wg.Wait()
+}
+
+func probeTCP(address string, timeout time.Duration) (time.Duration, error) {
+	begin := time.Now()
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return 0, err
+	}
+	rtt := time.Since(begin)
+
+	if err := conn.Close(); err != nil {
+		logger.Debugf("Error closing RTT probe connection to [%s]: %s", address, err)
+	}
+	return rtt, nil
+}
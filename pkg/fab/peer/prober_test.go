@@ -0,0 +1,72 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProber(t *testing.T) {
+	registry := &statsRegistry{stats: make(map[string]*Stats)}
+
+	var lock sync.Mutex
+	probed := make(map[string]int)
+
+	prober := NewProber(50*time.Millisecond, time.Second)
+	prober.registry = registry
+	prober.probe = func(address string, timeout time.Duration) (time.Duration, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		probed[address]++
+
+		if address == "peer3.example.com:7051" {
+			return 0, errors.New("unreachable")
+		}
+		return 10 * time.Millisecond, nil
+	}
+	defer prober.Close()
+
+	prober.Add("grpcs://peer1.example.com:7051", "peer1.example.com:7051", "peer3.example.com:7051")
+	prober.Start()
+	prober.Add("grpc://peer2.example.com:7051")
+
+	time.Sleep(200 * time.Millisecond)
+	prober.Close()
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Len(t, probed, 3)
+	assert.True(t, probed["peer1.example.com:7051"] > 1, "expected peer1 to be probed periodically")
+
+	stats := registry.get("peer1.example.com:7051")
+	assert.Equal(t, 10*time.Millisecond, stats.RTT)
+	assert.True(t, stats.Probes > 1)
+	assert.Equal(t, 10*time.Millisecond, registry.get("peer2.example.com:7051").RTT)
+	assert.Equal(t, Stats{}, registry.get("peer3.example.com:7051"))
+}
+
+func TestProbeTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	rtt, err := probeTCP(listener.Addr().String(), time.Second)
+	assert.NoError(t, err)
+	assert.True(t, rtt > 0)
+
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	_, err = probeTCP(address, time.Second)
+	assert.Error(t, err, "expected error probing closed listener")
+}
@@ -0,0 +1,122 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+)
+
+// latencyWeight is the weight of the latest sample in the moving average of the latency
+const latencyWeight = 0.2
+
+// Stats contains the proposal request statistics of a peer, which are used
+// by load-balancing strategies to choose among equivalent peers
+type Stats struct {
+	// Outstanding is the number of requests which have been sent to the peer and haven't completed
+	Outstanding int
+	// Latency is the moving average of the latency of the completed requests (zero if none have completed)
+	Latency time.Duration
+	// Requests is the number of completed requests
+	Requests uint64
+	// RTT is the moving average of the round-trip time measured by background probes (zero if the peer hasn't been probed)
+	RTT time.Duration
+	// Probes is the number of successful probes
+	Probes uint64
+}
+
+// peerStats holds the statistics of all peers of the process (by address) since
+// peers are created per request and equivalent peers are shared across channels
+var peerStats = &statsRegistry{stats: make(map[string]*Stats)}
+
+// GetStats returns the proposal request statistics of the peer with the given URL
+func GetStats(url string) Stats {
+	return peerStats.get(url)
+}
+
+// GetAllStats returns the statistics of all peers, keyed by peer address, which may be exposed as metrics
+func GetAllStats() map[string]Stats {
+	return peerStats.all()
+}
+
+type statsRegistry struct {
+	lock  sync.RWMutex
+	stats map[string]*Stats
+}
+
+func (r *statsRegistry) get(url string) Stats {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if s, ok := r.stats[endpoint.ToAddress(url)]; ok {
+		return *s
+	}
+	return Stats{}
+}
+
+func (r *statsRegistry) all() map[string]Stats {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	stats := make(map[string]Stats, len(r.stats))
+	for address, s := range r.stats {
+		stats[address] = *s
+	}
+	return stats
+}
+
+// getOrCreate returns the statistics of the given address. The caller must hold the write lock.
+func (r *statsRegistry) getOrCreate(address string) *Stats {
+	s, ok := r.stats[address]
+	if !ok {
+		s = &Stats{}
+		r.stats[address] = s
+	}
+	return s
+}
+
+// recordRTT records the round-trip time measured by a probe of the peer with the given URL
+func (r *statsRegistry) recordRTT(url string, rtt time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	s := r.getOrCreate(endpoint.ToAddress(url))
+	s.RTT = movingAverage(s.RTT, rtt, s.Probes)
+	s.Probes++
+}
+
+// start records the start of a request and returns a function which records its completion
+func (r *statsRegistry) start(url string) func() {
+	address := endpoint.ToAddress(url)
+	begin := time.Now()
+
+	r.lock.Lock()
+	s := r.getOrCreate(address)
+	s.Outstanding++
+	r.lock.Unlock()
+
+	return func() {
+		elapsed := time.Since(begin)
+
+		r.lock.Lock()
+		defer r.lock.Unlock()
+
+		s.Outstanding--
+		s.Latency = movingAverage(s.Latency, elapsed, s.Requests)
+		s.Requests++
+	}
+}
+
+// movingAverage adds the given sample to the moving average of the given number of samples
+func movingAverage(average, sample time.Duration, samples uint64) time.Duration {
+	if samples == 0 {
+		return sample
+	}
+	return time.Duration(latencyWeight*float64(sample) + (1-latencyWeight)*float64(average))
+}
@@ -0,0 +1,53 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	registry := &statsRegistry{stats: make(map[string]*Stats)}
+
+	assert.Equal(t, Stats{}, registry.get("grpcs://peer1.example.com:7051"))
+
+	done1 := registry.start("grpcs://peer1.example.com:7051")
+	done2 := registry.start("peer1.example.com:7051")
+
+	stats := registry.get("peer1.example.com:7051")
+	assert.Equal(t, 2, stats.Outstanding)
+	assert.EqualValues(t, 0, stats.Requests)
+
+	time.Sleep(10 * time.Millisecond)
+	done1()
+	done2()
+
+	stats = registry.get("grpcs://peer1.example.com:7051")
+	assert.Equal(t, 0, stats.Outstanding)
+	assert.EqualValues(t, 2, stats.Requests)
+	assert.True(t, stats.Latency >= 10*time.Millisecond, "expected latency of at least 10ms but got %s", stats.Latency)
+}
+
+func TestStatsRTT(t *testing.T) {
+	registry := &statsRegistry{stats: make(map[string]*Stats)}
+
+	registry.recordRTT("grpcs://peer1.example.com:7051", 10*time.Millisecond)
+	assert.Equal(t, 10*time.Millisecond, registry.get("peer1.example.com:7051").RTT)
+
+	registry.recordRTT("peer1.example.com:7051", 20*time.Millisecond)
+	stats := registry.get("peer1.example.com:7051")
+	assert.Equal(t, 12*time.Millisecond, stats.RTT)
+	assert.EqualValues(t, 2, stats.Probes)
+	assert.EqualValues(t, 0, stats.Requests)
+
+	all := registry.all()
+	assert.Len(t, all, 1)
+	assert.Equal(t, stats, all["peer1.example.com:7051"])
+}
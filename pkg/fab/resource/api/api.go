@@ -40,8 +40,11 @@ type InstallChaincodeRequest struct {
 	Path string
 	// chaincodeVersion: required - version of the chaincode
 	Version string
-	// required - package (chaincode package type and bytes)
+	// required unless a signed package is given - package (chaincode package type and bytes)
 	Package *CCPackage
+	// optional - signed chaincode package (envelope of type CHAINCODE_PACKAGE), which is installed
+	// in place of the package. Name, path and version must match the chaincode of the signed package.
+	SignedPackage *common.Envelope
 }
 
 // JoinChannelRequest allows a set of peers to transact on a channel on the network
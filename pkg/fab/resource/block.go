@@ -77,7 +77,7 @@ func retrieveBlock(reqCtx reqContext.Context, orderers []fab.Orderer, channel st
 		Data:   seekInfoBytes,
 	}
 
-	resp, err := retry.NewInvoker(retry.New(opts.retry)).Invoke(
+	resp, err := retry.NewInvoker(retry.New(opts.retry), retry.WithContext(reqCtx)).Invoke(
 		func() (interface{}, error) {
 			return txn.SendPayload(reqCtx, &payload, orderers)
 		},
@@ -85,7 +85,11 @@ func retrieveBlock(reqCtx reqContext.Context, orderers []fab.Orderer, channel st
 	if err != nil {
 		return nil, err
 	}
-	return resp.(*common.Block), err
+	block, ok := resp.(*common.Block)
+	if !ok || block == nil {
+		return nil, errors.New("no block received from orderer")
+	}
+	return block, nil
 }
 
 // newNewestSeekPosition returns a SeekPosition that requests the newest block
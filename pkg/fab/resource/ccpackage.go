@@ -0,0 +1,241 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resource
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/crypto"
+	fcutils "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// CreateSignedChaincodePackage creates a signed package of the given chaincode, i.e. an envelope of type
+// CHAINCODE_PACKAGE holding a SignedChaincodeDeploymentSpec, which is endorsed by the owner of the context.
+// The owner endorses the deployment spec along with the instantiation policy of the chaincode. Other owners
+// endorse the package with SignChaincodePackage before it's installed with the SignedPackage of the request.
+func CreateSignedChaincodePackage(ctx context.Client, req api.InstallChaincodeRequest, instantiationPolicy *common.SignaturePolicyEnvelope) (*common.Envelope, error) {
+	if err := checkInstallChaincodeRequest(req); err != nil {
+		return nil, err
+	}
+	if req.Package == nil {
+		return nil, errors.New("chaincode package is required")
+	}
+	if instantiationPolicy == nil {
+		return nil, errors.New("instantiation policy is required")
+	}
+
+	cds, err := newChaincodeDeploymentSpec(ChaincodeInstallRequest{
+		Name:    req.Name,
+		Path:    req.Path,
+		Version: req.Version,
+		Package: &ChaincodePackage{
+			Type: req.Package.Type,
+			Code: req.Package.Code,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	cdsBytes, err := proto.Marshal(cds)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of chaincode deployment spec failed")
+	}
+	policyBytes, err := proto.Marshal(instantiationPolicy)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of instantiation policy failed")
+	}
+
+	endorsement, err := endorseChaincodePackage(ctx, cdsBytes, policyBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return createChaincodePackageEnvelope(ctx, &pb.SignedChaincodeDeploymentSpec{
+		ChaincodeDeploymentSpec: cdsBytes,
+		InstantiationPolicy:     policyBytes,
+		OwnerEndorsements:       []*pb.Endorsement{endorsement},
+	})
+}
+
+// SignChaincodePackage adds the endorsement of the owner of the context to the given signed chaincode package
+// and returns the package signed by the owner
+func SignChaincodePackage(ctx context.Client, pkg *common.Envelope) (*common.Envelope, error) {
+	sdep, _, err := UnmarshalSignedChaincodePackage(pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	endorsement, err := endorseChaincodePackage(ctx, sdep.ChaincodeDeploymentSpec, sdep.InstantiationPolicy)
+	if err != nil {
+		return nil, err
+	}
+	sdep.OwnerEndorsements = append(sdep.OwnerEndorsements, endorsement)
+
+	return createChaincodePackageEnvelope(ctx, sdep)
+}
+
+// MergeSignedChaincodePackages merges the endorsements of copies of a signed chaincode package which were
+// endorsed by different owners. The packages must hold the same deployment spec and instantiation policy.
+// The envelope of the merged package isn't signed.
+func MergeSignedChaincodePackages(pkgs ...*common.Envelope) (*common.Envelope, error) {
+	if len(pkgs) == 0 {
+		return nil, errors.New("no chaincode packages to merge")
+	}
+
+	payload, err := protos_utils.ExtractPayload(pkgs[0])
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to extract payload of chaincode package")
+	}
+
+	var merged *pb.SignedChaincodeDeploymentSpec
+	for i, pkg := range pkgs {
+		sdep, _, err := UnmarshalSignedChaincodePackage(pkg)
+		if err != nil {
+			return nil, errors.WithMessage(err, "invalid chaincode package")
+		}
+		if merged == nil {
+			merged = sdep
+			continue
+		}
+		if !bytes.Equal(sdep.ChaincodeDeploymentSpec, merged.ChaincodeDeploymentSpec) {
+			return nil, errors.Errorf("chaincode deployment spec of package %d doesn't match", i)
+		}
+		if !bytes.Equal(sdep.InstantiationPolicy, merged.InstantiationPolicy) {
+			return nil, errors.Errorf("instantiation policy of package %d doesn't match", i)
+		}
+		merged.OwnerEndorsements = append(merged.OwnerEndorsements, sdep.OwnerEndorsements...)
+	}
+
+	payload.Data, err = proto.Marshal(merged)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of signed chaincode deployment spec failed")
+	}
+	payloadBytes, err := proto.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of chaincode package payload failed")
+	}
+	return &common.Envelope{Payload: payloadBytes}, nil
+}
+
+// VerifySignedChaincodePackage verifies that the given signed chaincode package is endorsed by at least one owner
+// and that the endorsements of the owners are valid, i.e. that the owners are members of the given channel and
+// that they signed the deployment spec and instantiation policy of the package. The deployment spec is returned
+// if the package is valid.
+func VerifySignedChaincodePackage(pkg *common.Envelope, membership fab.ChannelMembership) (*pb.ChaincodeDeploymentSpec, error) {
+	sdep, cds, err := UnmarshalSignedChaincodePackage(pkg)
+	if err != nil {
+		return nil, err
+	}
+	if len(sdep.OwnerEndorsements) == 0 {
+		return nil, errors.New("chaincode package has no owner endorsements")
+	}
+
+	for i, endorsement := range sdep.OwnerEndorsements {
+		if err := membership.Validate(endorsement.Endorser); err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("owner of endorsement %d is not valid", i))
+		}
+		msg := fcutils.ConcatenateBytes(sdep.ChaincodeDeploymentSpec, sdep.InstantiationPolicy, endorsement.Endorser)
+		if err := membership.Verify(endorsement.Endorser, msg, endorsement.Signature); err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("signature of endorsement %d is not valid", i))
+		}
+	}
+	return cds, nil
+}
+
+// UnmarshalSignedChaincodePackage returns the signed deployment spec held by the given signed chaincode
+// package and the deployment spec which it carries
+func UnmarshalSignedChaincodePackage(pkg *common.Envelope) (*pb.SignedChaincodeDeploymentSpec, *pb.ChaincodeDeploymentSpec, error) {
+	if pkg == nil {
+		return nil, nil, errors.New("chaincode package is required")
+	}
+
+	payload, err := protos_utils.ExtractPayload(pkg)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to extract payload of chaincode package")
+	}
+	if payload.Header == nil {
+		return nil, nil, errors.New("chaincode package has no header")
+	}
+	chdr, err := protos_utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to unmarshal channel header of chaincode package")
+	}
+	if common.HeaderType(chdr.Type) != common.HeaderType_CHAINCODE_PACKAGE {
+		return nil, nil, errors.Errorf("invalid chaincode package type: %s", common.HeaderType(chdr.Type))
+	}
+
+	sdep := &pb.SignedChaincodeDeploymentSpec{}
+	if err := proto.Unmarshal(payload.Data, sdep); err != nil {
+		return nil, nil, errors.Wrap(err, "unmarshal of signed chaincode deployment spec failed")
+	}
+	cds := &pb.ChaincodeDeploymentSpec{}
+	if err := proto.Unmarshal(sdep.ChaincodeDeploymentSpec, cds); err != nil {
+		return nil, nil, errors.Wrap(err, "unmarshal of chaincode deployment spec failed")
+	}
+	if cds.ChaincodeSpec == nil || cds.ChaincodeSpec.ChaincodeId == nil {
+		return nil, nil, errors.New("chaincode deployment spec has no chaincode ID")
+	}
+	return sdep, cds, nil
+}
+
+// endorseChaincodePackage returns the endorsement of the owner of the context over the deployment spec and
+// instantiation policy of a package
+func endorseChaincodePackage(ctx context.Client, cdsBytes, policyBytes []byte) (*pb.Endorsement, error) {
+	endorser, err := ctx.Serialize()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get user context's identity")
+	}
+
+	signature, err := ctx.SigningManager().Sign(fcutils.ConcatenateBytes(cdsBytes, policyBytes, endorser), ctx.PrivateKey())
+	if err != nil {
+		return nil, errors.WithMessage(err, "signing of chaincode package failed")
+	}
+	return &pb.Endorsement{Endorser: endorser, Signature: signature}, nil
+}
+
+// createChaincodePackageEnvelope returns the envelope of a signed chaincode package, signed by the owner of the context
+func createChaincodePackageEnvelope(ctx context.Client, sdep *pb.SignedChaincodeDeploymentSpec) (*common.Envelope, error) {
+	creator, err := ctx.Serialize()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get user context's identity")
+	}
+	nonce, err := crypto.GetRandomNonce()
+	if err != nil {
+		return nil, errors.WithMessage(err, "nonce creation failed")
+	}
+
+	data, err := proto.Marshal(sdep)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of signed chaincode deployment spec failed")
+	}
+	payloadBytes, err := proto.Marshal(&common.Payload{
+		Header: protos_utils.MakePayloadHeader(
+			protos_utils.MakeChannelHeader(common.HeaderType_CHAINCODE_PACKAGE, 0, "", 0),
+			&common.SignatureHeader{Creator: creator, Nonce: nonce},
+		),
+		Data: data,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of chaincode package payload failed")
+	}
+
+	signature, err := ctx.SigningManager().Sign(payloadBytes, ctx.PrivateKey())
+	if err != nil {
+		return nil, errors.WithMessage(err, "signing of chaincode package failed")
+	}
+	return &common.Envelope{Payload: payloadBytes, Signature: signature}, nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+func TestSignedChaincodePackage(t *testing.T) {
+	owner1 := setupContext()
+	owner2 := mocks.NewMockContext(mspmocks.NewMockSigningIdentity("owner2", "Org2MSP"))
+
+	req := api.InstallChaincodeRequest{Name: "examplecc", Path: "github.com/examplecc", Version: "1", Package: &api.CCPackage{Type: pb.ChaincodeSpec_GOLANG, Code: []byte("code")}}
+	policy := &common.SignaturePolicyEnvelope{Version: 1}
+
+	_, err := CreateSignedChaincodePackage(owner1, req, nil)
+	assert.Error(t, err, "expecting error without instantiation policy")
+	_, err = CreateSignedChaincodePackage(owner1, api.InstallChaincodeRequest{Name: "examplecc", Path: "github.com/examplecc", Version: "1"}, policy)
+	assert.Error(t, err, "expecting error without package")
+
+	pkg, err := CreateSignedChaincodePackage(owner1, req, policy)
+	require.NoError(t, err)
+	sdep, cds, err := UnmarshalSignedChaincodePackage(pkg)
+	require.NoError(t, err)
+	assert.Equal(t, "examplecc", cds.ChaincodeSpec.ChaincodeId.Name)
+	assert.Equal(t, []byte("code"), cds.CodePackage)
+	require.Len(t, sdep.OwnerEndorsements, 1)
+
+	pkg, err = SignChaincodePackage(owner2, pkg)
+	require.NoError(t, err)
+	sdep, _, err = UnmarshalSignedChaincodePackage(pkg)
+	require.NoError(t, err)
+	require.Len(t, sdep.OwnerEndorsements, 2)
+	owner2ID, err := owner2.Serialize()
+	require.NoError(t, err)
+	assert.Equal(t, owner2ID, sdep.OwnerEndorsements[1].Endorser)
+
+	membership := mocks.NewMockMembership()
+	_, err = VerifySignedChaincodePackage(pkg, membership)
+	require.NoError(t, err)
+
+	membership.VerifyErr = errors.New("invalid signature")
+	_, err = VerifySignedChaincodePackage(pkg, membership)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid signature")
+
+	membership = mocks.NewMockMembership()
+	membership.ValidateErr = errors.New("unknown MSP")
+	_, err = VerifySignedChaincodePackage(pkg, membership)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown MSP")
+
+	sdep.OwnerEndorsements = nil
+	_, err = VerifySignedChaincodePackage(newTestPackageEnvelope(t, common.HeaderType_CHAINCODE_PACKAGE, sdep), mocks.NewMockMembership())
+	assert.Error(t, err, "expecting error for package without endorsements")
+
+	_, _, err = UnmarshalSignedChaincodePackage(newTestPackageEnvelope(t, common.HeaderType_ENDORSER_TRANSACTION, sdep))
+	assert.Error(t, err, "expecting error for invalid header type")
+}
+
+func TestMergeSignedChaincodePackages(t *testing.T) {
+	owner1 := setupContext()
+	owner2 := mocks.NewMockContext(mspmocks.NewMockSigningIdentity("owner2", "Org2MSP"))
+
+	req := api.InstallChaincodeRequest{Name: "examplecc", Path: "github.com/examplecc", Version: "1", Package: &api.CCPackage{Type: pb.ChaincodeSpec_GOLANG, Code: []byte("code")}}
+	pkg, err := CreateSignedChaincodePackage(owner1, req, &common.SignaturePolicyEnvelope{Version: 1})
+	require.NoError(t, err)
+	pkg1, err := SignChaincodePackage(owner1, pkg)
+	require.NoError(t, err)
+	pkg2, err := SignChaincodePackage(owner2, pkg)
+	require.NoError(t, err)
+
+	merged, err := MergeSignedChaincodePackages(pkg1, pkg2)
+	require.NoError(t, err)
+	sdep, _, err := UnmarshalSignedChaincodePackage(merged)
+	require.NoError(t, err)
+	assert.Len(t, sdep.OwnerEndorsements, 4)
+
+	req.Version = "2"
+	otherPkg, err := CreateSignedChaincodePackage(owner2, req, &common.SignaturePolicyEnvelope{Version: 1})
+	require.NoError(t, err)
+	_, err = MergeSignedChaincodePackages(pkg1, otherPkg)
+	assert.Error(t, err, "expecting error for packages with different deployment specs")
+
+	_, err = MergeSignedChaincodePackages()
+	assert.Error(t, err, "expecting error without packages")
+}
+
+func TestCreateSignedChaincodeInstallProposal(t *testing.T) {
+	req := api.InstallChaincodeRequest{Name: "examplecc", Path: "github.com/examplecc", Version: "1", Package: &api.CCPackage{Type: pb.ChaincodeSpec_GOLANG, Code: []byte("code")}}
+	pkg, err := CreateSignedChaincodePackage(setupContext(), req, &common.SignaturePolicyEnvelope{Version: 1})
+	require.NoError(t, err)
+
+	cir, err := createInstallInvokeRequest(ChaincodeInstallRequest{Name: req.Name, Path: req.Path, Version: req.Version, SignedPackage: pkg})
+	require.NoError(t, err)
+	require.Len(t, cir.Args, 1)
+	installed := &common.Envelope{}
+	require.NoError(t, proto.Unmarshal(cir.Args[0], installed))
+	assert.True(t, proto.Equal(pkg, installed), "expecting signed package to be installed")
+}
+
+func newTestPackageEnvelope(t *testing.T, headerType common.HeaderType, sdep *pb.SignedChaincodeDeploymentSpec) *common.Envelope {
+	data, err := proto.Marshal(sdep)
+	require.NoError(t, err)
+	chdr, err := proto.Marshal(&common.ChannelHeader{Type: int32(headerType)})
+	require.NoError(t, err)
+	payload, err := proto.Marshal(&common.Payload{Header: &common.Header{ChannelHeader: chdr}, Data: data})
+	require.NoError(t, err)
+	return &common.Envelope{Payload: payload}
+}
@@ -106,13 +106,22 @@ func CreateConfigEnvelope(data []byte) (*common.ConfigEnvelope, error) {
 	return configEnvelope, nil
 }
 
-// GetLastConfigFromBlock returns the LastConfig data from the given block
+// GetLastConfigFromBlock returns the LastConfig data from the given block. Newer (e.g. BFT) orderers store the
+// LastConfig in the signatures metadata of the block, in which case the LAST_CONFIG metadata may be empty.
 func GetLastConfigFromBlock(block *common.Block) (*common.LastConfig, error) {
 	if block.Metadata == nil {
 		return nil, errors.New("block metadata is nil")
 	}
+
+	ordererMetadata, err := getOrdererBlockMetadata(block)
+	if err != nil {
+		logger.Debugf("Unable to get orderer metadata from block - using LAST_CONFIG metadata: %s", err)
+	} else if ordererMetadata.LastConfig != nil {
+		return ordererMetadata.LastConfig, nil
+	}
+
 	metadata := &common.Metadata{}
-	err := proto.Unmarshal(block.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG], metadata)
+	err = proto.Unmarshal(block.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG], metadata)
 	if err != nil {
 		return nil, errors.Wrap(err, "unmarshal block metadata failed")
 	}
@@ -125,3 +134,46 @@ func GetLastConfigFromBlock(block *common.Block) (*common.LastConfig, error) {
 
 	return lastConfig, err
 }
+
+// GetConsenterMetadataFromBlock returns the consensus-specific metadata (e.g. the SmartBFT view metadata)
+// which the orderers stored in the given block. Nil is returned if the block has no consenter metadata.
+func GetConsenterMetadataFromBlock(block *common.Block) ([]byte, error) {
+	if block.Metadata == nil {
+		return nil, errors.New("block metadata is nil")
+	}
+
+	ordererMetadata, err := getOrdererBlockMetadata(block)
+	if err != nil {
+		return nil, err
+	}
+	return ordererMetadata.ConsenterMetadata, nil
+}
+
+// ordererBlockMetadata is the metadata which newer orderers store in the value of the signatures metadata of a block
+type ordererBlockMetadata struct {
+	LastConfig        *common.LastConfig `protobuf:"bytes,1,opt,name=last_config,json=lastConfig" json:"last_config,omitempty"`
+	ConsenterMetadata []byte             `protobuf:"bytes,2,opt,name=consenter_metadata,json=consenterMetadata,proto3" json:"consenter_metadata,omitempty"`
+}
+
+func (m *ordererBlockMetadata) Reset()         { *m = ordererBlockMetadata{} }
+func (m *ordererBlockMetadata) String() string { return proto.CompactTextString(m) }
+func (*ordererBlockMetadata) ProtoMessage()    {}
+
+func getOrdererBlockMetadata(block *common.Block) (*ordererBlockMetadata, error) {
+	ordererMetadata := &ordererBlockMetadata{}
+	if len(block.Metadata.Metadata) <= int(common.BlockMetadataIndex_SIGNATURES) {
+		return ordererMetadata, nil
+	}
+
+	metadata := &common.Metadata{}
+	err := proto.Unmarshal(block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES], metadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal signatures metadata failed")
+	}
+
+	err = proto.Unmarshal(metadata.Value, ordererMetadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal orderer metadata from signatures metadata failed")
+	}
+	return ordererMetadata, nil
+}
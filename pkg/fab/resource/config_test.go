@@ -11,7 +11,12 @@ import (
 	"path"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"github.com/hyperledger/fabric-sdk-go/test/metadata"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 )
 
 func TestExtractChannelConfig(t *testing.T) {
@@ -39,3 +44,35 @@ func TestCreateConfigSignature(t *testing.T) {
 		t.Fatalf("Expected 'channel configuration required %v", err)
 	}
 }
+
+func TestGetLastConfigFromBlock(t *testing.T) {
+	marshal := func(msg proto.Message) []byte {
+		bytes, err := proto.Marshal(msg)
+		require.NoError(t, err)
+		return bytes
+	}
+
+	block := &common.Block{Metadata: &common.BlockMetadata{Metadata: make([][]byte, len(common.BlockMetadataIndex_name))}}
+	block.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG] = marshal(&common.Metadata{Value: marshal(&common.LastConfig{Index: 5})})
+
+	lastConfig, err := GetLastConfigFromBlock(block)
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, lastConfig.Index)
+
+	consenterMetadata, err := GetConsenterMetadataFromBlock(block)
+	require.NoError(t, err)
+	assert.Nil(t, consenterMetadata)
+
+	// BFT orderers store the last config in the signatures metadata
+	ordererMetadata := &ordererBlockMetadata{LastConfig: &common.LastConfig{Index: 7}, ConsenterMetadata: []byte("view")}
+	block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = marshal(&common.Metadata{Value: marshal(ordererMetadata)})
+	block.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG] = nil
+
+	lastConfig, err = GetLastConfigFromBlock(block)
+	require.NoError(t, err)
+	assert.EqualValues(t, 7, lastConfig.Index)
+
+	consenterMetadata, err = GetConsenterMetadataFromBlock(block)
+	require.NoError(t, err)
+	assert.Equal(t, "view", string(consenterMetadata))
+}
@@ -0,0 +1,27 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resource
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/tools/configtxlator/update"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+// ComputeConfigUpdate computes the config update of the channel which turns the original config into the
+// updated config, as the configtxlator compute_update command does. The read set of the update holds the
+// versions of the elements of the original config which the update depends on and its write set the
+// elements which are added or modified, with their versions incremented.
+func ComputeConfigUpdate(channelID string, original, updated *common.Config) (*common.ConfigUpdate, error) {
+	configUpdate, err := update.Compute(original, updated)
+	if err != nil {
+		return nil, errors.Wrap(err, "compute config update failed")
+	}
+	configUpdate.ChannelId = channelID
+	return configUpdate, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+func TestComputeConfigUpdate(t *testing.T) {
+	original := newTestConfig()
+
+	_, err := ComputeConfigUpdate("mychannel", original, proto.Clone(original).(*common.Config))
+	assert.Error(t, err, "expecting error without differences")
+	_, err = ComputeConfigUpdate("mychannel", original, &common.Config{})
+	assert.Error(t, err, "expecting error without channel group")
+
+	// Modified value
+	updated := proto.Clone(original).(*common.Config)
+	updated.ChannelGroup.Groups["Orderer"].Values["BatchSize"].Value = []byte("20")
+	configUpdate, err := ComputeConfigUpdate("mychannel", original, updated)
+	require.NoError(t, err)
+	assert.Equal(t, "mychannel", configUpdate.ChannelId)
+	orderer := configUpdate.WriteSet.Groups["Orderer"]
+	require.NotNil(t, orderer)
+	assert.Equal(t, uint64(2), orderer.Version, "group version shouldn't change when only a value is modified")
+	assert.Equal(t, uint64(4), orderer.Values["BatchSize"].Version)
+	assert.Equal(t, []byte("20"), orderer.Values["BatchSize"].Value)
+	assert.Empty(t, configUpdate.WriteSet.Groups["Application"], "unmodified group shouldn't be in the write set")
+
+	// Added group
+	updated = proto.Clone(original).(*common.Config)
+	updated.ChannelGroup.Groups["Application"].Groups["Org2MSP"] = &common.ConfigGroup{
+		ModPolicy: "Admins",
+		Values:    map[string]*common.ConfigValue{"MSP": {Value: []byte("org2"), ModPolicy: "Admins"}},
+	}
+	configUpdate, err = ComputeConfigUpdate("mychannel", original, updated)
+	require.NoError(t, err)
+	application := configUpdate.WriteSet.Groups["Application"]
+	require.NotNil(t, application)
+	assert.Equal(t, uint64(2), application.Version, "expecting version of group with new member to be incremented")
+	assert.Equal(t, uint64(0), application.Groups["Org2MSP"].Version)
+	assert.Equal(t, []byte("org2"), application.Groups["Org2MSP"].Values["MSP"].Value)
+	assert.Equal(t, uint64(3), application.Groups["Org1MSP"].Version, "expecting unmodified member in write set at its version")
+	assert.Equal(t, uint64(1), configUpdate.ReadSet.Groups["Application"].Version)
+}
+
+func newTestConfig() *common.Config {
+	return &common.Config{
+		ChannelGroup: &common.ConfigGroup{
+			Version:   0,
+			ModPolicy: "Admins",
+			Groups: map[string]*common.ConfigGroup{
+				"Orderer": {
+					Version:   2,
+					ModPolicy: "Admins",
+					Values: map[string]*common.ConfigValue{
+						"BatchSize": {Version: 3, ModPolicy: "Admins", Value: []byte("10")},
+					},
+				},
+				"Application": {
+					Version:   1,
+					ModPolicy: "Admins",
+					Groups: map[string]*common.ConfigGroup{
+						"Org1MSP": {Version: 3, ModPolicy: "Admins"},
+					},
+					Policies: map[string]*common.ConfigPolicy{
+						"Admins": {Version: 1, ModPolicy: "Admins", Policy: &common.Policy{Type: 1}},
+					},
+				},
+			},
+		},
+	}
+}
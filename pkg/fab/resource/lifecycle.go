@@ -0,0 +1,184 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resource
+
+import (
+	reqContext "context"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+)
+
+const (
+	lifecycleCC                       = "_lifecycle"
+	lifecycleInstall                  = "InstallChaincode"
+	lifecycleQueryInstalledChaincodes = "QueryInstalledChaincodes"
+)
+
+// LifecycleInstallChaincodeResponse is the response of a peer to a _lifecycle install proposal
+type LifecycleInstallChaincodeResponse struct {
+	*fab.TransactionProposalResponse
+	// PackageID is the ID of the installed package; it's empty if the install failed
+	PackageID string
+	// Label is the label of the installed package
+	Label string
+}
+
+// LifecycleInstalledChaincode is a chaincode package installed on a peer with the _lifecycle system chaincode
+type LifecycleInstalledChaincode struct {
+	PackageID string
+	Label     string
+}
+
+// LifecycleInstallChaincode sends a proposal to install the given chaincode package (see the lifecycle
+// ccpackager) with the _lifecycle system chaincode to one or more peers, which must support the new
+// chaincode lifecycle (Fabric 2.x).
+func LifecycleInstallChaincode(reqCtx reqContext.Context, installPackage []byte, targets []fab.ProposalProcessor, opts ...Opt) ([]*LifecycleInstallChaincodeResponse, fab.TransactionID, error) {
+	if len(installPackage) == 0 {
+		return nil, fab.EmptyTransactionID, errors.New("chaincode package is required")
+	}
+
+	args, err := proto.Marshal(&installChaincodeArgs{ChaincodeInstallPackage: installPackage})
+	if err != nil {
+		return nil, fab.EmptyTransactionID, errors.Wrap(err, "failed to marshal InstallChaincode arguments")
+	}
+
+	ctx, ok := contextImpl.RequestClientContext(reqCtx)
+	if !ok {
+		return nil, fab.EmptyTransactionID, errors.New("failed get client context from reqContext for txn header")
+	}
+
+	txh, err := txn.NewHeader(ctx, fab.SystemChannel)
+	if err != nil {
+		return nil, fab.EmptyTransactionID, errors.WithMessage(err, "create transaction ID failed")
+	}
+
+	prop, err := txn.CreateChaincodeInvokeProposal(txh, fab.ChaincodeInvokeRequest{
+		ChaincodeID: lifecycleCC,
+		Fcn:         lifecycleInstall,
+		Args:        [][]byte{args},
+	})
+	if err != nil {
+		return nil, fab.EmptyTransactionID, errors.WithMessage(err, "creation of install chaincode proposal failed")
+	}
+
+	optionsValue := getOpts(opts...)
+
+	resp, err := retry.NewInvoker(retry.New(optionsValue.retry), retry.WithContext(reqCtx)).Invoke(
+		func() (interface{}, error) {
+			return txn.SendProposal(reqCtx, prop, targets)
+		},
+	)
+	if err != nil {
+		return nil, prop.TxnID, err
+	}
+
+	var responses []*LifecycleInstallChaincodeResponse
+	for _, tpr := range resp.([]*fab.TransactionProposalResponse) {
+		response := &LifecycleInstallChaincodeResponse{TransactionProposalResponse: tpr}
+		if validateResponse(tpr) == nil {
+			result := &installChaincodeResult{}
+			if err := proto.Unmarshal(tpr.ProposalResponse.GetResponse().Payload, result); err != nil {
+				return nil, prop.TxnID, errors.Wrapf(err, "failed to unmarshal InstallChaincode result from %s", tpr.Endorser)
+			}
+			response.PackageID = result.PackageId
+			response.Label = result.Label
+		}
+		responses = append(responses, response)
+	}
+	return responses, prop.TxnID, nil
+}
+
+// LifecycleQueryInstalledChaincodes queries the chaincode packages installed on a peer with the _lifecycle
+// system chaincode
+func LifecycleQueryInstalledChaincodes(reqCtx reqContext.Context, peer fab.ProposalProcessor, opts ...Opt) ([]LifecycleInstalledChaincode, error) {
+	if peer == nil {
+		return nil, errors.New("peer required")
+	}
+
+	args, err := proto.Marshal(&queryInstalledChaincodesArgs{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal QueryInstalledChaincodes arguments")
+	}
+
+	cir := fab.ChaincodeInvokeRequest{
+		ChaincodeID: lifecycleCC,
+		Fcn:         lifecycleQueryInstalledChaincodes,
+		Args:        [][]byte{args},
+	}
+	payload, err := queryChaincodeWithTarget(reqCtx, cir, peer, getOpts(opts...))
+	if err != nil {
+		return nil, errors.WithMessage(err, "_lifecycle.QueryInstalledChaincodes failed")
+	}
+
+	result := &queryInstalledChaincodesResult{}
+	if err := proto.Unmarshal(payload, result); err != nil {
+		return nil, errors.Wrap(err, "unmarshal QueryInstalledChaincodesResult failed")
+	}
+
+	installed := make([]LifecycleInstalledChaincode, len(result.InstalledChaincodes))
+	for i, cc := range result.InstalledChaincodes {
+		installed[i] = LifecycleInstalledChaincode{PackageID: cc.PackageId, Label: cc.Label}
+	}
+	return installed, nil
+}
+
+// The messages below are the subset of the messages of the _lifecycle system chaincode
+// (fabric/protos/peer/lifecycle/lifecycle.proto) used to install chaincode packages, since
+// the protos of this version of Fabric don't include the new chaincode lifecycle.
+
+// installChaincodeArgs is the message sent to invoke InstallChaincode
+type installChaincodeArgs struct {
+	ChaincodeInstallPackage []byte `protobuf:"bytes,1,opt,name=chaincode_install_package,json=chaincodeInstallPackage,proto3" json:"chaincode_install_package,omitempty"`
+}
+
+func (m *installChaincodeArgs) Reset()         { *m = installChaincodeArgs{} }
+func (m *installChaincodeArgs) String() string { return proto.CompactTextString(m) }
+func (*installChaincodeArgs) ProtoMessage()    {}
+
+// installChaincodeResult is the message returned by InstallChaincode
+type installChaincodeResult struct {
+	PackageId string `protobuf:"bytes,1,opt,name=package_id,json=packageId" json:"package_id,omitempty"`
+	Label     string `protobuf:"bytes,2,opt,name=label" json:"label,omitempty"`
+}
+
+func (m *installChaincodeResult) Reset()         { *m = installChaincodeResult{} }
+func (m *installChaincodeResult) String() string { return proto.CompactTextString(m) }
+func (*installChaincodeResult) ProtoMessage()    {}
+
+// queryInstalledChaincodesArgs is the message sent to invoke QueryInstalledChaincodes
+type queryInstalledChaincodesArgs struct {
+}
+
+func (m *queryInstalledChaincodesArgs) Reset()         { *m = queryInstalledChaincodesArgs{} }
+func (m *queryInstalledChaincodesArgs) String() string { return proto.CompactTextString(m) }
+func (*queryInstalledChaincodesArgs) ProtoMessage()    {}
+
+// queryInstalledChaincodesResult is the message returned by QueryInstalledChaincodes. The references
+// of the installed chaincodes (the chaincode definitions which use them) are ignored.
+type queryInstalledChaincodesResult struct {
+	InstalledChaincodes []*installedChaincode `protobuf:"bytes,1,rep,name=installed_chaincodes,json=installedChaincodes" json:"installed_chaincodes,omitempty"`
+}
+
+func (m *queryInstalledChaincodesResult) Reset()         { *m = queryInstalledChaincodesResult{} }
+func (m *queryInstalledChaincodesResult) String() string { return proto.CompactTextString(m) }
+func (*queryInstalledChaincodesResult) ProtoMessage()    {}
+
+// installedChaincode is a chaincode package listed in the QueryInstalledChaincodes result
+type installedChaincode struct {
+	PackageId string `protobuf:"bytes,1,opt,name=package_id,json=packageId" json:"package_id,omitempty"`
+	Label     string `protobuf:"bytes,2,opt,name=label" json:"label,omitempty"`
+}
+
+func (m *installedChaincode) Reset()         { *m = installedChaincode{} }
+func (m *installedChaincode) String() string { return proto.CompactTextString(m) }
+func (*installedChaincode) ProtoMessage()    {}
@@ -0,0 +1,68 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resource
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+)
+
+func TestLifecycleInstallChaincode(t *testing.T) {
+	ctx := setupContext()
+	reqCtx, cancel := contextImpl.NewRequest(ctx, contextImpl.WithTimeout(10*time.Second))
+	defer cancel()
+
+	payload, err := proto.Marshal(&installChaincodeResult{PackageId: "mycc_1:1234", Label: "mycc_1"})
+	require.NoError(t, err)
+	peer1 := mocks.MockPeer{MockName: "Peer1", MockURL: "peer1.example.com", Payload: payload, Status: http.StatusOK}
+	peer2 := mocks.MockPeer{MockName: "Peer2", MockURL: "peer2.example.com", Payload: payload, Status: http.StatusOK}
+
+	responses, txID, err := LifecycleInstallChaincode(reqCtx, []byte("package"), []fab.ProposalProcessor{&peer1, &peer2})
+	require.NoError(t, err)
+	assert.NotEmpty(t, txID)
+	require.Len(t, responses, 2)
+	for _, resp := range responses {
+		assert.Equal(t, "mycc_1:1234", resp.PackageID)
+		assert.Equal(t, "mycc_1", resp.Label)
+	}
+
+	_, _, err = LifecycleInstallChaincode(reqCtx, nil, []fab.ProposalProcessor{&peer1})
+	assert.Error(t, err, "expecting error without package")
+}
+
+func TestLifecycleQueryInstalledChaincodes(t *testing.T) {
+	ctx := setupContext()
+	reqCtx, cancel := contextImpl.NewRequest(ctx, contextImpl.WithTimeout(10*time.Second))
+	defer cancel()
+
+	payload, err := proto.Marshal(&queryInstalledChaincodesResult{InstalledChaincodes: []*installedChaincode{
+		{PackageId: "mycc_1:1234", Label: "mycc_1"},
+		{PackageId: "mycc_2:5678", Label: "mycc_2"},
+	}})
+	require.NoError(t, err)
+	peer := mocks.MockPeer{MockName: "Peer1", MockURL: "peer1.example.com", Payload: payload, Status: http.StatusOK}
+
+	installed, err := LifecycleQueryInstalledChaincodes(reqCtx, &peer)
+	require.NoError(t, err)
+	assert.Equal(t, []LifecycleInstalledChaincode{{PackageID: "mycc_1:1234", Label: "mycc_1"}, {PackageID: "mycc_2:5678", Label: "mycc_2"}}, installed)
+
+	_, err = LifecycleQueryInstalledChaincodes(reqCtx, nil)
+	assert.Error(t, err, "expecting error without peer")
+
+	peer.Status = http.StatusInternalServerError
+	_, err = LifecycleQueryInstalledChaincodes(reqCtx, &peer)
+	assert.Error(t, err, "expecting error for bad status")
+}
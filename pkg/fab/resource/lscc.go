@@ -12,6 +12,7 @@ import (
 	"github.com/golang/protobuf/ptypes"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
@@ -29,6 +30,8 @@ type ChaincodeInstallRequest struct {
 	Path    string
 	Version string
 	Package *ChaincodePackage
+	// SignedPackage is installed in place of the package if it's set (see CreateSignedChaincodePackage)
+	SignedPackage *common.Envelope
 }
 
 // ChaincodePackage contains package type and bytes required to create CDS
@@ -50,21 +53,28 @@ func CreateChaincodeInstallProposal(txh fab.TransactionHeader, request Chaincode
 func createInstallInvokeRequest(request ChaincodeInstallRequest) (fab.ChaincodeInvokeRequest, error) {
 	// Generate arguments for install
 	args := [][]byte{}
-	timestamp := time.Now()
-	ts, err := ptypes.TimestampProto(timestamp)
-	if err != nil {
-		return fab.ChaincodeInvokeRequest{}, errors.Wrap(err, "failed to create timestamp in install proposal")
-	}
 
-	ccds := &pb.ChaincodeDeploymentSpec{ChaincodeSpec: &pb.ChaincodeSpec{
-		Type: request.Package.Type, ChaincodeId: &pb.ChaincodeID{Name: request.Name, Path: request.Path, Version: request.Version}},
-		CodePackage: request.Package.Code, EffectiveDate: ts}
+	var ccBytes []byte
+	if request.SignedPackage != nil {
+		// LSCC accepts the signed package in place of the chaincode deployment spec
+		signedPackageBytes, err := protos_utils.Marshal(request.SignedPackage)
+		if err != nil {
+			return fab.ChaincodeInvokeRequest{}, errors.WithMessage(err, "marshal of signed chaincode package failed")
+		}
+		ccBytes = signedPackageBytes
+	} else {
+		ccds, err := newChaincodeDeploymentSpec(request)
+		if err != nil {
+			return fab.ChaincodeInvokeRequest{}, err
+		}
 
-	ccdsBytes, err := protos_utils.Marshal(ccds)
-	if err != nil {
-		return fab.ChaincodeInvokeRequest{}, errors.WithMessage(err, "marshal of chaincode deployment spec failed")
+		ccdsBytes, err := protos_utils.Marshal(ccds)
+		if err != nil {
+			return fab.ChaincodeInvokeRequest{}, errors.WithMessage(err, "marshal of chaincode deployment spec failed")
+		}
+		ccBytes = ccdsBytes
 	}
-	args = append(args, ccdsBytes)
+	args = append(args, ccBytes)
 
 	cir := fab.ChaincodeInvokeRequest{
 		ChaincodeID: lscc,
@@ -74,6 +84,19 @@ func createInstallInvokeRequest(request ChaincodeInstallRequest) (fab.ChaincodeI
 	return cir, nil
 }
 
+func newChaincodeDeploymentSpec(request ChaincodeInstallRequest) (*pb.ChaincodeDeploymentSpec, error) {
+	timestamp := time.Now()
+	ts, err := ptypes.TimestampProto(timestamp)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create timestamp in install proposal")
+	}
+
+	ccds := &pb.ChaincodeDeploymentSpec{ChaincodeSpec: &pb.ChaincodeSpec{
+		Type: request.Package.Type, ChaincodeId: &pb.ChaincodeID{Name: request.Name, Path: request.Path, Version: request.Version}},
+		CodePackage: request.Package.Code, EffectiveDate: ts}
+	return ccds, nil
+}
+
 func createInstalledChaincodesInvokeRequest() fab.ChaincodeInvokeRequest {
 	cir := fab.ChaincodeInvokeRequest{
 		ChaincodeID: lscc,
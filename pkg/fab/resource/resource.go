@@ -109,7 +109,7 @@ func CreateChannel(reqCtx reqContext.Context, request api.CreateChannelRequest,
 
 	optionsValue := getOpts(opts...)
 
-	_, err = retry.NewInvoker(retry.New(optionsValue.retry)).Invoke(
+	_, err = retry.NewInvoker(retry.New(optionsValue.retry), retry.WithContext(reqCtx)).Invoke(
 		func() (interface{}, error) {
 			return nil, createOrUpdateChannel(reqCtx, txh, request)
 		},
@@ -138,6 +138,18 @@ func GenesisBlockFromOrderer(reqCtx reqContext.Context, channelName string, orde
 	return retrieveBlock(reqCtx, []fab.Orderer{orderer}, channelName, newSpecificSeekPosition(0), optionsValue)
 }
 
+// NewestBlockFromOrderer returns the newest block of the specified channel from the given orderer
+func NewestBlockFromOrderer(reqCtx reqContext.Context, channelName string, orderer fab.Orderer, opts ...Opt) (*common.Block, error) {
+	optionsValue := getOpts(opts...)
+	return retrieveBlock(reqCtx, []fab.Orderer{orderer}, channelName, newNewestSeekPosition(), optionsValue)
+}
+
+// BlockFromOrderer returns the block with the given number of the specified channel from the given orderer
+func BlockFromOrderer(reqCtx reqContext.Context, channelName string, orderer fab.Orderer, blockNumber uint64, opts ...Opt) (*common.Block, error) {
+	optionsValue := getOpts(opts...)
+	return retrieveBlock(reqCtx, []fab.Orderer{orderer}, channelName, newSpecificSeekPosition(blockNumber), optionsValue)
+}
+
 // LastConfigFromOrderer fetches the current configuration block for the specified channel
 // from the given orderer
 func LastConfigFromOrderer(reqCtx reqContext.Context, channelName string, orderer fab.Orderer, opts ...Opt) (*common.Block, error) {
@@ -150,7 +162,7 @@ func LastConfigFromOrderer(reqCtx reqContext.Context, channelName string, ordere
 	if err != nil {
 		return nil, err
 	}
-	logger.Debugf("channelConfig - Retrieved newest block number: %d\n", block.Header.Number)
+	logger.Debugf("channelConfig - Retrieved newest block number: %d\n", block.GetHeader().GetNumber())
 
 	// Get the index of the last config block
 	lastConfig, err := GetLastConfigFromBlock(block)
@@ -164,9 +176,9 @@ func LastConfigFromOrderer(reqCtx reqContext.Context, channelName string, ordere
 	if err != nil {
 		return nil, errors.WithMessage(err, "retrieve block failed")
 	}
-	logger.Debugf("channelConfig - Last config block number %d, Number of tx: %d", block.Header.Number, len(block.Data.Data))
+	logger.Debugf("channelConfig - Last config block number %d, Number of tx: %d", block.GetHeader().GetNumber(), len(block.GetData().GetData()))
 
-	if len(block.Data.Data) != 1 {
+	if len(block.GetData().GetData()) != 1 {
 		return nil, errors.New("apiconfig block must contain one transaction")
 	}
 
@@ -313,27 +325,24 @@ func QueryInstalledChaincodes(reqCtx reqContext.Context, peer fab.ProposalProces
 // InstallChaincode sends an install proposal to one or more endorsing peers.
 func InstallChaincode(reqCtx reqContext.Context, req api.InstallChaincodeRequest, targets []fab.ProposalProcessor, opts ...Opt) ([]*fab.TransactionProposalResponse, fab.TransactionID, error) {
 
-	if req.Name == "" {
-		return nil, fab.EmptyTransactionID, errors.New("chaincode name required")
-	}
-	if req.Path == "" {
-		return nil, fab.EmptyTransactionID, errors.New("chaincode path required")
-	}
-	if req.Version == "" {
-		return nil, fab.EmptyTransactionID, errors.New("chaincode version required")
+	if err := checkInstallChaincodeRequest(req); err != nil {
+		return nil, fab.EmptyTransactionID, err
 	}
-	if req.Package == nil {
+	if req.Package == nil && req.SignedPackage == nil {
 		return nil, fab.EmptyTransactionID, errors.New("chaincode package is required")
 	}
 
 	propReq := ChaincodeInstallRequest{
-		Name:    req.Name,
-		Path:    req.Path,
-		Version: req.Version,
-		Package: &ChaincodePackage{
+		Name:          req.Name,
+		Path:          req.Path,
+		Version:       req.Version,
+		SignedPackage: req.SignedPackage,
+	}
+	if req.Package != nil {
+		propReq.Package = &ChaincodePackage{
 			Type: req.Package.Type,
 			Code: req.Package.Code,
-		},
+		}
 	}
 
 	ctx, ok := contextImpl.RequestClientContext(reqCtx)
@@ -353,7 +362,7 @@ func InstallChaincode(reqCtx reqContext.Context, req api.InstallChaincodeRequest
 
 	optionsValue := getOpts(opts...)
 
-	resp, err := retry.NewInvoker(retry.New(optionsValue.retry)).Invoke(
+	resp, err := retry.NewInvoker(retry.New(optionsValue.retry), retry.WithContext(reqCtx)).Invoke(
 		func() (interface{}, error) {
 			return txn.SendProposal(reqCtx, prop, targets)
 		},
@@ -365,6 +374,19 @@ func InstallChaincode(reqCtx reqContext.Context, req api.InstallChaincodeRequest
 	return resp.([]*fab.TransactionProposalResponse), prop.TxnID, err
 }
 
+func checkInstallChaincodeRequest(req api.InstallChaincodeRequest) error {
+	if req.Name == "" {
+		return errors.New("chaincode name required")
+	}
+	if req.Path == "" {
+		return errors.New("chaincode path required")
+	}
+	if req.Version == "" {
+		return errors.New("chaincode version required")
+	}
+	return nil
+}
+
 func queryChaincodeWithTarget(reqCtx reqContext.Context, request fab.ChaincodeInvokeRequest, target fab.ProposalProcessor, opts options) ([]byte, error) {
 
 	targets := []fab.ProposalProcessor{target}
@@ -384,7 +406,7 @@ func queryChaincodeWithTarget(reqCtx reqContext.Context, request fab.ChaincodeIn
 		return nil, errors.WithMessage(err, "NewProposal failed")
 	}
 
-	resp, err := retry.NewInvoker(retry.New(opts.retry)).Invoke(
+	resp, err := retry.NewInvoker(retry.New(opts.retry), retry.WithContext(reqCtx)).Invoke(
 		func() (interface{}, error) {
 			return txn.SendProposal(reqCtx, tp, targets)
 		},
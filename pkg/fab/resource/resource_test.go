@@ -246,6 +246,27 @@ func TestGenesisBlock(t *testing.T) {
 	}
 }
 
+func TestBlockFromOrderer(t *testing.T) {
+	const channelName = "testchannel"
+	ctx := setupContext()
+
+	orderer := mocks.NewMockOrderer("", nil)
+	defer orderer.Close()
+	orderer.EnqueueForSendDeliver(mocks.NewSimpleMockBlock())
+	orderer.EnqueueForSendDeliver(common.Status_SUCCESS)
+	orderer.EnqueueForSendDeliver(mocks.NewSimpleMockBlock())
+	orderer.EnqueueForSendDeliver(common.Status_SUCCESS)
+	reqCtx, cancel := contextImpl.NewRequest(ctx, contextImpl.WithTimeout(10*time.Second))
+	defer cancel()
+
+	if _, err := BlockFromOrderer(reqCtx, channelName, orderer, 1); err != nil {
+		t.Fatalf("BlockFromOrderer failed: %s", err)
+	}
+	if _, err := NewestBlockFromOrderer(reqCtx, channelName, orderer); err != nil {
+		t.Fatalf("NewestBlockFromOrderer failed: %s", err)
+	}
+}
+
 func TestGenesisBlockWithRetry(t *testing.T) {
 	const channelName = "testchannel"
 	ctx := setupContext()
@@ -0,0 +1,95 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txn
+
+import (
+	reqContext "context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/bft"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+// SendBFT sends a transaction to a BFT ordering service for consensus and committing to the ledger.
+// See BroadcastPayloadBFT.
+func SendBFT(reqCtx reqContext.Context, tx *fab.Transaction, orderers []fab.Orderer) (*fab.TransactionResponse, error) {
+	payload, err := newTransactionPayload(tx, orderers)
+	if err != nil {
+		return nil, err
+	}
+
+	return BroadcastPayloadBFT(reqCtx, payload, orderers)
+}
+
+// BroadcastPayloadBFT sends the given payload to all of the orderers of a BFT ordering service concurrently.
+// Since up to f of the orderers may be faulty (e.g. drop the payload), the broadcast only succeeds once
+// f+1 orderers have accepted the payload, so that at least one correct orderer has received it.
+func BroadcastPayloadBFT(reqCtx reqContext.Context, payload *common.Payload, orderers []fab.Orderer) (*fab.TransactionResponse, error) {
+	if len(orderers) == 0 {
+		return nil, errors.New("orderers not set")
+	}
+
+	ctx, ok := context.RequestClientContext(reqCtx)
+	if !ok {
+		return nil, errors.New("failed get client context from reqContext for signPayload")
+	}
+	envelope, err := signPayload(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return broadcastEnvelopeBFT(reqCtx, envelope, orderers)
+}
+
+type broadcastResult struct {
+	resp *fab.TransactionResponse
+	err  error
+}
+
+func broadcastEnvelopeBFT(reqCtx reqContext.Context, envelope *fab.SignedEnvelope, orderers []fab.Orderer) (*fab.TransactionResponse, error) {
+	if len(orderers) == 0 {
+		return nil, errors.New("orderers not set")
+	}
+
+	required := bft.MaxFaulty(len(orderers)) + 1
+
+	// The channel is buffered so that the remaining broadcasts don't block once the outcome is known
+	results := make(chan broadcastResult, len(orderers))
+	for _, orderer := range orderers {
+		go func(orderer fab.Orderer) {
+			resp, err := sendBroadcastWithBreaker(reqCtx, envelope, orderer)
+			results <- broadcastResult{resp: resp, err: err}
+		}(orderer)
+	}
+
+	var accepted []string
+	var errs error
+	for i := 0; i < len(orderers); i++ {
+		result := <-results
+		if result.err != nil {
+			errs = multi.Append(errs, result.err)
+			if len(orderers)-(i+1-len(accepted)) < required {
+				// Too many orderers failed for the broadcast to succeed
+				break
+			}
+			continue
+		}
+
+		accepted = append(accepted, result.resp.Orderer)
+		if len(accepted) >= required {
+			logger.Debugf("Envelope was accepted by %d of %d orderers: %s", len(accepted), len(orderers), accepted)
+			return &fab.TransactionResponse{Orderer: strings.Join(accepted, ",")}, nil
+		}
+	}
+
+	return nil, errors.Wrapf(errs, "envelope was accepted by %d orderers but %d are required", len(accepted), required)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txn
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+)
+
+func TestBroadcastEnvelopeBFT(t *testing.T) {
+	user := mspmocks.NewMockSigningIdentity("test", "1234")
+	ctx := mocks.NewMockContext(user)
+
+	reqCtx, cancel := context.NewRequest(ctx, context.WithTimeout(10*time.Second))
+	defer cancel()
+
+	// With four orderers, up to one may be faulty so two orderers must accept the envelope.
+	// New orderers are used for each broadcast since the broadcasts to the remaining orderers
+	// complete in the background after the outcome is known.
+	newOrderers := func() ([]fab.Orderer, []*mocks.MockOrderer) {
+		var orderers []fab.Orderer
+		var mockOrderers []*mocks.MockOrderer
+		for _, url := range []string{"bft1", "bft2", "bft3", "bft4"} {
+			o := mocks.NewMockOrderer(url, nil)
+			orderers = append(orderers, o)
+			mockOrderers = append(mockOrderers, o)
+		}
+		return orderers, mockOrderers
+	}
+	orderers, mockOrderers := newOrderers()
+
+	sigEnvelope := &fab.SignedEnvelope{Signature: []byte(""), Payload: []byte("")}
+
+	resp, err := broadcastEnvelopeBFT(reqCtx, sigEnvelope, orderers)
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(resp.Orderer, ","), 2, "expected the response to contain the orderers which accepted the envelope")
+
+	// Two failures still leave enough orderers
+	orderers, mockOrderers = newOrderers()
+	mockOrderers[0].EnqueueSendBroadcastError(errors.New("service unavailable"))
+	mockOrderers[1].EnqueueSendBroadcastError(errors.New("service unavailable"))
+	_, err = broadcastEnvelopeBFT(reqCtx, sigEnvelope, orderers)
+	require.NoError(t, err)
+
+	// With three failures, only one orderer accepts the envelope
+	orderers, mockOrderers = newOrderers()
+	mockOrderers[0].EnqueueSendBroadcastError(errors.New("service unavailable"))
+	mockOrderers[1].EnqueueSendBroadcastError(errors.New("service unavailable"))
+	mockOrderers[2].EnqueueSendBroadcastError(errors.New("service unavailable"))
+	_, err = broadcastEnvelopeBFT(reqCtx, sigEnvelope, orderers)
+	assert.Error(t, err)
+
+	_, err = broadcastEnvelopeBFT(reqCtx, sigEnvelope, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,43 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txn
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/circuitbreaker"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+// ordererBreakers holds the circuit breakers of the orderers (keyed by URL). Orderers which
+// fail repeatedly are skipped by broadcasts for a cool-down period.
+var ordererBreakers = circuitbreaker.NewRegistry(circuitbreaker.DefaultConfig())
+
+// SetOrdererCircuitBreakerConfig sets the configuration of the orderer circuit breakers.
+// Note that the configuration is set process-wide.
+func SetOrdererCircuitBreakerConfig(config circuitbreaker.Config) {
+	ordererBreakers.SetConfig(config)
+}
+
+// OrdererCircuitBreakerMetrics returns the state and counters of the orderer circuit breakers
+func OrdererCircuitBreakerMetrics() []circuitbreaker.Metrics {
+	return ordererBreakers.Metrics()
+}
+
+// isOrdererFailure returns false if the orderer rejected the request because of the request
+// itself (e.g. a bad or forbidden request), in which case the orderer is considered healthy
+func isOrdererFailure(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok || s.Group != status.OrdererServerStatus {
+		return true
+	}
+	switch common.Status(s.Code) {
+	case common.Status_BAD_REQUEST, common.Status_FORBIDDEN, common.Status_NOT_FOUND, common.Status_REQUEST_ENTITY_TOO_LARGE:
+		return false
+	default:
+		return true
+	}
+}
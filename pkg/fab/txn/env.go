@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package txn
 
 import (
+	"crypto/sha512"
 	"encoding/hex"
 	"hash"
 	"time"
@@ -86,14 +87,17 @@ func NewHeader(ctx contextApi.Client, channelID string) (*TransactionHeader, err
 	return &txnID, nil
 }
 
+// computeTxnID computes the transaction ID, which is the hash of the nonce and the creator. The
+// nonce and the creator are written separately so that they're not copied to a new buffer.
 func computeTxnID(nonce, creator []byte, h hash.Hash) (string, error) {
-	b := append(nonce, creator...)
-
-	_, err := h.Write(b)
-	if err != nil {
+	if _, err := h.Write(nonce); err != nil {
+		return "", err
+	}
+	if _, err := h.Write(creator); err != nil {
 		return "", err
 	}
-	digest := h.Sum(nil)
+	var buf [sha512.Size]byte
+	digest := h.Sum(buf[:0])
 	id := hex.EncodeToString(digest)
 
 	return id, nil
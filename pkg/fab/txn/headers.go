@@ -0,0 +1,68 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txn
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// ProposalHeaders returns the application headers (see fab.ChaincodeInvokeRequest) of the proposal
+func ProposalHeaders(proposal *pb.Proposal) (map[string][]byte, error) {
+	cpp, err := protos_utils.GetChaincodeProposalPayload(proposal.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling chaincode proposal payload")
+	}
+	return invocationHeaders(cpp.Input)
+}
+
+// ProposalTransient returns the transient data of the proposal. The transient data isn't
+// included in the transaction.
+func ProposalTransient(proposal *pb.Proposal) (map[string][]byte, error) {
+	cpp, err := protos_utils.GetChaincodeProposalPayload(proposal.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling chaincode proposal payload")
+	}
+	return cpp.TransientMap, nil
+}
+
+// TransactionHeaders returns the application headers of the endorser transaction of the envelope
+// (e.g. of a block), which are the headers of the proposal of the transaction
+func TransactionHeaders(envelope *common.Envelope) (map[string][]byte, error) {
+	payload, err := protos_utils.GetPayload(envelope)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling envelope payload")
+	}
+	tx, err := protos_utils.GetTransaction(payload.Data)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling transaction payload")
+	}
+	if len(tx.Actions) == 0 {
+		return nil, errors.New("transaction has no actions")
+	}
+	cap, err := protos_utils.GetChaincodeActionPayload(tx.Actions[0].Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling chaincode action payload")
+	}
+	cpp, err := protos_utils.GetChaincodeProposalPayload(cap.ChaincodeProposalPayload)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling chaincode proposal payload")
+	}
+	return invocationHeaders(cpp.Input)
+}
+
+func invocationHeaders(input []byte) (map[string][]byte, error) {
+	ccis := &pb.ChaincodeInvocationSpec{}
+	if err := proto.Unmarshal(input, ccis); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling chaincode invocation spec")
+	}
+	return ccis.GetChaincodeSpec().GetInput().GetDecorations(), nil
+}
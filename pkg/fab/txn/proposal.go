@@ -8,7 +8,6 @@ package txn
 
 import (
 	reqContext "context"
-	"sync"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
@@ -42,7 +41,7 @@ func CreateChaincodeInvokeProposal(txh fab.TransactionHeader, request fab.Chainc
 	// create invocation spec to target a chaincode with arguments
 	ccis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{
 		Type: pb.ChaincodeSpec_GOLANG, ChaincodeId: &pb.ChaincodeID{Name: request.ChaincodeID},
-		Input: &pb.ChaincodeInput{Args: argsArray}}}
+		Input: &pb.ChaincodeInput{Args: argsArray, Decorations: request.Headers}}}
 
 	proposal, _, err := protos_utils.CreateChaincodeProposalWithTxIDNonceAndTransient(string(txh.TransactionID()), common.HeaderType_ENDORSER_TRANSACTION, txh.ChannelID(), ccis, txh.Nonce(), txh.Creator(), request.TransientMap)
 	if err != nil {
@@ -79,6 +78,14 @@ func signProposal(ctx contextApi.Client, proposal *pb.Proposal) (*pb.SignedPropo
 
 // SendProposal sends a TransactionProposal to ProposalProcessor.
 func SendProposal(reqCtx reqContext.Context, proposal *fab.TransactionProposal, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
+	return SendProposalUntil(reqCtx, proposal, targets, nil)
+}
+
+// SendProposalUntil sends a TransactionProposal to the ProposalProcessors in parallel and returns as soon as
+// done returns true for the responses received so far, cancelling the proposals still in flight. The errors
+// of the processors which failed until then are ignored. If done is nil or never returns true, it returns
+// once all the processors responded, like SendProposal.
+func SendProposalUntil(reqCtx reqContext.Context, proposal *fab.TransactionProposal, targets []fab.ProposalProcessor, done fab.ProposalCompletion) ([]*fab.TransactionProposalResponse, error) {
 
 	if proposal == nil {
 		return nil, errors.New("proposal is required")
@@ -105,33 +112,39 @@ func SendProposal(reqCtx reqContext.Context, proposal *fab.TransactionProposal,
 
 	request := fab.ProcessProposalRequest{SignedProposal: signedProposal}
 
-	var responseMtx sync.Mutex
-	var transactionProposalResponses []*fab.TransactionProposalResponse
-	var wg sync.WaitGroup
-	errs := multi.Errors{}
+	// The proposals still in flight are cancelled on return
+	sendCtx, cancel := reqContext.WithCancel(reqCtx)
+	defer cancel()
+
+	type result struct {
+		resp *fab.TransactionProposalResponse
+		err  error
+	}
+	results := make(chan result, len(targets))
 
 	for _, p := range targets {
-		wg.Add(1)
 		go func(processor fab.ProposalProcessor) {
-			defer wg.Done()
-
-			// TODO: The RPC should be timed-out.
-			//resp, err := processor.ProcessTransactionProposal(context.NewRequestOLD(ctx), request)
-			resp, err := processor.ProcessTransactionProposal(reqCtx, request)
-			if err != nil {
-				logger.Debugf("Received error response from txn proposal processing: %v", err)
-				responseMtx.Lock()
-				errs = append(errs, err)
-				responseMtx.Unlock()
-				return
-			}
-
-			responseMtx.Lock()
-			transactionProposalResponses = append(transactionProposalResponses, resp)
-			responseMtx.Unlock()
+			resp, err := processor.ProcessTransactionProposal(sendCtx, request)
+			results <- result{resp: resp, err: err}
 		}(p)
 	}
-	wg.Wait()
+
+	var transactionProposalResponses []*fab.TransactionProposalResponse
+	errs := multi.Errors{}
+	for range targets {
+		r := <-results
+		if r.err != nil {
+			logger.Debugf("Received error response from txn proposal processing: %v", r.err)
+			errs = append(errs, r.err)
+			continue
+		}
+
+		transactionProposalResponses = append(transactionProposalResponses, r.resp)
+		if done != nil && done(transactionProposalResponses) {
+			logger.Debugf("Proposal completed with %d of %d responses - cancelling the remaining proposals", len(transactionProposalResponses), len(targets))
+			return transactionProposalResponses, nil
+		}
+	}
 
 	return transactionProposalResponses, errs.ToError()
 }
@@ -6,6 +6,7 @@ SPDX-License-Identifier: Apache-2.0
 package txn
 
 import (
+	reqContext "context"
 	"fmt"
 	"reflect"
 	"strings"
@@ -23,6 +24,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
 
@@ -266,6 +268,52 @@ func TestProposalResponseError(t *testing.T) {
 	assert.Equal(t, testError, errs[0])
 }
 
+func TestSendProposalUntil(t *testing.T) {
+	user := mspmocks.NewMockSigningIdentity("test", "1234")
+	ctx := mocks.NewMockContext(user)
+
+	reqCtx, cancel := context.NewRequest(ctx, context.WithTimeout(10*time.Second))
+	defer cancel()
+
+	peer := mocks.NewMockPeer("Peer1", "http://peer1.com")
+	blocking := &blockingProcessor{cancelled: make(chan struct{})}
+	targets := []fab.ProposalProcessor{peer, blocking}
+
+	done := func(responses []*fab.TransactionProposalResponse) bool {
+		return len(responses) >= 1
+	}
+	result, err := SendProposalUntil(reqCtx, &fab.TransactionProposal{Proposal: &pb.Proposal{}}, targets, done)
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "http://peer1.com", result[0].Endorser)
+
+	select {
+	case <-blocking.cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the remaining proposal to be cancelled")
+	}
+
+	// Without completion all the responses are awaited
+	peer2 := mocks.NewMockPeer("Peer2", "http://peer2.com")
+	never := func(responses []*fab.TransactionProposalResponse) bool {
+		return false
+	}
+	result, err = SendProposalUntil(reqCtx, &fab.TransactionProposal{Proposal: &pb.Proposal{}}, []fab.ProposalProcessor{peer, peer2}, never)
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+}
+
+// blockingProcessor doesn't respond until its proposal is cancelled
+type blockingProcessor struct {
+	cancelled chan struct{}
+}
+
+func (p *blockingProcessor) ProcessTransactionProposal(ctx reqContext.Context, request fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
+	<-ctx.Done()
+	close(p.cancelled)
+	return nil, ctx.Err()
+}
+
 func setupMassiveTestPeers(numberOfPeers int) []fab.ProposalProcessor {
 	peers := []fab.ProposalProcessor{}
 
@@ -277,3 +325,44 @@ func setupMassiveTestPeers(numberOfPeers int) []fab.ProposalProcessor {
 
 	return peers
 }
+
+func TestProposalHeaders(t *testing.T) {
+	user := mspmocks.NewMockSigningIdentity("test", "1234")
+	ctx := mocks.NewMockContext(user)
+
+	request := fab.ChaincodeInvokeRequest{
+		ChaincodeID:  "cc",
+		Fcn:          "move",
+		TransientMap: map[string][]byte{"secret": []byte("value")},
+		Headers:      map[string][]byte{"correlation-id": []byte("abc-123")},
+	}
+	txh, err := NewHeader(ctx, testChannel)
+	assert.Nil(t, err)
+	tp, err := CreateChaincodeInvokeProposal(txh, request)
+	assert.Nil(t, err)
+
+	headers, err := ProposalHeaders(tp.Proposal)
+	assert.Nil(t, err)
+	assert.Equal(t, request.Headers, headers)
+	transient, err := ProposalTransient(tp.Proposal)
+	assert.Nil(t, err)
+	assert.Equal(t, request.TransientMap, transient)
+
+	// The headers are included in the transaction but the transient data isn't
+	tx, err := New(fab.TransactionRequest{
+		Proposal: tp,
+		ProposalResponses: []*fab.TransactionProposalResponse{{
+			ProposalResponse: &pb.ProposalResponse{Response: &pb.Response{Status: 200}, Endorsement: &pb.Endorsement{}},
+		}},
+	})
+	assert.Nil(t, err)
+	txBytes, err := proto.Marshal(tx.Transaction)
+	assert.Nil(t, err)
+	payloadBytes, err := proto.Marshal(&common.Payload{Data: txBytes})
+	assert.Nil(t, err)
+
+	headers, err = TransactionHeaders(&common.Envelope{Payload: payloadBytes})
+	assert.Nil(t, err)
+	assert.Equal(t, request.Headers, headers)
+	assert.NotContains(t, string(txBytes), "secret")
+}
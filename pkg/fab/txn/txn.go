@@ -11,11 +11,13 @@ import (
 	reqContext "context"
 	"math/rand"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/sdkevents"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
@@ -75,7 +77,7 @@ func New(request fab.TransactionRequest) (*fab.Transaction, error) {
 	cea := &pb.ChaincodeEndorsedAction{ProposalResponsePayload: responsePayload, Endorsements: endorsements}
 
 	// obtain the bytes of the proposal payload that will go to the transaction
-	propPayloadBytes, err := protos_utils.GetBytesProposalPayloadForTx(pPayl, hdrExt.PayloadVisibility)
+	propPayloadBytes, err := proposalPayloadForTx(proposal.Payload, pPayl, hdrExt.PayloadVisibility)
 	if err != nil {
 		return nil, err
 	}
@@ -98,8 +100,33 @@ func New(request fab.TransactionRequest) (*fab.Transaction, error) {
 	}, nil
 }
 
+// proposalPayloadForTx returns the bytes of the proposal payload that go to the transaction, which is the
+// payload without the transient map. If the proposal has no transient map and its payload bytes contain
+// nothing else than the unmarshalled payload, the bytes are reused instead of marshalling the payload again.
+func proposalPayloadForTx(payloadBytes []byte, payload *pb.ChaincodeProposalPayload, visibility []byte) ([]byte, error) {
+	if len(payload.TransientMap) == 0 && proto.Size(payload) == len(payloadBytes) {
+		return payloadBytes, nil
+	}
+	return protos_utils.GetBytesProposalPayloadForTx(payload, visibility)
+}
+
 // Send send a transaction to the chain’s orderer service (one or more orderer endpoints) for consensus and committing to the ledger.
 func Send(reqCtx reqContext.Context, tx *fab.Transaction, orderers []fab.Orderer) (*fab.TransactionResponse, error) {
+	payload, err := newTransactionPayload(tx, orderers)
+	if err != nil {
+		return nil, err
+	}
+
+	transactionResponse, err := BroadcastPayload(reqCtx, payload, orderers)
+	if err != nil {
+		return nil, err
+	}
+
+	return transactionResponse, nil
+}
+
+// newTransactionPayload creates the payload which is sent to the orderers for the given transaction
+func newTransactionPayload(tx *fab.Transaction, orderers []fab.Orderer) (*common.Payload, error) {
 	if len(orderers) == 0 {
 		return nil, errors.New("orderers is nil")
 	}
@@ -122,14 +149,7 @@ func Send(reqCtx reqContext.Context, tx *fab.Transaction, orderers []fab.Orderer
 	}
 
 	// create the payload
-	payload := common.Payload{Header: hdr, Data: txBytes}
-
-	transactionResponse, err := BroadcastPayload(reqCtx, &payload, orderers)
-	if err != nil {
-		return nil, err
-	}
-
-	return transactionResponse, nil
+	return &common.Payload{Header: hdr, Data: txBytes}, nil
 }
 
 // BroadcastPayload will send the given payload to some orderer, picking random endpoints
@@ -164,19 +184,78 @@ func broadcastEnvelope(reqCtx reqContext.Context, envelope *fab.SignedEnvelope,
 	randOrderers := []fab.Orderer{}
 	randOrderers = append(randOrderers, orderers...)
 
-	// Iterate them in a random order and try broadcasting 1 by 1
+	// Iterate them in a random order and try broadcasting 1 by 1,
+	// skipping the orderers whose circuit is open
 	var errResp error
+	var skipped []fab.Orderer
+	var failed []string
 	for _, i := range rand.Perm(len(randOrderers)) {
-		resp, err := sendBroadcast(reqCtx, envelope, randOrderers[i])
+		orderer := randOrderers[i]
+		if !ordererBreakers.Get(orderer.URL()).Allow() {
+			logger.Debugf("Circuit of orderer [%s] is open - skipping", orderer.URL())
+			skipped = append(skipped, orderer)
+			continue
+		}
+		resp, err := sendBroadcastWithBreaker(reqCtx, envelope, orderer)
+		if err != nil {
+			errResp = err
+			failed = append(failed, orderer.URL())
+		} else {
+			publishFailover(reqCtx, envelope, orderer, failed, errResp)
+			return resp, nil
+		}
+	}
+
+	// As a last resort, try the orderers whose circuit is open
+	for _, orderer := range skipped {
+		resp, err := sendBroadcastWithBreaker(reqCtx, envelope, orderer)
 		if err != nil {
 			errResp = err
+			failed = append(failed, orderer.URL())
 		} else {
+			publishFailover(reqCtx, envelope, orderer, failed, errResp)
 			return resp, nil
 		}
 	}
 	return nil, errResp
 }
 
+// publishFailover publishes an OrdererFailover event on the event bus of the SDK if the envelope was
+// broadcast to the given orderer after it failed on other orderers
+func publishFailover(reqCtx reqContext.Context, envelope *fab.SignedEnvelope, orderer fab.Orderer, failed []string, err error) {
+	if len(failed) == 0 {
+		return
+	}
+
+	event := &sdkevents.Event{Type: sdkevents.OrdererFailover, Target: orderer.URL(), FailedTargets: failed, Err: err}
+	payload := &common.Payload{}
+	if proto.Unmarshal(envelope.Payload, payload) == nil && payload.Header != nil {
+		if chdr, e := protos_utils.UnmarshalChannelHeader(payload.Header.ChannelHeader); e == nil {
+			event.ChannelID = chdr.ChannelId
+		}
+	}
+
+	bus := sdkevents.DefaultBus()
+	if ctx, ok := context.RequestClientContext(reqCtx); ok {
+		bus = sdkevents.BusFor(ctx.EndpointConfig())
+	}
+	bus.Publish(event)
+}
+
+// sendBroadcastWithBreaker broadcasts the envelope and reports the outcome to the circuit breaker of the orderer
+func sendBroadcastWithBreaker(reqCtx reqContext.Context, envelope *fab.SignedEnvelope, orderer fab.Orderer) (*fab.TransactionResponse, error) {
+	resp, err := sendBroadcast(reqCtx, envelope, orderer)
+
+	breaker := ordererBreakers.Get(orderer.URL())
+	if err != nil && isOrdererFailure(err) {
+		breaker.Failure()
+	} else {
+		// The orderer responded (even if it rejected the request)
+		breaker.Success()
+	}
+	return resp, err
+}
+
 func sendBroadcast(reqCtx reqContext.Context, envelope *fab.SignedEnvelope, orderer fab.Orderer) (*fab.TransactionResponse, error) {
 	logger.Debugf("Broadcasting envelope to orderer :%s\n", orderer.URL())
 	// Send request
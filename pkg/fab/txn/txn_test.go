@@ -8,6 +8,8 @@ package txn
 
 import (
 	reqContext "context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"strconv"
@@ -15,13 +17,18 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/circuitbreaker"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/sdkevents"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
@@ -112,6 +119,26 @@ func TestNewTransaction(t *testing.T) {
 
 }
 
+func TestProposalPayloadForTx(t *testing.T) {
+	payload := &pb.ChaincodeProposalPayload{Input: []byte("input")}
+	payloadBytes, err := proto.Marshal(payload)
+	require.NoError(t, err)
+
+	// Without transient map the bytes of the proposal are reused
+	txPayloadBytes, err := proposalPayloadForTx(payloadBytes, payload, nil)
+	require.NoError(t, err)
+	assert.Equal(t, payloadBytes, txPayloadBytes)
+	assert.True(t, &payloadBytes[0] == &txPayloadBytes[0], "expected payload bytes to be reused")
+
+	// The transient map is stripped
+	transientPayload := &pb.ChaincodeProposalPayload{Input: []byte("input"), TransientMap: map[string][]byte{"key": []byte("secret")}}
+	transientPayloadBytes, err := proto.Marshal(transientPayload)
+	require.NoError(t, err)
+	txPayloadBytes, err = proposalPayloadForTx(transientPayloadBytes, transientPayload, nil)
+	require.NoError(t, err)
+	assert.Equal(t, payloadBytes, txPayloadBytes)
+}
+
 func checkRepeatedFieldHeader(proposal fab.TransactionProposal, th TransactionHeader, proposalResp fab.TransactionProposalResponse, txnReq fab.TransactionRequest, t *testing.T) {
 	proposal = fab.TransactionProposal{
 		TxnID:    fab.TransactionID(th.id),
@@ -131,6 +158,32 @@ func checkRepeatedFieldHeader(proposal fab.TransactionProposal, th TransactionHe
 	}
 }
 
+func TestPublishFailover(t *testing.T) {
+	user := mspmocks.NewMockSigningIdentity("test", "1234")
+	reqCtx, cancel := context.NewRequest(mocks.NewMockContext(user), context.WithTimeout(10*time.Second))
+	defer cancel()
+
+	// The mock config doesn't hold an event bus, so the event is published on the default bus
+	sub, eventch := sdkevents.Subscribe(sdkevents.OrdererFailover)
+	defer sdkevents.Unsubscribe(sub)
+
+	chdr, err := proto.Marshal(&common.ChannelHeader{ChannelId: "mychannel"})
+	require.NoError(t, err)
+	payload, err := proto.Marshal(&common.Payload{Header: &common.Header{ChannelHeader: chdr}})
+	require.NoError(t, err)
+
+	publishFailover(reqCtx, &fab.SignedEnvelope{Payload: payload}, mocks.NewMockOrderer("orderer2", nil), []string{"orderer1"}, errors.New("service unavailable"))
+
+	select {
+	case event := <-eventch:
+		assert.Equal(t, "orderer2", event.Target)
+		assert.Equal(t, "mychannel", event.ChannelID)
+		assert.Equal(t, []string{"orderer1"}, event.FailedTargets)
+	default:
+		t.Fatal("expecting OrdererFailover event")
+	}
+}
+
 func TestBroadcastEnvelope(t *testing.T) {
 	user := mspmocks.NewMockSigningIdentity("test", "1234")
 	ctx := mocks.NewMockContext(user)
@@ -190,6 +243,63 @@ func TestBroadcastEnvelope(t *testing.T) {
 	checkBroadcastCount(broadcastCount, orderer1, orderer2, reqCtx, sigEnvelope, orderers, t)
 }
 
+func TestBroadcastCircuitBreaker(t *testing.T) {
+	defer SetOrdererCircuitBreakerConfig(ordererBreakers.Config())
+	SetOrdererCircuitBreakerConfig(circuitbreaker.Config{FailureThreshold: 2, CoolDown: time.Minute})
+
+	user := mspmocks.NewMockSigningIdentity("test", "1234")
+	ctx := mocks.NewMockContext(user)
+
+	failing := mocks.NewMockOrderer("breaker.failing.orderer", nil)
+	healthy := mocks.NewMockOrderer("breaker.healthy.orderer", nil)
+	orderers := []fab.Orderer{failing, healthy}
+
+	sigEnvelope := &fab.SignedEnvelope{
+		Signature: []byte(""),
+		Payload:   []byte(""),
+	}
+
+	reqCtx, cancel := context.NewRequest(ctx, context.WithTimeout(10*time.Second))
+	defer cancel()
+
+	for i := 0; i < 10; i++ {
+		failing.EnqueueSendBroadcastError(status.New(status.OrdererClientStatus, status.ConnectionFailed.ToInt32(), "connection failed", nil))
+	}
+
+	// Keep broadcasting until the failing orderer has been tried twice
+	for i := 0; i < 100 && len(failing.BroadcastErrors) > 8; i++ {
+		_, err := broadcastEnvelope(reqCtx, sigEnvelope, orderers)
+		require.NoError(t, err)
+	}
+	require.Len(t, failing.BroadcastErrors, 8)
+	assert.Equal(t, circuitbreaker.Open, ordererBreakers.Get(failing.URL()).State())
+
+	// The failing orderer is skipped
+	for i := 0; i < 10; i++ {
+		_, err := broadcastEnvelope(reqCtx, sigEnvelope, orderers)
+		require.NoError(t, err)
+	}
+	assert.Len(t, failing.BroadcastErrors, 8, "expected orderer with open circuit to be skipped")
+
+	// Requests rejected by the orderer don't open the circuit
+	healthy.EnqueueSendBroadcastError(status.New(status.OrdererServerStatus, int32(common.Status_BAD_REQUEST), "bad request", nil))
+	healthy.EnqueueSendBroadcastError(status.New(status.OrdererServerStatus, int32(common.Status_BAD_REQUEST), "bad request", nil))
+	_, err := broadcastEnvelope(reqCtx, sigEnvelope, []fab.Orderer{healthy})
+	assert.Error(t, err)
+	_, err = broadcastEnvelope(reqCtx, sigEnvelope, []fab.Orderer{healthy})
+	assert.Error(t, err)
+	assert.Equal(t, circuitbreaker.Closed, ordererBreakers.Get(healthy.URL()).State())
+
+	var found bool
+	for _, m := range OrdererCircuitBreakerMetrics() {
+		if m.Name == failing.URL() {
+			found = true
+			assert.Equal(t, uint64(1), m.Trips)
+		}
+	}
+	assert.True(t, found, "expected metrics for failing orderer")
+}
+
 func checkBroadcastCount(broadcastCount int, orderer1 *mocks.MockOrderer, orderer2 *mocks.MockOrderer, reqCtx reqContext.Context, sigEnvelope *fab.SignedEnvelope, orderers []fab.Orderer, t *testing.T) {
 	for i := 0; i < broadcastCount; i++ {
 		orderer1.EnqueueSendBroadcastError(errors.New("Service Unavailable"))
@@ -299,6 +409,30 @@ func TestBuildChannelHeader(t *testing.T) {
 
 }
 
+func TestComputeTxnID(t *testing.T) {
+	nonce := []byte("nonce")
+	creator := []byte("creator")
+
+	id, err := computeTxnID(nonce, creator, sha256.New())
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte("noncecreator"))
+	assert.Equal(t, hex.EncodeToString(digest[:]), id)
+	assert.Equal(t, "nonce", string(nonce), "nonce should not be modified")
+}
+
+func BenchmarkNewHeader(b *testing.B) {
+	user := mspmocks.NewMockSigningIdentity("test", "1234")
+	ctx := mocks.NewMockContext(user)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := NewHeader(ctx, "test"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func TestSignPayload(t *testing.T) {
 	user := mspmocks.NewMockSigningIdentity("test", "1234")
 	ctx := mocks.NewMockContext(user)
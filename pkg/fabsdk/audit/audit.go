@@ -0,0 +1,253 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package audit records the operations of the SDK (proposals sent to peers and envelopes
+// broadcast to orderers, which include chaincode invocations, chaincode administration and
+// channel configuration updates) to an append-only log, for deployments which must keep an
+// audit trail.
+//
+// Each entry records who (the MSP and subject of the creator of the request), where (the
+// channel, chaincode and target), the transaction ID and the outcome of the operation. Entries
+// are hash-chained: each entry contains the hash of the previous entry, so that removed, inserted
+// and modified entries are detected by Verify. Entries may also be signed (see WithSigner).
+//
+// Auditing is opt-in: the infra provider of the SDK is wrapped by the audit log.
+//
+//  writer, err := audit.NewFileWriter("/var/log/fabric/audit.log")
+//  ...
+//  auditLog, err := audit.New(writer, audit.WithSigner(signingIdentity))
+//  ...
+//  sdk, err := fabsdk.New(configProvider, fabsdk.WithCorePkg(audit.NewCorePkg(defcore.NewProviderFactory(), auditLog)))
+//
+//  entries, err := audit.ReadFile("/var/log/fabric/audit.log")
+//  ...
+//  err = audit.Verify(entries, verifySignature)
+package audit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+)
+
+var logger = logging.NewLogger("fabsdk")
+
+// Operation is the type of an audited operation
+type Operation string
+
+// Audited operations
+const (
+	// Proposal is a proposal sent to a peer for endorsement (or query)
+	Proposal Operation = "proposal"
+	// Broadcast is an envelope (a transaction or a channel configuration update) sent to an orderer
+	Broadcast Operation = "broadcast"
+)
+
+// Outcome is the outcome of an audited operation
+type Outcome string
+
+// Outcomes
+const (
+	Success Outcome = "success"
+	Failure Outcome = "failure"
+)
+
+// Entry is an entry of the audit log
+type Entry struct {
+	// Sequence is the position of the entry in the log, starting at 1
+	Sequence uint64 `json:"seq"`
+	// Time is the time at which the operation completed
+	Time time.Time `json:"time"`
+	// Operation is the type of the operation
+	Operation Operation `json:"operation"`
+	// Admin is true for administrative operations: invocations of system chaincodes and
+	// channel configuration updates
+	Admin bool `json:"admin"`
+	// MSPID is the MSP of the creator of the request
+	MSPID string `json:"mspId,omitempty"`
+	// Subject is the subject of the certificate of the creator of the request
+	Subject string `json:"subject,omitempty"`
+	// ChannelID is the channel of the request
+	ChannelID string `json:"channelId,omitempty"`
+	// ChaincodeID is the chaincode of the request, if any
+	ChaincodeID string `json:"chaincodeId,omitempty"`
+	// Function is the invoked chaincode function (the first argument), if any. The other
+	// arguments aren't recorded since they may contain sensitive data.
+	Function string `json:"function,omitempty"`
+	// Type is the header type of the request (e.g. ENDORSER_TRANSACTION or CONFIG_UPDATE)
+	Type string `json:"type,omitempty"`
+	// TxID is the ID of the transaction of the request
+	TxID string `json:"txId,omitempty"`
+	// Target is the URL of the peer or orderer to which the request was sent
+	Target string `json:"target"`
+	// Outcome is the outcome of the operation
+	Outcome Outcome `json:"outcome"`
+	// Status is the status returned by the peer or orderer, if any
+	Status int32 `json:"status,omitempty"`
+	// Error is the error of the operation, if any
+	Error string `json:"error,omitempty"`
+	// PrevHash is the hash of the previous entry (empty for the first entry)
+	PrevHash []byte `json:"prevHash,omitempty"`
+	// Hash is the SHA-256 hash of the entry, without the hash and the signature
+	Hash []byte `json:"hash"`
+	// Signature is the signature of the hash, if the log has a signer
+	Signature []byte `json:"signature,omitempty"`
+}
+
+// Writer appends the entries of the audit log to a durable store
+type Writer interface {
+	// Write appends the entry
+	Write(entry *Entry) error
+}
+
+// LastEntryReader is implemented by writers which may return the last entry which was written,
+// so that a log resumes the hash chain after a restart
+type LastEntryReader interface {
+	// LastEntry returns the last entry which was written, or nil if none was written
+	LastEntry() (*Entry, error)
+}
+
+// Signer signs the hashes of the entries (e.g. msp.SigningIdentity)
+type Signer interface {
+	Sign(msg []byte) ([]byte, error)
+}
+
+// Log is an append-only, hash-chained audit log
+type Log struct {
+	writer   Writer
+	signer   Signer
+	now      func() time.Time
+	lock     sync.Mutex
+	sequence uint64
+	prevHash []byte
+}
+
+// Option describes a functional parameter for the New constructor
+type Option func(*Log)
+
+// WithSigner makes the log sign the hash of each entry with the given signer
+func WithSigner(signer Signer) Option {
+	return func(l *Log) {
+		l.signer = signer
+	}
+}
+
+// New returns an audit log which appends its entries with the given writer. If the writer
+// implements LastEntryReader then the hash chain continues from the last entry.
+func New(writer Writer, opts ...Option) (*Log, error) {
+	if writer == nil {
+		return nil, errors.New("writer is required")
+	}
+
+	l := &Log{writer: writer, now: time.Now}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	if r, ok := writer.(LastEntryReader); ok {
+		last, err := r.LastEntry()
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to read last audit entry")
+		}
+		if last != nil {
+			l.sequence = last.Sequence
+			l.prevHash = last.Hash
+		}
+	}
+
+	return l, nil
+}
+
+// Record completes the entry (sequence, time, hashes and signature) and appends it to the log
+func (l *Log) Record(entry *Entry) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	entry.Sequence = l.sequence + 1
+	if entry.Time.IsZero() {
+		entry.Time = l.now().UTC()
+	}
+	entry.PrevHash = l.prevHash
+
+	hash, err := entryHash(entry)
+	if err != nil {
+		return err
+	}
+	entry.Hash = hash
+
+	if l.signer != nil {
+		signature, err := l.signer.Sign(hash)
+		if err != nil {
+			return errors.WithMessage(err, "failed to sign audit entry")
+		}
+		entry.Signature = signature
+	}
+
+	if err := l.writer.Write(entry); err != nil {
+		return errors.WithMessage(err, "failed to write audit entry")
+	}
+
+	l.sequence = entry.Sequence
+	l.prevHash = entry.Hash
+	return nil
+}
+
+// record records the entry, logging failures since the audited operation has already completed
+func (l *Log) record(entry *Entry) {
+	if err := l.Record(entry); err != nil {
+		logger.Errorf("Audit of %s of transaction [%s] to [%s] failed: %s", entry.Operation, entry.TxID, entry.Target, err)
+	}
+}
+
+// Verify verifies the hash chain of the given entries, which must be consecutive. If verifySignature
+// isn't nil then it is called to verify the signature of the hash of each entry.
+func Verify(entries []*Entry, verifySignature func(hash, signature []byte) error) error {
+	for i, entry := range entries {
+		if i > 0 {
+			prev := entries[i-1]
+			if entry.Sequence != prev.Sequence+1 {
+				return errors.Errorf("audit entry %d follows audit entry %d", entry.Sequence, prev.Sequence)
+			}
+			if !bytes.Equal(entry.PrevHash, prev.Hash) {
+				return errors.Errorf("audit entry %d isn't chained to audit entry %d", entry.Sequence, prev.Sequence)
+			}
+		}
+
+		hash, err := entryHash(entry)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(hash, entry.Hash) {
+			return errors.Errorf("hash mismatch for audit entry %d", entry.Sequence)
+		}
+
+		if verifySignature != nil {
+			if err := verifySignature(entry.Hash, entry.Signature); err != nil {
+				return errors.WithMessage(err, "invalid signature for audit entry")
+			}
+		}
+	}
+	return nil
+}
+
+// entryHash returns the hash of the entry without its hash and signature
+func entryHash(entry *Entry) ([]byte, error) {
+	e := *entry
+	e.Hash = nil
+	e.Signature = nil
+	data, err := json.Marshal(&e)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal audit entry")
+	}
+	hash := sha256.Sum256(data)
+	return hash[:], nil
+}
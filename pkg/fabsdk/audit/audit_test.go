@@ -0,0 +1,186 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/test/fixturegen"
+)
+
+func TestLog(t *testing.T) {
+	writer := &memWriter{}
+	log, err := New(writer)
+	require.NoError(t, err)
+
+	require.NoError(t, log.Record(&Entry{Operation: Proposal, ChannelID: "mychannel", ChaincodeID: "mycc", TxID: "tx1", Outcome: Success}))
+	require.NoError(t, log.Record(&Entry{Operation: Broadcast, ChannelID: "mychannel", TxID: "tx1", Outcome: Success}))
+	require.NoError(t, log.Record(&Entry{Operation: Broadcast, ChannelID: "mychannel", Admin: true, Outcome: Failure}))
+
+	entries := writer.entries
+	require.Len(t, entries, 3)
+	for i, entry := range entries {
+		assert.EqualValues(t, i+1, entry.Sequence)
+		assert.False(t, entry.Time.IsZero())
+		assert.Len(t, entry.Hash, 32)
+	}
+	assert.Empty(t, entries[0].PrevHash)
+	assert.Equal(t, entries[0].Hash, entries[1].PrevHash)
+	assert.Equal(t, entries[1].Hash, entries[2].PrevHash)
+
+	assert.NoError(t, Verify(entries, nil))
+}
+
+func TestVerifyTampering(t *testing.T) {
+	newEntries := func() []*Entry {
+		writer := &memWriter{}
+		log, err := New(writer)
+		require.NoError(t, err)
+		for _, txID := range []string{"tx1", "tx2", "tx3"} {
+			require.NoError(t, log.Record(&Entry{Operation: Proposal, TxID: txID, Outcome: Success}))
+		}
+		return writer.entries
+	}
+
+	entries := newEntries()
+	entries[1].Outcome = Failure
+	assert.Error(t, Verify(entries, nil), "expecting error for modified entry")
+
+	entries = newEntries()
+	assert.Error(t, Verify([]*Entry{entries[0], entries[2]}, nil), "expecting error for removed entry")
+
+	entries = newEntries()
+	forged := *entries[1]
+	forged.TxID = "forged"
+	forged.Hash, _ = entryHash(&forged)
+	assert.Error(t, Verify([]*Entry{entries[0], &forged, entries[2]}, nil), "expecting error for replaced entry")
+}
+
+func TestLogSigner(t *testing.T) {
+	org, err := fixturegen.NewOrg("AuditorMSP")
+	require.NoError(t, err)
+
+	writer := &memWriter{}
+	log, err := New(writer, WithSigner(org))
+	require.NoError(t, err)
+	require.NoError(t, log.Record(&Entry{Operation: Proposal, TxID: "tx1", Outcome: Success}))
+	require.NotEmpty(t, writer.entries[0].Signature)
+
+	verifySignature := func(hash, signature []byte) error {
+		return verifyECDSA(org.Cert(), hash, signature)
+	}
+	assert.NoError(t, Verify(writer.entries, verifySignature))
+
+	writer.entries[0].Signature[len(writer.entries[0].Signature)-1] ^= 0xff
+	assert.Error(t, Verify(writer.entries, verifySignature))
+
+	log, err = New(&memWriter{}, WithSigner(&failingSigner{}))
+	require.NoError(t, err)
+	assert.Error(t, log.Record(&Entry{Operation: Proposal}))
+}
+
+func TestFileWriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+
+	writer, err := NewFileWriter(path)
+	require.NoError(t, err)
+	log, err := New(writer)
+	require.NoError(t, err)
+	require.NoError(t, log.Record(&Entry{Operation: Proposal, TxID: "tx1", Outcome: Success}))
+	require.NoError(t, log.Record(&Entry{Operation: Broadcast, TxID: "tx1", Outcome: Success}))
+	require.NoError(t, writer.Close())
+
+	// The chain continues after a restart
+	writer, err = NewFileWriter(path)
+	require.NoError(t, err)
+	log, err = New(writer)
+	require.NoError(t, err)
+	require.NoError(t, log.Record(&Entry{Operation: Proposal, TxID: "tx2", Outcome: Failure, Error: "endorsement failed"}))
+	require.NoError(t, writer.Close())
+
+	entries, err := ReadFile(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.EqualValues(t, 3, entries[2].Sequence)
+	assert.Equal(t, "endorsement failed", entries[2].Error)
+	assert.NoError(t, Verify(entries, nil))
+
+	_, err = ReadFile(filepath.Join(dir, "missing.log"))
+	assert.Error(t, err)
+}
+
+func TestNewErrors(t *testing.T) {
+	_, err := New(nil)
+	assert.Error(t, err)
+
+	_, err = New(&memWriter{lastErr: errors.New("storage unavailable")})
+	assert.Error(t, err)
+}
+
+type memWriter struct {
+	entries []*Entry
+	lastErr error
+}
+
+func (w *memWriter) Write(entry *Entry) error {
+	e := *entry
+	w.entries = append(w.entries, &e)
+	return nil
+}
+
+func (w *memWriter) LastEntry() (*Entry, error) {
+	if w.lastErr != nil {
+		return nil, w.lastErr
+	}
+	if len(w.entries) == 0 {
+		return nil, nil
+	}
+	return w.entries[len(w.entries)-1], nil
+}
+
+type failingSigner struct{}
+
+func (s *failingSigner) Sign(msg []byte) ([]byte, error) {
+	return nil, errors.New("HSM unavailable")
+}
+
+// verifyECDSA verifies the signature of the SHA256 digest of the message, as signed by a fixturegen org
+func verifyECDSA(certPEM, msg, signature []byte) error {
+	bl, _ := pem.Decode(certPEM)
+	if bl == nil {
+		return errors.New("invalid certificate")
+	}
+	cert, err := x509.ParseCertificate(bl.Bytes)
+	if err != nil {
+		return err
+	}
+	sig := struct{ R, S *big.Int }{}
+	if _, err := asn1.Unmarshal(signature, &sig); err != nil {
+		return err
+	}
+	digest := sha256.Sum256(msg)
+	if !ecdsa.Verify(cert.PublicKey.(*ecdsa.PublicKey), digest[:], sig.R, sig.S) {
+		return errors.New("signature verification failed")
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit
+
+import (
+	reqContext "context"
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel/membership"
+	sdkApi "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/api"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// adminChaincodes are the system chaincodes whose invocations are administrative operations
+var adminChaincodes = map[string]struct{}{
+	"lscc": {},
+	"cscc": {},
+}
+
+// errorThreshold is the lowest chaincode status which denotes an error (as in the shim)
+const errorThreshold = 400
+
+type providerInit interface {
+	Initialize(providers context.Providers) error
+}
+
+type identityValidatorRegistrar interface {
+	RegisterIdentityValidator(name string, validator membership.Validator) error
+}
+
+// InfraProvider wraps the peers and orderers of an infra provider so that the proposals
+// sent to the peers and the envelopes broadcast to the orderers are audited
+type InfraProvider struct {
+	fab.InfraProvider
+	log *Log
+}
+
+// NewInfraProvider returns a new infra provider which audits the requests of the given infra provider
+func NewInfraProvider(infraProvider fab.InfraProvider, log *Log) *InfraProvider {
+	return &InfraProvider{InfraProvider: infraProvider, log: log}
+}
+
+// Initialize initializes the wrapped infra provider
+func (p *InfraProvider) Initialize(providers context.Providers) error {
+	if pi, ok := p.InfraProvider.(providerInit); ok {
+		return pi.Initialize(providers)
+	}
+	return nil
+}
+
+// RegisterIdentityValidator registers the given identity validator with the wrapped infra provider
+func (p *InfraProvider) RegisterIdentityValidator(name string, validator membership.Validator) error {
+	if r, ok := p.InfraProvider.(identityValidatorRegistrar); ok {
+		return r.RegisterIdentityValidator(name, validator)
+	}
+	return errors.New("wrapped infra provider does not support identity validators")
+}
+
+// CreatePeerFromConfig returns a new peer based on the given configuration
+func (p *InfraProvider) CreatePeerFromConfig(peerCfg *fab.NetworkPeer) (fab.Peer, error) {
+	peer, err := p.InfraProvider.CreatePeerFromConfig(peerCfg)
+	if err != nil {
+		return nil, err
+	}
+	return &auditingPeer{Peer: peer, log: p.log}, nil
+}
+
+// CreateChannelPeerFromConfig returns a new peer of the given channel based on the given configuration
+func (p *InfraProvider) CreateChannelPeerFromConfig(ctx fab.ClientContext, channelID string, peerCfg *fab.NetworkPeer) (fab.Peer, error) {
+	peer, err := p.InfraProvider.CreateChannelPeerFromConfig(ctx, channelID, peerCfg)
+	if err != nil {
+		return nil, err
+	}
+	return &auditingPeer{Peer: peer, log: p.log}, nil
+}
+
+// CreateOrdererFromConfig returns a new orderer based on the given configuration
+func (p *InfraProvider) CreateOrdererFromConfig(cfg *fab.OrdererConfig) (fab.Orderer, error) {
+	orderer, err := p.InfraProvider.CreateOrdererFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &auditingOrderer{Orderer: orderer, log: p.log}, nil
+}
+
+// CreateChannelOrdererFromConfig returns a new orderer of the given channel based on the given configuration
+func (p *InfraProvider) CreateChannelOrdererFromConfig(ctx fab.ClientContext, channelID string, cfg *fab.OrdererConfig) (fab.Orderer, error) {
+	orderer, err := p.InfraProvider.CreateChannelOrdererFromConfig(ctx, channelID, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &auditingOrderer{Orderer: orderer, log: p.log}, nil
+}
+
+// corePkg wraps the infra provider of a core provider factory
+type corePkg struct {
+	sdkApi.CoreProviderFactory
+	log *Log
+}
+
+// NewCorePkg returns a core provider factory whose infra provider audits the requests
+// of the infra provider of the given factory
+func NewCorePkg(factory sdkApi.CoreProviderFactory, log *Log) sdkApi.CoreProviderFactory {
+	return &corePkg{CoreProviderFactory: factory, log: log}
+}
+
+// CreateInfraProvider returns the wrapped infra provider
+func (f *corePkg) CreateInfraProvider(config fab.EndpointConfig) (fab.InfraProvider, error) {
+	infraProvider, err := f.CoreProviderFactory.CreateInfraProvider(config)
+	if err != nil {
+		return nil, err
+	}
+	return NewInfraProvider(infraProvider, f.log), nil
+}
+
+type auditingPeer struct {
+	fab.Peer
+	log *Log
+}
+
+// ProcessTransactionProposal sends the proposal to the peer and audits the outcome
+func (p *auditingPeer) ProcessTransactionProposal(reqCtx reqContext.Context, request fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
+	response, err := p.Peer.ProcessTransactionProposal(reqCtx, request)
+
+	entry, ierr := newProposalEntry(request)
+	if ierr != nil {
+		logger.Warnf("Proposal to [%s] not parsed for audit: %s", p.URL(), ierr)
+		entry = &Entry{Operation: Proposal}
+	}
+	entry.Target = p.URL()
+	entry.Outcome = Success
+	if response != nil {
+		entry.Status = response.Status
+		if response.ChaincodeStatus >= errorThreshold {
+			entry.Outcome = Failure
+		}
+	}
+	if err != nil {
+		entry.Outcome = Failure
+		entry.Error = err.Error()
+	}
+	p.log.record(entry)
+
+	return response, err
+}
+
+type auditingOrderer struct {
+	fab.Orderer
+	log *Log
+}
+
+// SendBroadcast sends the envelope to the orderer and audits the outcome
+func (o *auditingOrderer) SendBroadcast(ctx reqContext.Context, envelope *fab.SignedEnvelope) (*cb.Status, error) {
+	status, err := o.Orderer.SendBroadcast(ctx, envelope)
+
+	entry, ierr := newBroadcastEntry(envelope)
+	if ierr != nil {
+		logger.Warnf("Broadcast to [%s] not parsed for audit: %s", o.URL(), ierr)
+		entry = &Entry{Operation: Broadcast}
+	}
+	entry.Target = o.URL()
+	entry.Outcome = Success
+	if status != nil {
+		entry.Status = int32(*status)
+		if *status != cb.Status_SUCCESS {
+			entry.Outcome = Failure
+		}
+	}
+	if err != nil {
+		entry.Outcome = Failure
+		entry.Error = err.Error()
+	}
+	o.log.record(entry)
+
+	return status, err
+}
+
+// newProposalEntry returns an entry with the creator, channel, chaincode and function of the proposal
+func newProposalEntry(request fab.ProcessProposalRequest) (*Entry, error) {
+	if request.SignedProposal == nil {
+		return nil, errors.New("signed proposal is nil")
+	}
+	proposal := &pb.Proposal{}
+	if err := proto.Unmarshal(request.SignedProposal.ProposalBytes, proposal); err != nil {
+		return nil, errors.Wrap(err, "unmarshal proposal failed")
+	}
+	header, err := utils.GetHeader(proposal.Header)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal proposal header failed")
+	}
+	entry, err := newEntry(Proposal, header)
+	if err != nil {
+		return nil, err
+	}
+
+	ccProposalPayload, err := utils.GetChaincodeProposalPayload(proposal.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal chaincode proposal payload failed")
+	}
+	cis := &pb.ChaincodeInvocationSpec{}
+	if err := proto.Unmarshal(ccProposalPayload.Input, cis); err != nil {
+		return nil, errors.Wrap(err, "unmarshal chaincode invocation spec failed")
+	}
+	if spec := cis.ChaincodeSpec; spec != nil {
+		if spec.ChaincodeId != nil {
+			entry.ChaincodeID = spec.ChaincodeId.Name
+		}
+		if spec.Input != nil && len(spec.Input.Args) > 0 {
+			entry.Function = string(spec.Input.Args[0])
+		}
+	}
+	_, entry.Admin = adminChaincodes[entry.ChaincodeID]
+	return entry, nil
+}
+
+// newBroadcastEntry returns an entry with the creator, channel and chaincode of the envelope
+func newBroadcastEntry(envelope *fab.SignedEnvelope) (*Entry, error) {
+	if envelope == nil {
+		return nil, errors.New("envelope is nil")
+	}
+	payload := &cb.Payload{}
+	if err := proto.Unmarshal(envelope.Payload, payload); err != nil {
+		return nil, errors.Wrap(err, "unmarshal payload failed")
+	}
+	if payload.Header == nil {
+		return nil, errors.New("payload header is nil")
+	}
+	entry, err := newEntry(Broadcast, payload.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	switch entry.Type {
+	case cb.HeaderType_ENDORSER_TRANSACTION.String():
+		ccHeaderExtension, err := utils.GetChaincodeHeaderExtension(payload.Header)
+		if err != nil {
+			return nil, errors.Wrap(err, "unmarshal chaincode header extension failed")
+		}
+		if ccHeaderExtension.ChaincodeId != nil {
+			entry.ChaincodeID = ccHeaderExtension.ChaincodeId.Name
+		}
+		_, entry.Admin = adminChaincodes[entry.ChaincodeID]
+	case cb.HeaderType_CONFIG_UPDATE.String(), cb.HeaderType_CONFIG.String():
+		entry.Admin = true
+	}
+	return entry, nil
+}
+
+// newEntry returns an entry with the type, channel, transaction ID and creator of the header
+func newEntry(operation Operation, header *cb.Header) (*Entry, error) {
+	chHeader, err := utils.UnmarshalChannelHeader(header.ChannelHeader)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal channel header failed")
+	}
+	sigHeader, err := utils.GetSignatureHeader(header.SignatureHeader)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal signature header failed")
+	}
+
+	entry := &Entry{
+		Operation: operation,
+		Type:      cb.HeaderType(chHeader.Type).String(),
+		ChannelID: chHeader.ChannelId,
+		TxID:      chHeader.TxId,
+	}
+	entry.MSPID, entry.Subject = creator(sigHeader.Creator)
+	return entry, nil
+}
+
+// creator returns the MSP ID and the certificate subject of the serialized identity,
+// or as much of them as could be parsed
+func creator(serializedID []byte) (string, string) {
+	sID := &mb.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedID, sID); err != nil {
+		logger.Debugf("Creator not parsed for audit: %s", err)
+		return "", ""
+	}
+	bl, _ := pem.Decode(sID.IdBytes)
+	if bl == nil {
+		return sID.Mspid, ""
+	}
+	cert, err := x509.ParseCertificate(bl.Bytes)
+	if err != nil {
+		logger.Debugf("Certificate of creator not parsed for audit: %s", err)
+		return sID.Mspid, ""
+	}
+	return sID.Mspid, cert.Subject.CommonName
+}
@@ -0,0 +1,124 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit
+
+import (
+	reqContext "context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/test/fixturegen"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+func TestAuditingPeer(t *testing.T) {
+	gen, err := fixturegen.New("mychannel")
+	require.NoError(t, err)
+	tx, err := gen.Transaction("mycc", [][]byte{[]byte("transfer"), []byte("a"), []byte("b")})
+	require.NoError(t, err)
+
+	writer := &memWriter{}
+	log, err := New(writer)
+	require.NoError(t, err)
+	request := fab.ProcessProposalRequest{SignedProposal: tx.SignedProposal}
+
+	peer := &auditingPeer{Peer: &mocks.MockPeer{MockURL: "grpc://peer1:7051", Status: 200}, log: log}
+	_, err = peer.ProcessTransactionProposal(reqContext.Background(), request)
+	require.NoError(t, err)
+
+	failingPeer := &auditingPeer{Peer: &mocks.MockPeer{MockURL: "grpc://peer2:7051", Error: errors.New("peer unavailable")}, log: log}
+	_, err = failingPeer.ProcessTransactionProposal(reqContext.Background(), request)
+	require.Error(t, err)
+
+	require.Len(t, writer.entries, 2)
+	entry := writer.entries[0]
+	assert.Equal(t, Proposal, entry.Operation)
+	assert.False(t, entry.Admin)
+	assert.Equal(t, "Org1MSP", entry.MSPID)
+	assert.Equal(t, "member.Org1MSP", entry.Subject)
+	assert.Equal(t, "mychannel", entry.ChannelID)
+	assert.Equal(t, "mycc", entry.ChaincodeID)
+	assert.Equal(t, "transfer", entry.Function)
+	assert.Equal(t, cb.HeaderType_ENDORSER_TRANSACTION.String(), entry.Type)
+	assert.Equal(t, tx.TxID, entry.TxID)
+	assert.Equal(t, "grpc://peer1:7051", entry.Target)
+	assert.Equal(t, Success, entry.Outcome)
+
+	entry = writer.entries[1]
+	assert.Equal(t, "grpc://peer2:7051", entry.Target)
+	assert.Equal(t, Failure, entry.Outcome)
+	assert.Equal(t, "peer unavailable", entry.Error)
+	assert.NoError(t, Verify(writer.entries, nil))
+}
+
+func TestAuditingOrderer(t *testing.T) {
+	gen, err := fixturegen.New("mychannel")
+	require.NoError(t, err)
+	tx, err := gen.Transaction("lscc", [][]byte{[]byte("deploy")})
+	require.NoError(t, err)
+
+	writer := &memWriter{}
+	log, err := New(writer)
+	require.NoError(t, err)
+
+	mockOrderer := mocks.NewMockOrderer("grpc://orderer:7050", nil)
+	orderer := &auditingOrderer{Orderer: mockOrderer, log: log}
+	_, err = orderer.SendBroadcast(reqContext.Background(), &fab.SignedEnvelope{Payload: tx.Envelope.Payload, Signature: tx.Envelope.Signature})
+	require.NoError(t, err)
+
+	configUpdate, err := proto.Marshal(&cb.Payload{Header: &cb.Header{
+		ChannelHeader:   mustMarshal(t, &cb.ChannelHeader{Type: int32(cb.HeaderType_CONFIG_UPDATE), ChannelId: "newchannel"}),
+		SignatureHeader: mustMarshal(t, &cb.SignatureHeader{Creator: gen.Orgs()[0].Identity()}),
+	}})
+	require.NoError(t, err)
+	mockOrderer.EnqueueSendBroadcastError(errors.New("bad request"))
+	_, err = orderer.SendBroadcast(reqContext.Background(), &fab.SignedEnvelope{Payload: configUpdate})
+	require.Error(t, err)
+
+	require.Len(t, writer.entries, 2)
+	entry := writer.entries[0]
+	assert.Equal(t, Broadcast, entry.Operation)
+	assert.True(t, entry.Admin, "expecting invocation of lscc to be administrative")
+	assert.Equal(t, "lscc", entry.ChaincodeID)
+	assert.Equal(t, tx.TxID, entry.TxID)
+	assert.Equal(t, "grpc://orderer:7050", entry.Target)
+	assert.Equal(t, Success, entry.Outcome)
+
+	entry = writer.entries[1]
+	assert.True(t, entry.Admin, "expecting channel configuration update to be administrative")
+	assert.Equal(t, cb.HeaderType_CONFIG_UPDATE.String(), entry.Type)
+	assert.Equal(t, "newchannel", entry.ChannelID)
+	assert.Equal(t, "Org1MSP", entry.MSPID)
+	assert.Equal(t, Failure, entry.Outcome)
+	assert.Equal(t, "bad request", entry.Error)
+}
+
+func TestAuditingUnparsableRequest(t *testing.T) {
+	writer := &memWriter{}
+	log, err := New(writer)
+	require.NoError(t, err)
+
+	orderer := &auditingOrderer{Orderer: mocks.NewMockOrderer("grpc://orderer:7050", nil), log: log}
+	_, err = orderer.SendBroadcast(reqContext.Background(), &fab.SignedEnvelope{Payload: []byte("garbage")})
+	require.NoError(t, err)
+
+	require.Len(t, writer.entries, 1, "expecting request to be audited even if it couldn't be parsed")
+	assert.Equal(t, "grpc://orderer:7050", writer.entries[0].Target)
+	assert.Equal(t, Success, writer.entries[0].Outcome)
+}
+
+func mustMarshal(t *testing.T, msg proto.Message) []byte {
+	data, err := proto.Marshal(msg)
+	require.NoError(t, err)
+	return data
+}
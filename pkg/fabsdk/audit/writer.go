@@ -0,0 +1,102 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// FileWriter appends the entries of the audit log to a file, one JSON entry per line
+type FileWriter struct {
+	lock sync.Mutex
+	file *os.File
+	last *Entry
+}
+
+// NewFileWriter returns a writer which appends to the given file, creating it if needed.
+// The entries already in the file are read so that the hash chain continues from the last entry.
+func NewFileWriter(path string) (*FileWriter, error) {
+	entries, err := ReadFile(path)
+	if err != nil && !os.IsNotExist(errors.Cause(err)) {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open audit log")
+	}
+
+	w := &FileWriter{file: file}
+	if len(entries) > 0 {
+		w.last = entries[len(entries)-1]
+	}
+	return w, nil
+}
+
+// Write appends the entry to the file and syncs it to disk
+func (w *FileWriter) Write(entry *Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal audit entry")
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if _, err := w.file.Write(append(data, '\n')); err != nil {
+		return errors.Wrap(err, "failed to append to audit log")
+	}
+	if err := w.file.Sync(); err != nil {
+		return errors.Wrap(err, "failed to sync audit log")
+	}
+	w.last = entry
+	return nil
+}
+
+// LastEntry returns the last entry of the file, or nil if the file is empty
+func (w *FileWriter) LastEntry() (*Entry, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.last, nil
+}
+
+// Close closes the file
+func (w *FileWriter) Close() error {
+	return w.file.Close()
+}
+
+// ReadFile returns the entries of the given audit log file
+func ReadFile(path string) ([]*Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open audit log")
+	}
+	defer file.Close()
+
+	var entries []*Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		entry := &Entry{}
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal audit entry %d", len(entries)+1)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read audit log")
+	}
+	return entries, nil
+}
@@ -0,0 +1,216 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+)
+
+// defaultChannelClientIdleTimeout is the time after which a channel client which isn't used is closed
+const defaultChannelClientIdleTimeout = 10 * time.Minute
+
+// WithChannelClientIdleTimeout sets the time after which a channel client managed by the SDK (see ChannelClient)
+// is closed once it's released by all its users. A timeout of zero keeps the clients until the SDK is closed.
+func WithChannelClientIdleTimeout(timeout time.Duration) Option {
+	return func(opts *options) error {
+		if timeout < 0 {
+			return errors.Errorf("invalid channel client idle timeout [%s]", timeout)
+		}
+		opts.channelClientIdleTimeout = &timeout
+		return nil
+	}
+}
+
+// WithChannelClientOptions sets the options of the channel clients managed by the SDK (see ChannelClient)
+func WithChannelClientOptions(clientOpts ...channel.ClientOption) Option {
+	return func(opts *options) error {
+		opts.channelClientOpts = clientOpts
+		return nil
+	}
+}
+
+// PooledChannelClient is a channel client managed by the SDK, which is shared by all the users of the same
+// channel and identity. Release must be called when the client is no longer needed.
+type PooledChannelClient struct {
+	*channel.Client
+	entry *pooledChannelClient
+	once  sync.Once
+}
+
+// Release releases the client, which is closed once it's released by all its users and
+// isn't used for the idle timeout of the SDK. The client must not be used once it's released.
+func (c *PooledChannelClient) Release() {
+	c.once.Do(func() {
+		c.entry.pool.release(c.entry)
+	})
+}
+
+// ChannelClient returns the channel client of the given channel and identity, which is created on first use and
+// shared by the callers which ask for the same channel and identity, so that applications needn't create a client
+// per request or keep track of the clients they created. The client must be released with Release when it's no
+// longer needed; it's closed once it's released by all its users and isn't used for the idle timeout of the SDK.
+func (sdk *FabricSDK) ChannelClient(channelID string, options ...ContextOption) (*PooledChannelClient, error) {
+	identity, err := sdk.newIdentity(options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get identity of channel client")
+	}
+	return sdk.channelClients.acquire(channelID, identity)
+}
+
+type channelClientKey struct {
+	channelID string
+	mspID     string
+	id        string
+	cert      string
+}
+
+// pooledChannelClient is a reference counted channel client
+type pooledChannelClient struct {
+	pool   *channelClientPool
+	key    channelClientKey
+	once   sync.Once
+	client *channel.Client
+	err    error
+	refs   int
+	timer  *time.Timer
+}
+
+// channelClientPool holds the channel clients managed by the SDK
+type channelClientPool struct {
+	provider    contextApi.Providers
+	idleTimeout time.Duration
+	clientOpts  []channel.ClientOption
+	newClient   func(channelProvider contextApi.ChannelProvider, opts ...channel.ClientOption) (*channel.Client, error)
+
+	lock    sync.Mutex
+	clients map[channelClientKey]*pooledChannelClient
+	closed  bool
+}
+
+func newChannelClientPool(provider contextApi.Providers, idleTimeout time.Duration, clientOpts []channel.ClientOption) *channelClientPool {
+	return &channelClientPool{
+		provider:    provider,
+		idleTimeout: idleTimeout,
+		clientOpts:  clientOpts,
+		newClient:   channel.New,
+		clients:     make(map[channelClientKey]*pooledChannelClient),
+	}
+}
+
+// acquire returns a reference to the channel client of the given channel and identity, which is created if needed
+func (p *channelClientPool) acquire(channelID string, identity msp.SigningIdentity) (*PooledChannelClient, error) {
+	if channelID == "" {
+		return nil, errors.New("must provide channel ID")
+	}
+
+	key := channelClientKey{
+		channelID: channelID,
+		mspID:     identity.Identifier().MSPID,
+		id:        identity.Identifier().ID,
+		cert:      string(identity.EnrollmentCertificate()),
+	}
+
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+		return nil, errors.New("SDK is closed")
+	}
+	entry, ok := p.clients[key]
+	if !ok {
+		entry = &pooledChannelClient{pool: p, key: key}
+		p.clients[key] = entry
+	}
+	entry.refs++
+	if entry.timer != nil {
+		entry.timer.Stop()
+		entry.timer = nil
+	}
+	p.lock.Unlock()
+
+	// The client is created outside of the lock so that the creation of a client doesn't delay the users of other clients
+	entry.once.Do(func() {
+		logger.Debugf("Creating channel client of [%s] for channel [%s]", key.id, channelID)
+		clientProvider := func() (contextApi.Client, error) {
+			return &context.Client{Providers: p.provider, SigningIdentity: identity}, nil
+		}
+		entry.client, entry.err = p.newClient(func() (contextApi.Channel, error) {
+			return context.NewChannel(clientProvider, channelID)
+		}, p.clientOpts...)
+	})
+
+	if entry.err != nil {
+		p.remove(entry)
+		return nil, errors.WithMessage(entry.err, "failed to create channel client")
+	}
+	return &PooledChannelClient{Client: entry.client, entry: entry}, nil
+}
+
+// release releases a reference to the channel client, which is closed after the idle timeout if it was the last one
+func (p *channelClientPool) release(entry *pooledChannelClient) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	entry.refs--
+	if entry.refs > 0 || p.closed || p.idleTimeout == 0 {
+		return
+	}
+	entry.timer = time.AfterFunc(p.idleTimeout, func() {
+		p.expire(entry)
+	})
+}
+
+// expire closes the channel client if it's still unused
+func (p *channelClientPool) expire(entry *pooledChannelClient) {
+	p.lock.Lock()
+	if entry.refs > 0 || p.clients[entry.key] != entry {
+		p.lock.Unlock()
+		return
+	}
+	delete(p.clients, entry.key)
+	p.lock.Unlock()
+
+	logger.Debugf("Closing idle channel client of [%s] for channel [%s]", entry.key.id, entry.key.channelID)
+	entry.client.Close()
+}
+
+// remove removes a channel client which couldn't be created, so that it's created again by the next user
+func (p *channelClientPool) remove(entry *pooledChannelClient) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	entry.refs--
+	if p.clients[entry.key] == entry {
+		delete(p.clients, entry.key)
+	}
+}
+
+// close closes all the channel clients
+func (p *channelClientPool) close() {
+	p.lock.Lock()
+	clients := p.clients
+	p.clients = make(map[channelClientKey]*pooledChannelClient)
+	p.closed = true
+	p.lock.Unlock()
+
+	for _, entry := range clients {
+		if entry.timer != nil {
+			entry.timer.Stop()
+		}
+		entry.once.Do(func() {})
+		if entry.client != nil {
+			entry.client.Close()
+		}
+	}
+}
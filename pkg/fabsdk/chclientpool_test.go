@@ -0,0 +1,139 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+)
+
+func TestChannelClientPool(t *testing.T) {
+	pool, created := newTestChannelClientPool(0)
+	user1 := mspmocks.NewMockSigningIdentity("user1", "Org1MSP")
+	user2 := mspmocks.NewMockSigningIdentity("user2", "Org1MSP")
+
+	c1, err := pool.acquire("mychannel", user1)
+	require.NoError(t, err)
+	c2, err := pool.acquire("mychannel", user1)
+	require.NoError(t, err)
+	assert.True(t, c1.Client == c2.Client, "expecting client to be shared")
+
+	c3, err := pool.acquire("mychannel", user2)
+	require.NoError(t, err)
+	assert.False(t, c1.Client == c3.Client, "expecting client per identity")
+	c4, err := pool.acquire("otherchannel", user1)
+	require.NoError(t, err)
+	assert.False(t, c1.Client == c4.Client, "expecting client per channel")
+	assert.Equal(t, 3, created())
+
+	_, err = pool.acquire("", user1)
+	assert.Error(t, err, "expecting error without channel ID")
+
+	c1.Release()
+	c1.Release()
+	c2.Release()
+	c5, err := pool.acquire("mychannel", user1)
+	require.NoError(t, err)
+	assert.True(t, c1.Client == c5.Client, "expecting client to be kept without idle timeout")
+	assert.Equal(t, 3, created())
+
+	pool.close()
+	_, err = pool.acquire("mychannel", user1)
+	assert.Error(t, err, "expecting error once closed")
+}
+
+func TestChannelClientPoolIdleTimeout(t *testing.T) {
+	pool, created := newTestChannelClientPool(50 * time.Millisecond)
+	defer pool.close()
+	user1 := mspmocks.NewMockSigningIdentity("user1", "Org1MSP")
+
+	c1, err := pool.acquire("mychannel", user1)
+	require.NoError(t, err)
+	c1.Release()
+
+	// A client which is used again before the idle timeout is kept
+	c2, err := pool.acquire("mychannel", user1)
+	require.NoError(t, err)
+	assert.True(t, c1.Client == c2.Client)
+	time.Sleep(100 * time.Millisecond)
+	c3, err := pool.acquire("mychannel", user1)
+	require.NoError(t, err)
+	assert.True(t, c1.Client == c3.Client, "client in use shouldn't expire")
+	c2.Release()
+	c3.Release()
+
+	time.Sleep(100 * time.Millisecond)
+	c4, err := pool.acquire("mychannel", user1)
+	require.NoError(t, err)
+	assert.False(t, c1.Client == c4.Client, "expecting idle client to be closed and created again")
+	assert.Equal(t, 2, created())
+	c4.Release()
+}
+
+func TestChannelClientPoolCreationError(t *testing.T) {
+	pool, _ := newTestChannelClientPool(0)
+	defer pool.close()
+	user1 := mspmocks.NewMockSigningIdentity("user1", "Org1MSP")
+
+	fail := true
+	pool.newClient = func(channelProvider contextApi.ChannelProvider, opts ...channel.ClientOption) (*channel.Client, error) {
+		if fail {
+			return nil, errors.New("channel not found")
+		}
+		return &channel.Client{}, nil
+	}
+
+	_, err := pool.acquire("mychannel", user1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "channel not found")
+
+	fail = false
+	c1, err := pool.acquire("mychannel", user1)
+	require.NoError(t, err, "expecting client to be created again after an error")
+	c1.Release()
+}
+
+func TestChannelClientOptions(t *testing.T) {
+	_, err := New(config.FromFile(identityOptConfigFile), WithChannelClientIdleTimeout(-time.Second))
+	assert.Error(t, err, "expecting error for negative idle timeout")
+
+	sdk, err := New(config.FromFile(identityOptConfigFile), WithChannelClientIdleTimeout(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, sdk.channelClients.idleTimeout)
+	sdk.Close()
+
+	_, err = sdk.ChannelClient("mychannel", WithUser(identityValidOptUser), WithOrg(identityValidOptOrg))
+	assert.Error(t, err, "expecting error once the SDK is closed")
+}
+
+func newTestChannelClientPool(idleTimeout time.Duration) (*channelClientPool, func() int) {
+	pool := newChannelClientPool(nil, idleTimeout, nil)
+
+	var lock sync.Mutex
+	created := 0
+	pool.newClient = func(channelProvider contextApi.ChannelProvider, opts ...channel.ClientOption) (*channel.Client, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		created++
+		return &channel.Client{}, nil
+	}
+	return pool, func() int {
+		lock.Lock()
+		defer lock.Unlock()
+		return created
+	}
+}
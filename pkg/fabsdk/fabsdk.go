@@ -11,6 +11,7 @@ import (
 	"math/rand"
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
 	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
@@ -19,11 +20,17 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
 	fabImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel/membership"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
 	sdkApi "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/api"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/provider/chpvdr"
 	mspImpl "github.com/hyperledger/fabric-sdk-go/pkg/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/certexpiry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/circuitbreaker"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/sdkevents"
 	"github.com/pkg/errors"
 )
 
@@ -31,8 +38,9 @@ var logger = logging.NewLogger("fabsdk")
 
 // FabricSDK provides access (and context) to clients being managed by the SDK.
 type FabricSDK struct {
-	opts     options
-	provider *context.Provider
+	opts           options
+	provider       *context.Provider
+	channelClients *channelClientPool
 }
 
 type options struct {
@@ -44,6 +52,28 @@ type options struct {
 	endpointConfig    fab.EndpointConfig
 	IdentityConfig    msp.IdentityConfig
 	ConfigBackend     core.ConfigBackend
+
+	channelClientIdleTimeout *time.Duration
+	channelClientOpts        []channel.ClientOption
+	dialers                  map[string]comm.Dialer
+	certExpiryThreshold      *time.Duration
+	certExpiryHandlers       []certexpiry.Handler
+	identityValidators       []identityValidator
+}
+
+type identityValidator struct {
+	name      string
+	validator membership.Validator
+}
+
+// identityValidatorRegistrar is implemented by infra providers which support identity validators
+type identityValidatorRegistrar interface {
+	RegisterIdentityValidator(name string, validator membership.Validator) error
+}
+
+// dialerRegistrar is implemented by endpoint configs which support custom dialers
+type dialerRegistrar interface {
+	RegisterDialer(name string, dialer comm.Dialer)
 }
 
 // Option configures the SDK.
@@ -156,6 +186,71 @@ func WithLoggerPkg(logger api.LoggerProvider) Option {
 	}
 }
 
+// WithDialer registers a custom dialer under the given name with the endpoint config of the SDK. Peers and
+// orderers select the dialer with the 'dialer' GRPC option; a dialer registered as comm.DefaultDialer is used
+// for all peers and orderers which don't select one.
+func WithDialer(name string, dialer comm.Dialer) Option {
+	return func(opts *options) error {
+		if dialer == nil {
+			return errors.Errorf("dialer [%s] is nil", name)
+		}
+		if opts.dialers == nil {
+			opts.dialers = make(map[string]comm.Dialer)
+		}
+		opts.dialers[name] = dialer
+		return nil
+	}
+}
+
+// WithCertExpiryThreshold sets the remaining validity below which the channel MSP certificates and local
+// signing certificates of the SDK are reported as expiring (see package certexpiry).
+func WithCertExpiryThreshold(threshold time.Duration) Option {
+	return func(opts *options) error {
+		if threshold < 0 {
+			return errors.Errorf("invalid certificate expiry threshold [%s]", threshold)
+		}
+		opts.certExpiryThreshold = &threshold
+		return nil
+	}
+}
+
+// WithCertExpiryHandler registers a handler which is notified when the channel MSP certificates or local
+// signing certificates of the SDK are about to expire or have expired.
+func WithCertExpiryHandler(handler certexpiry.Handler) Option {
+	return func(opts *options) error {
+		if handler == nil {
+			return errors.New("certificate expiry handler is nil")
+		}
+		opts.certExpiryHandlers = append(opts.certExpiryHandlers, handler)
+		return nil
+	}
+}
+
+// WithIdentityValidator registers a validator with the infra provider of the SDK which is invoked, in addition
+// to the MSP validation, when channel membership validates an identity.
+func WithIdentityValidator(name string, validator membership.Validator) Option {
+	return func(opts *options) error {
+		if validator == nil {
+			return errors.Errorf("identity validator [%s] is nil", name)
+		}
+		opts.identityValidators = append(opts.identityValidators, identityValidator{name: name, validator: validator})
+		return nil
+	}
+}
+
+// WithOrdererCircuitBreaker sets the configuration of the circuit breakers which cause orderers that
+// fail repeatedly to be skipped by broadcasts for a cool-down period. A failure threshold of zero
+// disables the circuit breakers. Note that the configuration is set process-wide.
+func WithOrdererCircuitBreaker(config circuitbreaker.Config) Option {
+	return func(opts *options) error {
+		if config.FailureThreshold < 0 || config.CoolDown < 0 {
+			return errors.Errorf("invalid orderer circuit breaker configuration %+v", config)
+		}
+		txn.SetOrdererCircuitBreakerConfig(config)
+		return nil
+	}
+}
+
 // providerInit interface allows for initializing providers
 // TODO: minimize interface
 type providerInit interface {
@@ -182,6 +277,14 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 		return errors.WithMessage(err, "failed to initialize configuration")
 	}
 
+	if err = sdk.registerDialers(); err != nil {
+		return err
+	}
+
+	if err = sdk.configureCertExpiry(); err != nil {
+		return err
+	}
+
 	// Initialize crypto provider
 	cryptoSuite, err := sdk.opts.Core.CreateCryptoSuiteProvider(sdk.opts.CryptoSuiteConfig)
 	if err != nil {
@@ -225,6 +328,10 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 		return errors.WithMessage(err, "failed to create infra provider")
 	}
 
+	if err = sdk.registerIdentityValidators(infraProvider); err != nil {
+		return err
+	}
+
 	// Initialize discovery provider
 	discoveryProvider, err := sdk.opts.Service.CreateDiscoveryProvider(sdk.opts.endpointConfig)
 	if err != nil {
@@ -291,11 +398,18 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 		}
 	}
 
+	idleTimeout := defaultChannelClientIdleTimeout
+	if sdk.opts.channelClientIdleTimeout != nil {
+		idleTimeout = *sdk.opts.channelClientIdleTimeout
+	}
+	sdk.channelClients = newChannelClientPool(sdk.provider, idleTimeout, sdk.opts.channelClientOpts)
+
 	return nil
 }
 
 // Close frees up caches and connections being maintained by the SDK
 func (sdk *FabricSDK) Close() {
+	sdk.channelClients.close()
 	if pvdr, ok := sdk.provider.DiscoveryProvider().(closeable); ok {
 		pvdr.Close()
 	}
@@ -344,6 +458,76 @@ func (sdk *FabricSDK) ChannelContext(channelID string, options ...ContextOption)
 	return channelProvider
 }
 
+// registerDialers registers the custom dialers passed through opts with the endpoint config
+func (sdk *FabricSDK) registerDialers() error {
+	if len(sdk.opts.dialers) == 0 {
+		return nil
+	}
+
+	registrar, ok := sdk.opts.endpointConfig.(dialerRegistrar)
+	if !ok {
+		return errors.New("endpoint config does not support custom dialers")
+	}
+	for name, dialer := range sdk.opts.dialers {
+		registrar.RegisterDialer(name, dialer)
+	}
+	return nil
+}
+
+// registerIdentityValidators registers the identity validators passed through opts with the infra provider
+func (sdk *FabricSDK) registerIdentityValidators(infraProvider fab.InfraProvider) error {
+	if len(sdk.opts.identityValidators) == 0 {
+		return nil
+	}
+
+	registrar, ok := infraProvider.(identityValidatorRegistrar)
+	if !ok {
+		return errors.New("infra provider does not support identity validators")
+	}
+	for _, v := range sdk.opts.identityValidators {
+		if err := registrar.RegisterIdentityValidator(v.name, v.validator); err != nil {
+			return errors.WithMessage(err, "failed to register identity validator")
+		}
+	}
+	return nil
+}
+
+// configureCertExpiry applies the certificate expiry options to the tracker of the endpoint config
+// and publishes the expiry events of the tracker on the event bus of the SDK
+func (sdk *FabricSDK) configureCertExpiry() error {
+	provider, ok := sdk.opts.endpointConfig.(certexpiry.TrackerProvider)
+	if !ok {
+		if sdk.opts.certExpiryThreshold != nil || len(sdk.opts.certExpiryHandlers) > 0 {
+			return errors.New("endpoint config does not support certificate expiry tracking")
+		}
+		return nil
+	}
+
+	tracker := provider.CertExpiryTracker()
+	if sdk.opts.certExpiryThreshold != nil {
+		tracker.SetWarningThreshold(*sdk.opts.certExpiryThreshold)
+	}
+	for _, handler := range sdk.opts.certExpiryHandlers {
+		tracker.RegisterHandler(handler)
+	}
+	tracker.RegisterHandler(func(event *certexpiry.Event) {
+		sdk.EventBus().Publish(&sdkevents.Event{Type: sdkevents.CertExpiring, Target: event.Subject, Cert: event})
+	})
+	return nil
+}
+
+// EventBus returns the bus on which the SDK publishes the events about the health of its connections
+// to the network (see package sdkevents)
+func (sdk *FabricSDK) EventBus() *sdkevents.Bus {
+	return sdkevents.BusFor(sdk.opts.endpointConfig)
+}
+
+// CertExpiryMetrics returns the metrics of the certificates of the SDK which are about to expire
+// or have expired
+func (sdk *FabricSDK) CertExpiryMetrics() certexpiry.Metrics {
+	return certexpiry.TrackerFor(sdk.opts.endpointConfig).Metrics()
+}
+
 //loadConfig load config from config backend when configs are not provided through opts
 func (sdk *FabricSDK) loadConfig(configProvider core.ConfigProvider) error {
 	if sdk.opts.CryptoSuiteConfig == nil || sdk.opts.endpointConfig == nil || sdk.opts.IdentityConfig == nil {
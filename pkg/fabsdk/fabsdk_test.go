@@ -7,14 +7,23 @@ SPDX-License-Identifier: Apache-2.0
 package fabsdk
 
 import (
+	"crypto/x509"
+	"math/big"
+	"net"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
 	configImpl "github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel/membership"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	mockapisdk "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/test/mocksdkapi"
 	"github.com/hyperledger/fabric-sdk-go/pkg/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/certexpiry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/sdkevents"
 	"github.com/pkg/errors"
 )
 
@@ -268,3 +277,138 @@ func TestWithConfigFailure(t *testing.T) {
 		t.Fatal("Expected failure due to invalid config")
 	}
 }
+
+func TestWithDialer(t *testing.T) {
+	dialer := func(address string, timeout time.Duration) (net.Conn, error) {
+		client, _ := net.Pipe()
+		return client, nil
+	}
+
+	sdk1, err := New(configImpl.FromFile(sdkConfigFile), WithDialer("pipe", dialer))
+	if err != nil {
+		t.Fatalf("Error initializing SDK: %s", err)
+	}
+	defer sdk1.Close()
+
+	sdk2, err := New(configImpl.FromFile(sdkConfigFile))
+	if err != nil {
+		t.Fatalf("Error initializing SDK: %s", err)
+	}
+	defer sdk2.Close()
+
+	if _, err := comm.DialerDialOption(sdk1.opts.endpointConfig, "pipe"); err != nil {
+		t.Fatalf("Expected dialer to be registered with the endpoint config of the SDK: %s", err)
+	}
+	if _, err := comm.DialerDialOption(sdk2.opts.endpointConfig, "pipe"); err == nil {
+		t.Fatal("Expected dialer not to be registered with the endpoint config of another SDK")
+	}
+
+	if _, err := New(configImpl.FromFile(sdkConfigFile), WithDialer("pipe", nil)); err == nil {
+		t.Fatal("Expected error for nil dialer")
+	}
+}
+
+func TestWithIdentityValidator(t *testing.T) {
+	validator := func(identity *membership.IdentityInfo) error { return nil }
+
+	sdk, err := New(configImpl.FromFile(sdkConfigFile), WithIdentityValidator("test", validator))
+	if err != nil {
+		t.Fatalf("Error initializing SDK: %s", err)
+	}
+	defer sdk.Close()
+
+	if _, err := New(configImpl.FromFile(sdkConfigFile), WithIdentityValidator("test", nil)); err == nil {
+		t.Fatal("Expected error for nil validator")
+	}
+
+	sdk = &FabricSDK{opts: options{identityValidators: []identityValidator{{name: "test", validator: validator}}}}
+	if err := sdk.registerIdentityValidators(&mocks.MockInfraProvider{}); err == nil {
+		t.Fatal("Expected error for infra provider which doesn't support identity validators")
+	}
+}
+
+func TestWithCertExpiry(t *testing.T) {
+	var events []*certexpiry.Event
+	sdk1, err := New(configImpl.FromFile(sdkConfigFile), WithCertExpiryThreshold(time.Hour), WithCertExpiryHandler(func(event *certexpiry.Event) {
+		events = append(events, event)
+	}))
+	if err != nil {
+		t.Fatalf("Error initializing SDK: %s", err)
+	}
+	defer sdk1.Close()
+
+	sdk2, err := New(configImpl.FromFile(sdkConfigFile))
+	if err != nil {
+		t.Fatalf("Error initializing SDK: %s", err)
+	}
+	defer sdk2.Close()
+
+	tracker1 := certexpiry.TrackerFor(sdk1.opts.endpointConfig)
+	tracker2 := certexpiry.TrackerFor(sdk2.opts.endpointConfig)
+	if tracker1 == tracker2 || tracker1 == certexpiry.DefaultTracker() {
+		t.Fatal("Expected each SDK to have its own certificate expiry tracker")
+	}
+	if tracker1.WarningThreshold() != time.Hour {
+		t.Fatalf("Expected threshold of SDK to be set")
+	}
+	if tracker2.WarningThreshold() != certexpiry.DefaultWarningThreshold || certexpiry.WarningThreshold() != certexpiry.DefaultWarningThreshold {
+		t.Fatalf("Expected threshold of other trackers not to be modified")
+	}
+
+	cert := &x509.Certificate{SerialNumber: big.NewInt(1), NotAfter: time.Now().Add(time.Minute)}
+	tracker2.Check(certexpiry.IdentitySource, "User1", cert)
+	if len(events) != 0 {
+		t.Fatal("Expected handler not to be notified of events of another SDK")
+	}
+	tracker1.Check(certexpiry.IdentitySource, "User1", cert)
+	if len(events) != 1 || len(sdk1.CertExpiryMetrics().Expiring) != 1 {
+		t.Fatal("Expected handler to be notified of events of the SDK")
+	}
+
+	if _, err := New(configImpl.FromFile(sdkConfigFile), WithCertExpiryThreshold(-time.Hour)); err == nil {
+		t.Fatal("Expected error for negative threshold")
+	}
+	if _, err := New(configImpl.FromFile(sdkConfigFile), WithCertExpiryHandler(nil)); err == nil {
+		t.Fatal("Expected error for nil handler")
+	}
+}
+
+func TestEventBus(t *testing.T) {
+	sdk1, err := New(configImpl.FromFile(sdkConfigFile))
+	if err != nil {
+		t.Fatalf("Error initializing SDK: %s", err)
+	}
+	defer sdk1.Close()
+
+	sdk2, err := New(configImpl.FromFile(sdkConfigFile))
+	if err != nil {
+		t.Fatalf("Error initializing SDK: %s", err)
+	}
+	defer sdk2.Close()
+
+	if sdk1.EventBus() == sdk2.EventBus() || sdk1.EventBus() == sdkevents.DefaultBus() {
+		t.Fatal("Expected each SDK to have its own event bus")
+	}
+
+	sub1, eventch1 := sdk1.EventBus().Subscribe(sdkevents.CertExpiring)
+	defer sdk1.EventBus().Unsubscribe(sub1)
+	sub2, eventch2 := sdk2.EventBus().Subscribe(sdkevents.CertExpiring)
+	defer sdk2.EventBus().Unsubscribe(sub2)
+
+	cert := &x509.Certificate{SerialNumber: big.NewInt(1), NotAfter: time.Now().Add(time.Minute)}
+	certexpiry.TrackerFor(sdk1.opts.endpointConfig).Check(certexpiry.IdentitySource, "User1", cert)
+
+	select {
+	case event := <-eventch1:
+		if event.SDKID != sdk1.EventBus().SDKID() {
+			t.Fatalf("Expected event to be tagged with the ID of the SDK: %s", event)
+		}
+	default:
+		t.Fatal("Expected certificate expiry event on the event bus of the SDK")
+	}
+	select {
+	case event := <-eventch2:
+		t.Fatalf("Unexpected event of another SDK: %s", event)
+	default:
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/orderer"
 	peerImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazycache"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/sdkevents"
 	"github.com/pkg/errors"
 )
 
@@ -46,6 +47,7 @@ type InfraProvider struct {
 	eventServiceCache cache
 	chCfgCache        cache
 	membershipCache   cache
+	validators        *membership.Validators
 }
 
 // New creates a InfraProvider enabling access to core Fabric objects and functionality.
@@ -54,6 +56,7 @@ func New(config fab.EndpointConfig) *InfraProvider {
 	sweepTime := config.Timeout(fab.CacheSweepInterval)
 	eventIdleTime := config.Timeout(fab.EventServiceIdle)
 	chConfigRefresh := config.Timeout(fab.ChannelConfigRefresh)
+	chConfigMaxStaleness := config.Timeout(fab.ChannelConfigMaxStaleness)
 	membershipRefresh := config.Timeout(fab.ChannelMembershipRefresh)
 
 	eventServiceCache := lazycache.New(
@@ -69,17 +72,32 @@ func New(config fab.EndpointConfig) *InfraProvider {
 		},
 	)
 
+	validators := membership.NewValidators()
+
 	return &InfraProvider{
 		commManager:       comm.NewCachingConnector(sweepTime, idleTime),
 		eventServiceCache: eventServiceCache,
-		chCfgCache:        chconfig.NewRefCache(chConfigRefresh),
-		membershipCache:   membership.NewRefCache(membershipRefresh),
+		chCfgCache:        chconfig.NewRefCache(chConfigRefresh, chconfig.WithMaxStaleness(chConfigMaxStaleness)),
+		membershipCache:   membership.NewRefCache(membershipRefresh, membership.WithLazyMSPManager(), membership.WithValidators(validators)),
+		validators:        validators,
 	}
 }
 
 // Initialize sets the provider context
 func (f *InfraProvider) Initialize(providers context.Providers) error {
 	f.providerContext = providers
+	f.commManager.SetEventBus(sdkevents.BusFor(providers.EndpointConfig()))
+
+	if peers := standbyPeers(providers.EndpointConfig()); len(peers) > 0 {
+		go f.warmStandbyPeers(peers)
+	}
+	return nil
+}
+
+// RegisterIdentityValidator registers a validator which is invoked, in addition to the MSP validation,
+// when the channel memberships of this provider validate an identity
+func (f *InfraProvider) RegisterIdentityValidator(name string, validator membership.Validator) error {
+	f.validators.Register(name, validator)
 	return nil
 }
 
@@ -111,6 +129,14 @@ func (f *InfraProvider) CreateEventService(ctx fab.ClientContext, channelID stri
 	if err != nil {
 		return nil, err
 	}
+	if channelID != "" {
+		// Connections to the event endpoints trust the TLS certificates of the channel's MSPs
+		m, err := f.CreateChannelMembership(ctx, channelID)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to get channel membership")
+		}
+		ctx = &channelClientContext{ClientContext: ctx, endpointConfig: membership.NewChannelEndpointConfig(ctx.EndpointConfig(), m)}
+	}
 	key, err := NewCacheKey(ctx, chnlCfg, opts...)
 	if err != nil {
 		return nil, err
@@ -146,7 +172,8 @@ func (f *InfraProvider) CreateChannelCfg(ctx fab.ClientContext, channelID string
 }
 
 // CreateChannelMembership returns and caches a channel member identifier
-// A membership reference is returned that refreshes with the configured interval
+// A membership reference is returned that refreshes with the configured interval. The MSPs of
+// the channel are set up when an identity is first validated.
 func (f *InfraProvider) CreateChannelMembership(ctx fab.ClientContext, channelID string) (fab.ChannelMembership, error) {
 	chCfgRef, err := f.loadChannelCfgRef(ctx, channelID)
 	if err != nil {
@@ -162,7 +189,12 @@ func (f *InfraProvider) CreateChannelMembership(ctx fab.ClientContext, channelID
 		return nil, err
 	}
 
-	return ref.(*membership.Ref), nil
+	membershipRef := ref.(*membership.Ref)
+	membershipRef.ListenForConfigUpdates(func() (fab.EventService, error) {
+		return f.CreateEventService(ctx, channelID)
+	})
+
+	return membershipRef, nil
 }
 
 // CreateChannelTransactor initializes the transactor
@@ -175,6 +207,17 @@ func (f *InfraProvider) CreatePeerFromConfig(peerCfg *fab.NetworkPeer) (fab.Peer
 	return peerImpl.New(f.providerContext.EndpointConfig(), peerImpl.FromPeerConfig(peerCfg))
 }
 
+// CreateChannelPeerFromConfig returns a new default implementation of Peer for the given channel.
+// Connections to the peer trust the TLS certificates of the channel's MSPs in addition to the
+// configured TLS root certificates.
+func (f *InfraProvider) CreateChannelPeerFromConfig(ctx fab.ClientContext, channelID string, peerCfg *fab.NetworkPeer) (fab.Peer, error) {
+	m, err := f.CreateChannelMembership(ctx, channelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get channel membership")
+	}
+	return peerImpl.New(membership.NewChannelEndpointConfig(f.providerContext.EndpointConfig(), m), peerImpl.FromPeerConfig(peerCfg))
+}
+
 // CreateOrdererFromConfig creates a default implementation of Orderer based on configuration.
 func (f *InfraProvider) CreateOrdererFromConfig(cfg *fab.OrdererConfig) (fab.Orderer, error) {
 	newOrderer, err := orderer.New(f.providerContext.EndpointConfig(), orderer.FromOrdererConfig(cfg))
@@ -184,6 +227,25 @@ func (f *InfraProvider) CreateOrdererFromConfig(cfg *fab.OrdererConfig) (fab.Ord
 	return newOrderer, nil
 }
 
+// CreateChannelOrdererFromConfig creates a default implementation of Orderer for the given channel.
+// Connections to the orderer trust the TLS certificates of the channel's MSPs in addition to the
+// configured TLS root certificates. The orderer of the system channel only trusts the configured
+// TLS root certificates.
+func (f *InfraProvider) CreateChannelOrdererFromConfig(ctx fab.ClientContext, channelID string, cfg *fab.OrdererConfig) (fab.Orderer, error) {
+	if channelID == "" {
+		return f.CreateOrdererFromConfig(cfg)
+	}
+	m, err := f.CreateChannelMembership(ctx, channelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get channel membership")
+	}
+	newOrderer, err := orderer.New(membership.NewChannelEndpointConfig(f.providerContext.EndpointConfig(), m), orderer.FromOrdererConfig(cfg))
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating orderer failed")
+	}
+	return newOrderer, nil
+}
+
 func (f *InfraProvider) loadChannelCfgRef(ctx fab.ClientContext, channelID string) (*chconfig.Ref, error) {
 	key, err := chconfig.NewCacheKey(ctx, f.CreateChannelConfig, channelID)
 	if err != nil {
@@ -210,3 +272,14 @@ func getEventClient(ctx context.Client, chConfig fab.ChannelCfg, opts ...options
 		return nil, errors.Errorf("unsupported event service type: %d", ctx.EndpointConfig().EventServiceType())
 	}
 }
+
+// channelClientContext is a client context whose endpoint config uses the TLS CA cert pool of a channel
+type channelClientContext struct {
+	fab.ClientContext
+	endpointConfig fab.EndpointConfig
+}
+
+// EndpointConfig returns the endpoint config of the channel
+func (c *channelClientContext) EndpointConfig() fab.EndpointConfig {
+	return c.endpointConfig
+}
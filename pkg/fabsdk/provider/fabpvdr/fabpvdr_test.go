@@ -69,6 +69,26 @@ func TestCreatePeerFromConfig(t *testing.T) {
 	verifyPeer(t, peer, url)
 }
 
+func TestCreateChannelOrdererFromConfig(t *testing.T) {
+	p := newInfraProvider(t)
+	ctx := mocks.NewMockProviderContext()
+	user := mspmocks.NewMockSigningIdentity("user", "user")
+	clientCtx := &mockClientContext{
+		Providers:       ctx,
+		SigningIdentity: user,
+	}
+
+	url := "grpc://localhost:9999"
+	o, err := p.CreateChannelOrdererFromConfig(clientCtx, "test", &fab.OrdererConfig{URL: url})
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost:9999", o.URL())
+
+	// System channel
+	o, err = p.CreateChannelOrdererFromConfig(clientCtx, "", &fab.OrdererConfig{URL: url})
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost:9999", o.URL())
+}
+
 func TestCreateMembership(t *testing.T) {
 	p := newInfraProvider(t)
 	ctx := mocks.NewMockProviderContext()
@@ -0,0 +1,65 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabpvdr
+
+import (
+	reqContext "context"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	peerImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
+)
+
+// standbyPeers returns the peers which are configured as hot-standby peers in any of the channels
+func standbyPeers(config fab.EndpointConfig) []fab.NetworkPeer {
+	networkConfig, err := config.NetworkConfig()
+	if err != nil {
+		logger.Warnf("Unable to get network config for standby peers: %s", err)
+		return nil
+	}
+
+	var peers []fab.NetworkPeer
+	added := make(map[string]bool)
+	for channelID := range networkConfig.Channels {
+		chPeers, err := config.ChannelPeers(channelID)
+		if err != nil {
+			logger.Warnf("Unable to get peers of channel [%s]: %s", channelID, err)
+			continue
+		}
+		for _, chPeer := range chPeers {
+			if chPeer.Standby && !added[chPeer.URL] {
+				added[chPeer.URL] = true
+				peers = append(peers, chPeer.NetworkPeer)
+			}
+		}
+	}
+	return peers
+}
+
+// warmStandbyPeers opens connections to the given hot-standby peers and keeps them open so
+// that requests can fail over to the peers without the latency of establishing a connection
+func (f *InfraProvider) warmStandbyPeers(peers []fab.NetworkPeer) {
+	timeout := f.providerContext.EndpointConfig().Timeout(fab.EndorserConnection)
+
+	for i := range peers {
+		peerCfg := &peers[i]
+
+		p, err := peerImpl.New(f.providerContext.EndpointConfig(), peerImpl.FromPeerConfig(peerCfg), peerImpl.WithCommManager(f.commManager))
+		if err != nil {
+			logger.Warnf("Unable to create standby peer [%s]: %s", peerCfg.URL, err)
+			continue
+		}
+
+		ctx, cancel := reqContext.WithTimeout(reqContext.Background(), timeout)
+		err = p.Warm(ctx)
+		cancel()
+		if err != nil {
+			logger.Warnf("Unable to open connection to standby peer [%s]: %s", peerCfg.URL, err)
+			continue
+		}
+		logger.Debugf("Opened connection to standby peer [%s]", peerCfg.URL)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package recorder records the proposal responses, transaction responses and events of a run of
+// the SDK to disk and replays them, which allows deterministic regression tests as well as offline
+// debugging of production incidents.
+//
+// The infra provider of the SDK is wrapped by a recorder or a replayer. A replayer serves the
+// recorded responses and events without connecting to peers, orderers or event services:
+//  - proposal responses are matched by peer, channel, chaincode and arguments (in order)
+//  - transaction responses are matched by channel (in order)
+//  - events are matched by channel, event type and registration (in order of registration)
+//
+// Basic flow:
+//
+//  rec := recorder.NewRecorder()
+//  sdk, err := fabsdk.New(configProvider, fabsdk.WithCorePkg(recorder.NewCorePkg(defcore.NewProviderFactory(), rec)))
+//  ... run the test or the application
+//  err = rec.Recording().Save("run.json")
+//
+//  recording, err := recorder.Load("run.json")
+//  replayer := recorder.NewReplayer(recording)
+//  sdk, err := fabsdk.New(configProvider, fabsdk.WithCorePkg(recorder.NewCorePkg(defcore.NewProviderFactory(), replayer)))
+//
+// Since transaction IDs differ between runs, the transaction IDs of replayed transaction status
+// and chaincode events are replaced with the IDs of the transactions of the replay.
+package recorder
+
+import (
+	reqContext "context"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel/membership"
+	sdkApi "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/api"
+	"github.com/pkg/errors"
+)
+
+// Mode is the mode (recording or replaying) of a wrapped infra provider
+type Mode interface {
+	wrapPeer(peer fab.Peer) fab.Peer
+	wrapTransactor(channelID string, transactor fab.Transactor) fab.Transactor
+	eventService(channelID string, create func() (fab.EventService, error)) (fab.EventService, error)
+}
+
+type providerInit interface {
+	Initialize(providers context.Providers) error
+}
+
+type identityValidatorRegistrar interface {
+	RegisterIdentityValidator(name string, validator membership.Validator) error
+}
+
+// InfraProvider wraps the peers, transactors and event services of an infra provider
+type InfraProvider struct {
+	fab.InfraProvider
+	mode Mode
+}
+
+// NewInfraProvider returns a new infra provider which records or replays the requests of
+// the given infra provider, depending on the mode
+func NewInfraProvider(infraProvider fab.InfraProvider, mode Mode) *InfraProvider {
+	return &InfraProvider{InfraProvider: infraProvider, mode: mode}
+}
+
+// Initialize initializes the wrapped infra provider
+func (p *InfraProvider) Initialize(providers context.Providers) error {
+	if pi, ok := p.InfraProvider.(providerInit); ok {
+		return pi.Initialize(providers)
+	}
+	return nil
+}
+
+// RegisterIdentityValidator registers the given identity validator with the wrapped infra provider
+func (p *InfraProvider) RegisterIdentityValidator(name string, validator membership.Validator) error {
+	if r, ok := p.InfraProvider.(identityValidatorRegistrar); ok {
+		return r.RegisterIdentityValidator(name, validator)
+	}
+	return errors.New("wrapped infra provider does not support identity validators")
+}
+
+// CreatePeerFromConfig returns a new peer based on the given configuration
+func (p *InfraProvider) CreatePeerFromConfig(peerCfg *fab.NetworkPeer) (fab.Peer, error) {
+	peer, err := p.InfraProvider.CreatePeerFromConfig(peerCfg)
+	if err != nil {
+		return nil, err
+	}
+	return p.mode.wrapPeer(peer), nil
+}
+
+// CreateChannelPeerFromConfig returns a new peer of the given channel based on the given configuration
+func (p *InfraProvider) CreateChannelPeerFromConfig(ctx fab.ClientContext, channelID string, peerCfg *fab.NetworkPeer) (fab.Peer, error) {
+	peer, err := p.InfraProvider.CreateChannelPeerFromConfig(ctx, channelID, peerCfg)
+	if err != nil {
+		return nil, err
+	}
+	return p.mode.wrapPeer(peer), nil
+}
+
+// CreateChannelTransactor returns a new transactor for the given channel
+func (p *InfraProvider) CreateChannelTransactor(reqCtx reqContext.Context, cfg fab.ChannelCfg) (fab.Transactor, error) {
+	transactor, err := p.InfraProvider.CreateChannelTransactor(reqCtx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return p.mode.wrapTransactor(cfg.ID(), transactor), nil
+}
+
+// CreateEventService returns a new event service for the given channel
+func (p *InfraProvider) CreateEventService(ctx fab.ClientContext, channelID string, opts ...options.Opt) (fab.EventService, error) {
+	return p.mode.eventService(channelID, func() (fab.EventService, error) {
+		return p.InfraProvider.CreateEventService(ctx, channelID, opts...)
+	})
+}
+
+// corePkg wraps the infra provider of a core provider factory
+type corePkg struct {
+	sdkApi.CoreProviderFactory
+	mode Mode
+}
+
+// NewCorePkg returns a core provider factory whose infra provider records or replays
+// the requests of the infra provider of the given factory, depending on the mode
+func NewCorePkg(factory sdkApi.CoreProviderFactory, mode Mode) sdkApi.CoreProviderFactory {
+	return &corePkg{CoreProviderFactory: factory, mode: mode}
+}
+
+// CreateInfraProvider returns the wrapped infra provider
+func (f *corePkg) CreateInfraProvider(config fab.EndpointConfig) (fab.InfraProvider, error) {
+	infraProvider, err := f.CoreProviderFactory.CreateInfraProvider(config)
+	if err != nil {
+		return nil, err
+	}
+	return NewInfraProvider(infraProvider, f.mode), nil
+}
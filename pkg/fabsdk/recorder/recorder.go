@@ -0,0 +1,258 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package recorder
+
+import (
+	reqContext "context"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+var logger = logging.NewLogger("fabsdk")
+
+// Recorder records the proposal responses, transaction responses and events of a run
+type Recorder struct {
+	lock          sync.Mutex
+	recording     Recording
+	registrations map[string]int
+}
+
+// NewRecorder returns a new recorder with an empty recording
+func NewRecorder() *Recorder {
+	return &Recorder{registrations: make(map[string]int)}
+}
+
+// Recording returns a snapshot of the recording
+func (r *Recorder) Recording() *Recording {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return &Recording{
+		Proposals:    append([]*ProposalRecord{}, r.recording.Proposals...),
+		Transactions: append([]*TransactionRecord{}, r.recording.Transactions...),
+		Events:       append([]*EventRecord{}, r.recording.Events...),
+	}
+}
+
+func (r *Recorder) wrapPeer(peer fab.Peer) fab.Peer {
+	return &recordingPeer{Peer: peer, recorder: r}
+}
+
+func (r *Recorder) wrapTransactor(channelID string, transactor fab.Transactor) fab.Transactor {
+	return &recordingTransactor{Transactor: transactor, channelID: channelID, recorder: r}
+}
+
+func (r *Recorder) eventService(channelID string, create func() (fab.EventService, error)) (fab.EventService, error) {
+	eventService, err := create()
+	if err != nil {
+		return nil, err
+	}
+	return &recordingEventService{EventService: eventService, channelID: channelID, recorder: r}, nil
+}
+
+func (r *Recorder) addProposal(record *ProposalRecord) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.recording.Proposals = append(r.recording.Proposals, record)
+}
+
+func (r *Recorder) addTransaction(record *TransactionRecord) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.recording.Transactions = append(r.recording.Transactions, record)
+}
+
+func (r *Recorder) addEvent(record *EventRecord) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.recording.Events = append(r.recording.Events, record)
+}
+
+// nextRegistration returns the number of the next registration for the given channel and event type
+func (r *Recorder) nextRegistration(channelID, eventType string) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	key := channelID + "/" + eventType
+	n := r.registrations[key]
+	r.registrations[key] = n + 1
+	return n
+}
+
+type recordingPeer struct {
+	fab.Peer
+	recorder *Recorder
+}
+
+// ProcessTransactionProposal sends the proposal to the peer and records the response
+func (p *recordingPeer) ProcessTransactionProposal(reqCtx reqContext.Context, request fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
+	response, err := p.Peer.ProcessTransactionProposal(reqCtx, request)
+
+	info, ierr := newProposalInfo(request)
+	if ierr != nil {
+		logger.Warnf("Proposal to [%s] not recorded: %s", p.URL(), ierr)
+		return response, err
+	}
+
+	record := &ProposalRecord{
+		Peer:        p.URL(),
+		ChannelID:   info.channelID,
+		ChaincodeID: info.chaincodeID,
+		Args:        info.args,
+		TxID:        info.txID,
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	if response != nil {
+		record.Endorser = response.Endorser
+		record.Status = response.Status
+		record.ChaincodeStatus = response.ChaincodeStatus
+		if response.ProposalResponse != nil {
+			if record.ProposalResponse, ierr = proto.Marshal(response.ProposalResponse); ierr != nil {
+				logger.Warnf("Proposal response of [%s] not recorded: %s", p.URL(), ierr)
+			}
+		}
+	}
+	p.recorder.addProposal(record)
+
+	return response, err
+}
+
+type recordingTransactor struct {
+	fab.Transactor
+	channelID string
+	recorder  *Recorder
+}
+
+// SendTransaction sends the transaction to the orderers and records the response
+func (t *recordingTransactor) SendTransaction(tx *fab.Transaction) (*fab.TransactionResponse, error) {
+	response, err := t.Transactor.SendTransaction(tx)
+
+	record := &TransactionRecord{ChannelID: t.channelID}
+	if tx != nil && tx.Proposal != nil {
+		record.TxID = string(tx.Proposal.TxnID)
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	if response != nil {
+		record.Orderer = response.Orderer
+	}
+	t.recorder.addTransaction(record)
+
+	return response, err
+}
+
+type recordingEventService struct {
+	fab.EventService
+	channelID string
+	recorder  *Recorder
+}
+
+func (s *recordingEventService) newRecord(eventType string, registration int, sourceURL string) *EventRecord {
+	return &EventRecord{ChannelID: s.channelID, Type: eventType, Registration: registration, SourceURL: sourceURL}
+}
+
+// RegisterBlockEvent registers for block events and records the events
+func (s *recordingEventService) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
+	reg, eventch, err := s.EventService.RegisterBlockEvent(filter...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	n := s.recorder.nextRegistration(s.channelID, BlockEventType)
+	out := make(chan *fab.BlockEvent, cap(eventch))
+	go func() {
+		defer close(out)
+		for event := range eventch {
+			record := s.newRecord(BlockEventType, n, event.SourceURL)
+			if block, err := proto.Marshal(event.Block); err == nil {
+				record.Block = block
+			} else {
+				logger.Warnf("Block event not recorded: %s", err)
+			}
+			s.recorder.addEvent(record)
+			out <- event
+		}
+	}()
+	return reg, out, nil
+}
+
+// RegisterFilteredBlockEvent registers for filtered block events and records the events
+func (s *recordingEventService) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
+	reg, eventch, err := s.EventService.RegisterFilteredBlockEvent()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	n := s.recorder.nextRegistration(s.channelID, FilteredBlockEventType)
+	out := make(chan *fab.FilteredBlockEvent, cap(eventch))
+	go func() {
+		defer close(out)
+		for event := range eventch {
+			record := s.newRecord(FilteredBlockEventType, n, event.SourceURL)
+			if filteredBlock, err := proto.Marshal(event.FilteredBlock); err == nil {
+				record.FilteredBlock = filteredBlock
+			} else {
+				logger.Warnf("Filtered block event not recorded: %s", err)
+			}
+			s.recorder.addEvent(record)
+			out <- event
+		}
+	}()
+	return reg, out, nil
+}
+
+// RegisterChaincodeEvent registers for chaincode events and records the events
+func (s *recordingEventService) RegisterChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
+	reg, eventch, err := s.EventService.RegisterChaincodeEvent(ccID, eventFilter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	n := s.recorder.nextRegistration(s.channelID, ChaincodeEventType)
+	out := make(chan *fab.CCEvent, cap(eventch))
+	go func() {
+		defer close(out)
+		for event := range eventch {
+			record := s.newRecord(ChaincodeEventType, n, event.SourceURL)
+			record.CCEvent = event
+			s.recorder.addEvent(record)
+			out <- event
+		}
+	}()
+	return reg, out, nil
+}
+
+// RegisterTxStatusEvent registers for transaction status events and records the events
+func (s *recordingEventService) RegisterTxStatusEvent(txID string) (fab.Registration, <-chan *fab.TxStatusEvent, error) {
+	reg, eventch, err := s.EventService.RegisterTxStatusEvent(txID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	n := s.recorder.nextRegistration(s.channelID, TxStatusEventType)
+	out := make(chan *fab.TxStatusEvent, cap(eventch))
+	go func() {
+		defer close(out)
+		for event := range eventch {
+			record := s.newRecord(TxStatusEventType, n, event.SourceURL)
+			record.TxStatus = event
+			s.recorder.addEvent(record)
+			out <- event
+		}
+	}()
+	return reg, out, nil
+}
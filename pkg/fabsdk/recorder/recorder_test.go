@@ -0,0 +1,136 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package recorder
+
+import (
+	reqContext "context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+const testChannel = "mychannel"
+
+func newProposalRequest(t *testing.T, ctx *mocks.MockContext) (fab.ProcessProposalRequest, fab.TransactionID) {
+	txh, err := txn.NewHeader(ctx, testChannel)
+	require.NoError(t, err)
+
+	proposal, err := txn.CreateChaincodeInvokeProposal(txh, fab.ChaincodeInvokeRequest{
+		ChaincodeID: "example_cc",
+		Fcn:         "query",
+		Args:        [][]byte{[]byte("a")},
+	})
+	require.NoError(t, err)
+
+	proposalBytes, err := proto.Marshal(proposal.Proposal)
+	require.NoError(t, err)
+
+	return fab.ProcessProposalRequest{SignedProposal: &pb.SignedProposal{ProposalBytes: proposalBytes}}, proposal.TxnID
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	ctx := mocks.NewMockContext(mspmocks.NewMockSigningIdentity("user1", "Org1MSP"))
+	reqCtx := reqContext.Background()
+
+	// Record
+	rec := NewRecorder()
+
+	request, txID := newProposalRequest(t, ctx)
+	peer := rec.wrapPeer(&mocks.MockPeer{MockURL: "grpc://peer1:7051", Status: 200, Payload: []byte("10")})
+	_, err := peer.ProcessTransactionProposal(reqCtx, request)
+	require.NoError(t, err)
+
+	failingPeer := rec.wrapPeer(&mocks.MockPeer{MockURL: "grpc://peer2:7051", Error: errors.New("peer unavailable")})
+	_, err = failingPeer.ProcessTransactionProposal(reqCtx, request)
+	require.Error(t, err)
+
+	transactor := rec.wrapTransactor(testChannel, &mocks.MockTransactor{})
+	_, err = transactor.SendTransaction(&fab.Transaction{Proposal: &fab.TransactionProposal{TxnID: txID}})
+	require.NoError(t, err)
+
+	mockEventService := mocks.NewMockEventService()
+	eventService, err := rec.eventService(testChannel, func() (fab.EventService, error) { return mockEventService, nil })
+	require.NoError(t, err)
+	_, eventch, err := eventService.RegisterTxStatusEvent(string(txID))
+	require.NoError(t, err)
+
+	reg := <-mockEventService.TxStatusRegCh
+	reg.Eventch <- &fab.TxStatusEvent{TxID: string(txID), TxValidationCode: pb.TxValidationCode_MVCC_READ_CONFLICT}
+	close(reg.Eventch)
+
+	event, ok := <-eventch
+	require.True(t, ok)
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, event.TxValidationCode)
+	_, ok = <-eventch
+	assert.False(t, ok, "expecting event channel to be closed")
+
+	// Save and load
+	file, err := ioutil.TempFile("", "recording")
+	require.NoError(t, err)
+	file.Close() //nolint
+	defer os.Remove(file.Name())
+
+	require.NoError(t, rec.Recording().Save(file.Name()))
+	recording, err := Load(file.Name())
+	require.NoError(t, err)
+	assert.Len(t, recording.Proposals, 2)
+	assert.Len(t, recording.Transactions, 1)
+	assert.Len(t, recording.Events, 1)
+
+	// Replay with a new transaction ID
+	replayer := NewReplayer(recording)
+
+	request, txID = newProposalRequest(t, ctx)
+	peer = replayer.wrapPeer(&mocks.MockPeer{MockURL: "grpc://peer1:7051", Error: errors.New("peer must not be called")})
+	response, err := peer.ProcessTransactionProposal(reqCtx, request)
+	require.NoError(t, err)
+	assert.Equal(t, int32(200), response.Status)
+	assert.Equal(t, []byte("10"), response.ProposalResponse.Response.Payload)
+
+	_, err = peer.ProcessTransactionProposal(reqCtx, request)
+	assert.Error(t, err, "expecting error since the recorded response was already used")
+
+	failingPeer = replayer.wrapPeer(&mocks.MockPeer{MockURL: "grpc://peer2:7051"})
+	_, err = failingPeer.ProcessTransactionProposal(reqCtx, request)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "peer unavailable")
+
+	transactor = replayer.wrapTransactor(testChannel, &mocks.MockTransactor{})
+	txResponse, err := transactor.SendTransaction(&fab.Transaction{Proposal: &fab.TransactionProposal{TxnID: txID}})
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", txResponse.Orderer)
+
+	_, err = transactor.SendTransaction(&fab.Transaction{Proposal: &fab.TransactionProposal{TxnID: txID}})
+	assert.Error(t, err, "expecting error since there are no more recorded transactions")
+
+	eventService, err = replayer.eventService(testChannel, func() (fab.EventService, error) {
+		return nil, errors.New("event service must not be created")
+	})
+	require.NoError(t, err)
+	registration, eventch, err := eventService.RegisterTxStatusEvent(string(txID))
+	require.NoError(t, err)
+
+	event, ok = <-eventch
+	require.True(t, ok)
+	assert.Equal(t, string(txID), event.TxID)
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, event.TxValidationCode)
+
+	eventService.Unregister(registration)
+	_, ok = <-eventch
+	assert.False(t, ok, "expecting event channel to be closed")
+}
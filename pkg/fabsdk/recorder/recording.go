@@ -0,0 +1,156 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// Event types
+const (
+	BlockEventType         = "block"
+	FilteredBlockEventType = "filteredblock"
+	ChaincodeEventType     = "chaincode"
+	TxStatusEventType      = "txstatus"
+)
+
+// Recording holds the proposal responses, transaction responses and events of a run
+type Recording struct {
+	Proposals    []*ProposalRecord    `json:"proposals"`
+	Transactions []*TransactionRecord `json:"transactions"`
+	Events       []*EventRecord       `json:"events"`
+}
+
+// ProposalRecord is a proposal sent to a peer and the response of the peer
+type ProposalRecord struct {
+	Peer        string   `json:"peer"`
+	ChannelID   string   `json:"channelId"`
+	ChaincodeID string   `json:"chaincodeId"`
+	Args        [][]byte `json:"args"`
+	TxID        string   `json:"txId"`
+
+	Endorser         string `json:"endorser,omitempty"`
+	Status           int32  `json:"status,omitempty"`
+	ChaincodeStatus  int32  `json:"chaincodeStatus,omitempty"`
+	ProposalResponse []byte `json:"proposalResponse,omitempty"`
+	Error            string `json:"error,omitempty"`
+}
+
+// TransactionRecord is a transaction sent to the orderers of a channel and the response
+type TransactionRecord struct {
+	ChannelID string `json:"channelId"`
+	TxID      string `json:"txId"`
+	Orderer   string `json:"orderer,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// EventRecord is an event received by an event registration. Registrations are numbered
+// per channel and event type in the order in which they were made.
+type EventRecord struct {
+	ChannelID    string `json:"channelId"`
+	Type         string `json:"type"`
+	Registration int    `json:"registration"`
+	SourceURL    string `json:"sourceUrl,omitempty"`
+
+	Block         []byte             `json:"block,omitempty"`
+	FilteredBlock []byte             `json:"filteredBlock,omitempty"`
+	CCEvent       *fab.CCEvent       `json:"ccEvent,omitempty"`
+	TxStatus      *fab.TxStatusEvent `json:"txStatus,omitempty"`
+}
+
+// Load reads a recording from the given file
+func Load(path string) (*Recording, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read recording [%s] failed", path)
+	}
+
+	recording := &Recording{}
+	if err := json.Unmarshal(data, recording); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal recording [%s] failed", path)
+	}
+	return recording, nil
+}
+
+// Save writes the recording to the given file
+func (r *Recording) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshal recording failed")
+	}
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return errors.Wrapf(err, "write recording [%s] failed", path)
+	}
+	return nil
+}
+
+// matches returns true if the record is a recorded response of the given peer to the given proposal
+func (r *ProposalRecord) matches(peer string, proposal *proposalInfo) bool {
+	if r.Peer != peer || r.ChannelID != proposal.channelID || r.ChaincodeID != proposal.chaincodeID || len(r.Args) != len(proposal.args) {
+		return false
+	}
+	for i, arg := range r.Args {
+		if !bytes.Equal(arg, proposal.args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// proposalInfo is the information by which proposals are matched during replay
+type proposalInfo struct {
+	channelID   string
+	chaincodeID string
+	txID        string
+	args        [][]byte
+}
+
+func newProposalInfo(request fab.ProcessProposalRequest) (*proposalInfo, error) {
+	if request.SignedProposal == nil {
+		return nil, errors.New("signed proposal is nil")
+	}
+	proposal := &pb.Proposal{}
+	if err := proto.Unmarshal(request.SignedProposal.ProposalBytes, proposal); err != nil {
+		return nil, errors.Wrap(err, "unmarshal proposal failed")
+	}
+	header, err := utils.GetHeader(proposal.Header)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal proposal header failed")
+	}
+	chHeader, err := utils.UnmarshalChannelHeader(header.ChannelHeader)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal channel header failed")
+	}
+	ccProposalPayload, err := utils.GetChaincodeProposalPayload(proposal.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal chaincode proposal payload failed")
+	}
+	cis := &pb.ChaincodeInvocationSpec{}
+	if err := proto.Unmarshal(ccProposalPayload.Input, cis); err != nil {
+		return nil, errors.Wrap(err, "unmarshal chaincode invocation spec failed")
+	}
+
+	info := &proposalInfo{channelID: chHeader.ChannelId, txID: chHeader.TxId}
+	if spec := cis.ChaincodeSpec; spec != nil {
+		if spec.ChaincodeId != nil {
+			info.chaincodeID = spec.ChaincodeId.Name
+		}
+		if spec.Input != nil {
+			info.args = spec.Input.Args
+		}
+	}
+	return info, nil
+}
@@ -0,0 +1,273 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package recorder
+
+import (
+	reqContext "context"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// Replayer serves the proposal responses, transaction responses and events of a recording
+type Replayer struct {
+	lock          sync.Mutex
+	recording     *Recording
+	usedProposals map[int]bool
+	transactions  map[string]int
+	registrations map[string]int
+	txIDs         map[string]string
+}
+
+// NewReplayer returns a new replayer of the given recording
+func NewReplayer(recording *Recording) *Replayer {
+	return &Replayer{
+		recording:     recording,
+		usedProposals: make(map[int]bool),
+		transactions:  make(map[string]int),
+		registrations: make(map[string]int),
+		txIDs:         make(map[string]string),
+	}
+}
+
+func (r *Replayer) wrapPeer(peer fab.Peer) fab.Peer {
+	return &replayPeer{Peer: peer, replayer: r}
+}
+
+func (r *Replayer) wrapTransactor(channelID string, transactor fab.Transactor) fab.Transactor {
+	return &replayTransactor{Transactor: transactor, channelID: channelID, replayer: r}
+}
+
+// eventService returns an event service which replays the recorded events of the channel.
+// The event service of the infra provider isn't created, so no connection is made.
+func (r *Replayer) eventService(channelID string, create func() (fab.EventService, error)) (fab.EventService, error) {
+	return &replayEventService{replayer: r, channelID: channelID, registrations: make(map[fab.Registration]func())}, nil
+}
+
+// nextProposal returns the first unused recorded response of the given peer to the given proposal.
+// The transaction ID of the recorded proposal is mapped to the transaction ID of the replayed proposal.
+func (r *Replayer) nextProposal(peer string, proposal *proposalInfo) (*ProposalRecord, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for i, record := range r.recording.Proposals {
+		if r.usedProposals[i] || !record.matches(peer, proposal) {
+			continue
+		}
+		r.usedProposals[i] = true
+		if record.TxID != "" {
+			r.txIDs[record.TxID] = proposal.txID
+		}
+		return record, true
+	}
+	return nil, false
+}
+
+// nextTransaction returns the next recorded transaction response of the given channel
+func (r *Replayer) nextTransaction(channelID string) (*TransactionRecord, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	n := 0
+	for _, record := range r.recording.Transactions {
+		if record.ChannelID != channelID {
+			continue
+		}
+		if n == r.transactions[channelID] {
+			r.transactions[channelID]++
+			return record, true
+		}
+		n++
+	}
+	return nil, false
+}
+
+// nextEvents returns the recorded events of the next registration for the given channel and event type
+func (r *Replayer) nextEvents(channelID, eventType string) []*EventRecord {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	key := channelID + "/" + eventType
+	n := r.registrations[key]
+	r.registrations[key] = n + 1
+
+	var events []*EventRecord
+	for _, record := range r.recording.Events {
+		if record.ChannelID == channelID && record.Type == eventType && record.Registration == n {
+			events = append(events, record)
+		}
+	}
+	return events
+}
+
+// replayedTxID returns the ID of the replayed transaction of a recorded transaction
+func (r *Replayer) replayedTxID(txID string) string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if replayedTxID, ok := r.txIDs[txID]; ok {
+		return replayedTxID
+	}
+	return txID
+}
+
+type replayPeer struct {
+	fab.Peer
+	replayer *Replayer
+}
+
+// ProcessTransactionProposal returns the recorded response of the peer to the proposal
+func (p *replayPeer) ProcessTransactionProposal(reqCtx reqContext.Context, request fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
+	info, err := newProposalInfo(request)
+	if err != nil {
+		return nil, err
+	}
+
+	record, ok := p.replayer.nextProposal(p.URL(), info)
+	if !ok {
+		return nil, errors.Errorf("no recorded response of peer [%s] to proposal for chaincode [%s] on channel [%s]", p.URL(), info.chaincodeID, info.channelID)
+	}
+	if record.Error != "" {
+		return nil, errors.New(record.Error)
+	}
+
+	response := &fab.TransactionProposalResponse{
+		Endorser:        record.Endorser,
+		Status:          record.Status,
+		ChaincodeStatus: record.ChaincodeStatus,
+	}
+	if len(record.ProposalResponse) > 0 {
+		response.ProposalResponse = &pb.ProposalResponse{}
+		if err := proto.Unmarshal(record.ProposalResponse, response.ProposalResponse); err != nil {
+			return nil, errors.Wrap(err, "unmarshal recorded proposal response failed")
+		}
+	}
+	return response, nil
+}
+
+type replayTransactor struct {
+	fab.Transactor
+	channelID string
+	replayer  *Replayer
+}
+
+// SendTransaction returns the next recorded transaction response of the channel
+func (t *replayTransactor) SendTransaction(tx *fab.Transaction) (*fab.TransactionResponse, error) {
+	record, ok := t.replayer.nextTransaction(t.channelID)
+	if !ok {
+		return nil, errors.Errorf("no recorded transaction response on channel [%s]", t.channelID)
+	}
+	if record.Error != "" {
+		return nil, errors.New(record.Error)
+	}
+	return &fab.TransactionResponse{Orderer: record.Orderer}, nil
+}
+
+// replayEventService delivers the recorded events of the registrations with the same number.
+// The events are delivered as soon as the registration is made.
+type replayEventService struct {
+	replayer      *Replayer
+	channelID     string
+	lock          sync.Mutex
+	registrations map[fab.Registration]func()
+}
+
+type registration struct {
+	eventType string
+}
+
+func (s *replayEventService) register(eventType string, closeChannel func()) fab.Registration {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	reg := &registration{eventType: eventType}
+	s.registrations[reg] = closeChannel
+	return reg
+}
+
+// RegisterBlockEvent registers for the recorded block events
+func (s *replayEventService) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
+	records := s.replayer.nextEvents(s.channelID, BlockEventType)
+
+	eventch := make(chan *fab.BlockEvent, len(records))
+	for _, record := range records {
+		block := &common.Block{}
+		if err := proto.Unmarshal(record.Block, block); err != nil {
+			return nil, nil, errors.Wrap(err, "unmarshal recorded block failed")
+		}
+		if len(filter) > 0 && filter[0] != nil && !filter[0](block) {
+			continue
+		}
+		eventch <- &fab.BlockEvent{Block: block, SourceURL: record.SourceURL}
+	}
+	return s.register(BlockEventType, func() { close(eventch) }), eventch, nil
+}
+
+// RegisterFilteredBlockEvent registers for the recorded filtered block events
+func (s *replayEventService) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
+	records := s.replayer.nextEvents(s.channelID, FilteredBlockEventType)
+
+	eventch := make(chan *fab.FilteredBlockEvent, len(records))
+	for _, record := range records {
+		filteredBlock := &pb.FilteredBlock{}
+		if err := proto.Unmarshal(record.FilteredBlock, filteredBlock); err != nil {
+			return nil, nil, errors.Wrap(err, "unmarshal recorded filtered block failed")
+		}
+		eventch <- &fab.FilteredBlockEvent{FilteredBlock: filteredBlock, SourceURL: record.SourceURL}
+	}
+	return s.register(FilteredBlockEventType, func() { close(eventch) }), eventch, nil
+}
+
+// RegisterChaincodeEvent registers for the recorded chaincode events
+func (s *replayEventService) RegisterChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
+	records := s.replayer.nextEvents(s.channelID, ChaincodeEventType)
+
+	eventch := make(chan *fab.CCEvent, len(records))
+	for _, record := range records {
+		if record.CCEvent == nil {
+			continue
+		}
+		event := *record.CCEvent
+		event.TxID = s.replayer.replayedTxID(event.TxID)
+		eventch <- &event
+	}
+	return s.register(ChaincodeEventType, func() { close(eventch) }), eventch, nil
+}
+
+// RegisterTxStatusEvent registers for the recorded transaction status events.
+// The events are delivered with the given transaction ID.
+func (s *replayEventService) RegisterTxStatusEvent(txID string) (fab.Registration, <-chan *fab.TxStatusEvent, error) {
+	records := s.replayer.nextEvents(s.channelID, TxStatusEventType)
+
+	eventch := make(chan *fab.TxStatusEvent, len(records))
+	for _, record := range records {
+		if record.TxStatus == nil {
+			continue
+		}
+		event := *record.TxStatus
+		event.TxID = txID
+		eventch <- &event
+	}
+	return s.register(TxStatusEventType, func() { close(eventch) }), eventch, nil
+}
+
+// Unregister removes the registration and closes its event channel
+func (s *replayEventService) Unregister(reg fab.Registration) {
+	s.lock.Lock()
+	closeChannel, ok := s.registrations[reg]
+	delete(s.registrations, reg)
+	s.lock.Unlock()
+
+	if ok {
+		closeChannel()
+	}
+}
@@ -0,0 +1,240 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package shared allows several SDK instances of a process (e.g. one per tenant or per identity)
+// to share the infra provider, which holds the GRPC connection cache, the channel configuration
+// and membership caches and the event service cache, as well as the discovery providers and their
+// caches, instead of each SDK instance keeping its own connections and caches.
+//
+// The shared providers are created by the first SDK instance and closed when the last SDK
+// instance which uses them is closed:
+//
+//  providers := shared.New()
+//  sdk1, err := fabsdk.New(configProvider,
+//      fabsdk.WithCorePkg(shared.NewCorePkg(defcore.NewProviderFactory(), providers)),
+//      fabsdk.WithServicePkg(shared.NewServicePkg(defsvc.NewProviderFactory(), providers)))
+//  ...
+//  sdk2, err := fabsdk.New(configProvider,
+//      fabsdk.WithCorePkg(shared.NewCorePkg(defcore.NewProviderFactory(), providers)),
+//      fabsdk.WithServicePkg(shared.NewServicePkg(defsvc.NewProviderFactory(), providers)))
+//
+// The shared providers are initialized with the providers of the first SDK instance, so the SDK
+// instances which share them must use the same endpoint configuration.
+package shared
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	sdkApi "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/api"
+)
+
+var logger = logging.NewLogger("fabsdk")
+
+type providerInit interface {
+	Initialize(providers context.Providers) error
+}
+
+type closeable interface {
+	Close()
+}
+
+// Providers holds the providers shared by the SDK instances created with its core and service packages
+type Providers struct {
+	infra          sharedProvider
+	discovery      sharedProvider
+	localDiscovery sharedProvider
+}
+
+// New returns a new set of shared providers. The providers are created by the first SDK instance.
+func New() *Providers {
+	return &Providers{
+		infra:          sharedProvider{name: "infra provider"},
+		discovery:      sharedProvider{name: "discovery provider"},
+		localDiscovery: sharedProvider{name: "local discovery provider"},
+	}
+}
+
+// sharedProvider is a reference counted provider, which is created and initialized once and
+// closed when its last reference is released
+type sharedProvider struct {
+	name        string
+	lock        sync.Mutex
+	provider    interface{}
+	refs        int
+	initialized bool
+}
+
+// acquire returns the shared provider, which is created with the given function if it doesn't exist
+func (s *sharedProvider) acquire(create func() (interface{}, error)) (interface{}, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.provider == nil {
+		provider, err := create()
+		if err != nil {
+			return nil, err
+		}
+		logger.Debugf("Created shared %s", s.name)
+		s.provider = provider
+		s.initialized = false
+	}
+	s.refs++
+	return s.provider, nil
+}
+
+// initialize initializes the shared provider with the providers of the first SDK instance
+func (s *sharedProvider) initialize(providers context.Providers) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.initialized {
+		return nil
+	}
+	if pi, ok := s.provider.(providerInit); ok {
+		if err := pi.Initialize(providers); err != nil {
+			return err
+		}
+	}
+	s.initialized = true
+	return nil
+}
+
+// release releases a reference to the shared provider and closes it if it was the last one
+func (s *sharedProvider) release() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.refs--
+	if s.refs > 0 {
+		return
+	}
+
+	logger.Debugf("Closing shared %s", s.name)
+	if c, ok := s.provider.(closeable); ok {
+		c.Close()
+	}
+	s.provider = nil
+}
+
+// ref is the reference of an SDK instance to a shared provider
+type ref struct {
+	shared *sharedProvider
+	once   sync.Once
+}
+
+func (r *ref) release() {
+	r.once.Do(r.shared.release)
+}
+
+type infraProvider struct {
+	fab.InfraProvider
+	ref *ref
+}
+
+// Initialize initializes the shared infra provider unless it's already initialized
+func (p *infraProvider) Initialize(providers context.Providers) error {
+	return p.ref.shared.initialize(providers)
+}
+
+// Close releases the reference of the SDK instance to the shared infra provider, which is closed
+// when the last SDK instance is closed
+func (p *infraProvider) Close() {
+	p.ref.release()
+}
+
+type discoveryProvider struct {
+	fab.DiscoveryProvider
+	ref *ref
+}
+
+// Initialize initializes the shared discovery provider unless it's already initialized
+func (p *discoveryProvider) Initialize(providers context.Providers) error {
+	return p.ref.shared.initialize(providers)
+}
+
+// Close releases the reference of the SDK instance to the shared discovery provider
+func (p *discoveryProvider) Close() {
+	p.ref.release()
+}
+
+type localDiscoveryProvider struct {
+	fab.LocalDiscoveryProvider
+	ref *ref
+}
+
+// Initialize initializes the shared local discovery provider unless it's already initialized
+func (p *localDiscoveryProvider) Initialize(providers context.Providers) error {
+	return p.ref.shared.initialize(providers)
+}
+
+// Close releases the reference of the SDK instance to the shared local discovery provider
+func (p *localDiscoveryProvider) Close() {
+	p.ref.release()
+}
+
+// corePkg returns the shared infra provider
+type corePkg struct {
+	sdkApi.CoreProviderFactory
+	providers *Providers
+}
+
+// NewCorePkg returns a core provider factory whose infra provider is shared by the SDK instances
+// created with the given providers. The infra provider is created by the given factory.
+func NewCorePkg(factory sdkApi.CoreProviderFactory, providers *Providers) sdkApi.CoreProviderFactory {
+	return &corePkg{CoreProviderFactory: factory, providers: providers}
+}
+
+// CreateInfraProvider returns a reference to the shared infra provider
+func (f *corePkg) CreateInfraProvider(config fab.EndpointConfig) (fab.InfraProvider, error) {
+	shared := &f.providers.infra
+	p, err := shared.acquire(func() (interface{}, error) {
+		return f.CoreProviderFactory.CreateInfraProvider(config)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &infraProvider{InfraProvider: p.(fab.InfraProvider), ref: &ref{shared: shared}}, nil
+}
+
+// servicePkg returns the shared discovery providers
+type servicePkg struct {
+	sdkApi.ServiceProviderFactory
+	providers *Providers
+}
+
+// NewServicePkg returns a service provider factory whose discovery and local discovery providers are
+// shared by the SDK instances created with the given providers. The discovery providers are created
+// by the given factory. Selection providers aren't shared.
+func NewServicePkg(factory sdkApi.ServiceProviderFactory, providers *Providers) sdkApi.ServiceProviderFactory {
+	return &servicePkg{ServiceProviderFactory: factory, providers: providers}
+}
+
+// CreateDiscoveryProvider returns a reference to the shared discovery provider
+func (f *servicePkg) CreateDiscoveryProvider(config fab.EndpointConfig) (fab.DiscoveryProvider, error) {
+	shared := &f.providers.discovery
+	p, err := shared.acquire(func() (interface{}, error) {
+		return f.ServiceProviderFactory.CreateDiscoveryProvider(config)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &discoveryProvider{DiscoveryProvider: p.(fab.DiscoveryProvider), ref: &ref{shared: shared}}, nil
+}
+
+// CreateLocalDiscoveryProvider returns a reference to the shared local discovery provider
+func (f *servicePkg) CreateLocalDiscoveryProvider(config fab.EndpointConfig) (fab.LocalDiscoveryProvider, error) {
+	shared := &f.providers.localDiscovery
+	p, err := shared.acquire(func() (interface{}, error) {
+		return f.ServiceProviderFactory.CreateLocalDiscoveryProvider(config)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &localDiscoveryProvider{LocalDiscoveryProvider: p.(fab.LocalDiscoveryProvider), ref: &ref{shared: shared}}, nil
+}
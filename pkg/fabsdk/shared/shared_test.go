@@ -0,0 +1,110 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shared
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	configImpl "github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	sdkApi "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/factory/defcore"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/factory/defsvc"
+)
+
+const sdkConfigFile = "../../../test/fixtures/config/config_test.yaml"
+
+func TestSharedInfraProvider(t *testing.T) {
+	factory := &mockCorePkg{}
+	providers := New()
+
+	p1, err := NewCorePkg(factory, providers).CreateInfraProvider(nil)
+	require.NoError(t, err)
+	p2, err := NewCorePkg(factory, providers).CreateInfraProvider(nil)
+	require.NoError(t, err)
+	require.Len(t, factory.created, 1, "infra provider should be created once")
+	created := factory.created[0]
+
+	require.NoError(t, p1.(providerInit).Initialize(nil))
+	require.NoError(t, p2.(providerInit).Initialize(nil))
+	assert.Equal(t, 1, created.initialized, "infra provider should be initialized once")
+
+	p1.Close()
+	p1.Close()
+	assert.Equal(t, 0, created.closed, "infra provider shouldn't be closed while it's used by another SDK")
+	p2.Close()
+	assert.Equal(t, 1, created.closed, "infra provider should be closed with the last SDK")
+
+	// Once closed, a new infra provider is created
+	_, err = NewCorePkg(factory, providers).CreateInfraProvider(nil)
+	require.NoError(t, err)
+	assert.Len(t, factory.created, 2)
+}
+
+func TestSharedSDKProviders(t *testing.T) {
+	providers := New()
+	sdk1 := newSDK(t, providers)
+	sdk2 := newSDK(t, providers)
+
+	ctx1 := clientContext(t, sdk1)
+	ctx2 := clientContext(t, sdk2)
+	assert.True(t, ctx1.InfraProvider().CommManager() == ctx2.InfraProvider().CommManager(), "SDKs should share the connection cache")
+
+	ldp1 := ctx1.LocalDiscoveryProvider().(*localDiscoveryProvider)
+	ldp2 := ctx2.LocalDiscoveryProvider().(*localDiscoveryProvider)
+	assert.True(t, ldp1.LocalDiscoveryProvider == ldp2.LocalDiscoveryProvider, "SDKs should share the local discovery provider")
+
+	sdk1.Close()
+	sdk2.Close()
+	assert.Nil(t, providers.infra.provider, "infra provider should be closed with the last SDK")
+}
+
+func newSDK(t *testing.T, providers *Providers) *fabsdk.FabricSDK {
+	sdk, err := fabsdk.New(configImpl.FromFile(sdkConfigFile),
+		fabsdk.WithCorePkg(NewCorePkg(defcore.NewProviderFactory(), providers)),
+		fabsdk.WithServicePkg(NewServicePkg(defsvc.NewProviderFactory(), providers)))
+	require.NoError(t, err)
+	return sdk
+}
+
+func clientContext(t *testing.T, sdk *fabsdk.FabricSDK) context.Client {
+	ctx, err := sdk.Context(fabsdk.WithUser("User1"), fabsdk.WithOrg("org1"))()
+	require.NoError(t, err)
+	return ctx
+}
+
+type mockCorePkg struct {
+	sdkApi.CoreProviderFactory
+	created []*mockInfraProvider
+}
+
+func (f *mockCorePkg) CreateInfraProvider(config fab.EndpointConfig) (fab.InfraProvider, error) {
+	p := &mockInfraProvider{}
+	f.created = append(f.created, p)
+	return p, nil
+}
+
+type mockInfraProvider struct {
+	mocks.MockInfraProvider
+	initialized int
+	closed      int
+}
+
+func (p *mockInfraProvider) Initialize(providers context.Providers) error {
+	p.initialized++
+	return nil
+}
+
+func (p *mockInfraProvider) Close() {
+	p.closed++
+}
@@ -0,0 +1,152 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccsim
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+const ccName = "accounts"
+
+// accountsCC holds account balances. Init takes (account, balance) pairs.
+type accountsCC struct{}
+
+func (cc *accountsCC) Init(stub shim.ChaincodeStubInterface) pb.Response {
+	args := stub.GetStringArgs()
+	for i := 0; i+1 < len(args); i += 2 {
+		if err := stub.PutState(args[i], []byte(args[i+1])); err != nil {
+			return shim.Error(err.Error())
+		}
+	}
+	return shim.Success(nil)
+}
+
+func (cc *accountsCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
+	fcn, args := stub.GetFunctionAndParameters()
+	switch fcn {
+	case "get":
+		value, err := stub.GetState(args[0])
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		return shim.Success(value)
+	case "transfer":
+		return cc.transfer(stub, args)
+	case "list":
+		it, err := stub.GetStateByRange("", "")
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		defer it.Close()
+		var keys []string
+		for it.HasNext() {
+			kv, err := it.Next()
+			if err != nil {
+				return shim.Error(err.Error())
+			}
+			keys = append(keys, kv.Key)
+		}
+		return shim.Success([]byte(strings.Join(keys, ",")))
+	case "secret":
+		transient, err := stub.GetTransient()
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		return shim.Success(transient["secret"])
+	}
+	return shim.Error("unknown function " + fcn)
+}
+
+func (cc *accountsCC) transfer(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+	from, err := cc.balance(stub, args[0])
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	to, err := cc.balance(stub, args[1])
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	amount, _ := strconv.Atoi(args[2])
+	if amount > from {
+		return shim.Error("insufficient funds")
+	}
+	stub.PutState(args[0], []byte(strconv.Itoa(from-amount))) //nolint
+	stub.PutState(args[1], []byte(strconv.Itoa(to+amount)))   //nolint
+	stub.SetEvent("transferred", []byte(args[2]))             //nolint
+	return shim.Success(nil)
+}
+
+func (cc *accountsCC) balance(stub shim.ChaincodeStubInterface, account string) (int, error) {
+	value, err := stub.GetState(account)
+	if err != nil || value == nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(value))
+}
+
+func request(fcn string, args ...string) fab.ChaincodeInvokeRequest {
+	var argBytes [][]byte
+	for _, arg := range args {
+		argBytes = append(argBytes, []byte(arg))
+	}
+	return fab.ChaincodeInvokeRequest{ChaincodeID: ccName, Fcn: fcn, Args: argBytes}
+}
+
+func TestInvokeAndQuery(t *testing.T) {
+	h, err := New("mychannel")
+	require.NoError(t, err)
+	defer h.Close()
+
+	require.NoError(t, h.Install(ccName, &accountsCC{}, []byte("alice"), []byte("100"), []byte("bob"), []byte("50")))
+	assert.Equal(t, "100", string(h.Ledger().GetState(ccName, "alice")))
+
+	response, err := h.Invoke(request("transfer", "alice", "bob", "30"))
+	require.NoError(t, err)
+	assert.Equal(t, pb.TxValidationCode_VALID, response.TxValidationCode)
+	assert.NotEmpty(t, response.TxID)
+	assert.Equal(t, "70", string(h.Ledger().GetState(ccName, "alice")))
+	assert.Equal(t, "80", string(h.Ledger().GetState(ccName, "bob")))
+
+	// A query isn't committed
+	response, err = h.Query(request("get", "bob"))
+	require.NoError(t, err)
+	assert.Equal(t, "80", string(response.Payload))
+	assert.EqualValues(t, 1, h.Ledger().Height())
+
+	response, err = h.Query(request("list"))
+	require.NoError(t, err)
+	assert.Equal(t, "alice,bob", string(response.Payload))
+
+	// Chaincode errors fail the invocation
+	_, err = h.Invoke(request("transfer", "alice", "bob", "1000"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "insufficient funds")
+	assert.Equal(t, "70", string(h.Ledger().GetState(ccName, "alice")))
+}
+
+func TestTransient(t *testing.T) {
+	h, err := New("mychannel")
+	require.NoError(t, err)
+	defer h.Close()
+
+	require.NoError(t, h.Install(ccName, &accountsCC{}))
+
+	req := request("secret")
+	req.TransientMap = map[string][]byte{"secret": []byte("s3cr3t")}
+	response, err := h.Query(req)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", string(response.Payload))
+}
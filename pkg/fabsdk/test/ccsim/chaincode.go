@@ -0,0 +1,154 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ccsim
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/test/mocknetwork"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// Install registers the Go chaincode under the given name on the ledger and initializes it with the
+// given arguments. The writes of Init are applied to the state of the ledger directly.
+func Install(ledger *mocknetwork.Ledger, name string, cc shim.Chaincode, initArgs ...[]byte) error {
+	state := ledger.State(name)
+	mockStub := newMockStub(name, cc, "init", state)
+	response := mockStub.MockInit("init", initArgs)
+	if response.Status >= shim.ERRORTHRESHOLD {
+		return errors.Errorf("init of chaincode [%s] failed with status %d: %s", name, response.Status, response.Message)
+	}
+
+	for key, value := range mockStub.State {
+		if current, ok := state[key]; !ok || !bytes.Equal(current, value) {
+			ledger.PutState(name, key, value)
+		}
+	}
+	for key := range state {
+		if _, ok := mockStub.State[key]; !ok {
+			ledger.DelState(name, key)
+		}
+	}
+
+	ledger.RegisterChaincode(name, Adapt(name, cc, ledger))
+	return nil
+}
+
+// Adapt returns a mock network chaincode which invokes the Go chaincode. The state reads, writes and
+// events of the chaincode go through the stub of the mock network, so that they are recorded in the
+// read-write set of the proposal response and only applied once the transaction is committed.
+// Range, composite key and rich queries are served from the committed state of the ledger without
+// being recorded in the read-write set.
+func Adapt(name string, cc shim.Chaincode, ledger *mocknetwork.Ledger) mocknetwork.Chaincode {
+	return func(stub *mocknetwork.Stub) pb.Response {
+		mockStub := newMockStub(name, cc, stub.GetTxID(), ledger.State(name))
+		defer mockStub.MockTransactionEnd(stub.GetTxID())
+
+		return cc.Invoke(&simStub{MockStub: mockStub, stub: stub})
+	}
+}
+
+// newMockStub returns a shim mock stub whose state is the given state. The transaction is left open
+// so that the stub can be used for the given transaction.
+func newMockStub(name string, cc shim.Chaincode, txID string, state map[string][]byte) *shim.MockStub {
+	keys := make([]string, 0, len(state))
+	for key := range state {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	mockStub := shim.NewMockStub(name, cc)
+	mockStub.MockTransactionStart(txID)
+	for _, key := range keys {
+		mockStub.PutState(key, state[key]) //nolint
+	}
+	return mockStub
+}
+
+// simStub is a chaincode stub which serves the invocation and the state of a mock network stub.
+// The other functions of the stub are served by the shim mock stub.
+type simStub struct {
+	*shim.MockStub
+	stub *mocknetwork.Stub
+}
+
+// GetTxID returns the ID of the transaction
+func (s *simStub) GetTxID() string {
+	return s.stub.GetTxID()
+}
+
+// GetChannelID returns the ID of the channel
+func (s *simStub) GetChannelID() string {
+	return s.stub.GetChannelID()
+}
+
+// GetArgs returns the arguments of the invocation, including the function name
+func (s *simStub) GetArgs() [][]byte {
+	return s.stub.GetArgs()
+}
+
+// GetStringArgs returns the arguments of the invocation as strings
+func (s *simStub) GetStringArgs() []string {
+	args := s.stub.GetArgs()
+	strargs := make([]string, len(args))
+	for i, arg := range args {
+		strargs[i] = string(arg)
+	}
+	return strargs
+}
+
+// GetFunctionAndParameters returns the function name (the first argument) and the remaining arguments
+func (s *simStub) GetFunctionAndParameters() (string, []string) {
+	return s.stub.GetFunctionAndParameters()
+}
+
+// GetArgsSlice returns the concatenated arguments of the invocation
+func (s *simStub) GetArgsSlice() ([]byte, error) {
+	var res []byte
+	for _, arg := range s.stub.GetArgs() {
+		res = append(res, arg...)
+	}
+	return res, nil
+}
+
+// GetTransient returns the transient data of the proposal
+func (s *simStub) GetTransient() (map[string][]byte, error) {
+	return s.stub.GetTransient(), nil
+}
+
+// GetState returns the value of the given key, including the writes of this invocation
+func (s *simStub) GetState(key string) ([]byte, error) {
+	return s.stub.GetState(key), nil
+}
+
+// PutState records a write of the given key
+func (s *simStub) PutState(key string, value []byte) error {
+	if key == "" {
+		return errors.New("key must not be an empty string")
+	}
+	s.stub.PutState(key, value)
+	return nil
+}
+
+// DelState records a delete of the given key
+func (s *simStub) DelState(key string) error {
+	s.stub.DelState(key)
+	return nil
+}
+
+// SetEvent sets the chaincode event of the transaction
+func (s *simStub) SetEvent(name string, payload []byte) error {
+	if name == "" {
+		return errors.New("event name must not be an empty string")
+	}
+	s.stub.SetEvent(name, payload)
+	return nil
+}
@@ -0,0 +1,184 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package ccsim runs Go chaincodes in-process against the in-memory ledger of a mock network
+// (see pkg/fabsdk/test/mocknetwork), so that end-to-end tests can exercise the client and the
+// chaincode logic together without a running Fabric network.
+//
+// The chaincode is invoked with SDK-driven proposals: the harness endorses the proposals with the
+// peer client of the SDK and commits the transactions with the orderer client of the SDK. The peer
+// and orderer URLs of the harness may also be used to point a fully configured SDK at the network.
+//
+// Basic flow:
+//
+//  h, err := ccsim.New("mychannel")
+//  defer h.Close()
+//
+//  err = h.Install("mycc", new(MyChaincode), []byte("init"), []byte("a"), []byte("100"))
+//  resp, err := h.Invoke(fab.ChaincodeInvokeRequest{ChaincodeID: "mycc", Fcn: "move", Args: [][]byte{[]byte("a"), []byte("b")}})
+//  resp, err = h.Query(fab.ChaincodeInvokeRequest{ChaincodeID: "mycc", Fcn: "get", Args: [][]byte{[]byte("a")}})
+package ccsim
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/orderer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/test/mocknetwork"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+const requestTimeout = 10 * time.Second
+
+// Response is the response of a chaincode invocation
+type Response struct {
+	TxID             string
+	Status           int32
+	Message          string
+	Payload          []byte
+	TxValidationCode pb.TxValidationCode
+}
+
+// Harness is a mock network with a peer and an orderer on which Go chaincodes are run in-process
+type Harness struct {
+	channelID   string
+	network     *mocknetwork.Network
+	mockPeer    *mocknetwork.Peer
+	mockOrderer *mocknetwork.Orderer
+	ctx         *mocks.MockContext
+	peer        fab.ProposalProcessor
+	orderer     fab.Orderer
+}
+
+// New starts a mock network with a peer and an orderer for the given channel
+func New(channelID string) (*Harness, error) {
+	h := &Harness{
+		channelID: channelID,
+		network:   mocknetwork.New(),
+		ctx:       mocks.NewMockContext(mspmocks.NewMockSigningIdentity("user1", "Org1MSP")),
+	}
+	if err := h.start(); err != nil {
+		h.Close()
+		return nil, err
+	}
+	return h, nil
+}
+
+func (h *Harness) start() error {
+	var err error
+	if h.mockPeer, err = h.network.StartPeer("127.0.0.1:0"); err != nil {
+		return errors.WithMessage(err, "start peer failed")
+	}
+	if h.mockOrderer, err = h.network.StartOrderer("127.0.0.1:0"); err != nil {
+		return errors.WithMessage(err, "start orderer failed")
+	}
+	if h.peer, err = peer.New(mocks.NewMockEndpointConfig(), peer.WithURL(h.mockPeer.URL()), peer.WithInsecure()); err != nil {
+		return errors.WithMessage(err, "create peer client failed")
+	}
+	if h.orderer, err = orderer.New(mocks.NewMockEndpointConfig(), orderer.WithURL(h.mockOrderer.URL()), orderer.WithInsecure()); err != nil {
+		return errors.WithMessage(err, "create orderer client failed")
+	}
+	return nil
+}
+
+// Close stops the mock network
+func (h *Harness) Close() {
+	h.network.Stop()
+}
+
+// Ledger returns the ledger of the channel
+func (h *Harness) Ledger() *mocknetwork.Ledger {
+	return h.network.Ledger(h.channelID)
+}
+
+// PeerURL returns the URL of the peer
+func (h *Harness) PeerURL() string {
+	return h.mockPeer.URL()
+}
+
+// OrdererURL returns the URL of the orderer
+func (h *Harness) OrdererURL() string {
+	return h.mockOrderer.URL()
+}
+
+// Install installs the Go chaincode under the given name on the channel and initializes it with the given arguments
+func (h *Harness) Install(name string, cc shim.Chaincode, initArgs ...[]byte) error {
+	return Install(h.Ledger(), name, cc, initArgs...)
+}
+
+// Query endorses the request without committing the transaction
+func (h *Harness) Query(request fab.ChaincodeInvokeRequest) (*Response, error) {
+	_, _, response, err := h.endorse(request)
+	return response, err
+}
+
+// Invoke endorses the request and commits the transaction. The validation code of the
+// committed transaction is returned in the response.
+func (h *Harness) Invoke(request fab.ChaincodeInvokeRequest) (*Response, error) {
+	proposal, responses, response, err := h.endorse(request)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := txn.New(fab.TransactionRequest{Proposal: proposal, ProposalResponses: responses})
+	if err != nil {
+		return nil, errors.WithMessage(err, "create transaction failed")
+	}
+
+	reqCtx, cancel := context.NewRequest(h.ctx, context.WithTimeout(requestTimeout))
+	defer cancel()
+
+	if _, err := txn.Send(reqCtx, tx, []fab.Orderer{h.orderer}); err != nil {
+		return nil, errors.WithMessage(err, "send transaction failed")
+	}
+
+	processedTx := h.Ledger().Transaction(response.TxID)
+	if processedTx == nil {
+		return nil, errors.Errorf("transaction [%s] not committed", response.TxID)
+	}
+	response.TxValidationCode = pb.TxValidationCode(processedTx.ValidationCode)
+	return response, nil
+}
+
+func (h *Harness) endorse(request fab.ChaincodeInvokeRequest) (*fab.TransactionProposal, []*fab.TransactionProposalResponse, *Response, error) {
+	txh, err := txn.NewHeader(h.ctx, h.channelID)
+	if err != nil {
+		return nil, nil, nil, errors.WithMessage(err, "create transaction header failed")
+	}
+	proposal, err := txn.CreateChaincodeInvokeProposal(txh, request)
+	if err != nil {
+		return nil, nil, nil, errors.WithMessage(err, "create proposal failed")
+	}
+
+	reqCtx, cancel := context.NewRequest(h.ctx, context.WithTimeout(requestTimeout))
+	defer cancel()
+
+	responses, err := txn.SendProposal(reqCtx, proposal, []fab.ProposalProcessor{h.peer})
+	if err != nil {
+		return nil, nil, nil, errors.WithMessage(err, "send proposal failed")
+	}
+
+	ccResponse := responses[0].ProposalResponse.Response
+	if ccResponse.Status >= shim.ERRORTHRESHOLD {
+		return nil, nil, nil, errors.Errorf("chaincode [%s] failed with status %d: %s", request.ChaincodeID, ccResponse.Status, ccResponse.Message)
+	}
+
+	response := &Response{
+		TxID:    string(proposal.TxnID),
+		Status:  ccResponse.Status,
+		Message: ccResponse.Message,
+		Payload: ccResponse.Payload,
+	}
+	return proposal, responses, response, nil
+}
@@ -0,0 +1,355 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package fabricnet launches a minimal Fabric network in Docker containers (using testcontainers)
+// for integration tests, as an alternative to the static docker-compose fixtures. The network
+// consists of the Org1 CA, the orderer and peer0 of Org1, which use the crypto material and the
+// channel artifacts of the SDK's test fixtures. A connection profile which matches the network
+// (i.e. which points at the ports mapped on the Docker host) is generated on start.
+//
+// Basic flow:
+//
+//  1. Start the network
+//
+//  2. Create the SDK using the connection profile of the network
+//
+//  3. Create and join channels, install chaincodes, etc.
+//
+//  4. Terminate the network
+//
+//     network, err := fabricnet.Start(ctx)
+//     defer network.Terminate(ctx)
+//
+//     sdk, err := fabsdk.New(network.ConfigProvider())
+//
+// The Docker daemon must be reachable (e.g. via DOCKER_HOST) and the fixtures of the SDK must
+// be available in the project path (see WithProjectPath).
+package fabricnet
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/pkg/errors"
+	testcontainers "github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"golang.org/x/net/context"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/pathvar"
+	"github.com/hyperledger/fabric-sdk-go/test/metadata"
+)
+
+var logger = logging.NewLogger("fabsdk")
+
+const (
+	caPort      = "7054/tcp"
+	ordererPort = "7050/tcp"
+	peerPort    = "7051/tcp"
+
+	caHost      = "ca.org1.example.com"
+	ordererHost = "orderer.example.com"
+	peerHost    = "peer0.org1.example.com"
+)
+
+// Images are the Docker images of the network
+type Images struct {
+	CA      string
+	Orderer string
+	Peer    string
+	// CCEnv is the image in which the peer builds chaincodes
+	CCEnv string
+	// BaseOS is the image in which the peer runs chaincodes
+	BaseOS string
+}
+
+// DefaultImages are the images of the docker-compose fixtures
+var DefaultImages = Images{
+	CA:      "hyperledger/fabric-ca:x86_64-1.1.0",
+	Orderer: "hyperledger/fabric-orderer:x86_64-1.1.0",
+	Peer:    "hyperledger/fabric-peer:x86_64-1.1.0",
+	CCEnv:   "hyperledger/fabric-ccenv:x86_64-1.1.0",
+	BaseOS:  "hyperledger/fabric-baseos:x86_64-0.4.6",
+}
+
+type options struct {
+	projectPath    string
+	images         Images
+	startupTimeout time.Duration
+}
+
+// Option configures the network
+type Option func(*options)
+
+// WithProjectPath sets the path of the SDK project which contains the test fixtures
+// (default: ${GOPATH}/src/github.com/hyperledger/fabric-sdk-go)
+func WithProjectPath(path string) Option {
+	return func(o *options) {
+		o.projectPath = path
+	}
+}
+
+// WithImages sets the Docker images of the network
+func WithImages(images Images) Option {
+	return func(o *options) {
+		o.images = images
+	}
+}
+
+// WithStartupTimeout sets the maximum time to wait for each container to start (default: 2 minutes)
+func WithStartupTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.startupTimeout = timeout
+	}
+}
+
+// Network is a Fabric network running in Docker containers
+type Network struct {
+	options
+	network     testcontainers.Network
+	containers  []testcontainers.Container
+	caURL       string
+	ordererURL  string
+	peerURL     string
+	profilePath string
+}
+
+// Start launches the containers of the network and generates its connection profile.
+// The network is terminated if one of the containers fails to start.
+func Start(ctx context.Context, opts ...Option) (*Network, error) {
+	n := &Network{
+		options: options{
+			projectPath:    pathvar.Subst("${GOPATH}/src/github.com/hyperledger/fabric-sdk-go"),
+			images:         DefaultImages,
+			startupTimeout: 2 * time.Minute,
+		},
+	}
+	for _, opt := range opts {
+		opt(&n.options)
+	}
+
+	if err := n.start(ctx); err != nil {
+		if terr := n.Terminate(ctx); terr != nil {
+			logger.Warnf("terminate network failed: %s", terr)
+		}
+		return nil, err
+	}
+	return n, nil
+}
+
+func (n *Network) start(ctx context.Context) error {
+	networkName := fmt.Sprintf("fabricnet%d", time.Now().UnixNano())
+	network, err := testcontainers.GenericNetwork(ctx, testcontainers.GenericNetworkRequest{
+		NetworkRequest: testcontainers.NetworkRequest{Name: networkName, CheckDuplicate: true},
+	})
+	if err != nil {
+		return errors.Wrap(err, "create Docker network failed")
+	}
+	n.network = network
+
+	if n.caURL, err = n.startContainer(ctx, networkName, caHost, n.caRequest(), caPort); err != nil {
+		return err
+	}
+	if n.ordererURL, err = n.startContainer(ctx, networkName, ordererHost, n.ordererRequest(), ordererPort); err != nil {
+		return err
+	}
+	if n.peerURL, err = n.startContainer(ctx, networkName, peerHost, n.peerRequest(networkName), peerPort); err != nil {
+		return err
+	}
+
+	return n.writeProfile()
+}
+
+// startContainer starts a container on the network and returns the host address of the given port
+func (n *Network) startContainer(ctx context.Context, networkName, alias string, req testcontainers.ContainerRequest, port string) (string, error) {
+	req.Networks = []string{networkName}
+	req.NetworkAliases = map[string][]string{networkName: {alias}}
+	req.ExposedPorts = []string{port}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{ContainerRequest: req, Started: true})
+	if err != nil {
+		return "", errors.Wrapf(err, "start container [%s] failed", alias)
+	}
+	n.containers = append(n.containers, container)
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		return "", errors.Wrapf(err, "get host of container [%s] failed", alias)
+	}
+	mappedPort, err := container.MappedPort(ctx, nat.Port(port))
+	if err != nil {
+		return "", errors.Wrapf(err, "get mapped port of container [%s] failed", alias)
+	}
+
+	address := fmt.Sprintf("%s:%s", host, mappedPort.Port())
+	logger.Infof("Container [%s] started on %s", alias, address)
+	return address, nil
+}
+
+func (n *Network) caRequest() testcontainers.ContainerRequest {
+	return testcontainers.ContainerRequest{
+		Image: n.images.CA,
+		Env: map[string]string{
+			"FABRIC_CA_HOME":                "/etc/hyperledger/fabric-ca-server",
+			"FABRIC_CA_SERVER_CA_NAME":      caHost,
+			"FABRIC_CA_SERVER_CA_CERTFILE":  "/etc/hyperledger/fabric-ca-server-config/ca.org1.example.com-cert.pem",
+			"FABRIC_CA_SERVER_CA_KEYFILE":   "/etc/hyperledger/fabric-ca-server-config/8791d1363e89515f9afa042b0693a2c704bb8dd95d28f97d3549a2b9e3c4352d_sk",
+			"FABRIC_CA_SERVER_TLS_ENABLED":  "true",
+			"FABRIC_CA_SERVER_TLS_CERTFILE": "/etc/hyperledger/fabric-ca-server-config/tls/server_wild_org1or2.example.com.pem",
+			"FABRIC_CA_SERVER_TLS_KEYFILE":  "/etc/hyperledger/fabric-ca-server-config/tls/server_wild_org1or2.example.com-key.pem",
+		},
+		Cmd: []string{"sh", "-c", "fabric-ca-server start -b admin:adminpw -d"},
+		BindMounts: map[string]string{
+			n.cryptoConfigPath("peerOrganizations/org1.example.com/ca"): "/etc/hyperledger/fabric-ca-server-config",
+			n.fixturesPath("fabricca/tls/certs/server"):                 "/etc/hyperledger/fabric-ca-server-config/tls",
+		},
+		WaitingFor: wait.ForLog("Listening on").WithStartupTimeout(n.startupTimeout),
+	}
+}
+
+func (n *Network) ordererRequest() testcontainers.ContainerRequest {
+	return testcontainers.ContainerRequest{
+		Image: n.images.Orderer,
+		Env: map[string]string{
+			"ORDERER_GENERAL_LISTENADDRESS":   "0.0.0.0",
+			"ORDERER_GENERAL_GENESISMETHOD":   "file",
+			"ORDERER_GENERAL_GENESISFILE":     "/etc/hyperledger/configtx/twoorgs.genesis.block",
+			"ORDERER_GENERAL_LOCALMSPID":      "OrdererMSP",
+			"ORDERER_GENERAL_LOCALMSPDIR":     "/etc/hyperledger/msp/orderer",
+			"ORDERER_GENERAL_TLS_ENABLED":     "true",
+			"ORDERER_GENERAL_TLS_PRIVATEKEY":  "/etc/hyperledger/tls/orderer/server.key",
+			"ORDERER_GENERAL_TLS_CERTIFICATE": "/etc/hyperledger/tls/orderer/server.crt",
+			"ORDERER_GENERAL_TLS_ROOTCAS":     "[/etc/hyperledger/tls/orderer/ca.crt]",
+		},
+		Cmd: []string{"orderer"},
+		BindMounts: map[string]string{
+			n.projectFile(metadata.ChannelConfigPath):                                               "/etc/hyperledger/configtx",
+			n.cryptoConfigPath("ordererOrganizations/example.com/orderers/orderer.example.com/msp"): "/etc/hyperledger/msp/orderer",
+			n.cryptoConfigPath("ordererOrganizations/example.com/orderers/orderer.example.com/tls"): "/etc/hyperledger/tls/orderer",
+		},
+		WaitingFor: wait.ForLog("Beginning to serve requests").WithStartupTimeout(n.startupTimeout),
+	}
+}
+
+func (n *Network) peerRequest(networkName string) testcontainers.ContainerRequest {
+	return testcontainers.ContainerRequest{
+		Image: n.images.Peer,
+		Env: map[string]string{
+			"CORE_VM_ENDPOINT":                      "unix:///host/var/run/docker.sock",
+			"CORE_VM_DOCKER_HOSTCONFIG_NETWORKMODE": networkName,
+			"CORE_VM_DOCKER_ATTACHSTDOUT":           "true",
+			"CORE_CHAINCODE_BUILDER":                n.images.CCEnv,
+			"CORE_CHAINCODE_GOLANG_RUNTIME":         n.images.BaseOS,
+			"CORE_PEER_ID":                          peerHost,
+			"CORE_PEER_NETWORKID":                   networkName,
+			"CORE_PEER_LOCALMSPID":                  "Org1MSP",
+			"CORE_PEER_MSPCONFIGPATH":               "/etc/hyperledger/msp/peer/",
+			"CORE_PEER_LISTENADDRESS":               "0.0.0.0:7051",
+			"CORE_PEER_ADDRESS":                     "0.0.0.0:7051",
+			"CORE_PEER_CHAINCODELISTENADDRESS":      peerHost + ":7052",
+			"CORE_PEER_ADDRESSAUTODETECT":           "true",
+			"CORE_PEER_GOSSIP_BOOTSTRAP":            "127.0.0.1:7051",
+			"CORE_PEER_GOSSIP_EXTERNALENDPOINT":     peerHost + ":7051",
+			"CORE_PEER_TLS_ENABLED":                 "true",
+			"CORE_PEER_TLS_KEY_FILE":                "/etc/hyperledger/tls/peer/server.key",
+			"CORE_PEER_TLS_CERT_FILE":               "/etc/hyperledger/tls/peer/server.crt",
+			"CORE_PEER_TLS_ROOTCERT_FILE":           "/etc/hyperledger/tls/peer/ca.crt",
+		},
+		Cmd: []string{"peer", "node", "start"},
+		BindMounts: map[string]string{
+			"/var/run/": "/host/var/run/",
+			n.cryptoConfigPath("peerOrganizations/org1.example.com/peers/peer0.org1.example.com/msp"): "/etc/hyperledger/msp/peer",
+			n.cryptoConfigPath("peerOrganizations/org1.example.com/peers/peer0.org1.example.com/tls"): "/etc/hyperledger/tls/peer",
+		},
+		WaitingFor: wait.ForLog("Started peer with ID").WithStartupTimeout(n.startupTimeout),
+	}
+}
+
+// ConfigFile returns the path of the connection profile of the network
+func (n *Network) ConfigFile() string {
+	return n.profilePath
+}
+
+// ConfigProvider returns the provider of the connection profile of the network
+func (n *Network) ConfigProvider() core.ConfigProvider {
+	return config.FromFile(n.profilePath)
+}
+
+// Terminate removes the containers and the Docker network of the network
+// as well as the generated connection profile
+func (n *Network) Terminate(ctx context.Context) error {
+	var lastErr error
+	for i := len(n.containers) - 1; i >= 0; i-- {
+		if err := n.containers[i].Terminate(ctx); err != nil {
+			lastErr = errors.Wrap(err, "terminate container failed")
+		}
+	}
+	n.containers = nil
+
+	if n.network != nil {
+		if err := n.network.Remove(ctx); err != nil {
+			lastErr = errors.Wrap(err, "remove Docker network failed")
+		}
+		n.network = nil
+	}
+
+	if n.profilePath != "" {
+		if err := os.Remove(n.profilePath); err != nil && !os.IsNotExist(err) {
+			lastErr = errors.Wrap(err, "remove connection profile failed")
+		}
+		n.profilePath = ""
+	}
+
+	return lastErr
+}
+
+func (n *Network) writeProfile() error {
+	profile, err := connectionProfile(n.profileParams())
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile("", "fabricnet")
+	if err != nil {
+		return errors.Wrap(err, "create connection profile failed")
+	}
+	defer f.Close()
+
+	if _, err := f.Write(profile); err != nil {
+		return errors.Wrap(err, "write connection profile failed")
+	}
+	n.profilePath = f.Name()
+
+	return nil
+}
+
+func (n *Network) profileParams() profileParams {
+	return profileParams{
+		CryptoConfigPath: n.projectFile(metadata.CryptoConfigPath),
+		FixturesPath:     n.projectFile("test/fixtures"),
+		CAURL:            n.caURL,
+		OrdererURL:       n.ordererURL,
+		PeerURL:          n.peerURL,
+	}
+}
+
+func (n *Network) projectFile(path string) string {
+	return filepath.Join(n.projectPath, path)
+}
+
+func (n *Network) fixturesPath(path string) string {
+	return filepath.Join(n.projectPath, "test/fixtures", path)
+}
+
+func (n *Network) cryptoConfigPath(path string) string {
+	return filepath.Join(n.projectPath, metadata.CryptoConfigPath, path)
+}
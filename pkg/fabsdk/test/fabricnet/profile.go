@@ -0,0 +1,130 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabricnet
+
+import (
+	"bytes"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// profileParams are the parameters of the connection profile template
+type profileParams struct {
+	CryptoConfigPath string
+	FixturesPath     string
+	CAURL            string
+	OrdererURL       string
+	PeerURL          string
+}
+
+// profileTemplate is the connection profile of the network. The URLs of the peer and the
+// orderer are the addresses mapped on the Docker host, so the TLS host names are overridden
+// with the names of the certificates of the fixtures.
+var profileTemplate = template.Must(template.New("profile").Parse(`version: 1.0.0
+
+client:
+  organization: org1
+  logging:
+    level: info
+  eventService:
+    type: deliver
+  cryptoconfig:
+    path: {{.CryptoConfigPath}}
+  credentialStore:
+    path: /tmp/fabricnet/state-store
+    cryptoStore:
+      path: /tmp/fabricnet/msp
+  BCCSP:
+    security:
+      enabled: true
+      default:
+        provider: "SW"
+      hashAlgorithm: "SHA2"
+      softVerify: true
+      level: 256
+  tlsCerts:
+    systemCertPool: false
+    client:
+      key:
+        path: {{.FixturesPath}}/config/mutual_tls/client_sdk_go-key.pem
+      cert:
+        path: {{.FixturesPath}}/config/mutual_tls/client_sdk_go.pem
+
+channels:
+  mychannel:
+    orderers:
+      - orderer.example.com
+    peers:
+      peer0.org1.example.com:
+        endorsingPeer: true
+        chaincodeQuery: true
+        ledgerQuery: true
+        eventSource: true
+
+organizations:
+  org1:
+    mspid: Org1MSP
+    cryptoPath: peerOrganizations/org1.example.com/users/{username}@org1.example.com/msp
+    peers:
+      - peer0.org1.example.com
+    certificateAuthorities:
+      - ca.org1.example.com
+  ordererorg:
+    mspID: OrdererMSP
+    cryptoPath: ordererOrganizations/example.com/users/{username}@example.com/msp
+
+orderers:
+  orderer.example.com:
+    url: {{.OrdererURL}}
+    grpcOptions:
+      ssl-target-name-override: orderer.example.com
+      keep-alive-time: 0s
+      keep-alive-timeout: 20s
+      keep-alive-permit: false
+      fail-fast: false
+      allow-insecure: false
+    tlsCACerts:
+      path: {{.CryptoConfigPath}}/ordererOrganizations/example.com/tlsca/tlsca.example.com-cert.pem
+
+peers:
+  peer0.org1.example.com:
+    url: {{.PeerURL}}
+    grpcOptions:
+      ssl-target-name-override: peer0.org1.example.com
+      keep-alive-time: 0s
+      keep-alive-timeout: 20s
+      keep-alive-permit: false
+      fail-fast: false
+      allow-insecure: false
+    tlsCACerts:
+      path: {{.CryptoConfigPath}}/peerOrganizations/org1.example.com/tlsca/tlsca.org1.example.com-cert.pem
+
+certificateAuthorities:
+  ca.org1.example.com:
+    url: https://{{.CAURL}}
+    tlsCACerts:
+      path: {{.FixturesPath}}/fabricca/tls/certs/ca_root.pem
+      client:
+        key:
+          path: {{.FixturesPath}}/fabricca/tls/certs/client/client_fabric_client-key.pem
+        cert:
+          path: {{.FixturesPath}}/fabricca/tls/certs/client/client_fabric_client.pem
+    registrar:
+      enrollId: admin
+      enrollSecret: adminpw
+    caName: ca.org1.example.com
+`))
+
+// connectionProfile returns the connection profile for the given parameters
+func connectionProfile(params profileParams) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := profileTemplate.Execute(&buf, params); err != nil {
+		return nil, errors.Wrap(err, "generate connection profile failed")
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabricnet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+
+	fabImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab"
+	mspImpl "github.com/hyperledger/fabric-sdk-go/pkg/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/pathvar"
+)
+
+func TestConnectionProfile(t *testing.T) {
+	n := &Network{
+		options:    options{projectPath: pathvar.Subst("${GOPATH}/src/github.com/hyperledger/fabric-sdk-go")},
+		caURL:      "localhost:32001",
+		ordererURL: "localhost:32002",
+		peerURL:    "localhost:32003",
+	}
+	require.NoError(t, n.writeProfile())
+	defer n.Terminate(context.Background())
+
+	backend, err := n.ConfigProvider()()
+	require.NoError(t, err)
+
+	endpointConfig, err := fabImpl.ConfigFromBackend(backend)
+	require.NoError(t, err)
+
+	peerConfig, err := endpointConfig.PeerConfig("peer0.org1.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:32003", peerConfig.URL)
+	assert.Equal(t, "peer0.org1.example.com", peerConfig.GRPCOptions["ssl-target-name-override"])
+
+	ordererConfig, err := endpointConfig.OrdererConfig("orderer.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:32002", ordererConfig.URL)
+
+	channelPeers, err := endpointConfig.ChannelPeers("mychannel")
+	require.NoError(t, err)
+	assert.Len(t, channelPeers, 1)
+
+	identityConfig, err := mspImpl.ConfigFromBackend(backend)
+	require.NoError(t, err)
+	caConfig, err := identityConfig.CAConfig("org1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://localhost:32001", caConfig.URL)
+
+	// Terminating the network removes the connection profile
+	require.NoError(t, n.Terminate(context.Background()))
+	assert.Empty(t, n.ConfigFile())
+}
@@ -0,0 +1,184 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fixturegen
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/utils"
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	ledgerUtil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	protoutils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+func TestConfigBlock(t *testing.T) {
+	org1, err := NewOrg("Org1MSP", WithAnchorPeer("peer0.org1.example.com", 7051))
+	require.NoError(t, err)
+	org2, err := NewOrg("Org2MSP")
+	require.NoError(t, err)
+
+	gen, err := New("mychannel",
+		WithOrgs(org1, org2),
+		WithOrdererAddresses("orderer1:7050", "orderer2:7050"),
+		WithPolicy("Writers", "OR('Org1MSP.member')"),
+	)
+	require.NoError(t, err)
+
+	block, err := gen.ConfigBlock()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), block.Header.Number)
+	assert.Equal(t, uint64(1), gen.Height())
+
+	configEnvelope, err := resource.CreateConfigEnvelope(block.Data.Data[0])
+	require.NoError(t, err)
+	channel := configEnvelope.Config.ChannelGroup
+
+	addresses := &common.OrdererAddresses{}
+	require.NoError(t, proto.Unmarshal(channel.Values[channelConfig.OrdererAddressesKey].Value, addresses))
+	assert.Equal(t, []string{"orderer1:7050", "orderer2:7050"}, addresses.Addresses)
+
+	application := channel.Groups["Application"]
+	require.NotNil(t, application)
+	assert.Len(t, application.Groups, 2)
+	assert.Contains(t, application.Groups["Org1MSP"].Values, channelConfig.AnchorPeersKey)
+	assert.NotContains(t, application.Groups["Org2MSP"].Values, channelConfig.AnchorPeersKey)
+	assert.Equal(t, int32(common.Policy_SIGNATURE), application.Policies["Writers"].Policy.Type)
+	assert.Equal(t, int32(common.Policy_IMPLICIT_META), application.Policies["Readers"].Policy.Type)
+
+	lastConfig, err := resource.GetLastConfigFromBlock(block)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), lastConfig.Index)
+
+	// Config updates are chained to the previous blocks
+	update, err := gen.ConfigBlock()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), update.Header.Number)
+	lastConfig, err = resource.GetLastConfigFromBlock(update)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), lastConfig.Index)
+
+	configEnvelope, err = resource.CreateConfigEnvelope(update.Data.Data[0])
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), configEnvelope.Config.Sequence)
+}
+
+func TestInvalidPolicy(t *testing.T) {
+	gen, err := New("mychannel", WithPolicy("Writers", "SOME('Org1MSP.member')"))
+	require.NoError(t, err)
+
+	_, err = gen.ConfigBlock()
+	assert.Error(t, err)
+}
+
+func TestTransactionBlock(t *testing.T) {
+	gen, err := New("mychannel")
+	require.NoError(t, err)
+	org := gen.Orgs()[0]
+
+	genesis, err := gen.ConfigBlock()
+	require.NoError(t, err)
+
+	valid, err := gen.Transaction("mycc", [][]byte{[]byte("move"), []byte("a"), []byte("b")},
+		WithRead("a", 0, 0),
+		WithWrite("a", []byte("90")),
+		WithChaincodeEvent("moved", []byte("payload")),
+	)
+	require.NoError(t, err)
+	invalid, err := gen.Transaction("mycc", [][]byte{[]byte("move")}, WithValidationCode(pb.TxValidationCode_MVCC_READ_CONFLICT))
+	require.NoError(t, err)
+
+	block, err := gen.Block(valid, invalid)
+	require.NoError(t, err)
+
+	// Block header and metadata
+	assert.Equal(t, uint64(1), block.Header.Number)
+	genesisHeader, err := blockHeaderBytes(genesis.Header)
+	require.NoError(t, err)
+	genesisHash := sha256.Sum256(genesisHeader)
+	assert.Equal(t, genesisHash[:], block.Header.PreviousHash)
+	assert.Equal(t, blockDataHash(block.Data), block.Header.DataHash)
+
+	txFilter := ledgerUtil.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	assert.Equal(t, pb.TxValidationCode_VALID, txFilter.Flag(0))
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, txFilter.Flag(1))
+
+	lastConfig, err := resource.GetLastConfigFromBlock(block)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), lastConfig.Index)
+
+	headerBytes, err := blockHeaderBytes(block.Header)
+	require.NoError(t, err)
+	metadata := &common.Metadata{}
+	require.NoError(t, proto.Unmarshal(block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES], metadata))
+	require.Len(t, metadata.Signatures, 1)
+	verify(t, gen.OrdererOrg(), append(append(metadata.Value, metadata.Signatures[0].SignatureHeader...), headerBytes...), metadata.Signatures[0].Signature)
+
+	// Signed proposal and endorsement
+	verify(t, org, valid.SignedProposal.ProposalBytes, valid.SignedProposal.Signature)
+	require.Len(t, valid.ProposalResponses, 1)
+	response := valid.ProposalResponses[0]
+	assert.Equal(t, int32(200), response.Response.Status)
+	verify(t, org, append(append([]byte{}, response.Payload...), response.Endorsement.Endorser...), response.Endorsement.Signature)
+
+	// Transaction envelope
+	envelope, err := protoutils.ExtractEnvelope(block, 0)
+	require.NoError(t, err)
+	verify(t, org, envelope.Payload, envelope.Signature)
+
+	payload, err := protoutils.ExtractPayload(envelope)
+	require.NoError(t, err)
+	chHeader, err := protoutils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	require.NoError(t, err)
+	assert.Equal(t, valid.TxID, chHeader.TxId)
+	assert.Equal(t, "mychannel", chHeader.ChannelId)
+
+	tx, err := protoutils.GetTransaction(payload.Data)
+	require.NoError(t, err)
+	actionPayload, err := protoutils.GetChaincodeActionPayload(tx.Actions[0].Payload)
+	require.NoError(t, err)
+	prp, err := protoutils.GetProposalResponsePayload(actionPayload.Action.ProposalResponsePayload)
+	require.NoError(t, err)
+	action, err := protoutils.GetChaincodeAction(prp.Extension)
+	require.NoError(t, err)
+
+	event, err := protoutils.GetChaincodeEvents(action.Events)
+	require.NoError(t, err)
+	assert.Equal(t, "moved", event.EventName)
+	assert.Equal(t, valid.TxID, event.TxId)
+
+	rwSet := &rwsetutil.TxRwSet{}
+	require.NoError(t, rwSet.FromProtoBytes(action.Results))
+	require.Len(t, rwSet.NsRwSets, 1)
+	assert.Equal(t, "mycc", rwSet.NsRwSets[0].NameSpace)
+	assert.Equal(t, "a", rwSet.NsRwSets[0].KvRwSet.Writes[0].Key)
+	assert.Equal(t, []byte("90"), rwSet.NsRwSets[0].KvRwSet.Writes[0].Value)
+}
+
+// verify verifies the signature of the message with the certificate of the organization
+func verify(t *testing.T, org *Org, msg, signature []byte) {
+	block, _ := pem.Decode(org.Cert())
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+
+	r, s, err := utils.UnmarshalECDSASignature(signature)
+	require.NoError(t, err)
+	digest := sha256.Sum256(msg)
+	assert.True(t, ecdsa.Verify(cert.PublicKey.(*ecdsa.PublicKey), digest[:], r, s), "invalid signature")
+}
@@ -0,0 +1,486 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package fixturegen generates realistic, serialized Fabric fixtures for unit tests: config blocks,
+// blocks of endorsed transactions, signed proposals and proposal responses. The fixtures are built
+// for chosen organizations, channel policies and validation codes, and are chained and signed like
+// the blocks of a real channel, so that tests don't have to hand-craft protobufs.
+//
+// Basic flow:
+//
+//  org1, err := fixturegen.NewOrg("Org1MSP", fixturegen.WithAnchorPeer("peer0.org1.example.com", 7051))
+//  org2, err := fixturegen.NewOrg("Org2MSP")
+//  gen, err := fixturegen.New("mychannel",
+//      fixturegen.WithOrgs(org1, org2),
+//      fixturegen.WithPolicy("Writers", "OR('Org1MSP.member')"),
+//  )
+//
+//  genesis, err := gen.ConfigBlock()
+//  tx, err := gen.Transaction("mycc", [][]byte{[]byte("move"), []byte("a"), []byte("b")},
+//      fixturegen.WithEndorsers(org1, org2),
+//      fixturegen.WithWrite("a", []byte("90")),
+//      fixturegen.WithValidationCode(pb.TxValidationCode_MVCC_READ_CONFLICT),
+//  )
+//  block, err := gen.Block(tx)
+//
+// The identities of all organizations are signed by a self-signed CA of the organization, and the
+// orderer signatures of the blocks are made by the member identity of the orderer organization.
+package fixturegen
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	ledgerUtil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+const (
+	blockValidationPolicyKey = "BlockValidation"
+	nonceSize                = 24
+)
+
+// Generator generates the blocks of a channel. Each generated block is chained to the previous one.
+type Generator struct {
+	lock             sync.Mutex
+	channelID        string
+	orgs             []*Org
+	ordererOrg       *Org
+	ordererAddresses []string
+	consensusType    string
+	policies         map[string]string
+	number           uint64
+	previousHash     []byte
+	lastConfig       uint64
+	sequence         uint64
+}
+
+// Option is an option of a generator
+type Option func(*Generator)
+
+// WithOrgs sets the application organizations of the channel.
+// By default, the channel has a single organization, Org1MSP.
+func WithOrgs(orgs ...*Org) Option {
+	return func(g *Generator) {
+		g.orgs = orgs
+	}
+}
+
+// WithOrdererOrg sets the orderer organization of the channel.
+// By default, the orderer organization is OrdererMSP.
+func WithOrdererOrg(org *Org) Option {
+	return func(g *Generator) {
+		g.ordererOrg = org
+	}
+}
+
+// WithOrdererAddresses sets the orderer addresses of the channel configuration
+func WithOrdererAddresses(addresses ...string) Option {
+	return func(g *Generator) {
+		g.ordererAddresses = addresses
+	}
+}
+
+// WithConsensusType sets the consensus type (e.g. solo, kafka, etcdraft) of the channel configuration
+func WithConsensusType(consensusType string) Option {
+	return func(g *Generator) {
+		g.consensusType = consensusType
+	}
+}
+
+// WithPolicy sets a policy of the application group of the channel configuration. The rule is either
+// an implicit meta policy (e.g. "MAJORITY Admins") or a signature policy (e.g. "OR('Org1MSP.member')").
+// By default, the Readers and Writers policies are "ANY Readers" and "ANY Writers" and the Admins
+// policy is "MAJORITY Admins".
+func WithPolicy(name, rule string) Option {
+	return func(g *Generator) {
+		g.policies[name] = rule
+	}
+}
+
+// New returns a new generator for the given channel
+func New(channelID string, opts ...Option) (*Generator, error) {
+	g := &Generator{
+		channelID:        channelID,
+		ordererAddresses: []string{"orderer.example.com:7050"},
+		consensusType:    "solo",
+		policies:         make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	if len(g.orgs) == 0 {
+		org, err := NewOrg("Org1MSP", WithAnchorPeer("peer0.org1.example.com", 7051))
+		if err != nil {
+			return nil, err
+		}
+		g.orgs = []*Org{org}
+	}
+	if g.ordererOrg == nil {
+		org, err := NewOrg("OrdererMSP")
+		if err != nil {
+			return nil, err
+		}
+		g.ordererOrg = org
+	}
+	return g, nil
+}
+
+// ChannelID returns the ID of the channel
+func (g *Generator) ChannelID() string {
+	return g.channelID
+}
+
+// Orgs returns the application organizations of the channel
+func (g *Generator) Orgs() []*Org {
+	return g.orgs
+}
+
+// OrdererOrg returns the orderer organization of the channel
+func (g *Generator) OrdererOrg() *Org {
+	return g.ordererOrg
+}
+
+// Height returns the number of blocks generated so far
+func (g *Generator) Height() uint64 {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	return g.number
+}
+
+// ConfigBlock returns the next block of the channel, which contains the channel configuration.
+// The first block generated is the genesis block of the channel.
+func (g *Generator) ConfigBlock() (*common.Block, error) {
+	config, err := g.config()
+	if err != nil {
+		return nil, errors.WithMessage(err, "create channel config failed")
+	}
+
+	payload := &common.Payload{
+		Header: utils.MakePayloadHeader(
+			utils.MakeChannelHeader(common.HeaderType_CONFIG, 1, g.channelID, 0),
+			&common.SignatureHeader{Creator: g.ordererOrg.Identity()},
+		),
+		Data: utils.MarshalOrPanic(&common.ConfigEnvelope{Config: config}),
+	}
+	envelope, err := signEnvelope(g.ordererOrg, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.nextBlock([]*common.Envelope{envelope}, []pb.TxValidationCode{pb.TxValidationCode_VALID}, true)
+}
+
+// Block returns the next block of the channel, which contains the given transactions
+func (g *Generator) Block(txs ...*Transaction) (*common.Block, error) {
+	envelopes := make([]*common.Envelope, len(txs))
+	codes := make([]pb.TxValidationCode, len(txs))
+	for i, tx := range txs {
+		envelopes[i] = tx.Envelope
+		codes[i] = tx.ValidationCode
+	}
+	return g.nextBlock(envelopes, codes, false)
+}
+
+func (g *Generator) nextBlock(envelopes []*common.Envelope, codes []pb.TxValidationCode, isConfig bool) (*common.Block, error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	data := &common.BlockData{}
+	for _, envelope := range envelopes {
+		envelopeBytes, err := proto.Marshal(envelope)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshal envelope failed")
+		}
+		data.Data = append(data.Data, envelopeBytes)
+	}
+
+	header := &common.BlockHeader{
+		Number:       g.number,
+		PreviousHash: g.previousHash,
+		DataHash:     blockDataHash(data),
+	}
+	headerBytes, err := blockHeaderBytes(header)
+	if err != nil {
+		return nil, err
+	}
+
+	lastConfig := g.lastConfig
+	if isConfig {
+		lastConfig = g.number
+	}
+
+	txFilter := ledgerUtil.NewTxValidationFlags(len(codes))
+	for i, code := range codes {
+		txFilter[i] = uint8(code)
+	}
+
+	signatures, err := g.blockMetadata(nil, headerBytes)
+	if err != nil {
+		return nil, err
+	}
+	lastConfigMetadata, err := g.blockMetadata(utils.MarshalOrPanic(&common.LastConfig{Index: lastConfig}), headerBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := make([][]byte, len(common.BlockMetadataIndex_name))
+	metadata[common.BlockMetadataIndex_SIGNATURES] = signatures
+	metadata[common.BlockMetadataIndex_LAST_CONFIG] = lastConfigMetadata
+	metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txFilter
+	metadata[common.BlockMetadataIndex_ORDERER] = utils.MarshalOrPanic(&common.Metadata{})
+
+	hash := sha256.Sum256(headerBytes)
+	g.previousHash = hash[:]
+	g.lastConfig = lastConfig
+	g.number++
+
+	return &common.Block{Header: header, Data: data, Metadata: &common.BlockMetadata{Metadata: metadata}}, nil
+}
+
+// blockMetadata returns the block metadata with the given value, signed by the orderer organization
+func (g *Generator) blockMetadata(value, headerBytes []byte) ([]byte, error) {
+	nonce, err := newNonce()
+	if err != nil {
+		return nil, err
+	}
+	signatureHeader := utils.MarshalOrPanic(&common.SignatureHeader{Creator: g.ordererOrg.Identity(), Nonce: nonce})
+
+	msg := append(append(append([]byte{}, value...), signatureHeader...), headerBytes...)
+	signature, err := g.ordererOrg.Sign(msg)
+	if err != nil {
+		return nil, errors.WithMessage(err, "sign block metadata failed")
+	}
+
+	return utils.MarshalOrPanic(&common.Metadata{
+		Value:      value,
+		Signatures: []*common.MetadataSignature{{SignatureHeader: signatureHeader, Signature: signature}},
+	}), nil
+}
+
+// config returns the next channel configuration
+func (g *Generator) config() (*common.Config, error) {
+	application, err := g.applicationGroup()
+	if err != nil {
+		return nil, err
+	}
+	orderer, err := g.ordererGroup()
+	if err != nil {
+		return nil, err
+	}
+
+	g.lock.Lock()
+	sequence := g.sequence
+	g.sequence++
+	g.lock.Unlock()
+
+	channel := newConfigGroup()
+	channel.Groups[string(fab.ApplicationGroupKey)] = application
+	channel.Groups[string(fab.OrdererGroupKey)] = orderer
+	channel.Values[channelConfig.HashingAlgorithmKey] = configValue(&common.HashingAlgorithm{Name: "SHA256"})
+	channel.Values[channelConfig.BlockDataHashingStructureKey] = configValue(&common.BlockDataHashingStructure{Width: math.MaxUint32})
+	channel.Values[channelConfig.OrdererAddressesKey] = configValue(&common.OrdererAddresses{Addresses: g.ordererAddresses})
+	channel.Values[channelConfig.CapabilitiesKey] = capabilitiesValue(fab.V1_1Capability)
+	if err := setDefaultPolicies(channel); err != nil {
+		return nil, err
+	}
+
+	return &common.Config{Sequence: sequence, ChannelGroup: channel}, nil
+}
+
+func (g *Generator) applicationGroup() (*common.ConfigGroup, error) {
+	group := newConfigGroup()
+	for _, org := range g.orgs {
+		orgGroup, err := newOrgGroup(org)
+		if err != nil {
+			return nil, err
+		}
+		if len(org.anchorPeers) > 0 {
+			orgGroup.Values[channelConfig.AnchorPeersKey] = configValue(&pb.AnchorPeers{AnchorPeers: org.anchorPeers})
+		}
+		group.Groups[org.MSPID()] = orgGroup
+	}
+	group.Values[channelConfig.CapabilitiesKey] = capabilitiesValue(fab.V1_2Capability)
+	if err := setDefaultPolicies(group); err != nil {
+		return nil, err
+	}
+
+	for name, rule := range g.policies {
+		policy, err := newPolicy(rule)
+		if err != nil {
+			return nil, errors.WithMessage(err, "invalid policy "+name)
+		}
+		group.Policies[name] = &common.ConfigPolicy{Policy: policy, ModPolicy: channelConfig.AdminsPolicyKey}
+	}
+	return group, nil
+}
+
+func (g *Generator) ordererGroup() (*common.ConfigGroup, error) {
+	orgGroup, err := newOrgGroup(g.ordererOrg)
+	if err != nil {
+		return nil, err
+	}
+
+	group := newConfigGroup()
+	group.Groups[g.ordererOrg.MSPID()] = orgGroup
+	group.Values[channelConfig.ConsensusTypeKey] = configValue(&ab.ConsensusType{Type: g.consensusType})
+	group.Values[channelConfig.BatchSizeKey] = configValue(&ab.BatchSize{MaxMessageCount: 10, AbsoluteMaxBytes: 99 * 1024 * 1024, PreferredMaxBytes: 512 * 1024})
+	group.Values[channelConfig.BatchTimeoutKey] = configValue(&ab.BatchTimeout{Timeout: "2s"})
+	group.Values[channelConfig.ChannelRestrictionsKey] = configValue(&ab.ChannelRestrictions{})
+	group.Values[channelConfig.CapabilitiesKey] = capabilitiesValue(fab.V1_1Capability)
+	if err := setDefaultPolicies(group); err != nil {
+		return nil, err
+	}
+
+	policy, err := newPolicy("ANY " + channelConfig.WritersPolicyKey)
+	if err != nil {
+		return nil, err
+	}
+	group.Policies[blockValidationPolicyKey] = &common.ConfigPolicy{Policy: policy, ModPolicy: channelConfig.AdminsPolicyKey}
+	return group, nil
+}
+
+func newOrgGroup(org *Org) (*common.ConfigGroup, error) {
+	mspConfig, err := org.MSPConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	group := newConfigGroup()
+	group.Values[channelConfig.MSPKey] = configValue(mspConfig)
+	group.Policies[channelConfig.ReadersPolicyKey] = signaturePolicy(cauthdsl.SignedByMspMember(org.MSPID()))
+	group.Policies[channelConfig.WritersPolicyKey] = signaturePolicy(cauthdsl.SignedByMspMember(org.MSPID()))
+	group.Policies[channelConfig.AdminsPolicyKey] = signaturePolicy(cauthdsl.SignedByMspAdmin(org.MSPID()))
+	return group, nil
+}
+
+func newConfigGroup() *common.ConfigGroup {
+	return &common.ConfigGroup{
+		Groups:    make(map[string]*common.ConfigGroup),
+		Values:    make(map[string]*common.ConfigValue),
+		Policies:  make(map[string]*common.ConfigPolicy),
+		ModPolicy: channelConfig.AdminsPolicyKey,
+	}
+}
+
+// setDefaultPolicies sets the Readers, Writers and Admins implicit meta policies of the group
+func setDefaultPolicies(group *common.ConfigGroup) error {
+	rules := map[string]string{
+		channelConfig.ReadersPolicyKey: "ANY " + channelConfig.ReadersPolicyKey,
+		channelConfig.WritersPolicyKey: "ANY " + channelConfig.WritersPolicyKey,
+		channelConfig.AdminsPolicyKey:  "MAJORITY " + channelConfig.AdminsPolicyKey,
+	}
+	for name, rule := range rules {
+		policy, err := newPolicy(rule)
+		if err != nil {
+			return err
+		}
+		group.Policies[name] = &common.ConfigPolicy{Policy: policy, ModPolicy: channelConfig.AdminsPolicyKey}
+	}
+	return nil
+}
+
+// newPolicy returns the policy of the given rule, which is either an implicit meta policy
+// (e.g. "ANY Readers") or a signature policy (e.g. "AND('Org1MSP.member', 'Org2MSP.member')")
+func newPolicy(rule string) (*common.Policy, error) {
+	fields := strings.Fields(rule)
+	if len(fields) == 2 {
+		if r, ok := common.ImplicitMetaPolicy_Rule_value[fields[0]]; ok {
+			return &common.Policy{
+				Type:  int32(common.Policy_IMPLICIT_META),
+				Value: utils.MarshalOrPanic(&common.ImplicitMetaPolicy{Rule: common.ImplicitMetaPolicy_Rule(r), SubPolicy: fields[1]}),
+			}, nil
+		}
+	}
+
+	envelope, err := cauthdsl.FromString(rule)
+	if err != nil {
+		return nil, errors.WithMessage(err, "parse signature policy ["+rule+"] failed")
+	}
+	return &common.Policy{Type: int32(common.Policy_SIGNATURE), Value: utils.MarshalOrPanic(envelope)}, nil
+}
+
+func signaturePolicy(envelope *common.SignaturePolicyEnvelope) *common.ConfigPolicy {
+	return &common.ConfigPolicy{
+		Policy:    &common.Policy{Type: int32(common.Policy_SIGNATURE), Value: utils.MarshalOrPanic(envelope)},
+		ModPolicy: channelConfig.AdminsPolicyKey,
+	}
+}
+
+func configValue(msg proto.Message) *common.ConfigValue {
+	return &common.ConfigValue{Value: utils.MarshalOrPanic(msg), ModPolicy: channelConfig.AdminsPolicyKey}
+}
+
+func capabilitiesValue(capabilities ...string) *common.ConfigValue {
+	value := &common.Capabilities{Capabilities: make(map[string]*common.Capability)}
+	for _, capability := range capabilities {
+		value.Capabilities[capability] = &common.Capability{}
+	}
+	return configValue(value)
+}
+
+func signEnvelope(signer *Org, payload *common.Payload) (*common.Envelope, error) {
+	payloadBytes, err := proto.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal payload failed")
+	}
+	signature, err := signer.Sign(payloadBytes)
+	if err != nil {
+		return nil, errors.WithMessage(err, "sign payload failed")
+	}
+	return &common.Envelope{Payload: payloadBytes, Signature: signature}, nil
+}
+
+func newNonce() ([]byte, error) {
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.Wrap(err, "generate nonce failed")
+	}
+	return nonce, nil
+}
+
+type asn1Header struct {
+	Number       *big.Int
+	PreviousHash []byte
+	DataHash     []byte
+}
+
+// blockHeaderBytes returns the ASN.1 encoding of the block header, which is what the orderers sign
+func blockHeaderBytes(header *common.BlockHeader) ([]byte, error) {
+	bytes, err := asn1.Marshal(asn1Header{
+		Number:       new(big.Int).SetUint64(header.Number),
+		PreviousHash: header.PreviousHash,
+		DataHash:     header.DataHash,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal block header failed")
+	}
+	return bytes, nil
+}
+
+func blockDataHash(data *common.BlockData) []byte {
+	h := sha256.New()
+	for _, d := range data.Data {
+		h.Write(d) //nolint
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,161 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fixturegen
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/utils"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// Org is an organization with a self-signed CA and a member identity issued by the CA.
+// The member identity is also an admin of the organization.
+type Org struct {
+	mspID       string
+	rootCert    []byte
+	cert        []byte
+	identity    []byte
+	key         *ecdsa.PrivateKey
+	anchorPeers []*pb.AnchorPeer
+}
+
+// OrgOption is an option of an organization
+type OrgOption func(*Org)
+
+// WithAnchorPeer adds an anchor peer to the application configuration of the organization
+func WithAnchorPeer(host string, port int32) OrgOption {
+	return func(o *Org) {
+		o.anchorPeers = append(o.anchorPeers, &pb.AnchorPeer{Host: host, Port: port})
+	}
+}
+
+// NewOrg returns a new organization with the given MSP ID and freshly generated keys and certificates
+func NewOrg(mspID string, opts ...OrgOption) (*Org, error) {
+	org := &Org{mspID: mspID}
+	for _, opt := range opts {
+		opt(org)
+	}
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "generate CA key failed")
+	}
+	caTemplate := certTemplate("ca."+mspID, mspID)
+	caTemplate.IsCA = true
+	caTemplate.BasicConstraintsValid = true
+	caTemplate.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+	caCert, err := createCert(caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	org.key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "generate member key failed")
+	}
+	template := certTemplate("member."+mspID, mspID)
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	cert, err := createCert(template, caCert, &org.key.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	org.rootCert = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caCert.Raw})
+	org.cert = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	org.identity, err = proto.Marshal(&mb.SerializedIdentity{Mspid: mspID, IdBytes: org.cert})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal serialized identity failed")
+	}
+	return org, nil
+}
+
+// MSPID returns the MSP ID of the organization
+func (o *Org) MSPID() string {
+	return o.mspID
+}
+
+// RootCert returns the PEM encoded CA certificate of the organization
+func (o *Org) RootCert() []byte {
+	return o.rootCert
+}
+
+// Cert returns the PEM encoded certificate of the member identity
+func (o *Org) Cert() []byte {
+	return o.cert
+}
+
+// Identity returns the serialized member identity
+func (o *Org) Identity() []byte {
+	return o.identity
+}
+
+// Sign signs the SHA256 digest of the message with the key of the member identity.
+// The signature is a low-S ECDSA signature, as produced by Fabric.
+func (o *Org) Sign(msg []byte) ([]byte, error) {
+	digest := sha256.Sum256(msg)
+	r, s, err := ecdsa.Sign(rand.Reader, o.key, digest[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "sign failed")
+	}
+	s, _, err = utils.ToLowS(&o.key.PublicKey, s)
+	if err != nil {
+		return nil, errors.Wrap(err, "convert signature to low-S failed")
+	}
+	return utils.MarshalECDSASignature(r, s)
+}
+
+// MSPConfig returns the MSP configuration of the organization
+func (o *Org) MSPConfig() (*mb.MSPConfig, error) {
+	config, err := proto.Marshal(&mb.FabricMSPConfig{
+		Name:      o.mspID,
+		RootCerts: [][]byte{o.rootCert},
+		Admins:    [][]byte{o.cert},
+		CryptoConfig: &mb.FabricCryptoConfig{
+			SignatureHashFamily:            "SHA2",
+			IdentityIdentifierHashFunction: "SHA256",
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal MSP config failed")
+	}
+	return &mb.MSPConfig{Type: 0, Config: config}, nil
+}
+
+func certTemplate(commonName, org string) *x509.Certificate {
+	serial, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128)) //nolint
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName, Organization: []string{org}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(10 * 365 * 24 * time.Hour),
+	}
+}
+
+func createCert(template, parent *x509.Certificate, pub *ecdsa.PublicKey, signer *ecdsa.PrivateKey) (*x509.Certificate, error) {
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, pub, signer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "create certificate [%s] failed", template.Subject.CommonName)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse certificate [%s] failed", template.Subject.CommonName)
+	}
+	return cert, nil
+}
@@ -0,0 +1,240 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fixturegen
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// Transaction is an endorsed transaction: the signed proposal, the proposal responses of the
+// endorsers and the signed transaction envelope, along with the validation code with which the
+// transaction is committed in a block.
+type Transaction struct {
+	TxID              string
+	SignedProposal    *pb.SignedProposal
+	ProposalResponses []*pb.ProposalResponse
+	Envelope          *common.Envelope
+	ValidationCode    pb.TxValidationCode
+}
+
+type txOptions struct {
+	creator        *Org
+	endorsers      []*Org
+	response       *pb.Response
+	reads          []*kvrwset.KVRead
+	writes         []*kvrwset.KVWrite
+	event          *pb.ChaincodeEvent
+	validationCode pb.TxValidationCode
+}
+
+// TxOption is an option of a transaction
+type TxOption func(*txOptions)
+
+// WithCreator sets the organization whose member creates and signs the proposal and the transaction.
+// By default, the creator is the first organization of the channel.
+func WithCreator(org *Org) TxOption {
+	return func(o *txOptions) {
+		o.creator = org
+	}
+}
+
+// WithEndorsers sets the organizations which endorse the proposal.
+// By default, all organizations of the channel endorse the proposal.
+func WithEndorsers(orgs ...*Org) TxOption {
+	return func(o *txOptions) {
+		o.endorsers = orgs
+	}
+}
+
+// WithResponse sets the chaincode response of the endorsements. By default, the status is 200.
+func WithResponse(status int32, message string, payload []byte) TxOption {
+	return func(o *txOptions) {
+		o.response = &pb.Response{Status: status, Message: message, Payload: payload}
+	}
+}
+
+// WithRead adds the read of the given key at the given version to the read-write set
+func WithRead(key string, blockNum, txNum uint64) TxOption {
+	return func(o *txOptions) {
+		o.reads = append(o.reads, &kvrwset.KVRead{Key: key, Version: &kvrwset.Version{BlockNum: blockNum, TxNum: txNum}})
+	}
+}
+
+// WithWrite adds the write of the given key to the read-write set. A nil value deletes the key.
+func WithWrite(key string, value []byte) TxOption {
+	return func(o *txOptions) {
+		o.writes = append(o.writes, &kvrwset.KVWrite{Key: key, Value: value, IsDelete: value == nil})
+	}
+}
+
+// WithChaincodeEvent sets the chaincode event of the transaction
+func WithChaincodeEvent(eventName string, payload []byte) TxOption {
+	return func(o *txOptions) {
+		o.event = &pb.ChaincodeEvent{EventName: eventName, Payload: payload}
+	}
+}
+
+// WithValidationCode sets the validation code with which the transaction is committed. By default, it is VALID.
+func WithValidationCode(code pb.TxValidationCode) TxOption {
+	return func(o *txOptions) {
+		o.validationCode = code
+	}
+}
+
+// Transaction returns a new transaction which invokes the given chaincode with the given arguments
+func (g *Generator) Transaction(ccID string, args [][]byte, opts ...TxOption) (*Transaction, error) {
+	o := &txOptions{
+		creator:        g.orgs[0],
+		endorsers:      g.orgs,
+		response:       &pb.Response{Status: 200},
+		validationCode: pb.TxValidationCode_VALID,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return nil, err
+	}
+	txIDHash := sha256.Sum256(append(append([]byte{}, nonce...), o.creator.Identity()...))
+	txID := hex.EncodeToString(txIDHash[:])
+
+	cis := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_GOLANG,
+			ChaincodeId: &pb.ChaincodeID{Name: ccID},
+			Input:       &pb.ChaincodeInput{Args: args},
+		},
+	}
+	proposal, _, err := utils.CreateChaincodeProposalWithTxIDNonceAndTransient(txID, common.HeaderType_ENDORSER_TRANSACTION, g.channelID, cis, nonce, o.creator.Identity(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "create proposal failed")
+	}
+	signedProposal, err := signProposal(o.creator, proposal)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := endorse(ccID, txID, proposal, o)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope, err := newTxEnvelope(o.creator, proposal, responses)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transaction{
+		TxID:              txID,
+		SignedProposal:    signedProposal,
+		ProposalResponses: responses,
+		Envelope:          envelope,
+		ValidationCode:    o.validationCode,
+	}, nil
+}
+
+func signProposal(creator *Org, proposal *pb.Proposal) (*pb.SignedProposal, error) {
+	proposalBytes, err := proto.Marshal(proposal)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal proposal failed")
+	}
+	signature, err := creator.Sign(proposalBytes)
+	if err != nil {
+		return nil, errors.WithMessage(err, "sign proposal failed")
+	}
+	return &pb.SignedProposal{ProposalBytes: proposalBytes, Signature: signature}, nil
+}
+
+// endorse returns the proposal responses of the endorsers to the proposal
+func endorse(ccID, txID string, proposal *pb.Proposal, o *txOptions) ([]*pb.ProposalResponse, error) {
+	rwSet := &rwsetutil.TxRwSet{
+		NsRwSets: []*rwsetutil.NsRwSet{{
+			NameSpace: ccID,
+			KvRwSet:   &kvrwset.KVRWSet{Reads: o.reads, Writes: o.writes},
+		}},
+	}
+	results, err := rwSet.ToProtoBytes()
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal read-write set failed")
+	}
+
+	var event []byte
+	if o.event != nil {
+		event, err = utils.GetBytesChaincodeEvent(&pb.ChaincodeEvent{ChaincodeId: ccID, TxId: txID, EventName: o.event.EventName, Payload: o.event.Payload})
+		if err != nil {
+			return nil, errors.Wrap(err, "marshal chaincode event failed")
+		}
+	}
+
+	proposalHash := sha256.Sum256(append(append([]byte{}, proposal.Header...), proposal.Payload...))
+	payload, err := utils.GetBytesProposalResponsePayload(proposalHash[:], o.response, results, event, &pb.ChaincodeID{Name: ccID})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal proposal response payload failed")
+	}
+
+	var responses []*pb.ProposalResponse
+	for _, endorser := range o.endorsers {
+		signature, err := endorser.Sign(append(append([]byte{}, payload...), endorser.Identity()...))
+		if err != nil {
+			return nil, errors.WithMessage(err, "sign proposal response failed")
+		}
+		responses = append(responses, &pb.ProposalResponse{
+			Version:     1,
+			Response:    o.response,
+			Payload:     payload,
+			Endorsement: &pb.Endorsement{Endorser: endorser.Identity(), Signature: signature},
+		})
+	}
+	return responses, nil
+}
+
+// newTxEnvelope returns the signed transaction envelope of the endorsed proposal
+func newTxEnvelope(creator *Org, proposal *pb.Proposal, responses []*pb.ProposalResponse) (*common.Envelope, error) {
+	header, err := utils.GetHeader(proposal.Header)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal proposal header failed")
+	}
+	ccProposalPayload, err := utils.GetChaincodeProposalPayload(proposal.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal chaincode proposal payload failed")
+	}
+	ccProposalPayloadBytes, err := utils.GetBytesProposalPayloadForTx(ccProposalPayload, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal chaincode proposal payload failed")
+	}
+
+	action := &pb.ChaincodeEndorsedAction{}
+	for _, response := range responses {
+		action.ProposalResponsePayload = response.Payload
+		action.Endorsements = append(action.Endorsements, response.Endorsement)
+	}
+	actionPayload, err := utils.GetBytesChaincodeActionPayload(&pb.ChaincodeActionPayload{ChaincodeProposalPayload: ccProposalPayloadBytes, Action: action})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal chaincode action payload failed")
+	}
+
+	tx, err := utils.GetBytesTransaction(&pb.Transaction{
+		Actions: []*pb.TransactionAction{{Header: header.SignatureHeader, Payload: actionPayload}},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal transaction failed")
+	}
+
+	return signEnvelope(creator, &common.Payload{Header: header, Data: tx})
+}
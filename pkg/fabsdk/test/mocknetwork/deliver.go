@@ -0,0 +1,185 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocknetwork
+
+import (
+	"io"
+	"math"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+
+	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// deliverStream is a peer or orderer deliver stream
+type deliverStream interface {
+	Recv() (*common.Envelope, error)
+	Context() context.Context
+}
+
+type blockSender func(block *common.Block, channelID string) error
+type statusSender func(status common.Status) error
+
+// deliver handles the seek requests of a deliver stream. The blocks in the range of a request
+// are sent (waiting for blocks to be committed if necessary) followed by a status.
+func deliver(network *Network, srv deliverStream, sendBlock blockSender, sendStatus statusSender) error {
+	for {
+		envelope, err := srv.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		status, err := deliverBlocks(network, srv.Context(), envelope, sendBlock)
+		if err != nil {
+			return err
+		}
+		if err := sendStatus(status); err != nil {
+			return err
+		}
+	}
+}
+
+func deliverBlocks(network *Network, ctx context.Context, envelope *common.Envelope, sendBlock blockSender) (common.Status, error) {
+	payload, err := utils.GetPayload(envelope)
+	if err != nil || payload.Header == nil {
+		return common.Status_BAD_REQUEST, nil
+	}
+	chHeader, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return common.Status_BAD_REQUEST, nil
+	}
+	seekInfo := &ab.SeekInfo{}
+	if err := proto.Unmarshal(payload.Data, seekInfo); err != nil {
+		return common.Status_BAD_REQUEST, nil
+	}
+
+	ledger, ok := network.ledger(chHeader.ChannelId)
+	if !ok {
+		return common.Status_NOT_FOUND, nil
+	}
+
+	start, ok := seekNumber(seekInfo.Start, ledger, 0)
+	if !ok {
+		return common.Status_BAD_REQUEST, nil
+	}
+	stop, ok := seekNumber(seekInfo.Stop, ledger, start)
+	if !ok || stop < start {
+		return common.Status_BAD_REQUEST, nil
+	}
+
+	if seekInfo.Behavior == ab.SeekInfo_FAIL_IF_NOT_READY && start >= ledger.Height() {
+		return common.Status_NOT_FOUND, nil
+	}
+
+	next := start
+	for {
+		blocks, newBlock := ledger.blocksFrom(next)
+		for _, block := range blocks {
+			if err := sendBlock(block, chHeader.ChannelId); err != nil {
+				return common.Status_UNKNOWN, err
+			}
+			if next == stop {
+				return common.Status_SUCCESS, nil
+			}
+			next++
+		}
+
+		if seekInfo.Behavior == ab.SeekInfo_FAIL_IF_NOT_READY {
+			return common.Status_NOT_FOUND, nil
+		}
+
+		select {
+		case <-newBlock:
+		case <-ctx.Done():
+			return common.Status_UNKNOWN, ctx.Err()
+		}
+	}
+}
+
+// seekNumber returns the block number of the given seek position
+func seekNumber(position *ab.SeekPosition, ledger *Ledger, defaultNumber uint64) (uint64, bool) {
+	if position == nil {
+		return defaultNumber, true
+	}
+	switch t := position.Type.(type) {
+	case *ab.SeekPosition_Oldest:
+		return 0, true
+	case *ab.SeekPosition_Newest:
+		height := ledger.Height()
+		if height == 0 {
+			return 0, true
+		}
+		return height - 1, true
+	case *ab.SeekPosition_Specified:
+		if t.Specified == nil {
+			return math.MaxUint64, true
+		}
+		return t.Specified.Number, true
+	default:
+		return 0, false
+	}
+}
+
+// toFilteredBlock returns the filtered block of the given block, which contains the IDs, types
+// and validation codes of the transactions as well as their chaincode events (without payloads)
+func toFilteredBlock(block *common.Block, channelID string) (*pb.FilteredBlock, error) {
+	filteredBlock := &pb.FilteredBlock{ChannelId: channelID, Number: block.Header.Number}
+
+	txFilter := block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER]
+	for i, data := range block.Data.Data {
+		envelope, err := utils.GetEnvelopeFromBlock(data)
+		if err != nil {
+			return nil, err
+		}
+		payload, err := utils.GetPayload(envelope)
+		if err != nil {
+			return nil, err
+		}
+		chHeader, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			return nil, err
+		}
+
+		filteredTx := &pb.FilteredTransaction{
+			Txid:             chHeader.TxId,
+			Type:             common.HeaderType(chHeader.Type),
+			TxValidationCode: pb.TxValidationCode(txFilter[i]),
+		}
+		if filteredTx.Type == common.HeaderType_ENDORSER_TRANSACTION {
+			if event := chaincodeEventFromPayload(payload); event != nil {
+				event.Payload = nil
+				filteredTx.Data = &pb.FilteredTransaction_TransactionActions{
+					TransactionActions: &pb.FilteredTransactionActions{
+						ChaincodeActions: []*pb.FilteredChaincodeAction{{ChaincodeEvent: event}},
+					},
+				}
+			}
+		}
+		filteredBlock.FilteredTransactions = append(filteredBlock.FilteredTransactions, filteredTx)
+	}
+
+	return filteredBlock, nil
+}
+
+func chaincodeEventFromPayload(payload *common.Payload) *pb.ChaincodeEvent {
+	action, err := chaincodeActionFromPayload(payload)
+	if err != nil || len(action.Events) == 0 {
+		return nil
+	}
+	event, err := utils.GetChaincodeEvents(action.Events)
+	if err != nil {
+		return nil
+	}
+	return event
+}
@@ -0,0 +1,162 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocknetwork
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	grpcpeer "google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+// faults holds the faults injected into the requests of a peer or orderer. Faults are
+// deterministic: they apply to every request (latency) or to the next N requests.
+type faults struct {
+	lock          sync.Mutex
+	latency       time.Duration
+	resets        int
+	failures      int
+	failure       error
+	corruptBlocks int
+}
+
+// SetLatency delays every request by the given duration (zero disables the latency)
+func (s *server) SetLatency(latency time.Duration) {
+	s.faults.lock.Lock()
+	defer s.faults.lock.Unlock()
+
+	s.faults.latency = latency
+}
+
+// ResetConnections closes the client connections of the next n requests without responding
+func (s *server) ResetConnections(n int) {
+	s.faults.lock.Lock()
+	defer s.faults.lock.Unlock()
+
+	s.faults.resets = n
+}
+
+// FailRequests fails the next n requests with the given GRPC code and message
+func (s *server) FailRequests(n int, code codes.Code, message string) {
+	s.faults.lock.Lock()
+	defer s.faults.lock.Unlock()
+
+	s.faults.failures = n
+	s.faults.failure = status.Error(code, message)
+}
+
+// CorruptBlocks corrupts the data of the next n blocks delivered by the server (filtered
+// blocks aren't corrupted), so that the data hashes of their headers don't match.
+func (s *server) CorruptBlocks(n int) {
+	s.faults.lock.Lock()
+	defer s.faults.lock.Unlock()
+
+	s.faults.corruptBlocks = n
+}
+
+// inject injects the faults into a request. An error is returned if the request must fail.
+func (s *server) inject(ctx context.Context) error {
+	s.faults.lock.Lock()
+	latency := s.faults.latency
+	reset := s.faults.resets > 0
+	if reset {
+		s.faults.resets--
+	}
+	var err error
+	if !reset && s.faults.failures > 0 {
+		s.faults.failures--
+		err = s.faults.failure
+	}
+	s.faults.lock.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if reset {
+		s.closeConn(ctx)
+		return status.Error(codes.Unavailable, "connection reset")
+	}
+	return err
+}
+
+// corrupt returns a corrupted copy of the block if blocks must be corrupted
+func (s *server) corrupt(block *common.Block) *common.Block {
+	s.faults.lock.Lock()
+	defer s.faults.lock.Unlock()
+
+	if s.faults.corruptBlocks == 0 {
+		return block
+	}
+	s.faults.corruptBlocks--
+
+	return &common.Block{
+		Header:   block.Header,
+		Data:     &common.BlockData{Data: [][]byte{[]byte("corrupted")}},
+		Metadata: block.Metadata,
+	}
+}
+
+// closeConn closes the client connection of the request
+func (s *server) closeConn(ctx context.Context) {
+	p, ok := grpcpeer.FromContext(ctx)
+	if !ok {
+		return
+	}
+
+	s.connLock.Lock()
+	conn, ok := s.conns[p.Addr.String()]
+	s.connLock.Unlock()
+
+	if ok {
+		conn.Close() //nolint
+	}
+}
+
+// trackingListener keeps track of the accepted connections of a server so that they can be reset
+type trackingListener struct {
+	net.Listener
+	server *server
+}
+
+func (l *trackingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	tracked := &trackedConn{Conn: conn, server: l.server}
+
+	l.server.connLock.Lock()
+	l.server.conns[conn.RemoteAddr().String()] = tracked
+	l.server.connLock.Unlock()
+
+	return tracked, nil
+}
+
+type trackedConn struct {
+	net.Conn
+	server *server
+}
+
+func (c *trackedConn) Close() error {
+	c.server.connLock.Lock()
+	delete(c.server.conns, c.RemoteAddr().String())
+	c.server.connLock.Unlock()
+
+	return c.Conn.Close()
+}
@@ -0,0 +1,354 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocknetwork
+
+import (
+	"crypto/sha256"
+	"encoding/asn1"
+	"math/big"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// Version is the version of a key, i.e. the block number and transaction number of the last write
+type Version struct {
+	BlockNum uint64
+	TxNum    uint64
+}
+
+type versionedValue struct {
+	value   []byte
+	version Version
+}
+
+// Ledger is the in-memory ledger of a channel, which is shared by all of the mock peers and the
+// mock orderer of the network. The state and the chaincodes of the ledger are scriptable: the
+// state may be populated with PutState and chaincodes are registered with RegisterChaincode.
+type Ledger struct {
+	channelID   string
+	lock        sync.RWMutex
+	state       map[string]map[string]*versionedValue
+	chaincodes  map[string]Chaincode
+	blocks      []*common.Block
+	txs         map[string]*pb.ProcessedTransaction
+	configBlock *common.Block
+	newBlock    chan struct{}
+}
+
+func newLedger(channelID string) *Ledger {
+	return &Ledger{
+		channelID:  channelID,
+		state:      make(map[string]map[string]*versionedValue),
+		chaincodes: make(map[string]Chaincode),
+		txs:        make(map[string]*pb.ProcessedTransaction),
+		newBlock:   make(chan struct{}),
+	}
+}
+
+// ChannelID returns the ID of the ledger's channel
+func (l *Ledger) ChannelID() string {
+	return l.channelID
+}
+
+// RegisterChaincode registers the function which handles the proposals for the given chaincode
+func (l *Ledger) RegisterChaincode(name string, cc Chaincode) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.chaincodes[name] = cc
+}
+
+// PutState sets the value of a key of the given chaincode without committing a transaction
+func (l *Ledger) PutState(ccName, key string, value []byte) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.put(ccName, key, value, Version{})
+}
+
+// GetState returns the committed value of a key of the given chaincode or nil if the key doesn't exist
+func (l *Ledger) GetState(ccName, key string) []byte {
+	value, _ := l.getState(ccName, key)
+	return value
+}
+
+// DelState deletes a key of the given chaincode without committing a transaction
+func (l *Ledger) DelState(ccName, key string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	delete(l.state[ccName], key)
+}
+
+// State returns a snapshot of the committed state of the given chaincode
+func (l *Ledger) State(ccName string) map[string][]byte {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	state := make(map[string][]byte, len(l.state[ccName]))
+	for key, v := range l.state[ccName] {
+		state[key] = v.value
+	}
+	return state
+}
+
+// SetConfigBlock sets the block which is returned for config block queries (cscc GetConfigBlock)
+func (l *Ledger) SetConfigBlock(block *common.Block) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.configBlock = block
+}
+
+// Height returns the number of blocks in the ledger
+func (l *Ledger) Height() uint64 {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	return uint64(len(l.blocks))
+}
+
+// Block returns the block with the given number or nil if the block doesn't exist
+func (l *Ledger) Block(number uint64) *common.Block {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	if number >= uint64(len(l.blocks)) {
+		return nil
+	}
+	return l.blocks[number]
+}
+
+// Transaction returns the committed transaction with the given ID or nil if the transaction doesn't exist
+func (l *Ledger) Transaction(txID string) *pb.ProcessedTransaction {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	return l.txs[txID]
+}
+
+// Commit validates the given transaction envelopes, cuts a block which contains them and
+// applies the writes of the valid transactions to the state. The block is returned.
+func (l *Ledger) Commit(envelopes ...*common.Envelope) (*common.Block, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	number := uint64(len(l.blocks))
+	block := &common.Block{
+		Header:   &common.BlockHeader{Number: number},
+		Data:     &common.BlockData{},
+		Metadata: &common.BlockMetadata{Metadata: make([][]byte, len(common.BlockMetadataIndex_name))},
+	}
+	if number > 0 {
+		block.Header.PreviousHash = blockHeaderHash(l.blocks[number-1].Header)
+	}
+
+	txFilter := make([]byte, len(envelopes))
+	for i, envelope := range envelopes {
+		envelopeBytes, err := proto.Marshal(envelope)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshal envelope failed")
+		}
+		block.Data.Data = append(block.Data.Data, envelopeBytes)
+		txFilter[i] = byte(l.validateAndApply(envelope, number, uint64(i)))
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txFilter
+	block.Header.DataHash = blockDataHash(block.Data)
+
+	l.blocks = append(l.blocks, block)
+
+	// Wake up the deliver streams which are waiting for the block
+	close(l.newBlock)
+	l.newBlock = make(chan struct{})
+
+	return block, nil
+}
+
+// validateAndApply validates the transaction and, if it's valid, applies its writes to the state.
+// The caller must hold the write lock.
+func (l *Ledger) validateAndApply(envelope *common.Envelope, blockNum, txNum uint64) pb.TxValidationCode {
+	payload, err := utils.GetPayload(envelope)
+	if err != nil || payload.Header == nil {
+		return pb.TxValidationCode_BAD_PAYLOAD
+	}
+	chHeader, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return pb.TxValidationCode_BAD_CHANNEL_HEADER
+	}
+	if chHeader.TxId != "" {
+		if _, ok := l.txs[chHeader.TxId]; ok {
+			return pb.TxValidationCode_DUPLICATE_TXID
+		}
+	}
+
+	code := pb.TxValidationCode_VALID
+	if common.HeaderType(chHeader.Type) == common.HeaderType_ENDORSER_TRANSACTION {
+		code = l.validateAndApplyRWSet(payload, blockNum, txNum)
+	}
+
+	if chHeader.TxId != "" {
+		l.txs[chHeader.TxId] = &pb.ProcessedTransaction{TransactionEnvelope: envelope, ValidationCode: int32(code)}
+	}
+	return code
+}
+
+func (l *Ledger) validateAndApplyRWSet(payload *common.Payload, blockNum, txNum uint64) pb.TxValidationCode {
+	txRWSet, err := rwSetFromPayload(payload)
+	if err != nil {
+		return pb.TxValidationCode_BAD_RWSET
+	}
+
+	// MVCC validation - the keys which were read must not have been modified since
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		for _, read := range nsRWSet.KvRwSet.Reads {
+			current := l.state[nsRWSet.NameSpace][read.Key]
+			switch {
+			case current == nil && read.Version == nil:
+			case current == nil || read.Version == nil:
+				return pb.TxValidationCode_MVCC_READ_CONFLICT
+			case current.version.BlockNum != read.Version.BlockNum || current.version.TxNum != read.Version.TxNum:
+				return pb.TxValidationCode_MVCC_READ_CONFLICT
+			}
+		}
+	}
+
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		for _, write := range nsRWSet.KvRwSet.Writes {
+			if write.IsDelete {
+				delete(l.state[nsRWSet.NameSpace], write.Key)
+				continue
+			}
+			l.put(nsRWSet.NameSpace, write.Key, write.Value, Version{BlockNum: blockNum, TxNum: txNum})
+		}
+	}
+	return pb.TxValidationCode_VALID
+}
+
+// put sets the value of a key. The caller must hold the write lock.
+func (l *Ledger) put(ns, key string, value []byte, version Version) {
+	nsState, ok := l.state[ns]
+	if !ok {
+		nsState = make(map[string]*versionedValue)
+		l.state[ns] = nsState
+	}
+	nsState[key] = &versionedValue{value: value, version: version}
+}
+
+func (l *Ledger) getState(ns, key string) ([]byte, *Version) {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	v, ok := l.state[ns][key]
+	if !ok {
+		return nil, nil
+	}
+	version := v.version
+	return v.value, &version
+}
+
+func (l *Ledger) chaincode(name string) (Chaincode, bool) {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	cc, ok := l.chaincodes[name]
+	return cc, ok
+}
+
+// blocksFrom returns the blocks starting at the given number and a channel which is
+// closed when the next block is committed
+func (l *Ledger) blocksFrom(number uint64) ([]*common.Block, <-chan struct{}) {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	if number >= uint64(len(l.blocks)) {
+		return nil, l.newBlock
+	}
+	return l.blocks[number:], l.newBlock
+}
+
+func (l *Ledger) chainInfo() *common.BlockchainInfo {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	info := &common.BlockchainInfo{Height: uint64(len(l.blocks))}
+	if n := len(l.blocks); n > 0 {
+		info.CurrentBlockHash = blockHeaderHash(l.blocks[n-1].Header)
+		info.PreviousBlockHash = l.blocks[n-1].Header.PreviousHash
+	}
+	return info
+}
+
+// rwSetFromPayload returns the read-write set of an endorser transaction
+func rwSetFromPayload(payload *common.Payload) (*rwsetutil.TxRwSet, error) {
+	action, err := chaincodeActionFromPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(action.Results); err != nil {
+		return nil, errors.Wrap(err, "unmarshal read-write set failed")
+	}
+	return txRWSet, nil
+}
+
+// chaincodeActionFromPayload returns the chaincode action of an endorser transaction
+func chaincodeActionFromPayload(payload *common.Payload) (*pb.ChaincodeAction, error) {
+	tx, err := utils.GetTransaction(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+	if len(tx.Actions) == 0 {
+		return nil, errors.New("transaction has no actions")
+	}
+	ccActionPayload, err := utils.GetChaincodeActionPayload(tx.Actions[0].Payload)
+	if err != nil {
+		return nil, err
+	}
+	if ccActionPayload.Action == nil {
+		return nil, errors.New("chaincode action payload has no endorsed action")
+	}
+	prp, err := utils.GetProposalResponsePayload(ccActionPayload.Action.ProposalResponsePayload)
+	if err != nil {
+		return nil, err
+	}
+	return utils.GetChaincodeAction(prp.Extension)
+}
+
+type asn1Header struct {
+	Number       *big.Int
+	PreviousHash []byte
+	DataHash     []byte
+}
+
+func blockHeaderHash(header *common.BlockHeader) []byte {
+	bytes, err := asn1.Marshal(asn1Header{
+		Number:       new(big.Int).SetUint64(header.Number),
+		PreviousHash: header.PreviousHash,
+		DataHash:     header.DataHash,
+	})
+	if err != nil {
+		// Not expected since all of the fields can be encoded
+		return nil
+	}
+	hash := sha256.Sum256(bytes)
+	return hash[:]
+}
+
+func blockDataHash(data *common.BlockData) []byte {
+	h := sha256.New()
+	for _, d := range data.Data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
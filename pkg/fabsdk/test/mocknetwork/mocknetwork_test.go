@@ -0,0 +1,303 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocknetwork
+
+import (
+	reqContext "context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/orderer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+const (
+	channelID = "mychannel"
+	ccName    = "counter"
+)
+
+// counter increments the value of the key given as the argument
+func counter(stub *Stub) pb.Response {
+	fcn, args := stub.GetFunctionAndParameters()
+	if len(args) != 1 {
+		return Error("expecting one argument")
+	}
+
+	value := 0
+	if bytes := stub.GetState(args[0]); bytes != nil {
+		value, _ = strconv.Atoi(string(bytes))
+	}
+	if fcn == "get" {
+		return Success([]byte(strconv.Itoa(value)))
+	}
+
+	value++
+	stub.PutState(args[0], []byte(strconv.Itoa(value)))
+	stub.SetEvent("incremented", []byte(args[0]))
+	return Success([]byte(strconv.Itoa(value)))
+}
+
+type testNetwork struct {
+	*Network
+	ctx      *mocks.MockContext
+	reqCtx   reqContext.Context
+	peer     fab.ProposalProcessor
+	orderer  fab.Orderer
+	mockPeer *Peer
+}
+
+func newTestNetwork(t *testing.T) (*testNetwork, reqContext.CancelFunc) {
+	network := New()
+	network.Ledger(channelID).RegisterChaincode(ccName, counter)
+
+	mockPeer, err := network.StartPeer("127.0.0.1:0")
+	require.NoError(t, err)
+	mockOrderer, err := network.StartOrderer("127.0.0.1:0")
+	require.NoError(t, err)
+
+	p, err := peer.New(mocks.NewMockEndpointConfig(), peer.WithURL(mockPeer.URL()), peer.WithInsecure())
+	require.NoError(t, err)
+	o, err := orderer.New(mocks.NewMockEndpointConfig(), orderer.WithURL(mockOrderer.URL()), orderer.WithInsecure())
+	require.NoError(t, err)
+
+	ctx := mocks.NewMockContext(mspmocks.NewMockSigningIdentity("user", "Org1MSP"))
+	reqCtx, cancel := context.NewRequest(ctx, context.WithTimeout(10*time.Second))
+
+	return &testNetwork{Network: network, ctx: ctx, reqCtx: reqCtx, peer: p, orderer: o, mockPeer: mockPeer}, func() {
+		cancel()
+		network.Stop()
+	}
+}
+
+func (n *testNetwork) endorse(t *testing.T, cc, fcn string, args ...string) (*fab.TransactionProposal, []*fab.TransactionProposalResponse) {
+	txh, err := txn.NewHeader(n.ctx, channelID)
+	require.NoError(t, err)
+
+	var argBytes [][]byte
+	for _, arg := range args {
+		argBytes = append(argBytes, []byte(arg))
+	}
+	proposal, err := txn.CreateChaincodeInvokeProposal(txh, fab.ChaincodeInvokeRequest{ChaincodeID: cc, Fcn: fcn, Args: argBytes})
+	require.NoError(t, err)
+
+	responses, err := txn.SendProposal(n.reqCtx, proposal, []fab.ProposalProcessor{n.peer})
+	require.NoError(t, err)
+	require.Len(t, responses, 1)
+	return proposal, responses
+}
+
+func (n *testNetwork) commit(t *testing.T, proposal *fab.TransactionProposal, responses []*fab.TransactionProposalResponse) {
+	tx, err := txn.New(fab.TransactionRequest{Proposal: proposal, ProposalResponses: responses})
+	require.NoError(t, err)
+	_, err = txn.Send(n.reqCtx, tx, []fab.Orderer{n.orderer})
+	require.NoError(t, err)
+}
+
+func TestEndorseAndCommit(t *testing.T) {
+	network, stop := newTestNetwork(t)
+	defer stop()
+
+	ledger := network.Ledger(channelID)
+	ledger.PutState(ccName, "a", []byte("10"))
+
+	proposal, responses := network.endorse(t, ccName, "inc", "a")
+	assert.Equal(t, "11", string(responses[0].ProposalResponse.Response.Payload))
+	assert.Equal(t, "10", string(ledger.GetState(ccName, "a")), "state should not change before the transaction is committed")
+
+	network.commit(t, proposal, responses)
+	assert.Equal(t, "11", string(ledger.GetState(ccName, "a")))
+	assert.EqualValues(t, 1, ledger.Height())
+
+	tx := ledger.Transaction(string(proposal.TxnID))
+	require.NotNil(t, tx)
+	assert.EqualValues(t, pb.TxValidationCode_VALID, tx.ValidationCode)
+
+	// A chaincode error isn't endorsed
+	_, responses = network.endorse(t, ccName, "inc")
+	assert.EqualValues(t, 500, responses[0].Status)
+	assert.Equal(t, "expecting one argument", responses[0].ProposalResponse.Response.Message)
+	assert.Nil(t, responses[0].ProposalResponse.Endorsement)
+}
+
+func TestMVCCReadConflict(t *testing.T) {
+	network, stop := newTestNetwork(t)
+	defer stop()
+
+	proposal1, responses1 := network.endorse(t, ccName, "inc", "a")
+	proposal2, responses2 := network.endorse(t, ccName, "inc", "a")
+
+	network.commit(t, proposal1, responses1)
+	network.commit(t, proposal2, responses2)
+
+	ledger := network.Ledger(channelID)
+	assert.Equal(t, "1", string(ledger.GetState(ccName, "a")))
+	assert.EqualValues(t, pb.TxValidationCode_VALID, ledger.Transaction(string(proposal1.TxnID)).ValidationCode)
+	assert.EqualValues(t, pb.TxValidationCode_MVCC_READ_CONFLICT, ledger.Transaction(string(proposal2.TxnID)).ValidationCode)
+
+	block := ledger.Block(1)
+	require.NotNil(t, block)
+	assert.Equal(t, blockHeaderHash(ledger.Block(0).Header), block.Header.PreviousHash)
+}
+
+func TestQuerySystemChaincodes(t *testing.T) {
+	network, stop := newTestNetwork(t)
+	defer stop()
+
+	proposal, responses := network.endorse(t, ccName, "inc", "a")
+	network.commit(t, proposal, responses)
+
+	_, responses = network.endorse(t, "qscc", "GetChainInfo", channelID)
+	info := &common.BlockchainInfo{}
+	require.NoError(t, proto.Unmarshal(responses[0].ProposalResponse.Response.Payload, info))
+	assert.EqualValues(t, 1, info.Height)
+
+	_, responses = network.endorse(t, "qscc", "GetBlockByNumber", channelID, "0")
+	block := &common.Block{}
+	require.NoError(t, proto.Unmarshal(responses[0].ProposalResponse.Response.Payload, block))
+	assert.EqualValues(t, 0, block.Header.Number)
+
+	network.Ledger(channelID).SetConfigBlock(&common.Block{Header: &common.BlockHeader{Number: 0}, Data: &common.BlockData{Data: [][]byte{[]byte("config")}}})
+	_, responses = network.endorse(t, "cscc", "GetConfigBlock", channelID)
+	require.NoError(t, proto.Unmarshal(responses[0].ProposalResponse.Response.Payload, block))
+	assert.Equal(t, []byte("config"), block.Data.Data[0])
+}
+
+func TestDeliverFiltered(t *testing.T) {
+	network, stop := newTestNetwork(t)
+	defer stop()
+
+	conn, err := grpc.Dial(network.mockPeer.Address(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	stream, err := pb.NewDeliverClient(conn).DeliverFiltered(network.reqCtx)
+	require.NoError(t, err)
+
+	// Seek from the oldest block without stopping
+	seekInfo := &ab.SeekInfo{
+		Start:    &ab.SeekPosition{Type: &ab.SeekPosition_Oldest{Oldest: &ab.SeekOldest{}}},
+		Stop:     &ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: ^uint64(0)}}},
+		Behavior: ab.SeekInfo_BLOCK_UNTIL_READY,
+	}
+	payload := &common.Payload{
+		Header: &common.Header{ChannelHeader: utils.MarshalOrPanic(utils.MakeChannelHeader(common.HeaderType_DELIVER_SEEK_INFO, 0, channelID, 0))},
+		Data:   utils.MarshalOrPanic(seekInfo),
+	}
+	require.NoError(t, stream.Send(&common.Envelope{Payload: utils.MarshalOrPanic(payload)}))
+
+	// The block is delivered once the transaction is committed
+	proposal, responses := network.endorse(t, ccName, "inc", "a")
+	network.commit(t, proposal, responses)
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	filteredBlock := resp.GetFilteredBlock()
+	require.NotNil(t, filteredBlock)
+	assert.Equal(t, channelID, filteredBlock.ChannelId)
+	assert.EqualValues(t, 0, filteredBlock.Number)
+	require.Len(t, filteredBlock.FilteredTransactions, 1)
+
+	filteredTx := filteredBlock.FilteredTransactions[0]
+	assert.Equal(t, string(proposal.TxnID), filteredTx.Txid)
+	assert.Equal(t, pb.TxValidationCode_VALID, filteredTx.TxValidationCode)
+	ccActions := filteredTx.GetTransactionActions().ChaincodeActions
+	require.Len(t, ccActions, 1)
+	assert.Equal(t, "incremented", ccActions[0].ChaincodeEvent.EventName)
+	assert.Empty(t, ccActions[0].ChaincodeEvent.Payload, "filtered events should not have a payload")
+}
+
+func TestEndorsementFaults(t *testing.T) {
+	network, stop := newTestNetwork(t)
+	defer stop()
+
+	network.mockPeer.SetLatency(100 * time.Millisecond)
+	start := time.Now()
+	network.endorse(t, ccName, "inc", "a")
+	assert.True(t, time.Since(start) >= 100*time.Millisecond, "expected proposal to be delayed")
+	network.mockPeer.SetLatency(0)
+
+	network.mockPeer.FailEndorsements(1, 503, "endorsement failure")
+	_, responses := network.endorse(t, ccName, "inc", "a")
+	assert.EqualValues(t, 503, responses[0].Status)
+	assert.Nil(t, responses[0].ProposalResponse.Endorsement)
+
+	_, responses = network.endorse(t, ccName, "inc", "a")
+	assert.EqualValues(t, 200, responses[0].Status)
+	assert.NotNil(t, responses[0].ProposalResponse.Endorsement)
+
+	network.mockPeer.FailRequests(1, codes.Unavailable, "peer unavailable")
+	conn, err := grpc.Dial(network.mockPeer.Address(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = pb.NewEndorserClient(conn).ProcessProposal(network.reqCtx, &pb.SignedProposal{})
+	rpcStatus, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.Unavailable, rpcStatus.Code())
+
+	network.mockPeer.ResetConnections(1)
+	_, err = pb.NewEndorserClient(conn).ProcessProposal(network.reqCtx, &pb.SignedProposal{})
+	assert.Error(t, err, "expected connection to be reset")
+}
+
+func TestCorruptBlocks(t *testing.T) {
+	network, stop := newTestNetwork(t)
+	defer stop()
+
+	proposal, responses := network.endorse(t, ccName, "inc", "a")
+	network.commit(t, proposal, responses)
+
+	mockOrderer, err := network.StartOrderer("127.0.0.1:0")
+	require.NoError(t, err)
+	mockOrderer.CorruptBlocks(1)
+
+	conn, err := grpc.Dial(mockOrderer.Address(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	seekInfo := &ab.SeekInfo{
+		Start:    &ab.SeekPosition{Type: &ab.SeekPosition_Oldest{Oldest: &ab.SeekOldest{}}},
+		Stop:     &ab.SeekPosition{Type: &ab.SeekPosition_Oldest{Oldest: &ab.SeekOldest{}}},
+		Behavior: ab.SeekInfo_FAIL_IF_NOT_READY,
+	}
+	payload := &common.Payload{
+		Header: &common.Header{ChannelHeader: utils.MarshalOrPanic(utils.MakeChannelHeader(common.HeaderType_DELIVER_SEEK_INFO, 0, channelID, 0))},
+		Data:   utils.MarshalOrPanic(seekInfo),
+	}
+
+	deliverBlock := func() *common.Block {
+		stream, err := ab.NewAtomicBroadcastClient(conn).Deliver(network.reqCtx)
+		require.NoError(t, err)
+		require.NoError(t, stream.Send(&common.Envelope{Payload: utils.MarshalOrPanic(payload)}))
+		resp, err := stream.Recv()
+		require.NoError(t, err)
+		require.NotNil(t, resp.GetBlock())
+		return resp.GetBlock()
+	}
+
+	block := deliverBlock()
+	assert.NotEqual(t, blockDataHash(block.Data), block.Header.DataHash, "expected block to be corrupted")
+
+	block = deliverBlock()
+	assert.Equal(t, blockDataHash(block.Data), block.Header.DataHash)
+}
@@ -0,0 +1,157 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package mocknetwork provides an in-process mock Fabric network for unit tests. The network
+// consists of mock peers (endorser and deliver services) and mock orderers (broadcast and deliver
+// services) which share in-memory, scriptable ledgers, so that applications can test their channel,
+// resource management and event flows against GRPC endpoints without running a Fabric network.
+//
+// Basic flow:
+//  1) Create the network and script the ledgers of its channels
+//  2) Start the peers and orderers of the network
+//  3) Point the SDK (or peer/orderer clients) at the URLs of the peers and orderers
+//  4) Stop the network
+//
+//  net := mocknetwork.New()
+//  defer net.Stop()
+//
+//  ledger := net.Ledger("mychannel")
+//  ledger.PutState("mycc", "key", []byte("value"))
+//  ledger.RegisterChaincode("mycc", func(stub *mocknetwork.Stub) pb.Response {
+//      _, args := stub.GetFunctionAndParameters()
+//      stub.PutState(args[0], []byte(args[1]))
+//      return mocknetwork.Success(nil)
+//  })
+//
+//  peer, err := net.StartPeer("127.0.0.1:0")
+//  orderer, err := net.StartOrderer("127.0.0.1:0")
+//
+// Faults can be injected into the requests of the peers and orderers in order to test resilience
+// logic (retry, failover, reconnect) deterministically:
+//
+//  peer.SetLatency(100 * time.Millisecond)                   // delay every request
+//  peer.ResetConnections(1)                                  // close the connection of the next request
+//  orderer.FailRequests(2, codes.Unavailable, "unavailable") // fail the next two requests
+//  orderer.CorruptBlocks(1)                                  // corrupt the next delivered block
+//  peer.FailEndorsements(1, 500, "endorsement failure")      // refuse to endorse the next proposal
+//
+// The mock peers and orderers don't check signatures or policies and serve insecure (non-TLS) connections.
+package mocknetwork
+
+import (
+	"net"
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// Network is an in-process mock Fabric network
+type Network struct {
+	lock    sync.RWMutex
+	ledgers map[string]*Ledger
+	servers []*server
+}
+
+// New returns a new mock network without channels, peers or orderers
+func New() *Network {
+	return &Network{ledgers: make(map[string]*Ledger)}
+}
+
+// Ledger returns the ledger of the given channel. The channel is created if it doesn't exist.
+func (n *Network) Ledger(channelID string) *Ledger {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	ledger, ok := n.ledgers[channelID]
+	if !ok {
+		ledger = newLedger(channelID)
+		n.ledgers[channelID] = ledger
+	}
+	return ledger
+}
+
+func (n *Network) ledger(channelID string) (*Ledger, bool) {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+
+	ledger, ok := n.ledgers[channelID]
+	return ledger, ok
+}
+
+// StartPeer starts a mock peer which listens on the given address (e.g. 127.0.0.1:0
+// for a random port). The peer is joined to all of the channels of the network.
+func (n *Network) StartPeer(address string, opts ...PeerOption) (*Peer, error) {
+	peer := newPeer(n, opts...)
+	if err := n.start(&peer.server, address, peer.register); err != nil {
+		return nil, err
+	}
+	return peer, nil
+}
+
+// StartOrderer starts a mock orderer which listens on the given address
+func (n *Network) StartOrderer(address string) (*Orderer, error) {
+	orderer := newOrderer(n)
+	if err := n.start(&orderer.server, address, orderer.register); err != nil {
+		return nil, err
+	}
+	return orderer, nil
+}
+
+// Stop stops all of the peers and orderers of the network
+func (n *Network) Stop() {
+	n.lock.Lock()
+	servers := n.servers
+	n.servers = nil
+	n.lock.Unlock()
+
+	for _, s := range servers {
+		s.Stop()
+	}
+}
+
+func (n *Network) start(s *server, address string, register func(*grpc.Server)) error {
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		return errors.Wrapf(err, "listen on [%s] failed", address)
+	}
+
+	s.grpcServer = grpc.NewServer()
+	s.address = lis.Addr().String()
+	s.conns = make(map[string]net.Conn)
+	register(s.grpcServer)
+	go s.grpcServer.Serve(&trackingListener{Listener: lis, server: s})
+
+	n.lock.Lock()
+	n.servers = append(n.servers, s)
+	n.lock.Unlock()
+
+	return nil
+}
+
+// server is a GRPC server of the network
+type server struct {
+	grpcServer *grpc.Server
+	address    string
+	faults     faults
+	connLock   sync.Mutex
+	conns      map[string]net.Conn
+}
+
+// Address returns the address on which the server listens
+func (s *server) Address() string {
+	return s.address
+}
+
+// URL returns the URL of the server, which must be accessed without TLS
+func (s *server) URL() string {
+	return "grpc://" + s.address
+}
+
+// Stop stops the server
+func (s *server) Stop() {
+	s.grpcServer.Stop()
+}
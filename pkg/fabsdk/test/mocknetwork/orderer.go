@@ -0,0 +1,89 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocknetwork
+
+import (
+	"io"
+
+	"google.golang.org/grpc"
+
+	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// Orderer is a mock orderer which cuts a block for each broadcast envelope and commits
+// the block to the ledger of the envelope's channel, which notifies the deliver streams
+// of the peers and the orderer.
+type Orderer struct {
+	server
+	network *Network
+}
+
+func newOrderer(network *Network) *Orderer {
+	return &Orderer{network: network}
+}
+
+func (o *Orderer) register(grpcServer *grpc.Server) {
+	ab.RegisterAtomicBroadcastServer(grpcServer, o)
+}
+
+// Broadcast orders the envelopes of a broadcast stream
+func (o *Orderer) Broadcast(srv ab.AtomicBroadcast_BroadcastServer) error {
+	if err := o.inject(srv.Context()); err != nil {
+		return err
+	}
+	for {
+		envelope, err := srv.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		response := &ab.BroadcastResponse{Status: o.order(envelope)}
+		if response.Status != common.Status_SUCCESS {
+			response.Info = "envelope rejected"
+		}
+		if err := srv.Send(response); err != nil {
+			return err
+		}
+	}
+}
+
+func (o *Orderer) order(envelope *common.Envelope) common.Status {
+	payload, err := utils.GetPayload(envelope)
+	if err != nil || payload.Header == nil {
+		return common.Status_BAD_REQUEST
+	}
+	chHeader, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return common.Status_BAD_REQUEST
+	}
+
+	ledger, ok := o.network.ledger(chHeader.ChannelId)
+	if !ok {
+		return common.Status_NOT_FOUND
+	}
+	if _, err := ledger.Commit(envelope); err != nil {
+		return common.Status_INTERNAL_SERVER_ERROR
+	}
+	return common.Status_SUCCESS
+}
+
+// Deliver delivers the blocks of a channel
+func (o *Orderer) Deliver(srv ab.AtomicBroadcast_DeliverServer) error {
+	if err := o.inject(srv.Context()); err != nil {
+		return err
+	}
+	return deliver(o.network, srv, func(block *common.Block, channelID string) error {
+		return srv.Send(&ab.DeliverResponse{Type: &ab.DeliverResponse_Block{Block: o.corrupt(block)}})
+	}, func(status common.Status) error {
+		return srv.Send(&ab.DeliverResponse{Type: &ab.DeliverResponse_Status{Status: status}})
+	})
+}
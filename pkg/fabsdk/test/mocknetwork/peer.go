@@ -0,0 +1,281 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocknetwork
+
+import (
+	"crypto/sha256"
+	"strconv"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mspProtos "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+const (
+	qscc = "qscc"
+	cscc = "cscc"
+)
+
+// Signer signs the proposal responses of a mock peer
+type Signer func(msg []byte) ([]byte, error)
+
+// PeerOption configures a mock peer
+type PeerOption func(*Peer)
+
+// WithIdentity sets the MSP ID and the identity (e.g. PEM encoded certificate) with which the peer
+// endorses proposals and the function which signs the proposal responses. By default, the peer
+// endorses with a fake identity and signature.
+func WithIdentity(mspID string, idBytes []byte, signer Signer) PeerOption {
+	return func(p *Peer) {
+		p.mspID = mspID
+		p.idBytes = idBytes
+		p.signer = signer
+	}
+}
+
+// Peer is a mock peer which endorses proposals using the chaincodes registered with the ledgers of
+// the network and which delivers the blocks of the ledgers. The query system chaincode (qscc
+// GetChainInfo, GetBlockByNumber, GetTransactionByID) and config block queries (cscc GetConfigBlock)
+// are also supported.
+type Peer struct {
+	server
+	network *Network
+	mspID   string
+	idBytes []byte
+	signer  Signer
+
+	failureLock         sync.Mutex
+	endorsementFailures int
+	endorsementFailure  pb.Response
+}
+
+func newPeer(network *Network, opts ...PeerOption) *Peer {
+	p := &Peer{
+		network: network,
+		mspID:   "Org1MSP",
+		idBytes: []byte("peer"),
+		signer: func([]byte) ([]byte, error) {
+			return []byte("signature"), nil
+		},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// FailEndorsements makes the peer refuse to endorse the next n proposals with the given
+// status and message. Only this peer is affected, so failing the endorsements of some of
+// the peers of a channel results in partial endorsement failures.
+func (p *Peer) FailEndorsements(n int, status int32, message string) {
+	p.failureLock.Lock()
+	defer p.failureLock.Unlock()
+
+	p.endorsementFailures = n
+	p.endorsementFailure = pb.Response{Status: status, Message: message}
+}
+
+func (p *Peer) nextEndorsementFailure() (pb.Response, bool) {
+	p.failureLock.Lock()
+	defer p.failureLock.Unlock()
+
+	if p.endorsementFailures == 0 {
+		return pb.Response{}, false
+	}
+	p.endorsementFailures--
+	return p.endorsementFailure, true
+}
+
+func (p *Peer) register(grpcServer *grpc.Server) {
+	pb.RegisterEndorserServer(grpcServer, p)
+	pb.RegisterDeliverServer(grpcServer, p)
+}
+
+// ProcessProposal endorses the given proposal
+func (p *Peer) ProcessProposal(ctx context.Context, signedProposal *pb.SignedProposal) (*pb.ProposalResponse, error) {
+	if err := p.inject(ctx); err != nil {
+		return nil, err
+	}
+	if response, ok := p.nextEndorsementFailure(); ok {
+		return &pb.ProposalResponse{Version: 1, Response: &response}, nil
+	}
+
+	proposal := &pb.Proposal{}
+	if err := proto.Unmarshal(signedProposal.ProposalBytes, proposal); err != nil {
+		return nil, errors.Wrap(err, "unmarshal proposal failed")
+	}
+	header, err := utils.GetHeader(proposal.Header)
+	if err != nil {
+		return nil, err
+	}
+	chHeader, err := utils.UnmarshalChannelHeader(header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	ccProposalPayload, err := utils.GetChaincodeProposalPayload(proposal.Payload)
+	if err != nil {
+		return nil, err
+	}
+	cis := &pb.ChaincodeInvocationSpec{}
+	if err := proto.Unmarshal(ccProposalPayload.Input, cis); err != nil {
+		return nil, errors.Wrap(err, "unmarshal chaincode invocation spec failed")
+	}
+	if cis.ChaincodeSpec == nil || cis.ChaincodeSpec.ChaincodeId == nil || cis.ChaincodeSpec.Input == nil {
+		return nil, errors.New("invalid chaincode invocation spec")
+	}
+
+	ccName := cis.ChaincodeSpec.ChaincodeId.Name
+	args := cis.ChaincodeSpec.Input.Args
+
+	var response pb.Response
+	var stub *Stub
+	switch ccName {
+	case qscc, cscc:
+		response = p.invokeSystemChaincode(ccName, args)
+	default:
+		ledger, ok := p.network.ledger(chHeader.ChannelId)
+		if !ok {
+			return nil, errors.Errorf("channel [%s] not found", chHeader.ChannelId)
+		}
+		cc, ok := ledger.chaincode(ccName)
+		if !ok {
+			return nil, errors.Errorf("chaincode [%s] not found on channel [%s]", ccName, chHeader.ChannelId)
+		}
+		stub = newStub(ledger, ccName, chHeader.TxId, args, ccProposalPayload.TransientMap)
+		response = cc(stub)
+	}
+
+	if response.Status >= 400 {
+		// The proposal isn't endorsed
+		return &pb.ProposalResponse{Version: 1, Response: &response}, nil
+	}
+
+	return p.endorse(proposal, ccName, &response, stub)
+}
+
+func (p *Peer) endorse(proposal *pb.Proposal, ccName string, response *pb.Response, stub *Stub) (*pb.ProposalResponse, error) {
+	var results, event []byte
+	if stub != nil {
+		var err error
+		results, err = stub.rwSet().ToProtoBytes()
+		if err != nil {
+			return nil, errors.Wrap(err, "marshal read-write set failed")
+		}
+		if stub.event != nil {
+			event, err = proto.Marshal(stub.event)
+			if err != nil {
+				return nil, errors.Wrap(err, "marshal chaincode event failed")
+			}
+		}
+	}
+
+	proposalHash := sha256.Sum256(append(append([]byte{}, proposal.Header...), proposal.Payload...))
+	prpBytes, err := utils.GetBytesProposalResponsePayload(proposalHash[:], response, results, event, &pb.ChaincodeID{Name: ccName})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal proposal response payload failed")
+	}
+
+	endorser, err := proto.Marshal(&mspProtos.SerializedIdentity{Mspid: p.mspID, IdBytes: p.idBytes})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal endorser identity failed")
+	}
+	signature, err := p.signer(append(append([]byte{}, prpBytes...), endorser...))
+	if err != nil {
+		return nil, errors.WithMessage(err, "sign proposal response failed")
+	}
+
+	return &pb.ProposalResponse{
+		Version:     1,
+		Response:    response,
+		Payload:     prpBytes,
+		Endorsement: &pb.Endorsement{Endorser: endorser, Signature: signature},
+	}, nil
+}
+
+// invokeSystemChaincode handles the queries of the query and config system chaincodes.
+// The arguments are the function name, the channel ID and the parameters of the function.
+func (p *Peer) invokeSystemChaincode(ccName string, args [][]byte) pb.Response {
+	if len(args) < 2 {
+		return Error("incorrect number of arguments")
+	}
+	fcn, channelID := string(args[0]), string(args[1])
+
+	ledger, ok := p.network.ledger(channelID)
+	if !ok {
+		return Error("channel not found: " + channelID)
+	}
+
+	var result proto.Message
+	switch {
+	case ccName == qscc && fcn == "GetChainInfo":
+		result = ledger.chainInfo()
+	case ccName == qscc && fcn == "GetBlockByNumber" && len(args) > 2:
+		number, err := strconv.ParseUint(string(args[2]), 10, 64)
+		if err != nil {
+			return Error("invalid block number: " + string(args[2]))
+		}
+		if block := ledger.Block(number); block != nil {
+			result = block
+		}
+	case ccName == qscc && fcn == "GetTransactionByID" && len(args) > 2:
+		if tx := ledger.Transaction(string(args[2])); tx != nil {
+			result = tx
+		}
+	case ccName == cscc && fcn == "GetConfigBlock":
+		ledger.lock.RLock()
+		if ledger.configBlock != nil {
+			result = ledger.configBlock
+		}
+		ledger.lock.RUnlock()
+	default:
+		return Error("unsupported function: " + ccName + " " + fcn)
+	}
+
+	if result == nil {
+		return Error("not found")
+	}
+	payload, err := proto.Marshal(result)
+	if err != nil {
+		return Error(err.Error())
+	}
+	return Success(payload)
+}
+
+// Deliver delivers the blocks of a channel
+func (p *Peer) Deliver(srv pb.Deliver_DeliverServer) error {
+	if err := p.inject(srv.Context()); err != nil {
+		return err
+	}
+	return deliver(p.network, srv, func(block *common.Block, channelID string) error {
+		return srv.Send(&pb.DeliverResponse{Type: &pb.DeliverResponse_Block{Block: p.corrupt(block)}})
+	}, func(status common.Status) error {
+		return srv.Send(&pb.DeliverResponse{Type: &pb.DeliverResponse_Status{Status: status}})
+	})
+}
+
+// DeliverFiltered delivers the filtered blocks of a channel
+func (p *Peer) DeliverFiltered(srv pb.Deliver_DeliverFilteredServer) error {
+	if err := p.inject(srv.Context()); err != nil {
+		return err
+	}
+	return deliver(p.network, srv, func(block *common.Block, channelID string) error {
+		filteredBlock, err := toFilteredBlock(block, channelID)
+		if err != nil {
+			return err
+		}
+		return srv.Send(&pb.DeliverResponse{Type: &pb.DeliverResponse_FilteredBlock{FilteredBlock: filteredBlock}})
+	}, func(status common.Status) error {
+		return srv.Send(&pb.DeliverResponse{Type: &pb.DeliverResponse_Status{Status: status}})
+	})
+}
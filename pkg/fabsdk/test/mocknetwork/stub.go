@@ -0,0 +1,148 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocknetwork
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// Chaincode handles the proposals for a chaincode. The response status must be
+// 200 (OK) for the proposal to be endorsed.
+type Chaincode func(stub *Stub) pb.Response
+
+// Success returns a successful chaincode response with the given payload
+func Success(payload []byte) pb.Response {
+	return pb.Response{Status: 200, Payload: payload}
+}
+
+// Error returns a chaincode error response with the given message
+func Error(msg string) pb.Response {
+	return pb.Response{Status: 500, Message: msg}
+}
+
+// Stub gives a chaincode access to the proposal and to the state of the ledger. Reads and writes
+// are recorded in the read-write set of the proposal response; writes are only applied to the
+// state once the transaction is committed.
+type Stub struct {
+	ledger    *Ledger
+	ccName    string
+	channelID string
+	txID      string
+	args      [][]byte
+	transient map[string][]byte
+	reads     map[string]*kvrwset.KVRead
+	readKeys  []string
+	writes    map[string]*kvrwset.KVWrite
+	writeKeys []string
+	event     *pb.ChaincodeEvent
+}
+
+func newStub(ledger *Ledger, ccName, txID string, args [][]byte, transient map[string][]byte) *Stub {
+	return &Stub{
+		ledger:    ledger,
+		ccName:    ccName,
+		channelID: ledger.ChannelID(),
+		txID:      txID,
+		args:      args,
+		transient: transient,
+		reads:     make(map[string]*kvrwset.KVRead),
+		writes:    make(map[string]*kvrwset.KVWrite),
+	}
+}
+
+// GetChannelID returns the ID of the channel
+func (s *Stub) GetChannelID() string {
+	return s.channelID
+}
+
+// GetTxID returns the ID of the transaction
+func (s *Stub) GetTxID() string {
+	return s.txID
+}
+
+// GetArgs returns the arguments of the invocation, including the function name
+func (s *Stub) GetArgs() [][]byte {
+	return s.args
+}
+
+// GetFunctionAndParameters returns the function name (the first argument) and the remaining arguments
+func (s *Stub) GetFunctionAndParameters() (string, []string) {
+	if len(s.args) == 0 {
+		return "", nil
+	}
+	params := make([]string, len(s.args)-1)
+	for i, arg := range s.args[1:] {
+		params[i] = string(arg)
+	}
+	return string(s.args[0]), params
+}
+
+// GetTransient returns the transient data of the proposal
+func (s *Stub) GetTransient() map[string][]byte {
+	return s.transient
+}
+
+// GetState returns the value of the given key, including the writes of this invocation
+func (s *Stub) GetState(key string) []byte {
+	if w, ok := s.writes[key]; ok {
+		if w.IsDelete {
+			return nil
+		}
+		return w.Value
+	}
+
+	value, version := s.ledger.getState(s.ccName, key)
+	if _, ok := s.reads[key]; !ok {
+		read := &kvrwset.KVRead{Key: key}
+		if version != nil {
+			read.Version = &kvrwset.Version{BlockNum: version.BlockNum, TxNum: version.TxNum}
+		}
+		s.reads[key] = read
+		s.readKeys = append(s.readKeys, key)
+	}
+	return value
+}
+
+// PutState records a write of the given key
+func (s *Stub) PutState(key string, value []byte) {
+	s.write(&kvrwset.KVWrite{Key: key, Value: value})
+}
+
+// DelState records a delete of the given key
+func (s *Stub) DelState(key string) {
+	s.write(&kvrwset.KVWrite{Key: key, IsDelete: true})
+}
+
+// SetEvent sets the chaincode event of the transaction
+func (s *Stub) SetEvent(name string, payload []byte) {
+	s.event = &pb.ChaincodeEvent{ChaincodeId: s.ccName, TxId: s.txID, EventName: name, Payload: payload}
+}
+
+func (s *Stub) write(w *kvrwset.KVWrite) {
+	if _, ok := s.writes[w.Key]; !ok {
+		s.writeKeys = append(s.writeKeys, w.Key)
+	}
+	s.writes[w.Key] = w
+}
+
+// rwSet returns the read-write set of the invocation. The reads and writes are in the order
+// in which they were made so that the responses of all peers are identical.
+func (s *Stub) rwSet() *rwsetutil.TxRwSet {
+	kvRWSet := &kvrwset.KVRWSet{}
+	for _, key := range s.readKeys {
+		kvRWSet.Reads = append(kvRWSet.Reads, s.reads[key])
+	}
+	for _, key := range s.writeKeys {
+		kvRWSet.Writes = append(kvRWSet.Writes, s.writes[key])
+	}
+
+	return &rwsetutil.TxRwSet{
+		NsRwSets: []*rwsetutil.NsRwSet{{NameSpace: s.ccName, KvRwSet: kvRWSet}},
+	}
+}
@@ -8,19 +8,60 @@ package api
 
 import (
 	"errors"
+	"time"
 )
 
 var (
 	// ErrCARegistrarNotFound indicates the CA registrar was not found
 	ErrCARegistrarNotFound = errors.New("CA registrar not found")
+
+	// ErrCAChainMismatch indicates the CA chain returned by the CA doesn't match the pinned CA chain
+	ErrCAChainMismatch = errors.New("CA chain doesn't match the pinned CA chain")
+
+	// ErrIdemixNotSupported indicates the CA doesn't issue idemix credentials (e.g. a CA older than v1.3)
+	ErrIdemixNotSupported = errors.New("CA doesn't support idemix")
 )
 
 // CAClient provides management of identities in a Fabric network
 type CAClient interface {
-	Enroll(enrollmentID string, enrollmentSecret string) error
+	Enroll(request *EnrollmentRequest) error
 	Reenroll(enrollmentID string) error
 	Register(request *RegistrationRequest) (string, error)
 	Revoke(request *RevocationRequest) (*RevocationResponse, error)
+	GetCAInfo() (*GetCAInfoResponse, error)
+	GetIdemixIssuerKeys() (*IdemixIssuerKeys, error)
+}
+
+// IdemixIssuerKeys are the public keys of the idemix issuer of a CA, with which the idemix
+// credentials issued by the CA are verified
+type IdemixIssuerKeys struct {
+	// IssuerPublicKey is the serialized idemix issuer public key
+	IssuerPublicKey []byte
+	// RevocationPublicKey is the PEM-encoded revocation public key of the issuer
+	RevocationPublicKey []byte
+}
+
+// GetCAInfoResponse is the response of the GetCAInfo call
+type GetCAInfoResponse struct {
+	// CAName is the name of the CA
+	CAName string
+	// CAChain is the PEM-encoded bytes of the CA chain. The first certificate of the chain is the root CA certificate.
+	CAChain []byte
+	// Version of the CA server
+	Version string
+}
+
+// EnrollmentRequest is a request to enroll an identity
+type EnrollmentRequest struct {
+	// Name is the enrollment ID of the identity
+	Name string
+	// Secret is the enrollment secret returned by registration
+	Secret string
+	// CAName is the name of the CA to connect to (the CA of the configuration by default)
+	CAName string
+	// Profile is the name of the signing profile of the CA (e.g. "tls"). The credentials enrolled with
+	// a profile are stored separately from the enrollment certificate of the identity.
+	Profile string
 }
 
 // AttributeRequest is a request for an attribute.
@@ -36,10 +77,14 @@ type RegistrationRequest struct {
 	// Type of identity being registered (e.g. "peer, app, user")
 	Type string
 	// MaxEnrollments is the number of times the secret can  be reused to enroll.
-	// if omitted, this defaults to max_enrollments configured on the server
+	// if omitted, this defaults to max_enrollments configured on the server.
+	// -1 allows an unlimited number of enrollments.
 	MaxEnrollments int
 	// The identity's affiliation e.g. org1.department1
 	Affiliation string
+	// CreateAffiliation creates the affiliation (and its parent affiliations) before registering
+	// the identity, if it doesn't exist. The registrar must be allowed to manage affiliations.
+	CreateAffiliation bool
 	// Optional attributes associated with this identity
 	Attributes []Attribute
 	// CAName is the name of the CA to connect to
@@ -54,6 +99,8 @@ type RegistrationRequest struct {
 type Attribute struct {
 	Name  string
 	Value string
+	// ECert adds the attribute to the enrollment certificates of the identity by default,
+	// i.e. when the enrollment doesn't request attributes
 	ECert bool
 }
 
@@ -89,3 +136,40 @@ type RevokedCert struct {
 	// AKI of the revoked certificate
 	AKI string
 }
+
+// IdentityEventType is the type of an identity event
+type IdentityEventType string
+
+const (
+	// EnrollEvent is published when an identity is enrolled
+	EnrollEvent IdentityEventType = "enroll"
+	// ReenrollEvent is published when an identity is re-enrolled
+	ReenrollEvent IdentityEventType = "reenroll"
+	// RevokeEvent is published when certificates are revoked
+	RevokeEvent IdentityEventType = "revoke"
+)
+
+// IdentityEvent is published when an identity is successfully enrolled, re-enrolled or revoked with the CA
+type IdentityEvent struct {
+	Type IdentityEventType
+	// ID is the enrollment ID of the identity. It is empty for a revocation by serial and AKI.
+	ID string
+	// MSPID is the MSP ID under which the enrollment certificate is stored
+	MSPID string
+	// Profile is the CA profile of the enrollment, if any
+	Profile string
+	// Certificate is the PEM-encoded enrollment certificate (enrollment and re-enrollment)
+	Certificate []byte
+	// Serial is the serial number of the enrollment certificate, in hex (enrollment and re-enrollment)
+	Serial string
+	// NotAfter is the expiry of the enrollment certificate (enrollment and re-enrollment)
+	NotAfter time.Time
+	// RevokedCerts are the revoked certificates (revocation)
+	RevokedCerts []RevokedCert
+	// Reason is the reason of the revocation
+	Reason string
+}
+
+// IdentityEventHandler is notified of identity events. Handlers are invoked synchronously, after
+// the identity stores were updated, so they should return quickly.
+type IdentityEventHandler func(event *IdentityEvent)
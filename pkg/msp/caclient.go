@@ -7,14 +7,22 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"strings"
 
+	fabricCaUtil "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/util"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/recovery"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/cryptoutil"
 	"github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
 	"github.com/pkg/errors"
 )
@@ -30,10 +38,55 @@ type CAClientImpl struct {
 	userStore       msp.UserStore
 	adapter         *fabricCAAdapter
 	registrar       msp.EnrollCredentials
+	mspDir          string
+	revokedUsers    RevokedUserCleanup
+	eventHandlers   []api.IdentityEventHandler
+}
+
+// RevokedUserCleanup defines what Revoke does with the users of the user store
+// whose enrollment certificates were revoked
+type RevokedUserCleanup int
+
+const (
+	// KeepRevokedUsers leaves the revoked users in the user store (default)
+	KeepRevokedUsers RevokedUserCleanup = iota
+	// MarkRevokedUsers marks the revoked users in the user store (which must implement
+	// msp.UserLister and msp.UserRevocationStore): the identity manager returns msp.ErrUserRevoked for them
+	MarkRevokedUsers
+	// PurgeRevokedUsers deletes the revoked users from the user store (which must implement
+	// msp.UserLister and msp.UserDeleter) and their private keys from the key store
+	PurgeRevokedUsers
+)
+
+// CAClientOption describes a functional parameter for the NewCAClient constructor
+type CAClientOption func(*CAClientImpl)
+
+// WithCAChain pins the CA chain: the enrollments fail with api.ErrCAChainMismatch if the
+// CA returns another CA chain
+func WithCAChain(caChain []byte) CAClientOption {
+	return func(c *CAClientImpl) {
+		c.adapter.caChain = caChain
+	}
+}
+
+// WithRevokedUserCleanup sets what Revoke does with the users of the user store whose
+// enrollment certificates were revoked
+func WithRevokedUserCleanup(cleanup RevokedUserCleanup) CAClientOption {
+	return func(c *CAClientImpl) {
+		c.revokedUsers = cleanup
+	}
+}
+
+// WithIdentityEventHandler registers a handler which is notified when an identity is
+// successfully enrolled, re-enrolled or revoked
+func WithIdentityEventHandler(handler api.IdentityEventHandler) CAClientOption {
+	return func(c *CAClientImpl) {
+		c.eventHandlers = append(c.eventHandlers, handler)
+	}
 }
 
 // NewCAClient creates a new CA CAClient instance
-func NewCAClient(orgName string, ctx contextApi.Client) (*CAClientImpl, error) {
+func NewCAClient(orgName string, ctx contextApi.Client, opts ...CAClientOption) (*CAClientImpl, error) {
 
 	netConfig, err := ctx.EndpointConfig().NetworkConfig()
 	if err != nil {
@@ -93,6 +146,26 @@ func NewCAClient(orgName string, ctx contextApi.Client) (*CAClientImpl, error) {
 		userStore:       ctx.UserStore(),
 		adapter:         adapter,
 		registrar:       registrar,
+		mspDir:          adapter.caClient.Config.MSPDir,
+	}
+	for _, opt := range opts {
+		opt(mgr)
+	}
+
+	if mgr.revokedUsers != KeepRevokedUsers {
+		if _, ok := mgr.userStore.(msp.UserLister); !ok {
+			return nil, errors.New("user store doesn't support listing of users, which is required to clean up revoked users")
+		}
+	}
+	switch mgr.revokedUsers {
+	case MarkRevokedUsers:
+		if _, ok := mgr.userStore.(msp.UserRevocationStore); !ok {
+			return nil, errors.New("user store doesn't support marking of revoked users")
+		}
+	case PurgeRevokedUsers:
+		if _, ok := mgr.userStore.(msp.UserDeleter); !ok {
+			return nil, errors.New("user store doesn't support deletion of revoked users")
+		}
 	}
 	return mgr, nil
 }
@@ -101,37 +174,50 @@ func NewCAClient(orgName string, ctx contextApi.Client) (*CAClientImpl, error) {
 // A new key pair is generated for the user. The private key and the
 // enrollment certificate issued by the CA are stored in SDK stores.
 // They can be retrieved by calling IdentityManager.GetSigningIdentity().
+// The certificate of an enrollment with a profile (e.g. a TLS certificate) is stored
+// separately, under the MSP ID returned by ProfileMSPID.
 //
-// enrollmentID The registered ID to use for enrollment
-// enrollmentSecret The secret associated with the enrollment ID
-func (c *CAClientImpl) Enroll(enrollmentID string, enrollmentSecret string) error {
+// request The enrollment request, with the registered ID and its secret
+func (c *CAClientImpl) Enroll(request *api.EnrollmentRequest) error {
 
 	if c.adapter == nil {
 		return fmt.Errorf("no CAs configured for organization: %s", c.orgName)
 	}
-	if enrollmentID == "" {
+	if request == nil || request.Name == "" {
 		return errors.New("enrollmentID is required")
 	}
-	if enrollmentSecret == "" {
+	if request.Secret == "" {
 		return errors.New("enrollmentSecret is required")
 	}
 	// TODO add attributes
-	cert, err := c.adapter.Enroll(enrollmentID, enrollmentSecret)
+	cert, err := c.adapter.Enroll(request)
 	if err != nil {
 		return errors.Wrap(err, "enroll failed")
 	}
+	mspID := c.orgMSPID
+	if request.Profile != "" {
+		mspID = ProfileMSPID(c.orgMSPID, request.Profile)
+	}
 	userData := &msp.UserData{
-		MSPID: c.orgMSPID,
-		ID:    enrollmentID,
+		MSPID: mspID,
+		ID:    request.Name,
 		EnrollmentCertificate: cert,
 	}
 	err = c.userStore.Store(userData)
 	if err != nil {
 		return errors.Wrap(err, "enroll failed")
 	}
+	c.notify(newEnrollmentEvent(api.EnrollEvent, userData, request.Profile))
 	return nil
 }
 
+// ProfileMSPID returns the MSP ID under which the certificates enrolled with the given profile
+// of the CA by the identities of the MSP are stored in the user store, so that they don't replace
+// the enrollment certificates of the identities
+func ProfileMSPID(mspID, profile string) string {
+	return mspID + "." + profile
+}
+
 // Reenroll an enrolled user in order to obtain a new signed X509 certificate
 func (c *CAClientImpl) Reenroll(enrollmentID string) error {
 
@@ -161,6 +247,7 @@ func (c *CAClientImpl) Reenroll(enrollmentID string) error {
 	if err != nil {
 		return errors.Wrap(err, "reenroll failed")
 	}
+	c.notify(newEnrollmentEvent(api.ReenrollEvent, userData, ""))
 
 	return nil
 }
@@ -182,6 +269,9 @@ func (c *CAClientImpl) Register(request *api.RegistrationRequest) (string, error
 	if request.Name == "" {
 		return "", errors.New("request.Name is required")
 	}
+	if request.MaxEnrollments < -1 {
+		return "", errors.New("request.MaxEnrollments must be -1 (unlimited), 0 (default of the CA) or positive")
+	}
 
 	registrar, err := c.getRegistrar(c.registrar.EnrollID, c.registrar.EnrollSecret)
 	if err != nil {
@@ -199,6 +289,8 @@ func (c *CAClientImpl) Register(request *api.RegistrationRequest) (string, error
 // Revoke a User with the Fabric CA
 // registrar: The User that is initiating the revocation
 // request: Revocation Request
+// The users of the user store whose enrollment certificates were revoked are then
+// marked or purged, if configured (see WithRevokedUserCleanup)
 func (c *CAClientImpl) Revoke(request *api.RevocationRequest) (*api.RevocationResponse, error) {
 	if c.adapter == nil {
 		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
@@ -220,9 +312,141 @@ func (c *CAClientImpl) Revoke(request *api.RevocationRequest) (*api.RevocationRe
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to revoke")
 	}
+
+	if c.revokedUsers != KeepRevokedUsers {
+		if err := c.cleanupRevokedUsers(resp.RevokedCerts); err != nil {
+			return nil, errors.WithMessage(err, "cleanup of revoked users failed")
+		}
+	}
+	c.notify(&api.IdentityEvent{
+		Type:         api.RevokeEvent,
+		ID:           request.Name,
+		MSPID:        c.orgMSPID,
+		RevokedCerts: resp.RevokedCerts,
+		Reason:       request.Reason,
+	})
 	return resp, nil
 }
 
+// cleanupRevokedUsers marks or purges the users of the organization (and of its profile MSP IDs)
+// whose enrollment certificates are in the given list of revoked certificates
+func (c *CAClientImpl) cleanupRevokedUsers(revokedCerts []api.RevokedCert) error {
+	if len(revokedCerts) == 0 {
+		return nil
+	}
+
+	var revokedUsers []*msp.UserData
+	err := ForEachUser(c.userStore, "", func(user *msp.UserData) error {
+		if user.MSPID != c.orgMSPID && !strings.HasPrefix(user.MSPID, c.orgMSPID+".") {
+			return nil
+		}
+		if isCertRevoked(user.EnrollmentCertificate, revokedCerts) {
+			revokedUsers = append(revokedUsers, user)
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.WithMessage(err, "listing users failed")
+	}
+
+	for _, user := range revokedUsers {
+		id := msp.IdentityIdentifier{MSPID: user.MSPID, ID: user.ID}
+		if c.revokedUsers == MarkRevokedUsers {
+			logger.Debugf("Marking revoked user [%s@%s]", user.ID, user.MSPID)
+			if err := c.userStore.(msp.UserRevocationStore).MarkRevoked(id); err != nil {
+				return errors.WithMessage(err, "marking user ["+user.ID+"@"+user.MSPID+"] failed")
+			}
+			continue
+		}
+
+		logger.Debugf("Purging revoked user [%s@%s]", user.ID, user.MSPID)
+		if err := c.deletePrivateKey(user.EnrollmentCertificate); err != nil {
+			return errors.WithMessage(err, "deleting private key of user ["+user.ID+"@"+user.MSPID+"] failed")
+		}
+		if err := c.userStore.(msp.UserDeleter).Delete(id); err != nil {
+			return errors.WithMessage(err, "deleting user ["+user.ID+"@"+user.MSPID+"] failed")
+		}
+	}
+	return nil
+}
+
+// deletePrivateKey deletes the private key matching the given enrollment certificate
+// from the key store. The keys which aren't stored in files (e.g. in a HSM) are left as is.
+func (c *CAClientImpl) deletePrivateKey(cert []byte) error {
+	if c.mspDir == "" {
+		return nil
+	}
+	pubKey, err := cryptoutil.GetPublicKeyFromCert(cert, c.cryptoSuite)
+	if err != nil {
+		return errors.WithMessage(err, "fetching public key from cert failed")
+	}
+	// The fabric CA client adds the 'keystore' directory to the MSP directory
+	keyFile := filepath.Join(c.mspDir, "keystore", hex.EncodeToString(pubKey.SKI())+"_sk")
+	if err := os.Remove(keyFile); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// isCertRevoked returns true if the serial and the AKI of the given PEM encoded certificate
+// match one of the revoked certificates
+func isCertRevoked(cert []byte, revokedCerts []api.RevokedCert) bool {
+	block, _ := pem.Decode(cert)
+	if block == nil {
+		return false
+	}
+	x509Cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		logger.Warnf("Parsing stored enrollment certificate failed: %s", err)
+		return false
+	}
+	serial := fabricCaUtil.GetSerialAsHex(x509Cert.SerialNumber)
+	aki := hex.EncodeToString(x509Cert.AuthorityKeyId)
+	for _, revokedCert := range revokedCerts {
+		if strings.EqualFold(strings.TrimLeft(revokedCert.Serial, "0"), serial) && strings.EqualFold(revokedCert.AKI, aki) {
+			return true
+		}
+	}
+	return false
+}
+
+// notify invokes the identity event handlers. A panicking handler doesn't affect the others.
+func (c *CAClientImpl) notify(event *api.IdentityEvent) {
+	for _, handler := range c.eventHandlers {
+		if err := recovery.Call(func() { handler(event) }); err != nil {
+			logger.Errorf("Identity event handler failed on %s event of [%s]: %s", event.Type, event.ID, err)
+		}
+	}
+}
+
+// newEnrollmentEvent returns the event of an enrollment or a re-enrollment, with the metadata
+// of the enrollment certificate
+func newEnrollmentEvent(eventType api.IdentityEventType, userData *msp.UserData, profile string) *api.IdentityEvent {
+	event := &api.IdentityEvent{
+		Type:        eventType,
+		ID:          userData.ID,
+		MSPID:       userData.MSPID,
+		Profile:     profile,
+		Certificate: userData.EnrollmentCertificate,
+	}
+	cert, err := fabricCaUtil.GetX509CertificateFromPEM(userData.EnrollmentCertificate)
+	if err != nil {
+		logger.Warnf("Parsing enrollment certificate of [%s] failed: %s", userData.ID, err)
+		return event
+	}
+	event.Serial = fabricCaUtil.GetSerialAsHex(cert.SerialNumber)
+	event.NotAfter = cert.NotAfter
+	return event
+}
+
+// GetCAInfo returns the name, the chain and the version of the CA
+func (c *CAClientImpl) GetCAInfo() (*api.GetCAInfoResponse, error) {
+	if c.adapter == nil {
+		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
+	}
+	return c.adapter.GetCAInfo()
+}
+
 func (c *CAClientImpl) getRegistrar(enrollID string, enrollSecret string) (msp.SigningIdentity, error) {
 
 	if enrollID == "" {
@@ -239,7 +463,7 @@ func (c *CAClientImpl) getRegistrar(enrollID string, enrollSecret string) (msp.S
 		}
 
 		// Attempt to enroll the registrar
-		err = c.Enroll(enrollID, enrollSecret)
+		err = c.Enroll(&api.EnrollmentRequest{Name: enrollID, Secret: enrollSecret})
 		if err != nil {
 			return nil, err
 		}
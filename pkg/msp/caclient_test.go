@@ -7,6 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -39,13 +42,13 @@ func TestEnrollAndReenroll(t *testing.T) {
 	orgMSPID := mspIDByOrgName(t, f.endpointConfig, org1)
 
 	// Empty enrollment ID
-	err := f.caClient.Enroll("", "user1")
+	err := f.caClient.Enroll(&api.EnrollmentRequest{Name: "", Secret: "user1"})
 	if err == nil {
 		t.Fatalf("Enroll didn't return error")
 	}
 
 	// Empty enrollment secret
-	err = f.caClient.Enroll("enrolledUsername", "")
+	err = f.caClient.Enroll(&api.EnrollmentRequest{Name: "enrolledUsername", Secret: ""})
 	if err == nil {
 		t.Fatalf("Enroll didn't return error")
 	}
@@ -56,7 +59,7 @@ func TestEnrollAndReenroll(t *testing.T) {
 	if err != msp.ErrUserNotFound {
 		t.Fatalf("Expected to not find user in user store")
 	}
-	err = f.caClient.Enroll(enrollUsername, "enrollmentSecret")
+	err = f.caClient.Enroll(&api.EnrollmentRequest{Name: enrollUsername, Secret: "enrollmentSecret"})
 	if err != nil {
 		t.Fatalf("identityManager Enroll return error %v", err)
 	}
@@ -65,6 +68,19 @@ func TestEnrollAndReenroll(t *testing.T) {
 		t.Fatalf("Expected to load user from user store")
 	}
 
+	// Enrollment with a profile doesn't replace the enrollment certificate
+	err = f.caClient.Enroll(&api.EnrollmentRequest{Name: enrollUsername, Secret: "enrollmentSecret", Profile: "tls"})
+	if err != nil {
+		t.Fatalf("identityManager Enroll with profile return error %v", err)
+	}
+	if _, err = f.userStore.Load(msp.IdentityIdentifier{MSPID: ProfileMSPID(orgMSPID, "tls"), ID: enrollUsername}); err != nil {
+		t.Fatalf("Expected to load TLS credentials from user store: %s", err)
+	}
+	userData, err := f.userStore.Load(msp.IdentityIdentifier{MSPID: orgMSPID, ID: enrollUsername})
+	if err != nil || userData.EnrollmentCertificate == nil {
+		t.Fatalf("Expected to load user from user store")
+	}
+
 	// Reenroll with empty user
 	err = f.caClient.Reenroll("")
 	if err == nil {
@@ -134,7 +150,7 @@ func TestWrongURL(t *testing.T) {
 	if err != nil {
 		t.Fatalf("NewidentityManagerClient return error: %v", err)
 	}
-	err = f.caClient.Enroll("enrollmentID", "enrollmentSecret")
+	err = f.caClient.Enroll(&api.EnrollmentRequest{Name: "enrollmentID", Secret: "enrollmentSecret"})
 	if err == nil {
 		t.Fatalf("Enroll didn't return error")
 	}
@@ -205,6 +221,19 @@ func TestRegister(t *testing.T) {
 	if secret != "mockSecretValue" {
 		t.Fatalf("identityManager Register return wrong value %s", secret)
 	}
+
+	// Register a peer with an ecert attribute, unlimited enrollments and a new affiliation
+	attributes = []api.Attribute{{Name: "role", Value: "endorser", ECert: true}}
+	_, err = f.caClient.Register(&api.RegistrationRequest{Name: "peer1", Type: "peer", MaxEnrollments: -1, Affiliation: "org1.department2", CreateAffiliation: true, Attributes: attributes})
+	if err != nil {
+		t.Fatalf("identityManager Register with affiliation creation return error %v", err)
+	}
+
+	// Register with invalid max enrollments
+	_, err = f.caClient.Register(&api.RegistrationRequest{Name: "test", MaxEnrollments: -2})
+	if err == nil {
+		t.Fatalf("Expected error with invalid max enrollments")
+	}
 }
 
 // TestEmbeddedRegistar tests registration with embedded registrar identity
@@ -285,9 +314,191 @@ func TestRevoke(t *testing.T) {
 	user.SetEnrollmentCertificate(readCert(t))
 	user.SetPrivateKey(mockKey)
 
-	_, err = f.caClient.Revoke(&api.RevocationRequest{})
-	if err == nil {
-		t.Fatalf("Expected decoding error with test cert")
+	// The mock CA revokes its enrollment certificate
+	resp, err := f.caClient.Revoke(&api.RevocationRequest{Name: "test"})
+	if err != nil {
+		t.Fatalf("Revoke return error %v", err)
+	}
+	if len(resp.RevokedCerts) != 1 {
+		t.Fatalf("Expected one revoked cert, got: %v", resp.RevokedCerts)
+	}
+}
+
+// TestRevokeCleanup tests the marking and the purging of the revoked users
+func TestRevokeCleanup(t *testing.T) {
+
+	f := textFixture{}
+	f.setup(nil)
+	defer f.close()
+
+	orgMSPID := mspIDByOrgName(t, f.endpointConfig, org1)
+	caClient := f.caClient.(*CAClientImpl)
+	iManager, ok := f.identityManagerProvider.IdentityManager("org1")
+	if !ok {
+		t.Fatalf("failed to get identity manager")
+	}
+
+	// The mock CA enrolls every user with the certificate it revokes
+	revokedUsername := createRandomName()
+	err := caClient.Enroll(&api.EnrollmentRequest{Name: revokedUsername, Secret: "enrollmentSecret"})
+	if err != nil {
+		t.Fatalf("Enroll return error %v", err)
+	}
+
+	caClient.revokedUsers = MarkRevokedUsers
+	if _, err = caClient.Revoke(&api.RevocationRequest{Name: revokedUsername}); err != nil {
+		t.Fatalf("Revoke return error %v", err)
+	}
+	if _, err = iManager.GetSigningIdentity(revokedUsername); err != msp.ErrUserRevoked {
+		t.Fatalf("Expected ErrUserRevoked, got: %v", err)
+	}
+
+	// Enrolling again clears the mark
+	err = caClient.Enroll(&api.EnrollmentRequest{Name: revokedUsername, Secret: "enrollmentSecret"})
+	if err != nil {
+		t.Fatalf("Enroll return error %v", err)
+	}
+	user, err := iManager.GetSigningIdentity(revokedUsername)
+	if err != nil {
+		t.Fatalf("GetSigningIdentity return error %v", err)
+	}
+
+	// The registrar, which was revoked too, must enroll again
+	err = caClient.Enroll(&api.EnrollmentRequest{Name: "admin", Secret: "adminpw"})
+	if err != nil {
+		t.Fatalf("Enroll return error %v", err)
+	}
+
+	caClient.revokedUsers = PurgeRevokedUsers
+	if _, err = caClient.Revoke(&api.RevocationRequest{Name: revokedUsername}); err != nil {
+		t.Fatalf("Revoke return error %v", err)
+	}
+	if _, err = f.userStore.Load(msp.IdentityIdentifier{MSPID: orgMSPID, ID: revokedUsername}); err != msp.ErrUserNotFound {
+		t.Fatalf("Expected revoked user to be purged from user store, got: %v", err)
+	}
+	keyFile := filepath.Join(f.cryptSuiteConfig.KeyStorePath(), hex.EncodeToString(user.PrivateKey().SKI())+"_sk")
+	if _, err = os.Stat(keyFile); !os.IsNotExist(err) {
+		t.Fatalf("Expected private key of revoked user to be purged from key store, got: %v", err)
+	}
+}
+
+// TestIdentityEvents tests the notification of enrollments, re-enrollments and revocations
+func TestIdentityEvents(t *testing.T) {
+
+	f := textFixture{}
+	f.setup(nil)
+	defer f.close()
+
+	orgMSPID := mspIDByOrgName(t, f.endpointConfig, org1)
+
+	var events []*api.IdentityEvent
+	WithIdentityEventHandler(func(event *api.IdentityEvent) {
+		panic("handler failure")
+	})(f.caClient.(*CAClientImpl))
+	WithIdentityEventHandler(func(event *api.IdentityEvent) {
+		events = append(events, event)
+	})(f.caClient.(*CAClientImpl))
+
+	enrollUsername := createRandomName()
+	if err := f.caClient.Enroll(&api.EnrollmentRequest{Name: enrollUsername, Secret: "enrollmentSecret", Profile: "tls"}); err != nil {
+		t.Fatalf("Enroll return error %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("Expected one event, got %d", len(events))
+	}
+	event := events[0]
+	if event.Type != api.EnrollEvent || event.ID != enrollUsername || event.MSPID != ProfileMSPID(orgMSPID, "tls") || event.Profile != "tls" {
+		t.Fatalf("Unexpected enroll event: %+v", event)
+	}
+	if len(event.Certificate) == 0 || event.Serial == "" || event.NotAfter.IsZero() {
+		t.Fatalf("Expected enrollment certificate metadata in event: %+v", event)
+	}
+
+	if err := f.caClient.Enroll(&api.EnrollmentRequest{Name: enrollUsername, Secret: "enrollmentSecret"}); err != nil {
+		t.Fatalf("Enroll return error %v", err)
+	}
+	if err := f.caClient.Reenroll(enrollUsername); err != nil {
+		t.Fatalf("Reenroll return error %v", err)
+	}
+	event = events[len(events)-1]
+	if event.Type != api.ReenrollEvent || event.ID != enrollUsername || event.MSPID != orgMSPID {
+		t.Fatalf("Unexpected reenroll event: %+v", event)
+	}
+
+	// The revocation enrolls the registrar first
+	events = nil
+	if _, err := f.caClient.Revoke(&api.RevocationRequest{Name: enrollUsername, Reason: "keycompromise"}); err != nil {
+		t.Fatalf("Revoke return error %v", err)
+	}
+	event = events[len(events)-1]
+	if event.Type != api.RevokeEvent || event.ID != enrollUsername || event.Reason != "keycompromise" || len(event.RevokedCerts) != 1 {
+		t.Fatalf("Unexpected revoke event: %+v", event)
+	}
+}
+
+// TestGetCAInfo tests CA info retrieval and CA chain pinning
+func TestGetCAInfo(t *testing.T) {
+
+	f := textFixture{}
+	f.setup(nil)
+	defer f.close()
+
+	caInfo, err := f.caClient.GetCAInfo()
+	if err != nil {
+		t.Fatalf("GetCAInfo return error %v", err)
+	}
+	if caInfo.CAName != "MockCAName" || string(caInfo.CAChain) != "MockCAChain" || caInfo.Version == "" {
+		t.Fatalf("Unexpected CA info: %+v", caInfo)
+	}
+
+	// The pinned CA chain is returned by the CA
+	WithCAChain(caInfo.CAChain)(f.caClient.(*CAClientImpl))
+	if _, err = f.caClient.GetCAInfo(); err != nil {
+		t.Fatalf("GetCAInfo with pinned CA chain return error %v", err)
+	}
+	if err = f.caClient.Enroll(&api.EnrollmentRequest{Name: createRandomName(), Secret: "enrollmentSecret"}); err != nil {
+		t.Fatalf("Enroll with pinned CA chain return error %v", err)
+	}
+
+	// Another CA chain is returned by the CA
+	WithCAChain([]byte("OtherCAChain"))(f.caClient.(*CAClientImpl))
+	if _, err = f.caClient.GetCAInfo(); errors.Cause(err) != api.ErrCAChainMismatch {
+		t.Fatalf("Expected CA chain mismatch, got %v", err)
+	}
+	if err = f.caClient.Enroll(&api.EnrollmentRequest{Name: createRandomName(), Secret: "enrollmentSecret"}); errors.Cause(err) != api.ErrCAChainMismatch {
+		t.Fatalf("Expected CA chain mismatch on enroll, got %v", err)
+	}
+}
+
+// TestGetIdemixIssuerKeys tests the retrieval and the caching of the idemix issuer keys
+func TestGetIdemixIssuerKeys(t *testing.T) {
+
+	f := textFixture{}
+	f.setup(nil)
+	defer f.close()
+
+	iManager, ok := f.identityManagerProvider.IdentityManager("org1")
+	if !ok {
+		t.Fatalf("failed to get identity manager")
+	}
+
+	keys, err := f.caClient.GetIdemixIssuerKeys()
+	if err != nil {
+		t.Fatalf("GetIdemixIssuerKeys return error %v", err)
+	}
+	if string(keys.IssuerPublicKey) != "MockIssuerPublicKey" || string(keys.RevocationPublicKey) != "MockRevocationPublicKey" {
+		t.Fatalf("Unexpected idemix issuer keys: %+v", keys)
+	}
+
+	cachedKeys, ok := iManager.(*IdentityManager).IdemixIssuerKeys()
+	if !ok || cachedKeys != keys {
+		t.Fatalf("Expected idemix issuer keys to be cached for the identity manager")
+	}
+
+	// The cached keys are returned without a request to the CA
+	WithCAChain([]byte("OtherCAChain"))(f.caClient.(*CAClientImpl))
+	if keys, err = f.caClient.GetIdemixIssuerKeys(); err != nil || keys != cachedKeys {
+		t.Fatalf("Expected cached idemix issuer keys, got %+v, %v", keys, err)
 	}
 }
 
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"strings"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/keyvaluestore"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
@@ -21,8 +23,33 @@ type CertFileUserStore struct {
 	store core.KVStore
 }
 
+const (
+	certFileSuffix    = "-cert.pem"
+	revokedFileSuffix = "-revoked.pem"
+)
+
 func storeKeyFromUserIdentifier(key msp.IdentityIdentifier) string {
-	return key.ID + "@" + key.MSPID + "-cert.pem"
+	return key.ID + "@" + key.MSPID + certFileSuffix
+}
+
+// revokedStoreKeyFromUserIdentifier returns the key of the revocation mark of a user,
+// which holds the revoked enrollment cert
+func revokedStoreKeyFromUserIdentifier(key msp.IdentityIdentifier) string {
+	return key.ID + "@" + key.MSPID + revokedFileSuffix
+}
+
+// userIdentifierFromStoreKey parses the user identifier from a store key. Returns false
+// if the key isn't a user's key.
+func userIdentifierFromStoreKey(key string) (msp.IdentityIdentifier, bool) {
+	if !strings.HasSuffix(key, certFileSuffix) || strings.Contains(key, "/") {
+		return msp.IdentityIdentifier{}, false
+	}
+	key = strings.TrimSuffix(key, certFileSuffix)
+	i := strings.LastIndex(key, "@")
+	if i <= 0 || i == len(key)-1 {
+		return msp.IdentityIdentifier{}, false
+	}
+	return msp.IdentityIdentifier{ID: key[:i], MSPID: key[i+1:]}, true
 }
 
 // NewCertFileUserStore1 creates a new instance of CertFileUserStore
@@ -67,13 +94,66 @@ func (s *CertFileUserStore) Load(key msp.IdentityIdentifier) (*msp.UserData, err
 	return userData, nil
 }
 
-// Store stores a User into store
+// Store stores a User into store. The revocation mark of the user, if any, is cleared.
 func (s *CertFileUserStore) Store(user *msp.UserData) error {
-	key := storeKeyFromUserIdentifier(msp.IdentityIdentifier{MSPID: user.MSPID, ID: user.ID})
-	return s.store.Store(key, user.EnrollmentCertificate)
+	id := msp.IdentityIdentifier{MSPID: user.MSPID, ID: user.ID}
+	if err := s.store.Store(storeKeyFromUserIdentifier(id), user.EnrollmentCertificate); err != nil {
+		return err
+	}
+	return s.store.Delete(revokedStoreKeyFromUserIdentifier(id))
 }
 
-// Delete deletes a User from store
+// Delete deletes a User, and its revocation mark, from store
 func (s *CertFileUserStore) Delete(key msp.IdentityIdentifier) error {
-	return s.store.Delete(storeKeyFromUserIdentifier(key))
+	if err := s.store.Delete(storeKeyFromUserIdentifier(key)); err != nil {
+		return err
+	}
+	return s.store.Delete(revokedStoreKeyFromUserIdentifier(key))
+}
+
+// MarkRevoked marks the stored enrollment cert of a User as revoked
+func (s *CertFileUserStore) MarkRevoked(key msp.IdentityIdentifier) error {
+	userData, err := s.Load(key)
+	if err != nil {
+		return err
+	}
+	return s.store.Store(revokedStoreKeyFromUserIdentifier(key), userData.EnrollmentCertificate)
+}
+
+// IsRevoked returns true if the stored enrollment cert of a User is marked as revoked
+func (s *CertFileUserStore) IsRevoked(key msp.IdentityIdentifier) (bool, error) {
+	_, err := s.store.Load(revokedStoreKeyFromUserIdentifier(key))
+	if err != nil {
+		if err == core.ErrKeyValueNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// List returns the identifiers of the users of the given MSP (or of all users if the MSP ID is empty).
+// The underlying key-value store must be able to list its keys.
+func (s *CertFileUserStore) List(mspID string) ([]msp.IdentityIdentifier, error) {
+	lister, ok := s.store.(keyvaluestore.KeyLister)
+	if !ok {
+		return nil, errors.New("user store doesn't support listing of users")
+	}
+	keys, err := lister.Keys()
+	if err != nil {
+		return nil, errors.WithMessage(err, "listing users failed")
+	}
+
+	var ids []msp.IdentityIdentifier
+	for _, key := range keys {
+		id, ok := userIdentifierFromStoreKey(key)
+		if !ok {
+			continue
+		}
+		if mspID == "" || id.MSPID == mspID {
+			ids = append(ids, id)
+		}
+	}
+	sortIdentifiers(ids)
+	return ids, nil
 }
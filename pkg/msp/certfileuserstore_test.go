@@ -12,6 +12,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"reflect"
 	"testing"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
@@ -104,6 +105,143 @@ func TestStore(t *testing.T) {
 	checkNonExistingKey(store, t)
 }
 
+func TestList(t *testing.T) {
+
+	cleanupTestPath(t, storePathRoot)
+	defer cleanupTestPath(t, storePathRoot)
+
+	store, err := NewCertFileUserStore(storePath)
+	if err != nil {
+		t.Fatalf("NewFileKeyValueStore failed [%s]", err)
+	}
+
+	ids, err := store.List("")
+	if err != nil {
+		t.Fatalf("List failed on empty store [%s]", err)
+	}
+	if len(ids) != 0 {
+		t.Fatalf("expected no users but got %v", ids)
+	}
+
+	user1 := &msp.UserData{MSPID: "Org1", ID: "user1@example.com", EnrollmentCertificate: []byte(testCert1)}
+	user2 := &msp.UserData{MSPID: "Org2", ID: "user2", EnrollmentCertificate: []byte(testCert2)}
+	user3 := &msp.UserData{MSPID: "Org1", ID: "admin", EnrollmentCertificate: []byte(testCert2)}
+	createStore(store, user1, t, user2)
+	createStore(store, user3, t, user3)
+
+	// Files other than user certs are ignored
+	if err = ioutil.WriteFile(path.Join(storePath, "other.txt"), []byte("other"), 0600); err != nil {
+		t.Fatalf("WriteFile failed [%s]", err)
+	}
+
+	ids, err = store.List("")
+	if err != nil {
+		t.Fatalf("List failed [%s]", err)
+	}
+	expected := []msp.IdentityIdentifier{userIdentifier(user3), userIdentifier(user1), userIdentifier(user2)}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Fatalf("expected users %v but got %v", expected, ids)
+	}
+
+	ids, err = store.List("Org2")
+	if err != nil {
+		t.Fatalf("List failed [%s]", err)
+	}
+	if !reflect.DeepEqual(ids, []msp.IdentityIdentifier{userIdentifier(user2)}) {
+		t.Fatalf("expected user %s but got %v", user2.ID, ids)
+	}
+
+	var users []string
+	err = ForEachUser(store, "Org1", func(user *msp.UserData) error {
+		if user.EnrollmentCertificate == nil {
+			return errors.New("missing enrollment certificate")
+		}
+		users = append(users, user.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachUser failed [%s]", err)
+	}
+	if !reflect.DeepEqual(users, []string{"admin", "user1@example.com"}) {
+		t.Fatalf("unexpected users %v", users)
+	}
+
+	err = ForEachUser(store, "", func(user *msp.UserData) error {
+		return errors.New("stop")
+	})
+	if err == nil || err.Error() != "stop" {
+		t.Fatalf("expected ForEachUser to return error of function but got %v", err)
+	}
+
+	// Only the methods of msp.UserStore are exposed
+	unlistable := struct{ msp.UserStore }{store}
+	err = ForEachUser(unlistable, "", func(user *msp.UserData) error {
+		return nil
+	})
+	if err == nil || err.Error() != "user store doesn't support listing of users" {
+		t.Fatalf("expected ForEachUser to fail for a user store which can't list its users but got %v", err)
+	}
+}
+
+func TestMarkRevoked(t *testing.T) {
+
+	cleanupTestPath(t, storePathRoot)
+	defer cleanupTestPath(t, storePathRoot)
+
+	store, err := NewCertFileUserStore(storePath)
+	if err != nil {
+		t.Fatalf("NewFileKeyValueStore failed [%s]", err)
+	}
+
+	user1 := &msp.UserData{MSPID: "Org1", ID: "user1", EnrollmentCertificate: []byte(testCert1)}
+	user2 := &msp.UserData{MSPID: "Org1", ID: "user2", EnrollmentCertificate: []byte(testCert2)}
+	createStore(store, user1, t, user2)
+
+	if err = store.MarkRevoked(msp.IdentityIdentifier{MSPID: "Org1", ID: "userx"}); err != msp.ErrUserNotFound {
+		t.Fatalf("expected ErrUserNotFound when marking non-existing user but got %v", err)
+	}
+	if err = store.MarkRevoked(userIdentifier(user1)); err != nil {
+		t.Fatalf("MarkRevoked %s failed [%s]", user1.ID, err)
+	}
+	checkRevoked(store, user1, true, t)
+	checkRevoked(store, user2, false, t)
+
+	// Revocation marks aren't listed as users
+	ids, err := store.List("Org1")
+	if err != nil {
+		t.Fatalf("List failed [%s]", err)
+	}
+	if !reflect.DeepEqual(ids, []msp.IdentityIdentifier{userIdentifier(user1), userIdentifier(user2)}) {
+		t.Fatalf("unexpected users %v", ids)
+	}
+
+	// Storing a new enrollment certificate clears the mark
+	user1.EnrollmentCertificate = []byte(testCert2)
+	if err = store.Store(user1); err != nil {
+		t.Fatalf("Store %s failed [%s]", user1.ID, err)
+	}
+	checkRevoked(store, user1, false, t)
+
+	// Deleting a user deletes its mark
+	if err = store.MarkRevoked(userIdentifier(user2)); err != nil {
+		t.Fatalf("MarkRevoked %s failed [%s]", user2.ID, err)
+	}
+	if err = store.Delete(userIdentifier(user2)); err != nil {
+		t.Fatalf("Delete %s failed [%s]", user2.ID, err)
+	}
+	checkRevoked(store, user2, false, t)
+}
+
+func checkRevoked(store *CertFileUserStore, user *msp.UserData, expected bool, t *testing.T) {
+	revoked, err := store.IsRevoked(userIdentifier(user))
+	if err != nil {
+		t.Fatalf("IsRevoked %s failed [%s]", user.ID, err)
+	}
+	if revoked != expected {
+		t.Fatalf("expected revoked=%t for %s but got %t", expected, user.ID, revoked)
+	}
+}
+
 func createStore(store *CertFileUserStore, user1 *msp.UserData, t *testing.T, user2 *msp.UserData) {
 	if err := store.Store(user1); err != nil {
 		t.Fatalf("Store %s failed [%s]", user1.ID, err)
@@ -7,12 +7,24 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
 	"github.com/pkg/errors"
 
 	caapi "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/api"
 	calib "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/lib"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
 	"github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
 )
@@ -22,6 +34,10 @@ type fabricCAAdapter struct {
 	config      msp.IdentityConfig
 	cryptoSuite core.CryptoSuite
 	caClient    *calib.Client
+	// caChain is the pinned CA chain, if any
+	caChain []byte
+	// operations configures the requests of each operation of the CA
+	operations msp.CAOperationsConfig
 }
 
 func newFabricCAAdapter(orgName string, cryptoSuite core.CryptoSuite, config msp.IdentityConfig) (*fabricCAAdapter, error) {
@@ -30,30 +46,46 @@ func newFabricCAAdapter(orgName string, cryptoSuite core.CryptoSuite, config msp
 	if err != nil {
 		return nil, err
 	}
+	conf, err := config.CAConfig(orgName)
+	if err != nil {
+		return nil, err
+	}
 
 	a := &fabricCAAdapter{
 		config:      config,
 		cryptoSuite: cryptoSuite,
 		caClient:    caClient,
+		operations:  conf.Operations,
 	}
 	return a, nil
 }
 
 // Enroll handles enrollment.
-func (c *fabricCAAdapter) Enroll(enrollmentID string, enrollmentSecret string) ([]byte, error) {
+func (c *fabricCAAdapter) Enroll(request *api.EnrollmentRequest) ([]byte, error) {
 
-	logger.Debugf("Enrolling user [%s]", enrollmentID)
+	logger.Debugf("Enrolling user [%s] (profile [%s])", request.Name, request.Profile)
 
 	// TODO add attributes
 	careq := &caapi.EnrollmentRequest{
-		CAName: c.caClient.Config.CAName,
-		Name:   enrollmentID,
-		Secret: enrollmentSecret,
+		CAName:  c.caClient.Config.CAName,
+		Name:    request.Name,
+		Secret:  request.Secret,
+		Profile: request.Profile,
+	}
+	if request.CAName != "" {
+		careq.CAName = request.CAName
 	}
-	caresp, err := c.caClient.Enroll(careq)
+	var caresp *calib.EnrollmentResponse
+	err := c.invoke(c.operations.Enroll, func() (err error) {
+		caresp, err = c.caClient.Enroll(careq)
+		return err
+	})
 	if err != nil {
 		return nil, errors.WithMessage(err, "enroll failed")
 	}
+	if err := c.verifyCAChain(caresp.ServerInfo.CAChain); err != nil {
+		return nil, err
+	}
 	return caresp.Identity.GetECert().Cert(), nil
 }
 
@@ -70,10 +102,17 @@ func (c *fabricCAAdapter) Reenroll(key core.Key, cert []byte) ([]byte, error) {
 		return nil, errors.WithMessage(err, "failed to create CA signing identity")
 	}
 
-	caresp, err := caidentity.Reenroll(careq)
+	var caresp *calib.EnrollmentResponse
+	err = c.invoke(c.operations.Reenroll, func() (err error) {
+		caresp, err = caidentity.Reenroll(careq)
+		return err
+	})
 	if err != nil {
 		return nil, errors.WithMessage(err, "reenroll failed")
 	}
+	if err := c.verifyCAChain(caresp.ServerInfo.CAChain); err != nil {
+		return nil, err
+	}
 
 	return caresp.Identity.GetECert().Cert(), nil
 }
@@ -103,7 +142,17 @@ func (c *fabricCAAdapter) Register(key core.Key, cert []byte, request *api.Regis
 		return "", errors.Wrap(err, "failed to create CA signing identity")
 	}
 
-	response, err := registrar.Register(&req)
+	if request.CreateAffiliation && request.Affiliation != "" {
+		if err := c.addAffiliation(registrar, request.CAName, request.Affiliation); err != nil {
+			return "", err
+		}
+	}
+
+	var response *caapi.RegistrationResponse
+	err = c.invoke(c.operations.Register, func() (err error) {
+		response, err = registrar.Register(&req)
+		return err
+	})
 	if err != nil {
 		return "", errors.Wrap(err, "failed to register user")
 	}
@@ -111,6 +160,20 @@ func (c *fabricCAAdapter) Register(key core.Key, cert []byte, request *api.Regis
 	return response.Secret, nil
 }
 
+// addAffiliation adds the affiliation and its parent affiliations, unless the affiliation exists
+func (c *fabricCAAdapter) addAffiliation(registrar *calib.Identity, caName, affiliation string) error {
+	_, err := registrar.AddAffiliation(&caapi.AddAffiliationRequest{Name: affiliation, Force: true, CAName: caName})
+	if err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			logger.Debugf("Affiliation [%s] already exists", affiliation)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to add affiliation [%s]", affiliation)
+	}
+	logger.Debugf("Added affiliation [%s]", affiliation)
+	return nil
+}
+
 // Revoke handles user revocation.
 // key: registrar private key
 // cert: registrar enrollment certificate
@@ -130,7 +193,11 @@ func (c *fabricCAAdapter) Revoke(key core.Key, cert []byte, request *api.Revocat
 		return nil, errors.Wrap(err, "failed to create CA signing identity")
 	}
 
-	resp, err := registrar.Revoke(&req)
+	var resp *caapi.RevocationResponse
+	err = c.invoke(c.operations.Revoke, func() (err error) {
+		resp, err = registrar.Revoke(&req)
+		return err
+	})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to revoke")
 	}
@@ -150,6 +217,101 @@ func (c *fabricCAAdapter) Revoke(key core.Key, cert []byte, request *api.Revocat
 	}, nil
 }
 
+// GetCAInfo returns the name, the chain and the version of the CA
+func (c *fabricCAAdapter) GetCAInfo() (*api.GetCAInfoResponse, error) {
+	resp, err := c.caClient.GetCAInfo(&caapi.GetCAInfoRequest{CAName: c.caClient.Config.CAName})
+	if err != nil {
+		return nil, errors.WithMessage(err, "get CA info failed")
+	}
+	if err := c.verifyCAChain(resp.CAChain); err != nil {
+		return nil, err
+	}
+	return &api.GetCAInfoResponse{
+		CAName:  resp.CAName,
+		CAChain: resp.CAChain,
+		Version: resp.Version,
+	}, nil
+}
+
+// GetIdemixIssuerKeys returns the public keys of the idemix issuer of the CA
+func (c *fabricCAAdapter) GetIdemixIssuerKeys() (*api.IdemixIssuerKeys, error) {
+	resp, err := c.caClient.GetCAInfo(&caapi.GetCAInfoRequest{CAName: c.caClient.Config.CAName})
+	if err != nil {
+		return nil, errors.WithMessage(err, "get CA info failed")
+	}
+	if err := c.verifyCAChain(resp.CAChain); err != nil {
+		return nil, err
+	}
+	if len(resp.IssuerPublicKey) == 0 {
+		return nil, api.ErrIdemixNotSupported
+	}
+	return &api.IdemixIssuerKeys{
+		IssuerPublicKey:     resp.IssuerPublicKey,
+		RevocationPublicKey: resp.IssuerRevocationPublicKey,
+	}, nil
+}
+
+// verifyCAChain checks that the CA chain returned by the CA is the pinned CA chain, so that
+// certificates issued by another CA (e.g. of a hijacked CA endpoint) are rejected
+func (c *fabricCAAdapter) verifyCAChain(caChain []byte) error {
+	if len(c.caChain) == 0 || bytes.Equal(c.caChain, caChain) {
+		return nil
+	}
+	logger.Warnf("CA chain returned by CA [%s] doesn't match the pinned CA chain", c.caClient.Config.URL)
+	return api.ErrCAChainMismatch
+}
+
+// invoke performs the request of an operation of the CA, which is retried according to the
+// retry policy of the operation
+func (c *fabricCAAdapter) invoke(operation msp.CAOperationConfig, request func() error) error {
+	if operation.RetryPolicy == "" {
+		return request()
+	}
+	opts, err := retry.Policy(operation.RetryPolicy)
+	if err != nil {
+		return err
+	}
+	_, err = retry.NewInvoker(retry.New(opts), retry.WithAttemptTimeout(operation.Timeout)).Invoke(
+		func() (interface{}, error) {
+			return nil, caStatus(request())
+		})
+	return err
+}
+
+// caStatus converts the transport errors and the error responses of the CA into statuses,
+// so that they may be retried
+func caStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch cause := errors.Cause(err).(type) {
+	case *calib.HTTPError:
+		return status.New(status.HTTPTransportStatus, int32(cause.StatusCode), cause.Message, nil)
+	case net.Error:
+		if cause.Timeout() {
+			return status.New(status.ClientStatus, status.Timeout.ToInt32(), err.Error(), nil)
+		}
+		return status.New(status.ClientStatus, status.ConnectionFailed.ToInt32(), err.Error(), nil)
+	}
+	return err
+}
+
+// cryptoSuiteKey returns the private key of the crypto suite with the given hex-encoded SKI
+func cryptoSuiteKey(ski string, cryptoSuite core.CryptoSuite) (core.Key, error) {
+	skiBytes, err := hex.DecodeString(ski)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid SKI [%s]", ski)
+	}
+	key, err := cryptoSuite.GetKey(skiBytes)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("key with SKI [%s] not found", ski))
+	}
+	if !key.Private() {
+		return nil, errors.Errorf("private key with SKI [%s] not found", ski)
+	}
+	return key, nil
+}
+
 func createFabricCAClient(org string, cryptoSuite core.CryptoSuite, config msp.IdentityConfig) (*calib.Client, error) {
 
 	// Create new Fabric-ca client without configs
@@ -187,12 +349,49 @@ func createFabricCAClient(org string, cryptoSuite core.CryptoSuite, config msp.I
 		return nil, err
 	}
 
+	// TLS client key of the crypto suite
+	if conf.TLSClientKeySKI != "" {
+		c.Config.TLS.Client.Key, err = cryptoSuiteKey(conf.TLSClientKeySKI, cryptoSuite)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to get TLS client key of CA")
+		}
+		if len(c.Config.TLS.Client.KeyFile) > 0 {
+			logger.Warnf("TLS client key of CA [%s] is configured, but the key of the crypto suite is used", conf.URL)
+		}
+		c.Config.TLS.Client.KeyFile = nil
+	}
+
 	// get CAClient configs
-	_, err = config.Client()
+	client, err := config.Client()
 	if err != nil {
 		return nil, err
 	}
 
+	//proxy settings, CA specific settings take precedence over client settings
+	proxy := client.Proxy
+	if conf.Proxy.URL != "" {
+		proxy.URL = conf.Proxy.URL
+	}
+	if conf.Proxy.NoProxy != "" {
+		proxy.NoProxy = conf.Proxy.NoProxy
+	}
+	c.Config.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxy.ResolveProxy(req.URL.Host)
+	}
+
+	//certificate pinning
+	if len(conf.TLSCertPins) > 0 {
+		c.Config.VerifyPeerCertificate = comm.CertificatePinVerifier(conf.TLSCertPins)
+	}
+
+	//request timeouts
+	c.Config.Timeouts = map[string]time.Duration{
+		"enroll":   conf.Operations.Enroll.Timeout,
+		"reenroll": conf.Operations.Reenroll.Timeout,
+		"register": conf.Operations.Register.Timeout,
+		"revoke":   conf.Operations.Revoke.Timeout,
+	}
+
 	//TLS flag enabled/disabled
 	c.Config.TLS.Enabled = endpoint.IsTLSEnabled(conf.URL)
 	c.Config.MSPDir = config.CAKeyStorePath()
@@ -0,0 +1,153 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	calib "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/lib"
+	catls "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/lib/tls"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/sdkpatch/cryptosuitebridge"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+)
+
+func TestCAStatus(t *testing.T) {
+	s, ok := status.FromError(caStatus(errors.Wrap(&calib.HTTPError{StatusCode: http.StatusServiceUnavailable, Message: "server busy"}, "POST failure")))
+	if !ok || s.Group != status.HTTPTransportStatus || s.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Unexpected status of HTTP error: %v", s)
+	}
+
+	s, ok = status.FromError(caStatus(errors.Wrap(&url.Error{Op: "Post", URL: "http://ca", Err: timeoutError{}}, "POST failure")))
+	if !ok || s.Group != status.ClientStatus || s.Code != status.Timeout.ToInt32() {
+		t.Fatalf("Unexpected status of timeout: %v", s)
+	}
+
+	if _, ok = status.FromError(caStatus(errors.New("invalid request"))); ok {
+		t.Fatalf("Expected other errors not to be converted")
+	}
+}
+
+func TestInvokeRetryPolicy(t *testing.T) {
+	retry.RegisterPolicy("testCA", retry.Opts{Attempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, BackoffFactor: 1, RetryableCodes: retry.CARetryableCodes})
+	adapter := &fabricCAAdapter{}
+
+	busy := func(attempts *int) func() error {
+		return func() error {
+			*attempts++
+			return &calib.HTTPError{StatusCode: http.StatusServiceUnavailable, Message: "server busy"}
+		}
+	}
+
+	// Not retried without a retry policy
+	attempts := 0
+	if err := adapter.invoke(msp.CAOperationConfig{}, busy(&attempts)); err == nil || attempts != 1 {
+		t.Fatalf("Expected single failed attempt, got %d attempts (%v)", attempts, err)
+	}
+
+	attempts = 0
+	if err := adapter.invoke(msp.CAOperationConfig{RetryPolicy: "testCA"}, busy(&attempts)); err == nil || attempts != 3 {
+		t.Fatalf("Expected 3 failed attempts, got %d attempts (%v)", attempts, err)
+	}
+
+	// Errors which aren't transient aren't retried
+	attempts = 0
+	err := adapter.invoke(msp.CAOperationConfig{RetryPolicy: "testCA"}, func() error {
+		attempts++
+		return &calib.HTTPError{StatusCode: http.StatusUnauthorized, Message: "authentication failure"}
+	})
+	if err == nil || attempts != 1 {
+		t.Fatalf("Expected single failed attempt, got %d attempts (%v)", attempts, err)
+	}
+
+	if err = adapter.invoke(msp.CAOperationConfig{RetryPolicy: "unknown"}, busy(&attempts)); err == nil {
+		t.Fatalf("Expected error for unknown retry policy")
+	}
+}
+
+func TestCryptoSuiteTLSClientKey(t *testing.T) {
+	f := textFixture{}
+	f.setup(nil)
+	defer f.close()
+
+	key, err := f.cryptoSuite.KeyGen(cryptosuite.GetECDSAP256KeyGenOpts(false))
+	if err != nil {
+		t.Fatalf("Failed to generate key: %s", err)
+	}
+	otherKey, err := f.cryptoSuite.KeyGen(cryptosuite.GetECDSAP256KeyGenOpts(true))
+	if err != nil {
+		t.Fatalf("Failed to generate key: %s", err)
+	}
+
+	if _, err = cryptoSuiteKey("not hex", f.cryptoSuite); err == nil {
+		t.Fatalf("Expected error for invalid SKI")
+	}
+	if _, err = cryptoSuiteKey(hex.EncodeToString([]byte("unknown")), f.cryptoSuite); err == nil {
+		t.Fatalf("Expected error for unknown SKI")
+	}
+	tlsKey, err := cryptoSuiteKey(hex.EncodeToString(key.SKI()), f.cryptoSuite)
+	if err != nil {
+		t.Fatalf("Failed to get TLS client key: %s", err)
+	}
+
+	cfg := &catls.ClientTLSConfig{
+		CertFiles: [][]byte{selfSignedCert(t, f.cryptoSuite, otherKey)},
+		Client:    catls.KeyCertFiles{CertFile: selfSignedCert(t, f.cryptoSuite, tlsKey), Key: tlsKey},
+	}
+	tlsConfig, err := catls.GetClientTLSConfig(cfg, f.cryptoSuite)
+	if err != nil {
+		t.Fatalf("Failed to get TLS config: %s", err)
+	}
+	if len(tlsConfig.Certificates) != 1 {
+		t.Fatalf("Expected TLS client certificate")
+	}
+
+	// The key has to match the certificate
+	cfg.Client.Key = otherKey
+	if _, err = catls.GetClientTLSConfig(cfg, f.cryptoSuite); err == nil {
+		t.Fatalf("Expected error for TLS client key which doesn't match the certificate")
+	}
+}
+
+func selfSignedCert(t *testing.T, cs core.CryptoSuite, key core.Key) []byte {
+	signer, err := cryptosuitebridge.NewCspSigner(cs, key)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "tls-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, signer.Public(), signer)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %s", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "deadline exceeded" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
@@ -39,3 +39,16 @@ func NewFileKeyStore(cryptoConfigMSPPath string) (core.KVStore, error) {
 	}
 	return keyvaluestore.New(opts)
 }
+
+// PrivKeyKeySerializer serializes *msp.PrivKeyKey keys for key stores which
+// are backed by a key-value store other than the file key store
+func PrivKeyKeySerializer(key interface{}) (string, error) {
+	pkk, ok := key.(*msp.PrivKeyKey)
+	if !ok {
+		return "", errors.New("converting key to PrivKeyKey failed")
+	}
+	if pkk == nil || pkk.MSPID == "" || pkk.ID == "" || pkk.SKI == nil {
+		return "", errors.New("invalid key")
+	}
+	return path.Join(pkk.MSPID, pkk.ID, hex.EncodeToString(pkk.SKI)+"_sk"), nil
+}
@@ -17,6 +17,7 @@ import (
 	fabricCaUtil "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/util"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/certexpiry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/pathvar"
 	"github.com/pkg/errors"
 )
@@ -49,10 +50,20 @@ func (mgr *IdentityManager) loadUserFromStore(username string) (*User, error) {
 		return nil, msp.ErrUserNotFound
 	}
 	var user *User
-	userData, err := mgr.userStore.Load(msp.IdentityIdentifier{MSPID: mgr.orgMSPID, ID: username})
+	id := msp.IdentityIdentifier{MSPID: mgr.orgMSPID, ID: username}
+	userData, err := mgr.userStore.Load(id)
 	if err != nil {
 		return nil, err
 	}
+	if revocationStore, ok := mgr.userStore.(msp.UserRevocationStore); ok {
+		revoked, err := revocationStore.IsRevoked(id)
+		if err != nil {
+			return nil, errors.WithMessage(err, "checking revocation of user failed")
+		}
+		if revoked {
+			return nil, msp.ErrUserRevoked
+		}
+	}
 	user, err = mgr.NewUser(userData)
 	if err != nil {
 		return nil, err
@@ -74,6 +85,9 @@ func (mgr *IdentityManager) GetUser(username string) (*User, error) { //nolint
 
 	u, err := mgr.loadUserFromStore(username)
 	if err != nil {
+		if err == msp.ErrUserRevoked {
+			return nil, err
+		}
 		if err != msp.ErrUserNotFound {
 			return nil, errors.WithMessage(err, "loading user from store failed")
 		}
@@ -118,6 +132,8 @@ func (mgr *IdentityManager) GetUser(username string) (*User, error) { //nolint
 			privateKey:            privateKey,
 		}
 	}
+
+	certexpiry.TrackerFor(mgr.config).CheckPEM(certexpiry.IdentitySource, username, u.enrollmentCertificate)
 	return u, nil
 }
 
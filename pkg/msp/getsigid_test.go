@@ -97,6 +97,25 @@ func TestGetSigningIdentity(t *testing.T) {
 
 	// "Manually" enroll User1
 	enrollUser1(cryptoSuite, t, mspID, testUsername, userStore, mgr)
+
+	// The user is listed and can be found by its certificate
+	users, err := mgr.ListUsers()
+	if err != nil {
+		t.Fatalf("ListUsers failed: %s", err)
+	}
+	if len(users) != 1 || users[0] != testUsername {
+		t.Fatalf("Expected user [%s] to be listed, got %v", testUsername, users)
+	}
+	id, err := mgr.GetSigningIdentityByCert([]byte(testCert))
+	if err != nil {
+		t.Fatalf("GetSigningIdentityByCert failed: %s", err)
+	}
+	if id.Identifier().ID != testUsername || id.PrivateKey() == nil {
+		t.Fatalf("Unexpected signing identity [%s]", id.Identifier().ID)
+	}
+	if _, err = mgr.GetSigningIdentityByCert([]byte("invalid cert")); err != msp.ErrUserNotFound {
+		t.Fatalf("expected ErrUserNotFound, got: %v", err)
+	}
 }
 
 func getConfigs(t *testing.T) (core.CryptoSuiteConfig, providersFab.EndpointConfig, msp.IdentityConfig, providersFab.OrganizationConfig) {
@@ -241,6 +260,25 @@ func checkSigningIdentityFromEmbeddedCryptoConfig(mgr *IdentityManager, t *testi
 	if err := checkSigningIdentity(mgr, "EmbeddedUserMixed2"); err != nil {
 		t.Fatalf("checkSigningIdentity failes: %s", err)
 	}
+
+	users, err := mgr.ListUsers()
+	if err != nil {
+		t.Fatalf("ListUsers failed: %s", err)
+	}
+	if len(users) < 4 {
+		t.Fatalf("Expected the embedded users to be listed, got %v", users)
+	}
+	id, err := mgr.GetSigningIdentity("EmbeddedUser")
+	if err != nil {
+		t.Fatalf("GetSigningIdentity failed: %s", err)
+	}
+	userData, err := mgr.GetUserDataByCert(id.EnrollmentCertificate())
+	if err != nil {
+		t.Fatalf("GetUserDataByCert failed: %s", err)
+	}
+	if !strings.EqualFold(userData.ID, "EmbeddedUser") {
+		t.Fatalf("Expected embedded user, got [%s]", userData.ID)
+	}
 }
 
 func createRandomName() string {
@@ -0,0 +1,62 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
+)
+
+// idemixIssuerKeysCache caches the idemix issuer keys of the CAs by MSP ID. The keys of an
+// issuer don't change, so they are only retrieved from the CA of an organization once.
+var idemixIssuerKeysCache = struct {
+	sync.RWMutex
+	keys map[string]*api.IdemixIssuerKeys
+}{keys: make(map[string]*api.IdemixIssuerKeys)}
+
+func cachedIdemixIssuerKeys(mspID string) (*api.IdemixIssuerKeys, bool) {
+	idemixIssuerKeysCache.RLock()
+	defer idemixIssuerKeysCache.RUnlock()
+
+	keys, ok := idemixIssuerKeysCache.keys[mspID]
+	return keys, ok
+}
+
+func cacheIdemixIssuerKeys(mspID string, keys *api.IdemixIssuerKeys) {
+	idemixIssuerKeysCache.Lock()
+	defer idemixIssuerKeysCache.Unlock()
+
+	idemixIssuerKeysCache.keys[mspID] = keys
+}
+
+// GetIdemixIssuerKeys returns the public keys of the idemix issuer of the CA, with which the
+// idemix credentials issued by the CA are verified. The keys are retrieved from the CA once and
+// then cached for the identity managers of the organization (see IdentityManager.IdemixIssuerKeys).
+// Returns api.ErrIdemixNotSupported if the CA doesn't issue idemix credentials.
+func (c *CAClientImpl) GetIdemixIssuerKeys() (*api.IdemixIssuerKeys, error) {
+	if c.adapter == nil {
+		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
+	}
+	if keys, ok := cachedIdemixIssuerKeys(c.orgMSPID); ok {
+		return keys, nil
+	}
+
+	keys, err := c.adapter.GetIdemixIssuerKeys()
+	if err != nil {
+		return nil, err
+	}
+	cacheIdemixIssuerKeys(c.orgMSPID, keys)
+	return keys, nil
+}
+
+// IdemixIssuerKeys returns the idemix issuer keys of the CA of the organization, if they were
+// retrieved with CAClient.GetIdemixIssuerKeys
+func (mgr *IdentityManager) IdemixIssuerKeys() (*api.IdemixIssuerKeys, bool) {
+	return cachedIdemixIssuerKeys(mgr.orgMSPID)
+}
@@ -7,26 +7,51 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"strings"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
 )
 
 // MemoryUserStore is in-memory implementation of UserStore
 type MemoryUserStore struct {
-	store map[string][]byte
+	store   map[string][]byte
+	revoked map[string]bool
 }
 
 // NewMemoryUserStore creates a new MemoryUserStore instance
 func NewMemoryUserStore() *MemoryUserStore {
 	store := make(map[string][]byte)
-	return &MemoryUserStore{store: store}
+	return &MemoryUserStore{store: store, revoked: make(map[string]bool)}
 }
 
-// Store stores a user into store
+// Store stores a user into store. The revocation mark of the user, if any, is cleared.
 func (s *MemoryUserStore) Store(user *msp.UserData) error {
 	s.store[user.ID+"@"+user.MSPID] = user.EnrollmentCertificate
+	delete(s.revoked, user.ID+"@"+user.MSPID)
+	return nil
+}
+
+// Delete deletes a user from store
+func (s *MemoryUserStore) Delete(id msp.IdentityIdentifier) error {
+	delete(s.store, id.ID+"@"+id.MSPID)
+	delete(s.revoked, id.ID+"@"+id.MSPID)
 	return nil
 }
 
+// MarkRevoked marks the stored enrollment certificate of a user as revoked
+func (s *MemoryUserStore) MarkRevoked(id msp.IdentityIdentifier) error {
+	if _, ok := s.store[id.ID+"@"+id.MSPID]; !ok {
+		return msp.ErrUserNotFound
+	}
+	s.revoked[id.ID+"@"+id.MSPID] = true
+	return nil
+}
+
+// IsRevoked returns true if the stored enrollment certificate of a user is marked as revoked
+func (s *MemoryUserStore) IsRevoked(id msp.IdentityIdentifier) (bool, error) {
+	return s.revoked[id.ID+"@"+id.MSPID], nil
+}
+
 // Load loads a user from store
 func (s *MemoryUserStore) Load(id msp.IdentityIdentifier) (*msp.UserData, error) {
 	cert, ok := s.store[id.ID+"@"+id.MSPID]
@@ -40,3 +65,20 @@ func (s *MemoryUserStore) Load(id msp.IdentityIdentifier) (*msp.UserData, error)
 	}
 	return &userData, nil
 }
+
+// List returns the identifiers of the users of the given MSP (or of all users if the MSP ID is empty)
+func (s *MemoryUserStore) List(mspID string) ([]msp.IdentityIdentifier, error) {
+	var ids []msp.IdentityIdentifier
+	for key := range s.store {
+		i := strings.LastIndex(key, "@")
+		if i < 0 {
+			continue
+		}
+		id := msp.IdentityIdentifier{ID: key[:i], MSPID: key[i+1:]}
+		if mspID == "" || id.MSPID == mspID {
+			ids = append(ids, id)
+		}
+	}
+	sortIdentifiers(ids)
+	return ids, nil
+}
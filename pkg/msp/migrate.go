@@ -0,0 +1,192 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"bytes"
+	"encoding/hex"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/cryptoutil"
+	"github.com/pkg/errors"
+)
+
+// MigrationOptions specifies the stores to migrate identities (and their private keys) between
+type MigrationOptions struct {
+	// Source and target user stores, mandatory. The source user store must implement msp.UserLister.
+	SourceUserStore msp.UserStore
+	TargetUserStore msp.UserStore
+	// Optional. Source and target key stores (with *msp.PrivKeyKey keys). If provided,
+	// the private key of each migrated user is migrated as well.
+	SourceKeyStore core.KVStore
+	TargetKeyStore core.KVStore
+	// CryptoSuite is used to compute the SKIs of the users' keys. Mandatory if key stores are provided.
+	CryptoSuite core.CryptoSuite
+	// Optional. If provided, only the users of the given MSP are migrated.
+	MSPID string
+	// Optional. If true, users and keys which already exist in the target stores with
+	// different content are overwritten, otherwise the migration fails.
+	Overwrite bool
+}
+
+// MigrationResult contains the users and keys which were migrated
+type MigrationResult struct {
+	// Users which were copied to the target store
+	Users []msp.IdentityIdentifier
+	// Keys which were copied to the target store
+	Keys []*msp.PrivKeyKey
+	// Users which already existed in the target store with the same content
+	Unchanged []msp.IdentityIdentifier
+	// Users whose private key wasn't found in the source key store
+	MissingKeys []msp.IdentityIdentifier
+}
+
+// Migrate copies the users (and their private keys) from the source stores to the target stores.
+// Each copied user and key is read back from the target store and verified against the source.
+// The source stores are not modified, so the SDK may keep using them until the migration has
+// completed, and the migration may be run again to copy users that were enrolled in the meantime.
+func Migrate(opts *MigrationOptions) (*MigrationResult, error) {
+	if err := validateMigrationOptions(opts); err != nil {
+		return nil, err
+	}
+
+	result := &MigrationResult{}
+	err := ForEachUser(opts.SourceUserStore, opts.MSPID, func(user *msp.UserData) error {
+		return migrateUser(opts, user, result)
+	})
+	if err != nil {
+		return result, errors.WithMessage(err, "migration failed")
+	}
+	return result, nil
+}
+
+func validateMigrationOptions(opts *MigrationOptions) error {
+	if opts == nil {
+		return errors.New("migration options are nil")
+	}
+	if opts.SourceUserStore == nil || opts.TargetUserStore == nil {
+		return errors.New("source and target user stores are required")
+	}
+	if _, ok := opts.SourceUserStore.(msp.UserLister); !ok {
+		return errors.New("source user store doesn't support listing of users")
+	}
+	if (opts.SourceKeyStore == nil) != (opts.TargetKeyStore == nil) {
+		return errors.New("both source and target key stores are required to migrate keys")
+	}
+	if opts.SourceKeyStore != nil && opts.CryptoSuite == nil {
+		return errors.New("crypto suite is required to migrate keys")
+	}
+	return nil
+}
+
+func migrateUser(opts *MigrationOptions, user *msp.UserData, result *MigrationResult) error {
+	id := msp.IdentityIdentifier{ID: user.ID, MSPID: user.MSPID}
+
+	if opts.SourceKeyStore != nil {
+		migrated, err := migrateKey(opts, user, result)
+		if err != nil {
+			return err
+		}
+		if !migrated {
+			result.MissingKeys = append(result.MissingKeys, id)
+		}
+	}
+
+	existing, err := opts.TargetUserStore.Load(id)
+	if err != nil && err != msp.ErrUserNotFound {
+		return errors.WithMessage(err, "loading user ["+userName(id)+"] from target store failed")
+	}
+	if existing != nil {
+		if bytes.Equal(existing.EnrollmentCertificate, user.EnrollmentCertificate) {
+			logger.Debugf("User [%s] already exists in target store", userName(id))
+			result.Unchanged = append(result.Unchanged, id)
+			return nil
+		}
+		if !opts.Overwrite {
+			return errors.Errorf("user [%s] exists in target store with a different enrollment certificate", userName(id))
+		}
+	}
+
+	if err := opts.TargetUserStore.Store(user); err != nil {
+		return errors.WithMessage(err, "storing user ["+userName(id)+"] in target store failed")
+	}
+
+	stored, err := opts.TargetUserStore.Load(id)
+	if err != nil {
+		return errors.WithMessage(err, "verification of user ["+userName(id)+"] failed")
+	}
+	if !bytes.Equal(stored.EnrollmentCertificate, user.EnrollmentCertificate) {
+		return errors.Errorf("verification of user [%s] failed: enrollment certificate mismatch", userName(id))
+	}
+
+	logger.Debugf("Migrated user [%s]", userName(id))
+	result.Users = append(result.Users, id)
+	return nil
+}
+
+// migrateKey copies the private key of the user. Returns false if the key wasn't found in the source store.
+func migrateKey(opts *MigrationOptions, user *msp.UserData, result *MigrationResult) (bool, error) {
+	pubKey, err := cryptoutil.GetPublicKeyFromCert(user.EnrollmentCertificate, opts.CryptoSuite)
+	if err != nil {
+		return false, errors.WithMessage(err, "fetching public key of user ["+user.ID+"@"+user.MSPID+"] failed")
+	}
+	key := &msp.PrivKeyKey{ID: user.ID, MSPID: user.MSPID, SKI: pubKey.SKI()}
+
+	keyBytes, err := loadKeyBytes(opts.SourceKeyStore, key)
+	if err != nil {
+		if err == core.ErrKeyValueNotFound {
+			logger.Debugf("Private key of user [%s@%s] not found in source key store", user.ID, user.MSPID)
+			return false, nil
+		}
+		return false, errors.WithMessage(err, "loading private key ["+hex.EncodeToString(key.SKI)+"] from source store failed")
+	}
+
+	existing, err := loadKeyBytes(opts.TargetKeyStore, key)
+	if err != nil && err != core.ErrKeyValueNotFound {
+		return false, errors.WithMessage(err, "loading private key ["+hex.EncodeToString(key.SKI)+"] from target store failed")
+	}
+	if existing != nil {
+		if bytes.Equal(existing, keyBytes) {
+			return true, nil
+		}
+		if !opts.Overwrite {
+			return false, errors.Errorf("private key [%s] exists in target store with different content", hex.EncodeToString(key.SKI))
+		}
+	}
+
+	if err := opts.TargetKeyStore.Store(key, keyBytes); err != nil {
+		return false, errors.WithMessage(err, "storing private key ["+hex.EncodeToString(key.SKI)+"] in target store failed")
+	}
+
+	stored, err := loadKeyBytes(opts.TargetKeyStore, key)
+	if err != nil {
+		return false, errors.WithMessage(err, "verification of private key ["+hex.EncodeToString(key.SKI)+"] failed")
+	}
+	if !bytes.Equal(stored, keyBytes) {
+		return false, errors.Errorf("verification of private key [%s] failed: content mismatch", hex.EncodeToString(key.SKI))
+	}
+
+	result.Keys = append(result.Keys, key)
+	return true, nil
+}
+
+func loadKeyBytes(store core.KVStore, key *msp.PrivKeyKey) ([]byte, error) {
+	value, err := store.Load(key)
+	if err != nil {
+		return nil, err
+	}
+	keyBytes, ok := value.([]byte)
+	if !ok {
+		return nil, errors.New("key from store is not []byte")
+	}
+	return keyBytes, nil
+}
+
+func userName(id msp.IdentityIdentifier) string {
+	return id.ID + "@" + id.MSPID
+}
@@ -0,0 +1,153 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/cryptoutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/keyvaluestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var migrationPath = "/tmp/testmigration"
+
+func TestMigrate(t *testing.T) {
+	cleanupTestPath(t, migrationPath)
+	defer cleanupTestPath(t, migrationPath)
+
+	cryptoSuite, err := sw.GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+
+	sourceUserStore, err := NewCertFileUserStore(path.Join(migrationPath, "users"))
+	require.NoError(t, err)
+	sourceKeyStore, err := NewFileKeyStore(path.Join(migrationPath, "keys"))
+	require.NoError(t, err)
+
+	user1, key1 := newMigrationUser(t, "user1", "Org1MSP")
+	user2, _ := newMigrationUser(t, "user2", "Org1MSP")
+	user3, key3 := newMigrationUser(t, "user3", "Org2MSP")
+	for _, user := range []*msp.UserData{user1, user2, user3} {
+		require.NoError(t, sourceUserStore.Store(user))
+	}
+	require.NoError(t, sourceKeyStore.Store(privKeyKey(t, cryptoSuite, user1), key1))
+	require.NoError(t, sourceKeyStore.Store(privKeyKey(t, cryptoSuite, user3), key3))
+
+	targetUserStore := NewMemoryUserStore()
+	targetKeyStore, err := keyvaluestore.NewLevelDB(&keyvaluestore.LevelDBKeyValueStoreOptions{
+		Path:          path.Join(migrationPath, "target"),
+		KeySerializer: PrivKeyKeySerializer,
+	})
+	require.NoError(t, err)
+	defer targetKeyStore.Close()
+
+	opts := &MigrationOptions{
+		SourceUserStore: sourceUserStore,
+		TargetUserStore: targetUserStore,
+		SourceKeyStore:  sourceKeyStore,
+		TargetKeyStore:  targetKeyStore,
+		CryptoSuite:     cryptoSuite,
+		MSPID:           "Org1MSP",
+	}
+
+	result, err := Migrate(opts)
+	require.NoError(t, err)
+	assert.Equal(t, []msp.IdentityIdentifier{identifier(user1), identifier(user2)}, result.Users)
+	assert.Len(t, result.Keys, 1)
+	assert.Equal(t, []msp.IdentityIdentifier{identifier(user2)}, result.MissingKeys)
+
+	migrated, err := targetUserStore.Load(identifier(user1))
+	require.NoError(t, err)
+	assert.Equal(t, user1.EnrollmentCertificate, migrated.EnrollmentCertificate)
+
+	migratedKey, err := targetKeyStore.Load(privKeyKey(t, cryptoSuite, user1))
+	require.NoError(t, err)
+	assert.Equal(t, key1, migratedKey)
+
+	_, err = targetUserStore.Load(identifier(user3))
+	assert.Equal(t, msp.ErrUserNotFound, err, "expected users of other MSPs not to be migrated")
+
+	// Migration may be run again
+	result, err = Migrate(opts)
+	require.NoError(t, err)
+	assert.Empty(t, result.Users)
+	assert.Len(t, result.Unchanged, 2)
+
+	// Conflicting user in target store
+	require.NoError(t, targetUserStore.Store(&msp.UserData{ID: user1.ID, MSPID: user1.MSPID, EnrollmentCertificate: user2.EnrollmentCertificate}))
+	_, err = Migrate(opts)
+	assert.Error(t, err)
+
+	opts.Overwrite = true
+	result, err = Migrate(opts)
+	require.NoError(t, err)
+	assert.Equal(t, []msp.IdentityIdentifier{identifier(user1)}, result.Users)
+}
+
+func TestMigrateInvalidOptions(t *testing.T) {
+	_, err := Migrate(nil)
+	assert.Error(t, err)
+
+	_, err = Migrate(&MigrationOptions{SourceUserStore: NewMemoryUserStore()})
+	assert.EqualError(t, err, "source and target user stores are required")
+
+	keyStore, err := NewFileKeyStore(migrationPath)
+	require.NoError(t, err)
+
+	_, err = Migrate(&MigrationOptions{SourceUserStore: NewMemoryUserStore(), TargetUserStore: NewMemoryUserStore(), SourceKeyStore: keyStore})
+	assert.EqualError(t, err, "both source and target key stores are required to migrate keys")
+
+	_, err = Migrate(&MigrationOptions{SourceUserStore: NewMemoryUserStore(), TargetUserStore: NewMemoryUserStore(), SourceKeyStore: keyStore, TargetKeyStore: keyStore})
+	assert.EqualError(t, err, "crypto suite is required to migrate keys")
+}
+
+func newMigrationUser(t *testing.T, id, mspID string) (*msp.UserData, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: id},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	user := &msp.UserData{
+		ID:                    id,
+		MSPID:                 mspID,
+		EnrollmentCertificate: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+	return user, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func privKeyKey(t *testing.T, cryptoSuite core.CryptoSuite, user *msp.UserData) *msp.PrivKeyKey {
+	pubKey, err := cryptoutil.GetPublicKeyFromCert(user.EnrollmentCertificate, cryptoSuite)
+	require.NoError(t, err)
+	return &msp.PrivKeyKey{ID: user.ID, MSPID: user.MSPID, SKI: pubKey.SKI()}
+}
+
+func identifier(user *msp.UserData) msp.IdentityIdentifier {
+	return msp.IdentityIdentifier{ID: user.ID, MSPID: user.MSPID}
+}
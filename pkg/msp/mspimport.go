@@ -0,0 +1,237 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	fabricCaUtil "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/util"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/cryptoutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/certexpiry"
+	"github.com/pkg/errors"
+)
+
+// ImportOptions specifies the MSP folder to import an identity from and the stores to import it into
+type ImportOptions struct {
+	// MSPDir is the path of an MSP folder produced by cryptogen or fabric-ca-client, with the
+	// signcerts, keystore and cacerts (and optionally intermediatecerts) sub-folders. Mandatory.
+	MSPDir string
+	// MSPID is the MSP ID of the identity, mandatory
+	MSPID string
+	// Optional. ID of the identity. Defaults to the common name of the signing certificate.
+	ID string
+	// UserStore into which the signing certificate is imported, mandatory
+	UserStore msp.UserStore
+	// CryptoSuite into whose key store the private key is imported, mandatory
+	CryptoSuite core.CryptoSuite
+	// Optional. If true, a user which already exists in the user store with another
+	// enrollment certificate is overwritten, otherwise the import fails.
+	Overwrite bool
+	// Optional. Tracker to which the imported certificate is reported if it is about to expire.
+	// Defaults to the default tracker of package certexpiry.
+	CertExpiryTracker *certexpiry.Tracker
+}
+
+// ImportResult contains the imported identity
+type ImportResult struct {
+	// User is the identifier of the imported user
+	User msp.IdentityIdentifier
+	// SKI of the imported private key
+	SKI []byte
+	// Unchanged is true if the user already existed in the user store with the same certificate
+	Unchanged bool
+	// CACerts are the PEM-encoded certificates of the cacerts folder, which issued the signing certificate
+	CACerts [][]byte
+}
+
+// ImportMSPDir imports the identity of an MSP folder into the user store and the key store of the
+// crypto suite, so that it can be retrieved by calling IdentityManager.GetSigningIdentity() without
+// configuring the paths of its certificate and key. The signing certificate must be issued by one
+// of the certificates of the cacerts folder.
+func ImportMSPDir(opts *ImportOptions) (*ImportResult, error) {
+	if err := validateImportOptions(opts); err != nil {
+		return nil, err
+	}
+
+	signCerts, err := readPEMFiles(filepath.Join(opts.MSPDir, "signcerts"))
+	if err != nil {
+		return nil, errors.WithMessage(err, "reading signcerts failed")
+	}
+	if len(signCerts) != 1 {
+		return nil, errors.Errorf("expected one signing certificate in MSP folder [%s], found %d", opts.MSPDir, len(signCerts))
+	}
+	certBytes := signCerts[0]
+	cert, err := fabricCaUtil.GetX509CertificateFromPEM(certBytes)
+	if err != nil {
+		return nil, errors.WithMessage(err, "parsing signing certificate failed")
+	}
+
+	caCerts, err := verifyMSPDirCert(opts.MSPDir, cert)
+	if err != nil {
+		return nil, err
+	}
+
+	id := opts.ID
+	if id == "" {
+		id = cert.Subject.CommonName
+	}
+	if id == "" {
+		return nil, errors.New("signing certificate has no common name, ID is required")
+	}
+	result := &ImportResult{User: msp.IdentityIdentifier{ID: id, MSPID: opts.MSPID}, CACerts: caCerts}
+
+	existing, err := opts.UserStore.Load(result.User)
+	if err != nil && err != msp.ErrUserNotFound {
+		return nil, errors.WithMessage(err, "loading user ["+userName(result.User)+"] failed")
+	}
+	if existing != nil && !bytes.Equal(existing.EnrollmentCertificate, certBytes) && !opts.Overwrite {
+		return nil, errors.Errorf("user [%s] exists in user store with a different enrollment certificate", userName(result.User))
+	}
+
+	result.SKI, err = importMSPDirKey(opts, certBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil && bytes.Equal(existing.EnrollmentCertificate, certBytes) {
+		logger.Debugf("User [%s] already exists in user store", userName(result.User))
+		result.Unchanged = true
+		return result, nil
+	}
+
+	err = opts.UserStore.Store(&msp.UserData{ID: id, MSPID: opts.MSPID, EnrollmentCertificate: certBytes})
+	if err != nil {
+		return nil, errors.WithMessage(err, "storing user ["+userName(result.User)+"] failed")
+	}
+
+	tracker := opts.CertExpiryTracker
+	if tracker == nil {
+		tracker = certexpiry.DefaultTracker()
+	}
+	tracker.CheckPEM(certexpiry.IdentitySource, id, certBytes)
+	logger.Debugf("Imported user [%s] from MSP folder [%s]", userName(result.User), opts.MSPDir)
+	return result, nil
+}
+
+func validateImportOptions(opts *ImportOptions) error {
+	if opts == nil {
+		return errors.New("import options are nil")
+	}
+	if opts.MSPDir == "" {
+		return errors.New("MSP folder is required")
+	}
+	if opts.MSPID == "" {
+		return errors.New("MSP ID is required")
+	}
+	if opts.UserStore == nil {
+		return errors.New("user store is required")
+	}
+	if opts.CryptoSuite == nil {
+		return errors.New("crypto suite is required")
+	}
+	return nil
+}
+
+// verifyMSPDirCert verifies that the certificate was issued by one of the certificates of the
+// cacerts folder, through the certificates of the intermediatecerts folder if any. The validity
+// period isn't checked, since expired identities may be imported (and are reported as such).
+// Returns the certificates of the cacerts folder.
+func verifyMSPDirCert(mspDir string, cert *x509.Certificate) ([][]byte, error) {
+	caCerts, err := readPEMFiles(filepath.Join(mspDir, "cacerts"))
+	if err != nil {
+		return nil, errors.WithMessage(err, "reading cacerts failed")
+	}
+	if len(caCerts) == 0 {
+		return nil, errors.Errorf("no CA certificates in MSP folder [%s]", mspDir)
+	}
+	intermediateCerts, err := readPEMFiles(filepath.Join(mspDir, "intermediatecerts"))
+	if err != nil && !os.IsNotExist(errors.Cause(err)) {
+		return nil, errors.WithMessage(err, "reading intermediatecerts failed")
+	}
+
+	verifyOpts := x509.VerifyOptions{
+		Roots:         x509.NewCertPool(),
+		Intermediates: x509.NewCertPool(),
+		CurrentTime:   cert.NotBefore,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	for _, caCert := range caCerts {
+		if !verifyOpts.Roots.AppendCertsFromPEM(caCert) {
+			return nil, errors.New("parsing CA certificate failed")
+		}
+	}
+	for _, intermediateCert := range intermediateCerts {
+		if !verifyOpts.Intermediates.AppendCertsFromPEM(intermediateCert) {
+			return nil, errors.New("parsing intermediate CA certificate failed")
+		}
+	}
+	if _, err := cert.Verify(verifyOpts); err != nil {
+		return nil, errors.Wrap(err, "signing certificate wasn't issued by the CA certificates of the MSP folder")
+	}
+	return caCerts, nil
+}
+
+// importMSPDirKey imports the private key of the keystore folder which matches the certificate
+// into the key store of the crypto suite and returns its SKI
+func importMSPDirKey(opts *ImportOptions, cert []byte) ([]byte, error) {
+	pubKey, err := cryptoutil.GetPublicKeyFromCert(cert, opts.CryptoSuite)
+	if err != nil {
+		return nil, errors.WithMessage(err, "fetching public key from cert failed")
+	}
+
+	keys, err := readPEMFiles(filepath.Join(opts.MSPDir, "keystore"))
+	if err != nil {
+		return nil, errors.WithMessage(err, "reading keystore failed")
+	}
+	for _, keyBytes := range keys {
+		key, err := fabricCaUtil.ImportBCCSPKeyFromPEMBytes(keyBytes, opts.CryptoSuite, true)
+		if err != nil {
+			logger.Debugf("Skipping key of MSP folder [%s]: %s", opts.MSPDir, err)
+			continue
+		}
+		if !bytes.Equal(key.SKI(), pubKey.SKI()) {
+			continue
+		}
+		if _, err := fabricCaUtil.ImportBCCSPKeyFromPEMBytes(keyBytes, opts.CryptoSuite, false); err != nil {
+			return nil, errors.WithMessage(err, "importing private key failed")
+		}
+		return key.SKI(), nil
+	}
+	return nil, errors.Errorf("private key of signing certificate not found in MSP folder [%s]", opts.MSPDir)
+}
+
+// readPEMFiles returns the content of the PEM files of a folder, in the order of the file names.
+// Files which don't contain a PEM block are ignored.
+func readPEMFiles(dir string) ([][]byte, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading folder [%s] failed", dir)
+	}
+	var pems [][]byte
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		content, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading file [%s] failed", file.Name())
+		}
+		if block, _ := pem.Decode(content); block == nil {
+			logger.Debugf("Skipping file [%s] of folder [%s]: not PEM encoded", file.Name(), dir)
+			continue
+		}
+		pems = append(pems, content)
+	}
+	return pems, nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	org1UsersPath = "../../test/fixtures/fabric/v1/crypto-config/peerOrganizations/org1.example.com/users"
+	org2UsersPath = "../../test/fixtures/fabric/v1/crypto-config/peerOrganizations/org2.example.com/users"
+	importPath    = "/tmp/testmspimport"
+)
+
+func TestImportMSPDir(t *testing.T) {
+	cryptoSuite := importCryptoSuite(t)
+	userStore := NewMemoryUserStore()
+
+	adminMSPDir := filepath.Join(org1UsersPath, "Admin@org1.example.com", "msp")
+	result, err := ImportMSPDir(&ImportOptions{MSPDir: adminMSPDir, MSPID: "Org1MSP", UserStore: userStore, CryptoSuite: cryptoSuite})
+	require.NoError(t, err)
+	assert.Equal(t, msp.IdentityIdentifier{ID: "Admin@org1.example.com", MSPID: "Org1MSP"}, result.User)
+	assert.Equal(t, "ce142124e13093a3e13bc4708b0f2b26e1d4d2ea4d4cc59942790bfc0f3bcc6d", hex.EncodeToString(result.SKI))
+	assert.False(t, result.Unchanged)
+	assert.Len(t, result.CACerts, 1)
+
+	key, err := cryptoSuite.GetKey(result.SKI)
+	require.NoError(t, err, "private key should be in the key store")
+	assert.True(t, key.Private())
+
+	cert, err := ioutil.ReadFile(filepath.Join(adminMSPDir, "signcerts", "Admin@org1.example.com-cert.pem"))
+	require.NoError(t, err)
+	userData, err := userStore.Load(result.User)
+	require.NoError(t, err)
+	assert.Equal(t, cert, userData.EnrollmentCertificate)
+
+	// Importing again doesn't change the user
+	result, err = ImportMSPDir(&ImportOptions{MSPDir: adminMSPDir, MSPID: "Org1MSP", UserStore: userStore, CryptoSuite: cryptoSuite})
+	require.NoError(t, err)
+	assert.True(t, result.Unchanged)
+
+	// Another identity with the same ID is only imported with the overwrite option
+	userMSPDir := filepath.Join(org1UsersPath, "User1@org1.example.com", "msp")
+	opts := &ImportOptions{MSPDir: userMSPDir, MSPID: "Org1MSP", ID: "Admin@org1.example.com", UserStore: userStore, CryptoSuite: cryptoSuite}
+	_, err = ImportMSPDir(opts)
+	assert.Error(t, err)
+	opts.Overwrite = true
+	result, err = ImportMSPDir(opts)
+	require.NoError(t, err)
+	assert.Equal(t, "Admin@org1.example.com", result.User.ID)
+	assert.False(t, result.Unchanged)
+}
+
+func TestImportMSPDirInvalid(t *testing.T) {
+	cleanupTestPath(t, importPath)
+	defer cleanupTestPath(t, importPath)
+
+	cryptoSuite := importCryptoSuite(t)
+	userStore := NewMemoryUserStore()
+
+	_, err := ImportMSPDir(nil)
+	assert.Error(t, err)
+	_, err = ImportMSPDir(&ImportOptions{MSPID: "Org1MSP", UserStore: userStore, CryptoSuite: cryptoSuite})
+	assert.Error(t, err)
+	_, err = ImportMSPDir(&ImportOptions{MSPDir: importPath, UserStore: userStore, CryptoSuite: cryptoSuite})
+	assert.Error(t, err)
+
+	// Signing certificate of Org1 with the CA certificate and the key of Org2
+	org1MSPDir := filepath.Join(org1UsersPath, "Admin@org1.example.com", "msp")
+	org2MSPDir := filepath.Join(org2UsersPath, "Admin@org2.example.com", "msp")
+	copyMSPSubDir(t, org1MSPDir, "signcerts")
+	copyMSPSubDir(t, org2MSPDir, "cacerts")
+	copyMSPSubDir(t, org2MSPDir, "keystore")
+
+	opts := &ImportOptions{MSPDir: importPath, MSPID: "Org1MSP", UserStore: userStore, CryptoSuite: cryptoSuite}
+	_, err = ImportMSPDir(opts)
+	assert.Error(t, err, "expected error for certificate issued by another CA")
+
+	cleanupTestPath(t, filepath.Join(importPath, "cacerts"))
+	copyMSPSubDir(t, org1MSPDir, "cacerts")
+	_, err = ImportMSPDir(opts)
+	assert.Error(t, err, "expected error for missing private key")
+
+	copyMSPSubDir(t, org1MSPDir, "keystore")
+	_, err = ImportMSPDir(opts)
+	assert.NoError(t, err)
+}
+
+// importCryptoSuite returns a crypto suite with an empty file key store
+func importCryptoSuite(t *testing.T) core.CryptoSuite {
+	cryptoConfig, _, _, _ := getConfigs(t)
+	cleanupTestPath(t, cryptoConfig.KeyStorePath())
+
+	cryptoSuite, err := sw.GetSuiteByConfig(cryptoConfig)
+	require.NoError(t, err)
+	return cryptoSuite
+}
+
+// copyMSPSubDir copies a sub-folder of an MSP folder into the import test folder
+func copyMSPSubDir(t *testing.T, mspDir, subDir string) {
+	files, err := ioutil.ReadDir(filepath.Join(mspDir, subDir))
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(importPath, subDir), 0700))
+	for _, file := range files {
+		content, err := ioutil.ReadFile(filepath.Join(mspDir, subDir, file.Name()))
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(importPath, subDir, file.Name()), content, 0600))
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mockca
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	cfsslapi "github.com/cloudflare/cfssl/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/util"
+)
+
+func startServer(t *testing.T, opts ...Option) *Server {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := NewServer(opts...)
+	require.NoError(t, server.Start(lis))
+	return server
+}
+
+func post(t *testing.T, client *http.Client, url string) (int, *cfsslapi.Response) {
+	resp, err := client.Post(url, "application/json", bytes.NewReader([]byte("{}")))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body := &cfsslapi.Response{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(body))
+	return resp.StatusCode, body
+}
+
+func TestEnroll(t *testing.T) {
+	server := startServer(t, WithEnrollment([]byte("cert"), nil), WithCAInfo("ca1", []byte("chain")))
+	defer server.Stop()
+
+	status, body := post(t, http.DefaultClient, server.URL()+"/enroll")
+	assert.Equal(t, http.StatusOK, status)
+	require.True(t, body.Success)
+
+	result := body.Result.(map[string]interface{})
+	assert.Equal(t, util.B64Encode([]byte("cert")), result["Cert"])
+	serverInfo := result["ServerInfo"].(map[string]interface{})
+	assert.Equal(t, "ca1", serverInfo["CAName"])
+	assert.Equal(t, util.B64Encode([]byte("chain")), serverInfo["CAChain"])
+}
+
+func TestCAInfo(t *testing.T) {
+	server := startServer(t, WithCAInfo("ca1", []byte("chain")), WithIdemixIssuerKeys([]byte("ipk"), []byte("rpk")))
+	defer server.Stop()
+
+	status, body := post(t, http.DefaultClient, server.URL()+"/cainfo")
+	assert.Equal(t, http.StatusOK, status)
+	require.True(t, body.Success)
+
+	result := body.Result.(map[string]interface{})
+	assert.Equal(t, "ca1", result["CAName"])
+	assert.Equal(t, util.B64Encode([]byte("ipk")), result["IssuerPublicKey"])
+	assert.Equal(t, util.B64Encode([]byte("rpk")), result["IssuerRevocationPublicKey"])
+}
+
+func TestRegister(t *testing.T) {
+	server := startServer(t, WithSecret("secret"))
+	defer server.Stop()
+
+	status, body := post(t, http.DefaultClient, server.URL()+"/register")
+	assert.Equal(t, http.StatusOK, status)
+	require.True(t, body.Success)
+	assert.Equal(t, "secret", body.Result.(map[string]interface{})["secret"])
+}
+
+func TestError(t *testing.T) {
+	server := startServer(t, WithError(Enroll, http.StatusUnauthorized, "authentication failure"))
+	defer server.Stop()
+
+	status, body := post(t, http.DefaultClient, server.URL()+"/enroll")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.False(t, body.Success)
+	require.Len(t, body.Errors, 1)
+	assert.Equal(t, "authentication failure", body.Errors[0].Message)
+
+	// Other endpoints aren't affected
+	_, body = post(t, http.DefaultClient, server.URL()+"/reenroll")
+	assert.True(t, body.Success)
+
+	server.ClearError(Enroll)
+	_, body = post(t, http.DefaultClient, server.URL()+"/enroll")
+	assert.True(t, body.Success)
+
+	server.SetError(Revoke, http.StatusInternalServerError, "database failure")
+	status, _ = post(t, http.DefaultClient, server.URL()+"/revoke")
+	assert.Equal(t, http.StatusInternalServerError, status)
+}
+
+func TestTLS(t *testing.T) {
+	cert := newTLSCertificate(t)
+	server := startServer(t, WithTLS(cert))
+	defer server.Stop()
+
+	assert.Contains(t, server.URL(), "https://")
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	_, body := post(t, client, server.URL()+"/enroll")
+	assert.True(t, body.Success)
+}
+
+func TestStop(t *testing.T) {
+	server := startServer(t)
+	assert.True(t, server.Running())
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+	assert.Error(t, server.Start(lis), "server should not start twice")
+
+	require.NoError(t, server.Stop())
+	assert.False(t, server.Running())
+}
+
+func TestLatencyAndReset(t *testing.T) {
+	server := startServer(t, WithLatency(100*time.Millisecond))
+	defer server.Stop()
+
+	start := time.Now()
+	_, body := post(t, http.DefaultClient, server.URL()+"/enroll")
+	assert.True(t, body.Success)
+	assert.True(t, time.Since(start) >= 100*time.Millisecond, "expected response to be delayed")
+
+	server.SetLatency(0)
+	server.ResetConnections(1)
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	_, err := client.Post(server.URL()+"/enroll", "application/json", bytes.NewReader([]byte("{}")))
+	assert.Error(t, err, "expected connection to be reset")
+
+	_, body = post(t, client, server.URL()+"/enroll")
+	assert.True(t, body.Success)
+}
+
+func newTLSCertificate(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
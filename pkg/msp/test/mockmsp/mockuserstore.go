@@ -23,3 +23,8 @@ func (m *MockUserStore) Store(*msp.UserData) error {
 func (m *MockUserStore) Load(identifier msp.IdentityIdentifier) (*msp.UserData, error) {
 	return &msp.UserData{}, nil
 }
+
+// List ...
+func (m *MockUserStore) List(mspID string) ([]msp.IdentityIdentifier, error) {
+	return nil, nil
+}
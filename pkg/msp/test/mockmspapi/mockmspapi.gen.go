@@ -35,15 +35,41 @@ func (m *MockCAClient) EXPECT() *MockCAClientMockRecorder {
 }
 
 // Enroll mocks base method
-func (m *MockCAClient) Enroll(arg0, arg1 string) error {
-	ret := m.ctrl.Call(m, "Enroll", arg0, arg1)
+func (m *MockCAClient) Enroll(arg0 *api.EnrollmentRequest) error {
+	ret := m.ctrl.Call(m, "Enroll", arg0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Enroll indicates an expected call of Enroll
-func (mr *MockCAClientMockRecorder) Enroll(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enroll", reflect.TypeOf((*MockCAClient)(nil).Enroll), arg0, arg1)
+func (mr *MockCAClientMockRecorder) Enroll(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enroll", reflect.TypeOf((*MockCAClient)(nil).Enroll), arg0)
+}
+
+// GetCAInfo mocks base method
+func (m *MockCAClient) GetCAInfo() (*api.GetCAInfoResponse, error) {
+	ret := m.ctrl.Call(m, "GetCAInfo")
+	ret0, _ := ret[0].(*api.GetCAInfoResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCAInfo indicates an expected call of GetCAInfo
+func (mr *MockCAClientMockRecorder) GetCAInfo() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCAInfo", reflect.TypeOf((*MockCAClient)(nil).GetCAInfo))
+}
+
+// GetIdemixIssuerKeys mocks base method
+func (m *MockCAClient) GetIdemixIssuerKeys() (*api.IdemixIssuerKeys, error) {
+	ret := m.ctrl.Call(m, "GetIdemixIssuerKeys")
+	ret0, _ := ret[0].(*api.IdemixIssuerKeys)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdemixIssuerKeys indicates an expected call of GetIdemixIssuerKeys
+func (mr *MockCAClientMockRecorder) GetIdemixIssuerKeys() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdemixIssuerKeys", reflect.TypeOf((*MockCAClient)(nil).GetIdemixIssuerKeys))
 }
 
 // Reenroll mocks base method
@@ -0,0 +1,113 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"bytes"
+	"encoding/pem"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+)
+
+// ListUsers returns the IDs of the users of the organization which are enrolled in the user store
+// or embedded in the configuration. The users of the MSP folders of the crypto path can't be listed.
+// The user store must implement msp.UserLister.
+func (mgr *IdentityManager) ListUsers() ([]string, error) {
+	users := make(map[string]bool)
+	if mgr.userStore != nil {
+		lister, ok := mgr.userStore.(msp.UserLister)
+		if !ok {
+			return nil, errors.New("user store doesn't support listing of users")
+		}
+		ids, err := lister.List(mgr.orgMSPID)
+		if err != nil {
+			return nil, errors.WithMessage(err, "listing users of user store failed")
+		}
+		for _, id := range ids {
+			users[id.ID] = true
+		}
+	}
+	for username := range mgr.embeddedUsers {
+		users[username] = true
+	}
+
+	var ids []string
+	for id := range users {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// GetUserDataByCert returns the record of the user with the given (PEM or DER encoded) enrollment certificate
+func (mgr *IdentityManager) GetUserDataByCert(cert []byte) (*msp.UserData, error) {
+	der := certDER(cert)
+	if len(der) == 0 {
+		return nil, errors.New("certificate is required")
+	}
+
+	ids, err := mgr.ListUsers()
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		userData, err := mgr.loadUserData(id)
+		if err != nil {
+			if err == msp.ErrUserNotFound {
+				continue
+			}
+			return nil, errors.WithMessage(err, "loading user failed")
+		}
+		if bytes.Equal(certDER(userData.EnrollmentCertificate), der) {
+			return userData, nil
+		}
+	}
+	return nil, msp.ErrUserNotFound
+}
+
+// GetSigningIdentityByCert returns the signing identity of the user with the given (PEM or DER encoded)
+// enrollment certificate
+func (mgr *IdentityManager) GetSigningIdentityByCert(cert []byte) (msp.SigningIdentity, error) {
+	userData, err := mgr.GetUserDataByCert(cert)
+	if err != nil {
+		return nil, err
+	}
+	return mgr.GetSigningIdentity(userData.ID)
+}
+
+// loadUserData returns the record of the user from the user store or from the embedded users
+func (mgr *IdentityManager) loadUserData(username string) (*msp.UserData, error) {
+	if mgr.userStore != nil {
+		userData, err := mgr.userStore.Load(msp.IdentityIdentifier{MSPID: mgr.orgMSPID, ID: username})
+		if err == nil {
+			return userData, nil
+		}
+		if err != msp.ErrUserNotFound {
+			return nil, err
+		}
+	}
+	certBytes, err := mgr.getEmbeddedCertBytes(username)
+	if err != nil {
+		return nil, err
+	}
+	if certBytes == nil {
+		return nil, msp.ErrUserNotFound
+	}
+	return &msp.UserData{ID: username, MSPID: mgr.orgMSPID, EnrollmentCertificate: certBytes}, nil
+}
+
+// certDER returns the DER bytes of a PEM encoded certificate, or the given bytes if they aren't PEM encoded
+func certDER(cert []byte) []byte {
+	block, _ := pem.Decode(cert)
+	if block == nil {
+		return bytes.TrimSpace(cert)
+	}
+	return block.Bytes
+}
@@ -0,0 +1,53 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"sort"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/pkg/errors"
+)
+
+// ForEachUser loads each user of the given MSP (or each user if the MSP ID is empty)
+// from the user store and invokes the given function. Users are loaded one at a time.
+// Iteration stops at the first error, which is returned.
+// The user store must implement msp.UserLister.
+func ForEachUser(store msp.UserStore, mspID string, fn func(user *msp.UserData) error) error {
+	lister, ok := store.(msp.UserLister)
+	if !ok {
+		return errors.New("user store doesn't support listing of users")
+	}
+	ids, err := lister.List(mspID)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		user, err := store.Load(id)
+		if err != nil {
+			if err == msp.ErrUserNotFound {
+				// Deleted after it was listed
+				continue
+			}
+			return errors.WithMessage(err, "loading user ["+id.ID+"@"+id.MSPID+"] failed")
+		}
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortIdentifiers sorts the identifiers by MSP ID and then by ID
+func sortIdentifiers(ids []msp.IdentityIdentifier) {
+	sort.Slice(ids, func(i, j int) bool {
+		if ids[i].MSPID != ids[j].MSPID {
+			return ids[i].MSPID < ids[j].MSPID
+		}
+		return ids[i].ID < ids[j].ID
+	})
+}
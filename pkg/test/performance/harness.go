@@ -0,0 +1,229 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package performance provides a benchmark harness which drives a configurable mix of transactions
+// (invoke/query ratio, payload size, concurrency) through a channel client and reports the latency
+// percentiles and throughput of the run, for comparing SDK configurations.
+//
+// The channel client may be connected to a Fabric network or to the mock infrastructure
+// (see pkg/fabsdk/test/mocknetwork).
+//
+// Basic flow:
+//
+//  client, err := channel.New(sdk.ChannelContext("mychannel", fabsdk.WithUser("User1")))
+//  harness := performance.New(client, "example_cc",
+//      performance.WithConcurrency(10),
+//      performance.WithRequests(10000),
+//      performance.WithInvokeRatio(0.2),
+//      performance.WithPayloadSize(1024),
+//  )
+//  report, err := harness.Run(context.Background())
+//  fmt.Println(report)
+package performance
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+)
+
+// Client executes and queries chaincode. It is implemented by the channel client.
+type Client interface {
+	Execute(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+	Query(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+}
+
+type options struct {
+	concurrency int
+	requests    int
+	duration    time.Duration
+	invokeRatio float64
+	payloadSize int
+	keys        int
+	invokeFcn   string
+	queryFcn    string
+	seed        int64
+	reqOpts     []channel.RequestOption
+}
+
+// Option is an option of the harness
+type Option func(*options)
+
+// WithConcurrency sets the number of concurrent clients. The default is 1.
+func WithConcurrency(concurrency int) Option {
+	return func(o *options) {
+		o.concurrency = concurrency
+	}
+}
+
+// WithRequests sets the total number of requests of the run. The default is 1000.
+func WithRequests(requests int) Option {
+	return func(o *options) {
+		o.requests = requests
+	}
+}
+
+// WithDuration makes the run last for the given duration instead of a number of requests
+func WithDuration(duration time.Duration) Option {
+	return func(o *options) {
+		o.duration = duration
+	}
+}
+
+// WithInvokeRatio sets the ratio (between 0 and 1) of invokes in the transaction mix;
+// the other requests are queries. The default is 0.5.
+func WithInvokeRatio(ratio float64) Option {
+	return func(o *options) {
+		o.invokeRatio = ratio
+	}
+}
+
+// WithPayloadSize sets the size in bytes of the value written by invokes. The default is 100.
+func WithPayloadSize(size int) Option {
+	return func(o *options) {
+		o.payloadSize = size
+	}
+}
+
+// WithKeys sets the number of distinct keys written and queried. The default is 100.
+func WithKeys(keys int) Option {
+	return func(o *options) {
+		o.keys = keys
+	}
+}
+
+// WithFunctions sets the chaincode functions of invokes and queries. Invokes are called with
+// the arguments (key, payload) and queries with the argument (key). The defaults are "put" and "get".
+func WithFunctions(invokeFcn, queryFcn string) Option {
+	return func(o *options) {
+		o.invokeFcn = invokeFcn
+		o.queryFcn = queryFcn
+	}
+}
+
+// WithSeed sets the seed of the random transaction mix and payloads, which makes runs repeatable
+func WithSeed(seed int64) Option {
+	return func(o *options) {
+		o.seed = seed
+	}
+}
+
+// WithRequestOptions sets the options of the channel client requests (e.g. targets, timeouts, retries)
+func WithRequestOptions(opts ...channel.RequestOption) Option {
+	return func(o *options) {
+		o.reqOpts = opts
+	}
+}
+
+// Harness drives a transaction mix through a client
+type Harness struct {
+	client      Client
+	chaincodeID string
+	opts        options
+}
+
+// New returns a new harness for the given client and chaincode
+func New(client Client, chaincodeID string, opts ...Option) *Harness {
+	o := options{
+		concurrency: 1,
+		requests:    1000,
+		invokeRatio: 0.5,
+		payloadSize: 100,
+		keys:        100,
+		invokeFcn:   "put",
+		queryFcn:    "get",
+		seed:        time.Now().UnixNano(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Harness{client: client, chaincodeID: chaincodeID, opts: o}
+}
+
+// Run runs the transaction mix until the number of requests (or the duration) of the run is reached,
+// or until the context is done, and returns the report of the run
+func (h *Harness) Run(ctx context.Context) (*Report, error) {
+	if h.opts.concurrency < 1 {
+		return nil, errors.New("concurrency must be at least 1")
+	}
+	if h.opts.invokeRatio < 0 || h.opts.invokeRatio > 1 {
+		return nil, errors.New("invoke ratio must be between 0 and 1")
+	}
+	if h.opts.keys < 1 {
+		return nil, errors.New("number of keys must be at least 1")
+	}
+
+	if h.opts.duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.opts.duration)
+		defer cancel()
+	}
+
+	var sent int64
+	next := func() bool {
+		if ctx.Err() != nil {
+			return false
+		}
+		return h.opts.duration > 0 || atomic.AddInt64(&sent, 1) <= int64(h.opts.requests)
+	}
+
+	results := make([][]result, h.opts.concurrency)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < h.opts.concurrency; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			results[worker] = h.work(rand.New(rand.NewSource(h.opts.seed+int64(worker))), next)
+		}(i)
+	}
+	wg.Wait()
+
+	return newReport(time.Since(start), results), nil
+}
+
+// work sends requests until next returns false and returns the results of the requests
+func (h *Harness) work(rnd *rand.Rand, next func() bool) []result {
+	var results []result
+	for next() {
+		key := fmt.Sprintf("key%d", rnd.Intn(h.opts.keys))
+		if rnd.Float64() < h.opts.invokeRatio {
+			payload := make([]byte, h.opts.payloadSize)
+			rnd.Read(payload) //nolint
+			results = append(results, h.send(true, h.client.Execute, channel.Request{
+				ChaincodeID: h.chaincodeID,
+				Fcn:         h.opts.invokeFcn,
+				Args:        [][]byte{[]byte(key), payload},
+			}))
+		} else {
+			results = append(results, h.send(false, h.client.Query, channel.Request{
+				ChaincodeID: h.chaincodeID,
+				Fcn:         h.opts.queryFcn,
+				Args:        [][]byte{[]byte(key)},
+			}))
+		}
+	}
+	return results
+}
+
+func (h *Harness) send(invoke bool, fn func(channel.Request, ...channel.RequestOption) (channel.Response, error), request channel.Request) result {
+	start := time.Now()
+	_, err := fn(request, h.opts.reqOpts...)
+	return result{invoke: invoke, latency: time.Since(start), err: err}
+}
+
+type result struct {
+	invoke  bool
+	latency time.Duration
+	err     error
+}
@@ -0,0 +1,106 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package performance
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+)
+
+type mockClient struct {
+	invokes int32
+	queries int32
+	failing int32
+	latency time.Duration
+}
+
+func (c *mockClient) Execute(request channel.Request, options ...channel.RequestOption) (channel.Response, error) {
+	n := atomic.AddInt32(&c.invokes, 1)
+	time.Sleep(c.latency)
+	if n <= atomic.LoadInt32(&c.failing) {
+		return channel.Response{}, errors.New("endorsement failure")
+	}
+	if len(request.Args) != 2 || len(request.Args[1]) != 64 {
+		return channel.Response{}, errors.New("unexpected invoke args")
+	}
+	return channel.Response{}, nil
+}
+
+func (c *mockClient) Query(request channel.Request, options ...channel.RequestOption) (channel.Response, error) {
+	atomic.AddInt32(&c.queries, 1)
+	time.Sleep(c.latency)
+	return channel.Response{Payload: []byte("value")}, nil
+}
+
+func TestRunRequests(t *testing.T) {
+	client := &mockClient{failing: 5}
+	harness := New(client, "example_cc",
+		WithConcurrency(4),
+		WithRequests(200),
+		WithInvokeRatio(0.25),
+		WithPayloadSize(64),
+		WithSeed(1),
+	)
+
+	report, err := harness.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 200, report.Total.Count)
+	assert.Equal(t, int(client.invokes), report.Invokes.Count)
+	assert.Equal(t, int(client.queries), report.Queries.Count)
+	assert.InDelta(t, 50, report.Invokes.Count, 25, "expecting about a quarter of the requests to be invokes")
+	assert.Equal(t, 5, report.Invokes.Errors)
+	assert.Equal(t, 5, report.Total.Errors)
+	assert.True(t, report.Total.Throughput > 0)
+	assert.Contains(t, report.String(), "invoke")
+}
+
+func TestRunDuration(t *testing.T) {
+	client := &mockClient{latency: time.Millisecond}
+	harness := New(client, "example_cc", WithConcurrency(2), WithDuration(50*time.Millisecond))
+
+	start := time.Now()
+	report, err := harness.Run(context.Background())
+	require.NoError(t, err)
+	assert.True(t, time.Since(start) < time.Second)
+	assert.True(t, report.Total.Count > 0)
+	assert.True(t, report.Total.Min >= time.Millisecond)
+}
+
+func TestInvalidOptions(t *testing.T) {
+	_, err := New(&mockClient{}, "example_cc", WithConcurrency(0)).Run(context.Background())
+	assert.Error(t, err)
+
+	_, err = New(&mockClient{}, "example_cc", WithInvokeRatio(1.5)).Run(context.Background())
+	assert.Error(t, err)
+}
+
+func TestPercentiles(t *testing.T) {
+	var results []result
+	for i := 1; i <= 100; i++ {
+		results = append(results, result{latency: time.Duration(i) * time.Millisecond})
+	}
+	results = append(results, result{err: errors.New("failure")})
+
+	stats := newStats(time.Second, results)
+	assert.Equal(t, 101, stats.Count)
+	assert.Equal(t, 1, stats.Errors)
+	assert.Equal(t, time.Millisecond, stats.Min)
+	assert.Equal(t, 100*time.Millisecond, stats.Max)
+	assert.Equal(t, 50*time.Millisecond, stats.P50)
+	assert.Equal(t, 90*time.Millisecond, stats.P90)
+	assert.Equal(t, 99*time.Millisecond, stats.P99)
+	assert.Equal(t, float64(100), stats.Throughput)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package performance
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// Report is the report of a run
+type Report struct {
+	Duration time.Duration
+	Invokes  Stats
+	Queries  Stats
+	Total    Stats
+}
+
+// Stats are the latency and throughput statistics of a set of requests
+type Stats struct {
+	Count  int
+	Errors int
+	// Throughput is the number of successful requests per second
+	Throughput float64
+	Min        time.Duration
+	Mean       time.Duration
+	P50        time.Duration
+	P90        time.Duration
+	P95        time.Duration
+	P99        time.Duration
+	Max        time.Duration
+}
+
+// String returns the report as a table
+func (r *Report) String() string {
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(w, "\tcount\terrors\ttps\tmin\tmean\tp50\tp90\tp95\tp99\tmax\t\n")
+	for _, row := range []struct {
+		name  string
+		stats Stats
+	}{{"invoke", r.Invokes}, {"query", r.Queries}, {"total", r.Total}} {
+		s := row.stats
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.1f\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n", row.name, s.Count, s.Errors, s.Throughput,
+			s.Min, s.Mean, s.P50, s.P90, s.P95, s.P99, s.Max)
+	}
+	w.Flush() //nolint
+	return fmt.Sprintf("duration: %s\n%s", r.Duration, buf.String())
+}
+
+func newReport(duration time.Duration, results [][]result) *Report {
+	var invokes, queries, total []result
+	for _, workerResults := range results {
+		for _, r := range workerResults {
+			if r.invoke {
+				invokes = append(invokes, r)
+			} else {
+				queries = append(queries, r)
+			}
+			total = append(total, r)
+		}
+	}
+
+	return &Report{
+		Duration: duration,
+		Invokes:  newStats(duration, invokes),
+		Queries:  newStats(duration, queries),
+		Total:    newStats(duration, total),
+	}
+}
+
+// newStats returns the statistics of the given results. The latency statistics are those of
+// the successful requests.
+func newStats(duration time.Duration, results []result) Stats {
+	stats := Stats{Count: len(results)}
+
+	var latencies []time.Duration
+	var sum time.Duration
+	for _, r := range results {
+		if r.err != nil {
+			stats.Errors++
+			continue
+		}
+		latencies = append(latencies, r.latency)
+		sum += r.latency
+	}
+	if len(latencies) == 0 {
+		return stats
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	stats.Min = latencies[0]
+	stats.Max = latencies[len(latencies)-1]
+	stats.Mean = sum / time.Duration(len(latencies))
+	stats.P50 = percentile(latencies, 50)
+	stats.P90 = percentile(latencies, 90)
+	stats.P95 = percentile(latencies, 95)
+	stats.P99 = percentile(latencies, 99)
+	if duration > 0 {
+		stats.Throughput = float64(len(latencies)) / duration.Seconds()
+	}
+	return stats
+}
+
+// percentile returns the nearest-rank percentile of the given sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
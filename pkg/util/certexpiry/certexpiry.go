@@ -0,0 +1,256 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package certexpiry tracks certificates which are about to expire (or have expired) and
+// notifies registered handlers with structured events. The set of expiring certificates and
+// event counters are available as metrics. Each SDK instance has its own tracker, which is held
+// by its endpoint config; the package-level functions use a default tracker for certificates
+// which are checked outside of an SDK instance.
+package certexpiry
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/recovery"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+)
+
+var logger = logging.NewLogger("fabsdk/util")
+
+const (
+	// DefaultWarningThreshold is the remaining validity below which a certificate is reported as expiring
+	DefaultWarningThreshold = 30 * 24 * time.Hour
+)
+
+// Source identifies the component which checked a certificate
+type Source string
+
+const (
+	// MembershipSource is the source of events for certificates of channel MSPs
+	MembershipSource Source = "membership"
+	// IdentitySource is the source of events for local signing certificates
+	IdentitySource Source = "identity"
+)
+
+// Event is published when a certificate is found to be within the warning threshold
+// of its expiry or expired
+type Event struct {
+	Source       Source
+	Subject      string
+	CommonName   string
+	SerialNumber string
+	NotAfter     time.Time
+	Remaining    time.Duration
+	Expired      bool
+}
+
+func (e *Event) String() string {
+	if e.Expired {
+		return fmt.Sprintf("%s certificate [%s] of [%s] (serial %s) expired at %s", e.Source, e.CommonName, e.Subject, e.SerialNumber, e.NotAfter)
+	}
+	return fmt.Sprintf("%s certificate [%s] of [%s] (serial %s) expires in %s at %s", e.Source, e.CommonName, e.Subject, e.SerialNumber, e.Remaining, e.NotAfter)
+}
+
+// Handler is notified of certificate expiry events
+type Handler func(event *Event)
+
+// Metrics contains the certificate expiry metrics
+type Metrics struct {
+	// Warnings is the number of certificates reported as expiring
+	Warnings uint64
+	// Expirations is the number of certificates reported as expired
+	Expirations uint64
+	// Expiring contains the latest event of each certificate which is expiring or expired, ordered by expiry
+	Expiring []Event
+}
+
+// TrackerProvider is implemented by configs which hold the certificate expiry tracker of an SDK instance
+type TrackerProvider interface {
+	CertExpiryTracker() *Tracker
+}
+
+// defaultTracker tracks the certificates which are checked outside of an SDK instance
+var defaultTracker = NewTracker(DefaultWarningThreshold)
+
+// DefaultTracker returns the tracker of the certificates which are checked outside of an SDK instance
+func DefaultTracker() *Tracker {
+	return defaultTracker
+}
+
+// TrackerFor returns the tracker held by the given config if it implements TrackerProvider,
+// otherwise the default tracker is returned
+func TrackerFor(config interface{}) *Tracker {
+	if provider, ok := config.(TrackerProvider); ok {
+		if t := provider.CertExpiryTracker(); t != nil {
+			return t
+		}
+	}
+	return defaultTracker
+}
+
+// SetWarningThreshold sets the warning threshold of the default tracker
+func SetWarningThreshold(threshold time.Duration) {
+	defaultTracker.SetWarningThreshold(threshold)
+}
+
+// WarningThreshold returns the warning threshold of the default tracker
+func WarningThreshold() time.Duration {
+	return defaultTracker.WarningThreshold()
+}
+
+// RegisterHandler registers a handler with the default tracker
+func RegisterHandler(handler Handler) {
+	defaultTracker.RegisterHandler(handler)
+}
+
+// Check checks the given certificate with the default tracker
+func Check(source Source, subject string, cert *x509.Certificate) *Event {
+	return defaultTracker.Check(source, subject, cert)
+}
+
+// CheckPEM checks all certificates in the given PEM bytes with the default tracker
+func CheckPEM(source Source, subject string, pemCerts []byte) {
+	defaultTracker.CheckPEM(source, subject, pemCerts)
+}
+
+// GetMetrics returns the certificate expiry metrics of the default tracker
+func GetMetrics() Metrics {
+	return defaultTracker.Metrics()
+}
+
+// Tracker tracks the expiring certificates reported by the components of an SDK instance
+type Tracker struct {
+	lock        sync.RWMutex
+	threshold   time.Duration
+	handlers    []Handler
+	expiring    map[string]*Event
+	warnings    uint64
+	expirations uint64
+}
+
+// NewTracker returns a tracker which reports certificates as expiring once their remaining
+// validity is below the given threshold
+func NewTracker(threshold time.Duration) *Tracker {
+	return &Tracker{threshold: threshold, expiring: make(map[string]*Event)}
+}
+
+// SetWarningThreshold sets the remaining validity below which certificates are reported as expiring
+func (t *Tracker) SetWarningThreshold(threshold time.Duration) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.threshold = threshold
+}
+
+// WarningThreshold returns the remaining validity below which certificates are reported as expiring
+func (t *Tracker) WarningThreshold() time.Duration {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return t.threshold
+}
+
+// RegisterHandler registers a handler which is notified of certificate expiry events. Handlers
+// are invoked synchronously and only once per certificate and state (expiring or expired).
+func (t *Tracker) RegisterHandler(handler Handler) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.handlers = append(t.handlers, handler)
+}
+
+// Check checks whether the given certificate is within the warning threshold of its expiry (or
+// expired) and, if so, logs a warning and notifies the registered handlers. The event is returned
+// or nil if the certificate isn't expiring.
+func (t *Tracker) Check(source Source, subject string, cert *x509.Certificate) *Event {
+	return t.check(source, subject, cert, time.Now())
+}
+
+// CheckPEM checks all certificates in the given PEM bytes
+func (t *Tracker) CheckPEM(source Source, subject string, pemCerts []byte) {
+	for len(pemCerts) > 0 {
+		var block *pem.Block
+		block, pemCerts = pem.Decode(pemCerts)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			logger.Debugf("Failed to parse certificate of [%s]: %s", subject, err)
+			continue
+		}
+		t.Check(source, subject, cert)
+	}
+}
+
+// Metrics returns the certificate expiry metrics
+func (t *Tracker) Metrics() Metrics {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	m := Metrics{Warnings: t.warnings, Expirations: t.expirations}
+	for _, event := range t.expiring {
+		m.Expiring = append(m.Expiring, *event)
+	}
+	sort.Slice(m.Expiring, func(i, j int) bool {
+		return m.Expiring[i].NotAfter.Before(m.Expiring[j].NotAfter)
+	})
+	return m
+}
+
+func (t *Tracker) check(source Source, subject string, cert *x509.Certificate, now time.Time) *Event {
+	if cert == nil {
+		return nil
+	}
+
+	remaining := cert.NotAfter.Sub(now)
+
+	t.lock.Lock()
+	if remaining > t.threshold {
+		t.lock.Unlock()
+		return nil
+	}
+
+	event := &Event{
+		Source:       source,
+		Subject:      subject,
+		CommonName:   cert.Subject.CommonName,
+		SerialNumber: cert.SerialNumber.String(),
+		NotAfter:     cert.NotAfter,
+		Remaining:    remaining,
+		Expired:      remaining <= 0,
+	}
+
+	key := fmt.Sprintf("%s/%s/%x/%s", source, subject, cert.RawIssuer, event.SerialNumber)
+	previous, ok := t.expiring[key]
+	t.expiring[key] = event
+	if ok && previous.Expired == event.Expired {
+		// Already reported in this state
+		t.lock.Unlock()
+		return event
+	}
+
+	if event.Expired {
+		t.expirations++
+	} else {
+		t.warnings++
+	}
+	handlers := append([]Handler(nil), t.handlers...)
+	t.lock.Unlock()
+
+	logger.Warnf("Certificate expiry: %s", event)
+	for _, handler := range handlers {
+		if err := recovery.Call(func() { handler(event) }); err != nil {
+			logger.Errorf("Certificate expiry handler failed: %s", err)
+		}
+	}
+	return event
+}
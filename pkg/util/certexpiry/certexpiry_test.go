@@ -0,0 +1,139 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package certexpiry
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	tracker := NewTracker(24 * time.Hour)
+
+	var events []*Event
+	tracker.RegisterHandler(func(event *Event) {
+		events = append(events, event)
+	})
+
+	now := time.Now().Truncate(time.Second)
+	cert := newCert(t, 1, now.Add(48*time.Hour))
+
+	// Not within threshold
+	assert.Nil(t, tracker.check(MembershipSource, "Org1MSP", cert, now))
+	assert.Empty(t, events)
+
+	// Within threshold
+	event := tracker.check(MembershipSource, "Org1MSP", cert, now.Add(36*time.Hour))
+	require.NotNil(t, event)
+	assert.False(t, event.Expired)
+	assert.Equal(t, 12*time.Hour, event.Remaining)
+	assert.Equal(t, "Org1MSP", event.Subject)
+	assert.Equal(t, "1", event.SerialNumber)
+	assert.Len(t, events, 1)
+
+	// Handlers are only notified once per state
+	assert.NotNil(t, tracker.check(MembershipSource, "Org1MSP", cert, now.Add(40*time.Hour)))
+	assert.Len(t, events, 1)
+
+	// Expired
+	event = tracker.check(MembershipSource, "Org1MSP", cert, now.Add(72*time.Hour))
+	require.NotNil(t, event)
+	assert.True(t, event.Expired)
+	assert.Len(t, events, 2)
+
+	// Another certificate
+	tracker.check(IdentitySource, "User1", newCert(t, 2, now.Add(time.Hour)), now)
+	assert.Len(t, events, 3)
+
+	metrics := tracker.Metrics()
+	assert.Equal(t, uint64(2), metrics.Warnings)
+	assert.Equal(t, uint64(1), metrics.Expirations)
+	require.Len(t, metrics.Expiring, 2)
+	assert.Equal(t, "User1", metrics.Expiring[0].Subject)
+	assert.Equal(t, "Org1MSP", metrics.Expiring[1].Subject)
+}
+
+func TestPanickingHandler(t *testing.T) {
+	tracker := NewTracker(24 * time.Hour)
+
+	var events []*Event
+	tracker.RegisterHandler(func(event *Event) {
+		panic("handler failed")
+	})
+	tracker.RegisterHandler(func(event *Event) {
+		events = append(events, event)
+	})
+
+	// The other handlers are notified
+	now := time.Now()
+	assert.NotNil(t, tracker.check(MembershipSource, "Org1MSP", newCert(t, 1, now.Add(time.Hour)), now))
+	assert.Len(t, events, 1)
+}
+
+func TestWarningThreshold(t *testing.T) {
+	tracker := NewTracker(DefaultWarningThreshold)
+	tracker.SetWarningThreshold(time.Hour)
+	assert.Equal(t, time.Hour, tracker.WarningThreshold())
+	assert.Equal(t, DefaultWarningThreshold, WarningThreshold())
+}
+
+func TestCheckPEM(t *testing.T) {
+	tracker := NewTracker(DefaultWarningThreshold)
+
+	cert := newCert(t, 3, time.Now().Add(time.Hour))
+	pemCerts := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	tracker.CheckPEM(IdentitySource, "User1", append(pemCerts, []byte("invalid")...))
+
+	metrics := tracker.Metrics()
+	assert.Equal(t, uint64(1), metrics.Warnings)
+	require.Len(t, metrics.Expiring, 1)
+	assert.Equal(t, IdentitySource, metrics.Expiring[0].Source)
+	assert.Empty(t, GetMetrics().Expiring)
+}
+
+func TestTrackerFor(t *testing.T) {
+	tracker := NewTracker(DefaultWarningThreshold)
+	assert.True(t, tracker == TrackerFor(&trackerConfig{tracker: tracker}))
+	assert.True(t, defaultTracker == TrackerFor(&trackerConfig{}))
+	assert.True(t, defaultTracker == TrackerFor("config"))
+}
+
+type trackerConfig struct {
+	tracker *Tracker
+}
+
+func (c *trackerConfig) CertExpiryTracker() *Tracker {
+	return c.tracker
+}
+
+func newCert(t *testing.T, serial int64, notAfter time.Time) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
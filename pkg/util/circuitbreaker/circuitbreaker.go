@@ -0,0 +1,257 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package circuitbreaker provides circuit breakers which stop requests from being sent to
+// endpoints which have failed repeatedly, for a cool-down period. After the cool-down period
+// a single trial request is allowed; the circuit is closed again if the trial succeeds.
+package circuitbreaker
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+)
+
+var logger = logging.NewLogger("fabsdk/util")
+
+const (
+	// DefaultFailureThreshold is the default number of consecutive failures which opens a circuit
+	DefaultFailureThreshold = 3
+	// DefaultCoolDown is the default period for which an open circuit rejects requests
+	DefaultCoolDown = 30 * time.Second
+)
+
+// State is the state of a circuit breaker
+type State int
+
+const (
+	// Closed circuits allow all requests
+	Closed State = iota
+	// Open circuits reject requests until the cool-down period has elapsed
+	Open
+	// HalfOpen circuits allow a single trial request
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Config contains the circuit breaker configuration
+type Config struct {
+	// FailureThreshold is the number of consecutive failures which opens the circuit.
+	// A threshold of zero disables the circuit breaker.
+	FailureThreshold int
+	// CoolDown is the period for which an open circuit rejects requests
+	CoolDown time.Duration
+}
+
+// DefaultConfig returns the default circuit breaker configuration
+func DefaultConfig() Config {
+	return Config{
+		FailureThreshold: DefaultFailureThreshold,
+		CoolDown:         DefaultCoolDown,
+	}
+}
+
+// Metrics contains the state and counters of a circuit breaker
+type Metrics struct {
+	Name                string
+	State               State
+	ConsecutiveFailures int
+	Successes           uint64
+	Failures            uint64
+	// Trips is the number of times the circuit was opened
+	Trips uint64
+	// OpenedAt is the time at which the circuit was last opened
+	OpenedAt time.Time
+}
+
+// Breaker is a circuit breaker for a single endpoint
+type Breaker struct {
+	name                string
+	registry            *Registry
+	lock                sync.Mutex
+	state               State
+	consecutiveFailures int
+	openedAt            time.Time
+	trialInProgress     bool
+	successes           uint64
+	failures            uint64
+	trips               uint64
+}
+
+// Allow returns true if a request may be sent to the endpoint. If true is returned for a
+// half-open circuit then the caller must report the outcome with Success or Failure.
+func (b *Breaker) Allow() bool {
+	cfg := b.registry.Config()
+	if cfg.FailureThreshold <= 0 {
+		return true
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	switch b.state {
+	case Open:
+		if b.registry.now().Sub(b.openedAt) < cfg.CoolDown {
+			return false
+		}
+		logger.Debugf("Cool-down of [%s] elapsed - allowing trial request", b.name)
+		b.state = HalfOpen
+		b.trialInProgress = true
+		return true
+	case HalfOpen:
+		if b.trialInProgress {
+			return false
+		}
+		b.trialInProgress = true
+		return true
+	default:
+		return true
+	}
+}
+
+// Success reports a successful request. The circuit is closed.
+func (b *Breaker) Success() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.state != Closed {
+		logger.Infof("Circuit of [%s] closed", b.name)
+	}
+	b.successes++
+	b.consecutiveFailures = 0
+	b.trialInProgress = false
+	b.state = Closed
+}
+
+// Failure reports a failed request. The circuit is opened if the number of consecutive
+// failures reaches the threshold or if the trial request of a half-open circuit failed.
+func (b *Breaker) Failure() {
+	cfg := b.registry.Config()
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.failures++
+	b.consecutiveFailures++
+	b.trialInProgress = false
+
+	if cfg.FailureThreshold <= 0 {
+		return
+	}
+	if b.state == HalfOpen || (b.state == Closed && b.consecutiveFailures >= cfg.FailureThreshold) {
+		logger.Warnf("Circuit of [%s] opened after %d consecutive failures", b.name, b.consecutiveFailures)
+		b.state = Open
+		b.openedAt = b.registry.now()
+		b.trips++
+	}
+}
+
+// State returns the current state of the circuit
+func (b *Breaker) State() State {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.state
+}
+
+// Metrics returns the state and counters of the circuit breaker
+func (b *Breaker) Metrics() Metrics {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return Metrics{
+		Name:                b.name,
+		State:               b.state,
+		ConsecutiveFailures: b.consecutiveFailures,
+		Successes:           b.successes,
+		Failures:            b.failures,
+		Trips:               b.trips,
+		OpenedAt:            b.openedAt,
+	}
+}
+
+// Registry holds the circuit breakers of a set of endpoints, which share the same configuration
+type Registry struct {
+	lock     sync.RWMutex
+	config   Config
+	breakers map[string]*Breaker
+	now      func() time.Time
+}
+
+// NewRegistry returns a new circuit breaker registry with the given configuration
+func NewRegistry(config Config) *Registry {
+	return &Registry{
+		config:   config,
+		breakers: make(map[string]*Breaker),
+		now:      time.Now,
+	}
+}
+
+// Config returns the configuration of the circuit breakers
+func (r *Registry) Config() Config {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.config
+}
+
+// SetConfig sets the configuration of the circuit breakers
+func (r *Registry) SetConfig(config Config) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.config = config
+}
+
+// Get returns the circuit breaker of the given endpoint (which is created if it doesn't exist)
+func (r *Registry) Get(name string) *Breaker {
+	r.lock.RLock()
+	b, ok := r.breakers[name]
+	r.lock.RUnlock()
+	if ok {
+		return b
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if b, ok := r.breakers[name]; ok {
+		return b
+	}
+	b = &Breaker{name: name, registry: r}
+	r.breakers[name] = b
+	return b
+}
+
+// Metrics returns the metrics of all circuit breakers, ordered by name
+func (r *Registry) Metrics() []Metrics {
+	r.lock.RLock()
+	breakers := make([]*Breaker, 0, len(r.breakers))
+	for _, b := range r.breakers {
+		breakers = append(breakers, b)
+	}
+	r.lock.RUnlock()
+
+	metrics := make([]Metrics, len(breakers))
+	for i, b := range breakers {
+		metrics[i] = b.Metrics()
+	}
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].Name < metrics[j].Name
+	})
+	return metrics
+}
@@ -0,0 +1,78 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package circuitbreaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreaker(t *testing.T) {
+	registry := NewRegistry(Config{FailureThreshold: 2, CoolDown: time.Minute})
+	now := time.Now()
+	registry.now = func() time.Time { return now }
+
+	b := registry.Get("orderer1")
+	assert.Equal(t, b, registry.Get("orderer1"))
+	assert.Equal(t, Closed, b.State())
+
+	b.Failure()
+	assert.True(t, b.Allow(), "expected circuit to remain closed below the threshold")
+
+	b.Failure()
+	assert.Equal(t, Open, b.State())
+	assert.False(t, b.Allow(), "expected open circuit to reject requests")
+
+	// Cool-down elapsed - a single trial request is allowed
+	now = now.Add(time.Minute)
+	assert.True(t, b.Allow())
+	assert.Equal(t, HalfOpen, b.State())
+	assert.False(t, b.Allow(), "expected only one trial request")
+
+	// Failed trial opens the circuit again
+	b.Failure()
+	assert.Equal(t, Open, b.State())
+	assert.False(t, b.Allow())
+
+	now = now.Add(time.Minute)
+	assert.True(t, b.Allow())
+	b.Success()
+	assert.Equal(t, Closed, b.State())
+	assert.True(t, b.Allow())
+
+	metrics := registry.Metrics()
+	require.Len(t, metrics, 1)
+	assert.Equal(t, "orderer1", metrics[0].Name)
+	assert.Equal(t, Closed, metrics[0].State)
+	assert.Equal(t, uint64(2), metrics[0].Trips)
+	assert.Equal(t, uint64(3), metrics[0].Failures)
+	assert.Equal(t, uint64(1), metrics[0].Successes)
+	assert.Equal(t, 0, metrics[0].ConsecutiveFailures)
+}
+
+func TestDisabledBreaker(t *testing.T) {
+	registry := NewRegistry(Config{})
+	b := registry.Get("orderer1")
+	for i := 0; i < 10; i++ {
+		b.Failure()
+	}
+	assert.Equal(t, Closed, b.State())
+	assert.True(t, b.Allow())
+
+	registry.SetConfig(DefaultConfig())
+	assert.Equal(t, DefaultFailureThreshold, registry.Config().FailureThreshold)
+}
+
+func TestStateString(t *testing.T) {
+	assert.Equal(t, "closed", Closed.String())
+	assert.Equal(t, "open", Open.String())
+	assert.Equal(t, "half-open", HalfOpen.String())
+	assert.Equal(t, "unknown", State(99).String())
+}
@@ -150,6 +150,25 @@ func (r *Reference) Get() (interface{}, error) {
 	return value, nil
 }
 
+// Refresh invokes the initializer and, if the initializer was successful,
+// replaces the value of the reference with the new value.
+func (r *Reference) Refresh() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.closed {
+		return errors.New("reference is already closed")
+	}
+
+	value, err := r.initializer()
+	if err != nil {
+		return err
+	}
+	r.set(value)
+
+	return nil
+}
+
 // MustGet returns the value. If an error is returned
 // during initialization of the value then this function
 // will panic.
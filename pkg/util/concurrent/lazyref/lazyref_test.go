@@ -156,6 +156,32 @@ func TestMustGetPanic(t *testing.T) {
 	t.Fatalf("Expecting panic but got none")
 }
 
+func TestRefresh(t *testing.T) {
+	sequence := 0
+	fail := false
+	ref := New(func() (interface{}, error) {
+		if fail {
+			return nil, fmt.Errorf("initializer error")
+		}
+		sequence++
+		return fmt.Sprintf("Data_%d", sequence), nil
+	})
+
+	assert.Equal(t, "Data_1", ref.MustGet())
+	assert.Equal(t, "Data_1", ref.MustGet())
+
+	assert.NoError(t, ref.Refresh())
+	assert.Equal(t, "Data_2", ref.MustGet())
+
+	// The value is retained if the initializer fails
+	fail = true
+	assert.Error(t, ref.Refresh())
+	assert.Equal(t, "Data_2", ref.MustGet())
+
+	ref.Close()
+	assert.Error(t, ref.Refresh())
+}
+
 func TestGetWithFinalizer(t *testing.T) {
 	var numTimesInitialized int32
 	var numTimesFinalized int32
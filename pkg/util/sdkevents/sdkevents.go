@@ -0,0 +1,257 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package sdkevents is a bus of events about the health of the SDK's connections to the network, such as
+// connections to peers going down, orderer failovers, reconnects of event clients, discovery refreshes and
+// certificates nearing their expiry. Applications subscribe to the events to alert on degradation before
+// transactions start failing.
+//
+// Each SDK instance publishes its events on a bus of its own, which is held by its endpoint config
+// (see BusFor and FabricSDK.EventBus), and the events are tagged with the ID of the SDK instance:
+//
+//  sub, eventch := sdk.EventBus().Subscribe(sdkevents.ConnectionDown, sdkevents.OrdererFailover)
+//  defer sdk.EventBus().Unsubscribe(sub)
+//  for event := range eventch {
+//      fmt.Printf("%s\n", event)
+//  }
+//
+// Events published outside of an SDK instance, such as the expiry events of the default certificate
+// expiry tracker, are published on the default bus, to which the functions of the package apply.
+package sdkevents
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/certexpiry"
+)
+
+var logger = logging.NewLogger("fabsdk/util")
+
+// Type is the type of an event
+type Type string
+
+const (
+	// ConnectionUp is published when a connection to a peer or orderer becomes ready
+	ConnectionUp Type = "ConnectionUp"
+	// ConnectionDown is published when a ready connection to a peer or orderer fails
+	ConnectionDown Type = "ConnectionDown"
+	// OrdererFailover is published when a transaction is sent to an orderer after other orderers failed
+	OrdererFailover Type = "OrdererFailover"
+	// DeliverDisconnected is published when an event client is disconnected from the peer it receives events from
+	DeliverDisconnected Type = "DeliverDisconnected"
+	// DeliverReconnected is published when an event client connects again after it was disconnected
+	DeliverReconnected Type = "DeliverReconnected"
+	// DiscoveryRefresh is published when the peers of a channel (or of the local MSP) are refreshed
+	// from the discovery service
+	DiscoveryRefresh Type = "DiscoveryRefresh"
+	// CertExpiring is published when a certificate is found to be expiring or expired (see package certexpiry)
+	CertExpiring Type = "CertExpiring"
+)
+
+// DefaultBufferSize is the size of the event channel of a subscription
+const DefaultBufferSize = 100
+
+// Event is an event published on the bus
+type Event struct {
+	Type Type
+	Time time.Time
+	// SDKID is the ID of the SDK instance of the bus on which the event was published; it's empty
+	// for the events of the default bus
+	SDKID string
+	// Target is the URL of the peer or orderer of the event, if any
+	Target string
+	// ChannelID is the channel of the event, if any
+	ChannelID string
+	// Err is the error which caused the event, if any
+	Err error
+	// FailedTargets are the URLs of the orderers which failed before an OrdererFailover
+	FailedTargets []string
+	// Peers are the URLs of the peers returned by a DiscoveryRefresh
+	Peers []string
+	// Cert is the certificate expiry event of a CertExpiring event
+	Cert *certexpiry.Event
+}
+
+func (e *Event) String() string {
+	s := fmt.Sprintf("%s at %s", e.Type, e.Time.Format(time.RFC3339))
+	if e.SDKID != "" {
+		s += fmt.Sprintf(" - SDK [%s]", e.SDKID)
+	}
+	if e.Target != "" {
+		s += fmt.Sprintf(" - target [%s]", e.Target)
+	}
+	if e.ChannelID != "" {
+		s += fmt.Sprintf(" - channel [%s]", e.ChannelID)
+	}
+	if len(e.FailedTargets) > 0 {
+		s += fmt.Sprintf(" - failed targets %v", e.FailedTargets)
+	}
+	if e.Cert != nil {
+		s += fmt.Sprintf(" - %s", e.Cert)
+	}
+	if e.Err != nil {
+		s += fmt.Sprintf(" - error: %s", e.Err)
+	}
+	return s
+}
+
+// Subscription is a subscription to events of the bus
+type Subscription struct {
+	types   map[Type]bool
+	eventch chan *Event
+	dropped uint64
+}
+
+// Dropped returns the number of events which were dropped since the event channel of
+// the subscription was full
+func (s *Subscription) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+func (s *Subscription) accepts(t Type) bool {
+	return len(s.types) == 0 || s.types[t]
+}
+
+// Bus is a bus of events, to which the events of an SDK instance are published
+type Bus struct {
+	sdkID string
+	lock  sync.RWMutex
+	subs  map[*Subscription]struct{}
+}
+
+// BusProvider is implemented by configs which hold the event bus of an SDK instance
+type BusProvider interface {
+	EventBus() *Bus
+}
+
+var sdkCount uint64
+
+// NewBus returns a new bus of the events of an SDK instance, with an ID which is unique in the process
+func NewBus() *Bus {
+	return newBus(fmt.Sprintf("sdk%d", atomic.AddUint64(&sdkCount, 1)))
+}
+
+func newBus(sdkID string) *Bus {
+	return &Bus{sdkID: sdkID, subs: make(map[*Subscription]struct{})}
+}
+
+// defaultBus holds the events which are published outside of an SDK instance
+var defaultBus = newDefaultBus()
+
+func newDefaultBus() *Bus {
+	b := newBus("")
+	// The default certificate expiry tracker has its own handlers, which are bridged to the bus
+	certexpiry.RegisterHandler(func(event *certexpiry.Event) {
+		b.Publish(&Event{Type: CertExpiring, Target: event.Subject, Cert: event})
+	})
+	return b
+}
+
+// DefaultBus returns the bus of the events which are published outside of an SDK instance
+func DefaultBus() *Bus {
+	return defaultBus
+}
+
+// BusFor returns the bus held by the given config if it implements BusProvider,
+// otherwise the default bus is returned
+func BusFor(config interface{}) *Bus {
+	if provider, ok := config.(BusProvider); ok {
+		if b := provider.EventBus(); b != nil {
+			return b
+		}
+	}
+	return defaultBus
+}
+
+// Subscribe subscribes to the events of the given types on the default bus
+func Subscribe(types ...Type) (*Subscription, <-chan *Event) {
+	return defaultBus.Subscribe(types...)
+}
+
+// SubscribeWithBufferSize subscribes to the events of the given types on the default bus with an event
+// channel of the given size
+func SubscribeWithBufferSize(bufferSize int, types ...Type) (*Subscription, <-chan *Event) {
+	return defaultBus.SubscribeWithBufferSize(bufferSize, types...)
+}
+
+// Unsubscribe removes the given subscription from the default bus
+func Unsubscribe(sub *Subscription) {
+	defaultBus.Unsubscribe(sub)
+}
+
+// Publish publishes the given event on the default bus
+func Publish(event *Event) {
+	defaultBus.Publish(event)
+}
+
+// SDKID returns the ID of the SDK instance of the bus, with which its events are tagged
+func (b *Bus) SDKID() string {
+	return b.sdkID
+}
+
+// Subscribe subscribes to the events of the given types, or to all events if no type is given.
+// Events are never blocked on a subscriber: if the channel of the subscription is full then
+// the event is dropped for the subscription. Unsubscribe must be called when the subscription
+// is no longer needed, which closes the channel.
+func (b *Bus) Subscribe(types ...Type) (*Subscription, <-chan *Event) {
+	return b.SubscribeWithBufferSize(DefaultBufferSize, types...)
+}
+
+// SubscribeWithBufferSize subscribes to the events of the given types with an event channel of the given size
+func (b *Bus) SubscribeWithBufferSize(bufferSize int, types ...Type) (*Subscription, <-chan *Event) {
+	sub := &Subscription{
+		types:   make(map[Type]bool),
+		eventch: make(chan *Event, bufferSize),
+	}
+	for _, t := range types {
+		sub.types[t] = true
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.subs[sub] = struct{}{}
+	return sub, sub.eventch
+}
+
+// Unsubscribe removes the given subscription and closes its event channel
+func (b *Bus) Unsubscribe(sub *Subscription) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if _, ok := b.subs[sub]; !ok {
+		return
+	}
+	delete(b.subs, sub)
+	close(sub.eventch)
+}
+
+// Publish publishes the given event to the subscribers of its type, tagged with the ID of the SDK
+// instance of the bus. The time of the event is set if it's zero.
+func (b *Bus) Publish(event *Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	event.SDKID = b.sdkID
+
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	for sub := range b.subs {
+		if !sub.accepts(event.Type) {
+			continue
+		}
+		select {
+		case sub.eventch <- event:
+		default:
+			atomic.AddUint64(&sub.dropped, 1)
+			logger.Debugf("Event channel of subscription is full - dropping event: %s", event)
+		}
+	}
+}
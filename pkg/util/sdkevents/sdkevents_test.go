@@ -0,0 +1,136 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdkevents
+
+import (
+	"crypto/x509"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/certexpiry"
+)
+
+func TestSubscribe(t *testing.T) {
+	downSub, downch := Subscribe(ConnectionDown)
+	defer Unsubscribe(downSub)
+	allSub, allch := Subscribe()
+	defer Unsubscribe(allSub)
+
+	Publish(&Event{Type: ConnectionUp, Target: "peer0.org1.example.com:7051"})
+	Publish(&Event{Type: ConnectionDown, Target: "peer0.org1.example.com:7051", Err: errors.New("connection state is TRANSIENT_FAILURE")})
+
+	event := receive(t, downch)
+	assert.Equal(t, ConnectionDown, event.Type)
+	assert.Equal(t, "peer0.org1.example.com:7051", event.Target)
+	assert.False(t, event.Time.IsZero(), "expecting time of event to be set")
+	assert.Contains(t, event.String(), "TRANSIENT_FAILURE")
+
+	assert.Equal(t, ConnectionUp, receive(t, allch).Type)
+	assert.Equal(t, ConnectionDown, receive(t, allch).Type)
+
+	select {
+	case event := <-downch:
+		t.Fatalf("unexpected event: %s", event)
+	default:
+	}
+}
+
+func TestDropped(t *testing.T) {
+	sub, eventch := SubscribeWithBufferSize(1, OrdererFailover)
+	defer Unsubscribe(sub)
+
+	Publish(&Event{Type: OrdererFailover, Target: "orderer2.example.com:7050", FailedTargets: []string{"orderer1.example.com:7050"}})
+	Publish(&Event{Type: OrdererFailover, Target: "orderer3.example.com:7050", FailedTargets: []string{"orderer2.example.com:7050"}})
+
+	assert.Equal(t, uint64(1), sub.Dropped())
+	assert.Equal(t, "orderer2.example.com:7050", receive(t, eventch).Target)
+}
+
+func TestUnsubscribe(t *testing.T) {
+	sub, eventch := Subscribe()
+	Unsubscribe(sub)
+
+	_, ok := <-eventch
+	assert.False(t, ok, "expecting event channel to be closed")
+
+	// Unsubscribing twice and publishing after unsubscribing are no-ops
+	Unsubscribe(sub)
+	Publish(&Event{Type: DiscoveryRefresh})
+}
+
+func TestCertExpiring(t *testing.T) {
+	sub, eventch := Subscribe(CertExpiring)
+	defer Unsubscribe(sub)
+
+	cert := &x509.Certificate{SerialNumber: big.NewInt(1), NotAfter: time.Now().Add(time.Hour)}
+	require.NotNil(t, certexpiry.Check(certexpiry.IdentitySource, "User1", cert))
+
+	event := receive(t, eventch)
+	assert.Equal(t, "User1", event.Target)
+	require.NotNil(t, event.Cert)
+	assert.Equal(t, certexpiry.IdentitySource, event.Cert.Source)
+	assert.False(t, event.Cert.Expired)
+}
+
+func TestBusPerSDK(t *testing.T) {
+	bus1 := NewBus()
+	bus2 := NewBus()
+	assert.NotEqual(t, bus1.SDKID(), bus2.SDKID())
+
+	sub1, eventch1 := bus1.Subscribe()
+	defer bus1.Unsubscribe(sub1)
+	sub2, eventch2 := bus2.Subscribe()
+	defer bus2.Unsubscribe(sub2)
+	defaultSub, defaultch := Subscribe()
+	defer Unsubscribe(defaultSub)
+
+	bus1.Publish(&Event{Type: DeliverDisconnected, Target: "peer0.org1.example.com:7051", ChannelID: "mychannel"})
+
+	event := receive(t, eventch1)
+	assert.Equal(t, bus1.SDKID(), event.SDKID)
+	assert.Equal(t, "mychannel", event.ChannelID)
+	assert.Contains(t, event.String(), bus1.SDKID())
+
+	select {
+	case event := <-eventch2:
+		t.Fatalf("unexpected event from other SDK: %s", event)
+	case event := <-defaultch:
+		t.Fatalf("unexpected event on default bus: %s", event)
+	default:
+	}
+}
+
+func TestBusFor(t *testing.T) {
+	bus := NewBus()
+	assert.Equal(t, bus, BusFor(&busConfig{bus: bus}))
+	assert.Equal(t, DefaultBus(), BusFor(&busConfig{}))
+	assert.Equal(t, DefaultBus(), BusFor(nil))
+}
+
+type busConfig struct {
+	bus *Bus
+}
+
+func (c *busConfig) EventBus() *Bus {
+	return c.bus
+}
+
+func receive(t *testing.T, eventch <-chan *Event) *Event {
+	select {
+	case event, ok := <-eventch:
+		require.True(t, ok, "unexpected close of event channel")
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+		return nil
+	}
+}
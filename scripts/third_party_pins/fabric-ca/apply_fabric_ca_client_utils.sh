@@ -36,8 +36,10 @@ declare -a FILES=(
     "lib/util.go"
     "lib/serverrevoke.go"
     "lib/sdkpatch_serverstruct.go"
+    "lib/sdkpatch_httperror.go"
 
     "lib/tls/tls.go"
+    "lib/tls/sdkpatch_cspkey.go"
 
     "sdkpatch/logbridge/logbridge.go"
     "sdkpatch/logbridge/syslogwriter.go"
@@ -72,6 +74,7 @@ FILTERS_ENABLED="fn"
 
 FILTER_FILENAME="lib/client.go"
 FILTER_FN="Enroll,GenCSR,SendReq,Init,newPost,newEnrollmentResponse,newCertificateRequest"
+FILTER_FN+=",GetCAInfo"
 FILTER_FN+=",getURL,NormalizeURL,initHTTPClient,net2LocalServerInfo,NewIdentity,newCfsslBasicKeyRequest"
 gofilter
 sed -i'' -e 's/util.GetServerPort()/\"\"/g' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
@@ -94,6 +97,7 @@ done
 
 FILTER_FILENAME="lib/identity.go"
 FILTER_FN="newIdentity,Revoke,Post,addTokenAuthHdr,GetECert,Reenroll,Register,GetName"
+FILTER_FN+=",AddAffiliation"
 gofilter
 sed -i'' -e 's/util.GetDefaultBCCSP()/nil/g' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
 sed -i'' -e '/log "github.com\// a\
@@ -127,6 +131,7 @@ sed -i'' -e '/log "github.com\// a\
 "github.com\/hyperledger\/fabric-sdk-go\/pkg\/common\/providers\/core"\
 ' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
 sed -i'' -e 's/bccsp.BCCSP/core.CryptoSuite/g' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
+sed -i'' -e 's/bccsp.Key/core.Key/g' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
 START_LINE=`grep -n "// ServerTLSConfig defines key material for a TLS server" "${TMP_PROJECT_PATH}/${FILTER_FILENAME}" | head -n 1 | awk -F':' '{print $1}'`
 for i in {1..14}
 do
@@ -18,15 +18,18 @@ GOFILTER_CMD="go run scripts/_go/src/gofilter/cmd/gofilter/gofilter.go"
 
 declare -a PKGS=(
         "common/cauthdsl"
+        "common/tools/configtxlator/update"
         "protos/utils"
         "core/common/ccprovider"
         "core/ledger/kvledger/txmgmt/rwsetutil"
         "core/ledger/util"
+        "core/chaincode/shim"
 )
 
 declare -a FILES=(
         "common/cauthdsl/cauthdsl_builder.go"
         "common/cauthdsl/policyparser.go"
+        "common/tools/configtxlator/update/update.go"
         "protos/utils/commonutils.go"
         "protos/utils/proputils.go"
         "protos/utils/txutils.go"
@@ -34,6 +37,10 @@ declare -a FILES=(
         "core/common/ccprovider/cdspackage.go"
         "core/ledger/kvledger/txmgmt/rwsetutil/rwset_proto_util.go"
         "core/ledger/util/txvalidationflags.go"
+        "core/chaincode/shim/chaincode.go"
+        "core/chaincode/shim/interfaces.go"
+        "core/chaincode/shim/mockstub.go"
+        "core/chaincode/shim/response.go"
 )
 
 echo 'Removing current upstream project from working directory ...'
@@ -51,11 +58,13 @@ gofilter() {
     echo "Filtering: ${FILTER_FILENAME}"
     cp ${TMP_PROJECT_PATH}/${FILTER_FILENAME} ${TMP_PROJECT_PATH}/${FILTER_FILENAME}.bak
     $GOFILTER_CMD -filename "${TMP_PROJECT_PATH}/${FILTER_FILENAME}.bak" \
-        -filters fn -fn "$FILTER_FN" \
+        -filters "$FILTERS_ENABLED" -fn "$FILTER_FN" -gen "$FILTER_GEN" -type "$FILTER_TYPE" \
         > "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
 }
 
 echo "Filtering Go sources for allowed functions ..."
+FILTERS_ENABLED="fn"
+
 FILTER_FILENAME="protos/utils/commonutils.go"
 FILTER_FN="UnmarshalChannelHeader,MarshalOrPanic,UnmarshalChannelHeader,MakeChannelHeader,MakePayloadHeader,ExtractPayload"
 FILTER_FN+=",Marshal,ExtractEnvelope,ExtractEnvelopeOrPanic,ExtractPayloadOrPanic"
@@ -94,6 +103,18 @@ FILTER_FILENAME="core/ledger/util/txvalidationflags.go"
 FILTER_FN="IsValid,IsInvalid,Flag,IsSetTo,NewTxValidationFlags"
 gofilter
 
+# The channel config helpers of the protos aren't pinned; an empty group is equivalent for computing updates
+FILTER_FILENAME="common/tools/configtxlator/update/update.go"
+sed -i'' -e 's/cb.NewConfigGroup()/\&cb.ConfigGroup{}/g' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
+
+# The shim is only pinned for its mock stub, so that Go chaincodes can be simulated in-process
+FILTERS_ENABLED="fn,gen,type"
+FILTER_FILENAME="core/chaincode/shim/chaincode.go"
+FILTER_FN="createCompositeKey,splitCompositeKey,validateCompositeKeyAttribute,validateSimpleKeys"
+FILTER_GEN=""
+FILTER_TYPE="IMPORT,CONST"
+gofilter
+
 # Apply patching
 echo "Patching import paths on upstream project ..."
 WORKING_DIR=$TMP_PROJECT_PATH FILES="${FILES[@]}" IMPORT_SUBSTS="${IMPORT_SUBSTS[@]}" scripts/third_party_pins/common/apply_import_patching.sh
@@ -0,0 +1,96 @@
+#!/bin/bash
+#
+# Copyright SecureKey Technologies Inc. All Rights Reserved.
+#
+# SPDX-License-Identifier: Apache-2.0
+#
+
+# This script pins the idemix crypto package from Hyperledger Fabric into the SDK
+# These files are checked into internal paths.
+# Note: This script must be adjusted as upstream makes adjustments
+
+set -e
+
+NAMESPACE_PREFIX="sdk.idemix."
+
+declare -a PKGS=(
+    "idemix"
+)
+
+declare -a FILES=(
+    "idemix/credential.go"
+    "idemix/credrequest.go"
+    "idemix/idemix.pb.go"
+    "idemix/issuerkey.go"
+    "idemix/logging.go"
+    "idemix/nonrevocation-prover.go"
+    "idemix/nonrevocation-verifier.go"
+    "idemix/nymsignature.go"
+    "idemix/revocation_authority.go"
+    "idemix/signature.go"
+    "idemix/util.go"
+    "idemix/weak-bb.go"
+)
+
+declare -a NPBFILES=(
+    "idemix/credential.go"
+    "idemix/credrequest.go"
+    "idemix/issuerkey.go"
+    "idemix/logging.go"
+    "idemix/nonrevocation-prover.go"
+    "idemix/nonrevocation-verifier.go"
+    "idemix/nymsignature.go"
+    "idemix/revocation_authority.go"
+    "idemix/signature.go"
+    "idemix/util.go"
+    "idemix/weak-bb.go"
+)
+
+declare -a PBFILES=(
+    "idemix/idemix.pb.go"
+)
+
+# The idemix package is taken from a later upstream release than the rest of the pinned
+# fabric packages since the identities issued by current fabric CAs include a non-revocation proof
+echo "Fetching idemix package ($UPSTREAM_IDEMIX_COMMIT) ..."
+cd $TMP_PROJECT_PATH
+git checkout $UPSTREAM_IDEMIX_COMMIT -- idemix
+cd $CWD
+
+rm -Rf "${INTERNAL_PATH}/idemix"
+
+# Create directory structure for packages
+for i in "${PKGS[@]}"
+do
+    mkdir -p $INTERNAL_PATH/${i}
+done
+
+# The SDK is locked to golang/protobuf v1.0.0 so remove the XXX_ fields and methods generated
+# for newer versions of the proto package
+echo "Patching generated protos for golang/protobuf v1.0.0 ..."
+for i in "${PBFILES[@]}"
+do
+    sed -i'' -e '/^func (m \*[A-Za-z0-9]*) XXX_/,/^}/d' "${TMP_PROJECT_PATH}/${i}"
+    sed -i'' -e '/^var xxx_messageInfo_/d' "${TMP_PROJECT_PATH}/${i}"
+    sed -i'' -e '/^[[:space:]]*XXX_/d' "${TMP_PROJECT_PATH}/${i}"
+    sed -i'' -e 's/proto.ProtoPackageIsVersion3/proto.ProtoPackageIsVersion2/g' "${TMP_PROJECT_PATH}/${i}"
+    gofmt -s -w "${TMP_PROJECT_PATH}/${i}"
+done
+
+echo "Inserting modification notice ..."
+WORKING_DIR=$TMP_PROJECT_PATH FILES="${NPBFILES[@]}" scripts/third_party_pins/common/apply_header_notice.sh
+WORKING_DIR=$TMP_PROJECT_PATH FILES="${PBFILES[@]}" ALLOW_NONE_LICENSE_ID="true" scripts/third_party_pins/common/apply_header_notice.sh
+
+echo "Changing proto registration paths to be unique"
+for i in "${PBFILES[@]}"
+do
+    sed -i'' -e "/proto.RegisterType/s/\"\([A-Za-z0-9]*\)\")/\"${NAMESPACE_PREFIX}\1\")/g" "${TMP_PROJECT_PATH}/${i}"
+done
+
+# Copy patched project into internal paths
+echo "Copying patched upstream project into working directory ..."
+for i in "${FILES[@]}"
+do
+    TARGET_PATH=`dirname $INTERNAL_PATH/${i}`
+    cp $TMP_PROJECT_PATH/${i} $TARGET_PATH
+done
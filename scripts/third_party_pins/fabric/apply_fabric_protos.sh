@@ -24,6 +24,7 @@ declare -a PKGS=(
 
     "protos/ledger/rwset"
     "protos/ledger/rwset/kvrwset"
+    "protos/ledger/queryresult"
     "protos/orderer"
 )
 
@@ -52,6 +53,7 @@ declare -a FILES=(
 
     "protos/ledger/rwset/rwset.pb.go"
     "protos/ledger/rwset/kvrwset/kv_rwset.pb.go"
+    "protos/ledger/queryresult/kv_query_result.pb.go"
 
     "protos/orderer/configuration.pb.go"
 )
@@ -84,6 +86,9 @@ do
     sed -i'' -e "/proto.RegisterType/s/kvrwset/${NAMESPACE_PREFIX}kvrwset/g" "${TMP_PROJECT_PATH}/${i}"
     sed -i'' -e "/proto.RegisterEnum/s/kvrwset/${NAMESPACE_PREFIX}kvrwset/g" "${TMP_PROJECT_PATH}/${i}"
   fi
+  if [[ ${i} == "protos/ledger/queryresult/kv_query_result.pb.go" ]]; then
+    sed -i'' -e "/proto.RegisterType/s/queryresult/${NAMESPACE_PREFIX}queryresult/g" "${TMP_PROJECT_PATH}/${i}"
+  fi
   if [[ ${i} == "protos/msp"* ]]; then
     sed -i'' -e "/proto.RegisterType/s/msp/${NAMESPACE_PREFIX}msp/g" "${TMP_PROJECT_PATH}/${i}"
     sed -i'' -e "/proto.RegisterEnum/s/msp/${NAMESPACE_PREFIX}msp/g" "${TMP_PROJECT_PATH}/${i}"
@@ -0,0 +1,53 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+/*
+Notice: This file has been modified for Hyperledger Fabric SDK Go usage.
+Please review third_party pinning scripts and patches for more details.
+*/
+
+package shim
+
+import (
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+const (
+	// OK constant - status code less than 400, endorser will endorse it.
+	// OK means init or invoke successfully.
+	OK = 200
+
+	// ERRORTHRESHOLD constant - status code greater than or equal to 400 will be considered an error and rejected by endorser.
+	ERRORTHRESHOLD = 400
+
+	// ERROR constant - default error value
+	ERROR = 500
+)
+
+// Success ...
+func Success(payload []byte) pb.Response {
+	return pb.Response{
+		Status:  OK,
+		Payload: payload,
+	}
+}
+
+// Error ...
+func Error(msg string) pb.Response {
+	return pb.Response{
+		Status:  ERROR,
+		Message: msg,
+	}
+}